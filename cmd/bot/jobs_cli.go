@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/telegram-bot/internal/jobs"
+)
+
+const jobsUsage = `Usage: telegram-bot jobs [list | cancel <id> | retry <id>]
+
+Talks to the admin endpoint of a running bot (JOBS_ADMIN_ADDR, default ` + jobs.DefaultAdminAddr + `).`
+
+func jobsAdminAddr() string {
+	if addr := os.Getenv("JOBS_ADMIN_ADDR"); addr != "" {
+		return addr
+	}
+	return jobs.DefaultAdminAddr
+}
+
+// runJobsCLI lists, cancels or retries jobs of a running bot and returns the exit code.
+func runJobsCLI(args []string) int {
+	client := jobs.NewAdminClient(jobsAdminAddr())
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	if len(args) == 0 || args[0] == "list" {
+		infos, err := client.List(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		printJobs(infos)
+		return 0
+	}
+
+	if len(args) != 2 || (args[0] != "cancel" && args[0] != "retry") {
+		fmt.Fprintln(os.Stderr, jobsUsage)
+		return 2
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, jobsUsage)
+		return 2
+	}
+
+	if args[0] == "cancel" {
+		err = client.Cancel(ctx, id)
+	} else {
+		err = client.Retry(ctx, id)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: job %d: %v\n", id, err)
+		return 1
+	}
+	fmt.Printf("Job %d: %s requested\n", id, args[0])
+	return 0
+}
+
+func printJobs(infos []jobs.Info) {
+	if len(infos) == 0 {
+		fmt.Println("No jobs")
+		return
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tKIND\tSTATUS\tCHAT\tAGE\tRETRIES\tERROR")
+	for _, info := range infos {
+		age := now.Sub(info.EnqueuedAt).Round(time.Second)
+		if info.Status == jobs.StatusRunning {
+			age = now.Sub(info.StartedAt).Round(time.Second)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%d\t%s\n", info.ID, info.Kind, info.Status, info.ChatID, age, info.Retries(), info.Error)
+	}
+	w.Flush()
+}
@@ -2,19 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/user/telegram-bot/internal/admin"
 	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/analysisguard"
 	"github.com/user/telegram-bot/internal/bot"
 	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/cooldown"
 	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/dispatch"
 	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/jira"
+	"github.com/user/telegram-bot/internal/jobs"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/plans"
+	"github.com/user/telegram-bot/internal/quota"
+	"github.com/user/telegram-bot/internal/shard"
+	"github.com/user/telegram-bot/internal/taskcard"
+	"github.com/user/telegram-bot/internal/telemetry"
+	"github.com/user/telegram-bot/internal/timezone"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+	"github.com/user/telegram-bot/internal/tts"
 )
 
 func main() {
@@ -23,10 +41,25 @@ func main() {
 		log.Printf("Warning: .env file not found, using environment variables")
 	}
 
-	// Проверяем обязательные переменные окружения
-	telegramToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if telegramToken == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN is required")
+	// Подкоманда `jobs` управляет очередью уже запущенного бота
+	if len(os.Args) > 1 && os.Args[1] == "jobs" {
+		os.Exit(runJobsCLI(os.Args[2:]))
+	}
+
+	// Подкоманда `prompts` редактирует библиотеку промптов в БД
+	if len(os.Args) > 1 && os.Args[1] == "prompts" {
+		os.Exit(runPromptsCLI(os.Args[2:]))
+	}
+
+	// Флаг --validate-config проверяет конфигурацию для CI и завершает работу
+	if len(os.Args) > 1 && os.Args[1] == "--validate-config" {
+		os.Exit(runValidateConfig())
+	}
+
+	// Список ботов, обслуживаемых процессом (по умолчанию один бот из TELEGRAM_BOT_TOKEN)
+	hosting, err := bot.LoadHostingConfig(bot.HostingConfigPath)
+	if err != nil {
+		log.Fatalf("Failed to load hosting configuration: %v", err)
 	}
 
 	// Инициализируем базу данных
@@ -46,49 +79,428 @@ func main() {
 	// Assert that our db.Manager implements the commands.DBManager interface
 	var _ commands.DBManager = dbManager
 
-	// Загружаем конфигурацию API
-	apiConfigs, err := httpclient.LoadConfig("configs/api.yaml")
+	// AI-клиент создается при первом обращении: без настроек AI бот продолжает
+	// работать с Todoist, а AI-команды отвечают понятной ошибкой
+	// Провайдер выбирается через AI_PROVIDER: OpenRouter по умолчанию или Claude
+	aiProvider, err := ai.ProviderFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read AI provider: %v", err)
+	}
+	// Запасные провайдеры из AI_FALLBACK_PROVIDERS получают запрос, когда основной упал или не ответил вовремя
+	aiFallbacks, err := ai.FallbackProvidersFromEnv(aiProvider)
+	if err != nil {
+		log.Fatalf("Failed to read AI fallback providers: %v", err)
+	}
+	// Вызовы AI пишутся в ai_calls через dbManager; настройки проверяем сразу, а не при первом запросе
+	if _, err := ai.CallLogSettingsFromEnv(); err != nil {
+		log.Fatalf("Failed to read AI call log settings: %v", err)
+	}
+	// Бюджет обсуждения в промпте: более ранние сообщения длинных обсуждений сжимаются в краткое содержание
+	if _, err := ai.ContextTokensFromEnv(); err != nil {
+		log.Fatalf("Failed to read AI context budget: %v", err)
+	}
+	// /create_task предлагает до AI_DRAFT_ALTERNATIVES разных черновиков на выбор
+	draftAlternatives, err := ai.DraftAlternativesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read AI draft alternatives: %v", err)
+	}
+	// Цены токенов из AI_TOKEN_PRICES нужны для оценки стоимости в /usage
+	tokenPrices, err := ai.TokenPricesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read AI token prices: %v", err)
+	}
+	aiConcurrency := 0
+	aiClient := ai.NewLazyClient(func() (ai.Client, error) {
+		var chain []ai.ProviderConfig
+		for _, provider := range append([]string{aiProvider}, aiFallbacks...) {
+			providerConfig, err := aiProviderConfig(provider)
+			if err != nil {
+				return nil, err
+			}
+			chain = append(chain, ai.ProviderConfig{Name: provider, Config: providerConfig})
+		}
+		// Отредактированные через `telegram-bot prompts` промпты подхватываются без перезапуска
+		return ai.NewFailoverClient(chain, dbManager)
+	})
+	if providerConfig, err := aiProviderConfig(aiProvider); err == nil {
+		aiConcurrency = providerConfig.MaxConcurrency
+	}
+	if err := aiClient.Init(); err != nil {
+		log.Printf("Warning: AI features disabled: %v", err)
+	}
+
+	// Очередь AI-задач с ограничением параллельных запросов к провайдеру
+	jobQueue := jobs.NewQueue(map[string]int{
+		aiProvider: aiConcurrency,
+	})
+
+	// Сроки, тихие часы и время сводок считаются в BOT_TIMEZONE, если чат не выбрал свой пояс через /timezone
+	defaultTimezone, err := timezone.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read default timezone: %v", err)
+	}
+	timezone.SetDefault(defaultTimezone)
+
+	admins, err := admin.UsersFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", admin.EnvUserIDs, err)
+	}
+
+	quotaLimits, err := quota.LimitsFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read task quotas: %v", err)
+	}
+
+	// Тарифы включаются только для продуктовой инсталляции
+	var planGate plans.Gate = plans.Unlimited{}
+	if plans.Enabled() {
+		catalog, err := plans.DefaultCatalog()
+		if err != nil {
+			log.Fatalf("Failed to configure plans: %v", err)
+		}
+		planGate = plans.NewMetered(dbManager, catalog, os.Getenv(plans.EnvUpgradeURL))
+		log.Printf("Billing plans enabled")
+	}
+
+	// Озвучивание черновиков включается, только если задан TTS_PROVIDER
+	synthesizer, err := tts.NewFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to configure speech synthesis: %v", err)
+	}
+
+	// Посты в каналах с хэштегами из CHANNEL_TASK_HASHTAGS открывают черновик в связанной группе
+	channelConfig, err := bot.ChannelConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read channel settings: %v", err)
+	}
+
+	// Длинные сообщения (логи, стектрейсы) сохраняются в обсуждение обрезанными до MESSAGE_CAPTURE_LIMIT символов
+	captureLimit, err := bot.CaptureLimitFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read message capture settings: %v", err)
+	}
+
+	// Апдейты разных чатов обрабатываются параллельно в UPDATE_WORKERS потоков, апдейты одного чата — по порядку
+	updatePool, err := bot.UpdatePoolFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read update pool settings: %v", err)
+	}
+
+	// Обсуждения из пары реплик и стикера не отправляются в AI
+	analysisGuard, err := analysisguard.RulesFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read analysis guard settings: %v", err)
+	}
+
+	// Метки из AI-черновика сверяются с метками Todoist; неизвестные создаются при CREATE_MISSING_LABELS=true
+	createMissingLabels, err := bot.CreateMissingLabelsFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to load API configuration: %v", err)
+		log.Fatalf("Failed to read label settings: %v", err)
 	}
 
-	// Получаем конфигурацию OpenRouter
-	openrouterConfig, err := apiConfigs.GetClientConfig("openrouter")
+	cooldownRules, err := cooldown.RulesFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to get OpenRouter configuration: %v", err)
+		log.Fatalf("Failed to read command cooldowns: %v", err)
 	}
 
-	// Создаем AI клиент
-	aiClient, err := ai.NewClient(openrouterConfig)
+	listPageSize, err := bot.ListPageSizeFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to create AI client: %v", err)
+		log.Fatalf("Invalid list page size: %v", err)
 	}
 
-	// Создаем Todoist клиент
-	todoistClient, err := todoist.NewClient()
+	// Если long polling завис, процесс завершается с ошибкой, и оркестратор его перезапускает
+	pollingStallTimeout, err := bot.PollingStallTimeoutFromEnv()
 	if err != nil {
-		log.Fatalf("Failed to create Todoist client: %v", err)
+		log.Fatalf("Failed to read polling watchdog settings: %v", err)
 	}
 
-	// Создаем бота с AI и Todoist клиентами
-	b, err := bot.New(telegramToken, dbManager, aiClient, todoistClient)
+	// Если у созданной задачи нет исполнителя дольше TASK_NUDGE_AFTER, бот напоминает об этом в чате
+	taskNudgeAfter, err := bot.TaskNudgeAfterFromEnv()
 	if err != nil {
-		log.Fatalf("Error creating bot: %v", err)
+		log.Fatalf("Failed to read task nudge settings: %v", err)
 	}
 
+	// Созданные задачи приходят карточкой: картинка в цвете проекта с флажком приоритета
+	taskCardsEnabled, err := taskcard.EnabledFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read task card settings: %v", err)
+	}
+	var taskCards *taskcard.Renderer
+	if taskCardsEnabled {
+		taskCards = taskcard.NewRenderer()
+	}
+
+	// Уведомления чатов по email работают, только если задан SMTP_ADDR
+	emailConfig, emailEnabled, err := notify.EmailConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read SMTP settings: %v", err)
+	}
+
+	// Исходящие вебхуки повторяются с экспоненциальной паузой при сетевых ошибках, 5xx и 429
+	webhookConfig := httpclient.DefaultConfig()
+	webhookConfig.Timeout = 10 * time.Second
+	webhookConfig.MaxRetryWaitTime = 10 * time.Second
+	webhookClient := httpclient.NewClient(webhookConfig)
+
+	// Входящие вебхуки Todoist и собственного формата принимаются только с проверенной подписью
+	inboundConfig, err := bot.InboundWebhookConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read inbound webhook settings: %v", err)
+	}
+	// С TELEGRAM_WEBHOOK_URL апдейты приходят на эндпоинт входящих вебхуков вместо long polling
+	telegramWebhook, err := bot.TelegramWebhookConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read Telegram webhook settings: %v", err)
+	}
+	if telegramWebhook.Enabled() && !inboundConfig.Enabled() {
+		log.Fatalf("%s needs %s to receive updates", bot.EnvTelegramWebhookURL, bot.EnvInboundWebhookAddr)
+	}
+	// Под long polling все апдейты токена получает один процесс, и апдейты чужих чатов терялись бы,
+	// поэтому шардирование работает только в режиме вебхука с пересылкой апдейтов владельцу чата
+	shardInstance := shard.Instance{ID: os.Getenv(shard.EnvInstanceID), URL: os.Getenv(shard.EnvInstanceURL)}
+	if shardInstance.ID != "" {
+		if !telegramWebhook.Enabled() {
+			log.Fatalf("Chat sharding needs %s: under long polling one process receives every update of a token", bot.EnvTelegramWebhookURL)
+		}
+		if shardInstance.URL == "" {
+			log.Fatalf("Chat sharding needs %s for other instances to forward updates to", shard.EnvInstanceURL)
+		}
+	}
+
+	// Анонимная статистика использования функций отправляется, только если установка явно согласилась
+	telemetryConfig, telemetryEnabled, err := telemetry.ConfigFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to read telemetry settings: %v", err)
+	}
+	var featureUsage *telemetry.Collector
+	if telemetryEnabled {
+		featureUsage = telemetry.NewCollector()
+	}
+
+	// Трекер задач каждого чата задаётся в разделе trackers файла configs/api.yaml
+	trackerConfig, err := tracker.LoadConfig("configs/api.yaml")
+	if err != nil {
+		log.Fatalf("Failed to read task tracker settings: %v", err)
+	}
+	// Клиент Jira создаётся, только если jira выбрана трекером хотя бы для одного чата
+	var jiraClient *jira.Client
+	if trackerConfig.Uses(tracker.KindJira) {
+		if jiraClient, err = jira.NewClient(trackerConfig.Jira); err != nil {
+			log.Fatalf("Failed to create Jira client: %v", err)
+		}
+	}
+
+	// Создаем ботов; у каждого свой токен, Todoist-клиент и срез данных в общей БД
+	bots := make([]*bot.Bot, 0, len(hosting.Bots))
+	for _, identity := range hosting.Bots {
+		telegramToken, err := identity.TelegramToken()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		todoistClient, err := todoist.NewClientWithTokenEnv(identity.TodoistTokenEnvVar)
+		if err != nil {
+			log.Fatalf("Failed to create Todoist client for bot %q: %v", identity.ID, err)
+		}
+
+		trackers := tracker.NewSelector(trackerConfig)
+		trackers.Register(tracker.KindTodoist, tracker.NewTodoist(todoistClient))
+		if jiraClient != nil {
+			trackers.Register(tracker.KindJira, jiraClient)
+		}
+		if err := trackers.Validate(); err != nil {
+			log.Fatalf("Invalid task trackers for bot %q: %v", identity.ID, err)
+		}
+
+		botAdmins := admins
+		if identity.AdminUserIDs != "" {
+			if botAdmins, err = admin.ParseUsers(identity.AdminUserIDs); err != nil {
+				log.Fatalf("Failed to parse admin_user_ids for bot %q: %v", identity.ID, err)
+			}
+		}
+
+		b, err := bot.New(telegramToken, dbManager.ForBot(identity.ID), aiClient, todoistClient, jobQueue, botAdmins, quotaLimits, planGate)
+		if err != nil {
+			log.Fatalf("Error creating bot %q: %v", identity.ID, err)
+		}
+		if synthesizer != nil {
+			b.SetSynthesizer(synthesizer)
+		}
+		// Ответы Todoist с заголовками Deprecation/Sunset пересылаются администраторам раз в сутки
+		if client, ok := todoistClient.(*todoist.TodoistClient); ok {
+			client.Use(httpclient.DeprecationMiddleware("todoist", b.ReportAPIDeprecation))
+		}
+		b.SetTrackers(trackers)
+		b.SetCooldowns(cooldown.NewLimiter(cooldownRules))
+		b.SetAIProvider(aiProvider)
+		b.SetTokenPrices(tokenPrices)
+		b.SetCaptureLimit(captureLimit)
+		b.SetUpdatePool(updatePool)
+		b.SetAnalysisGuard(analysisGuard)
+		b.SetCreateMissingLabels(createMissingLabels)
+		b.SetDraftAlternatives(draftAlternatives)
+		b.SetListPageSize(listPageSize)
+		if telegramWebhook.Enabled() {
+			b.SetTelegramWebhook(telegramWebhook, identity.ID)
+		}
+		if pollingStallTimeout > 0 {
+			botID := identity.ID
+			b.SetPollingWatchdog(pollingStallTimeout, func(stalledFor time.Duration) {
+				log.Fatalf("Telegram polling of bot %q stalled for %v, exiting for restart", botID, stalledFor.Round(time.Second))
+			})
+		}
+		if taskNudgeAfter > 0 {
+			b.SetTaskNudges(taskNudgeAfter)
+		}
+		if channelConfig.Enabled() {
+			b.SetChannelConfig(channelConfig)
+		}
+		if taskCards != nil {
+			b.SetTaskCards(taskCards)
+		}
+		notifiers := notify.NewRegistry()
+		notifiers.Register(notify.KindWebhook, notify.NewWebhookFactory(webhookClient))
+		if emailEnabled {
+			notifiers.Register(notify.KindEmail, notify.NewEmailFactory(emailConfig))
+		}
+		b.SetNotifiers(notifiers)
+		if featureUsage != nil {
+			b.SetTelemetry(featureUsage)
+		}
+		bots = append(bots, b)
+
+		// Проверяем права токена Todoist, чтобы узнать о проблеме до первой задачи
+		if verifier, ok := todoistClient.(todoist.CapabilityVerifier); ok {
+			go verifyTodoistCapabilities(verifier, b)
+		}
+	}
+
+	// Локальный admin-эндпоинт для `telegram-bot jobs` и очередей апдейтов (/updates)
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/", jobs.NewAdminHandler(jobQueue))
+	adminMux.HandleFunc("/updates", updateStatsHandler(bots, hosting.Bots))
+	adminAddr := jobsAdminAddr()
+	if err := jobs.CheckAdminAddr(adminAddr); err != nil {
+		log.Fatalf("Invalid JOBS_ADMIN_ADDR: %v", err)
+	}
+	adminServer := &http.Server{Addr: adminAddr, Handler: adminMux}
 	go func() {
-		log.Println("Starting bot...")
-		if err := b.Start(); err != nil {
-			log.Fatalf("Error starting bot: %v", err)
+		log.Printf("Jobs admin endpoint listening on %s", adminServer.Addr)
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Printf("Jobs admin endpoint stopped: %v", err)
 		}
 	}()
 
+	// Шардирование чатов между инстансами: апдейты чужих чатов пересылаются их владельцу
+	var shardCoordinator *shard.Coordinator
+	if shardInstance.ID != "" {
+		shardCoordinator = shard.NewCoordinator(dbManager, shardInstance)
+		if err := shardCoordinator.Start(ctx); err != nil {
+			log.Fatalf("Failed to join shard ring: %v", err)
+		}
+		for _, b := range bots {
+			b.SetChatRouter(shardCoordinator)
+		}
+		log.Printf("Chat sharding enabled, instance %q at %s", shardInstance.ID, shardInstance.URL)
+	}
+
+	// Публичные эндпоинты входящих вебхуков: /webhooks/<bot>/todoist, /webhooks/<bot>/events и апдейты Telegram /telegram/<bot>
+	var inboundServer *http.Server
+	if inboundConfig.Enabled() {
+		botsByID := make(map[string]*bot.Bot, len(bots))
+		for i, b := range bots {
+			botsByID[hosting.Bots[i].ID] = b
+		}
+		inboundServer = &http.Server{Addr: inboundConfig.Addr, Handler: bot.InboundWebhookHandler(inboundConfig, botsByID)}
+		go func() {
+			log.Printf("Inbound webhooks listening on %s", inboundServer.Addr)
+			if err := inboundServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Inbound webhook endpoint stopped: %v", err)
+			}
+		}()
+	}
+
+	for i, b := range bots {
+		b := b
+		botID := hosting.Bots[i].ID
+		go func() {
+			log.Printf("Starting bot %q...", botID)
+			if err := b.Start(); err != nil {
+				log.Fatalf("Error starting bot %q: %v", botID, err)
+			}
+		}()
+	}
+
+	// Раз в TELEMETRY_INTERVAL отправляем счётчики использования функций
+	telemetryCtx, stopTelemetry := context.WithCancel(context.Background())
+	defer stopTelemetry()
+	if featureUsage != nil {
+		installationID, err := dbManager.InstallationID(ctx)
+		if err != nil {
+			log.Fatalf("Failed to get installation id for telemetry: %v", err)
+		}
+		reporter := telemetry.NewReporter(telemetryConfig, featureUsage, installationID, httpclient.NewClient(httpclient.DefaultConfig()))
+		go reporter.Run(telemetryCtx)
+		log.Printf("Telemetry enabled: feature usage counts are sent to %s every %v", telemetryConfig.Endpoint, telemetryConfig.Interval)
+	}
+
 	// Ожидаем сигнал завершения
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	log.Println("Shutting down bot...")
-	b.Stop()
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer shutdownCancel()
+	adminServer.Shutdown(shutdownCtx)
+	if inboundServer != nil {
+		inboundServer.Shutdown(shutdownCtx)
+	}
+	for _, b := range bots {
+		b.Stop()
+	}
+	if shardCoordinator != nil {
+		shardCoordinator.Stop(shutdownCtx)
+	}
 	log.Println("Bot stopped")
-}
\ No newline at end of file
+}
+
+// updateStatsHandler reports the update pool load of every bot, keyed by bot ID
+func updateStatsHandler(bots []*bot.Bot, identities []bot.Identity) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		stats := make(map[string]dispatch.Stats, len(bots))
+		for i, b := range bots {
+			stats[identities[i].ID] = b.UpdateStats()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(stats)
+	}
+}
+
+// aiProviderConfig reads the client settings of the AI provider from configs/api.yaml
+func aiProviderConfig(provider string) (*httpclient.ClientConfig, error) {
+	apiConfigs, err := httpclient.LoadConfig("configs/api.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API configuration: %w", err)
+	}
+
+	providerConfig, err := apiConfigs.GetClientConfig(provider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s configuration: %w", provider, err)
+	}
+	return providerConfig, nil
+}
+
+func verifyTodoistCapabilities(verifier todoist.CapabilityVerifier, b *bot.Bot) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	failed := todoist.FailedCapabilities(verifier.VerifyCapabilities(ctx))
+	if failed == "" {
+		log.Println("Todoist token capabilities verified")
+		return
+	}
+
+	log.Printf("Todoist token misconfigured:\n%s", failed)
+	b.NotifyAdmins("⚠️ Проблема с токеном Todoist, создание задач может не работать:\n" + failed)
+}
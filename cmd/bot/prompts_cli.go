@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+)
+
+const promptsUsage = `Usage: telegram-bot prompts [list | show <name> | history <name> | set <name> <file> | rollback <name> <version>]
+
+Edits the AI prompt library stored in the database (DATABASE_URL). Running bots
+pick up a new version on the next AI request.`
+
+// runPromptsCLI inspects and edits named AI prompts and returns the exit code.
+func runPromptsCLI(args []string) int {
+	settings, err := ai.LoadAiSettings("configs/ai_settings.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	dbManager, err := db.NewManager()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer dbManager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	library := ai.NewPromptLibraryFromSettings(settings, dbManager)
+
+	if len(args) == 0 || args[0] == "list" {
+		return listPrompts(ctx, library)
+	}
+
+	switch {
+	case args[0] == "show" && len(args) == 2:
+		text, err := library.Get(ctx, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Println(text)
+		return 0
+
+	case args[0] == "history" && len(args) == 2:
+		versions, err := library.History(ctx, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		printPromptVersions(versions)
+		return 0
+
+	case args[0] == "set" && len(args) == 3:
+		text, err := os.ReadFile(args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		prompt, err := library.Set(ctx, args[1], string(text))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Prompt %s: version %d saved\n", prompt.Name, prompt.Version)
+		return 0
+
+	case args[0] == "rollback" && len(args) == 3:
+		version, err := strconv.Atoi(args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, promptsUsage)
+			return 2
+		}
+		prompt, err := library.Rollback(ctx, args[1], version)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		fmt.Printf("Prompt %s: version %d restored as version %d\n", prompt.Name, version, prompt.Version)
+		return 0
+	}
+
+	fmt.Fprintln(os.Stderr, promptsUsage)
+	return 2
+}
+
+func listPrompts(ctx context.Context, library *ai.PromptLibrary) int {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tVERSION\tUPDATED")
+	for _, name := range library.Names() {
+		versions, err := library.History(ctx, name)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+		if len(versions) == 0 {
+			fmt.Fprintf(w, "%s\tdefault\t-\n", name)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", name, versions[0].Version, versions[0].CreatedAt.Format(time.RFC3339))
+	}
+	w.Flush()
+	return 0
+}
+
+func printPromptVersions(versions []ai.Prompt) {
+	if len(versions) == 0 {
+		fmt.Println("No stored versions, the default from ai_settings.yaml is active")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tCREATED\tPREVIEW")
+	for _, p := range versions {
+		preview := strings.Join(strings.Fields(p.Text), " ")
+		if runes := []rune(preview); len(runes) > 60 {
+			preview = string(runes[:60]) + "…"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", p.Version, p.CreatedAt.Format(time.RFC3339), preview)
+	}
+	w.Flush()
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/bot"
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// runValidateConfig checks configuration files without starting the bot and
+// returns the exit code, so CI pipelines can reject broken configs.
+func runValidateConfig() int {
+	checks := []struct {
+		path     string
+		validate func(string) error
+	}{
+		{"configs/api.yaml", httpclient.ValidateConfig},
+		{"configs/ai_settings.yaml", ai.ValidateAiSettings},
+		{bot.HostingConfigPath, func(path string) error {
+			_, err := bot.LoadHostingConfig(path)
+			return err
+		}},
+	}
+
+	failed := false
+	for _, check := range checks {
+		if err := check.validate(check.path); err != nil {
+			failed = true
+			fmt.Fprintf(os.Stderr, "%s: invalid\n%s\n", check.path, indent(err.Error()))
+			continue
+		}
+		fmt.Printf("%s: ok\n", check.path)
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(text, "\n", "\n  ")
+}
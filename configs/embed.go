@@ -0,0 +1,67 @@
+// Package configs embeds the default configuration files into the binary.
+// A file on disk with the same path takes precedence over the embedded copy,
+// so deployments can still override any of them.
+package configs
+
+import (
+	"embed"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// dirPrefix is the path of this directory relative to the working directory
+// the bot is usually started from
+const dirPrefix = "configs/"
+
+//go:embed api.yaml ai_settings.yaml task_templates/*.md
+var defaults embed.FS
+
+// ReadFile reads path from disk and falls back to the embedded default when
+// the file does not exist and lives under configs/.
+func ReadFile(name string) ([]byte, error) {
+	data, err := os.ReadFile(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return data, err
+	}
+
+	embedded, ok := embeddedName(name)
+	if !ok {
+		return nil, err
+	}
+	data, embedErr := defaults.ReadFile(embedded)
+	if embedErr != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// ReadDir lists a directory on disk and falls back to the embedded default
+// the same way ReadFile does.
+func ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := os.ReadDir(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return entries, err
+	}
+
+	embedded, ok := embeddedName(name)
+	if !ok {
+		return nil, err
+	}
+	entries, embedErr := defaults.ReadDir(embedded)
+	if embedErr != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func embeddedName(name string) (string, bool) {
+	clean := path.Clean(filepath.ToSlash(name))
+	if !strings.HasPrefix(clean, dirPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(clean, dirPrefix), true
+}
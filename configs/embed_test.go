@@ -0,0 +1,50 @@
+package configs
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Tests run inside configs/, so configs/... paths only resolve to the embedded copies
+func TestReadFile_FallsBackToEmbedded(t *testing.T) {
+	data, err := ReadFile("configs/api.yaml")
+	if err != nil {
+		t.Fatalf("expected embedded api.yaml, got %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("embedded api.yaml is empty")
+	}
+
+	entries, err := ReadDir("configs/task_templates")
+	if err != nil {
+		t.Fatalf("expected embedded task templates, got %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("no embedded task templates")
+	}
+}
+
+func TestReadFile_PrefersFileOnDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.yaml")
+	if err := os.WriteFile(path, []byte("clients: {}"), 0o644); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	data, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("read config: %v", err)
+	}
+	if string(data) != "clients: {}" {
+		t.Errorf("expected file on disk, got %q", data)
+	}
+}
+
+func TestReadFile_NoFallbackOutsideConfigs(t *testing.T) {
+	_, err := ReadFile("other/api.yaml")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected not-exist error, got %v", err)
+	}
+}
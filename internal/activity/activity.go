@@ -0,0 +1,101 @@
+// Package activity turns stored message timestamps into a weekly heatmap of
+// chat activity and suggests when the chat is most likely to read bot messages.
+package activity
+
+import (
+	"strings"
+	"time"
+)
+
+// MinMessages is how many messages a chat needs before a suggestion is trusted
+const MinMessages = 20
+
+// Bucket counts the messages sent in one hour of one weekday
+type Bucket struct {
+	Weekday  time.Weekday
+	Hour     int
+	Messages int
+}
+
+// Heatmap counts messages by weekday, Monday first, and hour of the day
+type Heatmap [7][24]int
+
+var weekdayNames = [7]string{"Пн", "Вт", "Ср", "Чт", "Пт", "Сб", "Вс"}
+
+// shades draw a cell by its share of the busiest cell
+var shades = []rune{'░', '▒', '▓', '█'}
+
+// FromBuckets builds a heatmap; buckets outside the week or the day are ignored
+func FromBuckets(buckets []Bucket) Heatmap {
+	var h Heatmap
+	for _, b := range buckets {
+		if b.Weekday < time.Sunday || b.Weekday > time.Saturday || b.Hour < 0 || b.Hour > 23 {
+			continue
+		}
+		h[(int(b.Weekday)+6)%7][b.Hour] += b.Messages
+	}
+	return h
+}
+
+// Total returns the number of messages in the heatmap
+func (h Heatmap) Total() int {
+	total := 0
+	for _, day := range h {
+		for _, n := range day {
+			total += n
+		}
+	}
+	return total
+}
+
+// BestHour returns the hour with the most messages over the week, the earliest
+// on ties. Hours for which skip returns true, e.g. quiet hours, are not
+// suggested. ok is false when there are fewer than MinMessages messages.
+func (h Heatmap) BestHour(skip func(hour int) bool) (int, bool) {
+	if h.Total() < MinMessages {
+		return 0, false
+	}
+	best, bestCount := 0, 0
+	for hour := 0; hour < 24; hour++ {
+		if skip != nil && skip(hour) {
+			continue
+		}
+		count := 0
+		for _, day := range h {
+			count += day[hour]
+		}
+		if count > bestCount {
+			best, bestCount = hour, count
+		}
+	}
+	return best, bestCount > 0
+}
+
+// Render draws the heatmap as monospace text: a row per weekday, a column per hour
+func (h Heatmap) Render() string {
+	max := 0
+	for _, day := range h {
+		for _, n := range day {
+			if n > max {
+				max = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("   0     6     12    18\n")
+	for i, day := range h {
+		b.WriteString(weekdayNames[i])
+		b.WriteByte(' ')
+		for _, n := range day {
+			if n == 0 {
+				b.WriteRune('·')
+				continue
+			}
+			level := (n*len(shades) - 1) / max
+			b.WriteRune(shades[level])
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
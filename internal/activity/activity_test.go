@@ -0,0 +1,61 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFromBuckets_StartsWeekOnMonday(t *testing.T) {
+	h := FromBuckets([]Bucket{
+		{Weekday: time.Monday, Hour: 9, Messages: 3},
+		{Weekday: time.Sunday, Hour: 23, Messages: 2},
+		{Weekday: time.Monday, Hour: 24, Messages: 100},
+	})
+	if h[0][9] != 3 || h[6][23] != 2 {
+		t.Fatalf("unexpected heatmap: %v", h)
+	}
+	if h.Total() != 5 {
+		t.Fatalf("expected invalid bucket to be ignored, total %d", h.Total())
+	}
+}
+
+func TestBestHour_PicksBusiestAllowedHour(t *testing.T) {
+	h := FromBuckets([]Bucket{
+		{Weekday: time.Monday, Hour: 10, Messages: 8},
+		{Weekday: time.Tuesday, Hour: 10, Messages: 8},
+		{Weekday: time.Wednesday, Hour: 23, Messages: 20},
+	})
+
+	if hour, ok := h.BestHour(nil); !ok || hour != 23 {
+		t.Fatalf("expected 23, got %d (ok=%v)", hour, ok)
+	}
+	night := func(hour int) bool { return hour >= 22 || hour < 8 }
+	if hour, ok := h.BestHour(night); !ok || hour != 10 {
+		t.Fatalf("expected 10 outside quiet hours, got %d (ok=%v)", hour, ok)
+	}
+}
+
+func TestBestHour_NeedsEnoughMessages(t *testing.T) {
+	h := FromBuckets([]Bucket{{Weekday: time.Monday, Hour: 10, Messages: MinMessages - 1}})
+	if _, ok := h.BestHour(nil); ok {
+		t.Fatal("expected no suggestion for a quiet chat")
+	}
+}
+
+func TestRender_DrawsWeekRows(t *testing.T) {
+	h := FromBuckets([]Bucket{
+		{Weekday: time.Monday, Hour: 0, Messages: 4},
+		{Weekday: time.Monday, Hour: 1, Messages: 1},
+	})
+	lines := strings.Split(h.Render(), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected header and 7 rows, got %d", len(lines))
+	}
+	if !strings.HasPrefix(lines[1], "Пн █░·") {
+		t.Fatalf("unexpected Monday row %q", lines[1])
+	}
+	if strings.ContainsAny(lines[7], "░▒▓█") {
+		t.Fatalf("expected empty Sunday row, got %q", lines[7])
+	}
+}
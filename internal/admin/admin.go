@@ -0,0 +1,55 @@
+package admin
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvUserIDs lists Telegram user IDs allowed to run operator commands.
+const EnvUserIDs = "ADMIN_USER_IDS"
+
+// Users is a set of Telegram user IDs with operator access to the bot.
+type Users struct {
+	ids map[int64]struct{}
+}
+
+// ParseUsers parses a comma-separated list of Telegram user IDs.
+func ParseUsers(raw string) (Users, error) {
+	users := Users{ids: make(map[int64]struct{})}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return Users{}, fmt.Errorf("invalid admin user id %q: %w", part, err)
+		}
+		users.ids[id] = struct{}{}
+	}
+	return users, nil
+}
+
+// UsersFromEnv reads admin user IDs from ADMIN_USER_IDS.
+func UsersFromEnv() (Users, error) {
+	return ParseUsers(os.Getenv(EnvUserIDs))
+}
+
+// Contains reports whether the user is an admin.
+func (u Users) Contains(userID int64) bool {
+	_, ok := u.ids[userID]
+	return ok
+}
+
+// IDs returns admin user IDs in ascending order.
+func (u Users) IDs() []int64 {
+	ids := make([]int64, 0, len(u.ids))
+	for id := range u.ids {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
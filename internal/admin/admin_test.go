@@ -0,0 +1,32 @@
+package admin
+
+import "testing"
+
+func TestParseUsers(t *testing.T) {
+	users, err := ParseUsers(" 42, 7,,42 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !users.Contains(42) || !users.Contains(7) {
+		t.Fatalf("expected 42 and 7 to be admins, got %v", users.IDs())
+	}
+	if users.Contains(1) {
+		t.Fatal("unexpected admin 1")
+	}
+	if ids := users.IDs(); len(ids) != 2 || ids[0] != 7 || ids[1] != 42 {
+		t.Fatalf("unexpected ids: %v", ids)
+	}
+}
+
+func TestParseUsers_Invalid(t *testing.T) {
+	if _, err := ParseUsers("42,alice"); err == nil {
+		t.Fatal("expected error for non-numeric id")
+	}
+}
+
+func TestUsers_ZeroValue(t *testing.T) {
+	var users Users
+	if users.Contains(42) {
+		t.Fatal("zero value should contain no admins")
+	}
+}
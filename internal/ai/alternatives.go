@@ -0,0 +1,96 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+// EnvDraftAlternatives is how many different drafts /create_task offers to
+// choose from; 1 keeps a single draft
+const EnvDraftAlternatives = "AI_DRAFT_ALTERNATIVES"
+
+// MaxDraftAlternatives keeps the choice short and the extra requests cheap
+const MaxDraftAlternatives = 3
+
+// alternativeTemperatures sample each alternative more freely than the last,
+// the first one with the temperature of a single draft
+var alternativeTemperatures = [MaxDraftAlternatives]float64{0.3, 0.7, 1.0}
+
+// AlternativesAnalyzer is implemented by clients that can draft several
+// different tasks from one discussion
+type AlternativesAnalyzer interface {
+	AnalyzeDiscussionAlternatives(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink, count int) ([]*AnalyzedTask, error)
+}
+
+// DraftAlternativesFromEnv reads AI_DRAFT_ALTERNATIVES, 1 by default
+func DraftAlternativesFromEnv() (int, error) {
+	count, err := readNonNegative(EnvDraftAlternatives, 1)
+	if err != nil {
+		return 0, err
+	}
+	if count < 1 || count > MaxDraftAlternatives {
+		return 0, fmt.Errorf("%s must be between 1 and %d, got %d", EnvDraftAlternatives, MaxDraftAlternatives, count)
+	}
+	return count, nil
+}
+
+// AnalyzeDiscussionAlternatives samples the create_task prompt count times in
+// parallel and returns the drafts with different titles, the one of the usual
+// temperature first. Failed samples are dropped while at least one succeeds.
+func (c *AIClient) AnalyzeDiscussionAlternatives(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink, count int) ([]*AnalyzedTask, error) {
+	count = min(max(count, 1), MaxDraftAlternatives)
+	request, err := c.discussionRequest(ctx, messages, selectedLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]*AnalyzedTask, count)
+	errs := make([]error, count)
+	var wg sync.WaitGroup
+	for i := range tasks {
+		options := *request.Options
+		options.Temperature = alternativeTemperatures[i]
+		sample := request
+		sample.Options = &options
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tasks[i], errs[i] = c.completeTask(ctx, sample)
+		}(i)
+	}
+	wg.Wait()
+
+	return distinctAlternatives(tasks, errs)
+}
+
+// distinctAlternatives keeps the successful drafts whose titles differ
+func distinctAlternatives(tasks []*AnalyzedTask, errs []error) ([]*AnalyzedTask, error) {
+	var alternatives []*AnalyzedTask
+	seen := make(map[string]struct{})
+	for i, task := range tasks {
+		if errs[i] != nil {
+			log.Printf("Draft alternative %d failed: %v", i+1, errs[i])
+			continue
+		}
+		key := strings.ToLower(strings.Join(strings.Fields(task.Title), " "))
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		alternatives = append(alternatives, task)
+	}
+	if len(alternatives) == 0 {
+		for _, err := range errs {
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return alternatives, nil
+}
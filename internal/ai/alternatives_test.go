@@ -0,0 +1,87 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// temperatureCompleter answers by the temperature of the request, failing
+// the temperatures without an answer
+type temperatureCompleter struct {
+	mu      sync.Mutex
+	answers map[float64]string
+	seen    []float64
+}
+
+func (t *temperatureCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seen = append(t.seen, request.Options.Temperature)
+	answer, ok := t.answers[request.Options.Temperature]
+	if !ok {
+		return nil, errors.New("provider is down")
+	}
+	return &OpenRouterResponse{Choices: []OpenRouterChoice{{Message: OpenRouterMessage{Role: "assistant", Content: answer}}}}, nil
+}
+
+func newAlternativesTestClient(completer completer) *AIClient {
+	return &AIClient{
+		completer: completer,
+		prompts:   NewPromptLibrary(map[string]string{PromptCreateTask: "create"}, nil),
+	}
+}
+
+func TestAnalyzeDiscussionAlternatives_KeepsDistinctDraftsInOrder(t *testing.T) {
+	completer := &temperatureCompleter{answers: map[float64]string{
+		0.3: `{"title": "Починить логин"}`,
+		0.7: `{"title": "  починить   логин "}`,
+		1.0: `{"title": "Разобраться с SSO"}`,
+	}}
+	client := newAlternativesTestClient(completer)
+
+	tasks, err := client.AnalyzeDiscussionAlternatives(context.Background(), []string{"логин сломан"}, nil, 3)
+	if err != nil {
+		t.Fatalf("AnalyzeDiscussionAlternatives() error = %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].Title != "Починить логин" || tasks[1].Title != "Разобраться с SSO" {
+		t.Fatalf("unexpected alternatives: %+v", tasks)
+	}
+	if len(completer.seen) != 3 {
+		t.Errorf("expected 3 samples, got temperatures %v", completer.seen)
+	}
+}
+
+func TestAnalyzeDiscussionAlternatives_DropsFailedSamples(t *testing.T) {
+	completer := &temperatureCompleter{answers: map[float64]string{0.7: `{"title": "Починить логин"}`}}
+	client := newAlternativesTestClient(completer)
+
+	tasks, err := client.AnalyzeDiscussionAlternatives(context.Background(), []string{"логин сломан"}, nil, 2)
+	if err != nil || len(tasks) != 1 || tasks[0].Title != "Починить логин" {
+		t.Fatalf("expected the one successful draft, got %+v, %v", tasks, err)
+	}
+
+	if _, err := newAlternativesTestClient(&temperatureCompleter{}).AnalyzeDiscussionAlternatives(context.Background(), []string{"логин сломан"}, nil, 2); err == nil {
+		t.Error("expected an error when every sample fails")
+	}
+}
+
+func TestDraftAlternativesFromEnv(t *testing.T) {
+	t.Setenv(EnvDraftAlternatives, "")
+	if count, err := DraftAlternativesFromEnv(); err != nil || count != 1 {
+		t.Errorf("default = %d, %v; want 1", count, err)
+	}
+
+	t.Setenv(EnvDraftAlternatives, "3")
+	if count, err := DraftAlternativesFromEnv(); err != nil || count != 3 {
+		t.Errorf("got %d, %v; want 3", count, err)
+	}
+
+	for _, raw := range []string{"0", "4", "two"} {
+		t.Setenv(EnvDraftAlternatives, raw)
+		if _, err := DraftAlternativesFromEnv(); err == nil {
+			t.Errorf("expected error for %q", raw)
+		}
+	}
+}
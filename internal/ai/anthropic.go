@@ -0,0 +1,183 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// ProviderAnthropic is the Claude provider name used in configs/api.yaml and for job queue limits.
+const ProviderAnthropic = "anthropic"
+
+// EnvProvider selects the AI provider: "openrouter" (default), "anthropic", "azure_openai", "openai" or "ollama"
+const EnvProvider = "AI_PROVIDER"
+
+const defaultAnthropicModel = "claude-3-5-haiku-latest"
+
+// jsonOutputInstruction is the system prompt of JSON requests to Claude,
+// which has no response format switch
+const jsonOutputInstruction = "Respond with a single JSON object and nothing else: no Markdown fences, no explanations."
+
+// ProviderFromEnv reads AI_PROVIDER
+func ProviderFromEnv() (string, error) {
+	switch provider := strings.ToLower(strings.TrimSpace(os.Getenv(EnvProvider))); provider {
+	case "", ProviderOpenRouter:
+		return ProviderOpenRouter, nil
+	case ProviderAnthropic, ProviderAzureOpenAI, ProviderOpenAI, ProviderOllama:
+		return provider, nil
+	default:
+		return "", fmt.Errorf("unsupported %s %q: expected %s, %s, %s, %s or %s", EnvProvider, provider, ProviderOpenRouter, ProviderAnthropic, ProviderAzureOpenAI, ProviderOpenAI, ProviderOllama)
+	}
+}
+
+// NewProviderClient creates the AI client of the provider with its settings from configs/api.yaml
+func NewProviderClient(provider string, config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, model, err := newProviderCompleter(provider, config)
+	if err != nil {
+		return nil, err
+	}
+	return newAIClient(completer, provider, model, store)
+}
+
+// newProviderCompleter creates the completer of a provider and the model it requests
+func newProviderCompleter(provider string, config *httpclient.ClientConfig) (completer, string, error) {
+	switch provider {
+	case ProviderOpenRouter:
+		return newOpenRouterCompleter(config)
+	case ProviderAnthropic:
+		return newAnthropicCompleter(config)
+	case ProviderAzureOpenAI:
+		return newAzureCompleter(config)
+	case ProviderOpenAI:
+		return newOpenAICompleter(config)
+	case ProviderOllama:
+		return newOllamaCompleter(config)
+	default:
+		return nil, "", fmt.Errorf("unsupported AI provider %q", provider)
+	}
+}
+
+// NewAnthropicClient creates a client for the Claude Messages API. The model
+// comes from ANTHROPIC_MODEL; base_url in configs/api.yaml may point to any
+// gateway speaking the same API.
+func NewAnthropicClient(config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, model, err := newAnthropicCompleter(config)
+	if err != nil {
+		return nil, err
+	}
+	return newAIClient(completer, ProviderAnthropic, model, store)
+}
+
+func newAnthropicCompleter(config *httpclient.ClientConfig) (completer, string, error) {
+	client, err := config.CreateClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	model := os.Getenv("ANTHROPIC_MODEL")
+	if model == "" {
+		model = defaultAnthropicModel
+	}
+	return &anthropicCompleter{httpClient: client}, model, nil
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature *float64           `json:"temperature,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Model      string                  `json:"model"`
+	Content    []anthropicContentBlock `json:"content"`
+	StopReason string                  `json:"stop_reason"`
+	Usage      anthropicUsage          `json:"usage"`
+}
+
+type anthropicContentBlock struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+}
+
+// anthropicCompleter translates chat completion requests to the Claude Messages API
+type anthropicCompleter struct {
+	httpClient *httpclient.Client
+}
+
+func (a *anthropicCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	var response anthropicResponse
+	if err := a.httpClient.Post(ctx, "messages", toAnthropicRequest(request), &response); err != nil {
+		return nil, fmt.Errorf("Anthropic API error: %w", err)
+	}
+	return fromAnthropicResponse(request, &response), nil
+}
+
+// toAnthropicRequest moves system messages to the system prompt, as Claude
+// only accepts user and assistant turns, and prefills "{" for JSON requests
+func toAnthropicRequest(request OpenRouterRequest) anthropicRequest {
+	var system []string
+	out := anthropicRequest{Model: request.Model, MaxTokens: 1024}
+	if request.Options != nil {
+		if request.Options.MaxTokens > 0 {
+			out.MaxTokens = request.Options.MaxTokens
+		}
+		temperature := request.Options.Temperature
+		out.Temperature = &temperature
+	}
+
+	for _, msg := range request.Messages {
+		if msg.Role == "system" {
+			system = append(system, msg.Content)
+			continue
+		}
+		out.Messages = append(out.Messages, anthropicMessage{Role: msg.Role, Content: msg.Content})
+	}
+	if request.JSONOutput {
+		system = append(system, jsonOutputInstruction)
+		out.Messages = append(out.Messages, anthropicMessage{Role: "assistant", Content: "{"})
+	}
+	out.System = strings.Join(system, "\n\n")
+	return out
+}
+
+// fromAnthropicResponse joins the text blocks into one choice, restoring the
+// prefilled "{" of JSON requests
+func fromAnthropicResponse(request OpenRouterRequest, response *anthropicResponse) *OpenRouterResponse {
+	var text strings.Builder
+	if request.JSONOutput {
+		text.WriteString("{")
+	}
+	for _, block := range response.Content {
+		if block.Type == "text" {
+			text.WriteString(block.Text)
+		}
+	}
+
+	return &OpenRouterResponse{
+		Model: response.Model,
+		Choices: []OpenRouterChoice{{
+			Message:      OpenRouterMessage{Role: "assistant", Content: text.String()},
+			FinishReason: response.StopReason,
+		}},
+		Usage: OpenRouterUsage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	}
+}
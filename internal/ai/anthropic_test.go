@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestProviderFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{value: "", want: ProviderOpenRouter},
+		{value: "openrouter", want: ProviderOpenRouter},
+		{value: " Anthropic ", want: ProviderAnthropic},
+		{value: "azure_openai", want: ProviderAzureOpenAI},
+		{value: "openai", want: ProviderOpenAI},
+		{value: "ollama", want: ProviderOllama},
+		{value: "yandex", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(EnvProvider, tt.value)
+		got, err := ProviderFromEnv()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ProviderFromEnv(%q) expected error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ProviderFromEnv(%q) = %q, %v; want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestToAnthropicRequest_MovesSystemAndPrefillsJSON(t *testing.T) {
+	request := OpenRouterRequest{
+		Model: "claude-test",
+		Messages: []OpenRouterMessage{
+			{Role: "system", Content: "You are a task assistant."},
+			{Role: "user", Content: "Discussion"},
+		},
+		Options:    &OpenRouterOptions{Temperature: 0.2, MaxTokens: 2000},
+		JSONOutput: true,
+	}
+
+	got := toAnthropicRequest(request)
+
+	if got.System != "You are a task assistant.\n\n"+jsonOutputInstruction {
+		t.Errorf("unexpected system prompt: %q", got.System)
+	}
+	if got.MaxTokens != 2000 || got.Temperature == nil || *got.Temperature != 0.2 {
+		t.Errorf("options not mapped: max_tokens=%d temperature=%v", got.MaxTokens, got.Temperature)
+	}
+	if len(got.Messages) != 2 {
+		t.Fatalf("expected user turn and prefill, got %+v", got.Messages)
+	}
+	if got.Messages[0].Role != "user" || got.Messages[1].Role != "assistant" || got.Messages[1].Content != "{" {
+		t.Errorf("unexpected messages: %+v", got.Messages)
+	}
+}
+
+func TestToAnthropicRequest_PlainTextHasDefaults(t *testing.T) {
+	got := toAnthropicRequest(OpenRouterRequest{
+		Model:    "claude-test",
+		Messages: []OpenRouterMessage{{Role: "user", Content: "Hello"}},
+	})
+
+	if got.System != "" || got.MaxTokens != 1024 || got.Temperature != nil {
+		t.Errorf("unexpected defaults: %+v", got)
+	}
+	if len(got.Messages) != 1 {
+		t.Errorf("plain requests must not be prefilled: %+v", got.Messages)
+	}
+}
+
+func TestFromAnthropicResponse_RestoresPrefillAndUsage(t *testing.T) {
+	response := &anthropicResponse{
+		Model: "claude-test",
+		Content: []anthropicContentBlock{
+			{Type: "text", Text: `"title": "Fix login"`},
+			{Type: "text", Text: "}"},
+		},
+		StopReason: "end_turn",
+		Usage:      anthropicUsage{InputTokens: 120, OutputTokens: 30},
+	}
+
+	got := fromAnthropicResponse(OpenRouterRequest{JSONOutput: true}, response)
+
+	if len(got.Choices) != 1 {
+		t.Fatalf("expected one choice, got %d", len(got.Choices))
+	}
+	if content := got.Choices[0].Message.Content; content != `{"title": "Fix login"}` {
+		t.Errorf("unexpected content: %q", content)
+	}
+	if got.Usage.PromptTokens != 120 || got.Usage.CompletionTokens != 30 || got.Usage.TotalTokens != 150 {
+		t.Errorf("unexpected usage: %+v", got.Usage)
+	}
+}
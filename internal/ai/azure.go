@@ -0,0 +1,132 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// ProviderAzureOpenAI is the Azure OpenAI provider name used in configs/api.yaml and for job queue limits
+const ProviderAzureOpenAI = "azure_openai"
+
+const (
+	// EnvAzureDeployment is the deployment name the requests are addressed to
+	EnvAzureDeployment = "AZURE_OPENAI_DEPLOYMENT"
+	// EnvAzureAPIVersion is the api-version query parameter, e.g. "2024-10-21"
+	EnvAzureAPIVersion = "AZURE_OPENAI_API_VERSION"
+	// EnvAzureAPIKey authenticates with a resource key; without it the client
+	// signs in to Azure AD with AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET
+	EnvAzureAPIKey = "AZURE_OPENAI_API_KEY"
+)
+
+const (
+	defaultAzureAPIVersion = "2024-10-21"
+	azureCognitiveScope    = "https://cognitiveservices.azure.com/.default"
+)
+
+// NewAzureOpenAIClient creates a client for an Azure OpenAI deployment. It
+// uses the resource key when AZURE_OPENAI_API_KEY is set and Azure AD
+// client credentials otherwise, refreshing the token before it expires.
+func NewAzureOpenAIClient(config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, deployment, err := newAzureCompleter(config)
+	if err != nil {
+		return nil, err
+	}
+	return newAIClient(completer, ProviderAzureOpenAI, deployment, store)
+}
+
+// newAzureCompleter creates the completer of the deployment; the deployment stands in for the model
+func newAzureCompleter(config *httpclient.ClientConfig) (completer, string, error) {
+	deployment := os.Getenv(EnvAzureDeployment)
+	if deployment == "" {
+		return nil, "", fmt.Errorf("%s is required for Azure OpenAI", EnvAzureDeployment)
+	}
+	apiVersion := os.Getenv(EnvAzureAPIVersion)
+	if apiVersion == "" {
+		apiVersion = defaultAzureAPIVersion
+	}
+
+	client, err := config.CreateClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	if apiKey := os.Getenv(EnvAzureAPIKey); apiKey != "" {
+		client.WithMiddleware(httpclient.HeaderMiddleware(map[string]string{"api-key": apiKey}))
+	} else {
+		credentials, err := azureADCredentialsFromEnv()
+		if err != nil {
+			return nil, "", err
+		}
+		client.WithMiddleware(httpclient.TokenMiddleware(credentials))
+	}
+
+	return &azureCompleter{
+		httpClient: client,
+		path:       azureCompletionsPath(deployment, apiVersion),
+	}, deployment, nil
+}
+
+func azureADCredentialsFromEnv() (*httpclient.AzureADCredentials, error) {
+	credentials := &httpclient.AzureADCredentials{
+		TenantID:     os.Getenv("AZURE_TENANT_ID"),
+		ClientID:     os.Getenv("AZURE_CLIENT_ID"),
+		ClientSecret: os.Getenv("AZURE_CLIENT_SECRET"),
+		Scope:        azureCognitiveScope,
+		AuthorityURL: os.Getenv("AZURE_AUTHORITY_HOST"),
+	}
+	if credentials.TenantID == "" || credentials.ClientID == "" || credentials.ClientSecret == "" {
+		return nil, fmt.Errorf("Azure OpenAI needs %s or AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_CLIENT_SECRET", EnvAzureAPIKey)
+	}
+	return credentials, nil
+}
+
+// azureCompletionsPath addresses a deployment rather than a model
+func azureCompletionsPath(deployment, apiVersion string) string {
+	return "deployments/" + url.PathEscape(deployment) + "/chat/completions?api-version=" + url.QueryEscape(apiVersion)
+}
+
+type azureRequest struct {
+	Messages       []OpenRouterMessage  `json:"messages"`
+	Temperature    *float64             `json:"temperature,omitempty"`
+	MaxTokens      int                  `json:"max_tokens,omitempty"`
+	TopP           float64              `json:"top_p,omitempty"`
+	ResponseFormat *azureResponseFormat `json:"response_format,omitempty"`
+}
+
+type azureResponseFormat struct {
+	Type string `json:"type"`
+}
+
+// azureCompleter sends chat completion requests to one Azure OpenAI deployment
+type azureCompleter struct {
+	httpClient *httpclient.Client
+	path       string
+}
+
+func (a *azureCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	var response OpenRouterResponse
+	if err := a.httpClient.Post(ctx, a.path, toAzureRequest(request), &response); err != nil {
+		return nil, fmt.Errorf("Azure OpenAI API error: %w", err)
+	}
+	return &response, nil
+}
+
+// toAzureRequest flattens the options into OpenAI fields and turns on JSON mode
+// for JSON requests; the deployment in the path decides the model
+func toAzureRequest(request OpenRouterRequest) azureRequest {
+	out := azureRequest{Messages: request.Messages}
+	if request.Options != nil {
+		temperature := request.Options.Temperature
+		out.Temperature = &temperature
+		out.MaxTokens = request.Options.MaxTokens
+		out.TopP = request.Options.TopP
+	}
+	if request.JSONOutput {
+		out.ResponseFormat = &azureResponseFormat{Type: "json_object"}
+	}
+	return out
+}
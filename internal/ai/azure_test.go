@@ -0,0 +1,55 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestAzureCompletionsPath(t *testing.T) {
+	got := azureCompletionsPath("gpt-4o mini", "2024-10-21")
+	want := "deployments/gpt-4o%20mini/chat/completions?api-version=2024-10-21"
+	if got != want {
+		t.Errorf("azureCompletionsPath() = %q, want %q", got, want)
+	}
+}
+
+func TestToAzureRequest(t *testing.T) {
+	request := OpenRouterRequest{
+		Model:      "deployment",
+		Messages:   []OpenRouterMessage{{Role: "user", Content: "Discussion"}},
+		Options:    &OpenRouterOptions{Temperature: 0.3, MaxTokens: 800, TopP: 0.9},
+		JSONOutput: true,
+	}
+
+	got := toAzureRequest(request)
+
+	if got.Temperature == nil || *got.Temperature != 0.3 || got.MaxTokens != 800 || got.TopP != 0.9 {
+		t.Errorf("options not mapped: %+v", got)
+	}
+	if got.ResponseFormat == nil || got.ResponseFormat.Type != "json_object" {
+		t.Errorf("expected JSON mode, got %+v", got.ResponseFormat)
+	}
+
+	plain := toAzureRequest(OpenRouterRequest{Messages: request.Messages})
+	if plain.ResponseFormat != nil || plain.Temperature != nil {
+		t.Errorf("plain request must not set JSON mode or temperature: %+v", plain)
+	}
+}
+
+func TestAzureADCredentialsFromEnv_RequiresServicePrincipal(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "tenant")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_CLIENT_SECRET", "secret")
+
+	if _, err := azureADCredentialsFromEnv(); err == nil {
+		t.Error("expected error without AZURE_CLIENT_ID")
+	}
+
+	t.Setenv("AZURE_CLIENT_ID", "app")
+	credentials, err := azureADCredentialsFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if credentials.Scope != azureCognitiveScope {
+		t.Errorf("unexpected scope %q", credentials.Scope)
+	}
+}
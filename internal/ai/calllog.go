@@ -0,0 +1,234 @@
+package ai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvCallLog set to "false" stops recording AI calls
+	EnvCallLog = "AI_CALL_LOG"
+	// EnvCallLogRetentionDays drops recorded calls older than this many days
+	EnvCallLogRetentionDays = "AI_CALL_LOG_RETENTION_DAYS"
+	// EnvCallLogMaxRows keeps at most this many recorded calls
+	EnvCallLogMaxRows = "AI_CALL_LOG_MAX_ROWS"
+	// EnvCallLogOutputLimit is how many characters of a response are kept
+	EnvCallLogOutputLimit = "AI_CALL_LOG_OUTPUT_LIMIT"
+)
+
+const (
+	defaultCallLogRetentionDays = 30
+	defaultCallLogMaxRows       = 20000
+	defaultCallLogOutputLimit   = 4000
+	callLogPruneInterval        = time.Hour
+	callLogTimeout              = 5 * time.Second
+)
+
+// Call is one request to an AI provider and what came back
+type Call struct {
+	Provider         string
+	Model            string
+	PromptHash       string
+	LatencyMs        int64
+	PromptTokens     int
+	CompletionTokens int
+	TotalTokens      int
+	Output           string
+	Error            string
+	CreatedAt        time.Time
+}
+
+// CallLog keeps AI calls for offline evaluation. A PromptStore that also
+// implements CallLog gets every call of the client recorded.
+type CallLog interface {
+	SaveAICall(ctx context.Context, call Call) error
+	// PruneAICalls deletes calls created before olderThan and all but the newest keep
+	PruneAICalls(ctx context.Context, olderThan time.Time, keep int) (int64, error)
+}
+
+// CallLogSettings control what is recorded and for how long. Zero values
+// disable the retention limit or the truncation they stand for.
+type CallLogSettings struct {
+	Enabled     bool
+	Retention   time.Duration
+	MaxRows     int
+	OutputLimit int
+}
+
+// CallLogSettingsFromEnv reads AI_CALL_LOG, AI_CALL_LOG_RETENTION_DAYS,
+// AI_CALL_LOG_MAX_ROWS and AI_CALL_LOG_OUTPUT_LIMIT
+func CallLogSettingsFromEnv() (CallLogSettings, error) {
+	settings := CallLogSettings{Enabled: true}
+	if raw := strings.TrimSpace(os.Getenv(EnvCallLog)); raw != "" {
+		enabled, err := strconv.ParseBool(raw)
+		if err != nil {
+			return CallLogSettings{}, fmt.Errorf("invalid %s %q: %w", EnvCallLog, raw, err)
+		}
+		settings.Enabled = enabled
+	}
+
+	days, err := readNonNegative(EnvCallLogRetentionDays, defaultCallLogRetentionDays)
+	if err != nil {
+		return CallLogSettings{}, err
+	}
+	settings.Retention = time.Duration(days) * 24 * time.Hour
+
+	if settings.MaxRows, err = readNonNegative(EnvCallLogMaxRows, defaultCallLogMaxRows); err != nil {
+		return CallLogSettings{}, err
+	}
+	if settings.OutputLimit, err = readNonNegative(EnvCallLogOutputLimit, defaultCallLogOutputLimit); err != nil {
+		return CallLogSettings{}, err
+	}
+	return settings, nil
+}
+
+func readNonNegative(name string, fallback int) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, raw)
+	}
+	return value, nil
+}
+
+// PromptHash identifies the prompt of a request without storing it, so calls
+// with the same input can be compared across providers and models
+func PromptHash(request OpenRouterRequest) string {
+	hash := sha256.New()
+	for _, msg := range request.Messages {
+		hash.Write([]byte(msg.Role))
+		hash.Write([]byte{0})
+		hash.Write([]byte(msg.Content))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// loggingCompleter records every completion in a CallLog. Recording never
+// fails the request; retention is enforced at most once per prune interval.
+type loggingCompleter struct {
+	next     completer
+	provider string
+	calls    CallLog
+	settings CallLogSettings
+	now      func() time.Time
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// withCallLog records the calls of a completer under the provider that served them
+func withCallLog(next completer, provider string, calls CallLog, settings CallLogSettings) completer {
+	return wrapProviders(next, provider, func(next completer, provider string) completer {
+		return newLoggingCompleter(next, provider, calls, settings)
+	})
+}
+
+// wrapProviders wraps a completer, or each provider of a failover chain, so
+// the wrapper knows which provider served a request
+func wrapProviders(next completer, provider string, wrap func(next completer, provider string) completer) completer {
+	if failover, ok := next.(*failoverCompleter); ok {
+		for _, link := range failover.links {
+			link.next = wrap(link.next, link.provider)
+		}
+		return failover
+	}
+	return wrap(next, provider)
+}
+
+func newLoggingCompleter(next completer, provider string, calls CallLog, settings CallLogSettings) *loggingCompleter {
+	return &loggingCompleter{
+		next:     next,
+		provider: provider,
+		calls:    calls,
+		settings: settings,
+		now:      time.Now,
+	}
+}
+
+func (l *loggingCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	start := l.now()
+	response, err := l.next.Complete(ctx, request)
+
+	call := Call{
+		Provider:   l.provider,
+		Model:      request.Model,
+		PromptHash: PromptHash(request),
+		LatencyMs:  l.now().Sub(start).Milliseconds(),
+		CreatedAt:  start,
+	}
+	if err != nil {
+		call.Error = truncateRunes(err.Error(), l.settings.OutputLimit)
+	} else {
+		if response.Model != "" {
+			call.Model = response.Model
+		}
+		call.PromptTokens = response.Usage.PromptTokens
+		call.CompletionTokens = response.Usage.CompletionTokens
+		call.TotalTokens = response.Usage.TotalTokens
+		if len(response.Choices) > 0 {
+			call.Output = truncateRunes(response.Choices[0].Message.Content, l.settings.OutputLimit)
+		}
+	}
+	l.record(call)
+
+	return response, err
+}
+
+// record saves the call on its own deadline, as the request context may
+// already be cancelled
+func (l *loggingCompleter) record(call Call) {
+	ctx, cancel := context.WithTimeout(context.Background(), callLogTimeout)
+	defer cancel()
+
+	if err := l.calls.SaveAICall(ctx, call); err != nil {
+		log.Printf("Error recording AI call to %s: %v", call.Provider, err)
+		return
+	}
+
+	if !l.pruneDue(call.CreatedAt) {
+		return
+	}
+	var olderThan time.Time
+	if l.settings.Retention > 0 {
+		olderThan = call.CreatedAt.Add(-l.settings.Retention)
+	}
+	if _, err := l.calls.PruneAICalls(ctx, olderThan, l.settings.MaxRows); err != nil {
+		log.Printf("Error pruning AI call log: %v", err)
+	}
+}
+
+func (l *loggingCompleter) pruneDue(now time.Time) bool {
+	if l.settings.Retention <= 0 && l.settings.MaxRows <= 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastPruned) < callLogPruneInterval {
+		return false
+	}
+	l.lastPruned = now
+	return true
+}
+
+func truncateRunes(text string, limit int) string {
+	if limit <= 0 {
+		return text
+	}
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit])
+}
@@ -0,0 +1,111 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type stubCompleter struct {
+	response *OpenRouterResponse
+	err      error
+}
+
+func (s *stubCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	return s.response, s.err
+}
+
+type recordingCallLog struct {
+	calls  []Call
+	prunes []time.Time
+	keep   int
+}
+
+func (r *recordingCallLog) SaveAICall(ctx context.Context, call Call) error {
+	r.calls = append(r.calls, call)
+	return nil
+}
+
+func (r *recordingCallLog) PruneAICalls(ctx context.Context, olderThan time.Time, keep int) (int64, error) {
+	r.prunes = append(r.prunes, olderThan)
+	r.keep = keep
+	return 0, nil
+}
+
+func TestLoggingCompleter_RecordsResponse(t *testing.T) {
+	calls := &recordingCallLog{}
+	next := &stubCompleter{response: &OpenRouterResponse{
+		Model:   "model-v2",
+		Choices: []OpenRouterChoice{{Message: OpenRouterMessage{Content: "абвгдеж"}}},
+		Usage:   OpenRouterUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}}
+	settings := CallLogSettings{Enabled: true, Retention: 24 * time.Hour, MaxRows: 100, OutputLimit: 3}
+	logging := newLoggingCompleter(next, ProviderAnthropic, calls, settings)
+
+	request := OpenRouterRequest{Model: "model", Messages: []OpenRouterMessage{{Role: "user", Content: "hi"}}}
+	if _, err := logging.Complete(context.Background(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	logging.Complete(context.Background(), request)
+
+	if len(calls.calls) != 2 {
+		t.Fatalf("expected two recorded calls, got %d", len(calls.calls))
+	}
+	call := calls.calls[0]
+	if call.Provider != ProviderAnthropic || call.Model != "model-v2" || call.Output != "абв" || call.TotalTokens != 15 {
+		t.Errorf("unexpected call: %+v", call)
+	}
+	if call.PromptHash != PromptHash(request) || len(call.PromptHash) != 64 {
+		t.Errorf("unexpected prompt hash %q", call.PromptHash)
+	}
+	if len(calls.prunes) != 1 || calls.keep != 100 {
+		t.Errorf("expected one prune per interval keeping 100 rows, got %d prunes keeping %d", len(calls.prunes), calls.keep)
+	}
+}
+
+func TestLoggingCompleter_RecordsError(t *testing.T) {
+	calls := &recordingCallLog{}
+	logging := newLoggingCompleter(&stubCompleter{err: errors.New("API error: 503")}, ProviderOpenRouter, calls, CallLogSettings{Enabled: true})
+
+	if _, err := logging.Complete(context.Background(), OpenRouterRequest{Model: "model"}); err == nil {
+		t.Fatal("expected provider error to be returned")
+	}
+	if len(calls.calls) != 1 || calls.calls[0].Error != "API error: 503" || calls.calls[0].Model != "model" {
+		t.Errorf("unexpected calls: %+v", calls.calls)
+	}
+	if len(calls.prunes) != 0 {
+		t.Error("expected no pruning without retention limits")
+	}
+}
+
+func TestPromptHash_DependsOnRoles(t *testing.T) {
+	user := OpenRouterRequest{Messages: []OpenRouterMessage{{Role: "user", Content: "text"}}}
+	system := OpenRouterRequest{Messages: []OpenRouterMessage{{Role: "system", Content: "text"}}}
+	if PromptHash(user) == PromptHash(system) {
+		t.Error("expected different hashes for different roles")
+	}
+	if PromptHash(user) != PromptHash(user) {
+		t.Error("expected stable hash")
+	}
+}
+
+func TestCallLogSettingsFromEnv(t *testing.T) {
+	t.Setenv(EnvCallLog, "false")
+	t.Setenv(EnvCallLogRetentionDays, "7")
+	t.Setenv(EnvCallLogMaxRows, "")
+	t.Setenv(EnvCallLogOutputLimit, "0")
+
+	settings, err := CallLogSettingsFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if settings.Enabled || settings.Retention != 7*24*time.Hour || settings.MaxRows != defaultCallLogMaxRows || settings.OutputLimit != 0 {
+		t.Errorf("unexpected settings: %+v", settings)
+	}
+
+	t.Setenv(EnvCallLogMaxRows, "-1")
+	if _, err := CallLogSettingsFromEnv(); err == nil {
+		t.Error("expected error for negative row limit")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/priority"
 	"github.com/user/telegram-bot/internal/taskfields"
 	"github.com/user/telegram-bot/internal/tasklinks"
 )
@@ -19,6 +20,8 @@ type Client interface {
 	AnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalyzedTask, error)
 	EditTask(ctx context.Context, task *AnalyzedTask, userFeedback string) (*AnalyzedTask, error)
 	AnalyzeAssignee(ctx context.Context, messages []string, assigneeNote string, candidates []AssigneeCandidate) (*AssigneeSelection, error)
+	// RunPrompt runs a named prompt from the prompt library over input and returns the raw answer
+	RunPrompt(ctx context.Context, name string, input string) (string, error)
 }
 
 // AnalyzedTask represents the structured task from AI analysis
@@ -94,66 +97,87 @@ func parsePriorityValue(value any) (int, error) {
 	case float64:
 		return int(v), nil
 	case string:
-		trimmed := strings.TrimSpace(v)
-		if trimmed == "" {
-			return 0, nil
-		}
-
-		switch trimmed {
-		case "1", "2", "3", "4":
-			return int(trimmed[0] - '0'), nil
-		}
-
-		switch strings.ToLower(trimmed) {
-		case "low", "низкий", "normal", "обычный":
-			return 1, nil
-		case "medium", "mid", "средний":
-			return 2, nil
-		case "high", "высокий":
-			return 3, nil
-		case "urgent", "critical", "срочный", "критичный":
-			return 4, nil
-		default:
-			return 0, fmt.Errorf("unsupported priority value %q", v)
+		level, err := priority.Parse(v)
+		if err != nil {
+			return 0, err
 		}
+		return int(level), nil
 	default:
 		return 0, fmt.Errorf("unsupported priority type %T", value)
 	}
 }
 
+// ProviderOpenRouter is the provider name used in configs/api.yaml and for job queue limits.
+const ProviderOpenRouter = "openrouter"
+
 // AIClient клиент для работы с OpenRouter AI
 type AIClient struct {
-	httpClient            *httpclient.Client
-	model                 string
-	createTaskPrompt      string
-	editTaskPrompt        string
-	analyzeLinksPrompt    string
-	analyzeAssigneePrompt string
-	taskTemplates         []TaskTemplate
-	taskTemplatesPrompt   string
+	completer           completer
+	model               string
+	prompts             *PromptLibrary
+	taskTemplates       []TaskTemplate
+	taskTemplatesPrompt string
+	// contextTokens is the discussion budget of a prompt, 0 for no limit
+	contextTokens int
 }
 
 // NewClient создает новый AI клиент (OpenRouter)
 // Принимает конфигурацию как аргумент для упрощения тестирования
 func NewClient(config *httpclient.ClientConfig) (Client, error) {
-	// Загружаем настройки AI
-	aiSettings, err := LoadAiSettings("configs/ai_settings.yaml")
+	return NewClientWithPrompts(config, nil)
+}
+
+// NewClientWithPrompts создает AI клиент, который берет отредактированные
+// промпты из store; без store используются промпты из ai_settings.yaml
+func NewClientWithPrompts(config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, model, err := newOpenRouterCompleter(config)
 	if err != nil {
-		log.Printf("Error loading AI settings: %v. Using default settings.", err)
-		return nil, fmt.Errorf("failed to load AI settings: %w", err)
+		return nil, err
 	}
+	return newAIClient(completer, ProviderOpenRouter, model, store)
+}
 
+// newOpenRouterCompleter создает completer OpenRouter и возвращает модель из OPENROUTER_MODEL
+func newOpenRouterCompleter(config *httpclient.ClientConfig) (completer, string, error) {
 	// Создаем HTTP клиент из переданной конфигурации
 	client, err := config.CreateClient()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
 	}
 
-	// Получаем модель из env (или используем gpt-4o-mini по умолчанию)
+	// Получаем модель из env (или используем qwen по умолчанию)
 	model := os.Getenv("OPENROUTER_MODEL")
 	if model == "" {
 		model = "qwen/qwen3.5-35b-a3b"
 	}
+	return &openRouterCompleter{httpClient: client}, model, nil
+}
+
+// newAIClient загружает настройки и шаблоны задач; провайдер задается completer.
+// Если store умеет хранить вызовы (CallLog), каждый запрос к провайдеру записывается,
+// а если умеет учитывать токены (UsageLog) — расход токенов по чатам.
+func newAIClient(completer completer, provider, model string, store PromptStore) (*AIClient, error) {
+	callLogSettings, err := CallLogSettingsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if calls, ok := store.(CallLog); ok && callLogSettings.Enabled {
+		completer = withCallLog(completer, provider, calls, callLogSettings)
+	}
+	if usage, ok := store.(UsageLog); ok {
+		completer = withUsageLog(completer, provider, usage)
+	}
+	contextTokens, err := ContextTokensFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	// Загружаем настройки AI
+	aiSettings, err := LoadAiSettings("configs/ai_settings.yaml")
+	if err != nil {
+		log.Printf("Error loading AI settings: %v. Using default settings.", err)
+		return nil, fmt.Errorf("failed to load AI settings: %w", err)
+	}
 
 	taskTemplates, err := LoadTaskTemplates(aiSettings.TaskTemplatesDir)
 	if err != nil {
@@ -161,23 +185,52 @@ func NewClient(config *httpclient.ClientConfig) (Client, error) {
 	}
 
 	return &AIClient{
-		httpClient:            client,
-		model:                 model,
-		createTaskPrompt:      aiSettings.CreateTaskPrompt,
-		editTaskPrompt:        aiSettings.EditTaskPrompt,
-		analyzeLinksPrompt:    aiSettings.AnalyzeLinksPrompt,
-		analyzeAssigneePrompt: aiSettings.AnalyzeAssigneePrompt,
-		taskTemplates:         taskTemplates,
-		taskTemplatesPrompt:   BuildTaskTemplatesPromptSection(taskTemplates),
+		completer:           completer,
+		model:               model,
+		prompts:             NewPromptLibraryFromSettings(aiSettings, store),
+		taskTemplates:       taskTemplates,
+		taskTemplatesPrompt: BuildTaskTemplatesPromptSection(taskTemplates),
+		contextTokens:       contextTokens,
 	}, nil
 }
 
+// completer sends one chat completion request to a provider
+type completer interface {
+	Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error)
+}
+
+// openRouterCompleter calls the OpenAI-compatible chat completions API of OpenRouter
+type openRouterCompleter struct {
+	httpClient *httpclient.Client
+}
+
+func (o *openRouterCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	body := openRouterRequestBody{OpenRouterRequest: request}
+	if request.JSONOutput {
+		body.ResponseFormat = &azureResponseFormat{Type: "json_object"}
+	}
+
+	var response OpenRouterResponse
+	if err := o.httpClient.Post(ctx, "chat/completions", body, &response); err != nil {
+		return nil, fmt.Errorf("OpenRouter API error: %w", err)
+	}
+	return &response, nil
+}
+
+// openRouterRequestBody turns on JSON mode for JSON requests on models that support it
+type openRouterRequestBody struct {
+	OpenRouterRequest
+	ResponseFormat *azureResponseFormat `json:"response_format,omitempty"`
+}
+
 // OpenRouter запрос
 type OpenRouterRequest struct {
 	Model    string              `json:"model"`
 	Messages []OpenRouterMessage `json:"messages"`
 	Stream   bool                `json:"stream"`
 	Options  *OpenRouterOptions  `json:"options,omitempty"`
+	// JSONOutput asks providers with a JSON mode to answer with one JSON object
+	JSONOutput bool `json:"-"`
 }
 
 type OpenRouterMessage struct {
@@ -217,14 +270,18 @@ func (c *AIClient) AnalyzeLinks(ctx context.Context, messages []string, candidat
 		Messages   []string                  `json:"messages"`
 		Candidates []tasklinks.LinkCandidate `json:"candidates"`
 	}{
-		Messages:   messages,
+		Messages:   c.recentMessages(messages),
 		Candidates: candidates,
 	}, "", "  ")
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal link candidates: %w", err)
 	}
 
-	fullPrompt := c.analyzeLinksPrompt + "\n\nInput:\n" + string(requestPayload)
+	analyzeLinksPrompt, err := c.prompts.Get(ctx, PromptAnalyzeLinks)
+	if err != nil {
+		return nil, err
+	}
+	fullPrompt := analyzeLinksPrompt + "\n\nInput:\n" + string(requestPayload)
 
 	request := OpenRouterRequest{
 		Model: c.model,
@@ -234,7 +291,8 @@ func (c *AIClient) AnalyzeLinks(ctx context.Context, messages []string, candidat
 				Content: fullPrompt,
 			},
 		},
-		Stream: false,
+		Stream:     false,
+		JSONOutput: true,
 		Options: &OpenRouterOptions{
 			Temperature: 0.2,
 			MaxTokens:   1200,
@@ -242,33 +300,79 @@ func (c *AIClient) AnalyzeLinks(ctx context.Context, messages []string, candidat
 		},
 	}
 
-	var response OpenRouterResponse
-	err = c.httpClient.Post(ctx, "chat/completions", request, &response)
+	response, err := c.completer.Complete(ctx, request)
 	if err != nil {
-		return nil, fmt.Errorf("OpenRouter API error: %w", err)
+		return nil, err
 	}
 
-	return c.parseLinkAnalysisResponse(&response, candidates)
+	return c.parseLinkAnalysisResponse(response, candidates)
 }
 
 // AnalyzeDiscussion анализирует сообщения используя OpenRouter AI
 func (c *AIClient) AnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalyzedTask, error) {
+	request, err := c.discussionRequest(ctx, messages, selectedLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.completeTask(ctx, request)
+}
+
+// TraceAnalyzeDiscussion runs the same request as AnalyzeDiscussion and keeps
+// everything the model saw and returned. A response that does not parse is
+// reported in the trace, not as an error.
+func (c *AIClient) TraceAnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalysisTrace, error) {
+	request, err := c.discussionRequest(ctx, messages, selectedLinks)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.completer.Complete(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	trace := &AnalysisTrace{
+		Model:  response.Model,
+		Prompt: request.Messages[0].Content,
+		Usage:  response.Usage,
+	}
+	if trace.Model == "" {
+		trace.Model = c.model
+	}
+	if len(response.Choices) > 0 {
+		trace.RawOutput = response.Choices[0].Message.Content
+	}
+	trace.Task, err = c.parseOpenRouterResponse(response)
+	if err != nil {
+		trace.ParseError = err.Error()
+	}
+	return trace, nil
+}
+
+// discussionRequest builds the chat completion request of a discussion analysis
+func (c *AIClient) discussionRequest(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (OpenRouterRequest, error) {
 	if len(messages) == 0 {
-		return nil, fmt.Errorf("no messages to analyze")
+		return OpenRouterRequest{}, fmt.Errorf("no messages to analyze")
 	}
 
-	discussionText := strings.Join(messages, "\n")
+	// Long discussions have their older messages summarized to fit the model context
+	discussionText := strings.Join(c.fitDiscussion(ctx, messages), "\n")
 	selectedLinksJSON, err := json.MarshalIndent(selectedLinks, "", "  ")
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal selected links: %w", err)
+		return OpenRouterRequest{}, fmt.Errorf("failed to marshal selected links: %w", err)
+	}
+	createTaskPrompt, err := c.prompts.Get(ctx, PromptCreateTask)
+	if err != nil {
+		return OpenRouterRequest{}, err
 	}
-	fullPrompt := c.createTaskPrompt +
+	fullPrompt := createTaskPrompt +
 		"\n\n" + c.taskTemplatesPrompt +
 		"\n\nSelected materials. Use these as task materials, but do not decide link usefulness again:\n" + string(selectedLinksJSON) +
 		"\n\nДиалог для анализа:\n" + discussionText +
 		"\n\nОтвет в JSON формате:"
 
-	request := OpenRouterRequest{
+	return OpenRouterRequest{
 		Model: c.model,
 		Messages: []OpenRouterMessage{
 			{
@@ -276,21 +380,14 @@ func (c *AIClient) AnalyzeDiscussion(ctx context.Context, messages []string, sel
 				Content: fullPrompt,
 			},
 		},
-		Stream: false,
+		Stream:     false,
+		JSONOutput: true,
 		Options: &OpenRouterOptions{
 			Temperature: 0.3,
 			MaxTokens:   2000,
 			TopP:        0.9,
 		},
-	}
-
-	var response OpenRouterResponse
-	err = c.httpClient.Post(ctx, "chat/completions", request, &response)
-	if err != nil {
-		return nil, fmt.Errorf("OpenRouter API error: %w", err)
-	}
-
-	return c.parseOpenRouterResponse(&response)
+	}, nil
 }
 
 // EditTask редактирует задачу используя OpenRouter AI
@@ -308,7 +405,11 @@ func (c *AIClient) EditTask(ctx context.Context, task *AnalyzedTask, userFeedbac
 		return nil, fmt.Errorf("failed to marshal task: %w", err)
 	}
 
-	fullPrompt := fmt.Sprintf(c.editTaskPrompt, c.taskTemplatesPrompt, string(taskJSON), userFeedback)
+	editTaskPrompt, err := c.prompts.Get(ctx, PromptEditTask)
+	if err != nil {
+		return nil, err
+	}
+	fullPrompt := fmt.Sprintf(editTaskPrompt, c.taskTemplatesPrompt, string(taskJSON), userFeedback)
 	log.Printf("[OpenRouter edit prompt]: %s", fullPrompt)
 
 	request := OpenRouterRequest{
@@ -319,7 +420,8 @@ func (c *AIClient) EditTask(ctx context.Context, task *AnalyzedTask, userFeedbac
 				Content: fullPrompt,
 			},
 		},
-		Stream: false,
+		Stream:     false,
+		JSONOutput: true,
 		Options: &OpenRouterOptions{
 			Temperature: 0.3,
 			MaxTokens:   2000,
@@ -327,13 +429,7 @@ func (c *AIClient) EditTask(ctx context.Context, task *AnalyzedTask, userFeedbac
 		},
 	}
 
-	var response OpenRouterResponse
-	err = c.httpClient.Post(ctx, "chat/completions", request, &response)
-	if err != nil {
-		return nil, fmt.Errorf("OpenRouter API error: %w", err)
-	}
-
-	return c.parseOpenRouterResponse(&response)
+	return c.completeTask(ctx, request)
 }
 
 func (c *AIClient) AnalyzeAssignee(ctx context.Context, messages []string, assigneeNote string, candidates []AssigneeCandidate) (*AssigneeSelection, error) {
@@ -346,7 +442,7 @@ func (c *AIClient) AnalyzeAssignee(ctx context.Context, messages []string, assig
 		AssigneeNote string              `json:"assignee_note"`
 		Candidates   []AssigneeCandidate `json:"candidates"`
 	}{
-		Messages:     messages,
+		Messages:     c.recentMessages(messages),
 		AssigneeNote: assigneeNote,
 		Candidates:   candidates,
 	}, "", "  ")
@@ -354,7 +450,11 @@ func (c *AIClient) AnalyzeAssignee(ctx context.Context, messages []string, assig
 		return nil, fmt.Errorf("failed to marshal assignee candidates: %w", err)
 	}
 
-	fullPrompt := c.analyzeAssigneePrompt + "\n\nInput:\n" + string(requestPayload)
+	analyzeAssigneePrompt, err := c.prompts.Get(ctx, PromptAnalyzeAssignee)
+	if err != nil {
+		return nil, err
+	}
+	fullPrompt := analyzeAssigneePrompt + "\n\nInput:\n" + string(requestPayload)
 	request := OpenRouterRequest{
 		Model: c.model,
 		Messages: []OpenRouterMessage{
@@ -363,7 +463,8 @@ func (c *AIClient) AnalyzeAssignee(ctx context.Context, messages []string, assig
 				Content: fullPrompt,
 			},
 		},
-		Stream: false,
+		Stream:     false,
+		JSONOutput: true,
 		Options: &OpenRouterOptions{
 			Temperature: 0.2,
 			MaxTokens:   900,
@@ -371,12 +472,75 @@ func (c *AIClient) AnalyzeAssignee(ctx context.Context, messages []string, assig
 		},
 	}
 
-	var response OpenRouterResponse
-	if err := c.httpClient.Post(ctx, "chat/completions", request, &response); err != nil {
-		return nil, fmt.Errorf("OpenRouter API error: %w", err)
+	response, err := c.completer.Complete(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.parseAssigneeAnalysisResponse(response, candidates)
+}
+
+// RunPrompt выполняет именованный промпт из библиотеки над произвольным вводом
+func (c *AIClient) RunPrompt(ctx context.Context, name string, input string) (string, error) {
+	prompt, err := c.prompts.Get(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	// Inputs are discussions joined line by line; a huge one is fitted like in AnalyzeDiscussion
+	input = strings.Join(c.fitDiscussion(ctx, strings.Split(input, "\n")), "\n")
+
+	request := OpenRouterRequest{
+		Model: c.model,
+		Messages: []OpenRouterMessage{
+			{
+				Role:    "user",
+				Content: prompt + "\n\nInput:\n" + input,
+			},
+		},
+		Stream: false,
+		Options: &OpenRouterOptions{
+			Temperature: 0.3,
+			MaxTokens:   2000,
+			TopP:        0.9,
+		},
+	}
+
+	response, err := c.completer.Complete(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no choices in response")
 	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// completeTask sends a task request and parses the answer. An answer that is
+// not a task JSON is sent back with the problems for a bounded number of
+// repair follow-ups before ErrInvalidOutput is returned.
+func (c *AIClient) completeTask(ctx context.Context, request OpenRouterRequest) (*AnalyzedTask, error) {
+	for attempt := 0; ; attempt++ {
+		response, err := c.completer.Complete(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		task, err := c.parseOpenRouterResponse(response)
+		if err == nil {
+			return task, nil
+		}
+		if attempt == maxRepairAttempts || len(response.Choices) == 0 {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+		}
 
-	return c.parseAssigneeAnalysisResponse(&response, candidates)
+		log.Printf("AI task answer is invalid, asking for a repair (%d/%d): %v", attempt+1, maxRepairAttempts, err)
+		// A new slice, so the follow-up never writes into the messages of the first request
+		messages := make([]OpenRouterMessage, 0, len(request.Messages)+2)
+		messages = append(messages, request.Messages...)
+		request.Messages = append(messages,
+			OpenRouterMessage{Role: "assistant", Content: response.Choices[0].Message.Content},
+			OpenRouterMessage{Role: "user", Content: fmt.Sprintf(repairInstruction, err)},
+		)
+	}
 }
 
 // parseOpenRouterResponse парсит ответ OpenRouter
@@ -388,17 +552,17 @@ func (c *AIClient) parseOpenRouterResponse(response *OpenRouterResponse) (*Analy
 	text := response.Choices[0].Message.Content
 	log.Printf("OpenRouter raw response: %s", text)
 
-	jsonStart := strings.Index(text, "{")
-	jsonEnd := strings.LastIndex(text, "}")
-	if jsonStart == -1 || jsonEnd == -1 || jsonEnd <= jsonStart {
-		return nil, fmt.Errorf("no valid JSON found in response")
+	object, err := extractJSONObject(text)
+	if err != nil {
+		return nil, err
+	}
+	if err := analyzedTaskSchema.validate(object); err != nil {
+		return nil, fmt.Errorf("invalid task JSON: %w", err)
 	}
-
-	jsonStr := text[jsonStart : jsonEnd+1]
 
 	var task AnalyzedTask
-	if err := json.Unmarshal([]byte(jsonStr), &task); err != nil {
-		log.Printf("Failed to parse JSON: %s, error: %v", jsonStr, err)
+	if err := json.Unmarshal(object, &task); err != nil {
+		log.Printf("Failed to parse JSON: %s, error: %v", object, err)
 		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
 	}
 
@@ -413,16 +577,15 @@ func (c *AIClient) parseLinkAnalysisResponse(response *OpenRouterResponse, candi
 	text := response.Choices[0].Message.Content
 	log.Printf("OpenRouter raw link response: %s", text)
 
-	jsonStart := strings.Index(text, "{")
-	jsonEnd := strings.LastIndex(text, "}")
-	if jsonStart == -1 || jsonEnd == -1 || jsonEnd <= jsonStart {
-		return nil, fmt.Errorf("no valid JSON found in link response")
+	object, err := extractJSONObject(text)
+	if err != nil {
+		return nil, fmt.Errorf("link response: %w", err)
 	}
 
 	var payload struct {
 		Links []tasklinks.TaskLink `json:"links"`
 	}
-	if err := json.Unmarshal([]byte(text[jsonStart:jsonEnd+1]), &payload); err != nil {
+	if err := json.Unmarshal(object, &payload); err != nil {
 		return nil, fmt.Errorf("failed to parse link response: %w", err)
 	}
 
@@ -437,14 +600,13 @@ func (c *AIClient) parseAssigneeAnalysisResponse(response *OpenRouterResponse, c
 	text := response.Choices[0].Message.Content
 	log.Printf("OpenRouter raw assignee response: %s", text)
 
-	jsonStart := strings.Index(text, "{")
-	jsonEnd := strings.LastIndex(text, "}")
-	if jsonStart == -1 || jsonEnd == -1 || jsonEnd <= jsonStart {
-		return nil, fmt.Errorf("no valid JSON found in assignee response")
+	object, err := extractJSONObject(text)
+	if err != nil {
+		return nil, fmt.Errorf("assignee response: %w", err)
 	}
 
 	var payload AssigneeSelection
-	if err := json.Unmarshal([]byte(text[jsonStart:jsonEnd+1]), &payload); err != nil {
+	if err := json.Unmarshal(object, &payload); err != nil {
 		return nil, fmt.Errorf("failed to parse assignee response: %w", err)
 	}
 
@@ -471,24 +633,13 @@ func (c *AIClient) validateAndCompleteTask(task *AnalyzedTask) *AnalyzedTask {
 		task.Description = "Описание не предоставлено"
 	}
 
-	if task.PriorityText == "" {
-		priorityMap := map[int]string{
-			1: "Низкий",
-			2: "Средний",
-			3: "Высокий",
-			4: "Срочный",
-		}
-		if text, ok := priorityMap[task.Priority]; ok {
-			task.PriorityText = text
-		} else {
-			task.Priority = 1
-			task.PriorityText = "Низкий"
-		}
-	}
-
-	if task.Priority < 1 || task.Priority > 4 {
-		task.Priority = 1
+	// The text always follows the level, whatever wording the model chose
+	level := priority.FromTodoist(task.Priority)
+	if !level.Valid() {
+		level = priority.LevelLow
 	}
+	task.Priority = level.Todoist()
+	task.PriorityText = priority.DefaultNames.Display(level)
 
 	if task.Labels == nil {
 		task.Labels = []string{}
@@ -1,9 +1,12 @@
 package ai
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
 
+	"github.com/user/telegram-bot/configs"
 	"gopkg.in/yaml.v3"
 )
 
@@ -15,6 +18,8 @@ type AiSettings struct {
 	AnalyzeLinksPrompt    string `yaml:"analyze_links_prompt"`
 	AnalyzeAssigneePrompt string `yaml:"analyze_assignee_prompt"`
 	TaskTemplatesDir      string `yaml:"task_templates_dir"`
+	// Prompts holds additional named prompts (summarize, breakdown, digest)
+	Prompts map[string]string `yaml:"prompts,omitempty"`
 }
 
 type AiSettingsRoot struct {
@@ -22,7 +27,7 @@ type AiSettingsRoot struct {
 }
 
 func LoadAiSettings(path string) (AiSettings, error) {
-	b, err := os.ReadFile(path)
+	b, err := configs.ReadFile(path)
 	if err != nil {
 		return AiSettings{}, fmt.Errorf("read prompts: %w", err)
 	}
@@ -68,3 +73,65 @@ Rules:
 - Select at most 10 links.
 - Keep reason compact: 4-8 words, no long sentences.
 - If no link is useful, return {"links":[]}.`
+
+// ValidateAiSettings checks the AI settings file strictly: unknown keys,
+// missing model or prompts and unreadable task templates are reported
+// together, one problem per line.
+func ValidateAiSettings(path string) error {
+	b, err := configs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read prompts: %w", err)
+	}
+
+	var root AiSettingsRoot
+	var problems []error
+
+	decoder := yaml.NewDecoder(bytes.NewReader(b))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&root); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("unmarshal prompts: %w", err)
+		}
+		for _, msg := range typeErr.Errors {
+			problems = append(problems, errors.New(msg))
+		}
+	}
+
+	settings := root.OpenRouter
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"model", settings.Model},
+		{"create_task_prompt", settings.CreateTaskPrompt},
+		{"edit_task_prompt", settings.EditTaskPrompt},
+	}
+	for _, r := range required {
+		if strings.TrimSpace(r.value) == "" {
+			problems = append(problems, fmt.Errorf("openrouter.%s: is required", r.key))
+		}
+	}
+
+	if settings.EditTaskPrompt != "" {
+		if err := ValidatePrompt(PromptEditTask, settings.EditTaskPrompt); err != nil {
+			problems = append(problems, fmt.Errorf("openrouter.edit_task_prompt: %w", err))
+		}
+	}
+	known := promptDefaults(AiSettings{})
+	for name := range settings.Prompts {
+		if _, ok := known[name]; !ok {
+			problems = append(problems, fmt.Errorf("openrouter.prompts.%s: unknown prompt name", name))
+		}
+	}
+
+	templatesDir := settings.TaskTemplatesDir
+	if templatesDir == "" {
+		templatesDir = "configs/task_templates"
+	}
+	if _, err := LoadTaskTemplates(templatesDir); err != nil {
+		problems = append(problems, fmt.Errorf("openrouter.task_templates_dir: %w", err))
+	}
+
+	return errors.Join(problems...)
+}
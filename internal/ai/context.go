@@ -0,0 +1,185 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+)
+
+// EnvContextTokens is the approximate token budget of the discussion in one
+// prompt; longer discussions have their older messages summarized
+const EnvContextTokens = "AI_CONTEXT_TOKENS"
+
+const (
+	// defaultContextTokens leaves room for the prompt and the answer within
+	// the 8K context of small local models
+	defaultContextTokens = 4000
+	// recentContextPercent of the budget keeps the latest messages verbatim
+	recentContextPercent = 60
+	// maxSummaryLevels bounds the summaries of summaries of a huge discussion
+	maxSummaryLevels = 3
+	// chunkSummaryTokens is the answer limit of one chunk summary
+	chunkSummaryTokens = 400
+	// runesPerToken is a conservative estimate for mixed Russian and English text
+	runesPerToken = 3
+)
+
+// earlierMessagesHeader introduces the summary of the messages that did not fit
+const earlierMessagesHeader = "Краткое содержание ранних сообщений обсуждения:"
+
+// ContextTokensFromEnv reads AI_CONTEXT_TOKENS; 0 sends discussions whole
+func ContextTokensFromEnv() (int, error) {
+	return readNonNegative(EnvContextTokens, defaultContextTokens)
+}
+
+// estimateTokens approximates the tokens of a text without a tokenizer
+func estimateTokens(text string) int {
+	return utf8.RuneCountInString(text)/runesPerToken + 1
+}
+
+func estimateMessagesTokens(messages []string) int {
+	total := 0
+	for _, message := range messages {
+		total += estimateTokens(message)
+	}
+	return total
+}
+
+// fitDiscussion keeps a discussion within the context budget: the latest
+// messages stay verbatim and the older ones are replaced by their summary,
+// built chunk by chunk and summarized again while it is still too long.
+// A failed summary drops the older messages rather than the analysis.
+func (c *AIClient) fitDiscussion(ctx context.Context, messages []string) []string {
+	if c.contextTokens <= 0 || estimateMessagesTokens(messages) <= c.contextTokens {
+		return messages
+	}
+
+	older, recent := splitRecent(messages, c.contextTokens*recentContextPercent/100)
+	if len(older) == 0 {
+		return recent
+	}
+	summaryBudget := c.contextTokens - estimateMessagesTokens(recent)
+
+	summary, err := c.summarizeMessages(ctx, older, summaryBudget)
+	if err != nil {
+		log.Printf("Summarizing %d older discussion messages failed, leaving them out: %v", len(older), err)
+		return recent
+	}
+	log.Printf("Discussion of %d messages summarized: %d older messages replaced by a summary", len(messages), len(older))
+	return append([]string{earlierMessagesHeader + "\n" + summary}, recent...)
+}
+
+// splitRecent returns the latest messages that fit into budget and the older
+// ones before them. The last message is always kept, cut to the budget.
+func splitRecent(messages []string, budget int) (older, recent []string) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	start := len(messages)
+	used := 0
+	for start > 0 {
+		tokens := estimateTokens(messages[start-1])
+		if used+tokens > budget {
+			break
+		}
+		used += tokens
+		start--
+	}
+	if start == len(messages) {
+		last := truncateRunes(messages[start-1], budget*runesPerToken)
+		return messages[:start-1], []string{last}
+	}
+	return messages[:start], messages[start:]
+}
+
+// summarizeMessages summarizes messages chunk by chunk until the joined
+// summaries fit into budget
+func (c *AIClient) summarizeMessages(ctx context.Context, messages []string, budget int) (string, error) {
+	prompt, err := c.prompts.Get(ctx, PromptSummarizeChunk)
+	if err != nil {
+		return "", err
+	}
+
+	// Each chunk is at most the whole budget, so its prompt fits like the discussion would
+	chunkBudget := c.contextTokens
+	for level := 0; level < maxSummaryLevels; level++ {
+		var summaries []string
+		for _, chunk := range chunkMessages(messages, chunkBudget) {
+			summary, err := c.summarizeChunk(ctx, prompt, chunk)
+			if err != nil {
+				return "", err
+			}
+			summaries = append(summaries, summary)
+		}
+		joined := strings.Join(summaries, "\n")
+		if estimateTokens(joined) <= budget || len(summaries) == 1 {
+			return truncateRunes(joined, budget*runesPerToken), nil
+		}
+		messages = summaries
+	}
+	return truncateRunes(strings.Join(messages, "\n"), budget*runesPerToken), nil
+}
+
+// chunkMessages groups messages into chunks of about budget tokens; a single
+// message over the budget is cut to fit
+func chunkMessages(messages []string, budget int) [][]string {
+	var chunks [][]string
+	var chunk []string
+	used := 0
+	for _, message := range messages {
+		tokens := estimateTokens(message)
+		if tokens > budget {
+			message = truncateRunes(message, budget*runesPerToken)
+			tokens = budget
+		}
+		if used+tokens > budget && len(chunk) > 0 {
+			chunks = append(chunks, chunk)
+			chunk, used = nil, 0
+		}
+		chunk = append(chunk, message)
+		used += tokens
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func (c *AIClient) summarizeChunk(ctx context.Context, prompt string, chunk []string) (string, error) {
+	request := OpenRouterRequest{
+		Model: c.model,
+		Messages: []OpenRouterMessage{
+			{
+				Role:    "user",
+				Content: prompt + "\n\nInput:\n" + strings.Join(chunk, "\n"),
+			},
+		},
+		Stream: false,
+		Options: &OpenRouterOptions{
+			Temperature: 0.2,
+			MaxTokens:   chunkSummaryTokens,
+			TopP:        0.9,
+		},
+	}
+
+	response, err := c.completer.Complete(ctx, request)
+	if err != nil {
+		return "", err
+	}
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no choices in response")
+	}
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// recentMessages keeps the latest messages that fit the context budget, for
+// requests where older messages matter too little to summarize
+func (c *AIClient) recentMessages(messages []string) []string {
+	if c.contextTokens <= 0 {
+		return messages
+	}
+	_, recent := splitRecent(messages, c.contextTokens)
+	return recent
+}
@@ -0,0 +1,94 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newContextTestClient(completer completer, contextTokens int) *AIClient {
+	return &AIClient{
+		completer:     completer,
+		prompts:       NewPromptLibrary(map[string]string{PromptSummarizeChunk: "summarize"}, nil),
+		contextTokens: contextTokens,
+	}
+}
+
+// discussion returns count messages of about 21 tokens each
+func discussion(count int) []string {
+	messages := make([]string, count)
+	for i := range messages {
+		messages[i] = strings.Repeat("ы", 60)
+	}
+	return messages
+}
+
+func TestFitDiscussion_KeepsShortDiscussion(t *testing.T) {
+	completer := &scriptedCompleter{answers: []string{"сводка"}}
+	client := newContextTestClient(completer, 1000)
+
+	messages := discussion(5)
+	got := client.fitDiscussion(context.Background(), messages)
+
+	if len(got) != len(messages) || len(completer.requests) != 0 {
+		t.Errorf("short discussion changed: %d messages, %d requests", len(got), len(completer.requests))
+	}
+}
+
+func TestFitDiscussion_SummarizesOlderMessages(t *testing.T) {
+	completer := &scriptedCompleter{answers: []string{"сводка"}}
+	client := newContextTestClient(completer, 100)
+
+	messages := discussion(50)
+	messages[49] = "последнее сообщение"
+	got := client.fitDiscussion(context.Background(), messages)
+
+	if !strings.HasPrefix(got[0], earlierMessagesHeader) || !strings.Contains(got[0], "сводка") {
+		t.Errorf("expected a summary first, got %q", got[0])
+	}
+	if got[len(got)-1] != "последнее сообщение" {
+		t.Errorf("expected the latest message verbatim, got %q", got[len(got)-1])
+	}
+	if estimateMessagesTokens(got) > 100+estimateTokens(earlierMessagesHeader) {
+		t.Errorf("fitted discussion is over the budget: %d tokens", estimateMessagesTokens(got))
+	}
+	for _, request := range completer.requests {
+		if estimateTokens(request.Messages[0].Content) > 110 {
+			t.Errorf("chunk prompt is over the budget: %d tokens", estimateTokens(request.Messages[0].Content))
+		}
+	}
+}
+
+func TestFitDiscussion_SummarizesSummariesWhileTooLong(t *testing.T) {
+	// Every summary is as long as a message, so one level of summaries is not enough
+	completer := &scriptedCompleter{answers: []string{strings.Repeat("с", 60)}}
+	client := newContextTestClient(completer, 100)
+
+	got := client.fitDiscussion(context.Background(), discussion(50))
+
+	if !strings.HasPrefix(got[0], earlierMessagesHeader) {
+		t.Fatalf("expected a summary first, got %q", got[0])
+	}
+	if len(completer.requests) <= 12 {
+		t.Errorf("expected summaries of summaries, got %d requests", len(completer.requests))
+	}
+}
+
+func TestFitDiscussion_DropsOlderMessagesWhenSummaryFails(t *testing.T) {
+	client := newContextTestClient(&stubCompleter{err: errors.New("timeout")}, 100)
+
+	got := client.fitDiscussion(context.Background(), discussion(50))
+
+	if len(got) == 0 || len(got) >= 50 || strings.HasPrefix(got[0], earlierMessagesHeader) {
+		t.Errorf("expected only the recent messages, got %d", len(got))
+	}
+}
+
+func TestSplitRecent_CutsHugeLastMessage(t *testing.T) {
+	older, recent := splitRecent([]string{"первое", strings.Repeat("я", 3000)}, 100)
+
+	if len(older) != 1 || len(recent) != 1 || estimateTokens(recent[0]) > 101 {
+		t.Errorf("unexpected split: %d older, %d recent of %d tokens", len(older), len(recent), estimateTokens(recent[0]))
+	}
+}
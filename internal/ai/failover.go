@@ -0,0 +1,176 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// EnvFallbackProviders lists the providers tried in order when AI_PROVIDER
+// fails or times out, e.g. "openai,ollama"
+const EnvFallbackProviders = "AI_FALLBACK_PROVIDERS"
+
+const (
+	// breakerThreshold is how many failures in a row open the circuit of a provider
+	breakerThreshold = 3
+	// breakerCooldown is how long an open circuit skips the provider before trying it again
+	breakerCooldown = time.Minute
+)
+
+// FallbackProvidersFromEnv reads AI_FALLBACK_PROVIDERS, dropping the primary
+// provider and repeats
+func FallbackProvidersFromEnv(primary string) ([]string, error) {
+	var providers []string
+	seen := map[string]bool{primary: true}
+	for _, name := range strings.Split(os.Getenv(EnvFallbackProviders), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		switch name {
+		case ProviderOpenRouter, ProviderAnthropic, ProviderAzureOpenAI, ProviderOpenAI, ProviderOllama:
+		default:
+			return nil, fmt.Errorf("unsupported provider %q in %s", name, EnvFallbackProviders)
+		}
+		seen[name] = true
+		providers = append(providers, name)
+	}
+	return providers, nil
+}
+
+// ProviderConfig is a provider with its client settings from configs/api.yaml
+type ProviderConfig struct {
+	Name   string
+	Config *httpclient.ClientConfig
+}
+
+// NewFailoverClient creates a client that sends each request to the providers
+// of chain in order, moving on when one fails, times out or has its circuit
+// open. A chain of one provider is the plain client of that provider.
+func NewFailoverClient(chain []ProviderConfig, store PromptStore) (Client, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("no AI providers configured")
+	}
+	if len(chain) == 1 {
+		return NewProviderClient(chain[0].Name, chain[0].Config, store)
+	}
+
+	failover := &failoverCompleter{now: time.Now}
+	for _, provider := range chain {
+		next, model, err := newProviderCompleter(provider.Name, provider.Config)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", provider.Name, err)
+		}
+		timeout, err := provider.Config.FailoverTimeoutDuration()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", provider.Name, err)
+		}
+		failover.links = append(failover.links, &failoverLink{
+			provider: provider.Name,
+			model:    model,
+			next:     next,
+			timeout:  timeout,
+		})
+	}
+	return newAIClient(failover, chain[0].Name, failover.links[0].model, store)
+}
+
+// failoverLink is one provider of a failover chain
+type failoverLink struct {
+	provider string
+	model    string
+	next     completer
+	timeout  time.Duration
+
+	mu          sync.Mutex
+	failures    int
+	openedUntil time.Time
+	// probing is set while the single request allowed after the cooldown runs
+	probing bool
+}
+
+// acquire reports whether the circuit lets a request through. After the
+// cooldown one request at a time probes the provider; the others skip it
+// until the probe is recorded or released.
+func (l *failoverLink) acquire(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.failures < breakerThreshold {
+		return true
+	}
+	if now.Before(l.openedUntil) || l.probing {
+		return false
+	}
+	l.probing = true
+	return true
+}
+
+func (l *failoverLink) record(err error, now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.probing = false
+	if err == nil {
+		l.failures = 0
+		return
+	}
+	l.failures++
+	if l.failures >= breakerThreshold {
+		l.openedUntil = now.Add(breakerCooldown)
+	}
+}
+
+// release ends a request that told nothing about the provider
+func (l *failoverLink) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.probing = false
+}
+
+// failoverCompleter tries the providers of a chain in order
+type failoverCompleter struct {
+	links []*failoverLink
+	now   func() time.Time
+}
+
+func (f *failoverCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	var errs []error
+	for i, link := range f.links {
+		if !link.acquire(f.now()) {
+			errs = append(errs, fmt.Errorf("%s: circuit open", link.provider))
+			continue
+		}
+
+		linkCtx, cancel := context.WithTimeout(ctx, link.timeout)
+		linkRequest := request
+		linkRequest.Model = link.model
+		response, err := link.next.Complete(linkCtx, linkRequest)
+		cancel()
+		if err != nil && ctx.Err() != nil {
+			// The caller gave up, which does not count against the provider
+			link.release()
+		} else {
+			link.record(err, f.now())
+		}
+
+		if err == nil {
+			if i > 0 {
+				log.Printf("AI request served by fallback provider %s (%s)", link.provider, link.model)
+			}
+			return response, nil
+		}
+		log.Printf("AI provider %s failed: %v", link.provider, err)
+		errs = append(errs, fmt.Errorf("%s: %w", link.provider, err))
+		// The caller gave up, e.g. the job was canceled; the next provider would be cut off too
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, fmt.Errorf("all AI providers failed: %w", errors.Join(errs...))
+}
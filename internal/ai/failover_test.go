@@ -0,0 +1,164 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// countingCompleter answers with err, or with its model when err is nil
+type countingCompleter struct {
+	err   error
+	calls int
+	model string
+}
+
+func (s *countingCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	s.calls++
+	s.model = request.Model
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &OpenRouterResponse{Model: request.Model, Choices: []OpenRouterChoice{{Message: OpenRouterMessage{Content: "{}"}}}}, nil
+}
+
+func newTestFailover(now *time.Time, completers ...*countingCompleter) *failoverCompleter {
+	failover := &failoverCompleter{now: func() time.Time { return *now }}
+	for i, c := range completers {
+		failover.links = append(failover.links, &failoverLink{
+			provider: []string{"primary", "fallback"}[i],
+			model:    []string{"model-a", "model-b"}[i],
+			next:     c,
+			timeout:  time.Second,
+		})
+	}
+	return failover
+}
+
+func TestFailoverCompleter_FallsBackAndOpensCircuit(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	primary := &countingCompleter{err: errors.New("503 Service Unavailable")}
+	fallback := &countingCompleter{}
+	failover := newTestFailover(&now, primary, fallback)
+
+	for i := 0; i < breakerThreshold+2; i++ {
+		response, err := failover.Complete(context.Background(), OpenRouterRequest{Model: "model-a"})
+		if err != nil {
+			t.Fatalf("Complete() error = %v", err)
+		}
+		if response.Model != "model-b" {
+			t.Errorf("fallback got model %q, want its own model-b", response.Model)
+		}
+	}
+	if primary.calls != breakerThreshold {
+		t.Errorf("primary called %d times, want %d before the circuit opens", primary.calls, breakerThreshold)
+	}
+
+	// After the cooldown the primary is probed again and closes the circuit on success
+	now = now.Add(breakerCooldown)
+	primary.err = nil
+	if _, err := failover.Complete(context.Background(), OpenRouterRequest{}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if primary.calls != breakerThreshold+1 || primary.model != "model-a" {
+		t.Errorf("primary was not probed after the cooldown: %d calls, model %q", primary.calls, primary.model)
+	}
+}
+
+func TestFailoverCompleter_ReportsAllErrors(t *testing.T) {
+	now := time.Now()
+	failover := newTestFailover(&now, &countingCompleter{err: errors.New("timeout")}, &countingCompleter{err: errors.New("connection refused")})
+
+	_, err := failover.Complete(context.Background(), OpenRouterRequest{})
+
+	if err == nil || !containsAll(err.Error(), "primary: timeout", "fallback: connection refused") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestFailoverCompleter_StopsWhenCallerGivesUp(t *testing.T) {
+	now := time.Now()
+	fallback := &countingCompleter{}
+	failover := newTestFailover(&now, &countingCompleter{err: context.Canceled}, fallback)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := failover.Complete(ctx, OpenRouterRequest{}); err == nil {
+		t.Error("expected error for a canceled request")
+	}
+	if fallback.calls != 0 {
+		t.Errorf("fallback called %d times after the caller gave up", fallback.calls)
+	}
+}
+
+func TestFailoverCompleter_CallerCancellationKeepsCircuitClosed(t *testing.T) {
+	now := time.Now()
+	primary := &countingCompleter{err: context.Canceled}
+	failover := newTestFailover(&now, primary, &countingCompleter{})
+
+	for i := 0; i < breakerThreshold+1; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		failover.Complete(ctx, OpenRouterRequest{})
+	}
+
+	if !failover.links[0].acquire(now) {
+		t.Error("canceled requests opened the circuit of the primary")
+	}
+}
+
+func TestFailoverLink_ProbesOneRequestAtATime(t *testing.T) {
+	now := time.Now()
+	link := &failoverLink{}
+	for i := 0; i < breakerThreshold; i++ {
+		link.record(errors.New("503"), now)
+	}
+	if link.acquire(now) {
+		t.Fatal("expected the circuit to be open")
+	}
+
+	now = now.Add(breakerCooldown)
+	if !link.acquire(now) {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if link.acquire(now) {
+		t.Fatal("expected a second request to wait for the probe")
+	}
+
+	// A released probe lets the next request probe instead
+	link.release()
+	if !link.acquire(now) {
+		t.Fatal("expected a new probe after the first was released")
+	}
+	link.record(nil, now)
+	if !link.acquire(now) || !link.acquire(now) {
+		t.Fatal("expected a successful probe to close the circuit")
+	}
+}
+
+func TestFallbackProvidersFromEnv(t *testing.T) {
+	t.Setenv(EnvFallbackProviders, " OpenAI, ollama,openrouter,openai ")
+	providers, err := FallbackProvidersFromEnv(ProviderOpenRouter)
+	if err != nil {
+		t.Fatalf("FallbackProvidersFromEnv() error = %v", err)
+	}
+	if len(providers) != 2 || providers[0] != ProviderOpenAI || providers[1] != ProviderOllama {
+		t.Errorf("providers = %v, want [openai ollama]", providers)
+	}
+
+	t.Setenv(EnvFallbackProviders, "yandex")
+	if _, err := FallbackProvidersFromEnv(ProviderOpenRouter); err == nil {
+		t.Error("expected error for an unknown provider")
+	}
+}
+
+func containsAll(s string, parts ...string) bool {
+	for _, part := range parts {
+		if !strings.Contains(s, part) {
+			return false
+		}
+	}
+	return true
+}
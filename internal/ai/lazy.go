@@ -0,0 +1,104 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+// ErrUnavailable is returned when the AI client could not be configured, so
+// deployments without AI settings can still serve the rest of the bot.
+var ErrUnavailable = errors.New("AI client is not configured")
+
+// LazyClient builds the real client on first use. A failed build is
+// remembered and reported on every call instead of stopping the process.
+type LazyClient struct {
+	build func() (Client, error)
+
+	once   sync.Once
+	client Client
+	err    error
+}
+
+// NewLazyClient returns a client that calls build on the first request
+func NewLazyClient(build func() (Client, error)) *LazyClient {
+	return &LazyClient{build: build}
+}
+
+// Init builds the client now; it is safe to call more than once
+func (l *LazyClient) Init() error {
+	l.once.Do(func() {
+		client, err := l.build()
+		if err != nil {
+			l.err = fmt.Errorf("%w: %v", ErrUnavailable, err)
+			return
+		}
+		l.client = client
+	})
+	return l.err
+}
+
+func (l *LazyClient) AnalyzeLinks(ctx context.Context, messages []string, candidates []tasklinks.LinkCandidate) ([]tasklinks.TaskLink, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	return l.client.AnalyzeLinks(ctx, messages, candidates)
+}
+
+func (l *LazyClient) AnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalyzedTask, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	return l.client.AnalyzeDiscussion(ctx, messages, selectedLinks)
+}
+
+func (l *LazyClient) EditTask(ctx context.Context, task *AnalyzedTask, userFeedback string) (*AnalyzedTask, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	return l.client.EditTask(ctx, task, userFeedback)
+}
+
+func (l *LazyClient) AnalyzeAssignee(ctx context.Context, messages []string, assigneeNote string, candidates []AssigneeCandidate) (*AssigneeSelection, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	return l.client.AnalyzeAssignee(ctx, messages, assigneeNote, candidates)
+}
+
+func (l *LazyClient) RunPrompt(ctx context.Context, name string, input string) (string, error) {
+	if err := l.Init(); err != nil {
+		return "", err
+	}
+	return l.client.RunPrompt(ctx, name, input)
+}
+
+func (l *LazyClient) TraceAnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalysisTrace, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	tracer, ok := l.client.(Tracer)
+	if !ok {
+		return nil, ErrTraceUnsupported
+	}
+	return tracer.TraceAnalyzeDiscussion(ctx, messages, selectedLinks)
+}
+
+// AnalyzeDiscussionAlternatives drafts several tasks when the client can, a
+// single one otherwise
+func (l *LazyClient) AnalyzeDiscussionAlternatives(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink, count int) ([]*AnalyzedTask, error) {
+	if err := l.Init(); err != nil {
+		return nil, err
+	}
+	if analyzer, ok := l.client.(AlternativesAnalyzer); ok {
+		return analyzer.AnalyzeDiscussionAlternatives(ctx, messages, selectedLinks, count)
+	}
+	task, err := l.client.AnalyzeDiscussion(ctx, messages, selectedLinks)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzedTask{task}, nil
+}
@@ -0,0 +1,136 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// ProviderOllama is the local Ollama provider name used in configs/api.yaml and for job queue limits
+const ProviderOllama = "ollama"
+
+const (
+	// EnvOllamaModel is the pulled model the requests use, e.g. "qwen2.5:14b"
+	EnvOllamaModel = "OLLAMA_MODEL"
+	// EnvOllamaNumCtx is the context window in tokens; Ollama's default of
+	// 2048 cuts long discussions, so it is raised unless set
+	EnvOllamaNumCtx = "OLLAMA_NUM_CTX"
+	// EnvOllamaKeepAlive is how long the model stays loaded after a request, e.g. "30m"
+	EnvOllamaKeepAlive = "OLLAMA_KEEP_ALIVE"
+)
+
+const (
+	defaultOllamaModel  = "qwen2.5:7b"
+	defaultOllamaNumCtx = 8192
+)
+
+// NewOllamaClient creates a client for the chat API of a local Ollama server,
+// so discussions never leave the host
+func NewOllamaClient(config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, model, err := newOllamaCompleter(config)
+	if err != nil {
+		return nil, err
+	}
+	return newAIClient(completer, ProviderOllama, model, store)
+}
+
+func newOllamaCompleter(config *httpclient.ClientConfig) (completer, string, error) {
+	numCtx, err := readNonNegative(EnvOllamaNumCtx, defaultOllamaNumCtx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := config.CreateClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	model := os.Getenv(EnvOllamaModel)
+	if model == "" {
+		model = defaultOllamaModel
+	}
+
+	return &ollamaCompleter{
+		httpClient: client,
+		numCtx:     numCtx,
+		keepAlive:  strings.TrimSpace(os.Getenv(EnvOllamaKeepAlive)),
+	}, model, nil
+}
+
+type ollamaRequest struct {
+	Model     string              `json:"model"`
+	Messages  []OpenRouterMessage `json:"messages"`
+	Stream    bool                `json:"stream"`
+	Format    string              `json:"format,omitempty"`
+	KeepAlive string              `json:"keep_alive,omitempty"`
+	Options   ollamaOptions       `json:"options"`
+}
+
+type ollamaOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        float64  `json:"top_p,omitempty"`
+	NumPredict  int      `json:"num_predict,omitempty"`
+	NumCtx      int      `json:"num_ctx,omitempty"`
+}
+
+type ollamaResponse struct {
+	Model           string            `json:"model"`
+	Message         OpenRouterMessage `json:"message"`
+	DoneReason      string            `json:"done_reason"`
+	PromptEvalCount int               `json:"prompt_eval_count"`
+	EvalCount       int               `json:"eval_count"`
+}
+
+// ollamaCompleter translates chat completion requests to the Ollama chat API
+type ollamaCompleter struct {
+	httpClient *httpclient.Client
+	numCtx     int
+	keepAlive  string
+}
+
+func (o *ollamaCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	var response ollamaResponse
+	if err := o.httpClient.Post(ctx, "chat", o.toOllamaRequest(request), &response); err != nil {
+		return nil, fmt.Errorf("Ollama API error: %w", err)
+	}
+	return fromOllamaResponse(&response), nil
+}
+
+// toOllamaRequest turns off streaming, maps max_tokens to num_predict and
+// uses Ollama's JSON format for JSON requests
+func (o *ollamaCompleter) toOllamaRequest(request OpenRouterRequest) ollamaRequest {
+	out := ollamaRequest{
+		Model:     request.Model,
+		Messages:  request.Messages,
+		KeepAlive: o.keepAlive,
+		Options:   ollamaOptions{NumCtx: o.numCtx},
+	}
+	if request.Options != nil {
+		temperature := request.Options.Temperature
+		out.Options.Temperature = &temperature
+		out.Options.TopP = request.Options.TopP
+		out.Options.NumPredict = request.Options.MaxTokens
+	}
+	if request.JSONOutput {
+		out.Format = "json"
+	}
+	return out
+}
+
+func fromOllamaResponse(response *ollamaResponse) *OpenRouterResponse {
+	return &OpenRouterResponse{
+		Model: response.Model,
+		Choices: []OpenRouterChoice{{
+			Message:      OpenRouterMessage{Role: "assistant", Content: response.Message.Content},
+			FinishReason: response.DoneReason,
+		}},
+		Usage: OpenRouterUsage{
+			PromptTokens:     response.PromptEvalCount,
+			CompletionTokens: response.EvalCount,
+			TotalTokens:      response.PromptEvalCount + response.EvalCount,
+		},
+	}
+}
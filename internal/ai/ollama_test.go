@@ -0,0 +1,58 @@
+package ai
+
+import (
+	"testing"
+)
+
+func TestToOllamaRequest(t *testing.T) {
+	completer := &ollamaCompleter{numCtx: 8192, keepAlive: "30m"}
+	request := OpenRouterRequest{
+		Model:      "qwen2.5:7b",
+		Messages:   []OpenRouterMessage{{Role: "user", Content: "Discussion"}},
+		Stream:     true,
+		Options:    &OpenRouterOptions{Temperature: 0.3, MaxTokens: 800},
+		JSONOutput: true,
+	}
+
+	got := completer.toOllamaRequest(request)
+
+	if got.Stream {
+		t.Error("streaming must be off, the completer reads one response")
+	}
+	if got.Format != "json" || got.KeepAlive != "30m" {
+		t.Errorf("unexpected format %q or keep_alive %q", got.Format, got.KeepAlive)
+	}
+	if got.Options.Temperature == nil || *got.Options.Temperature != 0.3 || got.Options.NumPredict != 800 || got.Options.NumCtx != 8192 {
+		t.Errorf("options not mapped: %+v", got.Options)
+	}
+
+	plain := completer.toOllamaRequest(OpenRouterRequest{Messages: request.Messages})
+	if plain.Format != "" || plain.Options.Temperature != nil {
+		t.Errorf("plain request must not set JSON format or temperature: %+v", plain)
+	}
+}
+
+func TestFromOllamaResponse(t *testing.T) {
+	got := fromOllamaResponse(&ollamaResponse{
+		Model:           "qwen2.5:7b",
+		Message:         OpenRouterMessage{Role: "assistant", Content: `{"title":"Задача"}`},
+		DoneReason:      "stop",
+		PromptEvalCount: 120,
+		EvalCount:       30,
+	})
+
+	if len(got.Choices) != 1 || got.Choices[0].Message.Content != `{"title":"Задача"}` {
+		t.Fatalf("unexpected choices: %+v", got.Choices)
+	}
+	if got.Usage.TotalTokens != 150 {
+		t.Errorf("TotalTokens = %d, want 150", got.Usage.TotalTokens)
+	}
+}
+
+func TestNewOllamaClient_RejectsInvalidNumCtx(t *testing.T) {
+	t.Setenv(EnvOllamaNumCtx, "many")
+
+	if _, err := NewOllamaClient(nil, nil); err == nil {
+		t.Error("expected error for invalid OLLAMA_NUM_CTX")
+	}
+}
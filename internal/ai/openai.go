@@ -0,0 +1,73 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// ProviderOpenAI is the provider name of any OpenAI-compatible Chat Completions
+// API (OpenAI, vLLM, LM Studio, LiteLLM…) used in configs/api.yaml and for job queue limits
+const ProviderOpenAI = "openai"
+
+const (
+	// EnvOpenAIModel is the model name sent with every request
+	EnvOpenAIModel = "OPENAI_MODEL"
+	// EnvOpenAIAPIKey is the bearer token; self-hosted servers often need none
+	EnvOpenAIAPIKey = "OPENAI_API_KEY"
+)
+
+const defaultOpenAIModel = "gpt-4o-mini"
+
+// NewOpenAIClient creates a client for an OpenAI-compatible server at the
+// base_url of configs/api.yaml (OPENAI_BASE_URL by default)
+func NewOpenAIClient(config *httpclient.ClientConfig, store PromptStore) (Client, error) {
+	completer, model, err := newOpenAICompleter(config)
+	if err != nil {
+		return nil, err
+	}
+	return newAIClient(completer, ProviderOpenAI, model, store)
+}
+
+func newOpenAICompleter(config *httpclient.ClientConfig) (completer, string, error) {
+	client, err := config.CreateClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	if apiKey := os.Getenv(EnvOpenAIAPIKey); apiKey != "" {
+		client.WithMiddleware(httpclient.HeaderMiddleware(map[string]string{"Authorization": "Bearer " + apiKey}))
+	}
+
+	model := os.Getenv(EnvOpenAIModel)
+	if model == "" {
+		model = defaultOpenAIModel
+	}
+
+	return &openAICompleter{httpClient: client}, model, nil
+}
+
+// openAIRequest is a Chat Completions request: the Azure body plus the model
+type openAIRequest struct {
+	Model string `json:"model"`
+	azureRequest
+}
+
+// openAICompleter sends chat completion requests to an OpenAI-compatible server
+type openAICompleter struct {
+	httpClient *httpclient.Client
+}
+
+func (o *openAICompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	var response OpenRouterResponse
+	if err := o.httpClient.Post(ctx, "chat/completions", toOpenAIRequest(request), &response); err != nil {
+		return nil, fmt.Errorf("OpenAI-compatible API error: %w", err)
+	}
+	return &response, nil
+}
+
+// toOpenAIRequest maps options and JSON mode like toAzureRequest and names the model
+func toOpenAIRequest(request OpenRouterRequest) openAIRequest {
+	return openAIRequest{Model: request.Model, azureRequest: toAzureRequest(request)}
+}
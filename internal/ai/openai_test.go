@@ -0,0 +1,32 @@
+package ai
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestToOpenAIRequest(t *testing.T) {
+	request := OpenRouterRequest{
+		Model:      "llama-3.1-8b-instruct",
+		Messages:   []OpenRouterMessage{{Role: "user", Content: "Discussion"}},
+		Options:    &OpenRouterOptions{Temperature: 0.3, MaxTokens: 800},
+		JSONOutput: true,
+	}
+
+	body, err := json.Marshal(toOpenAIRequest(request))
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`"model":"llama-3.1-8b-instruct"`,
+		`"temperature":0.3`,
+		`"max_tokens":800`,
+		`"response_format":{"type":"json_object"}`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("request %s does not contain %s", body, want)
+		}
+	}
+}
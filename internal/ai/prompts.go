@@ -0,0 +1,226 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Names of prompts in the prompt library
+const (
+	PromptCreateTask      = "create_task"
+	PromptEditTask        = "edit_task"
+	PromptAnalyzeLinks    = "analyze_links"
+	PromptAnalyzeAssignee = "analyze_assignee"
+	PromptSummarize       = "summarize"
+	PromptBreakdown       = "breakdown"
+	PromptDigest          = "digest"
+	// PromptDecisionSummary records why a discussion ended without a task
+	PromptDecisionSummary = "decision_summary"
+	// PromptSplitDiscussion splits a discussion into a parent task and subtasks
+	PromptSplitDiscussion = "split_discussion"
+	// PromptSummarizeChunk condenses the older messages of a discussion too long for one prompt
+	PromptSummarizeChunk = "summarize_chunk"
+)
+
+// editPromptPlaceholders is the number of %s verbs the edit prompt is
+// formatted with: task templates, current task JSON and user feedback.
+const editPromptPlaceholders = 3
+
+// Prompt is one stored version of a named prompt
+type Prompt struct {
+	Name      string
+	Version   int
+	Text      string
+	CreatedAt time.Time
+}
+
+// PromptStore keeps edited prompt versions. The latest version of a name is
+// the active one; names without stored versions use the configured default.
+type PromptStore interface {
+	GetActivePrompt(ctx context.Context, name string) (*Prompt, error)
+	ListPromptVersions(ctx context.Context, name string) ([]Prompt, error)
+	SavePrompt(ctx context.Context, name, text string) (*Prompt, error)
+}
+
+// PromptLibrary resolves prompts by name: an edited version from the store
+// wins over the default from ai_settings.yaml, so prompts can be changed
+// while the bot is running.
+type PromptLibrary struct {
+	defaults map[string]string
+	store    PromptStore
+}
+
+// NewPromptLibrary creates a library over the given defaults. store may be nil.
+func NewPromptLibrary(defaults map[string]string, store PromptStore) *PromptLibrary {
+	return &PromptLibrary{defaults: defaults, store: store}
+}
+
+// Names returns all prompt names known to the library
+func (l *PromptLibrary) Names() []string {
+	names := make([]string, 0, len(l.defaults))
+	for name := range l.defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the configured prompt text for name
+func (l *PromptLibrary) Default(name string) (string, bool) {
+	text, ok := l.defaults[name]
+	return text, ok
+}
+
+// Get returns the active text of a prompt with the chat's conventions from
+// ctx added. Store failures fall back to the default so a database hiccup
+// does not break analysis.
+func (l *PromptLibrary) Get(ctx context.Context, name string) (string, error) {
+	text, ok := l.defaults[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt %q", name)
+	}
+	if l.store != nil {
+		prompt, err := l.store.GetActivePrompt(ctx, name)
+		if err != nil {
+			log.Printf("Error loading prompt %q, using default: %v", name, err)
+		} else if prompt != nil {
+			text = prompt.Text
+		}
+	}
+	return mergeChatPrompt(ctx, name, text), nil
+}
+
+// Set validates and stores a new version of a prompt
+func (l *PromptLibrary) Set(ctx context.Context, name, text string) (*Prompt, error) {
+	if l.store == nil {
+		return nil, fmt.Errorf("prompt store is not configured")
+	}
+	if _, ok := l.defaults[name]; !ok {
+		return nil, fmt.Errorf("unknown prompt %q", name)
+	}
+	if err := ValidatePrompt(name, text); err != nil {
+		return nil, err
+	}
+	return l.store.SavePrompt(ctx, name, text)
+}
+
+// Rollback stores the text of an older version as the newest one, keeping
+// the history linear.
+func (l *PromptLibrary) Rollback(ctx context.Context, name string, version int) (*Prompt, error) {
+	if l.store == nil {
+		return nil, fmt.Errorf("prompt store is not configured")
+	}
+	versions, err := l.store.ListPromptVersions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range versions {
+		if p.Version == version {
+			return l.Set(ctx, name, p.Text)
+		}
+	}
+	return nil, fmt.Errorf("prompt %q has no version %d", name, version)
+}
+
+// History lists stored versions of a prompt, newest first
+func (l *PromptLibrary) History(ctx context.Context, name string) ([]Prompt, error) {
+	if l.store == nil {
+		return nil, nil
+	}
+	return l.store.ListPromptVersions(ctx, name)
+}
+
+// ValidatePrompt checks that a prompt can be used in place of the default
+func ValidatePrompt(name, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("prompt %q must not be empty", name)
+	}
+	if name == PromptEditTask && strings.Count(text, "%s") != editPromptPlaceholders {
+		return fmt.Errorf("prompt %q must contain exactly %d %%s placeholders (templates, task JSON, feedback)", name, editPromptPlaceholders)
+	}
+	return nil
+}
+
+// NewPromptLibraryFromSettings creates a library with defaults taken from
+// ai_settings.yaml. Prompts missing there use built-in texts.
+func NewPromptLibraryFromSettings(settings AiSettings, store PromptStore) *PromptLibrary {
+	return NewPromptLibrary(promptDefaults(settings), store)
+}
+
+func promptDefaults(settings AiSettings) map[string]string {
+	defaults := map[string]string{
+		PromptCreateTask:      settings.CreateTaskPrompt,
+		PromptEditTask:        settings.EditTaskPrompt,
+		PromptAnalyzeLinks:    settings.AnalyzeLinksPrompt,
+		PromptAnalyzeAssignee: settings.AnalyzeAssigneePrompt,
+		PromptSummarize:       defaultSummarizePrompt,
+		PromptBreakdown:       defaultBreakdownPrompt,
+		PromptDigest:          defaultDigestPrompt,
+		PromptDecisionSummary: defaultDecisionSummaryPrompt,
+		PromptSplitDiscussion: defaultSplitDiscussionPrompt,
+		PromptSummarizeChunk:  defaultSummarizeChunkPrompt,
+	}
+	for name, text := range settings.Prompts {
+		if strings.TrimSpace(text) != "" {
+			defaults[name] = text
+		}
+	}
+	return defaults
+}
+
+const defaultSummarizePrompt = `Summarize the discussion below in Russian for a team chat. Write three short parts:
+"Суть:" with 1-3 bullet points on what is discussed, "Решения:" with the decisions taken and their owners,
+"Открытые вопросы:" with what is still unclear or waiting for someone. Write "нет" for an empty part.
+Use "•" bullets, keep it under 15 lines. Return plain text without markdown.`
+
+const defaultBreakdownPrompt = `Split the task below into 2-7 concrete subtasks in Russian.
+Return only raw JSON: {"subtasks": [{"title": "...", "description": "..."}]}`
+
+const defaultDigestPrompt = `Write a short Russian digest of the tasks below for a team chat.
+Group by status, mention due dates, keep it under 15 lines. Return plain text.`
+
+const defaultDecisionSummaryPrompt = `The team discussed the messages below and consciously decided not to create a task.
+Write a short Russian decision summary for the chat in 2-4 sentences: what was discussed, what was decided
+and why no task is needed, e.g. "Обсудили X, решили не заводить задачу, потому что …".
+If the reason is not stated in the discussion, say that it was not named. Return plain text without markdown.`
+
+const defaultSplitDiscussionPrompt = `The team discussed the messages below. Split the work into one parent task and 2-10
+independent subtasks in Russian: the parent names the overall goal, each subtask is one concrete action.
+Return only raw JSON: {"title": "...", "description": "...", "subtasks": [{"title": "...", "description": "..."}]}`
+
+const defaultSummarizeChunkPrompt = `Below is a part of a long team discussion, or summaries of its parts. Condense it in Russian
+for a later task analysis: keep the problem, decisions, requirements, deadlines, names of people, links and
+numbers; drop greetings and repeats. Return plain text, at most 10 short lines.`
+
+// chatPromptKey keys the chat's additions to prompts in a context
+type chatPromptKey struct{ name string }
+
+// ChatPromptNames are the prompts a chat may add its team conventions to
+var ChatPromptNames = []string{PromptCreateTask, PromptEditTask}
+
+// WithChatPrompt adds a chat's team conventions to the named prompt for the
+// requests made with ctx; an empty text leaves the prompt as configured
+func WithChatPrompt(ctx context.Context, name, text string) context.Context {
+	if strings.TrimSpace(text) == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, chatPromptKey{name: name}, strings.TrimSpace(text))
+}
+
+// mergeChatPrompt appends the chat's conventions to a prompt, so the answer
+// format of the default stays in force
+func mergeChatPrompt(ctx context.Context, name, text string) string {
+	conventions, _ := ctx.Value(chatPromptKey{name: name}).(string)
+	if conventions == "" {
+		return text
+	}
+	// The edit prompt is a format string; the conventions must not add verbs to it
+	if name == PromptEditTask {
+		conventions = strings.ReplaceAll(conventions, "%", "%%")
+	}
+	return text + "\n\nTeam conventions of this chat. Follow them when writing the task, unless they conflict with the answer format above:\n" + conventions
+}
@@ -0,0 +1,169 @@
+package ai
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+// ErrInvalidOutput is returned when the model keeps answering with JSON that
+// does not match the expected schema, repair follow-ups included
+var ErrInvalidOutput = errors.New("AI answer does not match the expected format")
+
+// maxRepairAttempts bounds the follow-up requests asking the model to fix an invalid answer
+const maxRepairAttempts = 2
+
+// repairInstruction asks the model to fix its previous answer; formatted with
+// the list of problems
+const repairInstruction = `Your previous answer is not valid for this task: %s.
+Answer again with the corrected single raw JSON object only, no Markdown fences or explanations. Keep the content, fix only the format.`
+
+// schemaField is one property of the JSON object a prompt answers with
+type schemaField struct {
+	name     string
+	kinds    []string // JSON types allowed: string, number, array, object, bool
+	required bool
+	check    func(value any) error
+}
+
+// objectSchema is a small JSON schema for the flat objects the prompts ask for;
+// properties it does not list are allowed
+type objectSchema []schemaField
+
+// analyzedTaskSchema is what create_task and edit_task prompts must answer with
+var analyzedTaskSchema = objectSchema{
+	{name: "title", kinds: []string{"string"}, required: true, check: checkNotBlank},
+	{name: "description", kinds: []string{"string"}},
+	{name: "due_date", kinds: []string{"string"}, check: checkDueDate},
+	{name: "priority", kinds: []string{"number", "string"}, check: checkPriority},
+	{name: "priority_text", kinds: []string{"string"}},
+	{name: "assignee_note", kinds: []string{"string"}},
+	{name: "labels", kinds: []string{"array"}, check: checkStrings},
+	{name: "task_type", kinds: []string{"string"}},
+	{name: "selected_links", kinds: []string{"array"}},
+}
+
+// validate decodes data as a JSON object and reports every property that
+// breaks the schema; null stands for a missing property
+func (s objectSchema) validate(data []byte) error {
+	var object map[string]any
+	if err := json.Unmarshal(data, &object); err != nil {
+		return fmt.Errorf("not a JSON object: %w", err)
+	}
+
+	var problems []string
+	for _, field := range s {
+		value, ok := object[field.name]
+		if !ok || value == nil {
+			if field.required {
+				problems = append(problems, fmt.Sprintf("%q is required", field.name))
+			}
+			continue
+		}
+		kind := jsonKind(value)
+		if !containsString(field.kinds, kind) {
+			problems = append(problems, fmt.Sprintf("%q must be %s, got %s", field.name, strings.Join(field.kinds, " or "), kind))
+			continue
+		}
+		if field.check != nil {
+			if err := field.check(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%q %v", field.name, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// jsonKind names the JSON type of a value decoded into any
+func jsonKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func checkNotBlank(value any) error {
+	if strings.TrimSpace(value.(string)) == "" {
+		return errors.New("must not be empty")
+	}
+	return nil
+}
+
+func checkDueDate(value any) error {
+	date := strings.TrimSpace(value.(string))
+	if date == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("must be YYYY-MM-DD or empty, got %q", date)
+	}
+	return nil
+}
+
+func checkPriority(value any) error {
+	switch v := value.(type) {
+	case float64:
+		if v != float64(int(v)) || !priority.FromTodoist(int(v)).Valid() {
+			return fmt.Errorf("must be 1, 2, 3 or 4, got %v", v)
+		}
+	case string:
+		if _, err := priority.Parse(v); err != nil {
+			return fmt.Errorf("must be 1, 2, 3 or 4, got %q", v)
+		}
+	}
+	return nil
+}
+
+func checkStrings(value any) error {
+	for _, item := range value.([]any) {
+		if _, ok := item.(string); !ok {
+			return errors.New("must contain strings only")
+		}
+	}
+	return nil
+}
+
+// extractJSONObject returns the first complete JSON object of a model answer,
+// skipping Markdown fences and any text around it
+func extractJSONObject(text string) ([]byte, error) {
+	for start := strings.Index(text, "{"); start != -1; {
+		var object json.RawMessage
+		decoder := json.NewDecoder(strings.NewReader(text[start:]))
+		if err := decoder.Decode(&object); err == nil && bytes.HasPrefix(object, []byte("{")) {
+			return object, nil
+		}
+		next := strings.Index(text[start+1:], "{")
+		if next == -1 {
+			break
+		}
+		start += next + 1
+	}
+	return nil, errors.New("no valid JSON found in response")
+}
@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// scriptedCompleter answers with its contents in order and keeps the requests
+type scriptedCompleter struct {
+	answers  []string
+	requests []OpenRouterRequest
+}
+
+func (s *scriptedCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	s.requests = append(s.requests, request)
+	answer := s.answers[0]
+	if len(s.answers) > 1 {
+		s.answers = s.answers[1:]
+	}
+	return &OpenRouterResponse{Choices: []OpenRouterChoice{{Message: OpenRouterMessage{Role: "assistant", Content: answer}}}}, nil
+}
+
+func TestExtractJSONObject(t *testing.T) {
+	tests := map[string]string{
+		"```json\n{\"title\": \"A\"}\n```":                    `{"title": "A"}`,
+		"Вот задача: {\"title\": \"A {b}\"} Готово}":          `{"title": "A {b}"}`,
+		"{broken {\"title\": \"A\"}":                          `{"title": "A"}`,
+		"{\"title\": \"A\", \"labels\": [\"x\"]}\n{\"x\": 1}": `{"title": "A", "labels": ["x"]}`,
+	}
+	for text, want := range tests {
+		got, err := extractJSONObject(text)
+		if err != nil || string(got) != want {
+			t.Errorf("extractJSONObject(%q) = %s, %v; want %s", text, got, err, want)
+		}
+	}
+
+	if _, err := extractJSONObject("no json here"); err == nil {
+		t.Error("expected error without a JSON object")
+	}
+}
+
+func TestAnalyzedTaskSchema_ReportsAllProblems(t *testing.T) {
+	err := analyzedTaskSchema.validate([]byte(`{"title": " ", "due_date": "завтра", "priority": 7, "labels": ["ok", 3]}`))
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, want := range []string{`"title" must not be empty`, `"due_date" must be YYYY-MM-DD`, `"priority" must be 1, 2, 3 or 4`, `"labels" must contain strings only`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+
+	if err := analyzedTaskSchema.validate([]byte(`{"title": "A", "priority": "высокий", "due_date": "", "assignee_note": null, "extra": 1}`)); err != nil {
+		t.Errorf("unexpected error for a valid task: %v", err)
+	}
+}
+
+func TestCompleteTask_RepairsInvalidAnswer(t *testing.T) {
+	completer := &scriptedCompleter{answers: []string{
+		`{"description": "Без заголовка", "priority": 3}`,
+		`{"title": "Починить логин", "description": "Без заголовка", "priority": 3}`,
+	}}
+	client := &AIClient{completer: completer}
+
+	request := OpenRouterRequest{Messages: []OpenRouterMessage{{Role: "user", Content: "prompt"}}}
+	task, err := client.completeTask(context.Background(), request)
+	if err != nil {
+		t.Fatalf("completeTask() error = %v", err)
+	}
+	if task.Title != "Починить логин" {
+		t.Errorf("Title = %q, want the repaired one", task.Title)
+	}
+
+	if len(completer.requests) != 2 {
+		t.Fatalf("expected one repair follow-up, got %d requests", len(completer.requests))
+	}
+	repair := completer.requests[1].Messages
+	if len(repair) != 3 || repair[1].Role != "assistant" || repair[2].Role != "user" || !strings.Contains(repair[2].Content, `"title" is required`) {
+		t.Errorf("unexpected repair conversation: %+v", repair)
+	}
+	if len(completer.requests[0].Messages) != 1 {
+		t.Errorf("repair changed the first request: %+v", completer.requests[0].Messages)
+	}
+}
+
+func TestCompleteTask_GivesUpAfterRepairAttempts(t *testing.T) {
+	completer := &scriptedCompleter{answers: []string{"Не могу ответить"}}
+	client := &AIClient{completer: completer}
+
+	_, err := client.completeTask(context.Background(), OpenRouterRequest{Messages: []OpenRouterMessage{{Role: "user", Content: "prompt"}}})
+
+	if !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("expected ErrInvalidOutput, got %v", err)
+	}
+	if len(completer.requests) != maxRepairAttempts+1 {
+		t.Errorf("expected %d requests, got %d", maxRepairAttempts+1, len(completer.requests))
+	}
+}
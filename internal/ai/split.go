@@ -0,0 +1,54 @@
+package ai
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SplitPlan is a discussion split into a parent task and its subtasks
+type SplitPlan struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Subtasks    []SplitSubtask `json:"subtasks"`
+}
+
+// SplitSubtask is one subtask of a SplitPlan
+type SplitSubtask struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// ParseSplitPlan reads the JSON answer of the split_discussion prompt.
+// Subtasks without a title are dropped.
+func ParseSplitPlan(text string) (*SplitPlan, error) {
+	object, err := extractJSONObject(text)
+	if err != nil {
+		return nil, fmt.Errorf("split response: %w", err)
+	}
+
+	var plan SplitPlan
+	if err := json.Unmarshal(object, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse split response: %w", err)
+	}
+
+	plan.Title = strings.TrimSpace(plan.Title)
+	plan.Description = strings.TrimSpace(plan.Description)
+	subtasks := plan.Subtasks[:0]
+	for _, subtask := range plan.Subtasks {
+		subtask.Title = strings.TrimSpace(subtask.Title)
+		subtask.Description = strings.TrimSpace(subtask.Description)
+		if subtask.Title != "" {
+			subtasks = append(subtasks, subtask)
+		}
+	}
+	plan.Subtasks = subtasks
+
+	if plan.Title == "" {
+		return nil, fmt.Errorf("split response has no parent title")
+	}
+	if len(plan.Subtasks) == 0 {
+		return nil, fmt.Errorf("split response has no subtasks")
+	}
+	return &plan, nil
+}
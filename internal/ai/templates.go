@@ -2,11 +2,11 @@ package ai
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/user/telegram-bot/configs"
 	"github.com/user/telegram-bot/internal/taskfields"
 	"gopkg.in/yaml.v3"
 )
@@ -26,7 +26,7 @@ type taskTemplateFrontMatter struct {
 }
 
 func LoadTaskTemplates(dir string) ([]TaskTemplate, error) {
-	entries, err := os.ReadDir(dir)
+	entries, err := configs.ReadDir(dir)
 	if err != nil {
 		return nil, fmt.Errorf("read task templates dir: %w", err)
 	}
@@ -41,7 +41,7 @@ func LoadTaskTemplates(dir string) ([]TaskTemplate, error) {
 		templateType = normalizeTaskType(templateType)
 
 		path := filepath.Join(dir, entry.Name())
-		content, err := os.ReadFile(path)
+		content, err := configs.ReadFile(path)
 		if err != nil {
 			return nil, fmt.Errorf("read task template %s: %w", path, err)
 		}
@@ -0,0 +1,43 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+// ErrTraceUnsupported is returned when the configured client cannot trace analyses
+var ErrTraceUnsupported = errors.New("AI client does not support analysis traces")
+
+// Tracer is implemented by clients that can expose a single discussion
+// analysis for prompt debugging
+type Tracer interface {
+	TraceAnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*AnalysisTrace, error)
+}
+
+// AnalysisTrace is one discussion analysis as the model saw and answered it
+type AnalysisTrace struct {
+	Model      string          `json:"model"`
+	Prompt     string          `json:"prompt"`
+	RawOutput  string          `json:"raw_output"`
+	Task       *AnalyzedTask   `json:"parsed_task"`
+	ParseError string          `json:"parse_error,omitempty"`
+	Usage      OpenRouterUsage `json:"usage"`
+}
+
+var (
+	redactEmail  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	redactPhone  = regexp.MustCompile(`\+\d[\d\-\s()]{8,}\d|\b[78]9\d{9}\b`)
+	redactSecret = regexp.MustCompile(`\b[A-Za-z0-9_\-]{32,}\b`)
+)
+
+// Redact masks emails, phone numbers and long token-like strings, so prompts
+// can leave the bot for debugging without the personal data and secrets
+// people pasted into the chat
+func Redact(text string) string {
+	text = redactEmail.ReplaceAllString(text, "[email]")
+	text = redactSecret.ReplaceAllString(text, "[secret]")
+	return redactPhone.ReplaceAllString(text, "[phone]")
+}
@@ -0,0 +1,175 @@
+package ai
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvTokenPrices sets the price of a million input and output tokens per model
+// in USD, e.g. "gpt-4o-mini=0.15/0.6,claude-sonnet-4-5=3/15"
+const EnvTokenPrices = "AI_TOKEN_PRICES"
+
+// usageTimeout bounds recording the usage of one request
+const usageTimeout = 5 * time.Second
+
+// Usage is the tokens one AI request spent for a chat and its discussion
+type Usage struct {
+	ChatID           int64
+	SessionID        int
+	Provider         string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+	CreatedAt        time.Time
+}
+
+// UsageLog keeps token usage for accounting. A PromptStore that also
+// implements UsageLog gets the usage of every answered request recorded.
+type UsageLog interface {
+	SaveAIUsage(ctx context.Context, usage Usage) error
+}
+
+// ChatUsage is the token usage of a chat with one model over a period
+type ChatUsage struct {
+	ChatID           int64
+	Model            string
+	Requests         int
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+type usageScopeKey struct{}
+
+// usageScope is the chat and discussion the requests of a context are made for
+type usageScope struct {
+	chatID    int64
+	sessionID int
+}
+
+// WithUsageScope attributes the AI requests made with ctx to a chat and, when
+// sessionID is not zero, to its discussion
+func WithUsageScope(ctx context.Context, chatID int64, sessionID int) context.Context {
+	return context.WithValue(ctx, usageScopeKey{}, usageScope{chatID: chatID, sessionID: sessionID})
+}
+
+func usageScopeFrom(ctx context.Context) usageScope {
+	scope, _ := ctx.Value(usageScopeKey{}).(usageScope)
+	return scope
+}
+
+// usageCompleter records the tokens of every answered request in a UsageLog;
+// recording never fails the request
+type usageCompleter struct {
+	next     completer
+	provider string
+	usage    UsageLog
+	now      func() time.Time
+}
+
+// withUsageLog records the usage of a completer under the provider that served it
+func withUsageLog(next completer, provider string, usage UsageLog) completer {
+	return wrapProviders(next, provider, func(next completer, provider string) completer {
+		return newUsageCompleter(next, provider, usage)
+	})
+}
+
+func newUsageCompleter(next completer, provider string, usage UsageLog) *usageCompleter {
+	return &usageCompleter{next: next, provider: provider, usage: usage, now: time.Now}
+}
+
+func (u *usageCompleter) Complete(ctx context.Context, request OpenRouterRequest) (*OpenRouterResponse, error) {
+	response, err := u.next.Complete(ctx, request)
+	if err != nil || response.Usage.PromptTokens+response.Usage.CompletionTokens == 0 {
+		return response, err
+	}
+
+	scope := usageScopeFrom(ctx)
+	usage := Usage{
+		ChatID:           scope.chatID,
+		SessionID:        scope.sessionID,
+		Provider:         u.provider,
+		Model:            request.Model,
+		PromptTokens:     response.Usage.PromptTokens,
+		CompletionTokens: response.Usage.CompletionTokens,
+		CreatedAt:        u.now(),
+	}
+	if response.Model != "" {
+		usage.Model = response.Model
+	}
+
+	// The request context may already be cancelled once the answer is in
+	saveCtx, cancel := context.WithTimeout(context.Background(), usageTimeout)
+	defer cancel()
+	if err := u.usage.SaveAIUsage(saveCtx, usage); err != nil {
+		log.Printf("Error recording AI usage of chat %d: %v", usage.ChatID, err)
+	}
+	return response, nil
+}
+
+// TokenPrice is the USD price of a million input and output tokens
+type TokenPrice struct {
+	Input  float64
+	Output float64
+}
+
+// TokenPrices are the prices of the models by name
+type TokenPrices map[string]TokenPrice
+
+// TokenPricesFromEnv reads AI_TOKEN_PRICES; models without a price cost nothing
+// in reports and are marked as unpriced
+func TokenPricesFromEnv() (TokenPrices, error) {
+	return ParseTokenPrices(os.Getenv(EnvTokenPrices))
+}
+
+// ParseTokenPrices parses "model=input/output" pairs separated by commas
+func ParseTokenPrices(text string) (TokenPrices, error) {
+	prices := TokenPrices{}
+	for _, entry := range strings.Split(text, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// Model names have slashes and colons, so the price is what follows the last "="
+		eq := strings.LastIndex(entry, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid %s entry %q: want model=input/output", EnvTokenPrices, entry)
+		}
+		model := strings.TrimSpace(entry[:eq])
+		input, output, ok := strings.Cut(entry[eq+1:], "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s entry %q: want model=input/output", EnvTokenPrices, entry)
+		}
+		var price TokenPrice
+		var err error
+		if price.Input, err = parsePrice(input); err != nil {
+			return nil, fmt.Errorf("invalid input price of %s: %w", model, err)
+		}
+		if price.Output, err = parsePrice(output); err != nil {
+			return nil, fmt.Errorf("invalid output price of %s: %w", model, err)
+		}
+		prices[model] = price
+	}
+	return prices, nil
+}
+
+func parsePrice(text string) (float64, error) {
+	price, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil || price < 0 {
+		return 0, fmt.Errorf("want a non-negative number, got %q", text)
+	}
+	return price, nil
+}
+
+// Cost estimates the USD cost of the tokens; false when the model has no price
+func (p TokenPrices) Cost(model string, promptTokens, completionTokens int64) (float64, bool) {
+	price, ok := p[model]
+	if !ok {
+		return 0, false
+	}
+	return (float64(promptTokens)*price.Input + float64(completionTokens)*price.Output) / 1e6, true
+}
@@ -0,0 +1,91 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type recordingUsageLog struct {
+	usage []Usage
+}
+
+func (r *recordingUsageLog) SaveAIUsage(ctx context.Context, usage Usage) error {
+	r.usage = append(r.usage, usage)
+	return nil
+}
+
+func TestUsageCompleter_RecordsTokensOfScope(t *testing.T) {
+	usage := &recordingUsageLog{}
+	next := &stubCompleter{response: &OpenRouterResponse{
+		Model: "model-v2",
+		Usage: OpenRouterUsage{PromptTokens: 120, CompletionTokens: 30, TotalTokens: 150},
+	}}
+	completer := withUsageLog(next, ProviderOpenAI, usage)
+
+	ctx := WithUsageScope(context.Background(), -100123, 42)
+	if _, err := completer.Complete(ctx, OpenRouterRequest{Model: "model"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(usage.usage) != 1 {
+		t.Fatalf("expected one usage record, got %d", len(usage.usage))
+	}
+	got := usage.usage[0]
+	if got.ChatID != -100123 || got.SessionID != 42 || got.Provider != ProviderOpenAI || got.Model != "model-v2" ||
+		got.PromptTokens != 120 || got.CompletionTokens != 30 {
+		t.Errorf("unexpected usage: %+v", got)
+	}
+}
+
+func TestUsageCompleter_SkipsFailedAndEmptyAnswers(t *testing.T) {
+	usage := &recordingUsageLog{}
+
+	withUsageLog(&stubCompleter{err: errors.New("boom")}, ProviderOpenAI, usage).Complete(context.Background(), OpenRouterRequest{})
+	withUsageLog(&stubCompleter{response: &OpenRouterResponse{}}, ProviderOpenAI, usage).Complete(context.Background(), OpenRouterRequest{})
+
+	if len(usage.usage) != 0 {
+		t.Errorf("expected nothing recorded, got %+v", usage.usage)
+	}
+}
+
+func TestUsageCompleter_RecordsEachFailoverProvider(t *testing.T) {
+	usage := &recordingUsageLog{}
+	failover := &failoverCompleter{now: time.Now, links: []*failoverLink{
+		{provider: ProviderOpenRouter, model: "a", timeout: time.Second, next: &stubCompleter{err: errors.New("down")}},
+		{provider: ProviderOllama, model: "b", timeout: time.Second, next: &stubCompleter{response: &OpenRouterResponse{
+			Usage: OpenRouterUsage{PromptTokens: 10, CompletionTokens: 5},
+		}}},
+	}}
+
+	if _, err := withUsageLog(failover, ProviderOpenRouter, usage).Complete(context.Background(), OpenRouterRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(usage.usage) != 1 || usage.usage[0].Provider != ProviderOllama || usage.usage[0].Model != "b" {
+		t.Errorf("expected usage of the fallback provider, got %+v", usage.usage)
+	}
+}
+
+func TestParseTokenPrices(t *testing.T) {
+	prices, err := ParseTokenPrices(" gpt-4o-mini=0.15/0.6, qwen/qwen3.5-35b-a3b=0.1/0.4 ,")
+	if err != nil {
+		t.Fatalf("ParseTokenPrices() error = %v", err)
+	}
+	cost, ok := prices.Cost("gpt-4o-mini", 2_000_000, 1_000_000)
+	if !ok || cost < 0.899 || cost > 0.901 {
+		t.Errorf("Cost() = %v, %v; want 0.9", cost, ok)
+	}
+	if _, ok := prices.Cost("qwen/qwen3.5-35b-a3b", 1, 1); !ok {
+		t.Error("expected a price for a model with a slash")
+	}
+	if _, ok := prices.Cost("unknown", 1, 1); ok {
+		t.Error("expected no price for an unknown model")
+	}
+
+	for _, invalid := range []string{"gpt-4o-mini", "gpt-4o-mini=0.15", "gpt-4o-mini=-1/2", "=1/2"} {
+		if _, err := ParseTokenPrices(invalid); err == nil {
+			t.Errorf("ParseTokenPrices(%q) expected error", invalid)
+		}
+	}
+}
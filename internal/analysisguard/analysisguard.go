@@ -0,0 +1,133 @@
+// Package analysisguard decides whether a discussion holds enough to be worth
+// an AI analysis, so sessions with only "ok" and a sticker never reach the model.
+package analysisguard
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/user/telegram-bot/internal/db"
+)
+
+const (
+	// EnvMinMessages is how many messages a discussion needs, e.g. "2"
+	EnvMinMessages = "ANALYSIS_MIN_MESSAGES"
+	// EnvMinCharacters is how many letters and digits all messages need together
+	EnvMinCharacters = "ANALYSIS_MIN_CHARACTERS"
+	// EnvRequireOtherParticipant set to "true" requires someone besides the
+	// discussion owner to have written
+	EnvRequireOtherParticipant = "ANALYSIS_REQUIRE_OTHER_PARTICIPANT"
+)
+
+const (
+	defaultMinMessages   = 1
+	defaultMinCharacters = 20
+)
+
+// Rules are the minimum a discussion must contain. Zero values disable a check.
+type Rules struct {
+	MinMessages             int
+	MinCharacters           int
+	RequireOtherParticipant bool
+}
+
+// Reason names the rule a discussion failed
+type Reason string
+
+const (
+	ReasonTooFewMessages   Reason = "messages"
+	ReasonTooLittleContent Reason = "characters"
+	ReasonOwnerOnly        Reason = "participants"
+)
+
+// Violation describes why a discussion is not analyzed yet
+type Violation struct {
+	Reason Reason
+	Have   int
+	Need   int
+}
+
+// RulesFromEnv reads ANALYSIS_MIN_MESSAGES, ANALYSIS_MIN_CHARACTERS and
+// ANALYSIS_REQUIRE_OTHER_PARTICIPANT
+func RulesFromEnv() (Rules, error) {
+	minMessages, err := readCount(EnvMinMessages, defaultMinMessages)
+	if err != nil {
+		return Rules{}, err
+	}
+	minCharacters, err := readCount(EnvMinCharacters, defaultMinCharacters)
+	if err != nil {
+		return Rules{}, err
+	}
+
+	rules := Rules{MinMessages: minMessages, MinCharacters: minCharacters}
+	if raw := strings.TrimSpace(os.Getenv(EnvRequireOtherParticipant)); raw != "" {
+		rules.RequireOtherParticipant, err = strconv.ParseBool(raw)
+		if err != nil {
+			return Rules{}, fmt.Errorf("invalid %s %q: %w", EnvRequireOtherParticipant, raw, err)
+		}
+	}
+	return rules, nil
+}
+
+func readCount(name string, fallback int) (int, error) {
+	raw := strings.TrimSpace(os.Getenv(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, raw)
+	}
+	return count, nil
+}
+
+// Check returns the first rule the discussion of ownerID fails, if any
+func (r Rules) Check(messages []db.Message, ownerID int64) (Violation, bool) {
+	if r.MinMessages > 0 && len(messages) < r.MinMessages {
+		return Violation{Reason: ReasonTooFewMessages, Have: len(messages), Need: r.MinMessages}, false
+	}
+
+	if r.MinCharacters > 0 {
+		characters := 0
+		for _, msg := range messages {
+			characters += contentLength(msg.Text)
+		}
+		if characters < r.MinCharacters {
+			return Violation{Reason: ReasonTooLittleContent, Have: characters, Need: r.MinCharacters}, false
+		}
+	}
+
+	if r.RequireOtherParticipant {
+		others := 0
+		for _, msg := range messages {
+			if msg.UserID.Valid && msg.UserID.Int64 != ownerID {
+				others++
+			}
+		}
+		if others == 0 {
+			return Violation{Reason: ReasonOwnerOnly, Need: 1}, false
+		}
+	}
+
+	return Violation{}, true
+}
+
+// contentLength counts letters and digits, leaving out the placeholder that
+// stands in for stickers and other media, e.g. "[sticker 👍]"
+func contentLength(text string) int {
+	if strings.HasPrefix(text, "[") {
+		if end := strings.Index(text, "]"); end > 0 {
+			text = text[end+1:]
+		}
+	}
+	count := 0
+	for _, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,74 @@
+package analysisguard
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func message(userID int64, text string) db.Message {
+	return db.Message{UserID: sql.NullInt64{Int64: userID, Valid: true}, Text: text}
+}
+
+func TestRulesFromEnv(t *testing.T) {
+	t.Setenv(EnvMinMessages, "")
+	t.Setenv(EnvMinCharacters, "")
+	t.Setenv(EnvRequireOtherParticipant, "")
+
+	rules, err := RulesFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules != (Rules{MinMessages: defaultMinMessages, MinCharacters: defaultMinCharacters}) {
+		t.Fatalf("unexpected default rules: %+v", rules)
+	}
+
+	t.Setenv(EnvMinMessages, "3")
+	t.Setenv(EnvMinCharacters, "0")
+	t.Setenv(EnvRequireOtherParticipant, "true")
+	rules, err = RulesFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules != (Rules{MinMessages: 3, RequireOtherParticipant: true}) {
+		t.Fatalf("unexpected rules: %+v", rules)
+	}
+
+	t.Setenv(EnvMinCharacters, "-5")
+	if _, err := RulesFromEnv(); err == nil {
+		t.Fatal("expected error for negative minimum")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const owner = 1
+	rules := Rules{MinMessages: 2, MinCharacters: 20, RequireOtherParticipant: true}
+
+	tests := []struct {
+		name     string
+		messages []db.Message
+		reason   Reason
+		ok       bool
+	}{
+		{"too few messages", []db.Message{message(owner, "починить логин на проде срочно")}, ReasonTooFewMessages, false},
+		{"only ok and a sticker", []db.Message{message(2, "ok"), message(owner, "[sticker 👍]")}, ReasonTooLittleContent, false},
+		{"owner talks to themselves", []db.Message{message(owner, "логин падает на iOS"), message(owner, "после обновления 2.3")}, ReasonOwnerOnly, false},
+		{"real discussion", []db.Message{message(owner, "логин падает на iOS"), message(2, "[photo] вот скрин ошибки")}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation, ok := rules.Check(tt.messages, owner)
+			if ok != tt.ok || violation.Reason != tt.reason {
+				t.Fatalf("expected %q (ok=%v), got %+v (ok=%v)", tt.reason, tt.ok, violation, ok)
+			}
+		})
+	}
+}
+
+func TestCheck_ZeroRulesAllowEverything(t *testing.T) {
+	if _, ok := (Rules{}).Check(nil, 1); !ok {
+		t.Fatal("expected disabled rules to pass")
+	}
+}
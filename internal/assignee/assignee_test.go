@@ -33,6 +33,10 @@ func (s aiStub) AnalyzeAssignee(ctx context.Context, messages []string, assignee
 	return s.selection, s.err
 }
 
+func (s aiStub) RunPrompt(ctx context.Context, name string, input string) (string, error) {
+	return "", nil
+}
+
 func TestParseAndValidateYAML(t *testing.T) {
 	collaborators := []todoist.Collaborator{
 		{ID: "u1", Name: "Alice", Email: "alice@example.com"},
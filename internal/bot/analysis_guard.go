@@ -0,0 +1,17 @@
+package bot
+
+import (
+	"github.com/user/telegram-bot/internal/analysisguard"
+	"github.com/user/telegram-bot/internal/commands"
+)
+
+// SetAnalysisGuard sets the minimum a discussion must contain before /create_task analyzes it
+func (b *Bot) SetAnalysisGuard(rules analysisguard.Rules) {
+	command, ok := b.commandRegistry.Get("create_task")
+	if !ok {
+		return
+	}
+	if createTask, ok := command.(*commands.CreateTaskCommand); ok {
+		createTask.SetAnalysisGuard(rules)
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const (
+	// maxAttachmentSize is Telegram's limit for files bots may download
+	maxAttachmentSize = 20 << 20
+	// maxForwardedAttachments keeps a long discussion from flooding the task with comments
+	maxForwardedAttachments = 10
+	attachmentsTimeout      = 5 * time.Minute
+)
+
+// messageAttachment returns the photo or document of a captured message. Photos
+// come in several sizes, the largest one is kept.
+func messageAttachment(message *tgbotapi.Message) (db.Attachment, bool) {
+	attachment := db.Attachment{MessageID: message.MessageID}
+	switch {
+	case len(message.Photo) > 0:
+		photo := message.Photo[len(message.Photo)-1]
+		attachment.FileID = photo.FileID
+		attachment.FileName = fmt.Sprintf("photo_%d.jpg", message.MessageID)
+		attachment.FileSize = int64(photo.FileSize)
+	case message.Document != nil:
+		attachment.FileID = message.Document.FileID
+		attachment.FileName = message.Document.FileName
+		attachment.FileSize = int64(message.Document.FileSize)
+		if attachment.FileName == "" {
+			attachment.FileName = fmt.Sprintf("document_%d", message.MessageID)
+		}
+	default:
+		return db.Attachment{}, false
+	}
+	if attachment.FileSize > maxAttachmentSize {
+		return db.Attachment{}, false
+	}
+	return attachment, true
+}
+
+// saveAttachment remembers the file of a message captured into a discussion
+func (b *Bot) saveAttachment(ctx context.Context, sessionID int, message *tgbotapi.Message) {
+	attachment, ok := messageAttachment(message)
+	if !ok {
+		return
+	}
+	attachment.SessionID = sessionID
+	if err := b.dbManager.SaveAttachment(ctx, attachment); err != nil {
+		log.Printf("Error saving attachment of message %d: %v", message.MessageID, err)
+	}
+}
+
+// todoistUploader returns the client that comments on the tasks of a chat,
+// false when the chat keeps its tasks outside Todoist
+func (b *Bot) todoistUploader(chatID int64) (todoist.AttachmentUploader, bool) {
+	uploader, ok := b.todoistClient.(todoist.AttachmentUploader)
+	if !ok || (b.trackers != nil && b.trackers.Kind(chatID) != tracker.KindTodoist) {
+		return nil, false
+	}
+	return uploader, true
+}
+
+// forwardAttachments uploads the files of a discussion to the task created from
+// it, one comment per file. Only Todoist tasks take attachments.
+func (b *Bot) forwardAttachments(chatID int64, sessionID int, taskID string) {
+	uploader, ok := b.todoistUploader(chatID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), attachmentsTimeout)
+	defer cancel()
+
+	attachments, err := b.dbManager.GetSessionAttachments(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting attachments of session %d: %v", sessionID, err)
+		return
+	}
+	if len(attachments) > maxForwardedAttachments {
+		log.Printf("Session %d has %d attachments, forwarding the first %d", sessionID, len(attachments), maxForwardedAttachments)
+		attachments = attachments[:maxForwardedAttachments]
+	}
+
+	for _, attachment := range attachments {
+		content, err := b.downloadFile(attachment.FileID, maxAttachmentSize)
+		if err != nil {
+			log.Printf("Error downloading attachment %s: %v", attachment.FileName, err)
+			continue
+		}
+		if len(content) > maxAttachmentSize {
+			continue
+		}
+		file, err := uploader.UploadFile(ctx, attachment.FileName, content)
+		if err != nil {
+			log.Printf("Error uploading attachment %s to task %s: %v", attachment.FileName, taskID, err)
+			continue
+		}
+		if _, err := uploader.AddComment(ctx, taskID, "📎 "+attachment.FileName+" из обсуждения", file); err != nil {
+			log.Printf("Error attaching %s to task %s: %v", attachment.FileName, taskID, err)
+		}
+	}
+}
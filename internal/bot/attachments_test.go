@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestMessageAttachment(t *testing.T) {
+	photo := &tgbotapi.Message{MessageID: 7, Photo: []tgbotapi.PhotoSize{
+		{FileID: "small", FileSize: 100},
+		{FileID: "large", FileSize: 5000},
+	}}
+	if a, ok := messageAttachment(photo); !ok || a.FileID != "large" || a.FileName != "photo_7.jpg" {
+		t.Errorf("expected the largest photo, got %+v", a)
+	}
+
+	document := &tgbotapi.Message{MessageID: 8, Document: &tgbotapi.Document{FileID: "doc", FileName: "logs.txt", FileSize: 42}}
+	if a, ok := messageAttachment(document); !ok || a.FileName != "logs.txt" || a.FileSize != 42 {
+		t.Errorf("expected the document, got %+v", a)
+	}
+
+	huge := &tgbotapi.Message{MessageID: 9, Document: &tgbotapi.Document{FileID: "dump", FileName: "dump.bin", FileSize: maxAttachmentSize + 1}}
+	if _, ok := messageAttachment(huge); ok {
+		t.Error("files Telegram won't let the bot download must be skipped")
+	}
+
+	if _, ok := messageAttachment(&tgbotapi.Message{Text: "hello"}); ok {
+		t.Error("text messages have no attachment")
+	}
+}
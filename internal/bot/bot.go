@@ -2,6 +2,7 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,62 +13,159 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
 	"github.com/user/telegram-bot/internal/ai"
 	"github.com/user/telegram-bot/internal/assignee"
 	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/cooldown"
 	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/dispatch"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/plans"
+	"github.com/user/telegram-bot/internal/quota"
+	"github.com/user/telegram-bot/internal/taskcard"
 	"github.com/user/telegram-bot/internal/tasklinks"
+	"github.com/user/telegram-bot/internal/telemetry"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/topics"
+	"github.com/user/telegram-bot/internal/tracker"
+	"github.com/user/telegram-bot/internal/tts"
 )
 
 type Bot struct {
 	api             *tgbotapi.BotAPI
+	fileEndpoint    string
 	commandRegistry *commands.Registry
 	dbManager       commands.DBManager
 	callbackHandler *commands.CallbackHandler
+	callbacks       *commands.CallbackRouter
 	aiClient        ai.Client
+	aiProvider      string
 	todoistClient   todoist.Client
+	trackers        *tracker.Selector
+	jobQueue        *jobs.Queue
+	planGate        plans.Gate
+	admins          admin.Users
+	chatRouter      ChatRouter
+	synthesizer     tts.Synthesizer
+	channelConfig   ChannelConfig
+	captureLimit    int
+	cooldowns       *cooldown.Limiter
+	taskCards       *taskcard.Renderer
+	notifiers       *notify.Registry
+	telemetry       *telemetry.Collector
+	polling         *pollingClient
+	apiDeprecations *httpclient.DeprecationAlerts
+	dispatcher      *dispatch.Dispatcher
 	wg              sync.WaitGroup
 	stopCh          chan struct{}
 
-	// Track edit sessions
-	editSessions map[int64]string // map[botMessageID]sessionID
-	editMutex    sync.RWMutex
+	// Webhook mode: updates are posted to telegramWebhookPath instead of polled
+	telegramWebhook     TelegramWebhookConfig
+	telegramWebhookPath string
+	webhookUpdates      chan tgbotapi.Update
+
+	// Optional polling watchdog
+	pollingStallTimeout time.Duration
+	onPollingStall      func(stalledFor time.Duration)
+
+	// Optional reminders about created tasks without an assignee
+	taskNudgeAfter time.Duration
+
+	// Quick edits keep added labels the tracker does not have yet
+	createMissingLabels bool
 
 	assigneeUploadSessions map[int64]string // map[botMessageID]"chatID:projectID"
 	assigneeUploadMutex    sync.RWMutex
 
+	// Chats where Telegram reported the bot was blocked or removed
+	inactiveChats map[int64]struct{}
+	inactiveMutex sync.RWMutex
+
+	// Groups whose admins were told that privacy mode hides their messages
+	privacyWarned map[int64]struct{}
+	privacyMutex  sync.Mutex
+
+	// CSV imports: upload requests by bot message and parsed files waiting for confirmation
+	importUploadSessions map[int64]string // map[botMessageID]"chatID:projectID"
+	pendingImports       map[int64]*pendingImport
+	importMutex          sync.Mutex
+
+	// /complete_all and /reschedule previews waiting for confirmation
+	bulkOps *commands.BulkStore
+	// /delete and /list deletions waiting for confirmation
+	deletes *commands.DeleteStore
+	// /split previews waiting for the owner to pick subtasks
+	splitProposals *commands.SplitStore
+
 	// Track the last bot message in a chat that requires a user action.
 	pendingActionMessages map[int64]int
 	pendingActionMutex    sync.RWMutex
 }
 
-func New(telegramToken string, dbManager commands.DBManager, aiClient ai.Client, todoistClient todoist.Client) (*Bot, error) {
-	api, err := tgbotapi.NewBotAPI(telegramToken)
+// ChatRouter tells which instance serves a chat when chats are sharded across
+// several instances.
+type ChatRouter interface {
+	Owns(chatID int64) bool
+	// OwnerURL returns the URL of the instance serving the chat, empty for this one
+	OwnerURL(chatID int64) string
+}
+
+func New(telegramToken string, dbManager commands.DBManager, aiClient ai.Client, todoistClient todoist.Client, jobQueue *jobs.Queue, admins admin.Users, quotaLimits quota.Limits, planGate plans.Gate) (*Bot, error) {
+	endpoint, err := apiEndpointFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if endpoint != tgbotapi.APIEndpoint {
+		log.Printf("Using Telegram Bot API endpoint %s", fmt.Sprintf(endpoint, "<token>", "<method>"))
+	}
+
+	// Telegram responses with Deprecation/Sunset headers are forwarded to the admins
+	apiDeprecations := httpclient.NewDeprecationAlerts(httpclient.DefaultDeprecationInterval)
+	polling := newPollingClient(&http.Client{
+		Transport: httpclient.DeprecationTransport(nil, "telegram", apiDeprecations.Report),
+	})
+	api, err := tgbotapi.NewBotAPIWithClient(telegramToken, endpoint, polling)
 	if err != nil {
 		return nil, err
 	}
 
 	// Initialize command registry
 	registry := commands.NewRegistry()
+	registry.Use(commands.RecoveryMiddleware(dbManager), commands.LoggingMiddleware())
 
 	// Create and register commands
 	startCmd := commands.NewStartCommand(registry, todoistClient, dbManager)
 	registry.Register(startCmd)
 
-	helpCmd := commands.NewHelpCommand(registry)
+	helpCmd := commands.NewHelpCommand(registry, dbManager, admins)
 	registry.Register(helpCmd)
 
+	languageCmd := commands.NewLanguageCommand(dbManager)
+	registry.Register(languageCmd)
+
 	// Task management commands
-	listCmd := commands.NewListCommand(todoistClient)
+	listCmd := commands.NewListCommand(todoistClient, dbManager)
 	registry.Register(listCmd)
 
+	registry.Register(commands.NewStatusCommand(todoistClient, dbManager))
+
+	deletes := commands.NewDeleteStore()
+	registry.Register(commands.NewDeleteCommand(todoistClient, dbManager, deletes))
+
 	// Register discussion flow commands
 	setProjectCmd := commands.NewSetProjectCommand(todoistClient, dbManager)
 	registry.Register(setProjectCmd)
 
+	sectionCmd := commands.NewSectionCommand(dbManager, todoistClient)
+	registry.Register(sectionCmd)
+
 	setAssigneeMapCmd := commands.NewSetAssigneeMapCommand(dbManager)
 	registry.Register(setAssigneeMapCmd)
+	registry.Register(commands.NewMapUserCommand(todoistClient, dbManager))
 
 	startDiscussionCmd := commands.NewStartDiscussionCommand(dbManager, todoistClient)
 	registry.Register(startDiscussionCmd)
@@ -76,32 +174,148 @@ func New(telegramToken string, dbManager commands.DBManager, aiClient ai.Client,
 	registry.Register(cancelCmd)
 
 	// Create task from discussion command
-	createTaskCmd := commands.NewCreateTaskCommand(todoistClient, dbManager, aiClient)
+	createTaskCmd := commands.NewCreateTaskCommand(todoistClient, dbManager, aiClient, quotaLimits, admins)
 	registry.Register(createTaskCmd)
+	registry.Register(commands.NewDraftHistoryCommand(dbManager))
+
+	summaryCmd := commands.NewSummaryCommand(dbManager, aiClient)
+	registry.Register(summaryCmd)
+
+	quotaCmd := commands.NewQuotaCommand(dbManager, quotaLimits, admins)
+	registry.Register(quotaCmd)
+
+	// Billing is optional; self-hosted bots run with plans.Unlimited and no /plan
+	if planManager, ok := planGate.(commands.PlanManager); ok {
+		registry.Register(commands.NewPlanCommand(planManager, dbManager, admins))
+	}
+
+	reactionsCmd := commands.NewReactionsCommand(dbManager)
+	registry.Register(reactionsCmd)
+
+	participantsCmd := commands.NewParticipantsCommand(dbManager)
+	registry.Register(participantsCmd)
+
+	transcriptCmd := commands.NewTranscriptCommand(dbManager)
+	registry.Register(transcriptCmd)
+
+	quietHoursCmd := commands.NewQuietHoursCommand(dbManager)
+	registry.Register(quietHoursCmd)
+
+	digestTimeCmd := commands.NewDigestTimeCommand(dbManager)
+	registry.Register(digestTimeCmd)
+
+	timezoneCmd := commands.NewTimezoneCommand(dbManager)
+	registry.Register(timezoneCmd)
+
+	priorityNamesCmd := commands.NewPriorityNamesCommand(dbManager)
+	registry.Register(priorityNamesCmd)
+
+	taskDefaultsCmd := commands.NewTaskDefaultsCommand(dbManager, admins)
+	registry.Register(taskDefaultsCmd)
+
+	registry.Register(commands.NewSetPromptCommand(dbManager, admins))
+	registry.Register(commands.NewResetPromptCommand(dbManager, admins))
+
+	// Admin commands
+	jobsCmd := commands.NewJobsCommand(jobQueue, dbManager, admins)
+	registry.Register(jobsCmd)
+
+	debugAnalyzeCmd := commands.NewDebugAnalyzeCommand(dbManager, aiClient, admins)
+	registry.Register(debugAnalyzeCmd)
+
+	if _, ok := todoistClient.(todoist.BatchCreator); ok {
+		registry.Register(commands.NewImportCommand(dbManager))
+	}
+
+	if adder, ok := todoistClient.(todoist.QuickAdder); ok {
+		registry.Register(commands.NewQuickCommand(adder, dbManager))
+	}
+	if exporter, ok := todoistClient.(todoist.ProjectExporter); ok {
+		registry.Register(commands.NewBackupCommand(exporter, dbManager, admins))
+	}
+
+	if reporter, ok := todoistClient.(todoist.ProductivityReporter); ok {
+		registry.Register(commands.NewProductivityCommand(reporter, dbManager))
+	}
+
+	if filterer, ok := todoistClient.(todoist.TaskFilterer); ok {
+		registry.Register(commands.NewTodayCommand(filterer, dbManager))
+		registry.Register(commands.NewUpcomingCommand(filterer, dbManager))
+		registry.Register(commands.NewOverdueCommand(filterer, dbManager))
+	}
+
+	bulkOps := commands.NewBulkStore()
+	if updater, ok := todoistClient.(todoist.BulkUpdater); ok {
+		registry.Register(commands.NewCompleteAllCommand(updater, dbManager, bulkOps))
+		registry.Register(commands.NewRescheduleCommand(updater, dbManager, bulkOps))
+	}
+
+	splitProposals := commands.NewSplitStore()
+	if _, ok := todoistClient.(todoist.SubtaskCreator); ok {
+		registry.Register(commands.NewSplitCommand(dbManager, aiClient, splitProposals))
+	}
 
 	// Create callback handler
 	callbackHandler := commands.NewCallbackHandler(todoistClient, dbManager)
 
-	return &Bot{
+	b := &Bot{
 		api:                    api,
+		fileEndpoint:           fileEndpointFor(endpoint),
 		commandRegistry:        registry,
 		dbManager:              dbManager,
 		callbackHandler:        callbackHandler,
 		aiClient:               aiClient,
+		aiProvider:             ai.ProviderOpenRouter,
 		todoistClient:          todoistClient,
+		jobQueue:               jobQueue,
+		planGate:               planGate,
+		admins:                 admins,
+		cooldowns:              cooldown.NewLimiter(cooldown.DefaultRules()),
+		captureLimit:           defaultCaptureLimit,
+		polling:                polling,
+		apiDeprecations:        apiDeprecations,
+		dispatcher:             dispatch.New(defaultUpdateWorkers, defaultUpdateQueueSize),
 		stopCh:                 make(chan struct{}),
-		editSessions:           make(map[int64]string),
 		assigneeUploadSessions: make(map[int64]string),
+		importUploadSessions:   make(map[int64]string),
+		pendingImports:         make(map[int64]*pendingImport),
+		bulkOps:                bulkOps,
+		deletes:                deletes,
+		splitProposals:         splitProposals,
+		inactiveChats:          make(map[int64]struct{}),
+		privacyWarned:          make(map[int64]struct{}),
 		pendingActionMessages:  make(map[int64]int),
-	}, nil
+	}
+	b.callbacks = b.newCallbackRouter()
+	// Cooldowns go first so a rejected command is neither checked nor counted
+	registry.Use(b.cooldownMiddleware, b.preflightMiddleware, b.telemetryMiddleware)
+	apiDeprecations.SetNotify(b.notifyAPIDeprecation)
+	return b, nil
 }
 
 // Start begins listening for updates from Telegram
 func (b *Bot) Start() error {
-	updateConfig := tgbotapi.NewUpdate(0)
-	updateConfig.Timeout = 60
+	var updates tgbotapi.UpdatesChannel
+	if b.telegramWebhook.Enabled() {
+		if err := b.registerTelegramWebhook(); err != nil {
+			return err
+		}
+		updates = b.webhookUpdates
+	} else {
+		updateConfig := tgbotapi.NewUpdate(0)
+		updateConfig.Timeout = pollTimeoutSeconds
+		updates = b.api.GetUpdatesChan(updateConfig)
+	}
 
-	updates := b.api.GetUpdatesChan(updateConfig)
+	b.jobQueue.Start()
+	b.requeueStoredJobs()
+	b.dispatcher.Start()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.registerCommandMenu()
+	}()
 
 	b.wg.Add(1)
 	go func() {
@@ -109,6 +323,35 @@ func (b *Bot) Start() error {
 		b.handleUpdates(updates)
 	}()
 
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.runPreviewCleanup()
+	}()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		b.runDeferredDelivery()
+	}()
+
+	if b.taskNudgeAfter > 0 {
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.runTaskNudges()
+		}()
+	}
+
+	if b.onPollingStall != nil && b.pollingStallTimeout > 0 && !b.telegramWebhook.Enabled() {
+		b.polling.touch()
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.runPollingWatchdog()
+		}()
+	}
+
 	return nil
 }
 
@@ -117,6 +360,31 @@ func (b *Bot) Stop() {
 	close(b.stopCh)
 	b.api.StopReceivingUpdates()
 	b.wg.Wait()
+	// Updates already queued are handled before the jobs they may enqueue stop
+	b.dispatcher.Stop()
+	b.jobQueue.Stop()
+}
+
+// SetChatRouter makes the bot forward webhook updates of chats other
+// instances serve to them
+func (b *Bot) SetChatRouter(router ChatRouter) {
+	b.chatRouter = router
+}
+
+// ownsChat reports whether this instance serves the chat; background loops
+// leave other chats to the instances that own them
+func (b *Bot) ownsChat(chatID int64) bool {
+	return b.chatRouter == nil || b.chatRouter.Owns(chatID)
+}
+
+// NotifyAdmins sends a direct message to every bot admin. Admins who never
+// started a private chat with the bot cannot be reached; those errors are logged.
+func (b *Bot) NotifyAdmins(text string) {
+	for _, adminID := range b.admins.IDs() {
+		if _, err := b.send(tgbotapi.NewMessage(adminID, text)); err != nil {
+			log.Printf("Error notifying admin %d: %v", adminID, err)
+		}
+	}
 }
 
 // handleUpdates processes incoming updates from Telegram
@@ -129,13 +397,22 @@ func (b *Bot) handleUpdates(updates tgbotapi.UpdatesChannel) {
 			if !ok {
 				return
 			}
-			b.handleUpdate(update)
+			b.dispatchUpdate(update)
 		}
 	}
 }
 
 // handleUpdate processes a single update from Telegram
 func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	if chat := update.FromChat(); chat != nil {
+		b.reactivateChat(chat.ID)
+	}
+
+	if update.MyChatMember != nil {
+		b.handleMyChatMember(update.MyChatMember)
+		return
+	}
+
 	if update.Message != nil {
 		b.handleMessage(update.Message)
 		return
@@ -145,72 +422,29 @@ func (b *Bot) handleUpdate(update tgbotapi.Update) {
 		b.handleCallback(update.CallbackQuery)
 		return
 	}
+
+	if update.ChannelPost != nil {
+		b.handleChannelPost(update.ChannelPost)
+		return
+	}
 }
 
 // handleCallback processes callback queries from inline buttons
 func (b *Bot) handleCallback(callback *tgbotapi.CallbackQuery) {
 	log.Printf("[CALLBACK] %s: %s", callback.From.UserName, callback.Data)
 
-	// Extract command type from callback data
-	parts := strings.Split(callback.Data, "_")
-	if len(parts) < 1 {
-		log.Printf("Invalid callback data format: %s", callback.Data)
-		return
-	}
-
-	callbackType := parts[0]
-	log.Printf("Parsed callback type: %s, original data: %s", callbackType, callback.Data)
-
-	// Use our dedicated callback handler for all callback types
-	callbackResp := b.callbackHandler.HandleCallback(callback)
-	if callbackResp != nil && callbackResp.CallbackConfig != nil {
-		_, err := b.api.Request(callbackResp.CallbackConfig)
-		if err != nil {
-			log.Printf("Error sending callback response: %v", err)
-		}
-	}
-
-	// Only delete buttons if the user is the session owner
-	if callbackResp.IsOwner {
-		b.clearPendingActionIfMatches(callback.Message.Chat.ID, callback.Message.MessageID)
-
-		// Clear buttons without touching the already rendered message text/formatting.
-		editMarkup := tgbotapi.NewEditMessageReplyMarkup(callback.Message.Chat.ID, callback.Message.MessageID, tgbotapi.InlineKeyboardMarkup{
-			InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
-		})
-
-		if _, err := b.api.Request(editMarkup); err != nil {
-			log.Println("Error clearing reply markup:", err)
-			return
-		}
-
-		// Check if we need to send the edit message
-		if callbackResp.ResponseMessage != nil {
-			b.sendResponseWithOptions(callbackResp.ResponseMessage, callbackResp.WaitingForReply, callbackResp.SessionID)
-		} else if callbackType != commands.CallbackEdit {
-			// Send a confirmation message for non-edit callbacks
-			var text string
-			switch callbackType {
-			case commands.CallbackConfirm:
-				text = "✅ Задача успешно создана"
-			case commands.CallbackCancel:
-				text = "❌ Создание задачи отменено. Можете продолжать обсуждение"
-			default:
-				// Unknown callback type
-				text = "✅ Создание задачи отменено, продолжайте обсуждение"
-			}
-
-			msg := tgbotapi.NewMessage(callback.Message.Chat.ID, text)
-			_, err := b.api.Send(msg)
-			if err != nil {
-				log.Printf("Error sending confirmation message: %v", err)
-			}
-		}
+	if !b.callbacks.Dispatch(callback) {
+		log.Printf("No handler for callback data: %s", callback.Data)
 	}
 }
 
 // handleMessage processes a single message from a user
 func (b *Bot) handleMessage(message *tgbotapi.Message) {
+	if message.MigrateToChatID != 0 {
+		b.migrateChat(message.Chat.ID, message.MigrateToChatID)
+		return
+	}
+
 	log.Printf("[%s] %s", message.From.UserName, message.Text)
 
 	if message.ReplyToMessage != nil && !message.IsCommand() {
@@ -224,13 +458,22 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 			return
 		}
 
-		b.editMutex.RLock()
-		sessionID, isEditReply := b.editSessions[replyToID]
-		b.editMutex.RUnlock()
+		b.importMutex.Lock()
+		importContext, isImportReply := b.importUploadSessions[replyToID]
+		b.importMutex.Unlock()
+		if isImportReply {
+			b.handleImportReply(message, importContext)
+			return
+		}
 
-		if isEditReply {
-			log.Printf("Got reply to edit request for session %s", sessionID)
-			b.handleEditReply(message, sessionID)
+		// Edit requests are kept in the database, so replies still work after a
+		// restart or when another instance sent the request
+		sessionID, err := b.dbManager.GetPendingEdit(context.Background(), message.Chat.ID, message.ReplyToMessage.MessageID)
+		if err != nil {
+			log.Printf("Error looking up edit request %d in chat %d: %v", replyToID, message.Chat.ID, err)
+		} else if sessionID != 0 {
+			log.Printf("Got reply to edit request for session %d", sessionID)
+			b.enqueueEditReply(message, strconv.Itoa(sessionID))
 			return
 		}
 	}
@@ -241,66 +484,58 @@ func (b *Bot) handleMessage(message *tgbotapi.Message) {
 		}
 	}
 
-	// Save non-command messages during active sessions
-	if message.Text != "" && !message.IsCommand() {
+	// Save non-command messages during active sessions, media included as placeholders
+	if text := capturedText(message, b.captureLimit); text != "" && !message.IsCommand() {
 		ctx := context.Background()
 
-		hasActive, err := b.dbManager.HasActiveSession(ctx, message.Chat.ID)
+		session, err := b.sessionFor(ctx, message)
 		if err != nil {
 			log.Printf("Error checking active session: %v", err)
-		} else if hasActive {
+		} else if session != nil && !b.isCapturedChannelPost(message) {
 			links := tasklinks.ExtractFromTelegramMessage(message)
-			err := b.dbManager.SaveMessage(
+			err := b.dbManager.SaveSessionMessage(
 				ctx,
+				session.ID,
 				message.Chat.ID,
+				topics.ThreadID(message),
 				message.MessageID,
 				int64(message.From.ID),
 				message.From.UserName,
-				message.Text,
+				text,
 				links,
 			)
 			if err != nil {
 				log.Printf("Error saving message: %v", err)
+			} else {
+				b.acknowledgeCaptured(ctx, message)
+				b.addParticipant(ctx, session.ID, message)
+				b.saveAttachment(ctx, session.ID, message)
 			}
 		}
 	}
 
 	// Process commands
 	if message.IsCommand() {
-		commandName := message.Command()
+		commandName, ours := commandFor(message, b.api.Self.UserName)
+		if !ours {
+			return
+		}
 		log.Printf("[COMMAND] %s: %s", message.From.UserName, commandName)
 		command, exists := b.commandRegistry.Get(commandName)
 
 		if !exists {
-			b.sendMessage(message.Chat.ID, "Unknown command. Use /help to see available commands.")
+			b.sendMessage(message.Chat.ID, message, i18n.T(b.replyLanguage(message), i18n.CommandUnknown))
 			return
 		}
 
-		responseMsg := command.Execute(message)
-		if waitingCommand, ok := command.(commands.WaitingReplyCommand); ok {
-			replyKind, replyValue, shouldWait := waitingCommand.WaitingReply(message)
-			if shouldWait {
-				b.sendResponseWithTracking(responseMsg, replyKind, replyValue)
-				return
-			}
-		}
-		b.sendResponse(responseMsg)
+		b.dispatchCommand(command, message)
 	}
 }
 
 func (b *Bot) handleButtonText(message *tgbotapi.Message) bool {
-	buttonCommands := map[string]string{
-		"📁 Выбрать проект":       "set_project",
-		"💬 Начать обсуждение":    "start_discussion",
-		"✅ Создать задачу":       "create_task",
-		"🛑 Завершить обсуждение": "cancel",
-		"📋 Список задач":         "list",
-		"❓ Помощь":               "help",
-	}
-
-	commandName, exists := buttonCommands[message.Text]
+	commandName, exists := commands.MainKeyboardCommand(message.Text)
 	if !exists {
-		log.Printf("[BUTTON] Кнопка не найдена в мапе: '%s'", message.Text)
+		log.Printf("[BUTTON] No button matches '%s'", message.Text)
 		return false
 	}
 
@@ -308,15 +543,86 @@ func (b *Bot) handleButtonText(message *tgbotapi.Message) bool {
 
 	command, exists := b.commandRegistry.Get(commandName)
 	if !exists {
-		b.sendMessage(message.Chat.ID, "Команда недоступна.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(b.replyLanguage(message), i18n.CommandUnavailable))
 		return true
 	}
 
-	responseMsg := command.Execute(message)
-	b.sendResponse(responseMsg)
+	b.dispatchCommand(command, message)
 	return true
 }
 
+// dispatchCommand runs the command of a message or keyboard button through
+// the registry middlewares, which answer themselves when they stop it
+func (b *Bot) dispatchCommand(command commands.Command, message *tgbotapi.Message) {
+	response := b.commandRegistry.Dispatch(context.Background(), command, message, b.runCommand)
+	b.sendCommandResponse(message, response, "", "")
+}
+
+// runCommand is the innermost handler of dispatchCommand: it queues AI
+// commands, runs the others and sends their replies itself, so it returns nil
+func (b *Bot) runCommand(ctx context.Context, command commands.Command, message *tgbotapi.Message) *commands.Response {
+	if queuedCommand, ok := command.(commands.QueuedCommand); ok {
+		b.enqueueCommand(queuedCommand, message)
+		return nil
+	}
+
+	response := commands.RunCommand(ctx, command, message)
+	replyKind, replyValue := "", ""
+	if waitingCommand, ok := command.(commands.WaitingReplyCommand); ok {
+		if kind, value, shouldWait := waitingCommand.WaitingReply(message); shouldWait {
+			replyKind, replyValue = kind, value
+		}
+	}
+	b.sendCommandResponse(message, response, replyKind, replyValue)
+	if command.Name() == "start_discussion" {
+		b.pinDiscussionNotice(message.Chat.ID, message, message.From.ID, actorName(message.From))
+	}
+
+	if documentCommand, ok := command.(commands.DocumentReplyCommand); ok {
+		b.submitBackgroundJob(command.Name(), message.Chat.ID, jobs.PriorityNormal, func(ctx context.Context) error {
+			return b.sendDocumentReply(ctx, documentCommand, message)
+		})
+	}
+
+	if voiceCommand, ok := command.(commands.VoiceReplyCommand); ok && b.synthesizer != nil {
+		if text := voiceCommand.VoiceText(ctx, message); text != "" {
+			go b.sendVoice(message.Chat.ID, text)
+		}
+	}
+	return nil
+}
+
+// sendCommandResponse sends the items of a command response to the chat of
+// the command in order. Reply tracking applies to the last text message, the
+// one users answer.
+func (b *Bot) sendCommandResponse(command *tgbotapi.Message, response *commands.Response, replyKind, replyValue string) {
+	if response == nil {
+		return
+	}
+	chatID := command.Chat.ID
+	last := -1
+	for i, item := range response.Items {
+		if _, ok := item.(tgbotapi.MessageConfig); ok {
+			last = i
+		}
+	}
+	for i, item := range response.Items {
+		msg, ok := item.(tgbotapi.MessageConfig)
+		switch {
+		case ok && i == last:
+			inTopic(&msg, command)
+			b.sendResponseWithTracking(&msg, replyKind, replyValue)
+		case ok:
+			inTopic(&msg, command)
+			b.sendResponse(&msg)
+		default:
+			if err := b.request(chatID, item); err != nil {
+				log.Printf("Error sending command response item %T: %v", item, err)
+			}
+		}
+	}
+}
+
 // sendResponse sends a message with debugging logs
 func (b *Bot) sendResponse(msgConfig *tgbotapi.MessageConfig) {
 	b.sendResponseWithTracking(msgConfig, "", "")
@@ -346,19 +652,24 @@ func (b *Bot) sendResponseWithTracking(msgConfig *tgbotapi.MessageConfig, replyK
 		b.deletePendingActionMessage(msgConfig.ChatID)
 	}
 
-	sent, err := b.api.Send(msgConfig)
+	sent, err := b.send(*msgConfig)
 	if err != nil {
 		log.Printf("Error sending message: %v", err)
 		log.Printf("Message text was: %s", msgConfig.Text)
 		return
 	}
 
-	if replyKind == "edit" && replyValue != "" {
-		b.editMutex.Lock()
-		b.editSessions[int64(sent.MessageID)] = replyValue
-		b.editMutex.Unlock()
+	b.recordPreview(msgConfig, sent)
 
-		log.Printf("Added edit session for message ID %d, session %s", sent.MessageID, replyValue)
+	if replyKind == "edit" && replyValue != "" {
+		sessionID, err := strconv.Atoi(replyValue)
+		if err != nil {
+			log.Printf("Invalid edit session %q for message ID %d: %v", replyValue, sent.MessageID, err)
+		} else if err := b.dbManager.SavePendingEdit(context.Background(), sent.Chat.ID, sent.MessageID, sessionID); err != nil {
+			log.Printf("Error saving edit session for message ID %d: %v", sent.MessageID, err)
+		} else {
+			log.Printf("Added edit session for message ID %d, session %s", sent.MessageID, replyValue)
+		}
 	}
 
 	if replyKind == commands.ReplyKindAssigneeMapUpload && replyValue != "" {
@@ -367,6 +678,12 @@ func (b *Bot) sendResponseWithTracking(msgConfig *tgbotapi.MessageConfig, replyK
 		b.assigneeUploadMutex.Unlock()
 	}
 
+	if replyKind == commands.ReplyKindImportUpload && replyValue != "" {
+		b.importMutex.Lock()
+		b.importUploadSessions[int64(sent.MessageID)] = replyValue
+		b.importMutex.Unlock()
+	}
+
 	if requiresAction {
 		b.pendingActionMutex.Lock()
 		b.pendingActionMessages[msgConfig.ChatID] = sent.MessageID
@@ -375,8 +692,11 @@ func (b *Bot) sendResponseWithTracking(msgConfig *tgbotapi.MessageConfig, replyK
 }
 
 // sendMessage simplified method for sending text messages
-func (b *Bot) sendMessage(chatID int64, text string) {
+// sendMessage sends text to the chat, in the forum topic of trigger when the
+// text answers a message; chat-wide texts pass a nil trigger
+func (b *Bot) sendMessage(chatID int64, trigger *tgbotapi.Message, text string) {
 	msg := tgbotapi.NewMessage(chatID, text)
+	inTopic(&msg, trigger)
 	b.sendResponse(&msg)
 }
 
@@ -385,48 +705,45 @@ func containsHTTPLink(text string) bool {
 }
 
 // handleEditReply processes a user's reply to an edit request message
-func (b *Bot) handleEditReply(message *tgbotapi.Message, sessionID string) {
+func (b *Bot) handleEditReply(ctx context.Context, message *tgbotapi.Message, sessionID string) {
 	log.Printf("Processing edit request for session %s: %s", sessionID, message.Text)
 
-	// Clean up the tracking
-	b.editMutex.Lock()
-	delete(b.editSessions, int64(message.ReplyToMessage.MessageID))
-	b.editMutex.Unlock()
-
 	// Get draft task from database
 	sessionIDInt, _ := strconv.Atoi(sessionID)
-	ctx := context.Background()
+	ctx = ai.WithUsageScope(ctx, message.Chat.ID, sessionIDInt)
+	lang := b.replyLanguage(message)
+	ctx = commands.ChatPromptContext(ctx, b.dbManager, message.Chat.ID, ai.PromptEditTask)
 	draftTask, err := b.dbManager.GetDraftTask(ctx, sessionIDInt)
 	if err != nil {
 		log.Printf("Error retrieving draft task: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Error retrieving task details")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.EditDraftLoadFailed))
 		return
 	}
-	aiTask := &ai.AnalyzedTask{
-		Title:          draftTask.Title.String,
-		Description:    draftTask.Description.String,
-		DueDate:        draftTask.DueISO.String,
-		Priority:       int(draftTask.Priority.Int32),
-		PriorityText:   "",
-		AssigneeNote:   draftTask.AssigneeNote.String,
-		Labels:         []string(draftTask.Labels),
-		TaskType:       draftTask.TaskType.String,
-		MissingDetails: []string(draftTask.MissingDetails),
-		SelectedLinks:  []tasklinks.TaskLink(draftTask.SelectedLinks),
-		TaskFields:     draftTask.Fields,
-	}
+	aiTask := commands.DraftToAnalyzedTask(draftTask)
 
 	editedTask, err := b.aiClient.EditTask(ctx, aiTask, message.Text)
+	if ctx.Err() != nil {
+		log.Printf("Edit for session %s canceled: %v", sessionID, ctx.Err())
+		return
+	}
 	if err != nil {
 		log.Printf("Error editing task: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Error editing task")
+		if errors.Is(err, ai.ErrUnavailable) {
+			b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.AIUnavailable))
+			return
+		}
+		if errors.Is(err, ai.ErrInvalidOutput) {
+			b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.AIInvalidOutput))
+			return
+		}
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.EditFailed))
 		return
 	}
 
 	projectID, err := b.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
 	if err != nil {
 		log.Printf("Error getting Todoist project for assignee resolution: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Error retrieving project settings")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.ErrorProjectLoad))
 		return
 	}
 
@@ -458,7 +775,13 @@ func (b *Bot) handleEditReply(message *tgbotapi.Message, sessionID string) {
 		}
 	}
 
-	err = b.dbManager.SaveDraftTask(ctx, db.DraftTaskInput{
+	if ctx.Err() != nil {
+		log.Printf("Edit for session %s canceled before saving: %v", sessionID, ctx.Err())
+		return
+	}
+
+	editedTask.DueDate = commands.ConvertDueDate(editedTask.DueDate, b.chatNow(message.Chat.ID))
+	draft := db.DraftTaskInput{
 		SessionID:      sessionIDInt,
 		Title:          editedTask.Title,
 		Description:    editedTask.Description,
@@ -471,29 +794,50 @@ func (b *Bot) handleEditReply(message *tgbotapi.Message, sessionID string) {
 		AssigneeNote:   editedTask.AssigneeNote,
 		Assignee:       resolvedAssignee,
 		Fields:         editedTask.TaskFields,
-	})
-	if err != nil {
+	}
+	if err := b.dbManager.SaveDraftTask(ctx, draft); err != nil {
 		log.Printf("Error saving edited task: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Error saving task")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.EditSaveFailed))
 		return
 	}
+	commands.RecordDraftRevision(ctx, b.dbManager, draft, message.Text)
 
-	responseText := "✅ Задача обновлена!\n\nИзменения сохранены:\n"
-	responseText += commands.FormatTaskPreview(
-		editedTask,
-		editedTask.DueDate,
-		editedTask.AssigneeNote,
-		resolvedAssignee,
-		"Если хочешь, просто ответь на это сообщение и дополни это в задаче.",
-	)
-	responseText += "\n\n"
+	b.sendUpdatedDraft(message.Chat.ID, message, sessionIDInt, editedTask, resolvedAssignee)
+}
+
+// sendUpdatedDraft shows the edited draft with the confirm/edit/cancel and undo buttons
+func (b *Bot) sendUpdatedDraft(chatID int64, trigger *tgbotapi.Message, sessionID int, task *ai.AnalyzedTask, resolvedAssignee db.AssigneeSnapshot) {
+	lang := b.chatLanguage(chatID)
+	b.sendDraftPreview(chatID, trigger, lang, i18n.T(lang, i18n.PreviewUpdated), task, resolvedAssignee, commands.EditedDraftKeyboard(sessionID, lang))
+}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, responseText)
+// sendDraftPreview shows a draft under header with the given buttons, in the
+// forum topic of trigger
+func (b *Bot) sendDraftPreview(chatID int64, trigger *tgbotapi.Message, lang i18n.Lang, header string, task *ai.AnalyzedTask, resolvedAssignee db.AssigneeSnapshot, keyboard tgbotapi.InlineKeyboardMarkup) {
+	msg := tgbotapi.NewMessage(chatID, b.draftPreviewText(chatID, lang, header, task, resolvedAssignee))
 	msg.ParseMode = "Markdown"
 	msg.DisableWebPagePreview = true
-	msg.ReplyMarkup = commands.CreateInlineKeyboard(sessionIDInt)
+	msg.ReplyMarkup = keyboard
+	inTopic(&msg, trigger)
 
 	b.sendResponse(&msg)
+	b.sendVoicePreview(chatID, msg.Text)
+}
+
+// draftPreviewText is a draft preview under header in the chat's priority names
+func (b *Bot) draftPreviewText(chatID int64, lang i18n.Lang, header string, task *ai.AnalyzedTask, resolvedAssignee db.AssigneeSnapshot) string {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	commands.ApplyPriorityNames(task, commands.ChatPriorityNames(ctx, b.dbManager, chatID))
+
+	return header + commands.FormatTaskPreview(
+		task,
+		task.DueDate,
+		task.AssigneeNote,
+		resolvedAssignee,
+		i18n.T(lang, i18n.PreviewReplyHint),
+		lang,
+	) + "\n\n"
 }
 
 func buildMessageTexts(messages []db.Message) []string {
@@ -515,23 +859,24 @@ func (b *Bot) handleAssigneeMapReply(message *tgbotapi.Message, uploadContext st
 	b.assigneeUploadMutex.Lock()
 	delete(b.assigneeUploadSessions, int64(message.ReplyToMessage.MessageID))
 	b.assigneeUploadMutex.Unlock()
+	lang := b.replyLanguage(message)
 
 	if message.Document == nil {
-		b.sendMessage(message.Chat.ID, "❌ Пришлите YAML-файл документом в ответ на сообщение бота.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingSendDocument))
 		return
 	}
 
 	parts := strings.SplitN(uploadContext, ":", 2)
 	if len(parts) != 2 {
-		b.sendMessage(message.Chat.ID, "❌ Внутренняя ошибка загрузки маппинга.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingInternalError))
 		return
 	}
 	projectID := parts[1]
 
-	fileURL, err := b.api.GetFileDirectURL(message.Document.FileID)
+	fileURL, err := b.fileURL(message.Document.FileID)
 	if err != nil {
 		log.Printf("Error getting Telegram file URL: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Не удалось получить файл из Telegram.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingFileFailed))
 		return
 	}
 
@@ -539,20 +884,20 @@ func (b *Bot) handleAssigneeMapReply(message *tgbotapi.Message, uploadContext st
 	resp, err := httpClient.Get(fileURL)
 	if err != nil {
 		log.Printf("Error downloading Telegram file: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Не удалось скачать YAML-файл.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingDownloadFailed))
 		return
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		b.sendMessage(message.Chat.ID, "❌ Telegram вернул ошибку при скачивании файла.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingDownloadError))
 		return
 	}
 
 	raw, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Printf("Error reading uploaded mapping file: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Не удалось прочитать YAML-файл.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingReadFailed))
 		return
 	}
 
@@ -560,27 +905,27 @@ func (b *Bot) handleAssigneeMapReply(message *tgbotapi.Message, uploadContext st
 	collaborators, err := b.todoistClient.GetProjectCollaborators(ctx, projectID)
 	if err != nil {
 		log.Printf("Error loading collaborators for mapping import: %v", err)
-		b.sendMessage(message.Chat.ID, "❌ Не удалось загрузить участников Todoist-проекта.")
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.MappingCollaboratorsFailed))
 		return
 	}
 
 	mappings, summary, err := assignee.ParseAndValidateYAML(message.Chat.ID, projectID, raw, collaborators)
 	if err != nil {
-		b.sendMessage(message.Chat.ID, fmt.Sprintf("❌ Не удалось импортировать YAML-маппинг: %v", err))
+		b.sendMessage(message.Chat.ID, message, fmt.Sprintf(i18n.T(lang, i18n.MappingImportFailed), err))
 		return
 	}
 
 	if err := b.dbManager.ReplaceAssigneeMappings(ctx, message.Chat.ID, projectID, mappings); err != nil {
 		log.Printf("Error saving assignee mappings: %v", err)
-		b.sendMessage(message.Chat.ID, userFacingAssigneeMappingSaveError(err))
+		b.sendMessage(message.Chat.ID, message, userFacingAssigneeMappingSaveError(err, lang))
 		return
 	}
 
-	text := fmt.Sprintf("✅ Маппинг исполнителей обновлён.\nУчастников Todoist: %d\nЗагружено alias: %d", summary.CollaboratorsCount, summary.AliasesCount)
+	text := fmt.Sprintf(i18n.T(lang, i18n.MappingUpdated), summary.CollaboratorsCount, summary.AliasesCount)
 	if len(summary.Warnings) > 0 {
 		log.Printf("Assignee mapping imported with warnings for chat=%d project=%s: %s", message.Chat.ID, projectID, strings.Join(summary.Warnings, "; "))
 	}
-	b.sendMessage(message.Chat.ID, text)
+	b.sendMessage(message.Chat.ID, message, text)
 }
 
 func shouldPreferManualAssigneeResolution(userFeedback, previousAssigneeNote, editedAssigneeNote string) bool {
@@ -612,17 +957,17 @@ func shouldPreferManualAssigneeResolution(userFeedback, previousAssigneeNote, ed
 	return strings.Contains(userFeedback, "@")
 }
 
-func userFacingAssigneeMappingSaveError(err error) string {
+func userFacingAssigneeMappingSaveError(err error, lang i18n.Lang) string {
 	if err == nil {
-		return "❌ Не удалось сохранить маппинг исполнителей."
+		return i18n.T(lang, i18n.MappingSaveFailed)
 	}
 
 	errText := err.Error()
 	if strings.Contains(errText, "duplicate key value violates unique constraint") {
-		return "❌ Не удалось сохранить маппинг исполнителей: в YAML есть alias, которые после нормализации совпадают. Уберите дубли вроде `@user` и `user` для одного и того же ключа."
+		return i18n.T(lang, i18n.MappingSaveDuplicates)
 	}
 
-	return fmt.Sprintf("❌ Не удалось сохранить маппинг исполнителей: %s.", errText)
+	return fmt.Sprintf(i18n.T(lang, i18n.MappingSaveError), errText)
 }
 
 func hasInlineKeyboard(msgConfig *tgbotapi.MessageConfig) bool {
@@ -651,12 +996,12 @@ func (b *Bot) deletePendingActionMessage(chatID int64) {
 		return
 	}
 
-	b.editMutex.Lock()
-	delete(b.editSessions, int64(messageID))
-	b.editMutex.Unlock()
+	if err := b.dbManager.DeletePendingEdit(context.Background(), chatID, messageID); err != nil {
+		log.Printf("Error forgetting edit request %d in chat %d: %v", messageID, chatID, err)
+	}
 
 	deleteMsg := tgbotapi.NewDeleteMessage(chatID, messageID)
-	if _, err := b.api.Request(deleteMsg); err != nil {
+	if err := b.request(chatID, deleteMsg); err != nil {
 		log.Printf("Error deleting previous action message %d in chat %d: %v", messageID, chatID, err)
 	}
 }
@@ -0,0 +1,83 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+const bulkTimeout = 5 * time.Minute
+
+// handleBulkCallback runs or drops the pending bulk operation of the chat
+func (b *Bot) handleBulkCallback(c *commands.CallbackContext) {
+	chatID := c.ChatID()
+	op, isOwner := b.bulkOps.Take(chatID, c.Query.From.ID)
+
+	switch {
+	case op == nil:
+		c.Answer(c.T(i18n.BulkGone))
+		return
+	case !isOwner:
+		c.Answer(c.T(i18n.BulkOwnerOnly))
+		return
+	}
+	c.Answer("")
+
+	b.clearPendingActionIfMatches(chatID, c.MessageID())
+	c.ClearButtons()
+
+	if c.Data.Action == commands.CallbackBulkCancel {
+		b.sendMessage(chatID, c.Query.Message, c.T(i18n.BulkCancelled))
+		return
+	}
+
+	go b.runBulk(chatID, c.Query.Message, c.Lang(), op)
+}
+
+func (b *Bot) runBulk(chatID int64, trigger *tgbotapi.Message, lang i18n.Lang, op *commands.BulkOperation) {
+	updater, ok := b.todoistClient.(todoist.BulkUpdater)
+	if !ok {
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.BulkUnsupported))
+		return
+	}
+
+	progressID := b.sendProgressMessage(chatID, trigger, fmt.Sprintf(i18n.T(lang, i18n.BulkProgress), len(op.Tasks)))
+	defer b.deleteMessage(chatID, progressID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), bulkTimeout)
+	defer cancel()
+
+	var results []todoist.BatchTaskResult
+	var err error
+	if op.Kind == commands.BulkReschedule {
+		results, err = updater.RescheduleTasksBatch(ctx, op.TaskIDs(), op.Due)
+	} else {
+		results, err = updater.CompleteTasksBatch(ctx, op.TaskIDs())
+	}
+	if err != nil {
+		log.Printf("Error running bulk %s in chat %d: %v", op.Kind, chatID, err)
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.BulkFailed))
+		return
+	}
+	b.sendMessage(chatID, trigger, commands.FormatBulkReport(op, results, lang))
+
+	if op.Kind == commands.BulkComplete {
+		for i, result := range results {
+			if result.Err != nil || i >= len(op.Tasks) {
+				continue
+			}
+			task := op.Tasks[i]
+			b.notifyTaskCompleted(notify.TaskEvent{
+				ChatID: chatID,
+				Task:   notify.Task{ID: task.ID, Title: task.Content, URL: task.URL},
+			})
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+// newCallbackRouter registers the handlers of all inline buttons. A new button
+// flow adds its route here and answers presses through the CallbackContext.
+func (b *Bot) newCallbackRouter() *commands.CallbackRouter {
+	router := commands.NewCallbackRouter(b.request)
+	router.SetLanguage(b.chatLanguage)
+
+	for _, action := range []string{
+		commands.CallbackConfirm,
+		commands.CallbackEdit,
+		commands.CallbackCancel,
+		commands.CallbackSelectProject,
+		commands.CallbackProjectPage,
+		commands.CallbackSelectSection,
+		commands.CallbackMergeDuplicate,
+		commands.CallbackFinishDiscussion,
+		commands.CallbackKeepDiscussion,
+	} {
+		router.Handle(action, b.handleSessionCallback)
+	}
+
+	router.Handle(commands.CallbackImportConfirm, b.handleImportCallback)
+	router.Handle(commands.CallbackImportCancel, b.handleImportCallback)
+	router.Handle(commands.CallbackBulkConfirm, b.handleBulkCallback)
+	router.Handle(commands.CallbackBulkCancel, b.handleBulkCallback)
+	router.Handle(commands.CallbackDecisionSummary, b.handleDecisionSummaryCallback,
+		commands.SessionOwnerGuard(b.dbManager, i18n.CallbackOwnerOnlyDecision))
+	for _, action := range []string{commands.CallbackSplitToggle, commands.CallbackSplitCreate, commands.CallbackSplitCancel} {
+		router.Handle(action, b.handleSplitCallback,
+			commands.SessionOwnerGuard(b.dbManager, i18n.CallbackOwnerOnlySplit))
+	}
+	router.Handle(commands.CallbackDraftOption, b.handleDraftOptionCallback,
+		commands.SessionOwnerGuard(b.dbManager, i18n.CallbackOwnerOnlyAlternative))
+	router.Handle(commands.CallbackUndoEdit, b.handleUndoEditCallback,
+		commands.SessionOwnerGuard(b.dbManager, i18n.CallbackOwnerOnlyUndo))
+	for _, action := range []string{commands.CallbackQuickPriority, commands.CallbackQuickDue} {
+		router.Handle(action, b.handleQuickEditCallback,
+			commands.SessionOwnerGuard(b.dbManager, i18n.CallbackOwnerOnlyQuickEdit))
+	}
+	router.Handle(commands.CallbackListPage, b.handleListPageCallback)
+	router.Handle(commands.CallbackListDone, b.handleListDoneCallback)
+	router.Handle(commands.CallbackListDelete, b.handleListDeleteCallback)
+	router.Handle(commands.CallbackDeleteConfirm, b.handleDeleteCallback)
+	router.Handle(commands.CallbackDeleteCancel, b.handleDeleteCallback)
+	router.Handle(commands.CallbackTaskViewDone, b.handleTaskViewDoneCallback)
+	router.Handle(commands.CallbackNudgeTakeTask, b.handleNudgeCallback)
+	router.Handle(commands.CallbackNudgeAssign, b.handleNudgeCallback)
+
+	return router
+}
+
+// handleSessionCallback handles the draft and discussion buttons, whose logic
+// lives in commands.CallbackHandler
+func (b *Bot) handleSessionCallback(c *commands.CallbackContext) {
+	callback := c.Query
+	chatID := c.ChatID()
+
+	callbackResp := b.callbackHandler.HandleCallback(callback)
+	if callbackResp.CallbackConfig != nil {
+		c.Answer(callbackResp.CallbackConfig.Text)
+	}
+
+	// Only delete buttons if the user is the session owner
+	if !callbackResp.IsOwner {
+		return
+	}
+	b.clearPendingActionIfMatches(chatID, c.MessageID())
+	sessionID := c.Data.SessionID()
+
+	// The discussion is closed, so queued analysis and edits for it are obsolete;
+	// other discussions of the chat keep theirs
+	if c.Data.Action == commands.CallbackFinishDiscussion {
+		if canceled := b.jobQueue.CancelSession(sessionID); canceled > 0 {
+			log.Printf("Canceled %d AI jobs for closed discussion %d in chat %d", canceled, sessionID, chatID)
+		}
+		if callbackResp.ResponseMessage != nil {
+			b.releaseDiscussionNotice(chatID, sessionID, notify.ReasonCanceled)
+			b.notifySessionClosed(notify.SessionEvent{
+				ChatID:    chatID,
+				ChatTitle: callback.Message.Chat.Title,
+				SessionID: sessionID,
+				Actor:     actorName(callback.From),
+				Reason:    notify.ReasonCanceled,
+			})
+		}
+	}
+
+	if c.Data.Action == commands.CallbackMergeDuplicate && callbackResp.ResponseMessage != nil {
+		b.releaseDiscussionNotice(chatID, sessionID, notify.ReasonMerged)
+		b.notifySessionClosed(notify.SessionEvent{
+			ChatID:    chatID,
+			ChatTitle: callback.Message.Chat.Title,
+			SessionID: sessionID,
+			Actor:     actorName(callback.From),
+			Reason:    notify.ReasonMerged,
+		})
+	}
+
+	if task := callbackResp.CreatedTask; task != nil {
+		b.recordFeature("task_created", chatID)
+		b.notifyTaskCreated(notify.TaskEvent{
+			ChatID:    chatID,
+			ChatTitle: callback.Message.Chat.Title,
+			SessionID: sessionID,
+			Actor:     actorName(callback.From),
+			Task:      notify.Task{ID: task.ID, Title: task.Title, URL: task.URL},
+		})
+		go b.postTranscript(chatID, sessionID, task.ID)
+		go b.forwardAttachments(chatID, sessionID, task.ID)
+		b.releaseDiscussionNotice(chatID, sessionID, notify.ReasonTaskCreated)
+		b.notifySessionClosed(notify.SessionEvent{
+			ChatID:    chatID,
+			ChatTitle: callback.Message.Chat.Title,
+			SessionID: sessionID,
+			Actor:     actorName(callback.From),
+			Reason:    notify.ReasonTaskCreated,
+		})
+	}
+
+	if callbackResp.Edit != nil {
+		if err := b.request(chatID, callbackResp.Edit); err != nil {
+			log.Printf("Error editing %s message: %v", c.Data.Action, err)
+		}
+		return
+	}
+
+	if err := c.ClearButtons(); err != nil {
+		return
+	}
+
+	// Check if we need to send the edit message
+	if callbackResp.CreatedTask != nil && callbackResp.ResponseMessage != nil && b.taskCards != nil {
+		b.sendTaskCard(callbackResp.ResponseMessage, callbackResp.CreatedTask)
+	} else if callbackResp.ResponseMessage != nil {
+		inTopic(callbackResp.ResponseMessage, callback.Message)
+		b.sendResponseWithOptions(callbackResp.ResponseMessage, callbackResp.WaitingForReply, callbackResp.SessionID)
+	} else if c.Data.Action != commands.CallbackEdit {
+		// Send a confirmation message for non-edit callbacks
+		var text string
+		switch c.Data.Action {
+		case commands.CallbackConfirm:
+			text = c.T(i18n.CallbackTaskCreatedNotice)
+		case commands.CallbackCancel:
+			text = c.T(i18n.CallbackCancelledNotice)
+		default:
+			text = c.T(i18n.CallbackKeptNotice)
+		}
+
+		// The owner already got the callback toast, the chat can learn about the new task later
+		if c.Data.Action == commands.CallbackConfirm {
+			b.sendNotice(chatID, text)
+			return
+		}
+
+		if _, err := b.send(tgbotapi.NewMessage(chatID, text)); err != nil {
+			log.Printf("Error sending confirmation message: %v", err)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// EnvMessageCaptureLimit caps how many characters of a discussion message are
+// stored, e.g. "4000"; "0" stores messages whole. Pasted logs and stack traces
+// otherwise crowd the rest of the discussion out of the AI prompt.
+const EnvMessageCaptureLimit = "MESSAGE_CAPTURE_LIMIT"
+
+const defaultCaptureLimit = 4000
+
+// CaptureLimitFromEnv reads MESSAGE_CAPTURE_LIMIT
+func CaptureLimitFromEnv() (int, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvMessageCaptureLimit))
+	if raw == "" {
+		return defaultCaptureLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("invalid %s %q: expected a non-negative number of characters", EnvMessageCaptureLimit, raw)
+	}
+	return limit, nil
+}
+
+// SetCaptureLimit sets how many characters of a message are stored; 0 disables truncation
+func (b *Bot) SetCaptureLimit(limit int) {
+	b.captureLimit = limit
+}
+
+// capturedText is what a discussion message contributes to the transcript:
+// its text or caption, preceded by a placeholder for media the bot cannot read,
+// and cut at limit characters
+func capturedText(message *tgbotapi.Message, limit int) string {
+	text := message.Text
+	if text == "" {
+		text = message.Caption
+	}
+	if placeholder := mediaPlaceholder(message); placeholder != "" {
+		text = strings.TrimSpace(placeholder + " " + text)
+	}
+	return truncateCaptured(text, limit)
+}
+
+// mediaPlaceholder describes the media of a message, e.g. "[video 12s]",
+// so the transcript keeps the shape of the conversation
+func mediaPlaceholder(message *tgbotapi.Message) string {
+	switch {
+	case message.Sticker != nil:
+		if message.Sticker.Emoji != "" {
+			return "[sticker " + message.Sticker.Emoji + "]"
+		}
+		return "[sticker]"
+	case message.Animation != nil:
+		return "[gif]"
+	case message.Video != nil:
+		return "[video " + formatSeconds(message.Video.Duration) + "]"
+	case message.VideoNote != nil:
+		return "[video message " + formatSeconds(message.VideoNote.Duration) + "]"
+	case message.Voice != nil:
+		return "[voice " + formatSeconds(message.Voice.Duration) + "]"
+	case message.Audio != nil:
+		if message.Audio.Title != "" {
+			return fmt.Sprintf("[audio %q %s]", message.Audio.Title, formatSeconds(message.Audio.Duration))
+		}
+		return "[audio " + formatSeconds(message.Audio.Duration) + "]"
+	case len(message.Photo) > 0:
+		return "[photo]"
+	case message.Document != nil:
+		if message.Document.FileName != "" {
+			return "[file " + message.Document.FileName + "]"
+		}
+		return "[file]"
+	case message.Poll != nil:
+		return "[poll: " + message.Poll.Question + "]"
+	case message.Venue != nil:
+		return "[place: " + message.Venue.Title + "]"
+	case message.Location != nil:
+		return "[location]"
+	case message.Contact != nil:
+		return "[contact]"
+	case message.Dice != nil:
+		return "[dice " + message.Dice.Emoji + "]"
+	}
+	return ""
+}
+
+func formatSeconds(seconds int) string {
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+// truncateCaptured cuts text at limit characters and says how much was dropped
+func truncateCaptured(text string, limit int) string {
+	if limit <= 0 {
+		return text
+	}
+	total := utf8.RuneCountInString(text)
+	if total <= limit {
+		return text
+	}
+
+	cut, runes := 0, 0
+	for i := range text {
+		if runes == limit {
+			cut = i
+			break
+		}
+		runes++
+	}
+	kept := strings.TrimRightFunc(text[:cut], unicode.IsSpace)
+	return fmt.Sprintf("%s… [truncated, %d of %d characters kept]", kept, utf8.RuneCountInString(kept), total)
+}
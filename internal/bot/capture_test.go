@@ -0,0 +1,65 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestCaptureLimitFromEnv(t *testing.T) {
+	t.Setenv(EnvMessageCaptureLimit, "")
+	if limit, err := CaptureLimitFromEnv(); err != nil || limit != defaultCaptureLimit {
+		t.Fatalf("expected default limit, got %d, %v", limit, err)
+	}
+
+	t.Setenv(EnvMessageCaptureLimit, "0")
+	if limit, err := CaptureLimitFromEnv(); err != nil || limit != 0 {
+		t.Fatalf("expected disabled limit, got %d, %v", limit, err)
+	}
+
+	for _, raw := range []string{"-1", "4k"} {
+		t.Setenv(EnvMessageCaptureLimit, raw)
+		if _, err := CaptureLimitFromEnv(); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
+
+func TestCapturedText_TruncatesLongMessages(t *testing.T) {
+	message := &tgbotapi.Message{Text: "паника " + strings.Repeat("стек ", 100)}
+
+	got := capturedText(message, 10)
+	want := "паника сте… [truncated, 10 of 507 characters kept]"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+
+	if got := capturedText(message, 0); got != message.Text {
+		t.Fatalf("expected whole text without a limit, got %q", got)
+	}
+}
+
+func TestCapturedText_MediaPlaceholders(t *testing.T) {
+	tests := []struct {
+		name    string
+		message *tgbotapi.Message
+		want    string
+	}{
+		{"sticker", &tgbotapi.Message{Sticker: &tgbotapi.Sticker{Emoji: "👍"}}, "[sticker 👍]"},
+		{"video", &tgbotapi.Message{Video: &tgbotapi.Video{Duration: 12}}, "[video 12s]"},
+		{"voice", &tgbotapi.Message{Voice: &tgbotapi.Voice{Duration: 75}}, "[voice 1m15s]"},
+		{"photo with caption", &tgbotapi.Message{Photo: []tgbotapi.PhotoSize{{}}, Caption: "скрин ошибки"}, "[photo] скрин ошибки"},
+		{"document", &tgbotapi.Message{Document: &tgbotapi.Document{FileName: "trace.log"}}, "[file trace.log]"},
+		{"plain text", &tgbotapi.Message{Text: "просто текст"}, "просто текст"},
+		{"nothing to capture", &tgbotapi.Message{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := capturedText(tt.message, defaultCaptureLimit); got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+const (
+	// EnvChannelHashtags lists hashtags that turn a channel post into a draft
+	// task, e.g. "#задача,#task". Channel posts are ignored when it is empty.
+	EnvChannelHashtags = "CHANNEL_TASK_HASHTAGS"
+	// EnvChannelOwnerID is the Telegram user who confirms drafts from channel
+	// posts. Channel posts have no author, so the first admin is used by default.
+	EnvChannelOwnerID = "CHANNEL_TASK_OWNER_ID"
+)
+
+// ChannelConfig describes how the bot reacts to posts in channels where it is an admin
+type ChannelConfig struct {
+	Hashtags []string
+	OwnerID  int64
+}
+
+// ChannelConfigFromEnv reads channel settings from CHANNEL_TASK_HASHTAGS and CHANNEL_TASK_OWNER_ID
+func ChannelConfigFromEnv() (ChannelConfig, error) {
+	var config ChannelConfig
+	for _, tag := range strings.Split(os.Getenv(EnvChannelHashtags), ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			continue
+		}
+		if !strings.HasPrefix(tag, "#") {
+			tag = "#" + tag
+		}
+		config.Hashtags = append(config.Hashtags, tag)
+	}
+
+	if raw := strings.TrimSpace(os.Getenv(EnvChannelOwnerID)); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return ChannelConfig{}, fmt.Errorf("invalid %s %q: %w", EnvChannelOwnerID, raw, err)
+		}
+		config.OwnerID = id
+	}
+	return config, nil
+}
+
+// Enabled reports whether channel posts can open draft tasks
+func (c ChannelConfig) Enabled() bool {
+	return len(c.Hashtags) > 0
+}
+
+// matchesPost reports whether the post text contains one of the configured hashtags
+func (c ChannelConfig) matchesPost(text string) bool {
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return r == ' ' || r == '\n' || r == '\t' || r == ',' || r == '.' || r == ';'
+	}) {
+		for _, tag := range c.Hashtags {
+			if word == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetChannelConfig enables draft tasks from channel posts
+func (b *Bot) SetChannelConfig(config ChannelConfig) {
+	b.channelConfig = config
+}
+
+// channelOwner returns the user who confirms drafts opened from channel posts
+func (b *Bot) channelOwner() int64 {
+	if b.channelConfig.OwnerID != 0 {
+		return b.channelConfig.OwnerID
+	}
+	if ids := b.admins.IDs(); len(ids) > 0 {
+		return ids[0]
+	}
+	return 0
+}
+
+// handleChannelPost opens a discussion in the channel's linked group with the
+// post as its only message and queues its analysis there, so the draft is
+// confirmed in the group
+func (b *Bot) handleChannelPost(post *tgbotapi.Message) {
+	text := post.Text
+	if text == "" {
+		text = post.Caption
+	}
+	if !b.channelConfig.Enabled() || text == "" || !b.channelConfig.matchesPost(text) {
+		return
+	}
+
+	ownerID := b.channelOwner()
+	if ownerID == 0 {
+		log.Printf("Ignoring task post %d in channel %d: set %s or %s", post.MessageID, post.Chat.ID, EnvChannelOwnerID, admin.EnvUserIDs)
+		return
+	}
+
+	channel, err := b.api.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: tgbotapi.ChatConfig{ChatID: post.Chat.ID}})
+	if err != nil {
+		log.Printf("Error getting channel %d: %v", post.Chat.ID, err)
+		return
+	}
+	if channel.LinkedChatID == 0 {
+		log.Printf("Ignoring task post %d: channel %d has no linked discussion group", post.MessageID, post.Chat.ID)
+		return
+	}
+	groupID := channel.LinkedChatID
+
+	ctx := context.Background()
+	if _, err := b.dbManager.StartSession(ctx, groupID, 0, "", ownerID); err != nil {
+		if errors.Is(err, db.ErrSessionAlreadyExists) {
+			b.sendNotice(groupID, i18n.T(b.chatLanguage(groupID), i18n.ChannelBusy))
+			return
+		}
+		log.Printf("Error starting session for channel post %d: %v", post.MessageID, err)
+		return
+	}
+
+	author := post.AuthorSignature
+	if author == "" {
+		author = post.Chat.Title
+	}
+	b.pinDiscussionNotice(groupID, nil, ownerID, author)
+	links := tasklinks.ExtractFromTelegramMessage(post)
+	if err := b.dbManager.SaveMessage(ctx, groupID, 0, post.MessageID, ownerID, author, truncateCaptured(text, b.captureLimit), links); err != nil {
+		log.Printf("Error saving channel post %d: %v", post.MessageID, err)
+		return
+	}
+
+	command, ok := b.commandRegistry.Get("create_task")
+	if !ok {
+		return
+	}
+	queued, ok := command.(commands.QueuedCommand)
+	if !ok {
+		return
+	}
+
+	// create_task runs on behalf of the owner in the linked group
+	b.enqueueCommand(queued, &tgbotapi.Message{
+		MessageID: post.MessageID,
+		From:      &tgbotapi.User{ID: ownerID},
+		Chat:      &tgbotapi.Chat{ID: groupID, Type: "supergroup"},
+		Text:      "/create_task",
+	})
+}
+
+// isCapturedChannelPost reports whether a message is the automatic forward of
+// a channel post that was already saved by handleChannelPost
+func (b *Bot) isCapturedChannelPost(message *tgbotapi.Message) bool {
+	if !message.IsAutomaticForward || !b.channelConfig.Enabled() {
+		return false
+	}
+	text := message.Text
+	if text == "" {
+		text = message.Caption
+	}
+	return b.channelConfig.matchesPost(text)
+}
@@ -0,0 +1,53 @@
+package bot
+
+import "testing"
+
+func TestChannelConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvChannelHashtags, " #Задача, task ,")
+	t.Setenv(EnvChannelOwnerID, "42")
+
+	config, err := ChannelConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Hashtags) != 2 || config.Hashtags[0] != "#задача" || config.Hashtags[1] != "#task" {
+		t.Fatalf("unexpected hashtags: %v", config.Hashtags)
+	}
+	if config.OwnerID != 42 {
+		t.Fatalf("expected owner 42, got %d", config.OwnerID)
+	}
+}
+
+func TestChannelConfigFromEnv_DisabledByDefault(t *testing.T) {
+	t.Setenv(EnvChannelHashtags, "")
+	t.Setenv(EnvChannelOwnerID, "")
+
+	config, err := ChannelConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Enabled() {
+		t.Fatal("expected channel posts to be ignored without hashtags")
+	}
+}
+
+func TestChannelConfigFromEnv_InvalidOwner(t *testing.T) {
+	t.Setenv(EnvChannelOwnerID, "admin")
+	if _, err := ChannelConfigFromEnv(); err == nil {
+		t.Fatal("expected error for non-numeric owner")
+	}
+}
+
+func TestChannelConfig_MatchesPost(t *testing.T) {
+	config := ChannelConfig{Hashtags: []string{"#задача"}}
+
+	if !config.matchesPost("Починить выгрузку отчётов.\n#Задача") {
+		t.Fatal("expected hashtag on its own line to match")
+	}
+	if config.matchesPost("#задачами займёмся завтра") {
+		t.Fatal("expected longer hashtag not to match")
+	}
+	if config.matchesPost("просто новость") {
+		t.Fatal("expected post without hashtag not to match")
+	}
+}
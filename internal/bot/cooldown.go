@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/cooldown"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// SetCooldowns replaces the per-command cooldowns
+func (b *Bot) SetCooldowns(limiter *cooldown.Limiter) {
+	b.cooldowns = limiter
+}
+
+// cooldownMiddleware enforces the command's cooldown in the chat and tells
+// the user how long to wait when it is still running
+func (b *Bot) cooldownMiddleware(next commands.Handler) commands.Handler {
+	return func(ctx context.Context, cmd commands.Command, message *tgbotapi.Message) *commands.Response {
+		if b.cooldowns == nil {
+			return next(ctx, cmd, message)
+		}
+		commandName := cmd.Name()
+		wait, ok := b.cooldowns.Allow(commandName, message.Chat.ID)
+		if ok {
+			return next(ctx, cmd, message)
+		}
+
+		log.Printf("[COOLDOWN] /%s in chat %d, %v left", commandName, message.Chat.ID, wait)
+		lang := b.replyLanguage(message)
+		text := fmt.Sprintf(i18n.T(lang, i18n.CooldownWait),
+			commandName, cooldown.FormatWait(b.cooldowns.Cooldown(commandName), lang), cooldown.FormatWait(wait, lang),
+		)
+		return commands.NewResponse(tgbotapi.NewMessage(message.Chat.ID, text))
+	}
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/cooldown"
+)
+
+type okCommand struct{ name string }
+
+func (c okCommand) Name() string        { return c.name }
+func (c okCommand) Description() string { return "" }
+func (c okCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(message.Chat.ID, "ok")
+	return &msg
+}
+
+func TestCooldownMiddleware_RejectsRepeatedCommand(t *testing.T) {
+	b := &Bot{
+		dbManager: new(commands.MockDBManager),
+		cooldowns: cooldown.NewLimiter(map[string]time.Duration{"summary": time.Minute}),
+	}
+	registry := commands.NewRegistry()
+	registry.Use(b.cooldownMiddleware)
+	message := commands.CreateCommandMessage(1, "/summary")
+
+	first := registry.Execute(context.Background(), okCommand{name: "summary"}, message)
+	second := registry.Execute(context.Background(), okCommand{name: "summary"}, message)
+	other := registry.Execute(context.Background(), okCommand{name: "list"}, message)
+
+	if got := first.Message().Text; got != "ok" {
+		t.Fatalf("first run replied %q, want ok", got)
+	}
+	if got := second.Message().Text; got == "ok" || !strings.Contains(got, "/summary") {
+		t.Errorf("repeated run replied %q, want the cooldown notice", got)
+	}
+	if got := other.Message().Text; got != "ok" {
+		t.Errorf("command without a cooldown replied %q, want ok", got)
+	}
+}
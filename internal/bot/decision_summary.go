@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+)
+
+// handleDecisionSummaryCallback queues an AI summary of why the discussion
+// ended without a task; the router admits only the session owner
+func (b *Bot) handleDecisionSummaryCallback(c *commands.CallbackContext) {
+	chatID := c.ChatID()
+	sessionID := c.Data.SessionID()
+
+	c.Answer(c.T(i18n.DecisionPreparing))
+	c.ClearButtons()
+
+	if err := b.submitDecisionSummary(chatID, c.Query.Message, sessionID, 0); err != nil {
+		log.Printf("Error submitting decision summary job for session %d: %v", sessionID, err)
+		b.sendMessage(chatID, c.Query.Message, c.T(i18n.JobQueueFailed))
+	}
+}
+
+// submitDecisionSummary queues the summary job posted in the forum topic of
+// trigger; storedID is the stored job it was rebuilt from, 0 for a new one.
+func (b *Bot) submitDecisionSummary(chatID int64, trigger *tgbotapi.Message, sessionID, storedID int) error {
+	_, err := b.submitJob(jobs.Job{
+		Kind:      "decision_summary",
+		ChatID:    chatID,
+		SessionID: sessionID,
+		Provider:  b.aiProvider,
+		Priority:  jobs.PriorityNormal,
+		Run: func(ctx context.Context) error {
+			b.postDecisionSummary(ctx, chatID, trigger, sessionID)
+			return ctx.Err()
+		},
+	}, storedJob{Message: trigger}, storedID)
+	return err
+}
+
+func (b *Bot) postDecisionSummary(ctx context.Context, chatID int64, trigger *tgbotapi.Message, sessionID int) {
+	ctx = ai.WithUsageScope(ctx, chatID, sessionID)
+	lang := b.chatLanguage(chatID)
+	messages, err := b.dbManager.GetSessionMessages(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting messages of session %d for decision summary: %v", sessionID, err)
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.ErrorMessagesLoad))
+		return
+	}
+	texts := buildMessageTexts(messages)
+	if len(texts) == 0 {
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.DecisionNoMessages))
+		return
+	}
+
+	summary, err := b.aiClient.RunPrompt(ctx, ai.PromptDecisionSummary, strings.Join(texts, "\n"))
+	if err != nil {
+		log.Printf("Error writing decision summary for session %d: %v", sessionID, err)
+		if errors.Is(err, ai.ErrUnavailable) {
+			b.sendMessage(chatID, trigger, i18n.T(lang, i18n.AIUnavailable))
+			return
+		}
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.DecisionFailed))
+		return
+	}
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.DecisionFailed))
+		return
+	}
+
+	if err := b.dbManager.SaveDecisionSummary(ctx, sessionID, chatID, summary); err != nil {
+		log.Printf("Error saving decision summary for session %d: %v", sessionID, err)
+	}
+	b.sendMessage(chatID, trigger, fmt.Sprintf(i18n.T(lang, i18n.DecisionText), summary))
+}
@@ -0,0 +1,71 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func (b *Bot) deleteCommand() (*commands.DeleteCommand, bool) {
+	command, ok := b.commandRegistry.Get("delete")
+	if !ok {
+		return nil, false
+	}
+	deleteCmd, ok := command.(*commands.DeleteCommand)
+	return deleteCmd, ok
+}
+
+// handleDeleteCallback deletes or keeps the task the user asked to delete;
+// only that user can answer. The message is edited to show the outcome, or
+// the /list page without the task.
+func (b *Bot) handleDeleteCallback(c *commands.CallbackContext) {
+	deleteCmd, ok := b.deleteCommand()
+	ownerID, taskID, valid := commands.ParseDeleteData(c.Data)
+	if !ok || !valid {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+	if c.Query.From.ID != ownerID {
+		c.Answer(c.T(i18n.DeleteOwnerOnly))
+		return
+	}
+	chatID := c.ChatID()
+	pending, ok := b.deletes.Take(chatID, ownerID, taskID)
+	if !ok {
+		c.Answer(c.T(i18n.DeleteGone))
+		c.ClearButtons()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listPageTimeout)
+	defer cancel()
+
+	note := fmt.Sprintf(c.T(i18n.DeleteKept), pending.Task.Title)
+	if c.Data.Action == commands.CallbackDeleteConfirm {
+		if err := deleteCmd.Delete(ctx, chatID, taskID); err != nil {
+			log.Printf("Error deleting task %s in chat %d: %v", taskID, chatID, err)
+			c.Answer(c.T(i18n.DeleteFailed))
+			// The request stays pending, so the user can press the button again
+			b.deletes.Put(chatID, pending)
+			return
+		}
+		log.Printf("Task %s deleted in chat %d by user %d", taskID, chatID, ownerID)
+		note = fmt.Sprintf(c.T(i18n.DeleteDone), pending.Task.Title)
+	}
+	c.Answer("")
+
+	if pending.List != nil {
+		if list, ok := b.listCommand(); ok {
+			b.showListPage(ctx, c, list, *pending.List, note)
+			return
+		}
+	}
+	edit := tgbotapi.NewEditMessageText(chatID, c.MessageID(), note)
+	if err := b.request(chatID, edit); err != nil {
+		log.Printf("Error showing deletion of task %s in chat %d: %v", taskID, chatID, err)
+	}
+}
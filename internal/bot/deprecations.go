@@ -0,0 +1,37 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// apiDeprecationText tells admins that an API the bot depends on is going away
+func apiDeprecationText(d httpclient.Deprecation) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "⚠️ API %s сообщает, что используемый ботом метод устаревает", d.API)
+	if d.Path != "" {
+		fmt.Fprintf(&b, " (%s %s)", d.Method, d.Path)
+	}
+	b.WriteString(".")
+	if sunset := d.SunsetTime(); !sunset.IsZero() {
+		fmt.Fprintf(&b, "\nОтключение запланировано на %s.", sunset.Format("02.01.2006"))
+	}
+	if d.Link != "" {
+		fmt.Fprintf(&b, "\nПодробности: %s", d.Link)
+	}
+	b.WriteString("\nСледующее напоминание по этому API — не раньше чем через сутки.")
+	return b.String()
+}
+
+// ReportAPIDeprecation passes deprecation headers of an API response to the
+// bot admins, at most once a day per API
+func (b *Bot) ReportAPIDeprecation(d httpclient.Deprecation) {
+	b.apiDeprecations.Report(d)
+}
+
+func (b *Bot) notifyAPIDeprecation(d httpclient.Deprecation) {
+	// Reports come from inside API calls; the admin messages are themselves API calls
+	go b.NotifyAdmins(apiDeprecationText(d))
+}
@@ -0,0 +1,27 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+func TestAPIDeprecationText(t *testing.T) {
+	text := apiDeprecationText(httpclient.Deprecation{
+		API:         "todoist",
+		Method:      "GET",
+		Path:        "/rest/v2/tasks",
+		Deprecation: "@1735689600",
+		Sunset:      "Wed, 01 Jul 2026 00:00:00 GMT",
+		Link:        "https://developer.todoist.com/api/v1/#migrating-from-v9",
+	})
+	assert.Contains(t, text, "API todoist")
+	assert.Contains(t, text, "(GET /rest/v2/tasks)")
+	assert.Contains(t, text, "01.07.2026")
+	assert.Contains(t, text, "https://developer.todoist.com/api/v1/#migrating-from-v9")
+
+	text = apiDeprecationText(httpclient.Deprecation{API: "telegram", Deprecation: "true"})
+	assert.NotContains(t, text, "Отключение")
+	assert.NotContains(t, text, "Подробности")
+}
@@ -0,0 +1,96 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+const discussionNoticeTimeout = 10 * time.Second
+
+// discussionNoticeText tells latecomers that their messages are being collected
+func discussionNoticeText(lang i18n.Lang, starter string) string {
+	if starter == "" {
+		starter = i18n.T(lang, i18n.NoticeStarterUnknown)
+	}
+	return fmt.Sprintf(i18n.T(lang, i18n.NoticeDiscussion), starter)
+}
+
+// closedNoticeText replaces the notice once the discussion is over
+func closedNoticeText(lang i18n.Lang, reason string) string {
+	switch reason {
+	case notify.ReasonTaskCreated:
+		return i18n.T(lang, i18n.NoticeClosedCreated)
+	case notify.ReasonMerged:
+		return i18n.T(lang, i18n.NoticeClosedMerged)
+	}
+	return i18n.T(lang, i18n.NoticeClosedCanceled)
+}
+
+// pinDiscussionNotice posts and pins the status message of the discussion
+// ownerID has just started with trigger, nil for discussions the bot opened
+// itself. Without the right to pin, the message stays unpinned.
+func (b *Bot) pinDiscussionNotice(chatID int64, trigger *tgbotapi.Message, ownerID int64, starter string) {
+	ctx, cancel := context.WithTimeout(context.Background(), discussionNoticeTimeout)
+	defer cancel()
+
+	// A failed start leaves either no session or someone else's, which already has its notice
+	session, err := b.dbManager.GetNamedSession(ctx, chatID, topics.ThreadID(trigger), commands.SessionName(trigger))
+	if err != nil || session.OwnerID != ownerID {
+		return
+	}
+	if noticeID, err := b.dbManager.GetSessionNotice(ctx, session.ID); err != nil || noticeID != 0 {
+		if err != nil {
+			log.Printf("Error getting notice of session %d: %v", session.ID, err)
+		}
+		return
+	}
+
+	notice := tgbotapi.NewMessage(chatID, discussionNoticeText(b.chatLanguage(chatID), starter))
+	inTopic(&notice, trigger)
+	sent, err := b.send(notice)
+	if err != nil {
+		log.Printf("Error sending discussion notice in chat %d: %v", chatID, err)
+		return
+	}
+	if err := b.dbManager.SetSessionNotice(ctx, session.ID, sent.MessageID); err != nil {
+		log.Printf("Error saving notice of session %d: %v", session.ID, err)
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+	if err := b.request(chatID, pin); err != nil {
+		log.Printf("Error pinning discussion notice in chat %d: %v", chatID, err)
+	}
+}
+
+// releaseDiscussionNotice marks the notice of a closed session as finished and unpins it
+func (b *Bot) releaseDiscussionNotice(chatID int64, sessionID int, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), discussionNoticeTimeout)
+	defer cancel()
+
+	noticeID, err := b.dbManager.GetSessionNotice(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting notice of session %d: %v", sessionID, err)
+		return
+	}
+	if noticeID == 0 {
+		return
+	}
+
+	if err := b.request(chatID, tgbotapi.NewEditMessageText(chatID, noticeID, closedNoticeText(b.chatLanguage(chatID), reason))); err != nil {
+		log.Printf("Error updating discussion notice %d in chat %d: %v", noticeID, chatID, err)
+	}
+	if err := b.request(chatID, tgbotapi.UnpinChatMessageConfig{ChatID: chatID, MessageID: noticeID}); err != nil {
+		log.Printf("Error unpinning discussion notice %d in chat %d: %v", noticeID, chatID, err)
+	}
+	if err := b.dbManager.SetSessionNotice(ctx, sessionID, 0); err != nil {
+		log.Printf("Error clearing notice of session %d: %v", sessionID, err)
+	}
+}
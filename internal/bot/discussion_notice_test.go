@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+func TestDiscussionNoticeText_NamesStarterAndCommands(t *testing.T) {
+	text := discussionNoticeText(i18n.Russian, "@alice")
+	for _, want := range []string{"@alice", "/create_task", "/cancel"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected notice to contain %q, got %q", want, text)
+		}
+	}
+	if !strings.Contains(discussionNoticeText(i18n.Russian, ""), "участник чата") {
+		t.Error("expected a fallback for an unknown starter")
+	}
+}
+
+func TestClosedNoticeText_DependsOnReason(t *testing.T) {
+	if !strings.Contains(closedNoticeText(i18n.Russian, notify.ReasonTaskCreated), "задача создана") {
+		t.Error("expected created task to be mentioned")
+	}
+	if !strings.Contains(closedNoticeText(i18n.Russian, notify.ReasonCanceled), "без задачи") {
+		t.Error("expected canceled discussion to be mentioned")
+	}
+}
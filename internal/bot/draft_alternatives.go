@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+const draftOptionTimeout = 10 * time.Second
+
+// SetDraftAlternatives makes /create_task offer up to count drafts to choose from
+func (b *Bot) SetDraftAlternatives(count int) {
+	command, ok := b.commandRegistry.Get("create_task")
+	if !ok {
+		return
+	}
+	if createTask, ok := command.(*commands.CreateTaskCommand); ok {
+		createTask.SetDraftAlternatives(count)
+	}
+}
+
+// handleDraftOptionCallback makes the chosen alternative the session draft and
+// sends its preview; the router admits only the session owner
+func (b *Bot) handleDraftOptionCallback(c *commands.CallbackContext) {
+	option, err := strconv.Atoi(c.Data.Arg(1))
+	if err != nil {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), draftOptionTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+	sessionID := c.Data.SessionID()
+	preview, err := commands.SelectDraftAlternative(ctx, b.dbManager, chatID, sessionID, option)
+	if errors.Is(err, commands.ErrDraftAlreadyCreated) {
+		c.Answer(c.T(i18n.CallbackAlreadyCreated))
+		c.ClearButtons()
+		return
+	}
+	if err != nil {
+		log.Printf("Error selecting draft alternative %d of session %d: %v", option, sessionID, err)
+		c.Answer(c.T(i18n.AlternativeFailed))
+		return
+	}
+
+	c.Answer("")
+	b.clearPendingActionIfMatches(chatID, c.MessageID())
+	c.ClearButtons()
+	inTopic(preview, c.Query.Message)
+	b.sendResponse(preview)
+}
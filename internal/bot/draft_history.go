@@ -0,0 +1,47 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// handleUndoEditCallback rolls the draft back to the version before the last
+// edit and shows it; the router admits only the session owner
+func (b *Bot) handleUndoEditCallback(c *commands.CallbackContext) {
+	ctx, cancel := context.WithTimeout(context.Background(), draftOptionTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+	sessionID := c.Data.SessionID()
+
+	if created, err := b.dbManager.GetCreatedTask(ctx, sessionID); err == nil && created != nil {
+		c.Answer(c.T(i18n.CallbackAlreadyCreated))
+		c.ClearButtons()
+		return
+	}
+
+	draft, canUndoMore, err := commands.UndoDraftEdit(ctx, b.dbManager, sessionID)
+	if errors.Is(err, commands.ErrNothingToUndo) {
+		c.Answer(c.T(i18n.UndoNothing))
+		return
+	}
+	if err != nil {
+		log.Printf("Error undoing draft edit of session %d: %v", sessionID, err)
+		c.Answer(c.T(i18n.UndoFailed))
+		return
+	}
+
+	c.Answer(c.T(i18n.UndoDone))
+	b.clearPendingActionIfMatches(chatID, c.MessageID())
+	c.ClearButtons()
+
+	lang := b.chatLanguage(chatID)
+	keyboard := commands.CreateInlineKeyboard(sessionID, lang)
+	if canUndoMore {
+		keyboard = commands.EditedDraftKeyboard(sessionID, lang)
+	}
+	b.sendDraftPreview(chatID, c.Query.Message, lang, i18n.T(lang, i18n.PreviewUndone), commands.DraftInputTask(draft), draft.Assignee, keyboard)
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/user/telegram-bot/internal/db"
+	"gopkg.in/yaml.v3"
+)
+
+// HostingConfigPath lists the bot identities served by one process.
+// Without it the process runs a single bot from TELEGRAM_BOT_TOKEN.
+const HostingConfigPath = "configs/bots.yaml"
+
+var botIDRe = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+
+// Identity is the per-bot section of the hosting config
+type Identity struct {
+	// ID scopes chat settings and sessions in the shared database
+	ID string `yaml:"id"`
+	// TelegramTokenEnvVar names the variable holding the bot token
+	TelegramTokenEnvVar string `yaml:"telegram_token_env_var"`
+	// TodoistTokenEnvVar overrides the Todoist token, e.g. one workspace per department
+	TodoistTokenEnvVar string `yaml:"todoist_token_env_var,omitempty"`
+	// AdminUserIDs overrides ADMIN_USER_IDS for this bot
+	AdminUserIDs string `yaml:"admin_user_ids,omitempty"`
+}
+
+// HostingConfig describes every bot run by the process
+type HostingConfig struct {
+	Bots []Identity `yaml:"bots"`
+}
+
+// LoadHostingConfig reads the hosting config, falling back to a single
+// default bot when the file does not exist.
+func LoadHostingConfig(path string) (*HostingConfig, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &HostingConfig{Bots: []Identity{{
+			ID:                  db.DefaultBotID,
+			TelegramTokenEnvVar: "TELEGRAM_BOT_TOKEN",
+		}}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading hosting config: %w", err)
+	}
+
+	var config HostingConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("error parsing hosting config: %w", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (c *HostingConfig) validate() error {
+	if len(c.Bots) == 0 {
+		return fmt.Errorf("hosting config must list at least one bot")
+	}
+
+	seenIDs := make(map[string]bool)
+	seenTokens := make(map[string]bool)
+	for i, identity := range c.Bots {
+		if !botIDRe.MatchString(identity.ID) {
+			return fmt.Errorf("bots[%d]: id %q must be 1-32 lowercase letters, digits, '-' or '_'", i, identity.ID)
+		}
+		if seenIDs[identity.ID] {
+			return fmt.Errorf("bots[%d]: duplicate id %q", i, identity.ID)
+		}
+		seenIDs[identity.ID] = true
+
+		if identity.TelegramTokenEnvVar == "" {
+			return fmt.Errorf("bots[%d] (%s): telegram_token_env_var is required", i, identity.ID)
+		}
+		if seenTokens[identity.TelegramTokenEnvVar] {
+			// Two pollers on one token would steal each other's updates
+			return fmt.Errorf("bots[%d] (%s): token variable %s is used by another bot", i, identity.ID, identity.TelegramTokenEnvVar)
+		}
+		seenTokens[identity.TelegramTokenEnvVar] = true
+	}
+	return nil
+}
+
+// TelegramToken returns the bot token from the environment
+func (i Identity) TelegramToken() (string, error) {
+	token := os.Getenv(i.TelegramTokenEnvVar)
+	if token == "" {
+		return "", fmt.Errorf("%s is required for bot %q", i.TelegramTokenEnvVar, i.ID)
+	}
+	return token, nil
+}
@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeHostingConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bots.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadHostingConfig_DefaultsToSingleBot(t *testing.T) {
+	config, err := LoadHostingConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Bots) != 1 || config.Bots[0].ID != "default" || config.Bots[0].TelegramTokenEnvVar != "TELEGRAM_BOT_TOKEN" {
+		t.Fatalf("unexpected default config: %+v", config.Bots)
+	}
+}
+
+func TestLoadHostingConfig_MultipleBots(t *testing.T) {
+	path := writeHostingConfig(t, `
+bots:
+  - id: default
+    telegram_token_env_var: TELEGRAM_BOT_TOKEN
+  - id: marketing
+    telegram_token_env_var: TELEGRAM_BOT_TOKEN_MARKETING
+    todoist_token_env_var: TODOIST_API_TOKEN_MARKETING
+`)
+
+	config, err := LoadHostingConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(config.Bots) != 2 || config.Bots[1].TodoistTokenEnvVar != "TODOIST_API_TOKEN_MARKETING" {
+		t.Fatalf("unexpected bots: %+v", config.Bots)
+	}
+
+	t.Setenv("TELEGRAM_BOT_TOKEN_MARKETING", "")
+	if _, err := config.Bots[1].TelegramToken(); err == nil {
+		t.Fatal("expected error for missing token")
+	}
+}
+
+func TestLoadHostingConfig_RejectsInvalidBots(t *testing.T) {
+	cases := map[string]string{
+		"duplicate id": `
+bots:
+  - {id: sales, telegram_token_env_var: A}
+  - {id: sales, telegram_token_env_var: B}
+`,
+		"shared token": `
+bots:
+  - {id: sales, telegram_token_env_var: A}
+  - {id: support, telegram_token_env_var: A}
+`,
+		"bad id": `
+bots:
+  - {id: "Sales Team", telegram_token_env_var: A}
+`,
+		"no bots": `bots: []`,
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadHostingConfig(writeHostingConfig(t, content))
+			if err == nil {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
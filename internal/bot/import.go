@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/taskimport"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+const (
+	// maxImportFileSize keeps CSV uploads far below Telegram's download limit
+	maxImportFileSize = 1 << 20
+	importTimeout     = 5 * time.Minute
+)
+
+// pendingImport is a parsed CSV waiting for confirmation
+type pendingImport struct {
+	ownerID   int64
+	projectID string
+	rows      []taskimport.Row
+}
+
+// handleImportReply parses the CSV sent in reply to /import and shows a preview
+func (b *Bot) handleImportReply(message *tgbotapi.Message, uploadContext string) {
+	b.importMutex.Lock()
+	delete(b.importUploadSessions, int64(message.ReplyToMessage.MessageID))
+	b.importMutex.Unlock()
+
+	lang := b.replyLanguage(message)
+	if message.Document == nil {
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.ImportSendDocument))
+		return
+	}
+	if message.Document.FileSize > maxImportFileSize {
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.ImportTooLarge))
+		return
+	}
+
+	parts := strings.SplitN(uploadContext, ":", 2)
+	if len(parts) != 2 {
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.ImportInternalError))
+		return
+	}
+	projectID := parts[1]
+
+	raw, err := b.downloadFile(message.Document.FileID, maxImportFileSize)
+	if err != nil {
+		log.Printf("Error downloading import file: %v", err)
+		b.sendMessage(message.Chat.ID, message, i18n.T(lang, i18n.ImportDownloadFailed))
+		return
+	}
+
+	result, err := taskimport.Parse(raw)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, message, fmt.Sprintf(i18n.T(lang, i18n.ImportReadFailed), err))
+		return
+	}
+	if len(result.Rows) == 0 {
+		b.sendMessage(message.Chat.ID, message, commands.FormatImportPreview(result, lang)+"\n\n"+i18n.T(lang, i18n.ImportNothing))
+		return
+	}
+
+	ownerID := int64(message.From.ID)
+	b.importMutex.Lock()
+	b.pendingImports[message.Chat.ID] = &pendingImport{ownerID: ownerID, projectID: projectID, rows: result.Rows}
+	b.importMutex.Unlock()
+
+	data := commands.CallbackDataSeparator + strconv.FormatInt(ownerID, 10)
+	msg := tgbotapi.NewMessage(message.Chat.ID, commands.FormatImportPreview(result, lang))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(i18n.T(lang, i18n.ButtonImportCreate), len(result.Rows)), commands.CallbackImportConfirm+data),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancel), commands.CallbackImportCancel+data),
+	))
+	b.sendResponse(&msg)
+}
+
+// handleImportCallback creates or drops the pending import of the chat
+func (b *Bot) handleImportCallback(c *commands.CallbackContext) {
+	callback := c.Query
+	chatID := c.ChatID()
+
+	b.importMutex.Lock()
+	pending, ok := b.pendingImports[chatID]
+	if ok && pending.ownerID == callback.From.ID {
+		delete(b.pendingImports, chatID)
+	}
+	b.importMutex.Unlock()
+
+	switch {
+	case !ok:
+		c.Answer(c.T(i18n.ImportGone))
+		return
+	case pending.ownerID != callback.From.ID:
+		c.Answer(c.T(i18n.ImportOwnerOnly))
+		return
+	}
+	c.Answer("")
+
+	b.clearPendingActionIfMatches(chatID, c.MessageID())
+	c.ClearButtons()
+
+	if c.Data.Action == commands.CallbackImportCancel {
+		b.sendMessage(chatID, c.Query.Message, c.T(i18n.ImportCancelled))
+		return
+	}
+
+	go b.runImport(chatID, c.Query.Message, c.Lang(), pending)
+}
+
+func (b *Bot) runImport(chatID int64, trigger *tgbotapi.Message, lang i18n.Lang, pending *pendingImport) {
+	creator, ok := b.todoistClient.(todoist.BatchCreator)
+	if !ok {
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.ImportUnsupported))
+		return
+	}
+
+	progressID := b.sendProgressMessage(chatID, trigger, fmt.Sprintf(i18n.T(lang, i18n.ImportProgress), len(pending.rows)))
+	defer b.deleteMessage(chatID, progressID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), importTimeout)
+	defer cancel()
+
+	tasks := make([]*todoist.TaskRequest, len(pending.rows))
+	for i := range pending.rows {
+		task := pending.rows[i].Task
+		task.ProjectID = pending.projectID
+		tasks[i] = &task
+	}
+
+	results, err := creator.CreateTasksBatch(ctx, tasks)
+	if err != nil {
+		log.Printf("Error importing tasks into project %s: %v", pending.projectID, err)
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.ImportFailed))
+		return
+	}
+	b.sendMessage(chatID, trigger, commands.FormatImportReport(pending.rows, results, lang))
+}
+
+// downloadFile fetches a file sent to the bot, reading at most limit+1 bytes
+// so callers can tell an oversized file
+func (b *Bot) downloadFile(fileID string, limit int64) ([]byte, error) {
+	fileURL, err := b.fileURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file URL: %w", err)
+	}
+
+	httpClient := &http.Client{Timeout: 20 * time.Second}
+	resp, err := httpClient.Get(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("telegram returned status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, limit+1))
+}
@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/webhookauth"
+)
+
+const (
+	// EnvInboundWebhookAddr is the listen address of the inbound webhook endpoints;
+	// they are not served when it is empty
+	EnvInboundWebhookAddr = "INBOUND_WEBHOOK_ADDR"
+	// EnvTodoistClientSecret is the client secret of the Todoist app whose webhooks
+	// are accepted
+	EnvTodoistClientSecret = "TODOIST_CLIENT_SECRET"
+	// EnvInboundWebhookSecret is the secret webhooks in the bot's own event format
+	// are signed with, as /notify webhooks sign them
+	EnvInboundWebhookSecret = "INBOUND_WEBHOOK_SECRET"
+
+	inboundWebhookTimeout = 15 * time.Second
+)
+
+// InboundWebhookConfig describes the public endpoints other services tell the
+// bot about task changes through
+type InboundWebhookConfig struct {
+	Addr          string
+	TodoistSecret string
+	EventsSecret  string
+}
+
+// InboundWebhookConfigFromEnv reads the inbound webhook settings
+func InboundWebhookConfigFromEnv() (InboundWebhookConfig, error) {
+	config := InboundWebhookConfig{
+		Addr:          os.Getenv(EnvInboundWebhookAddr),
+		TodoistSecret: os.Getenv(EnvTodoistClientSecret),
+		EventsSecret:  os.Getenv(EnvInboundWebhookSecret),
+	}
+	if config.Addr != "" && config.TodoistSecret == "" && config.EventsSecret == "" && os.Getenv(EnvTelegramWebhookURL) == "" {
+		return InboundWebhookConfig{}, fmt.Errorf("%s is set, but none of %s, %s and %s is: there is nothing to serve",
+			EnvInboundWebhookAddr, EnvTodoistClientSecret, EnvInboundWebhookSecret, EnvTelegramWebhookURL)
+	}
+	return config, nil
+}
+
+// Enabled reports whether the endpoints are served
+func (c InboundWebhookConfig) Enabled() bool {
+	return c.Addr != ""
+}
+
+// InboundWebhookHandler serves the webhooks of each bot by its ID:
+// /webhooks/<bot>/todoist for Todoist and /webhooks/<bot>/events for the bot's
+// own event format. Every request passes webhookauth first; an endpoint
+// without a secret is not served at all. Bots in webhook mode also get their
+// Telegram updates at TelegramWebhookPath.
+func InboundWebhookHandler(config InboundWebhookConfig, bots map[string]*Bot) http.Handler {
+	mux := http.NewServeMux()
+	for id, b := range bots {
+		if b.telegramWebhook.Enabled() {
+			mux.HandleFunc(b.telegramWebhookPath, b.serveTelegramWebhook)
+		}
+		if config.TodoistSecret != "" {
+			verifier := webhookauth.NewVerifier(webhookauth.Todoist, config.TodoistSecret, webhookauth.DefaultTolerance)
+			mux.Handle("/webhooks/"+id+"/todoist", verifier.Middleware(http.HandlerFunc(b.handleTodoistWebhook)))
+		}
+		if config.EventsSecret != "" {
+			verifier := webhookauth.NewVerifier(webhookauth.Generic, config.EventsSecret, webhookauth.DefaultTolerance)
+			mux.Handle("/webhooks/"+id+"/events", verifier.Middleware(http.HandlerFunc(b.handleEventWebhook)))
+		}
+	}
+	return mux
+}
+
+// todoistWebhookEvent is the part of a Todoist webhook the bot reads
+type todoistWebhookEvent struct {
+	EventName string `json:"event_name"`
+	EventData struct {
+		ID      string `json:"id"`
+		Content string `json:"content"`
+	} `json:"event_data"`
+}
+
+// handleTodoistWebhook tells the chats a task was created from that it was
+// completed in Todoist. Other events are acknowledged and ignored.
+func (b *Bot) handleTodoistWebhook(w http.ResponseWriter, r *http.Request) {
+	var event todoistWebhookEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		webhookauth.WriteError(w, &webhookauth.Error{Status: http.StatusBadRequest, Code: "invalid_payload", Message: err.Error()})
+		return
+	}
+	if event.EventName != "item:completed" || event.EventData.ID == "" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), inboundWebhookTimeout)
+	defer cancel()
+	chatIDs, err := b.dbManager.ListTaskChats(ctx, event.EventData.ID)
+	if err != nil {
+		// The delivery is not marked processed, so Todoist's retry gets through
+		log.Printf("Error finding chats of completed Todoist task %s: %v", event.EventData.ID, err)
+		webhookauth.WriteError(w, &webhookauth.Error{Status: http.StatusServiceUnavailable, Code: "storage_unavailable", Message: "try again later"})
+		return
+	}
+	for _, chatID := range chatIDs {
+		b.announceCompletedTask(chatID, fmt.Sprintf(i18n.T(b.chatLanguage(chatID), i18n.TaskCompletedInTodoist), event.EventData.Content))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleEventWebhook posts task_completed events of the bot's own format to the
+// chat they name. Other events are acknowledged and ignored.
+func (b *Bot) handleEventWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		webhookauth.WriteError(w, &webhookauth.Error{Status: http.StatusBadRequest, Code: "unreadable_body", Message: err.Error()})
+		return
+	}
+	envelope, err := notify.ParseEnvelope(body)
+	if err != nil {
+		webhookauth.WriteError(w, &webhookauth.Error{Status: http.StatusBadRequest, Code: "invalid_payload", Message: err.Error()})
+		return
+	}
+	if envelope.Type != notify.EventTaskCompleted || envelope.Task == nil || envelope.Chat.ID == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	text := fmt.Sprintf(i18n.T(b.chatLanguage(envelope.Chat.ID), i18n.TaskCompletedBy), envelope.Task.Title)
+	if envelope.Actor != nil && envelope.Actor.Name != "" {
+		text += " (" + envelope.Actor.Name + ")"
+	}
+	b.announceCompletedTask(envelope.Chat.ID, text)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// announceCompletedTask tells a chat one of its tasks was completed outside the
+// bot. Chat notifiers are not called: the sender already knows.
+func (b *Bot) announceCompletedTask(chatID int64, text string) {
+	if b.isChatInactive(chatID) {
+		return
+	}
+	b.sendMessage(chatID, nil, text)
+}
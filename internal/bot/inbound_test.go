@@ -0,0 +1,168 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/webhookauth"
+)
+
+type sentText struct {
+	chatID string
+	text   string
+}
+
+// newInboundTestBot returns a bot whose Telegram messages are recorded
+func newInboundTestBot(t *testing.T, dbManager commands.DBManager) (*Bot, func() []sentText) {
+	var mu sync.Mutex
+	var sent []sentText
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		mu.Lock()
+		sent = append(sent, sentText{chatID: r.Form.Get("chat_id"), text: r.Form.Get("text")})
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1}}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	api := &tgbotapi.BotAPI{Token: "TOKEN", Client: server.Client()}
+	api.SetAPIEndpoint(server.URL + "/bot%s/%s")
+	return &Bot{api: api, dbManager: dbManager}, func() []sentText {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]sentText(nil), sent...)
+	}
+}
+
+func TestInboundWebhookHandler_RejectsUnsignedEvents(t *testing.T) {
+	b, sent := newInboundTestBot(t, new(commands.MockDBManager))
+	handler := InboundWebhookHandler(InboundWebhookConfig{EventsSecret: "s3cret"}, map[string]*Bot{"default": b})
+
+	body, _ := json.Marshal(notify.TaskEnvelope(notify.EventTaskCompleted, notify.TaskEvent{ChatID: 7, Task: notify.Task{ID: "1", Title: "Вход"}}))
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/default/events", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if len(sent()) != 0 {
+		t.Fatalf("expected no messages, got %v", sent())
+	}
+
+	// Todoist webhooks are not served without the Todoist secret
+	req = httptest.NewRequest(http.MethodPost, "/webhooks/default/todoist", strings.NewReader(`{}`))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestInboundWebhookHandler_AnnouncesCompletedEvent(t *testing.T) {
+	b, sent := newInboundTestBot(t, new(commands.MockDBManager))
+	handler := InboundWebhookHandler(InboundWebhookConfig{EventsSecret: "s3cret"}, map[string]*Bot{"default": b})
+
+	envelope := notify.TaskEnvelope(notify.EventTaskCompleted, notify.TaskEvent{ChatID: 7, Actor: "@alice", Task: notify.Task{ID: "1", Title: "Вход"}})
+	envelope.ID = "d-1"
+	body, _ := json.Marshal(envelope)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/default/events", strings.NewReader(string(body)))
+	req.Header.Set(notify.HeaderTimestamp, timestamp)
+	req.Header.Set(notify.HeaderDelivery, envelope.ID)
+	req.Header.Set(notify.HeaderSignature, notify.Signature("s3cret", timestamp, envelope.ID, body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	want := []sentText{{chatID: "7", text: "✅ Задача «Вход» выполнена (@alice)"}}
+	if got := sent(); len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestInboundWebhookHandler_AnnouncesTodoistCompletion(t *testing.T) {
+	dbManager := new(commands.MockDBManager)
+	dbManager.On("ListTaskChats", mock.Anything, "123").Return([]int64{42}, nil)
+	b, sent := newInboundTestBot(t, dbManager)
+	handler := InboundWebhookHandler(InboundWebhookConfig{TodoistSecret: "client"}, map[string]*Bot{"default": b})
+
+	for _, event := range []string{"item:updated", "item:completed"} {
+		body := `{"event_name":"` + event + `","event_data":{"id":"123","content":"Починить вход"}}`
+		mac := hmac.New(sha256.New, []byte("client"))
+		mac.Write([]byte(body))
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/default/todoist", strings.NewReader(body))
+		req.Header.Set(webhookauth.Todoist.SignatureHeader, base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+		req.Header.Set(webhookauth.Todoist.DeliveryHeader, event)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("%s: expected 204, got %d: %s", event, rec.Code, rec.Body.String())
+		}
+	}
+
+	want := []sentText{{chatID: "42", text: "✅ Задача «Починить вход» выполнена в Todoist"}}
+	if got := sent(); len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	dbManager.AssertNumberOfCalls(t, "ListTaskChats", 1)
+}
+
+func TestInboundWebhookHandler_TodoistRetriesFailedLookup(t *testing.T) {
+	dbManager := new(commands.MockDBManager)
+	dbManager.On("ListTaskChats", mock.Anything, "123").Return(nil, errors.New("db down")).Once()
+	dbManager.On("ListTaskChats", mock.Anything, "123").Return([]int64{42}, nil).Once()
+	b, sent := newInboundTestBot(t, dbManager)
+	handler := InboundWebhookHandler(InboundWebhookConfig{TodoistSecret: "client"}, map[string]*Bot{"default": b})
+
+	body := `{"event_name":"item:completed","event_data":{"id":"123","content":"Починить вход"}}`
+	mac := hmac.New(sha256.New, []byte("client"))
+	mac.Write([]byte(body))
+	signature := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusNoContent} {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/default/todoist", strings.NewReader(body))
+		req.Header.Set(webhookauth.Todoist.SignatureHeader, signature)
+		req.Header.Set(webhookauth.Todoist.DeliveryHeader, "d-1")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Fatalf("expected %d, got %d: %s", want, rec.Code, rec.Body.String())
+		}
+	}
+
+	if got := sent(); len(got) != 1 || got[0].chatID != "42" {
+		t.Fatalf("expected the retry to announce the task, got %v", got)
+	}
+}
+
+func TestInboundWebhookConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvInboundWebhookAddr, ":8090")
+	t.Setenv(EnvTodoistClientSecret, "")
+	t.Setenv(EnvInboundWebhookSecret, "")
+	t.Setenv(EnvTelegramWebhookURL, "")
+	if _, err := InboundWebhookConfigFromEnv(); err == nil {
+		t.Fatal("expected an error for endpoints without secrets")
+	}
+
+	t.Setenv(EnvInboundWebhookSecret, "s3cret")
+	config, err := InboundWebhookConfigFromEnv()
+	if err != nil || !config.Enabled() {
+		t.Fatalf("expected enabled config, got %+v (%v)", config, err)
+	}
+}
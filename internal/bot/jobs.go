@@ -0,0 +1,344 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+	"github.com/user/telegram-bot/internal/plans"
+)
+
+// documentReplyTimeout bounds exports that page through the whole Todoist project
+const documentReplyTimeout = 5 * time.Minute
+
+// backgroundProvider is the queue lane of the jobs that are not AI calls:
+// exports, nudges and digests run there one at a time
+const backgroundProvider = "background"
+
+// SetAIProvider names the AI provider whose concurrency limit AI jobs count against
+func (b *Bot) SetAIProvider(provider string) {
+	b.aiProvider = provider
+}
+
+// storedJobsTimeout bounds each database call that keeps AI jobs across restarts
+const storedJobsTimeout = 10 * time.Second
+
+// storedJob is what an AI job is rebuilt from after a restart. The kind, chat
+// and discussion are stored next to it.
+type storedJob struct {
+	Command    string            `json:"command,omitempty"`
+	Message    *tgbotapi.Message `json:"message,omitempty"`
+	ProgressID int               `json:"progress_id,omitempty"`
+}
+
+// enqueueCommand runs an AI-backed command through the job queue and keeps the
+// chat informed with a progress message that is removed once the result is ready.
+func (b *Bot) enqueueCommand(command commands.QueuedCommand, message *tgbotapi.Message) {
+	chatID := message.Chat.ID
+	// The job belongs to the discussion the command addresses, so closing that
+	// discussion cancels it
+	var sessionID int
+	if session, err := commands.FindSession(context.Background(), b.dbManager, message); err == nil && session != nil {
+		sessionID = session.ID
+	}
+	lang := b.replyLanguage(message)
+	progressID := b.sendProgressMessage(chatID, message, i18n.T(lang, i18n.JobQueued))
+
+	if err := b.submitCommandJob(command, message, sessionID, progressID, 0); err != nil {
+		log.Printf("Error submitting %s job: %v", command.JobKind(), err)
+		b.deleteMessage(chatID, progressID)
+		b.sendMessage(chatID, message, i18n.T(lang, i18n.JobQueueFailed))
+	}
+}
+
+// submitCommandJob queues a command whose progress message is already in the
+// chat; storedID is the stored job it was rebuilt from, 0 for a new one.
+func (b *Bot) submitCommandJob(command commands.QueuedCommand, message *tgbotapi.Message, sessionID, progressID, storedID int) error {
+	chatID := message.Chat.ID
+
+	// started guards the queue position update from overwriting the running status
+	var progressMu sync.Mutex
+	started := false
+
+	position, err := b.submitJob(jobs.Job{
+		Kind:      command.JobKind(),
+		ChatID:    chatID,
+		SessionID: sessionID,
+		Provider:  b.aiProvider,
+		Priority:  jobs.PriorityNormal,
+		OnStart: func() {
+			progressMu.Lock()
+			started = true
+			progressMu.Unlock()
+			b.editProgressMessage(chatID, progressID, i18n.T(b.replyLanguage(message), i18n.JobAnalyzing))
+			// Shows "typing…" in the chat header while the model answers
+			if err := b.request(chatID, tgbotapi.NewChatAction(chatID, tgbotapi.ChatTyping)); err != nil {
+				log.Printf("Error sending typing action to chat %d: %v", chatID, err)
+			}
+		},
+		Run: func(ctx context.Context) error {
+			// Commands that know the discussion narrow the scope down to it
+			ctx = ai.WithUsageScope(ctx, chatID, 0)
+			// The middlewares ran when the command was queued
+			response := commands.RunCommand(ctx, command, message)
+			responseMsg := response.Message()
+			b.deleteMessage(chatID, progressID)
+			if ctx.Err() != nil {
+				return fmt.Errorf("discussion closed before analysis finished: %w", ctx.Err())
+			}
+			b.sendCommandResponse(message, response, "", "")
+			if isDraftPreview(responseMsg) {
+				b.sendVoicePreview(chatID, responseMsg.Text)
+				b.summonParticipants(message)
+				b.notifyDraftCreated(message, responseMsg)
+			}
+			return nil
+		},
+	}, storedJob{Command: command.Name(), Message: message, ProgressID: progressID}, storedID)
+	if err != nil {
+		return err
+	}
+
+	progressMu.Lock()
+	defer progressMu.Unlock()
+	if position > 1 && !started {
+		b.editProgressMessage(chatID, progressID, fmt.Sprintf(i18n.T(b.replyLanguage(message), i18n.JobQueuedPosition), position))
+	}
+	return nil
+}
+
+// enqueueEditReply schedules an AI edit of the draft with a higher priority than
+// new analyses, since the user is actively waiting on the preview.
+func (b *Bot) enqueueEditReply(message *tgbotapi.Message, sessionID string) {
+	// Clean up the tracking
+	if err := b.dbManager.DeletePendingEdit(context.Background(), message.Chat.ID, message.ReplyToMessage.MessageID); err != nil {
+		log.Printf("Error forgetting edit request %d in chat %d: %v", message.ReplyToMessage.MessageID, message.Chat.ID, err)
+	}
+
+	// Simple field edits are applied locally: no AI call, no queue, no plan usage
+	if b.applyQuickEdit(message, sessionID) {
+		return
+	}
+
+	chatID := message.Chat.ID
+	lang := b.replyLanguage(message)
+	decision, err := b.planGate.Consume(context.Background(), chatID, plans.FeatureAIEdit)
+	if err != nil {
+		// Billing storage problems should not block editing
+		log.Printf("Error checking plan for chat %d, allowing edit: %v", chatID, err)
+	} else if !decision.Allowed {
+		b.sendMessage(chatID, message, commands.UpgradePromptText(decision, lang))
+		return
+	}
+
+	progressID := b.sendProgressMessage(chatID, message, i18n.T(lang, i18n.JobEditQueued))
+
+	if err := b.submitEditJob(message, sessionID, progressID, 0); err != nil {
+		log.Printf("Error submitting edit job for session %s: %v", sessionID, err)
+		b.deleteMessage(chatID, progressID)
+		b.sendMessage(chatID, message, i18n.T(lang, i18n.JobEditQueueFailed))
+	}
+}
+
+// submitEditJob queues an AI edit the plan already allowed; storedID is the
+// stored job it was rebuilt from, 0 for a new one.
+func (b *Bot) submitEditJob(message *tgbotapi.Message, sessionID string, progressID, storedID int) error {
+	chatID := message.Chat.ID
+	jobSessionID, _ := strconv.Atoi(sessionID)
+	_, err := b.submitJob(jobs.Job{
+		Kind:      "edit_draft",
+		ChatID:    chatID,
+		SessionID: jobSessionID,
+		Provider:  b.aiProvider,
+		Priority:  jobs.PriorityHigh,
+		OnStart: func() {
+			b.editProgressMessage(chatID, progressID, i18n.T(b.replyLanguage(message), i18n.JobEditApplying))
+		},
+		Run: func(ctx context.Context) error {
+			defer b.deleteMessage(chatID, progressID)
+			b.handleEditReply(ctx, message, sessionID)
+			return ctx.Err()
+		},
+	}, storedJob{Message: message, ProgressID: progressID}, storedID)
+	return err
+}
+
+// submitJob queues an AI job and keeps it in the database until it finishes,
+// so jobs a restart interrupts are queued again by requeueStoredJobs. A job
+// that cannot be stored still runs, it is only lost on a restart.
+func (b *Bot) submitJob(job jobs.Job, stored storedJob, storedID int) (int, error) {
+	if storedID == 0 {
+		storedID = b.storeJob(job, stored)
+	}
+	if storedID != 0 {
+		id := storedID
+		job.OnFinish = func() { b.forgetStoredJob(id) }
+	}
+
+	_, position, err := b.jobQueue.Submit(job)
+	if err != nil && storedID != 0 {
+		b.forgetStoredJob(storedID)
+	}
+	return position, err
+}
+
+func (b *Bot) storeJob(job jobs.Job, stored storedJob) int {
+	payload, err := json.Marshal(stored)
+	if err != nil {
+		log.Printf("Error encoding %s job of chat %d: %v", job.Kind, job.ChatID, err)
+		return 0
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), storedJobsTimeout)
+	defer cancel()
+	id, err := b.dbManager.SaveAIJob(ctx, db.AIJob{Kind: job.Kind, ChatID: job.ChatID, SessionID: job.SessionID, Payload: payload})
+	if err != nil {
+		log.Printf("Error storing %s job of chat %d, it will not survive a restart: %v", job.Kind, job.ChatID, err)
+		return 0
+	}
+	return id
+}
+
+func (b *Bot) forgetStoredJob(id int) {
+	ctx, cancel := context.WithTimeout(context.Background(), storedJobsTimeout)
+	defer cancel()
+	if err := b.dbManager.DeleteAIJob(ctx, id); err != nil {
+		log.Printf("Error deleting stored AI job %d: %v", id, err)
+	}
+}
+
+// requeueStoredJobs queues the AI jobs the previous run of the bot left
+// unfinished. A requeued edit does not use up the chat's plan allowance again.
+func (b *Bot) requeueStoredJobs() {
+	ctx, cancel := context.WithTimeout(context.Background(), storedJobsTimeout)
+	defer cancel()
+	storedJobs, err := b.dbManager.ListAIJobs(ctx)
+	if err != nil {
+		log.Printf("Error listing stored AI jobs: %v", err)
+		return
+	}
+
+	requeued := 0
+	for _, row := range storedJobs {
+		// With chats sharded, the instance that owns the chat requeues its jobs
+		if !b.ownsChat(row.ChatID) {
+			continue
+		}
+		if err := b.requeueStoredJob(row); err != nil {
+			log.Printf("Error requeueing stored %s job %d of chat %d, dropping it: %v", row.Kind, row.ID, row.ChatID, err)
+			b.forgetStoredJob(row.ID)
+			continue
+		}
+		requeued++
+	}
+	if requeued > 0 {
+		log.Printf("Requeued %d AI jobs left unfinished by the previous run", requeued)
+	}
+}
+
+func (b *Bot) requeueStoredJob(row db.AIJob) error {
+	var stored storedJob
+	if err := json.Unmarshal(row.Payload, &stored); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	switch row.Kind {
+	case "decision_summary":
+		return b.submitDecisionSummary(row.ChatID, stored.Message, row.SessionID, row.ID)
+	case "edit_draft":
+		if stored.Message == nil || stored.Message.Chat == nil {
+			return errors.New("no message to edit from")
+		}
+		return b.submitEditJob(stored.Message, strconv.Itoa(row.SessionID), stored.ProgressID, row.ID)
+	}
+
+	if stored.Message == nil || stored.Message.Chat == nil {
+		return errors.New("no command message")
+	}
+	command, ok := b.commandRegistry.Get(stored.Command)
+	if !ok {
+		return fmt.Errorf("unknown command %q", stored.Command)
+	}
+	queued, ok := command.(commands.QueuedCommand)
+	if !ok {
+		return fmt.Errorf("command %q is no longer queued", stored.Command)
+	}
+	return b.submitCommandJob(queued, stored.Message, row.SessionID, stored.ProgressID, row.ID)
+}
+
+// sendProgressMessage posts a status the caller edits or deletes later, in the
+// forum topic of trigger, and returns its ID, 0 when it was not sent
+func (b *Bot) sendProgressMessage(chatID int64, trigger *tgbotapi.Message, text string) int {
+	msg := tgbotapi.NewMessage(chatID, text)
+	inTopic(&msg, trigger)
+	sent, err := b.send(msg)
+	if err != nil {
+		log.Printf("Error sending progress message: %v", err)
+		return 0
+	}
+	return sent.MessageID
+}
+
+func (b *Bot) editProgressMessage(chatID int64, messageID int, text string) {
+	if messageID == 0 {
+		return
+	}
+	if err := b.request(chatID, tgbotapi.NewEditMessageText(chatID, messageID, text)); err != nil {
+		log.Printf("Error updating progress message %d in chat %d: %v", messageID, chatID, err)
+	}
+}
+
+func (b *Bot) deleteMessage(chatID int64, messageID int) {
+	if messageID == 0 {
+		return
+	}
+	if err := b.request(chatID, tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		log.Printf("Error deleting message %d in chat %d: %v", messageID, chatID, err)
+	}
+}
+
+// submitBackgroundJob queues work that is not an AI call, so it shows in /jobs
+// and can be canceled or retried there. These jobs are not stored, a restart
+// drops them.
+func (b *Bot) submitBackgroundJob(kind string, chatID int64, priority jobs.Priority, run func(ctx context.Context) error) {
+	_, _, err := b.jobQueue.Submit(jobs.Job{
+		Kind:     kind,
+		ChatID:   chatID,
+		Provider: backgroundProvider,
+		Priority: priority,
+		Run:      run,
+	})
+	if err != nil {
+		log.Printf("Error queueing %s job of chat %d: %v", kind, chatID, err)
+	}
+}
+
+// sendDocumentReply builds and sends the file of a command; it runs as a
+// background job
+func (b *Bot) sendDocumentReply(ctx context.Context, command commands.DocumentReplyCommand, message *tgbotapi.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, documentReplyTimeout)
+	defer cancel()
+
+	doc, err := command.ReplyDocument(ctx, message)
+	if err != nil {
+		b.sendMessage(message.Chat.ID, message, i18n.T(b.replyLanguage(message), i18n.DocumentFailed))
+		return fmt.Errorf("failed to prepare document: %w", err)
+	}
+	if doc == nil {
+		return nil
+	}
+	if err := b.request(message.Chat.ID, doc); err != nil {
+		return fmt.Errorf("failed to send document: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/jobs"
+)
+
+func TestRequeueStoredJobs(t *testing.T) {
+	dbManager := new(commands.MockDBManager)
+	dbManager.On("ListAIJobs", mock.Anything).Return([]db.AIJob{
+		{ID: 1, Kind: "decision_summary", ChatID: 1, SessionID: 10, Payload: []byte(`{}`)},
+		{ID: 2, Kind: "create_task", ChatID: 1, Payload: []byte(`{"command":"gone","message":{"message_id":5,"chat":{"id":1}}}`)},
+		{ID: 3, Kind: "decision_summary", ChatID: 2, SessionID: 20, Payload: []byte(`{}`)},
+	}, nil)
+	dbManager.On("DeleteAIJob", mock.Anything, mock.Anything).Return(nil)
+
+	// The queue is not started, so requeued jobs stay pending
+	queue := jobs.NewQueue(nil)
+	b := &Bot{
+		dbManager:       dbManager,
+		jobQueue:        queue,
+		commandRegistry: commands.NewRegistry(),
+		chatRouter:      fakeChatRouter{2: "http://other.example.com"},
+	}
+	b.requeueStoredJobs()
+
+	pending := queue.List()
+	if len(pending) != 1 || pending[0].Kind != "decision_summary" || pending[0].SessionID != 10 {
+		t.Fatalf("expected the summary of session 10 to be requeued, got %+v", pending)
+	}
+	// A job that can no longer be rebuilt is dropped; another instance's job is left alone
+	dbManager.AssertCalled(t, "DeleteAIJob", mock.Anything, 2)
+	dbManager.AssertNumberOfCalls(t, "DeleteAIJob", 1)
+	dbManager.AssertNotCalled(t, "SaveAIJob", mock.Anything, mock.Anything)
+
+	// The stored row goes once the requeued job is canceled
+	if err := queue.Cancel(pending[0].ID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	dbManager.AssertCalled(t, "DeleteAIJob", mock.Anything, 1)
+}
@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/commands"
+)
+
+// EnvCreateMissingLabels lets drafts keep AI-suggested labels the tracker does
+// not have yet and creates them when the task is confirmed; by default such
+// labels are dropped from the draft
+const EnvCreateMissingLabels = "CREATE_MISSING_LABELS"
+
+// CreateMissingLabelsFromEnv reads CREATE_MISSING_LABELS
+func CreateMissingLabelsFromEnv() (bool, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvCreateMissingLabels))
+	if raw == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: expected true or false", EnvCreateMissingLabels, raw)
+	}
+	return enabled, nil
+}
+
+// SetCreateMissingLabels makes drafts keep unknown suggested labels and
+// confirmed tasks create them
+func (b *Bot) SetCreateMissingLabels(enabled bool) {
+	b.createMissingLabels = enabled
+	b.callbackHandler.SetCreateMissingLabels(enabled)
+	command, ok := b.commandRegistry.Get("create_task")
+	if !ok {
+		return
+	}
+	if createTask, ok := command.(*commands.CreateTaskCommand); ok {
+		createTask.SetCreateMissingLabels(enabled)
+	}
+}
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+// EnvListPageSize is how many tasks one page of /list shows, e.g. "10"
+const EnvListPageSize = "LIST_PAGE_SIZE"
+
+// maxListPageSize keeps a page within one Telegram message, since pages are
+// turned by editing it
+const maxListPageSize = 25
+
+const listPageTimeout = 15 * time.Second
+
+// ListPageSizeFromEnv reads LIST_PAGE_SIZE
+func ListPageSizeFromEnv() (int, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvListPageSize))
+	if raw == "" {
+		return commands.DefaultListPageSize, nil
+	}
+	size, err := strconv.Atoi(raw)
+	if err != nil || size < 1 || size > maxListPageSize {
+		return 0, fmt.Errorf("invalid %s %q: expected a number of tasks from 1 to %d", EnvListPageSize, raw, maxListPageSize)
+	}
+	return size, nil
+}
+
+// SetListPageSize sets how many tasks one page of /list shows
+func (b *Bot) SetListPageSize(size int) {
+	if list, ok := b.listCommand(); ok {
+		list.SetPageSize(size)
+	}
+}
+
+func (b *Bot) listCommand() (*commands.ListCommand, bool) {
+	command, ok := b.commandRegistry.Get("list")
+	if !ok {
+		return nil, false
+	}
+	list, ok := command.(*commands.ListCommand)
+	return list, ok
+}
+
+// handleListPageCallback shows another page of a /list listing in place of
+// the current one; anyone in the chat can turn the pages
+func (b *Bot) handleListPageCallback(c *commands.CallbackContext) {
+	list, ok := b.listCommand()
+	query, valid := commands.ParseListPage(c.Data)
+	if !ok || !valid {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+	c.Answer("")
+
+	ctx, cancel := context.WithTimeout(context.Background(), listPageTimeout)
+	defer cancel()
+	b.showListPage(ctx, c, list, query, "")
+}
+
+// handleListDoneCallback completes a task of a /list page and shows the page again
+func (b *Bot) handleListDoneCallback(c *commands.CallbackContext) {
+	list, ok := b.listCommand()
+	taskID, query, valid := commands.ParseListTask(c.Data)
+	if !ok || !valid {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listPageTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+
+	task, err := list.CompleteTask(ctx, chatID, taskID)
+	if err != nil {
+		log.Printf("Error completing task %s from /list in chat %d: %v", taskID, chatID, err)
+		c.Answer(c.T(i18n.TaskCompleteFailed))
+		return
+	}
+	c.Answer(fmt.Sprintf(c.T(i18n.TaskCompleted), task.Title))
+	if !task.Completed {
+		b.notifyTaskCompleted(notify.TaskEvent{
+			ChatID: chatID,
+			Actor:  actorName(c.Query.From),
+			Task:   notify.Task{ID: task.ID, Title: task.Title, URL: task.URL},
+		})
+	}
+	b.showListPage(ctx, c, list, query, "")
+}
+
+// handleListDeleteCallback asks the user who pressed 🗑 to confirm deleting
+// a task of a /list page, in place of the page's buttons
+func (b *Bot) handleListDeleteCallback(c *commands.CallbackContext) {
+	deleteCmd, ok := b.deleteCommand()
+	taskID, query, valid := commands.ParseListTask(c.Data)
+	if !ok || !valid {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), listPageTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+
+	task, err := deleteCmd.Task(ctx, chatID, taskID)
+	if err != nil {
+		log.Printf("Error getting task %s to delete from /list in chat %d: %v", taskID, chatID, err)
+		c.Answer(c.T(i18n.TaskNotFound))
+		return
+	}
+	ownerID := c.Query.From.ID
+	b.deletes.Put(chatID, &commands.PendingDelete{OwnerID: ownerID, Task: task, List: &query})
+	c.Answer(c.T(i18n.DeleteConfirmPrompt))
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, c.MessageID(), commands.DeleteConfirmKeyboard(ownerID, task, c.Lang()))
+	if err := b.request(chatID, edit); err != nil {
+		log.Printf("Error asking to delete task %s in chat %d: %v", taskID, chatID, err)
+	}
+}
+
+// showListPage renders a page of the listing in place of the pressed message,
+// under the note if there is one
+func (b *Bot) showListPage(ctx context.Context, c *commands.CallbackContext, list *commands.ListCommand, query commands.TaskListQuery, note string) {
+	chatID := c.ChatID()
+	var text string
+	var keyboard *tgbotapi.InlineKeyboardMarkup
+	if note != "" {
+		text, keyboard = list.TasksPageWithNote(ctx, chatID, query, note)
+	} else {
+		text, keyboard = list.TasksPage(ctx, chatID, query)
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, c.MessageID(), text)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = keyboard
+	if err := b.request(chatID, edit); err != nil {
+		log.Printf("Error showing /list page in chat %d: %v", chatID, err)
+	}
+}
@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"log"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+)
+
+// commandMenu is the command list of one setMyCommands scope
+type commandMenu struct {
+	name   string
+	config tgbotapi.SetMyCommandsConfig
+}
+
+// registerCommandMenu sets the commands Telegram clients suggest after "/":
+// one menu for private chats, one for groups, and for each bot admin their
+// private chat's menu with the admin commands. A failed scope is logged and
+// keeps its previous menu.
+func (b *Bot) registerCommandMenu() {
+	menus := []commandMenu{
+		{"private chats", tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeAllPrivateChats(), b.commandRegistry.MenuCommands(commands.MenuPrivate)...)},
+		{"groups", tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeAllGroupChats(), b.commandRegistry.MenuCommands(commands.MenuGroups)...)},
+	}
+	adminMenu := b.commandRegistry.MenuCommands(commands.MenuAdmins)
+	for _, adminID := range b.admins.IDs() {
+		menus = append(menus, commandMenu{"admin chat", tgbotapi.NewSetMyCommandsWithScope(tgbotapi.NewBotCommandScopeChat(adminID), adminMenu...)})
+	}
+
+	for _, menu := range menus {
+		if _, err := b.api.Request(menu.config); err != nil {
+			// An admin who never opened a private chat with the bot has no chat to set it for
+			log.Printf("Error setting the command menu for %s: %v", menu.name, err)
+		}
+	}
+	log.Printf("Command menus registered: %d commands in private chats, %d in groups, %d for admins",
+		len(menus[0].config.Commands), len(menus[1].config.Commands), len(adminMenu))
+}
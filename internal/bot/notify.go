@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+// notifyTimeout bounds one notifier call including webhook retries, so a slow
+// receiver or SMTP server cannot pile up goroutines
+const notifyTimeout = time.Minute
+
+// SetNotifiers enables the /notify command and forwards chat events to the
+// notifiers chats configure. The telegram kind is bound to this bot.
+func (b *Bot) SetNotifiers(registry *notify.Registry) {
+	registry.Register(notify.KindTelegram, notify.NewTelegramFactory(func(chatID int64, text string) {
+		b.sendMessage(chatID, nil, text)
+	}))
+	b.notifiers = registry
+	b.commandRegistry.Register(commands.NewNotifyCommand(b.dbManager, registry, b.admins))
+}
+
+// notifyDraftCreated reports a new draft preview of a discussion
+func (b *Bot) notifyDraftCreated(message *tgbotapi.Message, preview *tgbotapi.MessageConfig) {
+	if b.notifiers == nil {
+		return
+	}
+	sessionID, ok := previewSessionID(preview)
+	if !ok {
+		return
+	}
+	draft, err := b.dbManager.GetDraftTask(context.Background(), sessionID)
+	if err != nil {
+		log.Printf("Error loading draft of session %d for notifiers: %v", sessionID, err)
+		return
+	}
+
+	event := notify.DraftEvent{
+		ChatID:    message.Chat.ID,
+		ChatTitle: message.Chat.Title,
+		SessionID: sessionID,
+		Actor:     actorName(message.From),
+		Draft: notify.Draft{
+			Title:       draft.Title.String,
+			Description: draft.Description.String,
+			DueDate:     draft.DueISO.String,
+			Priority:    int(draft.Priority.Int32),
+		},
+	}
+	b.dispatchNotify(event.ChatID, "draft_created", func(ctx context.Context, n notify.Notifier) error {
+		return n.OnDraftCreated(ctx, event)
+	})
+}
+
+// notifyTaskCreated reports a task created from a discussion
+func (b *Bot) notifyTaskCreated(event notify.TaskEvent) {
+	b.dispatchNotify(event.ChatID, "task_created", func(ctx context.Context, n notify.Notifier) error {
+		return n.OnTaskCreated(ctx, event)
+	})
+}
+
+// notifyTaskCompleted reports a task completed through the bot
+func (b *Bot) notifyTaskCompleted(event notify.TaskEvent) {
+	b.dispatchNotify(event.ChatID, "task_completed", func(ctx context.Context, n notify.Notifier) error {
+		return n.OnTaskCompleted(ctx, event)
+	})
+}
+
+// notifySessionClosed reports a closed discussion
+func (b *Bot) notifySessionClosed(event notify.SessionEvent) {
+	b.dispatchNotify(event.ChatID, "session_closed", func(ctx context.Context, n notify.Notifier) error {
+		return n.OnSessionClosed(ctx, event)
+	})
+}
+
+// dispatchNotify calls every notifier of a chat in the background. Failures
+// are only logged: notifications never affect the chat flow.
+func (b *Bot) dispatchNotify(chatID int64, event string, call func(ctx context.Context, n notify.Notifier) error) {
+	if b.notifiers == nil {
+		return
+	}
+
+	go func() {
+		configs, err := b.dbManager.ListChatNotifiers(context.Background(), chatID)
+		if err != nil {
+			log.Printf("Error loading notifiers for chat %d: %v", chatID, err)
+			return
+		}
+		for _, cfg := range configs {
+			notifier, err := b.notifiers.Build(cfg.Kind, cfg.Target, cfg.Secret)
+			if err != nil {
+				log.Printf("Skipping notifier %d of chat %d: %v", cfg.ID, chatID, err)
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+			if err := call(ctx, notifier); err != nil {
+				log.Printf("Notifier %d (%s) of chat %d failed on %s: %v", cfg.ID, cfg.Kind, chatID, event, err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// actorName returns how notifications refer to a Telegram user
+func actorName(user *tgbotapi.User) string {
+	if user == nil {
+		return ""
+	}
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return strings.TrimSpace(user.FirstName + " " + user.LastName)
+}
@@ -0,0 +1,241 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// EnvTaskNudgeAfter is how long a created task may stay without an assignee
+// before the chat is reminded, e.g. "24h"; empty or "0" disables nudges.
+const EnvTaskNudgeAfter = "TASK_NUDGE_AFTER"
+
+const (
+	minTaskNudgeAfter = 10 * time.Minute
+	nudgeInterval     = 5 * time.Minute
+	nudgeTimeout      = time.Minute
+	nudgeBatch        = 20
+	// nudgeMaxAge keeps nudges from reaching tasks that were created long
+	// before nudges were turned on
+	nudgeMaxAge = 7 * 24 * time.Hour
+)
+
+// TaskNudgeAfterFromEnv reads TASK_NUDGE_AFTER
+func TaskNudgeAfterFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvTaskNudgeAfter))
+	if raw == "" {
+		return 0, nil
+	}
+	after, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", EnvTaskNudgeAfter, raw, err)
+	}
+	if after != 0 && after < minTaskNudgeAfter {
+		return 0, fmt.Errorf("%s must be 0 or at least %v", EnvTaskNudgeAfter, minTaskNudgeAfter)
+	}
+	return after, nil
+}
+
+// SetTaskNudges reminds chats about created tasks that still have no assignee after the given period
+func (b *Bot) SetTaskNudges(after time.Duration) {
+	b.taskNudgeAfter = after
+}
+
+// isNudgeCallback reports whether callback data belongs to the buttons of a nudge
+func isNudgeCallback(data string) bool {
+	return strings.HasPrefix(data, commands.CallbackNudgeTakeTask+commands.CallbackDataSeparator) ||
+		strings.HasPrefix(data, commands.CallbackNudgeAssign+commands.CallbackDataSeparator)
+}
+
+// parseNudgeCallback returns the created task of a nudge button and the
+// Todoist user it assigns; the user is empty for "take it myself"
+func parseNudgeCallback(data string) (int, string, bool) {
+	parts := strings.Split(data, commands.CallbackDataSeparator)
+	switch {
+	case len(parts) == 2 && parts[0] == commands.CallbackNudgeTakeTask:
+	case len(parts) == 3 && parts[0] == commands.CallbackNudgeAssign && parts[2] != "":
+	default:
+		return 0, "", false
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, "", false
+	}
+	if len(parts) == 3 {
+		return id, parts[2], true
+	}
+	return id, "", true
+}
+
+// runTaskNudges periodically reminds chats about created tasks left without an assignee
+func (b *Bot) runTaskNudges() {
+	ticker := time.NewTicker(nudgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+			b.sendTaskNudges()
+		}
+	}
+}
+
+func (b *Bot) sendTaskNudges() {
+	ctx, cancel := context.WithTimeout(context.Background(), nudgeTimeout)
+	defer cancel()
+
+	now := time.Now()
+	cutoff := now.Add(-b.taskNudgeAfter)
+	nudges, err := b.dbManager.ListTasksToNudge(ctx, cutoff.Add(-nudgeMaxAge), cutoff, nudgeBatch)
+	if err != nil {
+		log.Printf("Error listing tasks to nudge: %v", err)
+		return
+	}
+
+	for _, nudge := range nudges {
+		if !b.ownsChat(nudge.ChatID) {
+			continue
+		}
+		// Left for the next run, when the chat is awake again or its digest time comes
+		if b.inQuietHours(ctx, nudge.ChatID, now) || !b.atDigestTime(ctx, nudge.ChatID, now) {
+			continue
+		}
+		// Marked before the job runs, so the next run does not queue it again;
+		// a failed nudge is retried from /jobs
+		if err := b.dbManager.MarkTaskNudged(ctx, nudge.CreatedTaskID); err != nil {
+			log.Printf("Error marking task %d nudged: %v", nudge.CreatedTaskID, err)
+			continue
+		}
+		nudge := nudge
+		b.submitBackgroundJob("nudge", nudge.ChatID, jobs.PriorityLow, func(ctx context.Context) error {
+			return b.sendTaskNudge(ctx, nudge)
+		})
+	}
+}
+
+// sendTaskNudge posts the reminder unless the task got an assignee in Todoist
+// or is completed
+func (b *Bot) sendTaskNudge(ctx context.Context, nudge db.TaskNudge) error {
+	task, err := b.todoistClient.GetTask(ctx, nudge.TodoistTaskID)
+	if err != nil {
+		return fmt.Errorf("failed to get Todoist task %s: %w", nudge.TodoistTaskID, err)
+	}
+	if task.AssigneeID != "" || task.IsCompleted {
+		return nil
+	}
+
+	var candidates []commands.NudgeCandidate
+	if mappings := b.chatAssigneeMappings(ctx, nudge.ChatID); len(mappings) > 0 {
+		participants, err := b.dbManager.GetSessionParticipants(ctx, nudge.SessionID)
+		if err != nil {
+			log.Printf("Error getting participants of session %d: %v", nudge.SessionID, err)
+		}
+		candidates = commands.NudgeCandidates(participants, mappings)
+	}
+
+	if _, err := b.send(commands.TaskNudgeMessage(nudge, candidates, b.chatLanguage(nudge.ChatID))); err != nil {
+		return fmt.Errorf("failed to send nudge for task %d: %w", nudge.CreatedTaskID, err)
+	}
+	return nil
+}
+
+// chatAssigneeMappings returns the assignee mappings of the chat's current project
+func (b *Bot) chatAssigneeMappings(ctx context.Context, chatID int64) []db.AssigneeMapping {
+	projectID, err := b.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		return nil
+	}
+	mappings, err := b.dbManager.GetAssigneeMappings(ctx, chatID, projectID)
+	if err != nil {
+		log.Printf("Error getting assignee mappings for chat %d: %v", chatID, err)
+		return nil
+	}
+	return mappings
+}
+
+// handleNudgeCallback assigns the task of a nudge in Todoist
+func (b *Bot) handleNudgeCallback(c *commands.CallbackContext) {
+	callback := c.Query
+	chatID := c.ChatID()
+	answer := c.Answer
+	clearButtons := func() { c.ClearButtons() }
+
+	createdID, todoistUserID, ok := parseNudgeCallback(callback.Data)
+	if !ok {
+		answer(c.T(i18n.CallbackStale))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nudgeTimeout)
+	defer cancel()
+
+	created, err := b.dbManager.GetChatCreatedTask(ctx, chatID, createdID)
+	if err != nil {
+		log.Printf("Error getting created task %d: %v", createdID, err)
+		answer(c.T(i18n.NudgeTaskLoadFailed))
+		return
+	}
+	if created == nil {
+		answer(c.T(i18n.TaskNotFound))
+		clearButtons()
+		return
+	}
+	if created.AssigneeTodoistID.Valid && created.AssigneeTodoistID.String != "" {
+		answer(fmt.Sprintf(c.T(i18n.NudgeAlreadyAssigned), created.AssigneeName.String))
+		clearButtons()
+		return
+	}
+
+	mappings := b.chatAssigneeMappings(ctx, chatID)
+	var mapping db.AssigneeMapping
+	if todoistUserID == "" {
+		mapping, ok = commands.MappingForAlias(mappings, callback.From.UserName)
+		if !ok {
+			answer(c.T(i18n.NudgeNotMapped))
+			return
+		}
+	} else if mapping, ok = commands.MappingForTodoistUser(mappings, todoistUserID); !ok {
+		answer(c.T(i18n.NudgeMappingGone))
+		return
+	}
+
+	task, err := b.todoistClient.GetTask(ctx, created.TodoistTaskID)
+	if err != nil {
+		log.Printf("Error getting Todoist task %s for nudge: %v", created.TodoistTaskID, err)
+		answer(c.T(i18n.NudgeTodoistLoadFailed))
+		return
+	}
+	if _, err := b.todoistClient.UpdateTask(ctx, created.TodoistTaskID, &todoist.TaskRequest{
+		Content:    task.Content,
+		AssigneeID: mapping.TodoistUserID,
+	}); err != nil {
+		log.Printf("Error assigning Todoist task %s: %v", created.TodoistTaskID, err)
+		answer(c.T(i18n.NudgeAssignFailed))
+		return
+	}
+
+	if err := b.dbManager.SetCreatedTaskAssignee(ctx, created.ID, db.AssigneeSnapshot{
+		TodoistID:   mapping.TodoistUserID,
+		Name:        mapping.TodoistUserName,
+		Email:       mapping.TodoistUserEmail,
+		MatchSource: commands.NudgeMatchSource,
+	}); err != nil {
+		log.Printf("Error saving assignee of created task %d: %v", created.ID, err)
+	}
+
+	answer("")
+	clearButtons()
+	b.sendMessage(chatID, c.Query.Message, fmt.Sprintf(c.T(i18n.NudgeAssigned), created.Title.String, mapping.TodoistUserName, actorName(callback.From)))
+}
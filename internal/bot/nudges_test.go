@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func TestTaskNudgeAfterFromEnv(t *testing.T) {
+	t.Setenv(EnvTaskNudgeAfter, "")
+	if after, err := TaskNudgeAfterFromEnv(); err != nil || after != 0 {
+		t.Fatalf("expected nudges to be off by default, got %v (%v)", after, err)
+	}
+
+	t.Setenv(EnvTaskNudgeAfter, "24h")
+	if after, err := TaskNudgeAfterFromEnv(); err != nil || after != 24*time.Hour {
+		t.Fatalf("expected 24h, got %v (%v)", after, err)
+	}
+
+	t.Setenv(EnvTaskNudgeAfter, "1m")
+	if _, err := TaskNudgeAfterFromEnv(); err == nil {
+		t.Fatal("expected error for a period shorter than the minimum")
+	}
+}
+
+func TestParseNudgeCallback(t *testing.T) {
+	id, user, ok := parseNudgeCallback("nudge_take:12")
+	if !ok || id != 12 || user != "" {
+		t.Fatalf("expected take of task 12, got %d %q (ok=%v)", id, user, ok)
+	}
+
+	id, user, ok = parseNudgeCallback("nudge_assign:12:777")
+	if !ok || id != 12 || user != "777" {
+		t.Fatalf("expected assignment of task 12 to 777, got %d %q (ok=%v)", id, user, ok)
+	}
+
+	for _, data := range []string{"nudge_take:x", "nudge_assign:12", "nudge_assign:12:", "nudge_take:1:2"} {
+		if _, _, ok := parseNudgeCallback(data); ok {
+			t.Errorf("expected %q to be rejected", data)
+		}
+	}
+}
+
+func TestSendTaskNudges_SkipsChatsOfOtherInstances(t *testing.T) {
+	dbManager := new(commands.MockDBManager)
+	dbManager.On("ListTasksToNudge", mock.Anything, mock.Anything, mock.Anything, nudgeBatch).
+		Return([]db.TaskNudge{{CreatedTaskID: 1, ChatID: 2, TodoistTaskID: "t1"}}, nil)
+
+	// The nudge would reach Todoist through the nil client if it were sent
+	b := &Bot{dbManager: dbManager, taskNudgeAfter: time.Hour, chatRouter: fakeChatRouter{2: "http://other.example.com"}}
+	b.sendTaskNudges()
+
+	dbManager.AssertNotCalled(t, "MarkTaskNudged", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// addParticipant records the author of a message saved into the discussion
+func (b *Bot) addParticipant(ctx context.Context, sessionID int, message *tgbotapi.Message) {
+	if message.From == nil || message.From.IsBot {
+		return
+	}
+	displayName := strings.TrimSpace(message.From.FirstName + " " + message.From.LastName)
+	if err := b.dbManager.AddSessionParticipant(ctx, sessionID, message.From.ID, message.From.UserName, displayName); err != nil {
+		log.Printf("Error adding participant %d in chat %d: %v", message.From.ID, message.Chat.ID, err)
+	}
+}
+
+// summonParticipants mentions everyone who wrote in the discussion, except the
+// user who asked for the draft, when the chat turned summoning on
+func (b *Bot) summonParticipants(request *tgbotapi.Message) {
+	chatID := request.Chat.ID
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	enabled, err := b.dbManager.SummonParticipantsEnabled(ctx, chatID)
+	if err != nil {
+		log.Printf("Error checking participant summoning for chat %d: %v", chatID, err)
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	session, err := commands.FindSession(ctx, b.dbManager, request)
+	if err != nil {
+		log.Printf("Error getting active session for chat %d: %v", chatID, err)
+		return
+	}
+	participants, err := b.dbManager.GetSessionParticipants(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error getting participants of session %d: %v", session.ID, err)
+		return
+	}
+
+	var requesterID int64
+	if request.From != nil {
+		requesterID = request.From.ID
+	}
+	lang := b.chatLanguage(chatID)
+	mentions := commands.FormatParticipantMentions(participants, requesterID, lang)
+	if mentions == "" {
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.ParticipantsSummon), mentions))
+	msg.ParseMode = "Markdown"
+	inTopic(&msg, request)
+	if _, err := b.send(msg); err != nil {
+		log.Printf("Error summoning participants in chat %d: %v", chatID, err)
+	}
+}
@@ -0,0 +1,69 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// permissionProblems lists what the bot cannot do in a group that a
+// discussion needs: read plain messages, send the draft and edit its preview
+func permissionProblems(lang i18n.Lang, self tgbotapi.User, member tgbotapi.ChatMember) []string {
+	if member.HasLeft() || member.WasKicked() {
+		return []string{i18n.T(lang, i18n.PermissionNotMember)}
+	}
+
+	var problems []string
+	if missesGroupMessages(self, member) {
+		problems = append(problems, i18n.T(lang, i18n.PermissionPrivacy))
+	}
+	if member.Status == "restricted" && !member.CanSendMessages {
+		problems = append(problems, i18n.T(lang, i18n.PermissionMuted))
+	}
+	return problems
+}
+
+// preflightMiddleware checks the bot's rights in a group before
+// /start_discussion and explains what to fix instead of collecting nothing
+func (b *Bot) preflightMiddleware(next commands.Handler) commands.Handler {
+	return func(ctx context.Context, cmd commands.Command, message *tgbotapi.Message) *commands.Response {
+		if cmd.Name() != "start_discussion" {
+			return next(ctx, cmd, message)
+		}
+		if notice := b.missingDiscussionRights(message); notice != "" {
+			return commands.NewResponse(tgbotapi.NewMessage(message.Chat.ID, notice))
+		}
+		return next(ctx, cmd, message)
+	}
+}
+
+// missingDiscussionRights explains what the bot lacks in a group to collect a
+// discussion, empty when nothing is missing
+func (b *Bot) missingDiscussionRights(message *tgbotapi.Message) string {
+	if !isGroupChat(message.Chat) {
+		return ""
+	}
+
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: message.Chat.ID, UserID: b.api.Self.ID},
+	})
+	if err != nil {
+		// The check is advisory: a failed lookup must not block discussions
+		log.Printf("Error checking bot rights in chat %d: %v", message.Chat.ID, err)
+		return ""
+	}
+
+	lang := b.replyLanguage(message)
+	problems := permissionProblems(lang, b.api.Self, member)
+	if len(problems) == 0 {
+		return ""
+	}
+
+	log.Printf("Discussion in chat %d blocked by missing bot rights: %s", message.Chat.ID, strings.Join(problems, "; "))
+	return fmt.Sprintf(i18n.T(lang, i18n.PermissionBlocked), strings.Join(problems, "\n— "))
+}
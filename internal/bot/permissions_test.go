@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestPermissionProblems(t *testing.T) {
+	privacyOn := tgbotapi.User{}
+	privacyOff := tgbotapi.User{CanReadAllGroupMessages: true}
+
+	tests := []struct {
+		name   string
+		self   tgbotapi.User
+		member tgbotapi.ChatMember
+		want   []string
+	}{
+		{name: "admin", self: privacyOn, member: tgbotapi.ChatMember{Status: "administrator"}},
+		{name: "member without privacy mode", self: privacyOff, member: tgbotapi.ChatMember{Status: "member"}},
+		{name: "member with privacy mode", self: privacyOn, member: tgbotapi.ChatMember{Status: "member"}, want: []string{"/setprivacy"}},
+		{name: "muted", self: privacyOff, member: tgbotapi.ChatMember{Status: "restricted"}, want: []string{"запрещено писать"}},
+		{name: "restricted but may write", self: privacyOff, member: tgbotapi.ChatMember{Status: "restricted", CanSendMessages: true}},
+		{name: "removed", self: privacyOff, member: tgbotapi.ChatMember{Status: "kicked"}, want: []string{"не состоит"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := permissionProblems(i18n.Russian, tt.self, tt.member)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %q", len(tt.want), problems)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %q does not mention %q", problems[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+const (
+	previewCleanupInterval = 10 * time.Minute
+	// previewTTL is how long a preview of a still open session keeps its
+	// buttons, and a request to reply with an edit stays valid
+	previewTTL            = 24 * time.Hour
+	previewCleanupBatch   = 50
+	previewCleanupTimeout = time.Minute
+)
+
+// previewSessionID returns the session of a draft preview from its confirm
+// or alternative button
+func previewSessionID(msgConfig *tgbotapi.MessageConfig) (int, bool) {
+	data, ok := draftPreviewButton(msgConfig)
+	if !ok {
+		return 0, false
+	}
+	sessionID := data.SessionID()
+	return sessionID, sessionID != 0
+}
+
+// recordPreview remembers a sent draft preview so its buttons can be removed later
+func (b *Bot) recordPreview(msgConfig *tgbotapi.MessageConfig, sent tgbotapi.Message) {
+	sessionID, ok := previewSessionID(msgConfig)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), previewCleanupTimeout)
+	defer cancel()
+	if err := b.dbManager.RecordPreviewMessage(ctx, sessionID, msgConfig.ChatID, sent.MessageID, msgConfig.Text); err != nil {
+		log.Printf("Error recording preview message %d: %v", sent.MessageID, err)
+	}
+}
+
+// runPreviewCleanup periodically removes buttons from previews of closed or
+// expired sessions and forgets the edit requests they led to
+func (b *Bot) runPreviewCleanup() {
+	ticker := time.NewTicker(previewCleanupInterval)
+	defer ticker.Stop()
+
+	b.cleanupStalePreviews()
+	b.cleanupStalePendingEdits()
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+			b.cleanupStalePreviews()
+			b.cleanupStalePendingEdits()
+		}
+	}
+}
+
+func (b *Bot) cleanupStalePreviews() {
+	ctx, cancel := context.WithTimeout(context.Background(), previewCleanupTimeout)
+	defer cancel()
+
+	previews, err := b.dbManager.ListStalePreviews(ctx, time.Now().Add(-previewTTL), previewCleanupBatch)
+	if err != nil {
+		log.Printf("Error listing stale previews: %v", err)
+		return
+	}
+
+	for _, preview := range previews {
+		if !b.ownsChat(preview.ChatID) {
+			continue
+		}
+		lang := b.chatLanguage(preview.ChatID)
+		note := i18n.T(lang, i18n.PreviewExpiredNote)
+		if preview.SessionClosed {
+			note = i18n.T(lang, i18n.PreviewClosedNote)
+		}
+
+		edit := tgbotapi.NewEditMessageTextAndMarkup(preview.ChatID, preview.MessageID, preview.Text+note,
+			tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+		edit.ParseMode = "Markdown"
+		edit.DisableWebPagePreview = true
+		if err := b.request(preview.ChatID, edit); err != nil {
+			// Deleted or too old messages cannot be edited; they are marked anyway so they are not retried forever
+			log.Printf("Error cleaning preview %d in chat %d: %v", preview.MessageID, preview.ChatID, err)
+		}
+
+		if err := b.dbManager.MarkPreviewCleaned(ctx, preview.ID); err != nil {
+			log.Printf("Error marking preview %d cleaned: %v", preview.ID, err)
+		}
+	}
+}
+
+func (b *Bot) cleanupStalePendingEdits() {
+	ctx, cancel := context.WithTimeout(context.Background(), previewCleanupTimeout)
+	defer cancel()
+
+	deleted, err := b.dbManager.DeleteStalePendingEdits(ctx, time.Now().Add(-previewTTL))
+	if err != nil {
+		log.Printf("Error deleting stale edit requests: %v", err)
+		return
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d stale edit requests", deleted)
+	}
+}
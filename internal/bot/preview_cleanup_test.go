@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestPreviewSessionID_ReadsConfirmButton(t *testing.T) {
+	msg := tgbotapi.NewMessage(1, "draft")
+	msg.ReplyMarkup = commands.CreateInlineKeyboard(42, i18n.Russian)
+
+	sessionID, ok := previewSessionID(&msg)
+	if !ok || sessionID != 42 {
+		t.Fatalf("expected session 42, got %d (ok=%v)", sessionID, ok)
+	}
+}
+
+func TestPreviewSessionID_IgnoresOtherMessages(t *testing.T) {
+	msg := tgbotapi.NewMessage(1, "plain")
+	if _, ok := previewSessionID(&msg); ok {
+		t.Fatal("expected plain message not to be a preview")
+	}
+
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Другое", "other:1"),
+	))
+	if _, ok := previewSessionID(&msg); ok {
+		t.Fatal("expected keyboard without confirm button not to be a preview")
+	}
+}
+
+func TestPreviewSessionID_ReadsDraftAlternatives(t *testing.T) {
+	msg := tgbotapi.NewMessage(1, "alternatives")
+	msg.ReplyMarkup = commands.DraftAlternativesKeyboard(42, 3, i18n.Default)
+
+	sessionID, ok := previewSessionID(&msg)
+	if !ok || sessionID != 42 {
+		t.Fatalf("expected session 42, got %d (ok=%v)", sessionID, ok)
+	}
+}
@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// maxMentionedAdmins caps how many chat admins a privacy warning mentions
+const maxMentionedAdmins = 5
+
+// commandFor returns the lowercased command of message, e.g. "create_task"
+// for "/Create_Task@JiraFBot". Commands addressed to another bot with
+// @othername are not ours and ok is false.
+func commandFor(message *tgbotapi.Message, botUsername string) (string, bool) {
+	name, addressee, addressed := strings.Cut(message.CommandWithAt(), "@")
+	if addressed && botUsername != "" && !strings.EqualFold(addressee, botUsername) {
+		return "", false
+	}
+	return strings.ToLower(name), true
+}
+
+// isGroupChat reports whether plain messages in chat are subject to privacy mode
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// missesGroupMessages reports whether Telegram withholds plain group messages
+// from the bot: privacy mode is on in BotFather and the bot is no chat admin
+func missesGroupMessages(self tgbotapi.User, member tgbotapi.ChatMember) bool {
+	if self.CanReadAllGroupMessages {
+		return false
+	}
+	return !member.IsAdministrator() && !member.IsCreator()
+}
+
+// privacyWarning explains to the chat admins why discussions stay empty
+func privacyWarning(lang i18n.Lang, admins []tgbotapi.ChatMember) string {
+	var mentions []string
+	for _, admin := range admins {
+		if admin.User == nil || admin.User.IsBot || admin.User.UserName == "" {
+			continue
+		}
+		mentions = append(mentions, "@"+admin.User.UserName)
+		if len(mentions) == maxMentionedAdmins {
+			break
+		}
+	}
+
+	addressee := i18n.T(lang, i18n.PrivacyAdmins)
+	if len(mentions) > 0 {
+		addressee = fmt.Sprintf("%s (%s)", addressee, strings.Join(mentions, ", "))
+	}
+	return fmt.Sprintf(i18n.T(lang, i18n.PrivacyWarning), addressee)
+}
+
+// checkGroupPrivacy warns the admins of a group once if the bot cannot read
+// its messages. It is called when the bot joins a group; discussions are
+// checked again by preflightMiddleware.
+func (b *Bot) checkGroupPrivacy(chat *tgbotapi.Chat) {
+	if !isGroupChat(chat) || b.api.Self.CanReadAllGroupMessages {
+		return
+	}
+
+	b.privacyMutex.Lock()
+	_, warned := b.privacyWarned[chat.ID]
+	b.privacyMutex.Unlock()
+	if warned {
+		return
+	}
+
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chat.ID, UserID: b.api.Self.ID},
+	})
+	if err != nil {
+		log.Printf("Error checking bot rights in chat %d: %v", chat.ID, err)
+		return
+	}
+	if !missesGroupMessages(b.api.Self, member) {
+		return
+	}
+
+	admins, err := b.api.GetChatAdministrators(tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: chat.ID},
+	})
+	if err != nil {
+		log.Printf("Error getting admins of chat %d: %v", chat.ID, err)
+	}
+
+	b.privacyMutex.Lock()
+	b.privacyWarned[chat.ID] = struct{}{}
+	b.privacyMutex.Unlock()
+
+	log.Printf("Privacy mode hides group messages in chat %d, warning admins", chat.ID)
+	b.sendMessage(chat.ID, nil, privacyWarning(b.chatLanguage(chat.ID), admins))
+}
+
+// forgetPrivacyWarning lets the chat be checked again, e.g. after the bot was made admin
+func (b *Bot) forgetPrivacyWarning(chatID int64) {
+	b.privacyMutex.Lock()
+	delete(b.privacyWarned, chatID)
+	b.privacyMutex.Unlock()
+}
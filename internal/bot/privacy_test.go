@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func commandMessage(text string) *tgbotapi.Message {
+	command, _, _ := strings.Cut(text, " ")
+	return &tgbotapi.Message{
+		Text:     text,
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(command)}},
+	}
+}
+
+func TestCommandFor(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantName string
+		wantOurs bool
+	}{
+		{text: "/create_task", wantName: "create_task", wantOurs: true},
+		{text: "/create_task@JiraFBot", wantName: "create_task", wantOurs: true},
+		{text: "/Create_Task@jirafbot some args", wantName: "create_task", wantOurs: true},
+		{text: "/create_task@OtherBot", wantOurs: false},
+	}
+
+	for _, tt := range tests {
+		name, ours := commandFor(commandMessage(tt.text), "JiraFBot")
+		if ours != tt.wantOurs || name != tt.wantName {
+			t.Errorf("commandFor(%q) = %q, %v; want %q, %v", tt.text, name, ours, tt.wantName, tt.wantOurs)
+		}
+	}
+}
+
+func TestMissesGroupMessages(t *testing.T) {
+	member := tgbotapi.ChatMember{Status: "member"}
+	admin := tgbotapi.ChatMember{Status: "administrator"}
+
+	if !missesGroupMessages(tgbotapi.User{}, member) {
+		t.Error("expected privacy mode to hide messages from a plain member")
+	}
+	if missesGroupMessages(tgbotapi.User{}, admin) {
+		t.Error("expected admins to see all messages")
+	}
+	if missesGroupMessages(tgbotapi.User{CanReadAllGroupMessages: true}, member) {
+		t.Error("expected bots without privacy mode to see all messages")
+	}
+}
+
+func TestPrivacyWarning_MentionsHumanAdmins(t *testing.T) {
+	warning := privacyWarning(i18n.Russian, []tgbotapi.ChatMember{
+		{User: &tgbotapi.User{UserName: "owner"}, Status: "creator"},
+		{User: &tgbotapi.User{UserName: "helper_bot", IsBot: true}, Status: "administrator"},
+		{User: &tgbotapi.User{FirstName: "NoUsername"}, Status: "administrator"},
+	})
+
+	if !strings.Contains(warning, "(@owner)") {
+		t.Errorf("expected owner to be mentioned: %s", warning)
+	}
+	if strings.Contains(warning, "helper_bot") {
+		t.Errorf("bots must not be mentioned: %s", warning)
+	}
+	if !strings.Contains(warning, "/setprivacy") {
+		t.Errorf("expected BotFather instructions: %s", warning)
+	}
+}
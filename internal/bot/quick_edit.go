@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quickedit"
+)
+
+// chatNow is the current time in the chat's time zone, which relative due
+// dates are resolved in
+func (b *Bot) chatNow(chatID int64) time.Time {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return time.Now().In(commands.ChatLocation(ctx, b.dbManager, chatID))
+}
+
+// chatLanguage is the language of the chat's drafts and their buttons
+func (b *Bot) chatLanguage(chatID int64) i18n.Lang {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return commands.ChatLanguage(ctx, b.dbManager, chatID)
+}
+
+// replyLanguage is the language of replies to the sender of message
+func (b *Bot) replyLanguage(message *tgbotapi.Message) i18n.Lang {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return commands.ReplyLanguage(ctx, b.dbManager, message)
+}
+
+// applyQuickEdit updates the draft directly when the reply is a simple field
+// edit ("срок пятница", "приоритет высокий"). It returns false when the reply
+// needs the AI edit prompt.
+func (b *Bot) applyQuickEdit(message *tgbotapi.Message, sessionID string) bool {
+	edit, ok := quickedit.Parse(message.Text, b.chatNow(message.Chat.ID))
+	if !ok {
+		return false
+	}
+
+	ctx := context.Background()
+	sessionIDInt, _ := strconv.Atoi(sessionID)
+	draftTask, err := b.dbManager.GetDraftTask(ctx, sessionIDInt)
+	if err != nil {
+		// Let the regular edit path report the problem
+		log.Printf("Error retrieving draft for quick edit of session %s: %v", sessionID, err)
+		return false
+	}
+
+	task, assignee, err := b.saveQuickEdit(ctx, message.Chat.ID, draftTask, edit, message.Text)
+	if err != nil {
+		log.Printf("Error saving quick edit for session %s: %v", sessionID, err)
+		b.sendMessage(message.Chat.ID, message, i18n.T(b.replyLanguage(message), i18n.EditSaveFailed))
+		return true
+	}
+
+	log.Printf("Applied quick edit to session %s without AI", sessionID)
+	b.sendUpdatedDraft(message.Chat.ID, message, sessionIDInt, task, assignee)
+	return true
+}
+
+// handleQuickEditCallback applies a priority or due button of a draft preview
+// and redraws the preview in place; the router admits only the session owner
+func (b *Bot) handleQuickEditCallback(c *commands.CallbackContext) {
+	ctx, cancel := context.WithTimeout(context.Background(), draftOptionTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+	sessionID := c.Data.SessionID()
+
+	edit, instruction, ok := commands.QuickEditFromButton(c.Data, b.chatNow(chatID), c.Lang())
+	if !ok {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+	if created, err := b.dbManager.GetCreatedTask(ctx, sessionID); err == nil && created != nil {
+		c.Answer(c.T(i18n.CallbackAlreadyCreated))
+		c.ClearButtons()
+		return
+	}
+	draftTask, err := b.dbManager.GetDraftTask(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error retrieving draft for quick edit of session %d: %v", sessionID, err)
+		c.Answer(c.T(i18n.QuickEditNoDraft))
+		return
+	}
+	if !quickEditChanges(draftTask, edit) {
+		c.Answer(c.T(i18n.QuickEditUnchanged))
+		return
+	}
+
+	task, assignee, err := b.saveQuickEdit(ctx, chatID, draftTask, edit, instruction)
+	if err != nil {
+		log.Printf("Error saving quick edit for session %d: %v", sessionID, err)
+		c.Answer(c.T(i18n.QuickEditSaveFailed))
+		return
+	}
+	c.Answer(fmt.Sprintf(c.T(i18n.QuickEditApplied), instruction))
+	log.Printf("Applied quick edit button to session %d without AI", sessionID)
+
+	lang := b.chatLanguage(chatID)
+	text := b.draftPreviewText(chatID, lang, i18n.T(lang, i18n.PreviewUpdated), task, assignee)
+	redraw := tgbotapi.NewEditMessageTextAndMarkup(chatID, c.MessageID(), text, commands.EditedDraftKeyboard(sessionID, lang))
+	redraw.ParseMode = "Markdown"
+	redraw.DisableWebPagePreview = true
+	if err := b.request(chatID, redraw); err != nil {
+		log.Printf("Error redrawing draft preview of session %d: %v", sessionID, err)
+	}
+}
+
+// quickEditChanges reports whether the edit changes the priority or due date of the draft
+func quickEditChanges(draftTask db.DraftTask, edit quickedit.Edit) bool {
+	if edit.Priority != nil && int(draftTask.Priority.Int32) != *edit.Priority {
+		return true
+	}
+	return edit.DueDate != nil && draftTask.DueISO.String != *edit.DueDate
+}
+
+// saveQuickEdit applies the edit to the draft, saves it and records it in the
+// draft history under instruction. Added labels are matched against the
+// chat's tracker like the labels the AI suggests.
+func (b *Bot) saveQuickEdit(ctx context.Context, chatID int64, draftTask db.DraftTask, edit quickedit.Edit, instruction string) (*ai.AnalyzedTask, db.AssigneeSnapshot, error) {
+	task := commands.DraftToAnalyzedTask(draftTask)
+	edit.Apply(task)
+	if len(edit.AddLabels) > 0 && b.trackers != nil {
+		if client, err := b.trackers.ForChat(chatID); err == nil {
+			task.Labels = commands.ResolveLabels(ctx, client, task.Labels, b.createMissingLabels)
+		}
+	}
+
+	assignee := db.AssigneeSnapshot{
+		TodoistID:   draftTask.AssigneeTodoistID.String,
+		Name:        draftTask.AssigneeName.String,
+		Email:       draftTask.AssigneeEmail.String,
+		MatchSource: draftTask.AssigneeMatchSource.String,
+	}
+	draft := db.DraftTaskInput{
+		SessionID:      draftTask.SessionID,
+		Title:          task.Title,
+		Description:    task.Description,
+		DueISO:         task.DueDate,
+		Priority:       task.Priority,
+		TaskType:       task.TaskType,
+		Labels:         task.Labels,
+		MissingDetails: task.MissingDetails,
+		SelectedLinks:  task.SelectedLinks,
+		AssigneeNote:   task.AssigneeNote,
+		Assignee:       assignee,
+		Fields:         task.TaskFields,
+	}
+	if err := b.dbManager.SaveDraftTask(ctx, draft); err != nil {
+		return nil, db.AssigneeSnapshot{}, err
+	}
+	commands.RecordDraftRevision(ctx, b.dbManager, draft, instruction)
+	return task, assignee, nil
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+	"github.com/user/telegram-bot/internal/quiethours"
+)
+
+const (
+	deferredDeliveryInterval = time.Minute
+	deferredDeliveryTimeout  = time.Minute
+	// digestWindow is how long after the digest time scheduled messages are still delivered, in minutes
+	digestWindow = 60
+)
+
+// sendNotice sends a non-urgent message, or defers it to the morning summary
+// while the chat's quiet hours are on. Messages that expect an answer, like
+// draft previews, are never deferred.
+func (b *Bot) sendNotice(chatID int64, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), deferredDeliveryTimeout)
+	defer cancel()
+
+	if b.inQuietHours(ctx, chatID, time.Now()) {
+		err := b.dbManager.DeferMessage(ctx, chatID, text)
+		if err == nil {
+			return
+		}
+		log.Printf("Error deferring message for chat %d, sending now: %v", chatID, err)
+	}
+	b.sendMessage(chatID, nil, text)
+}
+
+func (b *Bot) inQuietHours(ctx context.Context, chatID int64, now time.Time) bool {
+	value, err := b.dbManager.GetQuietHours(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting quiet hours for chat %d: %v", chatID, err)
+		return false
+	}
+	if value == "" {
+		return false
+	}
+	window, err := quiethours.Parse(value)
+	if err != nil {
+		log.Printf("Ignoring invalid quiet hours %q for chat %d: %v", value, chatID, err)
+		return false
+	}
+	return window.Contains(now.In(commands.ChatLocation(ctx, b.dbManager, chatID)))
+}
+
+// runDeferredDelivery sends the summary of deferred messages once quiet hours end
+func (b *Bot) runDeferredDelivery() {
+	ticker := time.NewTicker(deferredDeliveryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case <-ticker.C:
+			b.deliverDeferredMessages()
+		}
+	}
+}
+
+func (b *Bot) deliverDeferredMessages() {
+	ctx, cancel := context.WithTimeout(context.Background(), deferredDeliveryTimeout)
+	defer cancel()
+
+	chatIDs, err := b.dbManager.ListDeferredChats(ctx)
+	if err != nil {
+		log.Printf("Error listing chats with deferred messages: %v", err)
+		return
+	}
+
+	now := time.Now()
+	for _, chatID := range chatIDs {
+		if !b.ownsChat(chatID) || b.inQuietHours(ctx, chatID, now) {
+			continue
+		}
+
+		texts, err := b.dbManager.TakeDeferredMessages(ctx, chatID)
+		if err != nil {
+			log.Printf("Error taking deferred messages for chat %d: %v", chatID, err)
+			continue
+		}
+		if len(texts) > 0 {
+			chatID, summary := chatID, deferredSummary(b.chatLanguage(chatID), texts)
+			b.submitBackgroundJob("digest", chatID, jobs.PriorityLow, func(context.Context) error {
+				_, err := b.send(tgbotapi.NewMessage(chatID, summary))
+				return err
+			})
+		}
+	}
+}
+
+// deferredSummary joins deferred messages into one message
+func deferredSummary(lang i18n.Lang, texts []string) string {
+	header := i18n.T(lang, i18n.QuietHoursDeferred)
+	if len(texts) == 1 {
+		return header + "\n\n" + texts[0]
+	}
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, text := range texts {
+		sb.WriteString("\n• ")
+		sb.WriteString(strings.ReplaceAll(text, "\n", "\n  "))
+	}
+	return sb.String()
+}
+
+// atDigestTime reports whether scheduled messages like nudges may reach the
+// chat now: any time, or within the hour after the chat's digest time
+func (b *Bot) atDigestTime(ctx context.Context, chatID int64, now time.Time) bool {
+	value, err := b.dbManager.GetDigestTime(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting digest time for chat %d: %v", chatID, err)
+		return true
+	}
+	if value == "" {
+		return true
+	}
+	start, err := quiethours.ParseClock(value)
+	if err != nil {
+		log.Printf("Ignoring invalid digest time %q for chat %d: %v", value, chatID, err)
+		return true
+	}
+	return quiethours.Window{Start: start, End: (start + digestWindow) % (24 * 60)}.Contains(now.In(commands.ChatLocation(ctx, b.dbManager, chatID)))
+}
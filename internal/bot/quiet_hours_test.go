@@ -0,0 +1,20 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestDeferredSummary(t *testing.T) {
+	single := deferredSummary(i18n.Russian, []string{"✅ Задача успешно создана"})
+	if !strings.HasSuffix(single, "\n\n✅ Задача успешно создана") {
+		t.Fatalf("unexpected single summary: %q", single)
+	}
+
+	summary := deferredSummary(i18n.Russian, []string{"первое", "второе\nв две строки"})
+	if !strings.Contains(summary, "\n• первое") || !strings.Contains(summary, "\n• второе\n  в две строки") {
+		t.Fatalf("unexpected summary: %q", summary)
+	}
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// EnvAckReaction overrides the emoji used to acknowledge captured messages.
+// Telegram accepts only emojis from its reaction list.
+const EnvAckReaction = "ACK_REACTION_EMOJI"
+
+const defaultAckReaction = "👀"
+
+// acknowledgeCaptured reacts to a message saved into the discussion when the
+// chat enabled reaction acknowledgements
+func (b *Bot) acknowledgeCaptured(ctx context.Context, message *tgbotapi.Message) {
+	enabled, err := b.dbManager.ReactionAckEnabled(ctx, message.Chat.ID)
+	if err != nil {
+		log.Printf("Error checking reaction acknowledgements for chat %d: %v", message.Chat.ID, err)
+		return
+	}
+	if !enabled {
+		return
+	}
+
+	go b.setReaction(message.Chat.ID, message.MessageID, ackReaction())
+}
+
+// setReaction calls setMessageReaction directly, the library predates it
+func (b *Bot) setReaction(chatID int64, messageID int, emoji string) {
+	reaction, err := json.Marshal([]map[string]string{{"type": "emoji", "emoji": emoji}})
+	if err != nil {
+		log.Printf("Error encoding reaction: %v", err)
+		return
+	}
+
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params.AddNonZero("message_id", messageID)
+	params["reaction"] = string(reaction)
+
+	if _, err := b.api.MakeRequest("setMessageReaction", params); err != nil {
+		log.Printf("Error reacting to message %d in chat %d: %v", messageID, chatID, err)
+	}
+}
+
+func ackReaction() string {
+	if emoji := os.Getenv(EnvAckReaction); emoji != "" {
+		return emoji
+	}
+	return defaultAckReaction
+}
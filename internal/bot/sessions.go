@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// sessionFor returns the discussion a chat message is captured into, nil when
+// the topic has none. With several open discussions the sender chooses one by
+// replying to a message of it or with its #tag.
+func (b *Bot) sessionFor(ctx context.Context, message *tgbotapi.Message) (*db.Session, error) {
+	sessions, err := b.dbManager.ListActiveSessions(ctx, message.Chat.ID, topics.ThreadID(message))
+	if err != nil || len(sessions) == 0 {
+		return nil, err
+	}
+
+	var replySessionID int
+	if len(sessions) > 1 && message.ReplyToMessage != nil {
+		replySessionID, err = b.dbManager.GetMessageSession(ctx, message.Chat.ID, message.ReplyToMessage.MessageID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pickSession(sessions, replySessionID, commands.SessionTags(messageText(message))), nil
+}
+
+// pickSession chooses among the open sessions of a topic, oldest first: the
+// session of the replied message, then the one named by the first known tag,
+// then the unnamed session, then the latest one
+func pickSession(sessions []db.Session, replySessionID int, tags []string) *db.Session {
+	if len(sessions) == 0 {
+		return nil
+	}
+	for i := range sessions {
+		if replySessionID != 0 && sessions[i].ID == replySessionID {
+			return &sessions[i]
+		}
+	}
+	for _, tag := range tags {
+		for i := range sessions {
+			if sessions[i].Name == tag {
+				return &sessions[i]
+			}
+		}
+	}
+	for i := range sessions {
+		if sessions[i].Name == "" {
+			return &sessions[i]
+		}
+	}
+	return &sessions[len(sessions)-1]
+}
+
+// messageText returns the text or the caption of a message
+func messageText(message *tgbotapi.Message) string {
+	if message.Text != "" {
+		return message.Text
+	}
+	return message.Caption
+}
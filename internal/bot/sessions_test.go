@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func TestPickSession(t *testing.T) {
+	sessions := []db.Session{
+		{ID: 1, Name: "billing-bug"},
+		{ID: 2, Name: ""},
+		{ID: 3, Name: "release"},
+	}
+
+	cases := []struct {
+		name    string
+		replyID int
+		tags    []string
+		want    int
+	}{
+		{"reply wins over tags", 3, []string{"billing-bug"}, 3},
+		{"first known tag", 0, []string{"unknown", "billing-bug"}, 1},
+		{"reply to a closed session", 9, nil, 2},
+		{"unnamed by default", 0, nil, 2},
+	}
+	for _, tc := range cases {
+		if got := pickSession(sessions, tc.replyID, tc.tags); got.ID != tc.want {
+			t.Errorf("%s: expected session %d, got %d", tc.name, tc.want, got.ID)
+		}
+	}
+
+	named := []db.Session{{ID: 1, Name: "billing-bug"}, {ID: 3, Name: "release"}}
+	if got := pickSession(named, 0, nil); got.ID != 3 {
+		t.Errorf("expected the latest session without an unnamed one, got %d", got.ID)
+	}
+	if got := pickSession(nil, 0, nil); got != nil {
+		t.Errorf("expected no session, got %d", got.ID)
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+const splitTimeout = time.Minute
+
+// handleSplitCallback toggles, creates or drops the subtasks of a /split
+// preview; the router admits only the session owner
+func (b *Bot) handleSplitCallback(c *commands.CallbackContext) {
+	chatID := c.ChatID()
+	sessionID := c.Data.SessionID()
+
+	if c.Data.Action == commands.CallbackSplitToggle {
+		i, err := strconv.Atoi(c.Data.Arg(1))
+		if err != nil {
+			c.Answer(c.T(i18n.CallbackStale))
+			return
+		}
+		proposal, ok := b.splitProposals.Toggle(sessionID, i)
+		if !ok {
+			c.Answer(c.T(i18n.SplitGone))
+			return
+		}
+		c.Answer("")
+		edit := tgbotapi.NewEditMessageReplyMarkup(chatID, c.MessageID(), commands.SplitKeyboard(&proposal, c.Lang()))
+		if err := b.request(chatID, edit); err != nil {
+			log.Printf("Error updating split keyboard in chat %d: %v", chatID, err)
+		}
+		return
+	}
+
+	proposal, ok := b.splitProposals.Take(sessionID)
+	if !ok {
+		c.Answer(c.T(i18n.SplitGone))
+		return
+	}
+
+	if c.Data.Action == commands.CallbackSplitCancel {
+		c.Answer("")
+		c.ClearButtons()
+		b.sendMessage(chatID, c.Query.Message, c.T(i18n.SplitCancelled))
+		return
+	}
+
+	if len(proposal.SelectedSubtasks()) == 0 {
+		// Keep the preview so the owner can pick subtasks or cancel
+		b.splitProposals.Put(proposal)
+		c.Answer(c.T(i18n.SplitNothingChecked))
+		return
+	}
+	c.Answer("")
+	c.ClearButtons()
+
+	go b.runSplit(chatID, c.Query.Message, actorName(c.Query.From), proposal, c.Lang())
+}
+
+func (b *Bot) runSplit(chatID int64, trigger *tgbotapi.Message, actor string, proposal *commands.SplitProposal, lang i18n.Lang) {
+	creator, ok := b.todoistClient.(todoist.SubtaskCreator)
+	if !ok {
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.SplitUnsupported))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), splitTimeout)
+	defer cancel()
+
+	parent, subtasks := commands.SplitTaskRequests(proposal)
+	results, err := creator.CreateTaskWithSubtasks(ctx, parent, subtasks)
+	if err != nil {
+		log.Printf("Error creating split of session %d in chat %d: %v", proposal.SessionID, chatID, err)
+		b.sendMessage(chatID, trigger, i18n.T(lang, i18n.SplitCreateRetry))
+		return
+	}
+	b.sendMessage(chatID, trigger, commands.FormatSplitReport(proposal, results, lang))
+	if len(results) == 0 || results[0].Err != nil {
+		return
+	}
+
+	sessionID := proposal.SessionID
+	if err := b.dbManager.CloseSession(ctx, sessionID); err != nil {
+		log.Printf("Error closing session %d after split: %v", sessionID, err)
+	}
+	b.recordFeature("task_created", chatID)
+	b.notifyTaskCreated(notify.TaskEvent{
+		ChatID:    chatID,
+		ChatTitle: trigger.Chat.Title,
+		SessionID: sessionID,
+		Actor:     actor,
+		Task:      notify.Task{ID: results[0].ID, Title: parent.Content, URL: todoist.TaskURL(results[0].ID)},
+	})
+	b.releaseDiscussionNotice(chatID, sessionID, notify.ReasonTaskCreated)
+	b.notifySessionClosed(notify.SessionEvent{
+		ChatID:    chatID,
+		ChatTitle: trigger.Chat.Title,
+		SessionID: sessionID,
+		Actor:     actor,
+		Reason:    notify.ReasonTaskCreated,
+	})
+}
@@ -0,0 +1,74 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/msgsplit"
+	"github.com/user/telegram-bot/internal/taskcard"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const taskCardTimeout = 15 * time.Second
+
+// SetTaskCards sends created tasks as image cards instead of plain messages
+func (b *Bot) SetTaskCards(renderer *taskcard.Renderer) {
+	b.taskCards = renderer
+}
+
+// sendTaskCard sends msg as the caption of a card for task, falling back to
+// the plain message when the card cannot be built or sent
+func (b *Bot) sendTaskCard(msg *tgbotapi.MessageConfig, task *tracker.Task) {
+	caption := fmt.Sprintf("%s\n🚩 P%d", msg.Text, todoistPriorityLevel(task.Priority))
+	if msgsplit.Length(caption) > taskcard.MaxCaptionLength {
+		b.sendResponse(msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskCardTimeout)
+	defer cancel()
+
+	header, err := b.taskCards.Render(taskcard.Header{
+		ProjectColor: b.projectColor(ctx, task.ProjectID),
+		Priority:     task.Priority,
+	})
+	if err != nil {
+		log.Printf("Error rendering task card for %s: %v", task.ID, err)
+		b.sendResponse(msg)
+		return
+	}
+
+	photo := tgbotapi.NewPhoto(msg.ChatID, tgbotapi.FileBytes{Name: "task.png", Bytes: header})
+	photo.Caption = caption
+	photo.ParseMode = msg.ParseMode
+	if err := b.request(msg.ChatID, photo); err != nil {
+		log.Printf("Error sending task card for %s: %v", task.ID, err)
+		b.sendResponse(msg)
+	}
+}
+
+// projectColor returns the Todoist color name of the project, empty if unknown
+func (b *Bot) projectColor(ctx context.Context, projectID string) string {
+	projects, err := b.todoistClient.GetProjects(ctx)
+	if err != nil {
+		log.Printf("Error getting projects for task card: %v", err)
+		return ""
+	}
+	for _, project := range projects {
+		if project.ID == projectID {
+			return project.Color
+		}
+	}
+	return ""
+}
+
+// todoistPriorityLevel converts API priority (4 is urgent) to the P1–P4 shown in Todoist apps
+func todoistPriorityLevel(priority int) int {
+	if priority < 1 || priority > 4 {
+		return 4
+	}
+	return 5 - priority
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+const taskViewDoneTimeout = 15 * time.Second
+
+// handleTaskViewDoneCallback completes a task listed by /today, /upcoming or
+// /overdue and shows the view again without it
+func (b *Bot) handleTaskViewDoneCallback(c *commands.CallbackContext) {
+	command, ok := b.commandRegistry.Get(c.Data.Arg(0))
+	view, isView := command.(*commands.TaskViewCommand)
+	taskID := c.Data.Arg(1)
+	if !ok || !isView || taskID == "" {
+		c.Answer(c.T(i18n.CallbackStale))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), taskViewDoneTimeout)
+	defer cancel()
+	chatID := c.ChatID()
+
+	// Only the tasks of the chat's project can be completed from the chat
+	projectID, err := b.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting project of chat %d: %v", chatID, err)
+	}
+	task, err := b.todoistClient.GetTask(ctx, taskID)
+	if err != nil || projectID == "" || task.ProjectID != projectID {
+		c.Answer(c.T(i18n.TaskNotFound))
+		return
+	}
+	if !task.IsCompleted {
+		if err := b.todoistClient.CompleteTask(ctx, taskID); err != nil {
+			log.Printf("Error completing task %s from a view in chat %d: %v", taskID, chatID, err)
+			c.Answer(c.T(i18n.TaskCompleteFailed))
+			return
+		}
+		b.notifyTaskCompleted(notify.TaskEvent{
+			ChatID: chatID,
+			Actor:  actorName(c.Query.From),
+			Task:   notify.Task{ID: task.ID, Title: task.Content, URL: task.URL},
+		})
+	}
+	c.Answer(fmt.Sprintf(c.T(i18n.TaskCompleted), task.Content))
+
+	text, keyboard := view.Render(ctx, chatID)
+	edit := tgbotapi.NewEditMessageText(chatID, c.MessageID(), text)
+	edit.ParseMode = tgbotapi.ModeMarkdown
+	edit.DisableWebPagePreview = true
+	edit.ReplyMarkup = keyboard
+	if err := b.request(chatID, edit); err != nil {
+		log.Printf("Error refreshing /%s in chat %d: %v", view.Name(), chatID, err)
+	}
+}
@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	// EnvTelegramEnv selects the Telegram data center: "production" (default) or "test"
+	EnvTelegramEnv = "TELEGRAM_ENV"
+	// EnvTelegramAPIEndpoint overrides the Bot API endpoint, e.g. for a local Bot API server.
+	// It uses the tgbotapi format with two %s verbs, token and method, and a
+	// bot%s path segment files are downloaded next to.
+	EnvTelegramAPIEndpoint = "TELEGRAM_API_ENDPOINT"
+
+	// testAPIEndpoint is the Bot API endpoint of Telegram's test environment.
+	// Test DC bots and accounts are separate from production ones.
+	testAPIEndpoint = "https://api.telegram.org/bot%s/test/%s"
+)
+
+// apiEndpointFromEnv resolves the Bot API endpoint from TELEGRAM_ENV and TELEGRAM_API_ENDPOINT.
+func apiEndpointFromEnv() (string, error) {
+	if endpoint := os.Getenv(EnvTelegramAPIEndpoint); endpoint != "" {
+		if strings.Count(endpoint, "%s") != 2 || !strings.Contains(endpoint, "bot%s") {
+			return "", fmt.Errorf("%s must contain two %%s placeholders (bot%%s for the token and one for the method), got %q", EnvTelegramAPIEndpoint, endpoint)
+		}
+		return endpoint, nil
+	}
+
+	switch env := strings.ToLower(os.Getenv(EnvTelegramEnv)); env {
+	case "", "production", "prod":
+		return tgbotapi.APIEndpoint, nil
+	case "test":
+		return testAPIEndpoint, nil
+	default:
+		return "", fmt.Errorf("unknown %s %q: use production or test", EnvTelegramEnv, env)
+	}
+}
+
+// fileEndpointFor returns the file download endpoint served next to a Bot API
+// endpoint: /bot<token>/ becomes /file/bot<token>/, so the test environment
+// keeps its /test/ segment. tgbotapi.GetFileDirectURL always uses production.
+func fileEndpointFor(apiEndpoint string) string {
+	return strings.Replace(apiEndpoint, "bot%s", "file/bot%s", 1)
+}
+
+// fileURL resolves the download URL of a file sent to the bot
+func (b *Bot) fileURL(fileID string) (string, error) {
+	file, err := b.api.GetFile(tgbotapi.FileConfig{FileID: fileID})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(b.fileEndpoint, b.api.Token, file.FilePath), nil
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestFileEndpointFor(t *testing.T) {
+	tests := []struct {
+		api  string
+		want string
+	}{
+		{tgbotapi.APIEndpoint, tgbotapi.FileEndpoint},
+		{testAPIEndpoint, "https://api.telegram.org/file/bot%s/test/%s"},
+		{"http://localhost:8081/bot%s/%s", "http://localhost:8081/file/bot%s/%s"},
+	}
+	for _, tt := range tests {
+		if got := fileEndpointFor(tt.api); got != tt.want {
+			t.Errorf("fileEndpointFor(%q) = %q, want %q", tt.api, got, tt.want)
+		}
+	}
+}
+
+func TestAPIEndpointFromEnv_RequiresBotSegment(t *testing.T) {
+	t.Setenv(EnvTelegramAPIEndpoint, "http://localhost:8081/%s/%s")
+	if _, err := apiEndpointFromEnv(); err == nil {
+		t.Fatal("expected an endpoint without a bot segment to be rejected")
+	}
+}
+
+func TestFileURL_UsesTestEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/botTOKEN/test/getFile") {
+			t.Errorf("unexpected request path %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"ok":true,"result":{"file_id":"f1","file_path":"documents/file_1.csv"}}`))
+	}))
+	defer server.Close()
+
+	t.Setenv(EnvTelegramEnv, "test")
+	endpoint, err := apiEndpointFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	endpoint = strings.Replace(endpoint, "https://api.telegram.org", server.URL, 1)
+
+	api := &tgbotapi.BotAPI{Token: "TOKEN", Client: server.Client()}
+	api.SetAPIEndpoint(endpoint)
+	b := &Bot{api: api, fileEndpoint: fileEndpointFor(endpoint)}
+
+	got, err := b.fileURL("f1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := server.URL + "/file/botTOKEN/test/documents/file_1.csv"; got != want {
+		t.Fatalf("fileURL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,215 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/msgsplit"
+)
+
+// errChatInactive is returned for chats where the bot was blocked or removed
+var errChatInactive = errors.New("chat is inactive")
+
+// sendFailure is a Telegram error that needs a corrective action
+type sendFailure int
+
+const (
+	failureOther sendFailure = iota
+	// failureChatGone: the bot was blocked, kicked or the chat was deleted
+	failureChatGone
+	// failureMigrated: the group was upgraded to a supergroup with a new ID
+	failureMigrated
+	// failureMessageGone: the message to edit or delete no longer exists or is unchanged
+	failureMessageGone
+	// failureTooLong: the text is over msgsplit.MaxLength
+	failureTooLong
+)
+
+// classifySendError maps a Bot API error to the action it needs
+func classifySendError(err error) (sendFailure, int64) {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return failureOther, 0
+	}
+	if apiErr.MigrateToChatID != 0 {
+		return failureMigrated, apiErr.MigrateToChatID
+	}
+
+	message := strings.ToLower(apiErr.Message)
+	switch {
+	case apiErr.Code == 403 && (strings.Contains(message, "blocked") ||
+		strings.Contains(message, "kicked") ||
+		strings.Contains(message, "deactivated") ||
+		strings.Contains(message, "not a member")),
+		strings.Contains(message, "chat not found"):
+		return failureChatGone, 0
+	case strings.Contains(message, "message to edit not found"),
+		strings.Contains(message, "message to delete not found"),
+		strings.Contains(message, "message can't be edited"),
+		strings.Contains(message, "message can't be deleted"),
+		strings.Contains(message, "message is not modified"):
+		return failureMessageGone, 0
+	case strings.Contains(message, "message is too long"):
+		return failureTooLong, 0
+	}
+	return failureOther, 0
+}
+
+// send delivers a text message, handling errors Telegram reports about the chat
+func (b *Bot) send(msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	if b.isChatInactive(msg.ChatID) {
+		return tgbotapi.Message{}, errChatInactive
+	}
+	if msgsplit.Length(msg.Text) > msgsplit.MaxLength {
+		return b.sendParts(msg)
+	}
+
+	sent, err := b.api.Send(msg)
+	if err == nil {
+		return sent, nil
+	}
+
+	failure, newChatID := classifySendError(err)
+	switch failure {
+	case failureChatGone:
+		b.markChatInactive(msg.ChatID, err)
+	case failureMigrated:
+		b.migrateChat(msg.ChatID, newChatID)
+		msg.ChatID = newChatID
+		return b.api.Send(msg)
+	case failureTooLong:
+		return b.sendParts(msg)
+	}
+	return sent, err
+}
+
+// request performs a chat-scoped call like an edit, a delete or a file upload.
+// Edits of messages that are gone are not errors: there is nothing left to fix.
+func (b *Bot) request(chatID int64, c tgbotapi.Chattable) error {
+	if b.isChatInactive(chatID) {
+		return errChatInactive
+	}
+
+	_, err := b.api.Request(c)
+	if err == nil {
+		return nil
+	}
+
+	failure, newChatID := classifySendError(err)
+	switch failure {
+	case failureMessageGone:
+		return nil
+	case failureChatGone:
+		b.markChatInactive(chatID, err)
+	case failureMigrated:
+		// Message IDs do not survive the upgrade, so the call is not repeated
+		b.migrateChat(chatID, newChatID)
+	}
+	return err
+}
+
+// sendParts sends a long text as sequential parts with page indicators.
+// Each part after the first replies to the one before when the message is a
+// reply, so the parts stay together in the forum topic of the first one.
+func (b *Bot) sendParts(msg tgbotapi.MessageConfig) (tgbotapi.Message, error) {
+	parts := messageParts(msg)
+	var sent tgbotapi.Message
+	for i, part := range parts {
+		if i > 0 && part.ReplyToMessageID != 0 {
+			part.ReplyToMessageID = sent.MessageID
+		}
+		var err error
+		if sent, err = b.api.Send(part); err != nil {
+			return sent, fmt.Errorf("failed to send part %d of %d: %w", i+1, len(parts), err)
+		}
+	}
+	return sent, nil
+}
+
+// messageParts splits msg into messages that fit Telegram's limit, keeping its
+// options on every part. The keyboard goes on the last part, where the reader
+// ends up.
+func messageParts(msg tgbotapi.MessageConfig) []tgbotapi.MessageConfig {
+	texts := msgsplit.Split(msg.Text, msgsplit.MaxLength, msg.ParseMode == tgbotapi.ModeMarkdown)
+	parts := make([]tgbotapi.MessageConfig, len(texts))
+	for i, text := range texts {
+		parts[i] = msg
+		parts[i].Text = text
+		if i < len(texts)-1 {
+			parts[i].ReplyMarkup = nil
+		}
+	}
+	return parts
+}
+
+func (b *Bot) isChatInactive(chatID int64) bool {
+	b.inactiveMutex.RLock()
+	defer b.inactiveMutex.RUnlock()
+	_, ok := b.inactiveChats[chatID]
+	return ok
+}
+
+func (b *Bot) markChatInactive(chatID int64, cause error) {
+	log.Printf("Marking chat %d inactive: %v", chatID, cause)
+	b.inactiveMutex.Lock()
+	b.inactiveChats[chatID] = struct{}{}
+	b.inactiveMutex.Unlock()
+
+	// Nothing the queued AI jobs produce could be delivered
+	if b.jobQueue != nil {
+		if canceled := b.jobQueue.CancelChat(chatID); canceled > 0 {
+			log.Printf("Canceled %d AI jobs for inactive chat %d", canceled, chatID)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.dbManager.SetChatInactive(ctx, chatID, true); err != nil {
+		log.Printf("Error marking chat %d inactive: %v", chatID, err)
+	}
+}
+
+// handleMyChatMember tracks the bot being blocked or removed without waiting for a failed send
+func (b *Bot) handleMyChatMember(update *tgbotapi.ChatMemberUpdated) {
+	switch update.NewChatMember.Status {
+	case "kicked", "left":
+		b.markChatInactive(update.Chat.ID, fmt.Errorf("bot status changed to %s", update.NewChatMember.Status))
+	case "member":
+		// Added to a group, or demoted: privacy mode may now hide the group messages
+		b.forgetPrivacyWarning(update.Chat.ID)
+		go b.checkGroupPrivacy(&update.Chat)
+	case "administrator":
+		b.forgetPrivacyWarning(update.Chat.ID)
+	}
+}
+
+// reactivateChat is called when an update arrives from a chat marked inactive
+func (b *Bot) reactivateChat(chatID int64) {
+	if !b.isChatInactive(chatID) {
+		return
+	}
+	log.Printf("Chat %d is active again", chatID)
+	b.inactiveMutex.Lock()
+	delete(b.inactiveChats, chatID)
+	b.inactiveMutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := b.dbManager.SetChatInactive(ctx, chatID, false); err != nil {
+		log.Printf("Error reactivating chat %d: %v", chatID, err)
+	}
+}
+
+func (b *Bot) migrateChat(fromChatID, toChatID int64) {
+	log.Printf("Chat %d was upgraded to supergroup %d, migrating data", fromChatID, toChatID)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := b.dbManager.MigrateChat(ctx, fromChatID, toChatID); err != nil {
+		log.Printf("Error migrating chat %d to %d: %v", fromChatID, toChatID, err)
+	}
+}
@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/msgsplit"
+)
+
+func TestClassifySendError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		want       sendFailure
+		wantChatID int64
+	}{
+		{"not an api error", errors.New("connection reset"), failureOther, 0},
+		{"blocked", &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was blocked by the user"}, failureChatGone, 0},
+		{"kicked", &tgbotapi.Error{Code: 403, Message: "Forbidden: bot was kicked from the group chat"}, failureChatGone, 0},
+		{"chat not found", &tgbotapi.Error{Code: 400, Message: "Bad Request: chat not found"}, failureChatGone, 0},
+		{"migrated", &tgbotapi.Error{Code: 400, Message: "Bad Request: group chat was upgraded to a supergroup chat",
+			ResponseParameters: tgbotapi.ResponseParameters{MigrateToChatID: -1001}}, failureMigrated, -1001},
+		{"edit gone", &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"}, failureMessageGone, 0},
+		{"not modified", &tgbotapi.Error{Code: 400, Message: "Bad Request: message is not modified"}, failureMessageGone, 0},
+		{"too long", &tgbotapi.Error{Code: 400, Message: "Bad Request: message is too long"}, failureTooLong, 0},
+		{"wrapped", fmt.Errorf("send: %w", &tgbotapi.Error{Code: 403, Message: "Forbidden: user is deactivated"}), failureChatGone, 0},
+		{"other", &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities"}, failureOther, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, chatID := classifySendError(tt.err)
+			if got != tt.want || chatID != tt.wantChatID {
+				t.Errorf("classifySendError() = %v, %d; want %v, %d", got, chatID, tt.want, tt.wantChatID)
+			}
+		})
+	}
+}
+
+func TestMessageParts(t *testing.T) {
+	msg := tgbotapi.NewMessage(1, strings.Repeat("• *задача* с описанием\n", 600))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	msg.DisableNotification = true
+	msg.ReplyToMessageID = 7
+	msg.ReplyMarkup = commands.CreateInlineKeyboard(42, i18n.Russian)
+
+	parts := messageParts(msg)
+
+	if len(parts) < 2 {
+		t.Fatalf("expected several parts, got %d", len(parts))
+	}
+	for i, part := range parts {
+		if n := msgsplit.Length(part.Text); n > msgsplit.MaxLength {
+			t.Errorf("part %d has length %d", i+1, n)
+		}
+		if part.ParseMode != tgbotapi.ModeMarkdown || !part.DisableWebPagePreview || !part.DisableNotification {
+			t.Errorf("part %d lost the message options: %+v", i+1, part)
+		}
+		if part.ReplyToMessageID != 7 {
+			t.Errorf("part %d replies to %d, want 7", i+1, part.ReplyToMessageID)
+		}
+		if last := i == len(parts)-1; (part.ReplyMarkup != nil) != last {
+			t.Errorf("part %d has keyboard %v, want it only on the last part", i+1, part.ReplyMarkup != nil)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+const (
+	// EnvTelegramWebhookURL switches updates from long polling to a webhook: the
+	// public base URL Telegram posts updates to, TelegramWebhookPath appended
+	EnvTelegramWebhookURL = "TELEGRAM_WEBHOOK_URL"
+	// EnvTelegramWebhookSecret is the secret Telegram sends with every update;
+	// instances forwarding updates to each other send it too
+	EnvTelegramWebhookSecret = "TELEGRAM_WEBHOOK_SECRET"
+
+	telegramSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+	// shardForwardedHeader marks an update another instance passed on to this
+	// one. It is handled here even if the rings disagree during a rebalance,
+	// so an update never bounces between instances.
+	shardForwardedHeader = "X-Shard-Forwarded"
+
+	maxTelegramUpdateBytes = 1 << 20
+	webhookUpdateBuffer    = 100
+	shardForwardTimeout    = 10 * time.Second
+)
+
+var telegramWebhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// TelegramWebhookConfig describes where Telegram delivers updates in webhook mode
+type TelegramWebhookConfig struct {
+	URL    string
+	Secret string
+}
+
+// TelegramWebhookConfigFromEnv reads the webhook mode settings; without
+// TELEGRAM_WEBHOOK_URL the bot uses long polling
+func TelegramWebhookConfigFromEnv() (TelegramWebhookConfig, error) {
+	config := TelegramWebhookConfig{
+		URL:    os.Getenv(EnvTelegramWebhookURL),
+		Secret: os.Getenv(EnvTelegramWebhookSecret),
+	}
+	if config.URL == "" {
+		return config, nil
+	}
+	if u, err := url.Parse(config.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+		return TelegramWebhookConfig{}, fmt.Errorf("%s must be an https URL, got %q", EnvTelegramWebhookURL, config.URL)
+	}
+	if !telegramWebhookSecretRe.MatchString(config.Secret) {
+		return TelegramWebhookConfig{}, fmt.Errorf("%s is required with %s: 1-256 characters A-Z, a-z, 0-9, _ or -", EnvTelegramWebhookSecret, EnvTelegramWebhookURL)
+	}
+	return config, nil
+}
+
+// Enabled reports whether updates come through the webhook
+func (c TelegramWebhookConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// TelegramWebhookPath is where the updates of a bot are posted, by Telegram
+// or by another instance
+func TelegramWebhookPath(botID string) string {
+	return "/telegram/" + botID
+}
+
+// SetTelegramWebhook makes the bot receive updates at the webhook instead of
+// polling for them
+func (b *Bot) SetTelegramWebhook(config TelegramWebhookConfig, botID string) {
+	b.telegramWebhook = config
+	b.telegramWebhookPath = TelegramWebhookPath(botID)
+	b.webhookUpdates = make(chan tgbotapi.Update, webhookUpdateBuffer)
+}
+
+// registerTelegramWebhook points Telegram at the webhook. Every instance
+// registers the same URL, the load balancer in front of them.
+func (b *Bot) registerTelegramWebhook() error {
+	params := tgbotapi.Params{}
+	params["url"] = b.telegramWebhook.URL + b.telegramWebhookPath
+	params["secret_token"] = b.telegramWebhook.Secret
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("failed to set Telegram webhook: %w", err)
+	}
+	return nil
+}
+
+// serveTelegramWebhook queues an update for the bot. With chats sharded, an
+// update of a chat another instance owns is forwarded to that instance; when
+// it cannot be reached Telegram gets an error and delivers the update again.
+func (b *Bot) serveTelegramWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "updates must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !hmac.Equal([]byte(r.Header.Get(telegramSecretHeader)), []byte(b.telegramWebhook.Secret)) {
+		http.Error(w, "invalid secret token", http.StatusUnauthorized)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTelegramUpdateBytes))
+	if err != nil {
+		http.Error(w, "unreadable body", http.StatusBadRequest)
+		return
+	}
+	var update tgbotapi.Update
+	if err := json.Unmarshal(body, &update); err != nil {
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	if b.chatRouter != nil && r.Header.Get(shardForwardedHeader) == "" {
+		if chat := update.FromChat(); chat != nil {
+			if ownerURL := b.chatRouter.OwnerURL(chat.ID); ownerURL != "" {
+				if err := b.forwardUpdate(r.Context(), ownerURL, body); err != nil {
+					log.Printf("Error forwarding update %d of chat %d to %s: %v", update.UpdateID, chat.ID, ownerURL, err)
+					http.Error(w, "chat owner unavailable", http.StatusBadGateway)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
+	}
+
+	// tgbotapi drops the forum topic of messages, so it is kept aside
+	topics.RecordUpdate(body)
+	select {
+	case b.webhookUpdates <- update:
+		w.WriteHeader(http.StatusOK)
+	case <-b.stopCh:
+		http.Error(w, "bot is stopping", http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+	}
+}
+
+// forwardUpdate passes an update to the instance that owns its chat
+func (b *Bot) forwardUpdate(ctx context.Context, ownerURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, shardForwardTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ownerURL+b.telegramWebhookPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(telegramSecretHeader, b.telegramWebhook.Secret)
+	req.Header.Set(shardForwardedHeader, "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("owner returned status %d", resp.StatusCode)
+	}
+	return nil
+}
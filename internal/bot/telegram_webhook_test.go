@@ -0,0 +1,140 @@
+package bot
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+type fakeChatRouter map[int64]string
+
+func (r fakeChatRouter) Owns(chatID int64) bool       { return r[chatID] == "" }
+func (r fakeChatRouter) OwnerURL(chatID int64) string { return r[chatID] }
+
+func newWebhookTestBot(router ChatRouter) *Bot {
+	b := &Bot{chatRouter: router, stopCh: make(chan struct{})}
+	b.SetTelegramWebhook(TelegramWebhookConfig{URL: "https://bot.example.com", Secret: "s3cret"}, "default")
+	return b
+}
+
+func postUpdate(b *Bot, chatID string, headers map[string]string) *httptest.ResponseRecorder {
+	body := `{"update_id":1,"message":{"message_id":5,"chat":{"id":` + chatID + `,"type":"group"},"text":"hi"}}`
+	req := httptest.NewRequest(http.MethodPost, TelegramWebhookPath("default"), strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	b.serveTelegramWebhook(rec, req)
+	return rec
+}
+
+func TestTelegramWebhook_RejectsWrongSecret(t *testing.T) {
+	b := newWebhookTestBot(nil)
+	if rec := postUpdate(b, "1", map[string]string{telegramSecretHeader: "wrong"}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if len(b.webhookUpdates) != 0 {
+		t.Fatal("expected the update to be dropped")
+	}
+}
+
+func TestTelegramWebhook_ForwardsUpdatesOfOtherInstances(t *testing.T) {
+	var forwarded []*http.Request
+	var forwardedBody string
+	owner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		forwardedBody = string(body)
+		forwarded = append(forwarded, r)
+	}))
+	defer owner.Close()
+
+	b := newWebhookTestBot(fakeChatRouter{2: owner.URL})
+
+	if rec := postUpdate(b, "1", map[string]string{telegramSecretHeader: "s3cret"}); rec.Code != http.StatusOK {
+		t.Fatalf("own chat: expected 200, got %d", rec.Code)
+	}
+	if len(b.webhookUpdates) != 1 {
+		t.Fatal("expected the update of an own chat to be queued")
+	}
+	<-b.webhookUpdates
+
+	if rec := postUpdate(b, "2", map[string]string{telegramSecretHeader: "s3cret"}); rec.Code != http.StatusOK {
+		t.Fatalf("other chat: expected 200, got %d", rec.Code)
+	}
+	if len(b.webhookUpdates) != 0 {
+		t.Fatal("expected the update of another instance's chat not to be queued")
+	}
+	if len(forwarded) != 1 {
+		t.Fatalf("expected one forwarded update, got %d", len(forwarded))
+	}
+	if r := forwarded[0]; r.URL.Path != "/telegram/default" || r.Header.Get(telegramSecretHeader) != "s3cret" || r.Header.Get(shardForwardedHeader) == "" {
+		t.Fatalf("unexpected forward %s with headers %v", r.URL.Path, r.Header)
+	}
+	if !strings.Contains(forwardedBody, `"id":2`) {
+		t.Fatalf("expected the update body to be forwarded, got %s", forwardedBody)
+	}
+
+	// An instance that was forwarded an update handles it even if its ring disagrees
+	if rec := postUpdate(b, "2", map[string]string{telegramSecretHeader: "s3cret", shardForwardedHeader: "1"}); rec.Code != http.StatusOK {
+		t.Fatalf("forwarded update: expected 200, got %d", rec.Code)
+	}
+	if len(b.webhookUpdates) != 1 || len(forwarded) != 1 {
+		t.Fatal("expected a forwarded update to be queued, not forwarded again")
+	}
+}
+
+func TestTelegramWebhook_RecordsForumTopics(t *testing.T) {
+	b := newWebhookTestBot(nil)
+	body := `{"update_id":1,"message":{"message_id":77,"message_thread_id":9,"is_topic_message":true,"chat":{"id":-1001,"type":"supergroup"},"text":"hi"}}`
+	req := httptest.NewRequest(http.MethodPost, TelegramWebhookPath("default"), strings.NewReader(body))
+	req.Header.Set(telegramSecretHeader, "s3cret")
+	rec := httptest.NewRecorder()
+	b.serveTelegramWebhook(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	update := <-b.webhookUpdates
+	if got := topics.ThreadID(update.Message); got != 9 {
+		t.Fatalf("expected the update to be in topic 9, got %d", got)
+	}
+}
+
+func TestTelegramWebhook_FailsWhenOwnerIsDown(t *testing.T) {
+	owner := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	owner.Close()
+
+	b := newWebhookTestBot(fakeChatRouter{2: owner.URL})
+	// Telegram delivers the update again later
+	if rec := postUpdate(b, "2", map[string]string{telegramSecretHeader: "s3cret"}); rec.Code != http.StatusBadGateway {
+		t.Fatalf("expected 502, got %d", rec.Code)
+	}
+}
+
+func TestTelegramWebhookConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvTelegramWebhookURL, "")
+	if config, err := TelegramWebhookConfigFromEnv(); err != nil || config.Enabled() {
+		t.Fatalf("expected long polling by default, got %+v (%v)", config, err)
+	}
+
+	t.Setenv(EnvTelegramWebhookURL, "http://bot.example.com")
+	t.Setenv(EnvTelegramWebhookSecret, "s3cret")
+	if _, err := TelegramWebhookConfigFromEnv(); err == nil {
+		t.Fatal("expected an error for a plain http URL")
+	}
+
+	t.Setenv(EnvTelegramWebhookURL, "https://bot.example.com")
+	t.Setenv(EnvTelegramWebhookSecret, "")
+	if _, err := TelegramWebhookConfigFromEnv(); err == nil {
+		t.Fatal("expected an error without a secret")
+	}
+
+	t.Setenv(EnvTelegramWebhookSecret, "s3cret")
+	if config, err := TelegramWebhookConfigFromEnv(); err != nil || !config.Enabled() {
+		t.Fatalf("expected webhook mode, got %+v (%v)", config, err)
+	}
+}
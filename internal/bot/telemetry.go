@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/telemetry"
+)
+
+// SetTelemetry counts feature usage for the opt-in telemetry heartbeat
+func (b *Bot) SetTelemetry(collector *telemetry.Collector) {
+	b.telemetry = collector
+}
+
+// recordFeature counts a use of a feature; feature names must come from the
+// bot itself, never from user input
+func (b *Bot) recordFeature(feature string, chatID int64) {
+	if b.telemetry != nil {
+		b.telemetry.Record(feature, chatID)
+	}
+}
+
+// telemetryMiddleware counts each command that passed the checks under its
+// name
+func (b *Bot) telemetryMiddleware(next commands.Handler) commands.Handler {
+	return func(ctx context.Context, cmd commands.Command, message *tgbotapi.Message) *commands.Response {
+		b.recordFeature(cmd.Name(), message.Chat.ID)
+		return next(ctx, cmd, message)
+	}
+}
@@ -0,0 +1,16 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// inTopic makes msg a reply to trigger when trigger was written in a forum
+// topic. The Telegram library cannot set message_thread_id, but Telegram posts
+// a reply into the topic of the message it answers.
+func inTopic(msg *tgbotapi.MessageConfig, trigger *tgbotapi.Message) {
+	if trigger == nil || msg.ReplyToMessageID != 0 || topics.ThreadID(trigger) == 0 {
+		return
+	}
+	msg.ReplyToMessageID = trigger.MessageID
+}
@@ -0,0 +1,18 @@
+package bot
+
+import (
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// SetTrackers sends the task commands and confirmed drafts of each chat to the
+// chat's tracker instead of Todoist
+func (b *Bot) SetTrackers(trackers *tracker.Selector) {
+	for _, command := range b.commandRegistry.GetAll() {
+		if aware, ok := command.(commands.TrackerAware); ok {
+			aware.SetTrackers(trackers)
+		}
+	}
+	b.callbackHandler.SetTrackers(trackers)
+	b.trackers = trackers
+}
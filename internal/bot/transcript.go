@@ -0,0 +1,57 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/msgsplit"
+)
+
+const (
+	// maxTranscriptComment stays below Todoist's limit for comment content
+	maxTranscriptComment = 15000
+	// maxTranscriptComments bounds how many comments one discussion takes
+	maxTranscriptComments = 5
+	transcriptTimeout     = time.Minute
+)
+
+// postTranscript adds the discussion transcript to the created task as
+// comments when the chat asked for it with /transcript comment
+func (b *Bot) postTranscript(chatID int64, sessionID int, taskID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), transcriptTimeout)
+	defer cancel()
+
+	mode, err := b.dbManager.GetTranscriptMode(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting transcript mode for chat %d: %v", chatID, err)
+		return
+	}
+	if mode != db.TranscriptComment {
+		return
+	}
+	uploader, ok := b.todoistUploader(chatID)
+	if !ok {
+		return
+	}
+
+	messages, err := b.dbManager.GetSessionMessages(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting transcript of session %d: %v", sessionID, err)
+		return
+	}
+	lines := commands.FormatTranscript(messages, commands.ChatLocation(ctx, b.dbManager, chatID))
+	if len(lines) == 0 {
+		return
+	}
+
+	transcript := commands.TruncateTranscript(lines, maxTranscriptComment*maxTranscriptComments)
+	for _, part := range msgsplit.Split("💬 Переписка обсуждения\n\n"+transcript, maxTranscriptComment, false) {
+		if _, err := uploader.AddComment(ctx, taskID, part, nil); err != nil {
+			log.Printf("Error posting transcript of session %d to task %s: %v", sessionID, taskID, err)
+			return
+		}
+	}
+}
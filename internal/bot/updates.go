@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/dispatch"
+)
+
+const (
+	// EnvUpdateWorkers is how many updates are handled at once, e.g. "8".
+	// Updates of one chat are still handled one by one, in order.
+	EnvUpdateWorkers = "UPDATE_WORKERS"
+	// EnvUpdateQueueSize is how many updates may wait across all chats, e.g.
+	// "256"; polling pauses while the queue is full.
+	EnvUpdateQueueSize = "UPDATE_QUEUE_SIZE"
+)
+
+const (
+	defaultUpdateWorkers   = 8
+	defaultUpdateQueueSize = 256
+)
+
+// UpdatePoolConfig sizes the pool that handles Telegram updates
+type UpdatePoolConfig struct {
+	Workers   int
+	QueueSize int
+}
+
+// UpdatePoolFromEnv reads UPDATE_WORKERS and UPDATE_QUEUE_SIZE
+func UpdatePoolFromEnv() (UpdatePoolConfig, error) {
+	cfg := UpdatePoolConfig{Workers: defaultUpdateWorkers, QueueSize: defaultUpdateQueueSize}
+	if raw := strings.TrimSpace(os.Getenv(EnvUpdateWorkers)); raw != "" {
+		workers, err := strconv.Atoi(raw)
+		if err != nil || workers < 1 {
+			return UpdatePoolConfig{}, fmt.Errorf("invalid %s %q: expected a positive number of workers", EnvUpdateWorkers, raw)
+		}
+		cfg.Workers = workers
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvUpdateQueueSize)); raw != "" {
+		size, err := strconv.Atoi(raw)
+		if err != nil || size < 1 {
+			return UpdatePoolConfig{}, fmt.Errorf("invalid %s %q: expected a positive number of updates", EnvUpdateQueueSize, raw)
+		}
+		cfg.QueueSize = size
+	}
+	if cfg.QueueSize < cfg.Workers {
+		return UpdatePoolConfig{}, fmt.Errorf("%s (%d) must not be smaller than %s (%d)", EnvUpdateQueueSize, cfg.QueueSize, EnvUpdateWorkers, cfg.Workers)
+	}
+	return cfg, nil
+}
+
+// SetUpdatePool resizes the update pool; call it before Start
+func (b *Bot) SetUpdatePool(cfg UpdatePoolConfig) {
+	b.dispatcher = dispatch.New(cfg.Workers, cfg.QueueSize)
+}
+
+// UpdateStats reports the load of the update pool
+func (b *Bot) UpdateStats() dispatch.Stats {
+	return b.dispatcher.Stats()
+}
+
+// dispatchUpdate queues an update behind the earlier updates of its chat.
+// Updates without a chat, like inline queries, share one queue.
+func (b *Bot) dispatchUpdate(update tgbotapi.Update) {
+	var chatID int64
+	if chat := update.FromChat(); chat != nil {
+		chatID = chat.ID
+	}
+	if err := b.dispatcher.Submit(chatID, func() { b.handleUpdate(update) }); err != nil {
+		log.Printf("Dropping update %d for chat %d: %v", update.UpdateID, chatID, err)
+	}
+}
@@ -0,0 +1,14 @@
+package bot
+
+import (
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/commands"
+)
+
+// SetTokenPrices registers /usage with the prices its cost estimate uses,
+// when the database records AI usage
+func (b *Bot) SetTokenPrices(prices ai.TokenPrices) {
+	if reporter, ok := b.dbManager.(commands.AIUsageReporter); ok {
+		b.commandRegistry.Register(commands.NewUsageCommand(reporter, b.dbManager, prices, b.admins))
+	}
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"context"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/commands"
+	"github.com/user/telegram-bot/internal/tts"
+)
+
+// voiceTimeout bounds a single speech synthesis request
+const voiceTimeout = 60 * time.Second
+
+// SetSynthesizer enables voice notes for task previews and registers /speak
+func (b *Bot) SetSynthesizer(synthesizer tts.Synthesizer) {
+	b.synthesizer = synthesizer
+	b.commandRegistry.Register(commands.NewSpeakCommand(b.dbManager))
+}
+
+// sendVoicePreview voices a draft preview when the chat turned voice previews on
+func (b *Bot) sendVoicePreview(chatID int64, previewText string) {
+	if b.synthesizer == nil {
+		return
+	}
+
+	enabled, err := b.dbManager.VoicePreviewsEnabled(context.Background(), chatID)
+	if err != nil {
+		log.Printf("Error checking voice previews for chat %d: %v", chatID, err)
+		return
+	}
+	if enabled {
+		go b.sendVoice(chatID, previewText)
+	}
+}
+
+// sendVoice synthesizes text and sends it as a voice note. Failures are only
+// logged: the text message with the same content has already been sent.
+func (b *Bot) sendVoice(chatID int64, text string) {
+	text = tts.PlainText(text)
+	if text == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), voiceTimeout)
+	defer cancel()
+
+	audio, err := b.synthesizer.Synthesize(ctx, text)
+	if err != nil {
+		log.Printf("Error synthesizing voice for chat %d: %v", chatID, err)
+		return
+	}
+
+	voice := tgbotapi.NewVoice(chatID, tgbotapi.FileBytes{Name: "preview.ogg", Bytes: audio})
+	if err := b.request(chatID, voice); err != nil {
+		log.Printf("Error sending voice to chat %d: %v", chatID, err)
+	}
+}
+
+// isDraftPreview reports whether a message is a task draft with confirm
+// buttons, or the draft alternatives to choose from
+func isDraftPreview(msgConfig *tgbotapi.MessageConfig) bool {
+	_, ok := draftPreviewButton(msgConfig)
+	return ok
+}
+
+// draftPreviewButton returns the data of the confirm or alternative button of a draft preview
+func draftPreviewButton(msgConfig *tgbotapi.MessageConfig) (commands.CallbackData, bool) {
+	if msgConfig == nil {
+		return commands.CallbackData{}, false
+	}
+	markup, ok := msgConfig.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !ok {
+		return commands.CallbackData{}, false
+	}
+	for _, row := range markup.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData == nil {
+				continue
+			}
+			data := commands.ParseCallbackData(*button.CallbackData)
+			if data.Action == commands.CallbackConfirm || data.Action == commands.CallbackDraftOption {
+				return data, true
+			}
+		}
+	}
+	return commands.CallbackData{}, false
+}
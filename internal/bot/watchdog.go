@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// EnvPollingStallTimeout is how long polling may go without a finished
+// getUpdates request before the bot gives up, e.g. "5m"; "0" disables the watchdog.
+const EnvPollingStallTimeout = "POLLING_STALL_TIMEOUT"
+
+const (
+	// pollTimeoutSeconds is the long polling timeout passed to getUpdates
+	pollTimeoutSeconds = 60
+	// pollRequestTimeout aborts getUpdates requests stuck on a dead connection,
+	// tgbotapi then retries with a fresh one
+	pollRequestTimeout        = (pollTimeoutSeconds + 30) * time.Second
+	defaultPollingStall       = 5 * time.Minute
+	pollingWatchdogCheckEvery = 30 * time.Second
+)
+
+// PollingStallTimeoutFromEnv reads POLLING_STALL_TIMEOUT
+func PollingStallTimeoutFromEnv() (time.Duration, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvPollingStallTimeout))
+	if raw == "" {
+		return defaultPollingStall, nil
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", EnvPollingStallTimeout, raw, err)
+	}
+	if timeout != 0 && timeout < 2*pollRequestTimeout {
+		return 0, fmt.Errorf("%s must be 0 or at least %v", EnvPollingStallTimeout, 2*pollRequestTimeout)
+	}
+	return timeout, nil
+}
+
+// pollingClient records when getUpdates requests finish, which happens at
+// least every pollTimeoutSeconds while polling is alive, even in idle bots
+type pollingClient struct {
+	next     tgbotapi.HTTPClient
+	lastPoll atomic.Int64
+}
+
+func newPollingClient(next tgbotapi.HTTPClient) *pollingClient {
+	c := &pollingClient{next: next}
+	c.touch()
+	return c
+}
+
+func (c *pollingClient) Do(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/getUpdates") {
+		return c.next.Do(req)
+	}
+
+	defer c.touch()
+
+	ctx, cancel := context.WithTimeout(req.Context(), pollRequestTimeout)
+	defer cancel()
+	resp, err := c.next.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	// The body would be cut off by cancel, so it is read before returning
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	// tgbotapi drops the forum topic of messages, so it is kept aside
+	topics.Record(data)
+	return resp, nil
+}
+
+func (c *pollingClient) touch() {
+	c.lastPoll.Store(time.Now().UnixNano())
+}
+
+// sinceLastPoll returns how long ago the last getUpdates request finished
+func (c *pollingClient) sinceLastPoll(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, c.lastPoll.Load()))
+}
+
+// SetPollingWatchdog calls onStall when no getUpdates request finished within
+// timeout. Polling cannot be restarted inside tgbotapi, so the usual handler
+// exits and lets the orchestrator restart the process.
+func (b *Bot) SetPollingWatchdog(timeout time.Duration, onStall func(stalledFor time.Duration)) {
+	b.pollingStallTimeout = timeout
+	b.onPollingStall = onStall
+}
+
+func (b *Bot) runPollingWatchdog() {
+	ticker := time.NewTicker(pollingWatchdogCheckEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopCh:
+			return
+		case now := <-ticker.C:
+			if stalled := b.polling.sinceLastPoll(now); stalled > b.pollingStallTimeout {
+				b.onPollingStall(stalled)
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type httpClientFunc func(req *http.Request) (*http.Response, error)
+
+func (f httpClientFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestPollingClient_TracksOnlyGetUpdates(t *testing.T) {
+	client := newPollingClient(httpClientFunc(func(req *http.Request) (*http.Response, error) {
+		if _, ok := req.Context().Deadline(); !ok && strings.HasSuffix(req.URL.Path, "/getUpdates") {
+			t.Error("expected getUpdates to have a deadline")
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"ok":true}`))}, nil
+	}))
+
+	old := time.Now().Add(-time.Hour).UnixNano()
+	client.lastPoll.Store(old)
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.telegram.org/botTOKEN/sendMessage", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.lastPoll.Load() != old {
+		t.Fatal("expected sendMessage not to count as a poll")
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "https://api.telegram.org/botTOKEN/getUpdates", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != `{"ok":true}` {
+		t.Fatalf("expected body to survive the request context, got %q", body)
+	}
+	if client.sinceLastPoll(time.Now()) > time.Minute {
+		t.Fatal("expected getUpdates to refresh the last poll time")
+	}
+}
+
+func TestPollingStallTimeoutFromEnv(t *testing.T) {
+	t.Setenv(EnvPollingStallTimeout, "")
+	if timeout, err := PollingStallTimeoutFromEnv(); err != nil || timeout != defaultPollingStall {
+		t.Fatalf("expected default timeout, got %v (%v)", timeout, err)
+	}
+
+	t.Setenv(EnvPollingStallTimeout, "0")
+	if timeout, err := PollingStallTimeoutFromEnv(); err != nil || timeout != 0 {
+		t.Fatalf("expected disabled watchdog, got %v (%v)", timeout, err)
+	}
+
+	t.Setenv(EnvPollingStallTimeout, "30s")
+	if _, err := PollingStallTimeoutFromEnv(); err == nil {
+		t.Fatal("expected error for timeout shorter than a poll")
+	}
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+// analysisCacheTTL limits how long a cached analysis is reused, so prompt or
+// model changes eventually reach old discussions too
+const analysisCacheTTL = 24 * time.Hour
+
+// cachedAnalysis is the stored form of an analysis. MissingDetails is not part
+// of the task JSON, so it is kept separately.
+type cachedAnalysis struct {
+	Task           *ai.AnalyzedTask `json:"task"`
+	MissingDetails []string         `json:"missing_details,omitempty"`
+}
+
+// analysisHash identifies the AI input of a discussion: the transcript, the
+// link candidates offered to the model and the chat's conventions
+func analysisHash(messageTexts []string, candidates []tasklinks.LinkCandidate, chatPrompt string) string {
+	h := sha256.New()
+	for _, text := range messageTexts {
+		h.Write([]byte(text))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte{1})
+	for _, candidate := range candidates {
+		h.Write([]byte(candidate.URL))
+		h.Write([]byte{0})
+	}
+	h.Write([]byte{1})
+	h.Write([]byte(chatPrompt))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCachedAnalysis returns a previous analysis of the same transcript, or nil
+func (c *CreateTaskCommand) loadCachedAnalysis(ctx context.Context, sessionID int, hash string) *ai.AnalyzedTask {
+	payload, err := c.dbManager.GetAnalysisCache(ctx, sessionID, hash, time.Now().Add(-analysisCacheTTL))
+	if err != nil {
+		log.Printf("Error loading analysis cache for session %d: %v", sessionID, err)
+		return nil
+	}
+	if payload == nil {
+		return nil
+	}
+
+	var cached cachedAnalysis
+	if err := json.Unmarshal(payload, &cached); err != nil || cached.Task == nil {
+		log.Printf("Ignoring broken analysis cache for session %d: %v", sessionID, err)
+		return nil
+	}
+	cached.Task.MissingDetails = cached.MissingDetails
+	log.Printf("Reusing cached analysis for session %d", sessionID)
+	return cached.Task
+}
+
+func (c *CreateTaskCommand) saveCachedAnalysis(ctx context.Context, sessionID int, hash string, task *ai.AnalyzedTask) {
+	payload, err := json.Marshal(cachedAnalysis{Task: task, MissingDetails: task.MissingDetails})
+	if err != nil {
+		log.Printf("Error encoding analysis cache for session %d: %v", sessionID, err)
+		return
+	}
+	if err := c.dbManager.SaveAnalysisCache(ctx, sessionID, hash, payload); err != nil {
+		log.Printf("Error saving analysis cache for session %d: %v", sessionID, err)
+	}
+}
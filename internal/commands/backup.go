@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// BackupCommand exports the chat's Todoist project to a JSON document
+type BackupCommand struct {
+	exporter  todoist.ProjectExporter
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewBackupCommand(exporter todoist.ProjectExporter, dbManager DBManager, admins admin.Users) *BackupCommand {
+	return &BackupCommand{
+		exporter:  exporter,
+		dbManager: dbManager,
+		admins:    admins,
+	}
+}
+
+func (c *BackupCommand) Name() string {
+	return "backup"
+}
+
+func (c *BackupCommand) Description() string {
+	return "Резервная копия Todoist-проекта чата в JSON (для администраторов)"
+}
+
+func (c *BackupCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+func (c *BackupCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AdminOnlyCommand))
+		return &msg
+	}
+
+	if _, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID); err != nil {
+		text := i18n.T(lang, i18n.BackupProjectFailed)
+		if err == db.ErrProjectIDNotSet {
+			text = i18n.T(lang, i18n.BackupNoProject)
+		} else {
+			log.Printf("Error getting project for backup in chat %d: %v", message.Chat.ID, err)
+		}
+		msg := tgbotapi.NewMessage(message.Chat.ID, text)
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.BackupStarting))
+	return &msg
+}
+
+// ReplyDocument exports the project; Execute has already explained why there
+// is nothing to send when the sender is not an admin or no project is set
+func (c *BackupCommand) ReplyDocument(ctx context.Context, message *tgbotapi.Message) (*tgbotapi.DocumentConfig, error) {
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		return nil, nil
+	}
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
+	if err != nil {
+		return nil, nil
+	}
+
+	snapshot, err := c.exporter.ExportProject(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export project %s: %w", projectID, err)
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode project backup: %w", err)
+	}
+
+	doc := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("todoist-%s-%s.json", projectID, snapshot.ExportedAt.Format("2006-01-02")),
+		Bytes: data,
+	})
+	doc.Caption = fmt.Sprintf(i18n.T(ReplyLanguage(ctx, c.dbManager, message), i18n.BackupCaption),
+		snapshot.Project.Name, len(snapshot.Tasks), len(snapshot.Sections), snapshot.ExportedAt.Format(time.DateTime))
+	return &doc, nil
+}
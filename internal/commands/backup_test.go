@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+type exporterStub struct {
+	snapshot *todoist.ProjectSnapshot
+}
+
+func (s *exporterStub) ExportProject(ctx context.Context, projectID string) (*todoist.ProjectSnapshot, error) {
+	return s.snapshot, nil
+}
+
+func TestBackupCommand_NotAdmin(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	cmd := NewBackupCommand(&exporterStub{}, mockDB, admins)
+
+	message := CreateCommandMessage(100, "/backup")
+	response := cmd.Execute(message)
+	doc, err := cmd.ReplyDocument(context.Background(), message)
+
+	assert.Contains(t, response.Text, "только администраторам")
+	assert.NoError(t, err)
+	assert.Nil(t, doc)
+	mockDB.AssertNotCalled(t, "GetTodoistProjectID", mock.Anything, mock.Anything)
+}
+
+func TestBackupCommand_NoProject(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, int64(42)).Return("", db.ErrProjectIDNotSet)
+
+	response := NewBackupCommand(&exporterStub{}, mockDB, admins).Execute(CreateCommandMessage(42, "/backup"))
+
+	assert.Contains(t, response.Text, "/set_project")
+}
+
+func TestBackupCommand_ReplyDocument(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, int64(42)).Return("777", nil)
+	exporter := &exporterStub{snapshot: &todoist.ProjectSnapshot{
+		ExportedAt: time.Date(2024, 5, 10, 9, 0, 0, 0, time.UTC),
+		Project:    todoist.Project{ID: "777", Name: "Board"},
+		Tasks:      []todoist.TaskBackup{{TaskResponse: &todoist.TaskResponse{ID: "1", Content: "First"}}},
+	}}
+
+	doc, err := NewBackupCommand(exporter, mockDB, admins).ReplyDocument(context.Background(), CreateCommandMessage(42, "/backup"))
+
+	assert.NoError(t, err)
+	assert.NotNil(t, doc)
+	file := doc.File.(tgbotapi.FileBytes)
+	assert.Equal(t, "todoist-777-2024-05-10.json", file.Name)
+	assert.Contains(t, doc.Caption, "«Board» — задач: 1")
+
+	var decoded todoist.ProjectSnapshot
+	assert.NoError(t, json.Unmarshal(file.Bytes, &decoded))
+	assert.Equal(t, "First", decoded.Tasks[0].Content)
+}
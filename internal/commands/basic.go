@@ -4,45 +4,76 @@ import (
 	"context"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
-func GetMainKeyboard() tgbotapi.ReplyKeyboardMarkup {
-	keyboard := tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📁 Выбрать проект"),
-			tgbotapi.NewKeyboardButton("💬 Начать обсуждение"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("✅ Создать задачу"),
-			tgbotapi.NewKeyboardButton("🛑 Завершить обсуждение"),
-		),
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("📋 Список задач"),
-			tgbotapi.NewKeyboardButton("❓ Помощь"),
-		),
-	)
+// mainKeyboardButtons are the buttons of the main keyboard with the commands
+// they run, two in a row
+var mainKeyboardButtons = []struct {
+	text    i18n.Key
+	command string
+}{
+	{i18n.ButtonMainSetProject, "set_project"},
+	{i18n.ButtonMainStartDiscussion, "start_discussion"},
+	{i18n.ButtonMainCreateTask, "create_task"},
+	{i18n.ButtonMainCancel, "cancel"},
+	{i18n.ButtonMainList, "list"},
+	{i18n.ButtonMainHelp, "help"},
+}
+
+// GetMainKeyboard is the reply keyboard with the main commands in lang
+func GetMainKeyboard(lang i18n.Lang) tgbotapi.ReplyKeyboardMarkup {
+	var rows [][]tgbotapi.KeyboardButton
+	for i, button := range mainKeyboardButtons {
+		if i%2 == 0 {
+			rows = append(rows, nil)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], tgbotapi.NewKeyboardButton(i18n.T(lang, button.text)))
+	}
+	keyboard := tgbotapi.NewReplyKeyboard(rows...)
 	keyboard.ResizeKeyboard = true
 	keyboard.OneTimeKeyboard = false
 	return keyboard
 }
 
+// MainKeyboardCommand returns the command of a main keyboard button by its
+// text. Buttons of every language match, since a keyboard sent before the
+// language changed stays with the user.
+func MainKeyboardCommand(text string) (string, bool) {
+	for _, button := range mainKeyboardButtons {
+		for _, lang := range i18n.Languages {
+			if text == i18n.T(lang, button.text) {
+				return button.command, true
+			}
+		}
+	}
+	return "", false
+}
+
 // StartCommand handles the /start command
 type StartCommand struct {
-	registry      *Registry
-	todoistClient todoist.Client
-	dbManager     DBManager
+	registry  *Registry
+	trackers  *tracker.Selector
+	dbManager DBManager
 }
 
 // NewStartCommand creates a new start command handler
 func NewStartCommand(registry *Registry, todoistClient todoist.Client, dbManager DBManager) *StartCommand {
 	return &StartCommand{
-		registry:      registry,
-		todoistClient: todoistClient,
-		dbManager:     dbManager,
+		registry:  registry,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
+		dbManager: dbManager,
 	}
 }
 
+// SetTrackers sets the trackers that offer a project in the welcome message
+func (c *StartCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
 // Name returns the command name
 func (c *StartCommand) Name() string {
 	return "start"
@@ -53,43 +84,42 @@ func (c *StartCommand) Description() string {
 	return "Start interacting with the bot"
 }
 
-func (c *StartCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	welcomeText := `🤖 Привет! Я AI Task Assistant JiraF 🤖
-
-Я помогаю превращать обсуждения в чате в готовые задачи.
-
-🔧 Что я умею:
-— анализировать обсуждение
-— формировать черновик задачи
-— отправлять задачу в Todoist
-
-📋 Как пользоваться:
-1️⃣ Выбери проект
-2️⃣ Начни обсуждение
-3️⃣ Создай задачу из контекста обсуждения
+// MenuScope offers /start in private chats only, where users meet the bot
+func (c *StartCommand) MenuScope() MenuScope {
+	return MenuPrivate
+}
 
-Нажмите на любую кнопку ниже для быстрого доступа:`
+func (c *StartCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	welcomeText := i18n.T(lang, i18n.StartWelcome)
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, welcomeText)
 	msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = GetMainKeyboard()
+	msg.ReplyMarkup = GetMainKeyboard(lang)
 
-	if _, err := c.dbManager.GetTodoistProjectID(context.Background(), message.Chat.ID); err == nil {
+	if _, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID); err == nil {
 		return &msg
 	}
 
-	return buildProjectSelectionMessage(context.Background(), c.todoistClient, message.Chat.ID, welcomeText+"\n\nСначала выберите проект Todoist:")
+	return buildProjectSelectionMessage(ctx, c.trackers, message.Chat.ID, lang, welcomeText+"\n\n"+i18n.T(lang, i18n.StartChooseProject))
 }
 
-// HelpCommand handles the /help command
+// HelpCommand handles the /help command. It lists the commands of the
+// registry offered in the chat, as the command menu does.
 type HelpCommand struct {
-	registry *Registry
+	registry  *Registry
+	dbManager DBManager
+	admins    admin.Users
 }
 
-// NewHelpCommand creates a new help command handler
-func NewHelpCommand(registry *Registry) *HelpCommand {
+// NewHelpCommand creates a new help command handler; admins also see the
+// admin commands in their private chats
+func NewHelpCommand(registry *Registry, dbManager DBManager, admins admin.Users) *HelpCommand {
 	return &HelpCommand{
-		registry: registry,
+		registry:  registry,
+		dbManager: dbManager,
+		admins:    admins,
 	}
 }
 
@@ -104,21 +134,23 @@ func (c *HelpCommand) Description() string {
 }
 
 func (c *HelpCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	// ✅ ИСПРАВЛЕНО: Убраны символы < > которые ломают Markdown
-	helpText := `🧩 Полный список команд:
-
-📁 /set_project — выбрать проект Todoist для этого чата
-💬 /start_discussion — начать сбор сообщений для создания задачи
-✅ /create_task — создать задачу на основе обсуждения
-🛑 /cancel — завершить обсуждение без задачи
-📋 /list — показать список задач
-❓ /help — показать эту справку
-
-Используйте кнопки ниже для быстрого доступа:`
+	lang := ReplyLanguage(context.Background(), c.dbManager, message)
+	helpText := i18n.T(lang, i18n.Help) + "\n\n" + c.registry.HelpText(c.scope(message)) + "\n\n" + i18n.T(lang, i18n.HelpFooter)
 
+	// Plain text: descriptions carry underscores and brackets of the usage
 	msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
-	// ✅ ИСПРАВЛЕНО: Убран ParseMode чтобы не было ошибок парсинга
-	// msg.ParseMode = "Markdown"
-	msg.ReplyMarkup = GetMainKeyboard()
+	msg.ReplyMarkup = GetMainKeyboard(lang)
 	return &msg
 }
+
+// scope is the command menu of the chat the help is asked in
+func (c *HelpCommand) scope(message *tgbotapi.Message) MenuScope {
+	switch {
+	case !message.Chat.IsPrivate():
+		return MenuGroups
+	case message.From != nil && c.admins.Contains(message.From.ID):
+		return MenuAdmins
+	default:
+		return MenuPrivate
+	}
+}
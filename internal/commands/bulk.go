@@ -0,0 +1,225 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// Kinds of bulk operations
+const (
+	BulkComplete   = "complete"
+	BulkReschedule = "reschedule"
+)
+
+const (
+	// bulkPreviewLimit is how many affected tasks are listed before confirmation
+	bulkPreviewLimit  = 15
+	bulkFilterTimeout = 20 * time.Second
+)
+
+// BulkOperation is a change of many tasks waiting for confirmation
+type BulkOperation struct {
+	Kind    string
+	OwnerID int64
+	Filter  string
+	Due     string
+	Tasks   []*todoist.TaskResponse
+}
+
+// TaskIDs returns the IDs of the affected tasks
+func (op *BulkOperation) TaskIDs() []string {
+	ids := make([]string, len(op.Tasks))
+	for i, task := range op.Tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+// BulkStore keeps the bulk operation each chat is asked to confirm
+type BulkStore struct {
+	mu  sync.Mutex
+	ops map[int64]*BulkOperation
+}
+
+func NewBulkStore() *BulkStore {
+	return &BulkStore{ops: make(map[int64]*BulkOperation)}
+}
+
+// Put replaces the pending operation of the chat
+func (s *BulkStore) Put(chatID int64, op *BulkOperation) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ops[chatID] = op
+}
+
+// Take removes and returns the pending operation of the chat if userID started it.
+// The operation is returned without removing it when someone else started it.
+func (s *BulkStore) Take(chatID, userID int64) (*BulkOperation, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	op, ok := s.ops[chatID]
+	if !ok || op.OwnerID != userID {
+		return op, false
+	}
+	delete(s.ops, chatID)
+	return op, true
+}
+
+// BulkCommand completes or reschedules the chat project's tasks matching a Todoist filter
+type BulkCommand struct {
+	kind          string
+	todoistClient todoist.BulkUpdater
+	dbManager     DBManager
+	store         *BulkStore
+}
+
+func NewCompleteAllCommand(todoistClient todoist.BulkUpdater, dbManager DBManager, store *BulkStore) *BulkCommand {
+	return &BulkCommand{kind: BulkComplete, todoistClient: todoistClient, dbManager: dbManager, store: store}
+}
+
+func NewRescheduleCommand(todoistClient todoist.BulkUpdater, dbManager DBManager, store *BulkStore) *BulkCommand {
+	return &BulkCommand{kind: BulkReschedule, todoistClient: todoistClient, dbManager: dbManager, store: store}
+}
+
+func (c *BulkCommand) Name() string {
+	if c.kind == BulkReschedule {
+		return "reschedule"
+	}
+	return "complete_all"
+}
+
+func (c *BulkCommand) Description() string {
+	if c.kind == BulkReschedule {
+		return "Перенести задачи по фильтру Todoist: /reschedule overdue 2026-10-20"
+	}
+	return "Закрыть задачи по фильтру Todoist: /complete_all overdue & @bug"
+}
+
+func (c *BulkCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	ctx, cancel := context.WithTimeout(context.Background(), bulkFilterTimeout)
+	defer cancel()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	filter, due, ok := c.parseArguments(message.CommandArguments())
+	if !ok {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.Usage), c.Description()))
+		return &msg
+	}
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectChooseFirstCommand))
+		return &msg
+	}
+
+	tasks, err := c.todoistClient.GetTasksByFilter(ctx, filter)
+	if err != nil {
+		log.Printf("Error getting tasks by filter %q: %v", filter, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.BulkFilterRejected))
+		return &msg
+	}
+
+	// Filters search the whole account, the chat only manages its own project
+	var projectTasks []*todoist.TaskResponse
+	for _, task := range tasks {
+		if task.ProjectID == projectID {
+			projectTasks = append(projectTasks, task)
+		}
+	}
+	if len(projectTasks) == 0 {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.BulkNoTasks), filter))
+		return &msg
+	}
+
+	ownerID := message.From.ID
+	op := &BulkOperation{Kind: c.kind, OwnerID: ownerID, Filter: filter, Due: due, Tasks: projectTasks}
+	c.store.Put(chatID, op)
+
+	action := fmt.Sprintf(i18n.T(lang, i18n.ButtonBulkComplete), len(projectTasks))
+	if c.kind == BulkReschedule {
+		action = fmt.Sprintf(i18n.T(lang, i18n.ButtonBulkReschedule), len(projectTasks))
+	}
+	data := CallbackDataSeparator + strconv.FormatInt(ownerID, 10)
+	msg := tgbotapi.NewMessage(chatID, FormatBulkPreview(op, lang))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(action, CallbackBulkConfirm+data),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancel), CallbackBulkCancel+data),
+	))
+	return &msg
+}
+
+// parseArguments splits "<filter>" or, for /reschedule, "<filter> <date>" where the date is the last word
+func (c *BulkCommand) parseArguments(args string) (string, string, bool) {
+	args = strings.TrimSpace(args)
+	if c.kind != BulkReschedule {
+		return args, "", args != ""
+	}
+	i := strings.LastIndexAny(args, " \t")
+	if i < 0 {
+		return "", "", false
+	}
+	filter, due := strings.TrimSpace(args[:i]), strings.TrimSpace(args[i+1:])
+	return filter, due, filter != "" && due != ""
+}
+
+// FormatBulkPreview lists the tasks a bulk operation will change
+func FormatBulkPreview(op *BulkOperation, lang i18n.Lang) string {
+	var sb strings.Builder
+	if op.Kind == BulkReschedule {
+		fmt.Fprintf(&sb, i18n.T(lang, i18n.BulkPreviewReschedule)+"\n", op.Due, op.Filter, len(op.Tasks))
+	} else {
+		fmt.Fprintf(&sb, i18n.T(lang, i18n.BulkPreviewComplete)+"\n", op.Filter, len(op.Tasks))
+	}
+	for i, task := range op.Tasks {
+		if i == bulkPreviewLimit {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.MoreItems)+"\n", len(op.Tasks)-bulkPreviewLimit)
+			break
+		}
+		fmt.Fprintf(&sb, "• %s", task.Content)
+		if task.Due != nil && task.Due.Date != "" {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.DueSuffix), task.Due.Date)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + i18n.T(lang, i18n.CommandAuthorConfirms))
+	return sb.String()
+}
+
+// FormatBulkReport summarizes a finished bulk operation
+func FormatBulkReport(op *BulkOperation, results []todoist.BatchTaskResult, lang i18n.Lang) string {
+	var failed []string
+	for i, result := range results {
+		if result.Err != nil {
+			failed = append(failed, fmt.Sprintf("• %s: %v", op.Tasks[i].Content, result.Err))
+		}
+	}
+
+	var sb strings.Builder
+	done := len(results) - len(failed)
+	if op.Kind == BulkReschedule {
+		fmt.Fprintf(&sb, i18n.T(lang, i18n.BulkRescheduled)+"\n", done, len(results))
+	} else {
+		fmt.Fprintf(&sb, i18n.T(lang, i18n.BulkCompleted)+"\n", done, len(results))
+	}
+	if len(failed) > 0 {
+		fmt.Fprintf(&sb, "\n"+i18n.T(lang, i18n.BulkFailures)+"\n", len(failed))
+		for i, line := range failed {
+			if i == bulkPreviewLimit {
+				fmt.Fprintf(&sb, i18n.T(lang, i18n.MoreItems)+"\n", len(failed)-bulkPreviewLimit)
+				break
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
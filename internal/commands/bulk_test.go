@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+type fakeBulkUpdater struct {
+	tasks []*todoist.TaskResponse
+	query string
+}
+
+func (f *fakeBulkUpdater) GetTasksByFilter(ctx context.Context, query string) ([]*todoist.TaskResponse, error) {
+	f.query = query
+	return f.tasks, nil
+}
+
+func (f *fakeBulkUpdater) CompleteTasksBatch(ctx context.Context, taskIDs []string) ([]todoist.BatchTaskResult, error) {
+	return nil, nil
+}
+
+func (f *fakeBulkUpdater) RescheduleTasksBatch(ctx context.Context, taskIDs []string, due string) ([]todoist.BatchTaskResult, error) {
+	return nil, nil
+}
+
+func TestRescheduleCommand_PreviewsChatProjectTasks(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	updater := &fakeBulkUpdater{tasks: []*todoist.TaskResponse{
+		{ID: "t1", Content: "Починить логин", ProjectID: "p1", Due: &todoist.DueObject{Date: "2026-10-01"}},
+		{ID: "t2", Content: "Чужая задача", ProjectID: "p2"},
+	}}
+	store := NewBulkStore()
+
+	msg := NewRescheduleCommand(updater, mockDB, store).Execute(CreateCommandMessage(chatID, "/reschedule", "overdue & @bug 2026-10-20"))
+
+	assert.Equal(t, "overdue & @bug", updater.query)
+	assert.Contains(t, msg.Text, "Починить логин")
+	assert.NotContains(t, msg.Text, "Чужая задача")
+	markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if assert.True(t, ok) {
+		assert.Equal(t, "bulk_confirm:123456789", *markup.InlineKeyboard[0][0].CallbackData)
+	}
+
+	op, isOwner := store.Take(chatID, 42)
+	assert.False(t, isOwner)
+	assert.NotNil(t, op)
+
+	op, isOwner = store.Take(chatID, chatID)
+	assert.True(t, isOwner)
+	assert.Equal(t, "2026-10-20", op.Due)
+	assert.Equal(t, []string{"t1"}, op.TaskIDs())
+
+	op, _ = store.Take(chatID, chatID)
+	assert.Nil(t, op)
+}
+
+func TestCompleteAllCommand_RequiresFilter(t *testing.T) {
+	mockDB := new(MockDBManager)
+
+	msg := NewCompleteAllCommand(&fakeBulkUpdater{}, mockDB, NewBulkStore()).Execute(CreateCommandMessage(123456789, "/complete_all"))
+
+	assert.Contains(t, msg.Text, "Использование")
+	mockDB.AssertNotCalled(t, "GetTodoistProjectID", mock.Anything, mock.Anything)
+}
+
+func TestFormatBulkReport_ListsFailures(t *testing.T) {
+	op := &BulkOperation{Kind: BulkComplete, Tasks: []*todoist.TaskResponse{{ID: "t1", Content: "A"}, {ID: "t2", Content: "B"}}}
+	report := FormatBulkReport(op, []todoist.BatchTaskResult{{ID: "t1"}, {Err: assert.AnError}}, i18n.Russian)
+
+	assert.Contains(t, report, "Закрыто задач: 1 из 2")
+	assert.Contains(t, report, "• B:")
+}
@@ -0,0 +1,201 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// CallbackData is button data of the form "{action}:{arg}:{arg}…"
+type CallbackData struct {
+	Action string
+	Args   []string
+}
+
+// ParseCallbackData splits button data by CallbackDataSeparator
+func ParseCallbackData(data string) CallbackData {
+	parts := strings.Split(data, CallbackDataSeparator)
+	return CallbackData{Action: parts[0], Args: parts[1:]}
+}
+
+// Arg returns the i-th argument, empty if there is none
+func (d CallbackData) Arg(i int) string {
+	if i < 0 || i >= len(d.Args) {
+		return ""
+	}
+	return d.Args[i]
+}
+
+// SessionID returns the first argument as a session ID, 0 if it is not one
+func (d CallbackData) SessionID() int {
+	id, err := strconv.Atoi(d.Arg(0))
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// CallbackRequestFunc sends a Telegram request on behalf of a chat
+type CallbackRequestFunc func(chatID int64, c tgbotapi.Chattable) error
+
+// CallbackContext is a button press routed to a handler
+type CallbackContext struct {
+	Query *tgbotapi.CallbackQuery
+	Data  CallbackData
+
+	request  CallbackRequestFunc
+	language func(chatID int64) i18n.Lang
+	lang     i18n.Lang
+	answered bool
+}
+
+// ChatID returns the chat of the message with the button, 0 for presses
+// that come without it: buttons of inline messages and messages too old
+// for Telegram to send
+func (c *CallbackContext) ChatID() int64 {
+	if c.Query.Message == nil || c.Query.Message.Chat == nil {
+		return 0
+	}
+	return c.Query.Message.Chat.ID
+}
+
+// MessageID returns the message with the button, 0 when the press has none
+func (c *CallbackContext) MessageID() int {
+	if c.Query.Message == nil {
+		return 0
+	}
+	return c.Query.Message.MessageID
+}
+
+// Lang returns the language of the chat with the button
+func (c *CallbackContext) Lang() i18n.Lang {
+	if c.lang == "" {
+		c.lang = i18n.Default
+		if c.language != nil && c.ChatID() != 0 {
+			c.lang = c.language(c.ChatID())
+		}
+	}
+	return c.lang
+}
+
+// T returns the text of key in the language of the chat with the button
+func (c *CallbackContext) T(key i18n.Key) string {
+	return i18n.T(c.Lang(), key)
+}
+
+// Answer stops the button spinner, showing text as a toast if it is not
+// empty. Telegram accepts one answer per press, so later calls do nothing.
+func (c *CallbackContext) Answer(text string) {
+	if c.answered {
+		return
+	}
+	c.answered = true
+	if err := c.request(c.ChatID(), tgbotapi.NewCallback(c.Query.ID, text)); err != nil {
+		log.Printf("Error answering %s callback: %v", c.Data.Action, err)
+	}
+}
+
+// ClearButtons removes the inline keyboard without touching the message text
+func (c *CallbackContext) ClearButtons() error {
+	editMarkup := tgbotapi.NewEditMessageReplyMarkup(c.ChatID(), c.MessageID(), tgbotapi.InlineKeyboardMarkup{
+		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+	})
+	if err := c.request(c.ChatID(), editMarkup); err != nil {
+		log.Println("Error clearing reply markup:", err)
+		return err
+	}
+	return nil
+}
+
+// CallbackFunc handles a button press
+type CallbackFunc func(c *CallbackContext)
+
+// CallbackGuard runs before a handler; it answers the press and returns false
+// to reject it
+type CallbackGuard func(c *CallbackContext) bool
+
+type callbackRoute struct {
+	handler CallbackFunc
+	guards  []CallbackGuard
+}
+
+// CallbackRouter sends button presses to the handler registered for their action
+type CallbackRouter struct {
+	request  CallbackRequestFunc
+	language func(chatID int64) i18n.Lang
+	routes   map[string]callbackRoute
+}
+
+// NewCallbackRouter creates a router that answers presses and edits messages with request
+func NewCallbackRouter(request CallbackRequestFunc) *CallbackRouter {
+	return &CallbackRouter{
+		request: request,
+		routes:  make(map[string]callbackRoute),
+	}
+}
+
+// SetLanguage sets how the language of a chat is found for the answers to
+// its presses; without it they are in the default language
+func (r *CallbackRouter) SetLanguage(language func(chatID int64) i18n.Lang) {
+	r.language = language
+}
+
+// Handle registers the handler of an action, e.g. CallbackConfirm. Guards run
+// in order before it.
+func (r *CallbackRouter) Handle(action string, handler CallbackFunc, guards ...CallbackGuard) {
+	r.routes[action] = callbackRoute{handler: handler, guards: guards}
+}
+
+// Dispatch runs the handler of the press. Presses of unknown buttons, e.g.
+// from messages sent by an older version, are answered as outdated and
+// reported with false. A press the handler did not answer is answered
+// silently, so the button does not spin until Telegram gives up.
+func (r *CallbackRouter) Dispatch(query *tgbotapi.CallbackQuery) bool {
+	c := &CallbackContext{
+		Query:    query,
+		Data:     ParseCallbackData(query.Data),
+		request:  r.request,
+		language: r.language,
+	}
+	defer c.Answer("")
+
+	route, ok := r.routes[c.Data.Action]
+	if !ok || query.Message == nil {
+		c.Answer(c.T(i18n.CallbackStale))
+		return false
+	}
+	for _, guard := range route.guards {
+		if !guard(c) {
+			return true
+		}
+	}
+	route.handler(c)
+	return true
+}
+
+// SessionOwnerGuard admits only the owner of the session named by the first
+// argument; others get the denied text as a toast
+func SessionOwnerGuard(dbManager DBManager, denied i18n.Key) CallbackGuard {
+	return func(c *CallbackContext) bool {
+		sessionID := c.Data.SessionID()
+		if sessionID == 0 {
+			c.Answer(c.T(i18n.CallbackStale))
+			return false
+		}
+		isOwner, err := dbManager.IsSessionOwner(context.Background(), sessionID, c.Query.From.ID)
+		if err != nil {
+			log.Printf("Error verifying owner of session %d: %v", sessionID, err)
+			c.Answer(c.T(i18n.CallbackOwnerCheckFailed))
+			return false
+		}
+		if !isOwner {
+			c.Answer(c.T(denied))
+			return false
+		}
+		return true
+	}
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+type recordedRequests struct {
+	answers []string
+	edits   int
+}
+
+func (r *recordedRequests) request(chatID int64, c tgbotapi.Chattable) error {
+	switch req := c.(type) {
+	case tgbotapi.CallbackConfig:
+		r.answers = append(r.answers, req.Text)
+	case tgbotapi.EditMessageReplyMarkupConfig:
+		r.edits++
+	}
+	return nil
+}
+
+func callbackQuery(data string, userID int64) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "cb",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{MessageID: 7, Chat: &tgbotapi.Chat{ID: 100}},
+		Data:    data,
+	}
+}
+
+func TestParseCallbackData(t *testing.T) {
+	data := ParseCallbackData("nudge_assign:12:777")
+	assert.Equal(t, "nudge_assign", data.Action)
+	assert.Equal(t, "777", data.Arg(1))
+	assert.Equal(t, "", data.Arg(2))
+	assert.Equal(t, 12, data.SessionID())
+
+	assert.Equal(t, 0, ParseCallbackData("confirm_task:abc").SessionID())
+	assert.Equal(t, 0, ParseCallbackData("confirm_task").SessionID())
+}
+
+func TestCallbackRouter_DispatchesByAction(t *testing.T) {
+	requests := &recordedRequests{}
+	router := NewCallbackRouter(requests.request)
+
+	var got CallbackData
+	router.Handle("bulk_confirm", func(c *CallbackContext) {
+		got = c.Data
+		c.Answer("done")
+		require.NoError(t, c.ClearButtons())
+	})
+
+	assert.True(t, router.Dispatch(callbackQuery("bulk_confirm:5", 1)))
+	assert.Equal(t, CallbackData{Action: "bulk_confirm", Args: []string{"5"}}, got)
+	assert.Equal(t, []string{"done"}, requests.answers, "a press is answered once")
+	assert.Equal(t, 1, requests.edits)
+}
+
+func TestCallbackRouter_AnswersUnknownAndUnansweredPresses(t *testing.T) {
+	requests := &recordedRequests{}
+	router := NewCallbackRouter(requests.request)
+	router.Handle("keep_discussion", func(*CallbackContext) {})
+
+	assert.False(t, router.Dispatch(callbackQuery("old_button:1", 1)))
+	assert.True(t, router.Dispatch(callbackQuery("keep_discussion:1", 1)))
+	assert.Equal(t, []string{"Кнопка устарела", ""}, requests.answers)
+}
+
+func TestCallbackRouter_AnswersPressWithoutMessage(t *testing.T) {
+	requests := &recordedRequests{}
+	router := NewCallbackRouter(requests.request)
+	handled := false
+	router.Handle("keep_discussion", func(*CallbackContext) { handled = true })
+
+	query := callbackQuery("keep_discussion:1", 1)
+	query.Message = nil
+	query.InlineMessageID = "inline"
+
+	assert.NotPanics(t, func() {
+		assert.False(t, router.Dispatch(query))
+	})
+	assert.False(t, handled)
+	assert.Equal(t, []string{"Кнопка устарела"}, requests.answers)
+}
+
+func TestSessionOwnerGuard(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("IsSessionOwner", mock.Anything, 42, int64(1)).Return(true, nil)
+	mockDB.On("IsSessionOwner", mock.Anything, 42, int64(2)).Return(false, nil)
+
+	requests := &recordedRequests{}
+	router := NewCallbackRouter(requests.request)
+	handled := 0
+	router.Handle("decision_summary", func(*CallbackContext) { handled++ }, SessionOwnerGuard(mockDB, i18n.CallbackOwnerOnlyDecision))
+
+	router.Dispatch(callbackQuery("decision_summary:42", 1))
+	router.Dispatch(callbackQuery("decision_summary:42", 2))
+	router.Dispatch(callbackQuery("decision_summary:oops", 1))
+
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, []string{"", "Записать решение может только автор обсуждения", "Кнопка устарела"}, requests.answers)
+	mockDB.AssertExpectations(t)
+}
+
+func TestCallbackRouter_AnswersInChatLanguage(t *testing.T) {
+	requests := &recordedRequests{}
+	router := NewCallbackRouter(requests.request)
+	router.SetLanguage(func(int64) i18n.Lang { return i18n.English })
+
+	router.Dispatch(callbackQuery("gone:1", 1))
+
+	assert.Equal(t, []string{"This button is out of date"}, requests.answers)
+}
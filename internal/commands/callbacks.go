@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"log"
 	"strconv"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
 // Callback data constants for task actions
@@ -21,10 +23,56 @@ const (
 	CallbackCancel = "cancel_task"
 	// CallbackSelectProject is used for selecting the Todoist project for the chat
 	CallbackSelectProject = "select_project"
+	// CallbackProjectPage turns the page of the project selection keyboard
+	CallbackProjectPage = "project_page"
+	// CallbackSelectSection creates a confirmed draft in the chosen section of the project
+	CallbackSelectSection = "select_section"
+	// CallbackMergeDuplicate adds a draft as a comment to the existing task it likely duplicates
+	CallbackMergeDuplicate = "merge_task"
 	// CallbackFinishDiscussion is used for confirming discussion finish without task creation
 	CallbackFinishDiscussion = "finish_discussion"
 	// CallbackKeepDiscussion is used for declining discussion finish and continuing the session
 	CallbackKeepDiscussion = "keep_discussion"
+	// CallbackImportConfirm is used for creating the tasks of a CSV import preview
+	CallbackImportConfirm = "import_confirm"
+	// CallbackImportCancel is used for dropping a CSV import preview
+	CallbackImportCancel = "import_cancel"
+	// CallbackBulkConfirm is used for running a /complete_all or /reschedule preview
+	CallbackBulkConfirm = "bulk_confirm"
+	// CallbackBulkCancel is used for dropping a /complete_all or /reschedule preview
+	CallbackBulkCancel = "bulk_cancel"
+	// CallbackSplitToggle is used for selecting or dropping a subtask of a /split preview
+	CallbackSplitToggle = "split_toggle"
+	// CallbackSplitCreate is used for creating the selected subtasks of a /split preview
+	CallbackSplitCreate = "split_create"
+	// CallbackSplitCancel is used for dropping a /split preview
+	CallbackSplitCancel = "split_cancel"
+	// CallbackDecisionSummary is used for posting an AI summary of why a discussion ended without a task
+	CallbackDecisionSummary = "decision_summary"
+	// CallbackNudgeTakeTask is used for assigning an unassigned created task to the user who pressed the button
+	CallbackNudgeTakeTask = "nudge_take"
+	// CallbackNudgeAssign is used for assigning an unassigned created task to a mapped Todoist user
+	CallbackNudgeAssign = "nudge_assign"
+	// CallbackDraftOption is used for choosing one of the draft alternatives as the session draft
+	CallbackDraftOption = "draft_option"
+	// CallbackUndoEdit is used for rolling a draft back to the version before the last edit
+	CallbackUndoEdit = "undo_edit"
+	// CallbackQuickPriority is used for setting the draft priority without the AI
+	CallbackQuickPriority = "quick_priority"
+	// CallbackQuickDue is used for setting or clearing the draft due date without the AI
+	CallbackQuickDue = "quick_due"
+	// CallbackListPage is used for turning the pages of a /list task listing
+	CallbackListPage = "list_page"
+	// CallbackListDone is used for completing a task of a /list page
+	CallbackListDone = "list_done"
+	// CallbackListDelete is used for asking to delete a task of a /list page
+	CallbackListDelete = "list_delete"
+	// CallbackDeleteConfirm is used for deleting a task asked for by /delete or on a /list page
+	CallbackDeleteConfirm = "delete_confirm"
+	// CallbackDeleteCancel is used for keeping a task asked to be deleted
+	CallbackDeleteCancel = "delete_cancel"
+	// CallbackTaskViewDone is used for completing a task from /today, /upcoming or /overdue
+	CallbackTaskViewDone = "view_done"
 )
 
 // Separator used in callback data
@@ -37,58 +85,89 @@ type CallbackResponse struct {
 	ResponseMessage *tgbotapi.MessageConfig // Message to send to the user
 	SessionID       string                  // Session ID for context
 	WaitingForReply bool                    // Indicates if we're waiting for a reply
+	CreatedTask     *tracker.Task           // Task created by the callback, for the task card
+	Edit            tgbotapi.Chattable      // Edit of the message with the buttons, sent instead of clearing them
 }
 
 // CallbackHandler processes callback queries from buttons
 type CallbackHandler struct {
-	dbManager     DBManager
-	todoistClient todoist.Client
+	dbManager DBManager
+	trackers  *tracker.Selector
+	// createMissingLabels creates the labels of a confirmed draft the tracker does not have
+	createMissingLabels bool
 }
 
 // NewCallbackHandler creates a new callback handler
 func NewCallbackHandler(todoistClient todoist.Client, dbManager DBManager) *CallbackHandler {
 	return &CallbackHandler{
-		dbManager:     dbManager,
-		todoistClient: todoistClient,
+		dbManager: dbManager,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
 	}
 }
 
+// SetTrackers sets the trackers confirmed drafts are created in
+func (h *CallbackHandler) SetTrackers(trackers *tracker.Selector) {
+	h.trackers = trackers
+}
+
+// SetCreateMissingLabels creates the labels of confirmed drafts the tracker does not have yet
+func (h *CallbackHandler) SetCreateMissingLabels(enabled bool) {
+	h.createMissingLabels = enabled
+}
+
+// text is the text of key in the language of the chat with the button
+func (h *CallbackHandler) text(callback *tgbotapi.CallbackQuery, key i18n.Key) string {
+	if callback.Message == nil {
+		return i18n.T(i18n.Default, key)
+	}
+	return i18n.T(ChatLanguage(context.Background(), h.dbManager, callback.Message.Chat.ID), key)
+}
+
 // HandleCallback processes callback queries
 func (h *CallbackHandler) HandleCallback(callback *tgbotapi.CallbackQuery) *CallbackResponse {
 	// Extract callback type and session ID from format "{action}:{session_id}"
-	parts := strings.Split(callback.Data, CallbackDataSeparator)
-	if len(parts) != 2 {
+	data := ParseCallbackData(callback.Data)
+	// Only the section picker and the duplicate merge carry a second argument,
+	// "{action}:{session_id}:{section_id}" and "{action}:{session_id}:{task_id}"
+	twoArgs := data.Action == CallbackSelectSection || data.Action == CallbackMergeDuplicate
+	if len(data.Args) != 1 && !(twoArgs && len(data.Args) == 2) {
 		log.Printf("Invalid callback data format: %s", callback.Data)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Invalid callback data")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackStale))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
 		}
 	}
 
-	callbackType := parts[0]
+	callbackType := data.Action
 	log.Printf("Callback type: %s", callbackType)
 
-	// The session ID is the second part
-	sessionIDStr := parts[1]
+	sessionIDStr := data.Arg(0)
 	log.Printf("Session ID: %s", sessionIDStr)
 
 	// Process different callback types
 	switch callbackType {
 	case CallbackConfirm:
-		return h.handleConfirmCallback(callback, sessionIDStr)
+		return h.handleConfirmCallback(callback, sessionIDStr, nil)
+	case CallbackSelectSection:
+		sectionID := data.Arg(1)
+		return h.handleConfirmCallback(callback, sessionIDStr, &sectionID)
+	case CallbackMergeDuplicate:
+		return h.handleMergeDuplicateCallback(callback, sessionIDStr, data.Arg(1))
 	case CallbackEdit:
 		return h.handleEditCallback(callback, sessionIDStr)
 	case CallbackCancel:
 		return h.handleCancelCallback(callback, sessionIDStr)
 	case CallbackSelectProject:
 		return h.handleSelectProjectCallback(callback, sessionIDStr)
+	case CallbackProjectPage:
+		return h.handleProjectPageCallback(callback, sessionIDStr)
 	case CallbackFinishDiscussion:
 		return h.handleFinishDiscussionCallback(callback, sessionIDStr)
 	case CallbackKeepDiscussion:
 		return h.handleKeepDiscussionCallback(callback, sessionIDStr)
 	default:
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Unknown callback type")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackStale))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -123,13 +202,15 @@ func (h *CallbackHandler) verifySessionOwner(sessionIDStr string, userID int64)
 	return isOwner, nil
 }
 
-// handleConfirmCallback handles confirming a task
-func (h *CallbackHandler) handleConfirmCallback(callback *tgbotapi.CallbackQuery, sessionIDStr string) *CallbackResponse {
+// handleConfirmCallback creates the task of a draft. A nil sectionID lets the
+// chat default decide and asks with the section picker when there is none; an
+// empty one creates the task outside sections.
+func (h *CallbackHandler) handleConfirmCallback(callback *tgbotapi.CallbackQuery, sessionIDStr string, sectionID *string) *CallbackResponse {
 	// Check if the user is the owner of the session
 	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
 	if err != nil {
 		log.Printf("Error verifying session owner: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to verify session ownership")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -137,7 +218,7 @@ func (h *CallbackHandler) handleConfirmCallback(callback *tgbotapi.CallbackQuery
 	}
 
 	if !isOwner {
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Только автор обсуждения может создать задачу")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyCreate))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -154,64 +235,133 @@ func (h *CallbackHandler) handleConfirmCallback(callback *tgbotapi.CallbackQuery
 	task, err := h.dbManager.GetDraftTask(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error getting draft task: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to get draft task")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackDraftLoadFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
 		}
 	}
 
+	// A repeated confirm, e.g. after a restart left the buttons in place, must not create the task twice
+	existing, err := h.dbManager.GetCreatedTask(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error checking created task of session %d: %v", sessionID, err)
+	} else if existing != nil {
+		return alreadyCreatedResponse(callback, task.Title.String, existing.TodoistTaskID, ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID))
+	}
+
 	projectID, err := h.dbManager.GetTodoistProjectID(ctx, callback.Message.Chat.ID)
 	if err != nil {
 		log.Printf("Error getting Todoist project ID: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to get Todoist project ID")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackProjectLoadFailed))
+		return &CallbackResponse{
+			CallbackConfig: &callbackCfg,
+			IsOwner:        true,
+		}
+	}
+
+	client, err := h.trackers.ForChat(callback.Message.Chat.ID)
+	if err != nil {
+		log.Printf("Error getting task tracker: %v", err)
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackTrackerUnavailable))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
 		}
 	}
 
-	todoistRequest := &todoist.TaskRequest{
-		Content:     task.Title.String,
+	if sectionID == nil {
+		chosen, picker := h.pickSection(ctx, callback, client, projectID, sessionID)
+		if picker != nil {
+			return picker
+		}
+		sectionID = &chosen
+	}
+
+	input := &tracker.TaskInput{
+		Title:       task.Title.String,
 		Description: BuildTodoistDescription(task.Description.String, task.Fields, task.SelectedLinks),
 		ProjectID:   projectID,
 		Priority:    int(task.Priority.Int32),
 		DueDate:     task.DueISO.String,
 		Labels:      []string(task.Labels),
+		SectionID:   *sectionID,
 	}
 	if task.AssigneeTodoistID.Valid {
-		todoistRequest.AssigneeID = task.AssigneeTodoistID.String
+		input.AssigneeID = task.AssigneeTodoistID.String
+	}
+	if h.createMissingLabels {
+		EnsureLabels(ctx, client, input.Labels)
+	}
+	if h.transcriptMode(ctx, callback.Message.Chat.ID) == db.TranscriptDescription {
+		messages, err := h.dbManager.GetSessionMessages(ctx, sessionID)
+		if err != nil {
+			log.Printf("Error getting transcript of session %d: %v", sessionID, err)
+		} else {
+			input.Description = AppendTranscript(input.Description, FormatTranscript(messages, ChatLocation(ctx, h.dbManager, callback.Message.Chat.ID)))
+		}
 	}
 
-	resp, err := h.todoistClient.CreateTask(ctx, todoistRequest)
+	resp, err := client.CreateTask(ctx, input)
 	if err != nil {
 		log.Printf("Error creating task: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to create task")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackCreateFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
 		}
 	}
 
-	err = h.dbManager.SaveCreatedTask(ctx, task, resp.ID, resp.URL)
+	saved, created, err := h.dbManager.SaveCreatedTask(ctx, task, resp.ID, resp.URL)
 	if err != nil {
 		log.Printf("Error saving created task: %v", err)
+	} else if !created {
+		// A concurrent confirm of the same draft saved its task first; drop the duplicate
+		log.Printf("Task of session %d was already created as %s, deleting duplicate %s", sessionID, saved.TodoistTaskID, resp.ID)
+		if err := client.DeleteTask(ctx, resp.ID); err != nil {
+			log.Printf("Error deleting duplicate task %s: %v", resp.ID, err)
+		}
+		return alreadyCreatedResponse(callback, task.Title.String, saved.TodoistTaskID, ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID))
 	}
 
-	err = h.dbManager.CloseSession(ctx, callback.Message.Chat.ID)
+	err = h.dbManager.CloseSession(ctx, sessionID)
 	if err != nil {
 		log.Printf("Error closing session: %v", err)
 	}
 
-	// ✅ Формируем правильную ссылку на задачу Todoist
-	taskURL := fmt.Sprintf("https://app.todoist.com/app/task/%s", resp.ID)
-
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "✅ Отлично! Создаю задачу.")
-	messageText := fmt.Sprintf("✅ *Задача создана*: [%s](%s)", escapeTelegramMarkdown(task.Title.String), taskURL)
+	lang := ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackCreating))
+	messageText := fmt.Sprintf(i18n.T(lang, i18n.CallbackTaskCreated), escapeTelegramMarkdown(task.Title.String), resp.URL)
 	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, messageText)
 	msg.ParseMode = "Markdown"
 	msg.DisableWebPagePreview = true
 
+	return &CallbackResponse{
+		CallbackConfig:  &callbackCfg,
+		IsOwner:         true,
+		ResponseMessage: &msg,
+		CreatedTask:     resp,
+	}
+}
+
+// transcriptMode returns where the chat wants the discussion transcript, nowhere on errors
+func (h *CallbackHandler) transcriptMode(ctx context.Context, chatID int64) string {
+	mode, err := h.dbManager.GetTranscriptMode(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting transcript mode for chat %d: %v", chatID, err)
+		return db.TranscriptOff
+	}
+	return mode
+}
+
+// alreadyCreatedResponse answers a confirm of a draft whose task already exists
+func alreadyCreatedResponse(callback *tgbotapi.CallbackQuery, title, todoistTaskID string, lang i18n.Lang) *CallbackResponse {
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackAlreadyCreated))
+	taskURL := todoist.TaskURL(todoistTaskID)
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.CallbackAlreadyCreatedMsg), escapeTelegramMarkdown(title), taskURL))
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+
 	return &CallbackResponse{
 		CallbackConfig:  &callbackCfg,
 		IsOwner:         true,
@@ -225,7 +375,7 @@ func (h *CallbackHandler) handleEditCallback(callback *tgbotapi.CallbackQuery, s
 	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
 	if err != nil {
 		log.Printf("Error verifying session owner: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to verify session ownership")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -233,7 +383,7 @@ func (h *CallbackHandler) handleEditCallback(callback *tgbotapi.CallbackQuery, s
 	}
 
 	if !isOwner {
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Только автор обсуждения может редактировать задачу")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyEdit))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -244,22 +394,13 @@ func (h *CallbackHandler) handleEditCallback(callback *tgbotapi.CallbackQuery, s
 
 	// Send a message asking for edit instructions
 	chatID := callback.Message.Chat.ID
+	lang := ChatLanguage(context.Background(), h.dbManager, chatID)
 
-	// Create the message asking for edit instructions
-	messageText := `
-✏️ Отредактировать задачу
-Пожалуйста, ответьте на это сообщение, указав ваши инструкции по редактированию в произвольном формате.
-Примеры:
-• "Измени заголовок на: Исправление ошибки входа в систему"
-• "Установи высокий приоритет"
-• "Измени срок выполнения на пятницу"
-• "Добавить метку: frontend"
-`
-	msg := tgbotapi.NewMessage(chatID, messageText)
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.CallbackEditPrompt))
 	msg.ParseMode = "Markdown"
 
 	// Create acknowledgment for the callback
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "✏️ Пожалуйста, ответьте на это сообщение с инструкциями по редактированию")
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackEditAnswer))
 
 	// Return both the callback acknowledgment and the message to send
 	return &CallbackResponse{
@@ -277,7 +418,7 @@ func (h *CallbackHandler) handleCancelCallback(callback *tgbotapi.CallbackQuery,
 	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
 	if err != nil {
 		log.Printf("Error verifying session owner: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to verify session ownership")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -285,7 +426,7 @@ func (h *CallbackHandler) handleCancelCallback(callback *tgbotapi.CallbackQuery,
 	}
 
 	if !isOwner {
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Только автор обсуждения может отменить задачу")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyCancel))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -297,7 +438,7 @@ func (h *CallbackHandler) handleCancelCallback(callback *tgbotapi.CallbackQuery,
 	sessionID, err := h.parseSessionID(sessionIDStr)
 	if err != nil {
 		log.Printf("Error parsing session ID on cancel: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Invalid session ID")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackStale))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
@@ -311,8 +452,10 @@ func (h *CallbackHandler) handleCancelCallback(callback *tgbotapi.CallbackQuery,
 
 	log.Printf("Canceling task from session %s", sessionIDStr)
 
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "❌ Создание задачи отменено")
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "❌ Создание задачи отменено. Обсуждение продолжается.")
+	lang := ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackCancelled))
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, i18n.T(lang, i18n.CallbackCancelledMsg))
+	msg.ReplyMarkup = buildDecisionSummaryKeyboard(sessionID, lang)
 	return &CallbackResponse{
 		CallbackConfig:  &callbackCfg,
 		IsOwner:         true,
@@ -324,7 +467,7 @@ func (h *CallbackHandler) handleFinishDiscussionCallback(callback *tgbotapi.Call
 	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
 	if err != nil {
 		log.Printf("Error verifying session owner: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to verify session ownership")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -332,25 +475,29 @@ func (h *CallbackHandler) handleFinishDiscussionCallback(callback *tgbotapi.Call
 	}
 
 	if !isOwner {
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Только автор обсуждения может завершить его")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyFinish))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
 		}
 	}
 
+	// The owner check parsed the ID already
+	sessionID, _ := h.parseSessionID(sessionIDStr)
 	ctx := context.Background()
-	if err := h.dbManager.CloseSession(ctx, callback.Message.Chat.ID); err != nil {
+	if err := h.dbManager.CloseSession(ctx, sessionID); err != nil {
 		log.Printf("Error closing session: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Не удалось завершить обсуждение")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackFinishFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
 		}
 	}
 
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "🛑 Обсуждение завершено")
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "🛑 Обсуждение завершено без создания задачи.")
+	lang := ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackFinished))
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, i18n.T(lang, i18n.CallbackFinishedMsg))
+	msg.ReplyMarkup = buildDecisionSummaryKeyboard(sessionID, lang)
 
 	return &CallbackResponse{
 		CallbackConfig:  &callbackCfg,
@@ -363,7 +510,7 @@ func (h *CallbackHandler) handleKeepDiscussionCallback(callback *tgbotapi.Callba
 	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
 	if err != nil {
 		log.Printf("Error verifying session owner: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Error: Failed to verify session ownership")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
@@ -371,15 +518,16 @@ func (h *CallbackHandler) handleKeepDiscussionCallback(callback *tgbotapi.Callba
 	}
 
 	if !isOwner {
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Только автор обсуждения может продолжить обсуждение")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyKeep))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        false,
 		}
 	}
 
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "Обсуждение продолжается")
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, "↩️ Обсуждение продолжается.")
+	lang := ChatLanguage(context.Background(), h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackKept))
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, i18n.T(lang, i18n.CallbackKeptMsg))
 
 	return &CallbackResponse{
 		CallbackConfig:  &callbackCfg,
@@ -392,19 +540,64 @@ func (h *CallbackHandler) handleSelectProjectCallback(callback *tgbotapi.Callbac
 	ctx := context.Background()
 	if err := h.dbManager.SetTodoistProjectID(ctx, callback.Message.Chat.ID, projectID); err != nil {
 		log.Printf("Error saving Todoist project ID: %v", err)
-		callbackCfg := tgbotapi.NewCallback(callback.ID, "Не удалось сохранить проект")
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackProjectSaveFailed))
 		return &CallbackResponse{
 			CallbackConfig: &callbackCfg,
 			IsOwner:        true,
 		}
 	}
 
-	callbackCfg := tgbotapi.NewCallback(callback.ID, "✅ Проект выбран")
-	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, fmt.Sprintf("✅ Проект выбран. ID: %s", projectID))
+	lang := ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackProjectChosen))
+	// The keyboard is replaced by the confirmation, so the choice stays visible
+	edit := tgbotapi.NewEditMessageText(callback.Message.Chat.ID, callback.Message.MessageID,
+		fmt.Sprintf(i18n.T(lang, i18n.CallbackProjectChosenMsg), pressedButtonText(callback, projectID)))
 
 	return &CallbackResponse{
-		CallbackConfig:  &callbackCfg,
-		IsOwner:         true,
-		ResponseMessage: &msg,
+		CallbackConfig: &callbackCfg,
+		IsOwner:        true,
+		Edit:           edit,
+	}
+}
+
+// handleProjectPageCallback shows another page of the project selection keyboard
+func (h *CallbackHandler) handleProjectPageCallback(callback *tgbotapi.CallbackQuery, pageStr string) *CallbackResponse {
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 0 {
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackStale))
+		return &CallbackResponse{CallbackConfig: &callbackCfg}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	chatID := callback.Message.Chat.ID
+	projects, err := listChatProjects(ctx, h.trackers, chatID)
+	if err != nil {
+		log.Printf("Error loading projects for chat %d: %v", chatID, err)
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackProjectsLoadFailed))
+		return &CallbackResponse{CallbackConfig: &callbackCfg}
+	}
+
+	callbackCfg := tgbotapi.NewCallback(callback.ID, "")
+	return &CallbackResponse{
+		CallbackConfig: &callbackCfg,
+		IsOwner:        true,
+		Edit:           tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, buildProjectSelectionKeyboard(projects, page)),
+	}
+}
+
+// pressedButtonText returns the label of the pressed button, fallback if the
+// message no longer carries it
+func pressedButtonText(callback *tgbotapi.CallbackQuery, fallback string) string {
+	if callback.Message == nil || callback.Message.ReplyMarkup == nil {
+		return fallback
+	}
+	for _, row := range callback.Message.ReplyMarkup.InlineKeyboard {
+		for _, button := range row {
+			if button.CallbackData != nil && *button.CallbackData == callback.Data {
+				return button.Text
+			}
+		}
 	}
+	return fallback
 }
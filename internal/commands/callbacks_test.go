@@ -2,6 +2,8 @@ package commands
 
 import (
 	"database/sql"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -21,6 +23,7 @@ func TestCallbackHandler_HandleCallback_ParsesSessionIDCorrectly(t *testing.T) {
 
 	sessionID := 123
 	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
 	userID := int64(456)
 
 	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
@@ -47,7 +50,9 @@ func TestCallbackHandler_HandleCallback_ParsesSessionIDCorrectly(t *testing.T) {
 		},
 		UpdatedAt: time.Now(),
 	}, nil)
+	mockDB.On("GetCreatedTask", mock.Anything, sessionID).Return(nil, nil)
 	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptOff, nil)
 	mockTodoist.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *todoist.TaskRequest) bool {
 		return task != nil &&
 			task.Content == "Test Task" &&
@@ -71,8 +76,8 @@ func TestCallbackHandler_HandleCallback_ParsesSessionIDCorrectly(t *testing.T) {
 			len(task.SelectedLinks) == 1 &&
 			task.AssigneeNote.String == "@ivan" &&
 			task.AssigneeTodoistID.String == "user-123"
-	}), "todoist123", mock.Anything).Return(nil)
-	mockDB.On("CloseSession", mock.Anything, chatID).Return(nil)
+	}), "todoist123", mock.Anything).Return(db.CreatedTask{SessionID: sessionID, TodoistTaskID: "todoist123"}, true, nil)
+	mockDB.On("CloseSession", mock.Anything, sessionID).Return(nil)
 
 	handler := NewCallbackHandler(mockTodoist, mockDB)
 
@@ -134,6 +139,7 @@ func TestCallbackHandler_HandleCallback_CancelKeepsSessionOpen(t *testing.T) {
 
 	sessionID := 123
 	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
 	userID := int64(456)
 
 	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
@@ -158,7 +164,11 @@ func TestCallbackHandler_HandleCallback_CancelKeepsSessionOpen(t *testing.T) {
 	assert.NotNil(t, response.CallbackConfig)
 	assert.NotNil(t, response.ResponseMessage)
 	assert.Contains(t, response.ResponseMessage.Text, "Обсуждение продолжается")
-	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, chatID)
+	markup, ok := response.ResponseMessage.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if assert.True(t, ok, "expected the decision summary button") {
+		assert.Equal(t, "decision_summary:123", *markup.InlineKeyboard[0][0].CallbackData)
+	}
+	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, sessionID)
 	mockDB.AssertExpectations(t)
 }
 
@@ -168,10 +178,11 @@ func TestCallbackHandler_HandleCallback_FinishDiscussion(t *testing.T) {
 
 	sessionID := 123
 	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
 	userID := int64(456)
 
 	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
-	mockDB.On("CloseSession", mock.Anything, chatID).Return(nil)
+	mockDB.On("CloseSession", mock.Anything, sessionID).Return(nil)
 
 	handler := NewCallbackHandler(mockTodoist, mockDB)
 
@@ -192,6 +203,10 @@ func TestCallbackHandler_HandleCallback_FinishDiscussion(t *testing.T) {
 	assert.NotNil(t, response.CallbackConfig)
 	assert.NotNil(t, response.ResponseMessage)
 	assert.Contains(t, response.ResponseMessage.Text, "Обсуждение завершено")
+	markup, ok := response.ResponseMessage.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if assert.True(t, ok, "expected the decision summary button") {
+		assert.Equal(t, "decision_summary:123", *markup.InlineKeyboard[0][0].CallbackData)
+	}
 	mockDB.AssertExpectations(t)
 }
 
@@ -201,6 +216,7 @@ func TestCallbackHandler_HandleCallback_KeepDiscussion(t *testing.T) {
 
 	sessionID := 123
 	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
 	userID := int64(456)
 
 	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
@@ -224,7 +240,7 @@ func TestCallbackHandler_HandleCallback_KeepDiscussion(t *testing.T) {
 	assert.NotNil(t, response.CallbackConfig)
 	assert.NotNil(t, response.ResponseMessage)
 	assert.Contains(t, response.ResponseMessage.Text, "Обсуждение продолжается")
-	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, chatID)
+	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, sessionID)
 	mockDB.AssertExpectations(t)
 }
 
@@ -233,6 +249,7 @@ func TestCallbackHandler_HandleCallback_SelectProject(t *testing.T) {
 	mockTodoist := new(MockTodoistClient)
 
 	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
 
 	mockDB.On("SetTodoistProjectID", mock.Anything, chatID, "project123").Return(nil)
 
@@ -253,11 +270,42 @@ func TestCallbackHandler_HandleCallback_SelectProject(t *testing.T) {
 	assert.NotNil(t, response)
 	assert.True(t, response.IsOwner)
 	assert.NotNil(t, response.CallbackConfig)
-	assert.NotNil(t, response.ResponseMessage)
-	assert.Contains(t, response.ResponseMessage.Text, "Проект выбран")
+	assert.Nil(t, response.ResponseMessage)
+	edit, ok := response.Edit.(tgbotapi.EditMessageTextConfig)
+	if assert.True(t, ok) {
+		assert.Equal(t, 101, edit.MessageID)
+		assert.Equal(t, "✅ Проект выбран: project123", edit.Text)
+	}
 	mockDB.AssertExpectations(t)
 }
 
+// Tests that the page buttons of the project keyboard replace it with the requested page
+func TestCallbackHandler_HandleCallback_ProjectPage(t *testing.T) {
+	mockTodoist := new(MockTodoistClient)
+	var projects []todoist.Project
+	for i := 1; i <= 10; i++ {
+		projects = append(projects, todoist.Project{ID: fmt.Sprintf("p%d", i), Name: fmt.Sprintf("Project %d", i)})
+	}
+	mockTodoist.On("GetProjects", mock.Anything).Return(projects, nil)
+
+	handler := NewCallbackHandler(mockTodoist, new(MockDBManager))
+	response := handler.HandleCallback(&tgbotapi.CallbackQuery{
+		ID:      "test_callback_id",
+		From:    &tgbotapi.User{ID: 456},
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: 789}, MessageID: 101},
+		Data:    "project_page:1",
+	})
+
+	edit, ok := response.Edit.(tgbotapi.EditMessageReplyMarkupConfig)
+	if assert.True(t, ok) {
+		rows := edit.ReplyMarkup.InlineKeyboard
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "Project 9", rows[0][0].Text)
+		assert.Equal(t, "◀️", rows[2][0].Text)
+		assert.Equal(t, "2/2", rows[2][1].Text)
+	}
+}
+
 // Tests that malformed callback data without proper separator is handled gracefully
 func TestCallbackHandler_HandleCallback_InvalidCallbackData(t *testing.T) {
 	mockDB := new(MockDBManager)
@@ -276,7 +324,7 @@ func TestCallbackHandler_HandleCallback_InvalidCallbackData(t *testing.T) {
 	assert.NotNil(t, response)
 	assert.False(t, response.IsOwner)
 	assert.NotNil(t, response.CallbackConfig)
-	assert.Contains(t, response.CallbackConfig.Text, "Invalid callback data")
+	assert.Equal(t, "Кнопка устарела", response.CallbackConfig.Text)
 
 	mockDB.AssertNotCalled(t, "IsSessionOwner", mock.Anything, mock.Anything, mock.Anything)
 	mockDB.AssertExpectations(t)
@@ -300,7 +348,7 @@ func TestCallbackHandler_HandleCallback_UnknownCallbackType(t *testing.T) {
 	assert.NotNil(t, response)
 	assert.False(t, response.IsOwner)
 	assert.NotNil(t, response.CallbackConfig)
-	assert.Contains(t, response.CallbackConfig.Text, "Unknown callback type")
+	assert.Equal(t, "Кнопка устарела", response.CallbackConfig.Text)
 
 	mockDB.AssertExpectations(t)
 }
@@ -329,3 +377,142 @@ func TestCallbackHandler_HandleCallback_InvalidSessionID(t *testing.T) {
 
 	mockDB.AssertExpectations(t)
 }
+
+// Tests that confirming a draft whose task was already created does not call Todoist again
+func TestCallbackHandler_HandleCallback_ConfirmAlreadyCreated(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockTodoist := new(MockTodoistClient)
+
+	sessionID := 123
+	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	userID := int64(456)
+
+	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
+	mockDB.On("GetDraftTask", mock.Anything, sessionID).Return(db.DraftTask{
+		SessionID: sessionID,
+		Title:     sql.NullString{String: "Test Task", Valid: true},
+	}, nil)
+	mockDB.On("GetCreatedTask", mock.Anything, sessionID).Return(&db.CreatedTask{SessionID: sessionID, TodoistTaskID: "todoist123"}, nil)
+
+	handler := NewCallbackHandler(mockTodoist, mockDB)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:   "test_callback_id",
+		From: &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{
+			Chat:      &tgbotapi.Chat{ID: chatID},
+			MessageID: 101,
+		},
+		Data: "confirm_task:123",
+	}
+
+	response := handler.HandleCallback(callback)
+
+	assert.NotNil(t, response)
+	assert.True(t, response.IsOwner)
+	assert.Equal(t, "Задача уже создана", response.CallbackConfig.Text)
+	assert.Nil(t, response.CreatedTask)
+	assert.Contains(t, response.ResponseMessage.Text, "todoist123")
+
+	mockDB.AssertExpectations(t)
+	mockTodoist.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+}
+
+// Tests that a confirm losing the save race deletes its duplicate Todoist task
+func TestCallbackHandler_HandleCallback_ConfirmLosesSaveRace(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockTodoist := new(MockTodoistClient)
+
+	sessionID := 123
+	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	userID := int64(456)
+
+	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
+	mockDB.On("GetDraftTask", mock.Anything, sessionID).Return(db.DraftTask{
+		SessionID: sessionID,
+		Title:     sql.NullString{String: "Test Task", Valid: true},
+	}, nil)
+	mockDB.On("GetCreatedTask", mock.Anything, sessionID).Return(nil, nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptOff, nil)
+	mockTodoist.On("CreateTask", mock.Anything, mock.Anything).Return(&todoist.TaskResponse{ID: "todoist456"}, nil)
+	mockDB.On("SaveCreatedTask", mock.Anything, mock.Anything, "todoist456", mock.Anything).
+		Return(db.CreatedTask{SessionID: sessionID, TodoistTaskID: "todoist123"}, false, nil)
+	mockTodoist.On("DeleteTask", mock.Anything, "todoist456").Return(nil)
+
+	handler := NewCallbackHandler(mockTodoist, mockDB)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:   "test_callback_id",
+		From: &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{
+			Chat:      &tgbotapi.Chat{ID: chatID},
+			MessageID: 101,
+		},
+		Data: "confirm_task:123",
+	}
+
+	response := handler.HandleCallback(callback)
+
+	assert.NotNil(t, response)
+	assert.Equal(t, "Задача уже создана", response.CallbackConfig.Text)
+	assert.Nil(t, response.CreatedTask)
+	assert.Contains(t, response.ResponseMessage.Text, "todoist123")
+
+	mockDB.AssertExpectations(t)
+	mockTodoist.AssertExpectations(t)
+	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, mock.Anything)
+}
+
+// Tests that a chat in description mode gets the discussion transcript appended to the task description
+func TestCallbackHandler_HandleCallback_ConfirmAppendsTranscript(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockTodoist := new(MockTodoistClient)
+
+	sessionID := 123
+	chatID := int64(789)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	userID := int64(456)
+
+	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
+	mockDB.On("GetDraftTask", mock.Anything, sessionID).Return(db.DraftTask{
+		SessionID:   sessionID,
+		Title:       sql.NullString{String: "Test Task", Valid: true},
+		Description: sql.NullString{String: "Починить логин", Valid: true},
+	}, nil)
+	mockDB.On("GetCreatedTask", mock.Anything, sessionID).Return(nil, nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptDescription, nil)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetSessionMessages", mock.Anything, sessionID).Return([]db.Message{
+		{Username: sql.NullString{String: "ivan", Valid: true}, Text: "логин падает"},
+	}, nil)
+	mockTodoist.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *todoist.TaskRequest) bool {
+		return strings.HasPrefix(task.Description, "## Описание\nПочинить логин") &&
+			strings.Contains(task.Description, "## Переписка обсуждения") &&
+			strings.Contains(task.Description, "@ivan: логин падает")
+	})).Return(&todoist.TaskResponse{ID: "todoist456"}, nil)
+	mockDB.On("SaveCreatedTask", mock.Anything, mock.Anything, "todoist456", mock.Anything).
+		Return(db.CreatedTask{SessionID: sessionID, TodoistTaskID: "todoist456"}, true, nil)
+	mockDB.On("CloseSession", mock.Anything, sessionID).Return(nil)
+
+	handler := NewCallbackHandler(mockTodoist, mockDB)
+
+	callback := &tgbotapi.CallbackQuery{
+		ID:   "test_callback_id",
+		From: &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{
+			Chat:      &tgbotapi.Chat{ID: chatID},
+			MessageID: 101,
+		},
+		Data: "confirm_task:123",
+	}
+
+	response := handler.HandleCallback(callback)
+
+	assert.NotNil(t, response.CreatedTask)
+	mockDB.AssertExpectations(t)
+	mockTodoist.AssertExpectations(t)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
 )
 
 type CancelCommand struct {
@@ -27,32 +28,40 @@ func (c *CancelCommand) Description() string {
 
 func (c *CancelCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
 	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
 
 	// Get the active session
-	session, err := c.dbManager.GetActiveSession(ctx, message.Chat.ID)
+	session, err := FindSession(ctx, c.dbManager, message)
 	if err != nil {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Нет активного обсуждения.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.CancelNoSession), sessionTitle(SessionName(message))))
 		return &msg
 	}
 
 	// Check if the user is the session owner
 	senderID := int64(message.From.ID)
 	if session.OwnerID != senderID {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Только автор обсуждения может завершить его.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.CancelOwnerOnly))
 		return &msg
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, "Завершить обсуждение без создания задачи?")
-	msg.ReplyMarkup = buildCancelDiscussionKeyboard(session.ID)
+	msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.CancelConfirm), sessionTitle(session.Name)))
+	msg.ReplyMarkup = buildCancelDiscussionKeyboard(session.ID, lang)
 	return &msg
 }
 
-func buildCancelDiscussionKeyboard(sessionID int) tgbotapi.InlineKeyboardMarkup {
+func buildCancelDiscussionKeyboard(sessionID int, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
 	sessionIDStr := fmt.Sprintf("%d", sessionID)
-	finishButton := tgbotapi.NewInlineKeyboardButtonData("🛑 Завершить", CallbackFinishDiscussion+CallbackDataSeparator+sessionIDStr)
-	continueButton := tgbotapi.NewInlineKeyboardButtonData("↩️ Продолжить", CallbackKeepDiscussion+CallbackDataSeparator+sessionIDStr)
+	finishButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonFinishDiscussion), CallbackFinishDiscussion+CallbackDataSeparator+sessionIDStr)
+	continueButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonKeepDiscussion), CallbackKeepDiscussion+CallbackDataSeparator+sessionIDStr)
 
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(finishButton, continueButton),
 	)
 }
+
+// buildDecisionSummaryKeyboard offers to record why the discussion did not become a task
+func buildDecisionSummaryKeyboard(sessionID int, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonDecisionSummary), CallbackDecisionSummary+CallbackDataSeparator+fmt.Sprintf("%d", sessionID)),
+	))
+}
@@ -12,7 +12,7 @@ func TestCancelCommand_Execute_Success(t *testing.T) {
 	chatID := int64(123456789)
 
 	mockDBManager := new(MockDBManager)
-	mockDBManager.On("GetActiveSession", mock.Anything, chatID).Return(&db.Session{
+	mockDBManager.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{
 		ID:      1,
 		ChatID:  chatID,
 		OwnerID: chatID,
@@ -33,7 +33,7 @@ func TestCancelCommand_Execute_NotOwner(t *testing.T) {
 	chatID := int64(123456789)
 
 	mockDBManager := new(MockDBManager)
-	mockDBManager.On("GetActiveSession", mock.Anything, chatID).Return(&db.Session{
+	mockDBManager.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{
 		ID:      1,
 		ChatID:  chatID,
 		OwnerID: 999999,
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// maxChatPromptLength keeps chat conventions from crowding out the discussion in the prompt
+const maxChatPromptLength = 2000
+
+// chatPromptKinds maps the command argument to the prompt it extends
+var chatPromptKinds = map[string]string{
+	"create": ai.PromptCreateTask,
+	"edit":   ai.PromptEditTask,
+}
+
+// SetPromptCommand shows the chat's team conventions for the AI; bot admins
+// change the conventions added to the create and edit prompts
+type SetPromptCommand struct {
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewSetPromptCommand(dbManager DBManager, admins admin.Users) *SetPromptCommand {
+	return &SetPromptCommand{dbManager: dbManager, admins: admins}
+}
+
+func (c *SetPromptCommand) Name() string {
+	return "set_prompt"
+}
+
+func (c *SetPromptCommand) Description() string {
+	return "Правила оформления задач для AI в этом чате: /set_prompt create|edit <правила> (для администраторов)"
+}
+
+func (c *SetPromptCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		msg := tgbotapi.NewMessage(chatID, formatChatPrompts(ctx, c.dbManager, chatID, lang)+"\n\n"+i18n.T(lang, i18n.PromptUsage))
+		return &msg
+	}
+
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PromptAdminOnly))
+		return &msg
+	}
+
+	kind, text, _ := strings.Cut(arg, " ")
+	name, ok := chatPromptKinds[strings.ToLower(kind)]
+	text = strings.TrimSpace(text)
+	if !ok || text == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PromptUsage))
+		return &msg
+	}
+	if length := len([]rune(text)); length > maxChatPromptLength {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.PromptTooLong), length, maxChatPromptLength))
+		return &msg
+	}
+
+	if err := c.dbManager.SetChatPrompt(ctx, chatID, name, text); err != nil {
+		log.Printf("Error setting %s prompt for chat %d: %v", name, chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.PromptSaved), chatPromptTitle(name, lang), kind))
+	return &msg
+}
+
+// ResetPromptCommand returns the chat to the default create and edit prompts
+type ResetPromptCommand struct {
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewResetPromptCommand(dbManager DBManager, admins admin.Users) *ResetPromptCommand {
+	return &ResetPromptCommand{dbManager: dbManager, admins: admins}
+}
+
+func (c *ResetPromptCommand) Name() string {
+	return "reset_prompt"
+}
+
+func (c *ResetPromptCommand) Description() string {
+	return "Сбросить правила для AI: /reset_prompt [create|edit] (для администраторов)"
+}
+
+func (c *ResetPromptCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+func (c *ResetPromptCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PromptAdminOnly))
+		return &msg
+	}
+
+	names := ai.ChatPromptNames
+	if kind := strings.ToLower(strings.TrimSpace(message.CommandArguments())); kind != "" {
+		name, ok := chatPromptKinds[kind]
+		if !ok {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PromptResetUsage))
+			return &msg
+		}
+		names = []string{name}
+	}
+
+	for _, name := range names {
+		if err := c.dbManager.SetChatPrompt(ctx, chatID, name, ""); err != nil {
+			log.Printf("Error resetting %s prompt for chat %d: %v", name, chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+	}
+	msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PromptReset))
+	return &msg
+}
+
+// ChatPromptContext adds the chat's conventions for the named prompt to ctx;
+// errors leave the prompt as configured
+func ChatPromptContext(ctx context.Context, dbManager DBManager, chatID int64, name string) context.Context {
+	text, err := dbManager.GetChatPrompt(ctx, chatID, name)
+	if err != nil {
+		log.Printf("Error getting %s prompt for chat %d: %v", name, chatID, err)
+		return ctx
+	}
+	return ai.WithChatPrompt(ctx, name, text)
+}
+
+func formatChatPrompts(ctx context.Context, dbManager DBManager, chatID int64, lang i18n.Lang) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.PromptHeader))
+	for _, name := range ai.ChatPromptNames {
+		text, err := dbManager.GetChatPrompt(ctx, chatID, name)
+		if err != nil {
+			log.Printf("Error getting %s prompt for chat %d: %v", name, chatID, err)
+		}
+		if text == "" {
+			text = i18n.T(lang, i18n.PromptDefault)
+		}
+		fmt.Fprintf(&sb, "\n• %s: %s", chatPromptTitle(name, lang), text)
+	}
+	return sb.String()
+}
+
+func chatPromptTitle(name string, lang i18n.Lang) string {
+	if name == ai.PromptEditTask {
+		return i18n.T(lang, i18n.PromptEditTitle)
+	}
+	return i18n.T(lang, i18n.PromptCreateTitle)
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+)
+
+func TestSetPromptCommand_Execute(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockDB.On("SetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask, "Заголовок начинай с глагола").Return(nil)
+	cmd := NewSetPromptCommand(mockDB, admins)
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/set_prompt", "create Заголовок начинай с глагола"))
+
+	assert.Contains(t, response.Text, "Правила для создания задачи сохранены")
+	mockDB.AssertExpectations(t)
+}
+
+func TestSetPromptCommand_Execute_NotAdmin(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	cmd := NewSetPromptCommand(mockDB, admins)
+
+	response := cmd.Execute(CreateCommandMessage(100, "/set_prompt", "create Пиши коротко"))
+
+	assert.Contains(t, response.Text, "только администратор")
+	mockDB.AssertNotCalled(t, "SetChatPrompt", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSetPromptCommand_Execute_RejectsBadInput(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	cmd := NewSetPromptCommand(new(MockDBManager), admins)
+
+	assert.Contains(t, cmd.Execute(CreateCommandMessage(42, "/set_prompt", "digest Пиши коротко")).Text, "Использование")
+	assert.Contains(t, cmd.Execute(CreateCommandMessage(42, "/set_prompt", "edit")).Text, "Использование")
+	assert.Contains(t, cmd.Execute(CreateCommandMessage(42, "/set_prompt", "edit "+strings.Repeat("я", maxChatPromptLength+1))).Text, "слишком длинные")
+}
+
+func TestSetPromptCommand_Execute_ShowsCurrent(t *testing.T) {
+	chatID := int64(100)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask).Return("Пиши по-английски", nil)
+	mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptEditTask).Return("", nil)
+
+	response := NewSetPromptCommand(mockDB, admin.Users{}).Execute(CreateCommandMessage(chatID, "/set_prompt"))
+
+	assert.Contains(t, response.Text, "• создания задачи: Пиши по-английски")
+	assert.Contains(t, response.Text, "• правки задачи: не заданы")
+}
+
+func TestResetPromptCommand_Execute(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockDB.On("SetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask, "").Return(nil)
+	mockDB.On("SetChatPrompt", mock.Anything, chatID, ai.PromptEditTask, "").Return(nil)
+	cmd := NewResetPromptCommand(mockDB, admins)
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/reset_prompt"))
+
+	assert.Contains(t, response.Text, "Правила сброшены")
+	mockDB.AssertExpectations(t)
+}
+
+func TestChatPromptContext_AddsConventions(t *testing.T) {
+	chatID := int64(100)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask).Return("Заголовок на английском", nil)
+	library := ai.NewPromptLibrary(map[string]string{ai.PromptCreateTask: "Создай задачу"}, nil)
+
+	ctx := ChatPromptContext(context.Background(), mockDB, chatID, ai.PromptCreateTask)
+	prompt, err := library.Get(ctx, ai.PromptCreateTask)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(prompt, "Создай задачу"))
+	assert.Contains(t, prompt, "Заголовок на английском")
+}
@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"strings"
+	"context"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -20,9 +20,33 @@ type WaitingReplyCommand interface {
 	WaitingReply(message *tgbotapi.Message) (replyKind string, replyValue string, ok bool)
 }
 
+// QueuedCommand is implemented by commands that call AI providers. The bot runs
+// them through the job queue so a slow model never blocks the update loop.
+type QueuedCommand interface {
+	Command
+	// JobKind names the job in queue logs and listings
+	JobKind() string
+	// ExecuteContext is Execute bound to the job context
+	ExecuteContext(ctx context.Context, message *tgbotapi.Message) *tgbotapi.MessageConfig
+}
+
+// VoiceReplyCommand is implemented by commands that answer with a voice note.
+// The bot synthesizes the returned text; an empty string means no voice reply.
+type VoiceReplyCommand interface {
+	VoiceText(ctx context.Context, message *tgbotapi.Message) string
+}
+
+// DocumentReplyCommand is implemented by commands that answer with a file.
+// The bot sends the Execute reply first and then the document; a nil document
+// means there is nothing to send.
+type DocumentReplyCommand interface {
+	ReplyDocument(ctx context.Context, message *tgbotapi.Message) (*tgbotapi.DocumentConfig, error)
+}
+
 // Registry holds all available commands
 type Registry struct {
-	commands map[string]Command
+	commands    map[string]Command
+	middlewares []Middleware
 }
 
 // NewRegistry creates a new command registry
@@ -37,6 +61,30 @@ func (r *Registry) Register(cmd Command) {
 	r.commands[cmd.Name()] = cmd
 }
 
+// Use adds middlewares around every command run through Execute. The first
+// middleware is the outermost one.
+func (r *Registry) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Execute runs the command through the middlewares. Queued commands run with
+// ExecuteContext, so pass the job context for them; response commands run
+// with Respond.
+func (r *Registry) Execute(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response {
+	return r.Dispatch(ctx, cmd, message, RunCommand)
+}
+
+// Dispatch runs the command through the middlewares with run as the innermost
+// handler, for callers that run commands their own way, like the bot queueing
+// the slow ones
+func (r *Registry) Dispatch(ctx context.Context, cmd Command, message *tgbotapi.Message, run Handler) *Response {
+	handler := run
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+	return handler(ctx, cmd, message)
+}
+
 // Get returns a command by name
 func (r *Registry) Get(name string) (Command, bool) {
 	cmd, exists := r.commands[name]
@@ -51,22 +99,3 @@ func (r *Registry) GetAll() []Command {
 	}
 	return cmds
 }
-
-// GenerateHelpText generates help text for all commands
-func (r *Registry) GenerateHelpText() string {
-	helpText := "*Available Commands:*\n\n"
-	for _, cmd := range r.GetAll() {
-		// Use escape characters for special Markdown characters
-		description := cmd.Description()
-
-		// Replace Markdown special characters with their escaped versions
-		description = strings.ReplaceAll(description, "[", "\\[")
-		description = strings.ReplaceAll(description, "]", "\\]")
-		description = strings.ReplaceAll(description, "*", "\\*")
-		description = strings.ReplaceAll(description, "_", "\\_")
-		description = strings.ReplaceAll(description, "`", "\\`")
-
-		helpText += "/" + cmd.Name() + " - " + description + "\n"
-	}
-	return helpText
-}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -9,30 +10,73 @@ import (
 	"unicode"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
 	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/analysisguard"
 	"github.com/user/telegram-bot/internal/assignee"
+	"github.com/user/telegram-bot/internal/dates"
 	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quota"
+	"github.com/user/telegram-bot/internal/taskdefaults"
 	"github.com/user/telegram-bot/internal/taskfields"
 	"github.com/user/telegram-bot/internal/tasklinks"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/topics"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
 // CreateTaskCommand handles the /create_task command
 type CreateTaskCommand struct {
 	todoistClient todoist.Client
+	trackers      *tracker.Selector
 	dbManager     DBManager
 	aiClient      ai.Client
+	quotaLimits   quota.Limits
+	admins        admin.Users
+	guard         analysisguard.Rules
+	// createMissingLabels keeps suggested labels the tracker does not have yet
+	createMissingLabels bool
+	// alternatives is how many different drafts the owner chooses from
+	alternatives int
 }
 
 // NewCreateTaskCommand creates a new create_task command handler
-func NewCreateTaskCommand(todoistClient todoist.Client, dbManager DBManager, aiClient ai.Client) *CreateTaskCommand {
+func NewCreateTaskCommand(todoistClient todoist.Client, dbManager DBManager, aiClient ai.Client, quotaLimits quota.Limits, admins admin.Users) *CreateTaskCommand {
 	return &CreateTaskCommand{
 		todoistClient: todoistClient,
+		trackers:      tracker.Single(tracker.NewTodoist(todoistClient)),
 		dbManager:     dbManager,
 		aiClient:      aiClient,
+		quotaLimits:   quotaLimits,
+		admins:        admins,
+		alternatives:  1,
 	}
 }
 
+// SetAnalysisGuard sets the minimum a discussion must contain before it is analyzed
+func (c *CreateTaskCommand) SetAnalysisGuard(rules analysisguard.Rules) {
+	c.guard = rules
+}
+
+// SetCreateMissingLabels keeps AI-suggested labels the tracker does not have,
+// so they are created on confirm, instead of dropping them
+func (c *CreateTaskCommand) SetCreateMissingLabels(enabled bool) {
+	c.createMissingLabels = enabled
+}
+
+// SetDraftAlternatives makes /create_task offer up to count different drafts
+// to choose from when the AI client can sample them
+func (c *CreateTaskCommand) SetDraftAlternatives(count int) {
+	c.alternatives = count
+}
+
+// SetTrackers sets the trackers that offer a project when the chat has none
+// and resolve the labels of a draft
+func (c *CreateTaskCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
 // Name returns the command name
 func (c *CreateTaskCommand) Name() string {
 	return "create_task"
@@ -43,98 +87,111 @@ func (c *CreateTaskCommand) Description() string {
 	return "Создать задачу на основе обсуждения"
 }
 
+// JobKind returns the job queue kind for discussion analysis
+func (c *CreateTaskCommand) JobKind() string {
+	return "analyze_discussion"
+}
+
 // Execute handles the command execution
 func (c *CreateTaskCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	ctx := context.Background()
+	return c.ExecuteContext(context.Background(), message)
+}
 
+// ExecuteContext handles the command execution within a job context
+func (c *CreateTaskCommand) ExecuteContext(ctx context.Context, message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	lang := ReplyLanguage(ctx, c.dbManager, message)
 	if _, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID); err != nil {
 		if err == db.ErrProjectIDNotSet {
-			return buildProjectSelectionMessage(ctx, c.todoistClient, message.Chat.ID, "Сначала выберите проект Todoist:")
+			return buildProjectSelectionMessage(ctx, c.trackers, message.Chat.ID, lang, i18n.T(lang, i18n.StartChooseProject))
 		}
 		log.Printf("Error getting project: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error getting project: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ErrorProjectLoad))
 		return &msg
 	}
 	projectID, _ := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
 
 	// Check if there's an active session
-	hasActive, err := c.dbManager.HasActiveSession(ctx, message.Chat.ID)
+	hasActive, err := c.dbManager.HasActiveSession(ctx, message.Chat.ID, topics.ThreadID(message))
 	if err != nil {
 		log.Printf("Error checking session: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error checking session: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ErrorSessionLoad))
 		return &msg
 	}
 
 	if !hasActive {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Нет активного обсуждения. Начните его командой /start_discussion.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.SessionNoActive))
 		return &msg
 	}
 
-	// Get active session
-	session, err := c.dbManager.GetActiveSession(ctx, message.Chat.ID)
+	// Get the session named by the argument, or the active one
+	session, err := FindSession(ctx, c.dbManager, message)
+	if errors.Is(err, db.ErrNoActiveSession) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.SessionNotFound), sessionTitle(SessionName(message)), SessionName(message)))
+		return &msg
+	}
 	if err != nil {
 		log.Printf("Error getting session: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error getting session: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ErrorSessionLoad))
 		return &msg
 	}
 
 	// Check if the user is the session owner
 	senderID := int64(message.From.ID)
 	if session.OwnerID != senderID {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Только автор обсуждения может создать задачу по итогам обсуждения.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.CreateTaskOwnerOnly))
 		return &msg
 	}
+	ctx = ai.WithUsageScope(ctx, message.Chat.ID, session.ID)
 
 	// Get all messages from the session
 	messages, err := c.dbManager.GetSessionMessages(ctx, session.ID)
 	if err != nil {
 		log.Printf("Error getting messages: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error getting messages: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ErrorMessagesLoad))
 		return &msg
 	}
 
 	if len(messages) == 0 {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "В обсуждении нет сообщений, чтобы создать задачу.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.CreateTaskNoMessages))
 		return &msg
 	}
 
-	// Extract text from messages
-	var messageTexts []string
-	for _, msg := range messages {
-		if msg.Text != "" {
-			var username string
-			if msg.Username.Valid {
-				username = msg.Username.String
-			} else {
-				username = "Unknown Author"
-			}
-			messageTexts = append(
-				messageTexts,
-				fmt.Sprintf("%s, [%s]: %s", username, msg.Timestamp.Format("2006-01-02 15:04:05"), msg.Text),
-			)
-		}
+	if violation, ok := c.guard.Check(messages, session.OwnerID); !ok {
+		msg := tgbotapi.NewMessage(message.Chat.ID, guardViolationText(violation, lang))
+		return &msg
 	}
 
-	linkCandidates := buildLinkCandidates(messages)
-	selectedLinks := []tasklinks.TaskLink{}
-	if len(linkCandidates) > 0 {
-		selectedLinks, err = c.aiClient.AnalyzeLinks(ctx, messageTexts, linkCandidates)
-		if err != nil {
-			log.Printf("AI link analysis failed, continuing without selected links: %v", err)
-			selectedLinks = []tasklinks.TaskLink{}
-		}
-	}
+	messageTexts := discussionTexts(messages)
 
-	// Analyze with AI using our structured prompt
-	log.Printf("Calling AI client to analyze discussion with %d messages", len(messageTexts))
+	linkCandidates := buildLinkCandidates(messages)
 
-	analyzedTask, err := c.aiClient.AnalyzeDiscussion(ctx, messageTexts, selectedLinks)
+	// The chat's conventions from /set_prompt shape the draft as much as the
+	// discussion does, so changing them must not reuse an older analysis
+	chatPrompt, err := c.dbManager.GetChatPrompt(ctx, message.Chat.ID, ai.PromptCreateTask)
 	if err != nil {
-		log.Printf("AI analysis failed: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, "❌ AI суммаризация не удалась(. Попробуйте заново")
-		return &msg
+		log.Printf("Error getting %s prompt for chat %d: %v", ai.PromptCreateTask, message.Chat.ID, err)
+	}
+
+	// Re-running /create_task on an unchanged discussion reuses the previous analysis
+	transcriptHash := analysisHash(messageTexts, linkCandidates, chatPrompt)
+	analyzedTask := c.loadCachedAnalysis(ctx, session.ID, transcriptHash)
+	fromCache := analyzedTask != nil
+	var alternatives []*ai.AnalyzedTask
+	if !fromCache {
+		reservation, quotaMsg := c.reserveQuota(ctx, message.Chat.ID, senderID, session.ID, lang)
+		if quotaMsg != nil {
+			return quotaMsg
+		}
+
+		var failMsg *tgbotapi.MessageConfig
+		alternatives, failMsg = c.analyzeDiscussion(ctx, message.Chat.ID, messageTexts, linkCandidates, chatPrompt, lang)
+		if failMsg != nil {
+			// Only analyses that produced a draft count against the quota
+			c.releaseQuota(ctx, reservation)
+			return failMsg
+		}
+		analyzedTask = alternatives[0]
 	}
-	analyzedTask.SelectedLinks = selectedLinks
 
 	log.Printf("AI analysis successful: Title: %s, Priority: %d, Due: %s",
 		analyzedTask.Title, analyzedTask.Priority, analyzedTask.DueDate)
@@ -169,32 +226,148 @@ func (c *CreateTaskCommand) Execute(message *tgbotapi.Message) *tgbotapi.Message
 		}
 	}
 
-	// Format due date in ISO
-	dueISO := c.convertToDueISO(analyzedTask.DueDate)
+	// The cache keeps the analysis itself, without this chat's defaults
+	analysisToCache := *analyzedTask
+	analysisToCache.Labels = append([]string(nil), analyzedTask.Labels...)
 
-	// Save draft task to database
-	err = c.dbManager.SaveDraftTask(ctx, db.DraftTaskInput{
-		SessionID:      session.ID,
-		Title:          analyzedTask.Title,
-		Description:    analyzedTask.Description,
-		DueISO:         dueISO,
-		Priority:       analyzedTask.Priority,
-		TaskType:       analyzedTask.TaskType,
-		Labels:         analyzedTask.Labels,
-		MissingDetails: analyzedTask.MissingDetails,
-		SelectedLinks:  analyzedTask.SelectedLinks,
-		AssigneeNote:   assigneeNote,
-		Assignee:       resolvedAssignee,
-		Fields:         analyzedTask.TaskFields,
-	})
+	defaults := ChatTaskDefaults(ctx, c.dbManager, message.Chat.ID)
+	draft, defaultsNote := c.draftInput(ctx, message.Chat.ID, session.ID, analyzedTask, assigneeNote, resolvedAssignee, defaults)
+
+	// Save draft task to database; with alternatives it is the first option
+	err = c.dbManager.SaveDraftTask(ctx, draft)
 	if err != nil {
 		log.Printf("Failed to save draft task: %v", err)
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error saving draft: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ErrorDraftSave))
 		return &msg
 	}
+	StartDraftHistory(ctx, c.dbManager, draft)
+
+	if !fromCache {
+		c.saveCachedAnalysis(ctx, session.ID, transcriptHash, &analysisToCache)
+	}
+
+	duplicate := c.findDuplicate(ctx, message.Chat.ID, projectID, analyzedTask.Title)
+
+	if len(alternatives) > 1 {
+		drafts := []db.DraftTaskInput{draft}
+		for _, alternative := range alternatives[1:] {
+			note := alternative.AssigneeNote
+			if note == "" {
+				note = c.extractAssignee(strings.Join(messageTexts, " "))
+			}
+			// The assignee is resolved once: it comes from the discussion, not the wording
+			alternativeDraft, _ := c.draftInput(ctx, message.Chat.ID, session.ID, alternative, note, resolvedAssignee, defaults)
+			drafts = append(drafts, alternativeDraft)
+		}
+		if err := c.dbManager.SaveDraftAlternatives(ctx, session.ID, drafts); err != nil {
+			log.Printf("Failed to save draft alternatives of session %d, showing the first one: %v", session.ID, err)
+		} else {
+			return c.alternativesMessage(ctx, message.Chat.ID, session.ID, drafts, defaultsNote, duplicate)
+		}
+	}
 
 	// Create preview message
-	return c.createPreviewMessage(message.Chat.ID, session.ID, analyzedTask, dueISO, assigneeNote, resolvedAssignee)
+	return c.createPreviewMessage(message.Chat.ID, session.ID, analyzedTask, draft.DueISO, assigneeNote, resolvedAssignee, defaultsNote, duplicate)
+}
+
+// draftInput resolves the labels, due date and chat defaults of an analyzed
+// task into the draft saved for the session. It returns the note on the
+// defaults applied, empty when there were none.
+func (c *CreateTaskCommand) draftInput(ctx context.Context, chatID int64, sessionID int, task *ai.AnalyzedTask, assigneeNote string, resolvedAssignee db.AssigneeSnapshot, defaults taskdefaults.Policy) (db.DraftTaskInput, string) {
+	if client, err := c.trackers.ForChat(chatID); err == nil {
+		task.Labels = ResolveLabels(ctx, client, task.Labels, c.createMissingLabels)
+	}
+
+	// Format due date in ISO, relative dates counted in the chat time zone
+	loc := ChatLocation(ctx, c.dbManager, chatID)
+	dueISO := c.convertToDueISO(task.DueDate, loc)
+	dueISO, defaultsNote := ApplyTaskDefaults(task, dueISO, defaults, time.Now().In(loc), ChatLanguage(ctx, c.dbManager, chatID))
+
+	return db.DraftTaskInput{
+		SessionID:      sessionID,
+		Title:          task.Title,
+		Description:    task.Description,
+		DueISO:         dueISO,
+		Priority:       task.Priority,
+		TaskType:       task.TaskType,
+		Labels:         task.Labels,
+		MissingDetails: task.MissingDetails,
+		SelectedLinks:  task.SelectedLinks,
+		AssigneeNote:   assigneeNote,
+		Assignee:       resolvedAssignee,
+		Fields:         task.TaskFields,
+	}, defaultsNote
+}
+
+// analyzeDiscussion selects useful links and asks the AI for a task draft, or
+// for several to choose from when alternatives are enabled. It returns a
+// message for the chat when the analysis failed.
+func (c *CreateTaskCommand) analyzeDiscussion(ctx context.Context, chatID int64, messageTexts []string, linkCandidates []tasklinks.LinkCandidate, chatPrompt string, lang i18n.Lang) ([]*ai.AnalyzedTask, *tgbotapi.MessageConfig) {
+	selectedLinks := selectLinks(ctx, c.aiClient, messageTexts, linkCandidates)
+	ctx = ai.WithChatPrompt(ctx, ai.PromptCreateTask, chatPrompt)
+
+	// Analyze with AI using our structured prompt
+	log.Printf("Calling AI client to analyze discussion with %d messages", len(messageTexts))
+
+	var tasks []*ai.AnalyzedTask
+	var err error
+	if analyzer, ok := c.aiClient.(ai.AlternativesAnalyzer); ok && c.alternatives > 1 {
+		tasks, err = analyzer.AnalyzeDiscussionAlternatives(ctx, messageTexts, selectedLinks, c.alternatives)
+	} else {
+		var task *ai.AnalyzedTask
+		task, err = c.aiClient.AnalyzeDiscussion(ctx, messageTexts, selectedLinks)
+		tasks = []*ai.AnalyzedTask{task}
+	}
+	if err != nil {
+		log.Printf("AI analysis failed: %v", err)
+		if errors.Is(err, ai.ErrUnavailable) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AIUnavailable))
+			return nil, &msg
+		}
+		if errors.Is(err, ai.ErrInvalidOutput) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AIInvalidOutput))
+			return nil, &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AIAnalysisFailed))
+		return nil, &msg
+	}
+	for _, task := range tasks {
+		task.SelectedLinks = selectedLinks
+	}
+	return tasks, nil
+}
+
+// selectLinks asks the AI which links are worth attaching; a failure only costs the links
+func selectLinks(ctx context.Context, aiClient ai.Client, messageTexts []string, linkCandidates []tasklinks.LinkCandidate) []tasklinks.TaskLink {
+	if len(linkCandidates) == 0 {
+		return []tasklinks.TaskLink{}
+	}
+	links, err := aiClient.AnalyzeLinks(ctx, messageTexts, linkCandidates)
+	if err != nil {
+		log.Printf("AI link analysis failed, continuing without selected links: %v", err)
+		return []tasklinks.TaskLink{}
+	}
+	return links
+}
+
+// discussionTexts formats the saved messages as the transcript lines the AI analyzes
+func discussionTexts(messages []db.Message) []string {
+	var messageTexts []string
+	for _, msg := range messages {
+		if msg.Text != "" {
+			var username string
+			if msg.Username.Valid {
+				username = msg.Username.String
+			} else {
+				username = "Unknown Author"
+			}
+			messageTexts = append(
+				messageTexts,
+				fmt.Sprintf("%s, [%s]: %s", username, msg.Timestamp.Format("2006-01-02 15:04:05"), msg.Text),
+			)
+		}
+	}
+	return messageTexts
 }
 
 func buildLinkCandidates(messages []db.Message) []tasklinks.LinkCandidate {
@@ -233,23 +406,97 @@ func buildLinkCandidates(messages []db.Message) []tasklinks.LinkCandidate {
 	return candidates
 }
 
-func CreateInlineKeyboard(sessionID int) tgbotapi.InlineKeyboardMarkup {
+func CreateInlineKeyboard(sessionID int, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
 	sessionIDStr := fmt.Sprintf("%d", sessionID)
-	confirmButton := tgbotapi.NewInlineKeyboardButtonData("✅ Подтвердить", CallbackConfirm+CallbackDataSeparator+sessionIDStr)
-	editButton := tgbotapi.NewInlineKeyboardButtonData("✏️ Редактировать", CallbackEdit+CallbackDataSeparator+sessionIDStr)
-	cancelButton := tgbotapi.NewInlineKeyboardButtonData("❌ Отменить создание", CallbackCancel+CallbackDataSeparator+sessionIDStr)
+	confirmButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonConfirm), CallbackConfirm+CallbackDataSeparator+sessionIDStr)
+	editButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonEdit), CallbackEdit+CallbackDataSeparator+sessionIDStr)
+	cancelButton := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancelCreation), CallbackCancel+CallbackDataSeparator+sessionIDStr)
 
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(confirmButton, editButton, cancelButton),
 	)
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, quickEditRows(sessionID, lang)...)
 	return keyboard
 }
 
+// guardViolationText explains what the discussion is missing before it can be analyzed
+func guardViolationText(v analysisguard.Violation, lang i18n.Lang) string {
+	switch v.Reason {
+	case analysisguard.ReasonTooFewMessages:
+		return fmt.Sprintf(i18n.T(lang, i18n.GuardTooFewMessages), v.Have, v.Need)
+	case analysisguard.ReasonOwnerOnly:
+		return i18n.T(lang, i18n.GuardOwnerOnly)
+	default:
+		return fmt.Sprintf(i18n.T(lang, i18n.GuardTooLittleText), v.Have, v.Need)
+	}
+}
+
+// reserveQuota enforces task quotas before any AI call by counting the
+// analysis up front. It returns the counted analysis, 0 when nothing was
+// counted, or a message when the analysis must not run.
+func (c *CreateTaskCommand) reserveQuota(ctx context.Context, chatID, userID int64, sessionID int, lang i18n.Lang) (int, *tgbotapi.MessageConfig) {
+	if !c.quotaLimits.Enabled() || c.admins.Contains(userID) {
+		return 0, nil
+	}
+
+	exempt, err := c.dbManager.IsQuotaExempt(ctx, chatID)
+	if err != nil {
+		// Quotas protect the budget but must not take the bot down with the database
+		log.Printf("Error checking quota exemption for chat %d, skipping quota: %v", chatID, err)
+		return 0, nil
+	}
+	if exempt {
+		return 0, nil
+	}
+
+	id, chatCount, userCount, err := c.dbManager.ReserveTaskAnalysis(ctx, chatID, userID, sessionID,
+		time.Now().Add(-quota.Window), c.quotaLimits.PerChat, c.quotaLimits.PerUser)
+	if err != nil {
+		log.Printf("Error reserving task analysis for chat %d, skipping quota: %v", chatID, err)
+		return 0, nil
+	}
+	if id == 0 {
+		scope, limit, _ := c.quotaLimits.Exceeded(quota.Usage{Chat: chatCount, User: userCount})
+		log.Printf("Task quota exceeded for chat %d, user %d: %s limit %d", chatID, userID, scope, limit)
+		msg := tgbotapi.NewMessage(chatID, quotaExceededText(scope, limit, lang))
+		return 0, &msg
+	}
+	return id, nil
+}
+
+// releaseQuota uncounts a reserved analysis that failed
+func (c *CreateTaskCommand) releaseQuota(ctx context.Context, id int) {
+	if id == 0 {
+		return
+	}
+	// A canceled job still gives its reservation back
+	if err := c.dbManager.ReleaseTaskAnalysis(context.WithoutCancel(ctx), id); err != nil {
+		log.Printf("Error releasing task analysis %d: %v", id, err)
+	}
+}
+
+func quotaExceededText(scope quota.Scope, limit int, lang i18n.Lang) string {
+	if scope == quota.ScopeUser {
+		return fmt.Sprintf(i18n.T(lang, i18n.QuotaExceededUser), limit)
+	}
+	return fmt.Sprintf(i18n.T(lang, i18n.QuotaExceededChat), limit)
+}
+
 // createPreviewMessage creates a task preview with buttons
-func (c *CreateTaskCommand) createPreviewMessage(chatID int64, sessionID int, task *ai.AnalyzedTask, dueISO, assigneeNote string, resolvedAssignee db.AssigneeSnapshot) *tgbotapi.MessageConfig {
-	responseText := "✅ Черновик задачи готов.\n\n"
-	responseText += FormatTaskPreview(task, dueISO, assigneeNote, resolvedAssignee, "Если хочешь, нажми `Редактировать` и дополни это в задаче.")
-	responseText += "\n\nПроверь описание и выбери действие:"
+func (c *CreateTaskCommand) createPreviewMessage(chatID int64, sessionID int, task *ai.AnalyzedTask, dueISO, assigneeNote string, resolvedAssignee db.AssigneeSnapshot, defaultsNote string, duplicate *tracker.Task) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	ApplyPriorityNames(task, ChatPriorityNames(ctx, c.dbManager, chatID))
+	lang := ChatLanguage(ctx, c.dbManager, chatID)
+
+	responseText := i18n.T(lang, i18n.PreviewDraftReady) + "\n\n"
+	responseText += FormatTaskPreview(task, dueISO, assigneeNote, resolvedAssignee, i18n.T(lang, i18n.PreviewMissingHint), lang)
+	if defaultsNote != "" {
+		responseText += "\n\n" + defaultsNote
+	}
+	if duplicate != nil {
+		responseText += "\n\n" + FormatDuplicateWarning(duplicate, lang)
+	}
+	responseText += "\n\n" + i18n.T(lang, i18n.PreviewChooseAction)
 
 	// Create message with inline buttons
 	msg := tgbotapi.NewMessage(chatID, responseText)
@@ -257,50 +504,54 @@ func (c *CreateTaskCommand) createPreviewMessage(chatID int64, sessionID int, ta
 	msg.DisableWebPagePreview = true
 
 	// Add inline keyboard
-	msg.ReplyMarkup = CreateInlineKeyboard(sessionID)
+	msg.ReplyMarkup = CreateInlineKeyboard(sessionID, lang)
+	if duplicate != nil {
+		msg.ReplyMarkup = DuplicateInlineKeyboard(sessionID, duplicate, lang)
+	}
 
 	return &msg
 }
 
-func FormatTaskPreview(task *ai.AnalyzedTask, dueISO, assigneeNote string, resolvedAssignee db.AssigneeSnapshot, missingDetailsHint string) string {
+// FormatTaskPreview formats a draft in Telegram Markdown with its field names in lang
+func FormatTaskPreview(task *ai.AnalyzedTask, dueISO, assigneeNote string, resolvedAssignee db.AssigneeSnapshot, missingDetailsHint string, lang i18n.Lang) string {
 	if task == nil {
 		return ""
 	}
 
-	dueDisplay := escapeTelegramMarkdown(FormatDueDateForDisplay(dueISO))
+	dueDisplay := escapeTelegramMarkdown(FormatDueDateForDisplay(dueISO, lang))
 	description := FormatDescriptionForTelegram(task.Description)
 
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("*Название:* %s\n", escapeTelegramMarkdown(task.Title)))
+	b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewTitle), escapeTelegramMarkdown(task.Title)))
 	if description != "" {
-		b.WriteString(fmt.Sprintf("*Описание:*\n%s\n", description))
+		b.WriteString(fmt.Sprintf("*%s:*\n%s\n", i18n.T(lang, i18n.PreviewDescription), description))
 	}
 	if fieldsPreview := FormatTaskFieldsPreview(task.TaskFields); fieldsPreview != "" {
 		b.WriteString(fieldsPreview)
 		b.WriteString("\n")
 	}
 	if dueDisplay != "" {
-		b.WriteString(fmt.Sprintf("*Срок выполнения:* %s\n", dueDisplay))
+		b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewDue), dueDisplay))
 	}
 	if task.PriorityText != "" {
-		b.WriteString(fmt.Sprintf("*Приоритет:* %s\n", escapeTelegramMarkdown(task.PriorityText)))
+		b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewPriority), escapeTelegramMarkdown(task.PriorityText)))
 	}
-	b.WriteString(fmt.Sprintf("*Тип задачи:* %s\n", escapeTelegramMarkdown(formatTaskType(task.TaskType))))
+	b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewTaskType), escapeTelegramMarkdown(formatTaskType(task.TaskType, lang))))
 	if assigneeDisplay := FormatAssigneeForPreview(assigneeNote, resolvedAssignee); assigneeDisplay != "" {
-		b.WriteString(fmt.Sprintf("*Исполнитель:* %s\n", escapeTelegramMarkdown(assigneeDisplay)))
+		b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewAssignee), escapeTelegramMarkdown(assigneeDisplay)))
 	}
 	labels := cleanLabels(task.Labels)
 	if len(labels) > 0 {
-		b.WriteString(fmt.Sprintf("*Метки:* %s\n", escapeTelegramMarkdown(strings.Join(labels, ", "))))
+		b.WriteString(fmt.Sprintf("*%s:* %s\n", i18n.T(lang, i18n.PreviewLabels), escapeTelegramMarkdown(strings.Join(labels, ", "))))
 	}
 	if len(task.SelectedLinks) > 0 {
 		b.WriteString("\n")
-		b.WriteString(FormatSelectedLinksPreview(task.SelectedLinks))
+		b.WriteString(FormatSelectedLinksPreview(task.SelectedLinks, lang))
 		b.WriteString("\n")
 	}
 	if len(task.MissingDetails) > 0 {
 		b.WriteString("\n")
-		b.WriteString(FormatMissingDetailsPrompt(task.MissingDetails))
+		b.WriteString(FormatMissingDetailsPrompt(task.MissingDetails, lang))
 		b.WriteString("\n")
 		if missingDetailsHint != "" {
 			b.WriteString(missingDetailsHint)
@@ -368,14 +619,14 @@ func cleanLabels(labels []string) []string {
 	return cleaned
 }
 
-func formatTaskType(taskType string) string {
+func formatTaskType(taskType string, lang i18n.Lang) string {
 	normalized := strings.ToLower(strings.TrimSpace(taskType))
 
 	switch normalized {
 	case "bug":
-		return "Баг"
+		return i18n.T(lang, i18n.TaskTypeBug)
 	case "epic":
-		return "Эпик"
+		return i18n.T(lang, i18n.TaskTypeEpic)
 	}
 
 	parts := strings.FieldsFunc(normalized, func(r rune) bool {
@@ -389,32 +640,32 @@ func formatTaskType(taskType string) string {
 	}
 
 	if len(parts) == 0 {
-		return "Задача"
+		return i18n.T(lang, i18n.TaskTypeTask)
 	}
 
 	return strings.Join(parts, " ")
 }
 
 func FormatTaskTypeForBot(taskType string) string {
-	return formatTaskType(taskType)
+	return formatTaskType(taskType, i18n.Default)
 }
 
-func FormatMissingDetailsPrompt(details []string) string {
-	formattedDetails := formatDetailsList(details)
+func FormatMissingDetailsPrompt(details []string, lang i18n.Lang) string {
+	formattedDetails := formatDetailsList(details, lang)
 	if formattedDetails == "" {
 		return ""
 	}
 
-	return fmt.Sprintf("*Можно ещё уточнить:* похоже, перед созданием задачи стоит обсудить %s.", escapeTelegramMarkdown(formattedDetails))
+	return fmt.Sprintf(i18n.T(lang, i18n.PreviewMissingDetails), escapeTelegramMarkdown(formattedDetails))
 }
 
-func FormatSelectedLinksPreview(links []tasklinks.TaskLink) string {
+func FormatSelectedLinksPreview(links []tasklinks.TaskLink, lang i18n.Lang) string {
 	if len(links) == 0 {
 		return ""
 	}
 
 	var b strings.Builder
-	b.WriteString("*Полезные материалы:*\n")
+	b.WriteString(i18n.T(lang, i18n.PreviewLinks) + "\n")
 	for _, link := range tasklinks.NormalizeLinks(links) {
 		b.WriteString(fmt.Sprintf(
 			"• %s: %s — %s\n",
@@ -512,7 +763,7 @@ func formatSelectedLinksForTodoist(links []tasklinks.TaskLink) string {
 	return strings.TrimSpace(b.String())
 }
 
-func formatDetailsList(details []string) string {
+func formatDetailsList(details []string, lang i18n.Lang) string {
 	cleaned := make([]string, 0, len(details))
 	seen := make(map[string]struct{}, len(details))
 
@@ -540,9 +791,9 @@ func formatDetailsList(details []string) string {
 	case 1:
 		return cleaned[0]
 	case 2:
-		return cleaned[0] + " и " + cleaned[1]
+		return cleaned[0] + i18n.T(lang, i18n.TextAnd) + cleaned[1]
 	default:
-		return strings.Join(cleaned[:len(cleaned)-1], ", ") + " и " + cleaned[len(cleaned)-1]
+		return strings.Join(cleaned[:len(cleaned)-1], ", ") + i18n.T(lang, i18n.TextAnd) + cleaned[len(cleaned)-1]
 	}
 }
 
@@ -595,114 +846,47 @@ func (c *CreateTaskCommand) extractAssignee(text string) string {
 	return ""
 }
 
-// convertToDueISO converts human-readable due date to ISO format
-func (c *CreateTaskCommand) convertToDueISO(dueStr string) string {
+// convertToDueISO converts human-readable due date to ISO format in the chat time zone loc
+func (c *CreateTaskCommand) convertToDueISO(dueStr string, loc *time.Location) string {
 	if dueStr == "" {
 		return ""
 	}
-
-	// Moscow timezone
-	moscowLoc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		log.Printf("Error loading timezone: %v", err)
-		return dueStr
-	}
-
-	// Current time in Moscow
-	now := time.Now().In(moscowLoc)
-
-	// Basic conversions
-	switch dueStr {
-	case "today":
-		return now.Format("2006-01-02")
-	case "tomorrow":
-		return now.AddDate(0, 0, 1).Format("2006-01-02")
-	}
-
-	// Handle day names
-	switch dueStr {
-	case "monday", "понедельник":
-		return c.nextWeekday(now, time.Monday)
-	case "tuesday", "вторник":
-		return c.nextWeekday(now, time.Tuesday)
-	case "wednesday", "среда":
-		return c.nextWeekday(now, time.Wednesday)
-	case "thursday", "четверг":
-		return c.nextWeekday(now, time.Thursday)
-	case "friday", "пятница":
-		return c.nextWeekday(now, time.Friday)
-	case "saturday", "суббота":
-		return c.nextWeekday(now, time.Saturday)
-	case "sunday", "воскресенье":
-		return c.nextWeekday(now, time.Sunday)
-	}
-
-	// Handle specific dates (for now, just return as is if in proper format)
-	if strings.HasPrefix(dueStr, "202") && len(dueStr) >= 10 {
-		return dueStr
-	}
-
-	return dueStr
+	return ConvertDueDate(dueStr, time.Now().In(loc))
 }
 
-// nextWeekday returns the date of the next occurrence of the given weekday
-func (c *CreateTaskCommand) nextWeekday(now time.Time, weekday time.Weekday) string {
-	daysUntil := int(weekday - now.Weekday())
-	if daysUntil <= 0 {
-		daysUntil += 7
+// ConvertDueDate resolves a due date written in words ("завтра", "через 3
+// дня", "пятница вечером") relative to now into YYYY-MM-DD, or RFC 3339 when
+// it has a time. Text it does not understand is returned as it is.
+func ConvertDueDate(dueStr string, now time.Time) string {
+	due, ok := dates.Parse(dueStr, now)
+	if !ok {
+		return dueStr
 	}
-	return now.AddDate(0, 0, daysUntil).Format("2006-01-02")
+	return due.String()
 }
 
-// formatDueDateForDisplay formats ISO date to human-readable form in MSK timezone
-func FormatDueDateForDisplay(dueISO string) string {
+// FormatDueDateForDisplay formats ISO date to human-readable form in lang; a
+// time is shown in the offset it was set in, which is the chat's time zone
+func FormatDueDateForDisplay(dueISO string, lang i18n.Lang) string {
 	if dueISO == "" {
 		return ""
 	}
 
-	// Try parsing as ISO date
-	t, err := time.Parse("2006-01-02", dueISO)
+	// Try parsing as ISO date, then as a date with a time
+	t, err := time.Parse(dates.DateLayout, dueISO)
+	withTime := false
 	if err != nil {
-		return dueISO // Return original if not parseable
+		if t, err = time.Parse(dates.DateTimeLayout, dueISO); err != nil {
+			return dueISO // Return original if not parseable
+		}
+		withTime = true
 	}
 
-	// Moscow timezone
-	moscowLoc, err := time.LoadLocation("Europe/Moscow")
-	if err != nil {
-		log.Printf("Error loading timezone: %v", err)
-		return dueISO
-	}
-
-	// Format in Russian style
-	t = t.In(moscowLoc)
-
-	// Get day of week in Russian
-	dayOfWeek := []string{
-		"Воскресенье",
-		"Понедельник",
-		"Вторник",
-		"Среда",
-		"Четверг",
-		"Пятница",
-		"Суббота",
-	}[t.Weekday()]
-
-	// Get month in Russian
-	months := []string{
-		"января",
-		"февраля",
-		"марта",
-		"апреля",
-		"мая",
-		"июня",
-		"июля",
-		"августа",
-		"сентября",
-		"октября",
-		"ноября",
-		"декабря",
-	}
-	month := months[t.Month()-1]
-
-	return fmt.Sprintf("%d %s (%s)", t.Day(), month, dayOfWeek)
+	dayOfWeek := strings.Split(i18n.T(lang, i18n.DateWeekdays), ",")[t.Weekday()]
+	month := strings.Split(i18n.T(lang, i18n.DateMonths), ",")[t.Month()-1]
+	date := fmt.Sprintf(i18n.T(lang, i18n.DateFormat), t.Day(), month, dayOfWeek)
+	if withTime {
+		return date + ", " + t.Format("15:04")
+	}
+	return date
 }
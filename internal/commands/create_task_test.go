@@ -9,8 +9,12 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
 	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/analysisguard"
 	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quota"
 	"github.com/user/telegram-bot/internal/taskfields"
 	"github.com/user/telegram-bot/internal/tasklinks"
 	"github.com/user/telegram-bot/internal/todoist"
@@ -53,24 +57,30 @@ func (m *MockAIClient) AnalyzeAssignee(ctx context.Context, messages []string, a
 	return args.Get(0).(*ai.AssigneeSelection), args.Error(1)
 }
 
+func (m *MockAIClient) RunPrompt(ctx context.Context, name string, input string) (string, error) {
+	args := m.Called(ctx, name, input)
+	return args.String(0), args.Error(1)
+}
+
 // Tests the CreateTaskCommand execution when there is an active discussion session
 // Verifies that a task preview is created with correct buttons and formatting
 func TestCreateTaskCommand_Execute(t *testing.T) {
 	// Create mock dependencies
 	mockDB := new(MockDBManager)
+	mockDB.On("GetChatLanguage", mock.Anything, mock.Anything).Return("", nil)
 	mockAI := new(MockAIClient)
 	mockTodoist := new(MockTodoistClient)
 
 	// Create command
-	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI)
+	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI, quota.Limits{}, admin.Users{})
 
 	// Tests task preview creation from an active discussion with messages
 	t.Run("Create task preview", func(t *testing.T) {
 		// Set up mocks
-		mockDB.On("HasActiveSession", mock.Anything, int64(123)).Return(true, nil)
+		mockDB.On("HasActiveSession", mock.Anything, int64(123), 0).Return(true, nil)
 
 		session := &db.Session{ID: 42, ChatID: 123, Status: "open", OwnerID: 456}
-		mockDB.On("GetActiveSession", mock.Anything, int64(123)).Return(session, nil)
+		mockDB.On("GetActiveSession", mock.Anything, int64(123), 0).Return(session, nil)
 
 		// Mock some messages
 		messages := []db.Message{
@@ -98,10 +108,16 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 			},
 		}
 		mockDB.On("GetSessionMessages", mock.Anything, 42).Return(messages, nil)
+		mockDB.On("GetAnalysisCache", mock.Anything, 42, mock.Anything, mock.Anything).Return(nil, nil)
+		mockDB.On("SaveAnalysisCache", mock.Anything, 42, mock.Anything, mock.Anything).Return(nil)
 
 		// Mock project ID
 		mockDB.On("GetTodoistProjectID", mock.Anything, int64(123)).Return("project123", nil)
 		mockDB.On("GetAssigneeMappings", mock.Anything, int64(123), "project123").Return([]db.AssigneeMapping(nil), nil)
+		mockDB.On("GetPriorityNames", mock.Anything, int64(123)).Return("", nil)
+		mockDB.On("GetTaskDefaults", mock.Anything, int64(123)).Return("", nil)
+		mockDB.On("GetChatTimezone", mock.Anything, int64(123)).Return("", nil)
+		mockDB.On("GetChatPrompt", mock.Anything, int64(123), ai.PromptCreateTask).Return("", nil)
 
 		// Mock AI analysis - with formatted messages (as in real code)
 		analyzedTask := &ai.AnalyzedTask{
@@ -131,6 +147,10 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 			"Unknown Author, [0001-01-01 00:00:00]: This is high priority",
 		}, selectedLinks).Return(analyzedTask, nil)
 
+		// Suggested labels are checked against the Todoist ones
+		mockTodoist.On("GetLabels", mock.Anything).Return([]todoist.Label{{Name: "backend"}, {Name: "ai"}, {Name: "frontend"}}, nil)
+		mockTodoist.On("GetTasks", mock.Anything, "project123").Return([]*todoist.TaskResponse{{ID: "t9", Content: "Update README"}}, nil)
+
 		// Mock saving draft task
 		mockDB.On(
 			"SaveDraftTask",
@@ -148,6 +168,9 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 					input.Fields.BriefSolution == "Реализовать NLP-фичу."
 			}),
 		).Return(nil)
+		// The new draft starts its edit history over
+		mockDB.On("DeleteDraftRevisions", mock.Anything, 42, 1).Return(nil)
+		mockDB.On("AddDraftRevision", mock.Anything, mock.Anything, "").Return(1, nil)
 
 		// Create a mock message
 		message := &tgbotapi.Message{
@@ -182,7 +205,7 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 		// Check that the message has a reply markup with buttons
 		markup, ok := result.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
 		assert.True(t, ok)
-		assert.Len(t, markup.InlineKeyboard, 1)
+		assert.Len(t, markup.InlineKeyboard, 3)
 		assert.Len(t, markup.InlineKeyboard[0], 3)
 		assert.Contains(t, markup.InlineKeyboard[0][0].Text, "✅")
 		assert.Contains(t, markup.InlineKeyboard[0][1].Text, "✏️")
@@ -192,7 +215,7 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 	// Tests behavior when user tries to create task without active discussion session
 	t.Run("No active session", func(t *testing.T) {
 		mockDB.On("GetTodoistProjectID", mock.Anything, int64(456)).Return("project456", nil)
-		mockDB.On("HasActiveSession", mock.Anything, int64(456)).Return(false, nil)
+		mockDB.On("HasActiveSession", mock.Anything, int64(456), 0).Return(false, nil)
 
 		message := &tgbotapi.Message{
 			Chat: &tgbotapi.Chat{
@@ -234,13 +257,137 @@ func TestCreateTaskCommand_Execute(t *testing.T) {
 	})
 }
 
+// Tests that an exhausted chat quota stops the command before any AI call
+func TestCreateTaskCommand_Execute_QuotaExceeded(t *testing.T) {
+	chatID := int64(123456789)
+	session := &db.Session{ID: 7, ChatID: chatID, OwnerID: chatID, Status: "open"}
+
+	newMocks := func() (*MockDBManager, *MockAIClient) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project-1", nil)
+		mockDB.On("HasActiveSession", mock.Anything, chatID, 0).Return(true, nil)
+		mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(session, nil)
+		mockDB.On("GetSessionMessages", mock.Anything, session.ID).Return([]db.Message{{Text: "починить логин"}}, nil)
+		mockDB.On("GetAnalysisCache", mock.Anything, session.ID, mock.Anything, mock.Anything).Return(nil, nil)
+		mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask).Return("", nil)
+		return mockDB, new(MockAIClient)
+	}
+
+	t.Run("limit reached", func(t *testing.T) {
+		mockDB, mockAI := newMocks()
+		mockDB.On("IsQuotaExempt", mock.Anything, chatID).Return(false, nil)
+		mockDB.On("ReserveTaskAnalysis", mock.Anything, chatID, chatID, session.ID, mock.Anything, 3, 0).Return(0, 3, 1, nil)
+
+		cmd := NewCreateTaskCommand(new(MockTodoistClient), mockDB, mockAI, quota.Limits{PerChat: 3}, admin.Users{})
+		result := cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+		assert.Contains(t, result.Text, "Достигнут лимит для этого чата: 3")
+		mockAI.AssertNotCalled(t, "AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("failed analysis is not counted", func(t *testing.T) {
+		mockDB, mockAI := newMocks()
+		mockDB.On("IsQuotaExempt", mock.Anything, chatID).Return(false, nil)
+		mockDB.On("ReserveTaskAnalysis", mock.Anything, chatID, chatID, session.ID, mock.Anything, 3, 0).Return(41, 1, 1, nil)
+		mockDB.On("ReleaseTaskAnalysis", mock.Anything, 41).Return(nil)
+		mockAI.On("AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		cmd := NewCreateTaskCommand(new(MockTodoistClient), mockDB, mockAI, quota.Limits{PerChat: 3}, admin.Users{})
+		cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+		mockDB.AssertCalled(t, "ReleaseTaskAnalysis", mock.Anything, 41)
+	})
+
+	t.Run("admin is not limited", func(t *testing.T) {
+		mockDB, mockAI := newMocks()
+		mockAI.On("AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything).Return(nil, assert.AnError)
+
+		admins, _ := admin.ParseUsers("123456789")
+		cmd := NewCreateTaskCommand(new(MockTodoistClient), mockDB, mockAI, quota.Limits{PerChat: 3}, admins)
+		cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+		mockDB.AssertNotCalled(t, "ReserveTaskAnalysis", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockAI.AssertCalled(t, "AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// Tests that a discussion below the analysis guard stops before quota and AI
+func TestCreateTaskCommand_Execute_GuardRejectsThinDiscussion(t *testing.T) {
+	chatID := int64(123456789)
+	session := &db.Session{ID: 7, ChatID: chatID, OwnerID: chatID, Status: "open"}
+
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project-1", nil)
+	mockDB.On("HasActiveSession", mock.Anything, chatID, 0).Return(true, nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(session, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, session.ID).Return([]db.Message{{Text: "ok"}, {Text: "[sticker 👍]"}}, nil)
+
+	mockAI := new(MockAIClient)
+	cmd := NewCreateTaskCommand(new(MockTodoistClient), mockDB, mockAI, quota.Limits{PerChat: 3}, admin.Users{})
+	cmd.SetAnalysisGuard(analysisguard.Rules{MinMessages: 1, MinCharacters: 20})
+	result := cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+	assert.Contains(t, result.Text, "слишком мало текста")
+	assert.Contains(t, result.Text, "2 символов из 20")
+	mockDB.AssertNotCalled(t, "ReserveTaskAnalysis", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockAI.AssertNotCalled(t, "AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Tests that re-running /create_task on an unchanged discussion reuses the cached
+// analysis without calling the AI or spending quota
+func TestCreateTaskCommand_Execute_ReusesCachedAnalysis(t *testing.T) {
+	chatID := int64(123456789)
+	session := &db.Session{ID: 7, ChatID: chatID, OwnerID: chatID, Status: "open"}
+	messages := []db.Message{{Text: "починить логин"}}
+
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project-1", nil)
+	mockDB.On("HasActiveSession", mock.Anything, chatID, 0).Return(true, nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(session, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, session.ID).Return(messages, nil)
+	mockDB.On("GetAssigneeMappings", mock.Anything, chatID, "project-1").Return([]db.AssigneeMapping(nil), nil)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetTaskDefaults", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+
+	mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask).Return("", nil)
+
+	expectedHash := analysisHash([]string{"Unknown Author, [0001-01-01 00:00:00]: починить логин"}, nil, "")
+	cached := []byte(`{"task":{"title":"Починить логин","priority":2},"missing_details":["срок"]}`)
+	mockDB.On("GetAnalysisCache", mock.Anything, session.ID, expectedHash, mock.Anything).Return(cached, nil)
+	mockDB.On("SaveDraftTask", mock.Anything, mock.MatchedBy(func(input db.DraftTaskInput) bool {
+		return input.Title == "Починить логин" && assert.ObjectsAreEqual(input.MissingDetails, []string{"срок"})
+	})).Return(nil)
+	mockDB.On("DeleteDraftRevisions", mock.Anything, session.ID, 1).Return(nil)
+	mockDB.On("AddDraftRevision", mock.Anything, mock.Anything, "").Return(1, nil)
+
+	mockAI := new(MockAIClient)
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTasks", mock.Anything, "project-1").Return([]*todoist.TaskResponse{}, nil)
+	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI, quota.Limits{PerChat: 3}, admin.Users{})
+	result := cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+	assert.Contains(t, result.Text, "Починить логин")
+	mockAI.AssertNotCalled(t, "AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "ReserveTaskAnalysis", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertNotCalled(t, "SaveAnalysisCache", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Tests that changing the chat's conventions does not reuse an older analysis
+func TestAnalysisHash_DependsOnChatPrompt(t *testing.T) {
+	texts := []string{"починить логин"}
+	assert.NotEqual(t, analysisHash(texts, nil, ""), analysisHash(texts, nil, "Заголовки на английском"))
+	assert.Equal(t, analysisHash(texts, nil, "a"), analysisHash(texts, nil, "a"))
+}
+
 // Tests the conversion of human-readable dates to ISO format (YYYY-MM-DD)
 func TestCreateTaskCommand_ConvertToDueISO(t *testing.T) {
 	// Create command with empty mocks
 	mockDB := new(MockDBManager)
 	mockAI := new(MockAIClient)
 	mockTodoist := new(MockTodoistClient)
-	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI)
+	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI, quota.Limits{}, admin.Users{})
 
 	// Test date conversions
 	today := time.Now().Format("2006-01-02")
@@ -275,7 +422,7 @@ func TestCreateTaskCommand_ConvertToDueISO(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			result := cmd.convertToDueISO(tc.input)
+			result := cmd.convertToDueISO(tc.input, time.Local)
 			if tc.name == "already ISO" {
 				assert.Contains(t, result, tc.expected)
 			} else {
@@ -285,6 +432,18 @@ func TestCreateTaskCommand_ConvertToDueISO(t *testing.T) {
 	}
 }
 
+// Tests that due dates in words are resolved to a date or a date with a time
+func TestConvertDueDate(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, moscow)
+
+	assert.Equal(t, "2026-10-18", ConvertDueDate("через 3 дня", now))
+	assert.Equal(t, "2026-12-31", ConvertDueDate("31 декабря", now))
+	assert.Equal(t, "2026-10-16T18:00:00+03:00", ConvertDueDate("пятница вечером", now))
+	assert.Equal(t, "когда-нибудь", ConvertDueDate("когда-нибудь", now))
+	assert.Equal(t, "16 октября (Пятница), 18:00", FormatDueDateForDisplay("2026-10-16T18:00:00+03:00", i18n.Russian))
+}
+
 // Tests the extraction of assignee information from message text
 // Checks mentions (@username), Russian phrases ("назначить", "ответственный"), and empty cases
 func TestCreateTaskCommand_ExtractAssignee(t *testing.T) {
@@ -292,7 +451,7 @@ func TestCreateTaskCommand_ExtractAssignee(t *testing.T) {
 	mockDB := new(MockDBManager)
 	mockAI := new(MockAIClient)
 	mockTodoist := new(MockTodoistClient)
-	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI)
+	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI, quota.Limits{}, admin.Users{})
 
 	testCases := []struct {
 		name     string
@@ -344,13 +503,13 @@ func TestFormatTaskType(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			assert.Equal(t, tc.expected, formatTaskType(tc.input))
+			assert.Equal(t, tc.expected, formatTaskType(tc.input, i18n.Russian))
 		})
 	}
 }
 
 func TestFormatMissingDetailsPrompt(t *testing.T) {
-	result := FormatMissingDetailsPrompt([]string{"Срок", "Риски", "Критерии готовности"})
+	result := FormatMissingDetailsPrompt([]string{"Срок", "Риски", "Критерии готовности"}, i18n.Russian)
 
 	assert.Equal(
 		t,
@@ -379,7 +538,7 @@ func TestFormatTaskPreviewSkipsEmptyFields(t *testing.T) {
 		Labels:       []string{"", "  "},
 	}
 
-	result := FormatTaskPreview(task, "", "", db.AssigneeSnapshot{}, "")
+	result := FormatTaskPreview(task, "", "", db.AssigneeSnapshot{}, "", i18n.Russian)
 
 	assert.NotContains(t, result, "*Срок выполнения:*")
 	assert.NotContains(t, result, "*Исполнитель:*")
@@ -387,6 +546,30 @@ func TestFormatTaskPreviewSkipsEmptyFields(t *testing.T) {
 	assert.Contains(t, result, "*Тип задачи:* Баг")
 }
 
+func TestFormatTaskPreviewInEnglish(t *testing.T) {
+	task := &ai.AnalyzedTask{
+		Title:    "Fix the bot",
+		TaskType: "epic",
+		Labels:   []string{"backend"},
+	}
+
+	result := FormatTaskPreview(task, "2026-10-16", "", db.AssigneeSnapshot{}, "", i18n.English)
+
+	assert.Contains(t, result, "*Title:* Fix the bot")
+	assert.Contains(t, result, "*Due:*")
+	assert.Contains(t, result, "*Task type:* Epic")
+	assert.Contains(t, result, "*Labels:* backend")
+	assert.NotContains(t, result, "Название")
+}
+
+func TestCreateInlineKeyboardInEnglish(t *testing.T) {
+	keyboard := CreateInlineKeyboard(42, i18n.English)
+
+	assert.Equal(t, "✅ Confirm", keyboard.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "✏️ Edit", keyboard.InlineKeyboard[0][1].Text)
+	assert.Equal(t, "📅 Tomorrow", keyboard.InlineKeyboard[2][1].Text)
+}
+
 func TestFormatTaskPreviewEscapesTelegramMarkdown(t *testing.T) {
 	task := &ai.AnalyzedTask{
 		Title:        "Починить preview_parser",
@@ -399,7 +582,7 @@ func TestFormatTaskPreviewEscapesTelegramMarkdown(t *testing.T) {
 	result := FormatTaskPreview(task, "", "", db.AssigneeSnapshot{
 		Name:  "Александр",
 		Email: "alex_zanozin@example.com",
-	}, "")
+	}, "", i18n.Russian)
 
 	assert.Contains(t, result, "preview\\_parser")
 	assert.Contains(t, result, "alex\\_zanozin@example.com")
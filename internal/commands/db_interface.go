@@ -2,7 +2,9 @@ package commands
 
 import (
 	"context"
+	"time"
 
+	"github.com/user/telegram-bot/internal/activity"
 	"github.com/user/telegram-bot/internal/db"
 	"github.com/user/telegram-bot/internal/tasklinks"
 )
@@ -10,25 +12,146 @@ import (
 type DBManager interface {
 	// Methods needed for the start_discussion command
 	GetTodoistProjectID(ctx context.Context, chatID int64) (string, error)
-	HasActiveSession(ctx context.Context, chatID int64) (bool, error)
-	StartSession(ctx context.Context, chatID int64, ownerID int64) (int, error)
+	// Sessions are per forum topic; threadID is 0 outside topics. A topic runs
+	// one unnamed session and any number of named ones.
+	HasActiveSession(ctx context.Context, chatID int64, threadID int) (bool, error)
+	StartSession(ctx context.Context, chatID int64, threadID int, name string, ownerID int64) (int, error)
 	IsSessionOwner(ctx context.Context, sessionID int, userID int64) (bool, error)
 
 	// Methods needed for the set_project command
 	SetTodoistProjectID(ctx context.Context, chatID int64, projectID string) error
+	SetDefaultSection(ctx context.Context, chatID int64, sectionID string) error
+	GetDefaultSection(ctx context.Context, chatID int64) (string, error)
 
 	// Methods needed for other commands
-	GetActiveSession(ctx context.Context, chatID int64) (*db.Session, error)
-	CloseSession(ctx context.Context, chatID int64) error
-	SaveMessage(ctx context.Context, chatID int64, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error
+	GetActiveSession(ctx context.Context, chatID int64, threadID int) (*db.Session, error)
+	GetNamedSession(ctx context.Context, chatID int64, threadID int, name string) (*db.Session, error)
+	ListActiveSessions(ctx context.Context, chatID int64, threadID int) ([]db.Session, error)
+	CloseSession(ctx context.Context, sessionID int) error
+	SaveMessage(ctx context.Context, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error
+	SaveSessionMessage(ctx context.Context, sessionID int, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error
+	GetMessageSession(ctx context.Context, chatID int64, messageID int) (int, error)
 	GetSessionMessages(ctx context.Context, sessionID int) ([]db.Message, error)
+	SaveAttachment(ctx context.Context, attachment db.Attachment) error
+	GetSessionAttachments(ctx context.Context, sessionID int) ([]db.Attachment, error)
+
+	// Pinned status message of a discussion
+	SetSessionNotice(ctx context.Context, sessionID int, messageID int) error
+	GetSessionNotice(ctx context.Context, sessionID int) (int, error)
+
+	// Discussion participants
+	AddSessionParticipant(ctx context.Context, sessionID int, userID int64, username, displayName string) error
+	GetSessionParticipants(ctx context.Context, sessionID int) ([]db.SessionParticipant, error)
+	SetSummonParticipants(ctx context.Context, chatID int64, enabled bool) error
+	SummonParticipantsEnabled(ctx context.Context, chatID int64) (bool, error)
+
+	// Discussion transcript in created tasks
+	SetTranscriptMode(ctx context.Context, chatID int64, mode string) error
+	GetTranscriptMode(ctx context.Context, chatID int64) (string, error)
 
 	// Methods for draft and created tasks
 	SaveDraftTask(ctx context.Context, input db.DraftTaskInput) error
 	GetDraftTask(ctx context.Context, sessionID int) (db.DraftTask, error)
 	DeleteDraftTask(ctx context.Context, sessionID int) error
+	SaveDraftAlternatives(ctx context.Context, sessionID int, drafts []db.DraftTaskInput) error
+	GetDraftAlternative(ctx context.Context, sessionID, option int) (db.DraftTaskInput, error)
+	AddDraftRevision(ctx context.Context, draft db.DraftTaskInput, instruction string) (int, error)
+	ListDraftRevisions(ctx context.Context, sessionID int) ([]db.DraftRevision, error)
+	DeleteDraftRevisions(ctx context.Context, sessionID, fromRevision int) error
 
-	SaveCreatedTask(ctx context.Context, task db.DraftTask, todoistTaskID, url string) error
+	// Bot messages whose replies edit a draft
+	SavePendingEdit(ctx context.Context, chatID int64, messageID int, sessionID int) error
+	GetPendingEdit(ctx context.Context, chatID int64, messageID int) (int, error)
+	DeletePendingEdit(ctx context.Context, chatID int64, messageID int) error
+	DeleteStalePendingEdits(ctx context.Context, createdBefore time.Time) (int64, error)
+
+	SaveCreatedTask(ctx context.Context, task db.DraftTask, todoistTaskID, url string) (db.CreatedTask, bool, error)
+	GetCreatedTask(ctx context.Context, sessionID int) (*db.CreatedTask, error)
 	ReplaceAssigneeMappings(ctx context.Context, chatID int64, projectID string, mappings []db.AssigneeMapping) error
 	GetAssigneeMappings(ctx context.Context, chatID int64, projectID string) ([]db.AssigneeMapping, error)
+	SaveAssigneeMapping(ctx context.Context, mapping db.AssigneeMapping) error
+
+	// Nudges about created tasks left without an assignee
+	ListTasksToNudge(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]db.TaskNudge, error)
+	MarkTaskNudged(ctx context.Context, id int) error
+	GetChatCreatedTask(ctx context.Context, chatID int64, id int) (*db.CreatedTask, error)
+	ListTaskChats(ctx context.Context, todoistTaskID string) ([]int64, error)
+	SetCreatedTaskAssignee(ctx context.Context, id int, assignee db.AssigneeSnapshot) error
+
+	// Summaries of discussions that ended without a task
+	SaveDecisionSummary(ctx context.Context, sessionID int, chatID int64, text string) error
+
+	// Methods for task quotas
+	ReserveTaskAnalysis(ctx context.Context, chatID, userID int64, sessionID int, since time.Time, chatLimit, userLimit int) (id, chatCount, userCount int, err error)
+	ReleaseTaskAnalysis(ctx context.Context, id int) error
+	CountTaskAnalyses(ctx context.Context, chatID, userID int64, since time.Time) (chatCount, userCount int, err error)
+	SetQuotaExempt(ctx context.Context, chatID int64, exempt bool) error
+	IsQuotaExempt(ctx context.Context, chatID int64) (bool, error)
+
+	// Voice previews
+	SetVoicePreviews(ctx context.Context, chatID int64, enabled bool) error
+	VoicePreviewsEnabled(ctx context.Context, chatID int64) (bool, error)
+
+	// Emoji acknowledgements of captured messages
+	SetReactionAck(ctx context.Context, chatID int64, enabled bool) error
+	ReactionAckEnabled(ctx context.Context, chatID int64) (bool, error)
+
+	// Chat lifecycle reported by Telegram
+	SetChatInactive(ctx context.Context, chatID int64, inactive bool) error
+	MigrateChat(ctx context.Context, fromChatID, toChatID int64) error
+
+	// Reply language chosen by a user
+	SetUserLanguage(ctx context.Context, userID int64, language string) error
+	GetUserLanguage(ctx context.Context, userID int64) (string, error)
+
+	// Quiet hours
+	SetQuietHours(ctx context.Context, chatID int64, window string) error
+	GetQuietHours(ctx context.Context, chatID int64) (string, error)
+	DeferMessage(ctx context.Context, chatID int64, text string) error
+	ListDeferredChats(ctx context.Context) ([]int64, error)
+	TakeDeferredMessages(ctx context.Context, chatID int64) ([]string, error)
+
+	// Delivery time of digests and nudges, chosen with the help of chat activity
+	SetDigestTime(ctx context.Context, chatID int64, clock string) error
+	GetDigestTime(ctx context.Context, chatID int64) (string, error)
+	MessageActivity(ctx context.Context, chatID int64, since time.Time, timezone string) ([]activity.Bucket, error)
+
+	// Time zone the chat reads and writes dates in
+	SetChatTimezone(ctx context.Context, chatID int64, zone string) error
+	GetChatTimezone(ctx context.Context, chatID int64) (string, error)
+
+	// Language of the chat's drafts, buttons and task confirmations
+	SetChatLanguage(ctx context.Context, chatID int64, language string) error
+	GetChatLanguage(ctx context.Context, chatID int64) (string, error)
+
+	// Priority display names
+	SetPriorityNames(ctx context.Context, chatID int64, names string) error
+	GetPriorityNames(ctx context.Context, chatID int64) (string, error)
+
+	// Task defaults applied to drafts
+	SetTaskDefaults(ctx context.Context, chatID int64, defaults string) error
+	GetTaskDefaults(ctx context.Context, chatID int64) (string, error)
+
+	// Team conventions added to the create_task and edit_task prompts of a chat
+	SetChatPrompt(ctx context.Context, chatID int64, name, text string) error
+	GetChatPrompt(ctx context.Context, chatID int64, name string) (string, error)
+
+	// Notifier plugins
+	AddChatNotifier(ctx context.Context, chatID int64, kind, target, secret string) (int, error)
+	ListChatNotifiers(ctx context.Context, chatID int64) ([]db.ChatNotifier, error)
+	RemoveChatNotifier(ctx context.Context, chatID int64, id int) (bool, error)
+
+	// Draft previews with live buttons
+	RecordPreviewMessage(ctx context.Context, sessionID int, chatID int64, messageID int, text string) error
+	ListStalePreviews(ctx context.Context, createdBefore time.Time, limit int) ([]db.PreviewMessage, error)
+	MarkPreviewCleaned(ctx context.Context, id int) error
+
+	// AI jobs kept until they finish, so a restart queues them again
+	SaveAIJob(ctx context.Context, job db.AIJob) (int, error)
+	DeleteAIJob(ctx context.Context, id int) error
+	ListAIJobs(ctx context.Context) ([]db.AIJob, error)
+
+	// AI analysis cache
+	GetAnalysisCache(ctx context.Context, sessionID int, hash string, since time.Time) ([]byte, error)
+	SaveAnalysisCache(ctx context.Context, sessionID int, hash string, payload []byte) error
 }
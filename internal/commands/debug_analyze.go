@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tasklinks"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// DebugAnalyzeCommand runs the discussion analysis without saving a draft or
+// spending quota and sends everything the model saw and answered as a file,
+// so bad extractions can be debugged without digging in the database
+type DebugAnalyzeCommand struct {
+	dbManager DBManager
+	aiClient  ai.Client
+	admins    admin.Users
+}
+
+func NewDebugAnalyzeCommand(dbManager DBManager, aiClient ai.Client, admins admin.Users) *DebugAnalyzeCommand {
+	return &DebugAnalyzeCommand{
+		dbManager: dbManager,
+		aiClient:  aiClient,
+		admins:    admins,
+	}
+}
+
+func (c *DebugAnalyzeCommand) Name() string {
+	return "debug_analyze"
+}
+
+func (c *DebugAnalyzeCommand) Description() string {
+	return "Прогнать анализ обсуждения и прислать промпт и ответ модели файлом (для администраторов)"
+}
+
+func (c *DebugAnalyzeCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+// debugAnalysisReport is the document sent by /debug_analyze
+type debugAnalysisReport struct {
+	SessionID     int                  `json:"session_id"`
+	Messages      int                  `json:"messages"`
+	SelectedLinks []tasklinks.TaskLink `json:"selected_links"`
+	ai.AnalysisTrace
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+func (c *DebugAnalyzeCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	lang := ReplyLanguage(context.Background(), c.dbManager, message)
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AdminOnlyCommand))
+		return &msg
+	}
+	if _, ok := c.aiClient.(ai.Tracer); !ok {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.DebugUnsupported))
+		return &msg
+	}
+
+	if _, err := c.dbManager.GetActiveSession(context.Background(), message.Chat.ID, topics.ThreadID(message)); err != nil {
+		if !errors.Is(err, db.ErrNoActiveSession) {
+			log.Printf("Error getting active session for debug analysis in chat %d: %v", message.Chat.ID, err)
+		}
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.SessionNoActive))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.DebugStarting))
+	return &msg
+}
+
+// ReplyDocument runs the traced analysis; Execute has already explained why
+// there is nothing to send when the sender is not an admin or no discussion is open
+func (c *DebugAnalyzeCommand) ReplyDocument(ctx context.Context, message *tgbotapi.Message) (*tgbotapi.DocumentConfig, error) {
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		return nil, nil
+	}
+	tracer, ok := c.aiClient.(ai.Tracer)
+	if !ok {
+		return nil, nil
+	}
+	session, err := c.dbManager.GetActiveSession(ctx, message.Chat.ID, topics.ThreadID(message))
+	if err != nil {
+		return nil, nil
+	}
+	ctx = ai.WithUsageScope(ctx, message.Chat.ID, session.ID)
+	ctx = ChatPromptContext(ctx, c.dbManager, message.Chat.ID, ai.PromptCreateTask)
+
+	messages, err := c.dbManager.GetSessionMessages(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages of session %d: %w", session.ID, err)
+	}
+	messageTexts := discussionTexts(messages)
+	selectedLinks := selectLinks(ctx, c.aiClient, messageTexts, buildLinkCandidates(messages))
+
+	trace, err := tracer.TraceAnalyzeDiscussion(ctx, messageTexts, selectedLinks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to trace analysis of session %d: %w", session.ID, err)
+	}
+	trace.Prompt = ai.Redact(trace.Prompt)
+
+	report := debugAnalysisReport{
+		SessionID:     session.ID,
+		Messages:      len(messageTexts),
+		SelectedLinks: selectedLinks,
+		AnalysisTrace: *trace,
+		GeneratedAt:   time.Now().UTC(),
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode analysis trace: %w", err)
+	}
+
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	result := i18n.T(lang, i18n.DebugParsed)
+	if trace.ParseError != "" {
+		result = fmt.Sprintf(i18n.T(lang, i18n.DebugParseError), trace.ParseError)
+	}
+	doc := tgbotapi.NewDocument(message.Chat.ID, tgbotapi.FileBytes{
+		Name:  fmt.Sprintf("analysis-session-%d-%s.json", session.ID, report.GeneratedAt.Format("20060102-150405")),
+		Bytes: data,
+	})
+	doc.Caption = fmt.Sprintf(i18n.T(lang, i18n.DebugCaption),
+		trace.Model, trace.Usage.TotalTokens, trace.Usage.PromptTokens, trace.Usage.CompletionTokens, result)
+	return &doc, nil
+}
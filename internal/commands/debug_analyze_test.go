@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+type tracerStub struct {
+	MockAIClient
+	trace    *ai.AnalysisTrace
+	messages []string
+}
+
+func (s *tracerStub) TraceAnalyzeDiscussion(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink) (*ai.AnalysisTrace, error) {
+	s.messages = messages
+	return s.trace, nil
+}
+
+func TestDebugAnalyzeCommand_NotAdmin(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	cmd := NewDebugAnalyzeCommand(mockDB, &tracerStub{}, admins)
+
+	message := CreateCommandMessage(100, "/debug_analyze")
+	response := cmd.Execute(message)
+	doc, err := cmd.ReplyDocument(context.Background(), message)
+
+	assert.Contains(t, response.Text, "только администраторам")
+	assert.NoError(t, err)
+	assert.Nil(t, doc)
+	mockDB.AssertNotCalled(t, "GetActiveSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDebugAnalyzeCommand_ClientWithoutTraces(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+
+	response := NewDebugAnalyzeCommand(new(MockDBManager), new(MockAIClient), admins).Execute(CreateCommandMessage(42, "/debug_analyze"))
+
+	assert.Contains(t, response.Text, "не поддерживает")
+}
+
+func TestDebugAnalyzeCommand_ReplyDocument(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, int64(42), 0).Return(&db.Session{ID: 7}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 7).Return([]db.Message{{Text: "логин падает, пишите ivan@example.com"}}, nil)
+	mockDB.On("GetChatPrompt", mock.Anything, mock.Anything, ai.PromptCreateTask).Return("", nil)
+	tracer := &tracerStub{trace: &ai.AnalysisTrace{
+		Model:      "test-model",
+		Prompt:     "Диалог: логин падает, пишите ivan@example.com",
+		RawOutput:  "not json",
+		ParseError: "no valid JSON found in response",
+		Usage:      ai.OpenRouterUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	}}
+
+	doc, err := NewDebugAnalyzeCommand(mockDB, tracer, admins).ReplyDocument(context.Background(), CreateCommandMessage(42, "/debug_analyze"))
+
+	assert.NoError(t, err)
+	assert.NotNil(t, doc)
+	assert.Len(t, tracer.messages, 1)
+	assert.Contains(t, doc.Caption, "токенов: 120")
+	assert.Contains(t, doc.Caption, "ошибка разбора")
+
+	var report map[string]any
+	assert.NoError(t, json.Unmarshal(doc.File.(tgbotapi.FileBytes).Bytes, &report))
+	assert.Equal(t, "Диалог: логин падает, пишите [email]", report["prompt"])
+	assert.Equal(t, "not json", report["raw_output"])
+	assert.EqualValues(t, 7, report["session_id"])
+	mockDB.AssertNotCalled(t, "SaveDraftTask", mock.Anything, mock.Anything)
+}
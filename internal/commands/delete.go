@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const deleteTimeout = 15 * time.Second
+
+// PendingDelete is a task deletion waiting for the user who asked for it
+type PendingDelete struct {
+	OwnerID int64
+	Task    *tracker.Task
+	// List is the /list page to show again once the task is gone, nil for /delete
+	List *TaskListQuery
+}
+
+type deleteKey struct {
+	chatID int64
+	userID int64
+}
+
+// DeleteStore keeps the deletion each user of a chat is asked to confirm
+type DeleteStore struct {
+	mu      sync.Mutex
+	pending map[deleteKey]*PendingDelete
+}
+
+func NewDeleteStore() *DeleteStore {
+	return &DeleteStore{pending: make(map[deleteKey]*PendingDelete)}
+}
+
+// Put replaces the pending deletion of the user in the chat
+func (s *DeleteStore) Put(chatID int64, d *PendingDelete) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[deleteKey{chatID: chatID, userID: d.OwnerID}] = d
+}
+
+// Take removes and returns the pending deletion of the task userID asked for.
+// A button of an older request, replaced since, finds nothing.
+func (s *DeleteStore) Take(chatID, userID int64, taskID string) (*PendingDelete, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := deleteKey{chatID: chatID, userID: userID}
+	d, ok := s.pending[key]
+	if !ok || d.Task.ID != taskID {
+		return nil, false
+	}
+	delete(s.pending, key)
+	return d, true
+}
+
+// DeleteCommand permanently deletes a task of the chat's tracker once the
+// user who asked confirms it with a button
+type DeleteCommand struct {
+	trackers  *tracker.Selector
+	dbManager DBManager
+	store     *DeleteStore
+}
+
+func NewDeleteCommand(todoistClient todoist.Client, dbManager DBManager, store *DeleteStore) *DeleteCommand {
+	return &DeleteCommand{trackers: tracker.Single(tracker.NewTodoist(todoistClient)), dbManager: dbManager, store: store}
+}
+
+// SetTrackers sets the trackers tasks are deleted from
+func (c *DeleteCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *DeleteCommand) Name() string {
+	return "delete"
+}
+
+func (c *DeleteCommand) Description() string {
+	return "Удалить задачу по ID или ссылке, с подтверждением: /delete <ссылка на задачу>"
+}
+
+func (c *DeleteCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	ctx, cancel := context.WithTimeout(context.Background(), deleteTimeout)
+	defer cancel()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" || message.From == nil {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.Usage), c.Description()))
+		return &msg
+	}
+	taskID, ok := ParseTaskReference(arg)
+	if !ok {
+		// Other trackers have their own keys, e.g. PROJ-12 in Jira
+		taskID = arg
+	}
+
+	task, err := c.Task(ctx, chatID, taskID)
+	if err != nil {
+		log.Printf("Error getting task %s to delete in chat %d: %v", taskID, chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.DeleteTaskNotFound))
+		return &msg
+	}
+
+	c.store.Put(chatID, &PendingDelete{OwnerID: message.From.ID, Task: task})
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.DeleteConfirm), task.Title))
+	msg.ReplyMarkup = DeleteConfirmKeyboard(message.From.ID, task, lang)
+	return &msg
+}
+
+// Task returns a task of the chat's tracker
+func (c *DeleteCommand) Task(ctx context.Context, chatID int64, taskID string) (*tracker.Task, error) {
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		return nil, err
+	}
+	return client.GetTask(ctx, taskID)
+}
+
+// Delete permanently deletes a task of the chat's tracker
+func (c *DeleteCommand) Delete(ctx context.Context, chatID int64, taskID string) error {
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		return err
+	}
+	return client.DeleteTask(ctx, taskID)
+}
+
+// DeleteConfirmKeyboard asks ownerID to confirm deleting the task;
+// "{action}:{owner_id}:{task_id}" lets the button check who pressed it
+func DeleteConfirmKeyboard(ownerID int64, task *tracker.Task, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	data := CallbackDataSeparator + strconv.FormatInt(ownerID, 10) + CallbackDataSeparator + task.ID
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(i18n.T(lang, i18n.ButtonDelete), truncateRunes(task.Title, listButtonTitleLimit)), CallbackDeleteConfirm+data),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonDeleteKeep), CallbackDeleteCancel+data),
+	))
+}
+
+// ParseDeleteData reads the owner and the task of a delete confirmation button
+func ParseDeleteData(data CallbackData) (int64, string, bool) {
+	ownerID, err := strconv.ParseInt(data.Arg(0), 10, 64)
+	taskID := data.Arg(1)
+	return ownerID, taskID, err == nil && taskID != ""
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+func TestDeleteCommand_Execute_AsksAuthor(t *testing.T) {
+	chatID := int64(123456789)
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTask", mock.Anything, "6Jf8VQXxpwv56VQ7").
+		Return(&todoist.TaskResponse{ID: "6Jf8VQXxpwv56VQ7", Content: "Старый баг"}, nil)
+	store := NewDeleteStore()
+
+	response := NewDeleteCommand(mockTodoist, new(MockDBManager), store).Execute(
+		CreateCommandMessage(chatID, "/delete", "https://app.todoist.com/app/task/6Jf8VQXxpwv56VQ7"))
+
+	assert.Contains(t, response.Text, "Удалить задачу «Старый баг»")
+	keyboard, ok := response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	assert.Equal(t, "delete_confirm:123456789:6Jf8VQXxpwv56VQ7", *keyboard.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "delete_cancel:123456789:6Jf8VQXxpwv56VQ7", *keyboard.InlineKeyboard[0][1].CallbackData)
+
+	pending, ok := store.Take(chatID, chatID, "6Jf8VQXxpwv56VQ7")
+	require.True(t, ok)
+	assert.Equal(t, "Старый баг", pending.Task.Title)
+	assert.Nil(t, pending.List)
+	mockTodoist.AssertNotCalled(t, "DeleteTask", mock.Anything, mock.Anything)
+}
+
+func TestDeleteCommand_Execute_UnknownTask(t *testing.T) {
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTask", mock.Anything, "missing1").Return(nil, errors.New("404"))
+
+	response := NewDeleteCommand(mockTodoist, new(MockDBManager), NewDeleteStore()).Execute(CreateCommandMessage(1, "/delete", "missing1"))
+
+	assert.Contains(t, response.Text, "Задача не найдена")
+	assert.Nil(t, response.ReplyMarkup)
+}
+
+func TestDeleteStore_TakeChecksOwnerAndTask(t *testing.T) {
+	store := NewDeleteStore()
+	store.Put(1, &PendingDelete{OwnerID: 10, Task: &tracker.Task{ID: "a"}})
+	// A newer request replaces the older one of the same user
+	store.Put(1, &PendingDelete{OwnerID: 10, Task: &tracker.Task{ID: "b"}})
+
+	_, ok := store.Take(1, 11, "b")
+	assert.False(t, ok)
+	_, ok = store.Take(1, 10, "a")
+	assert.False(t, ok)
+	pending, ok := store.Take(1, 10, "b")
+	require.True(t, ok)
+	assert.Equal(t, "b", pending.Task.ID)
+	_, ok = store.Take(1, 10, "b")
+	assert.False(t, ok)
+}
+
+func TestParseDeleteData(t *testing.T) {
+	ownerID, taskID, ok := ParseDeleteData(ParseCallbackData("delete_confirm:42:PROJ-12"))
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), ownerID)
+	assert.Equal(t, "PROJ-12", taskID)
+
+	_, _, ok = ParseDeleteData(ParseCallbackData("delete_confirm:someone:PROJ-12"))
+	assert.False(t, ok)
+	_, _, ok = ParseDeleteData(ParseCallbackData("delete_confirm:42"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/activity"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quiethours"
+)
+
+// activityLookback is how far back /digest_time suggest looks at chat messages
+const activityLookback = 28 * 24 * time.Hour
+
+// DigestTimeCommand sets when digests and nudges reach the chat and suggests
+// a time from the chat's activity
+type DigestTimeCommand struct {
+	dbManager DBManager
+}
+
+func NewDigestTimeCommand(dbManager DBManager) *DigestTimeCommand {
+	return &DigestTimeCommand{dbManager: dbManager}
+}
+
+func (c *DigestTimeCommand) Name() string {
+	return "digest_time"
+}
+
+func (c *DigestTimeCommand) Description() string {
+	return "Время сводок и напоминаний: /digest_time 09:30, off или suggest — подобрать по активности чата"
+}
+
+func (c *DigestTimeCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	usage := i18n.T(lang, i18n.DigestUsage)
+
+	args := strings.Fields(message.CommandArguments())
+	switch {
+	case len(args) == 0:
+		clock, err := c.dbManager.GetDigestTime(ctx, chatID)
+		if err != nil {
+			log.Printf("Error getting digest time for chat %d: %v", chatID, err)
+		}
+		text := i18n.T(lang, i18n.DigestUnset)
+		if clock != "" {
+			text = fmt.Sprintf(i18n.T(lang, i18n.DigestAt), clock, ChatLocation(ctx, c.dbManager, chatID))
+		}
+		text += "\n\n" + usage
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	case len(args) == 1 && args[0] == "off":
+		return c.set(ctx, chatID, lang, "", i18n.T(lang, i18n.DigestReset))
+	case args[0] == "suggest" && (len(args) == 1 || len(args) == 2 && args[1] == "apply"):
+		return c.suggest(ctx, chatID, lang, len(args) == 2)
+	case len(args) == 1:
+		minute, err := quiethours.ParseClock(args[0])
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.DigestBadTime)+" "+usage)
+			return &msg
+		}
+		clock := quiethours.FormatClock(minute)
+		return c.set(ctx, chatID, lang, clock, fmt.Sprintf(i18n.T(lang, i18n.DigestSet), clock, ChatLocation(ctx, c.dbManager, chatID)))
+	default:
+		msg := tgbotapi.NewMessage(chatID, usage)
+		return &msg
+	}
+}
+
+func (c *DigestTimeCommand) set(ctx context.Context, chatID int64, lang i18n.Lang, clock, text string) *tgbotapi.MessageConfig {
+	if err := c.dbManager.SetDigestTime(ctx, chatID, clock); err != nil {
+		log.Printf("Error setting digest time for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	return &msg
+}
+
+// suggest shows the activity heatmap of the chat and the busiest hour outside
+// quiet hours; apply also stores that hour as the digest time
+func (c *DigestTimeCommand) suggest(ctx context.Context, chatID int64, lang i18n.Lang, apply bool) *tgbotapi.MessageConfig {
+	loc := ChatLocation(ctx, c.dbManager, chatID)
+	buckets, err := c.dbManager.MessageActivity(ctx, chatID, time.Now().Add(-activityLookback), loc.String())
+	if err != nil {
+		log.Printf("Error getting message activity for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.DigestActivityFailed))
+		return &msg
+	}
+	heatmap := activity.FromBuckets(buckets)
+
+	var skip func(hour int) bool
+	if stored, err := c.dbManager.GetQuietHours(ctx, chatID); err != nil {
+		log.Printf("Error getting quiet hours for chat %d: %v", chatID, err)
+	} else if window, err := quiethours.Parse(stored); err == nil {
+		skip = func(hour int) bool { return window.ContainsMinute(hour * 60) }
+	}
+
+	hour, ok := heatmap.BestHour(skip)
+	if !ok {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.DigestTooLittleData), activity.MinMessages))
+		return &msg
+	}
+	clock := quiethours.FormatClock(hour * 60)
+
+	text := fmt.Sprintf(i18n.T(lang, i18n.DigestActivity)+"\n```\n%s\n```\n", loc, heatmap.Total(), heatmap.Render())
+	if apply {
+		if err := c.dbManager.SetDigestTime(ctx, chatID, clock); err != nil {
+			log.Printf("Error setting digest time for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		text += fmt.Sprintf(i18n.T(lang, i18n.DigestApplied), clock)
+	} else {
+		text += fmt.Sprintf(i18n.T(lang, i18n.DigestSuggested), clock)
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	return &msg
+}
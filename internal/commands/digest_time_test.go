@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/activity"
+)
+
+func TestDigestTimeCommand_Execute(t *testing.T) {
+	chatID := int64(123456789)
+	busyEvening := []activity.Bucket{
+		{Weekday: time.Monday, Hour: 10, Messages: 15},
+		{Weekday: time.Tuesday, Hour: 23, Messages: 30},
+	}
+
+	t.Run("stores normalized time", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetDigestTime", mock.Anything, chatID, "09:05").Return(nil)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+
+		response := NewDigestTimeCommand(mockDB).Execute(CreateCommandMessage(chatID, "/digest_time", "9:05"))
+
+		assert.Contains(t, response.Text, "09:05")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid time", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewDigestTimeCommand(mockDB).Execute(CreateCommandMessage(chatID, "/digest_time", "утром"))
+
+		assert.Contains(t, response.Text, "Не понял время")
+		mockDB.AssertNotCalled(t, "SetDigestTime", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("suggests busiest hour outside quiet hours", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("Asia/Yekaterinburg", nil)
+		mockDB.On("MessageActivity", mock.Anything, chatID, mock.Anything, "Asia/Yekaterinburg").Return(busyEvening, nil)
+		mockDB.On("GetQuietHours", mock.Anything, chatID).Return("22:00-08:00", nil)
+
+		response := NewDigestTimeCommand(mockDB).Execute(CreateCommandMessage(chatID, "/digest_time", "suggest"))
+
+		assert.Contains(t, response.Text, "с 10:00")
+		assert.Contains(t, response.Text, "Пн")
+		assert.Contains(t, response.Text, "(Asia/Yekaterinburg)")
+		mockDB.AssertNotCalled(t, "SetDigestTime", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("applies suggestion", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+		mockDB.On("MessageActivity", mock.Anything, chatID, mock.Anything, "Europe/Moscow").Return(busyEvening, nil)
+		mockDB.On("GetQuietHours", mock.Anything, chatID).Return("", nil)
+		mockDB.On("SetDigestTime", mock.Anything, chatID, "23:00").Return(nil)
+
+		response := NewDigestTimeCommand(mockDB).Execute(CreateCommandMessage(chatID, "/digest_time", "suggest apply"))
+
+		assert.Contains(t, response.Text, "теперь будут приходить")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("needs enough messages", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+		mockDB.On("MessageActivity", mock.Anything, chatID, mock.Anything, "Europe/Moscow").Return([]activity.Bucket{{Weekday: time.Monday, Hour: 10, Messages: 3}}, nil)
+		mockDB.On("GetQuietHours", mock.Anything, chatID).Return("", nil)
+
+		response := NewDigestTimeCommand(mockDB).Execute(CreateCommandMessage(chatID, "/digest_time", "suggest"))
+
+		assert.Contains(t, response.Text, "мало данных")
+	})
+}
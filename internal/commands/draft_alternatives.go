@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// alternativeDescriptionRunes is how much of each description the choice shows
+const alternativeDescriptionRunes = 300
+
+// ErrDraftAlreadyCreated is returned when an alternative is chosen for a
+// session whose task already exists
+var ErrDraftAlreadyCreated = errors.New("task of the session is already created")
+
+// alternativesMessage offers the drafts to choose from; the first one is
+// already saved as the session draft
+func (c *CreateTaskCommand) alternativesMessage(ctx context.Context, chatID int64, sessionID int, drafts []db.DraftTaskInput, defaultsNote string, duplicate *tracker.Task) *tgbotapi.MessageConfig {
+	names := ChatPriorityNames(ctx, c.dbManager, chatID)
+	lang := ChatLanguage(ctx, c.dbManager, chatID)
+	tasks := make([]*ai.AnalyzedTask, 0, len(drafts))
+	for _, draft := range drafts {
+		task := DraftInputTask(draft)
+		ApplyPriorityNames(task, names)
+		tasks = append(tasks, task)
+	}
+
+	responseText := fmt.Sprintf(i18n.T(lang, i18n.AlternativesReady), len(drafts)) + "\n\n"
+	responseText += FormatDraftAlternatives(tasks, lang)
+	if defaultsNote != "" {
+		responseText += "\n\n" + defaultsNote
+	}
+	if duplicate != nil {
+		responseText += "\n\n" + FormatDuplicateWarning(duplicate, lang)
+	}
+	responseText += "\n\n" + i18n.T(lang, i18n.AlternativesChoose)
+
+	msg := tgbotapi.NewMessage(chatID, responseText)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = DraftAlternativesKeyboard(sessionID, len(drafts), lang)
+	return &msg
+}
+
+// FormatDraftAlternatives lists the drafts by title, due date, priority and
+// the start of the description
+func FormatDraftAlternatives(tasks []*ai.AnalyzedTask, lang i18n.Lang) string {
+	parts := make([]string, 0, len(tasks))
+	for i, task := range tasks {
+		var b strings.Builder
+		fmt.Fprintf(&b, i18n.T(lang, i18n.AlternativesOption)+"\n", i+1, escapeTelegramMarkdown(task.Title))
+		if due := FormatDueDateForDisplay(task.DueDate, lang); due != "" {
+			fmt.Fprintf(&b, i18n.T(lang, i18n.AlternativesDue)+"\n", escapeTelegramMarkdown(due))
+		}
+		if task.PriorityText != "" {
+			fmt.Fprintf(&b, i18n.T(lang, i18n.AlternativesPriority)+"\n", escapeTelegramMarkdown(task.PriorityText))
+		}
+		if description := strings.TrimSpace(task.Description); description != "" {
+			b.WriteString(escapeTelegramMarkdown(truncateRunes(description, alternativeDescriptionRunes)))
+		}
+		parts = append(parts, strings.TrimSpace(b.String()))
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// DraftAlternativesKeyboard has a button per alternative and the cancel button
+func DraftAlternativesKeyboard(sessionID, count int, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	var options []tgbotapi.InlineKeyboardButton
+	for option := 1; option <= count; option++ {
+		data := fmt.Sprintf("%s%s%d%s%d", CallbackDraftOption, CallbackDataSeparator, sessionID, CallbackDataSeparator, option)
+		options = append(options, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(i18n.T(lang, i18n.ButtonAlternative), option), data))
+	}
+	cancel := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancelCreation), fmt.Sprintf("%s%s%d", CallbackCancel, CallbackDataSeparator, sessionID))
+	return tgbotapi.NewInlineKeyboardMarkup(options, tgbotapi.NewInlineKeyboardRow(cancel))
+}
+
+// SelectDraftAlternative makes the chosen alternative the session draft and
+// returns its preview with the usual confirm, edit and cancel buttons
+func SelectDraftAlternative(ctx context.Context, dbManager DBManager, chatID int64, sessionID, option int) (*tgbotapi.MessageConfig, error) {
+	if existing, err := dbManager.GetCreatedTask(ctx, sessionID); err == nil && existing != nil {
+		return nil, ErrDraftAlreadyCreated
+	}
+
+	draft, err := dbManager.GetDraftAlternative(ctx, sessionID, option)
+	if err != nil {
+		return nil, err
+	}
+	if err := dbManager.SaveDraftTask(ctx, draft); err != nil {
+		return nil, err
+	}
+	lang := ChatLanguage(ctx, dbManager, chatID)
+	RecordDraftRevision(ctx, dbManager, draft, fmt.Sprintf(i18n.T(lang, i18n.RevisionAlternative), option))
+
+	task := DraftInputTask(draft)
+	ApplyPriorityNames(task, ChatPriorityNames(ctx, dbManager, chatID))
+
+	text := fmt.Sprintf(i18n.T(lang, i18n.PreviewAlternativeChosen), option) + "\n\n"
+	text += FormatTaskPreview(task, draft.DueISO, draft.AssigneeNote, draft.Assignee, i18n.T(lang, i18n.PreviewMissingHint), lang)
+	text += "\n\n" + i18n.T(lang, i18n.PreviewChooseAction)
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = EditedDraftKeyboard(sessionID, lang)
+	return &msg, nil
+}
+
+// DraftInputTask is the task of a draft before it is saved
+func DraftInputTask(draft db.DraftTaskInput) *ai.AnalyzedTask {
+	return &ai.AnalyzedTask{
+		Title:          draft.Title,
+		Description:    draft.Description,
+		DueDate:        draft.DueISO,
+		Priority:       draft.Priority,
+		AssigneeNote:   draft.AssigneeNote,
+		Labels:         draft.Labels,
+		TaskType:       draft.TaskType,
+		MissingDetails: draft.MissingDetails,
+		SelectedLinks:  draft.SelectedLinks,
+		TaskFields:     draft.Fields,
+	}
+}
@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quota"
+	"github.com/user/telegram-bot/internal/tasklinks"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// alternativesAIClient is a MockAIClient that also samples draft alternatives
+type alternativesAIClient struct {
+	MockAIClient
+}
+
+func (m *alternativesAIClient) AnalyzeDiscussionAlternatives(ctx context.Context, messages []string, selectedLinks []tasklinks.TaskLink, count int) ([]*ai.AnalyzedTask, error) {
+	args := m.Called(ctx, messages, selectedLinks, count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*ai.AnalyzedTask), args.Error(1)
+}
+
+func TestCreateTaskCommand_Execute_OffersAlternatives(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockAI := new(alternativesAIClient)
+	mockTodoist := new(MockTodoistClient)
+	cmd := NewCreateTaskCommand(mockTodoist, mockDB, mockAI, quota.Limits{}, admin.Users{})
+	cmd.SetDraftAlternatives(2)
+
+	chatID := int64(123)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	mockDB.On("HasActiveSession", mock.Anything, chatID, 0).Return(true, nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 42, ChatID: chatID, Status: "open", OwnerID: 123}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 42).Return([]db.Message{
+		{ChatID: chatID, SessionID: sql.NullInt32{Int32: 42, Valid: true}, MessageID: 1, Text: "Логин не работает после обновления"},
+	}, nil)
+	mockDB.On("GetAnalysisCache", mock.Anything, 42, mock.Anything, mock.Anything).Return(nil, nil)
+	mockDB.On("SaveAnalysisCache", mock.Anything, 42, mock.Anything, mock.Anything).Return(nil)
+	mockDB.On("GetChatPrompt", mock.Anything, chatID, ai.PromptCreateTask).Return("", nil)
+	mockDB.On("GetAssigneeMappings", mock.Anything, chatID, "project123").Return([]db.AssigneeMapping(nil), nil)
+	mockDB.On("GetTaskDefaults", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("", nil)
+	mockTodoist.On("GetTasks", mock.Anything, "project123").Return([]*todoist.TaskResponse{}, nil)
+
+	mockAI.On("AnalyzeDiscussionAlternatives", mock.Anything, mock.Anything, []tasklinks.TaskLink{}, 2).Return([]*ai.AnalyzedTask{
+		{Title: "Починить логин", Priority: 3},
+		{Title: "Откатить обновление авторизации", Priority: 4},
+	}, nil)
+	mockDB.On("SaveDraftTask", mock.Anything, mock.MatchedBy(func(input db.DraftTaskInput) bool {
+		return input.SessionID == 42 && input.Title == "Починить логин"
+	})).Return(nil)
+	mockDB.On("DeleteDraftRevisions", mock.Anything, 42, 1).Return(nil)
+	mockDB.On("AddDraftRevision", mock.Anything, mock.Anything, "").Return(1, nil)
+	mockDB.On("SaveDraftAlternatives", mock.Anything, 42, mock.MatchedBy(func(drafts []db.DraftTaskInput) bool {
+		return len(drafts) == 2 && drafts[0].Title == "Починить логин" && drafts[1].Title == "Откатить обновление авторизации"
+	})).Return(nil)
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/create_task"))
+
+	assert.Contains(t, response.Text, "Готово 2 варианта черновика")
+	assert.Contains(t, response.Text, "*Вариант 2.* Откатить обновление авторизации")
+	assert.Equal(t, DraftAlternativesKeyboard(42, 2, i18n.Default), response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup))
+	mockAI.AssertNotCalled(t, "AnalyzeDiscussion", mock.Anything, mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
+
+func TestFormatDraftAlternatives(t *testing.T) {
+	text := FormatDraftAlternatives([]*ai.AnalyzedTask{
+		{Title: "Починить логин", DueDate: "2026-10-20", PriorityText: "высокий", Description: "Пользователи не могут войти"},
+		{Title: "Разобраться с SSO_провайдером"},
+	}, i18n.Russian)
+
+	assert.Contains(t, text, "*Вариант 1.* Починить логин\nСрок: 20 октября (Вторник)\nПриоритет: высокий\nПользователи не могут войти")
+	assert.Contains(t, text, "*Вариант 2.* Разобраться с SSO\\_провайдером")
+
+	english := FormatDraftAlternatives([]*ai.AnalyzedTask{{Title: "Fix login", DueDate: "2026-10-20"}}, i18n.English)
+	assert.Contains(t, english, "*Option 1.* Fix login\nDue: October 20 (Tuesday)")
+}
+
+func TestDraftAlternativesKeyboard(t *testing.T) {
+	keyboard := DraftAlternativesKeyboard(7, 2, i18n.Default)
+
+	assert.Len(t, keyboard.InlineKeyboard, 2)
+	assert.Equal(t, "draft_option:7:1", *keyboard.InlineKeyboard[0][0].CallbackData)
+	assert.Equal(t, "draft_option:7:2", *keyboard.InlineKeyboard[0][1].CallbackData)
+	assert.Equal(t, "cancel_task:7", *keyboard.InlineKeyboard[1][0].CallbackData)
+}
+
+func TestSelectDraftAlternative(t *testing.T) {
+	chatID := int64(100)
+	draft := db.DraftTaskInput{SessionID: 7, Title: "Разобраться с SSO", Priority: 3}
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetCreatedTask", mock.Anything, 7).Return(nil, nil)
+	mockDB.On("GetDraftAlternative", mock.Anything, 7, 2).Return(draft, nil)
+	mockDB.On("SaveDraftTask", mock.Anything, draft).Return(nil)
+	mockDB.On("AddDraftRevision", mock.Anything, draft, "выбран вариант 2").Return(2, nil)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("", nil)
+
+	msg, err := SelectDraftAlternative(context.Background(), mockDB, chatID, 7, 2)
+
+	assert.NoError(t, err)
+	assert.Contains(t, msg.Text, "Выбран вариант 2")
+	assert.Contains(t, msg.Text, "*Название:* Разобраться с SSO")
+	assert.Equal(t, EditedDraftKeyboard(7, i18n.Russian), msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup))
+	mockDB.AssertExpectations(t)
+}
+
+func TestSelectDraftAlternative_TaskAlreadyCreated(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("GetCreatedTask", mock.Anything, 7).Return(&db.CreatedTask{TodoistTaskID: "123"}, nil)
+
+	_, err := SelectDraftAlternative(context.Background(), mockDB, 100, 7, 1)
+
+	assert.ErrorIs(t, err, ErrDraftAlreadyCreated)
+	mockDB.AssertNotCalled(t, "SaveDraftTask", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/taskfields"
+)
+
+const (
+	draftHistoryTimeout = 10 * time.Second
+	// maxHistoryInstructionRunes keeps long edit instructions from flooding the history
+	maxHistoryInstructionRunes = 120
+)
+
+// ErrNothingToUndo is returned when the draft has no edit to roll back
+var ErrNothingToUndo = errors.New("draft has no edit to undo")
+
+// EditedDraftKeyboard is the preview keyboard of a draft with an edit to undo
+func EditedDraftKeyboard(sessionID int, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	keyboard := CreateInlineKeyboard(sessionID, lang)
+	undo := tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonUndoEdit), fmt.Sprintf("%s%s%d", CallbackUndoEdit, CallbackDataSeparator, sessionID))
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, tgbotapi.NewInlineKeyboardRow(undo))
+	return keyboard
+}
+
+// StartDraftHistory records a new analysis of the discussion as the first
+// version of the draft, dropping the history of the previous one
+func StartDraftHistory(ctx context.Context, dbManager DBManager, draft db.DraftTaskInput) {
+	if err := dbManager.DeleteDraftRevisions(ctx, draft.SessionID, 1); err != nil {
+		log.Printf("Error clearing draft history of session %d: %v", draft.SessionID, err)
+		return
+	}
+	RecordDraftRevision(ctx, dbManager, draft, "")
+}
+
+// RecordDraftRevision adds a saved draft to its history; the draft itself is
+// already saved, so a failure only costs the undo
+func RecordDraftRevision(ctx context.Context, dbManager DBManager, draft db.DraftTaskInput, instruction string) {
+	if _, err := dbManager.AddDraftRevision(ctx, draft, instruction); err != nil {
+		log.Printf("Error recording draft revision of session %d: %v", draft.SessionID, err)
+	}
+}
+
+// UndoDraftEdit rolls the draft back to the version before the last edit and
+// returns it with whether there is an earlier edit to undo as well
+func UndoDraftEdit(ctx context.Context, dbManager DBManager, sessionID int) (db.DraftTaskInput, bool, error) {
+	revisions, err := dbManager.ListDraftRevisions(ctx, sessionID)
+	if err != nil {
+		return db.DraftTaskInput{}, false, err
+	}
+	if len(revisions) < 2 {
+		return db.DraftTaskInput{}, false, ErrNothingToUndo
+	}
+
+	last, previous := revisions[len(revisions)-1], revisions[len(revisions)-2]
+	if err := dbManager.SaveDraftTask(ctx, previous.Draft); err != nil {
+		return db.DraftTaskInput{}, false, err
+	}
+	if err := dbManager.DeleteDraftRevisions(ctx, sessionID, last.Revision); err != nil {
+		return db.DraftTaskInput{}, false, err
+	}
+	return previous.Draft, len(revisions) > 2, nil
+}
+
+// DraftHistoryCommand shows the versions of the discussion draft and what each edit changed
+type DraftHistoryCommand struct {
+	dbManager DBManager
+}
+
+func NewDraftHistoryCommand(dbManager DBManager) *DraftHistoryCommand {
+	return &DraftHistoryCommand{dbManager: dbManager}
+}
+
+func (c *DraftHistoryCommand) Name() string {
+	return "draft_history"
+}
+
+func (c *DraftHistoryCommand) Description() string {
+	return "История правок черновика задачи"
+}
+
+func (c *DraftHistoryCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), draftHistoryTimeout)
+	defer cancel()
+	chatID := message.Chat.ID
+	name := SessionName(message)
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	session, err := FindSession(ctx, c.dbManager, message)
+	if errors.Is(err, db.ErrNoActiveSession) {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.SessionNone), sessionTitle(name)))
+		return &msg
+	}
+	if err != nil {
+		log.Printf("Error getting session for draft history in chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ErrorSessionLoad))
+		return &msg
+	}
+
+	revisions, err := c.dbManager.ListDraftRevisions(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error listing draft revisions of session %d: %v", session.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.HistoryLoadFailed))
+		return &msg
+	}
+	if len(revisions) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.HistoryNoDraft))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(chatID, FormatDraftHistory(revisions, ChatPriorityNames(ctx, c.dbManager, chatID), ChatLocation(ctx, c.dbManager, chatID), lang))
+	return &msg
+}
+
+// FormatDraftHistory lists the versions of a draft, each edit with its
+// instruction and the fields it changed, times in the chat time zone loc
+func FormatDraftHistory(revisions []db.DraftRevision, names priority.Names, loc *time.Location, lang i18n.Lang) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.HistoryHeader) + "\n")
+	for i, r := range revisions {
+		when := r.CreatedAt.In(loc).Format("02.01 15:04")
+		instruction := i18n.T(lang, i18n.HistoryFromDiscussion)
+		if r.Instruction != "" {
+			instruction = "«" + truncateRunes(r.Instruction, maxHistoryInstructionRunes) + "»"
+		}
+		fmt.Fprintf(&sb, "\n%d. %s — %s\n", r.Revision, when, instruction)
+		if i == 0 {
+			continue
+		}
+		changes := DiffDrafts(revisions[i-1].Draft, r.Draft, names, lang)
+		if len(changes) == 0 {
+			sb.WriteString("   " + i18n.T(lang, i18n.HistoryNoChanges) + "\n")
+		}
+		for _, change := range changes {
+			fmt.Fprintf(&sb, "   • %s\n", change)
+		}
+	}
+	if len(revisions) > 1 {
+		sb.WriteString("\n" + fmt.Sprintf(i18n.T(lang, i18n.HistoryUndoHint), i18n.T(lang, i18n.ButtonUndoEdit)))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// DiffDrafts describes the fields that differ between two versions of a
+// draft; long texts are only reported as changed
+func DiffDrafts(before, after db.DraftTaskInput, names priority.Names, lang i18n.Lang) []string {
+	var changes []string
+	change := func(label, from, to string) {
+		if from == to {
+			return
+		}
+		if from == "" {
+			from = i18n.T(lang, i18n.DiffNone)
+		}
+		if to == "" {
+			to = i18n.T(lang, i18n.DiffNone)
+		}
+		changes = append(changes, fmt.Sprintf("%s: %s → %s", label, from, to))
+	}
+	changed := func(label string) {
+		changes = append(changes, fmt.Sprintf(i18n.T(lang, i18n.DiffChanged), label))
+	}
+
+	change(i18n.T(lang, i18n.PreviewTitle), before.Title, after.Title)
+	if strings.TrimSpace(before.Description) != strings.TrimSpace(after.Description) {
+		changed(i18n.T(lang, i18n.PreviewDescription))
+	}
+	change(i18n.T(lang, i18n.DiffDue), FormatDueDateForDisplay(before.DueISO, lang), FormatDueDateForDisplay(after.DueISO, lang))
+	change(i18n.T(lang, i18n.PreviewPriority), priorityDisplay(before.Priority, names), priorityDisplay(after.Priority, names))
+	change(i18n.T(lang, i18n.PreviewTaskType), formatTaskType(before.TaskType, lang), formatTaskType(after.TaskType, lang))
+	change(i18n.T(lang, i18n.PreviewLabels), strings.Join(cleanLabels(before.Labels), ", "), strings.Join(cleanLabels(after.Labels), ", "))
+	change(i18n.T(lang, i18n.PreviewAssignee), FormatAssigneeForPreview(before.AssigneeNote, before.Assignee), FormatAssigneeForPreview(after.AssigneeNote, after.Assignee))
+	if len(before.SelectedLinks) != len(after.SelectedLinks) {
+		linkCount := i18n.T(lang, i18n.DiffLinkCount)
+		change(i18n.T(lang, i18n.DiffLinks), fmt.Sprintf(linkCount, len(before.SelectedLinks)), fmt.Sprintf(linkCount, len(after.SelectedLinks)))
+	}
+	for _, field := range taskfields.KnownDefinitions() {
+		if before.Fields.Value(field.Key) != after.Fields.Value(field.Key) {
+			changed(field.Label)
+		}
+	}
+	return changes
+}
+
+func priorityDisplay(p int, names priority.Names) string {
+	level := priority.FromTodoist(p)
+	if !level.Valid() {
+		return ""
+	}
+	return names.Display(level)
+}
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/taskfields"
+)
+
+func TestUndoDraftEdit_RestoresPreviousVersion(t *testing.T) {
+	first := db.DraftTaskInput{SessionID: 7, Title: "Починить логин", Priority: 2}
+	second := db.DraftTaskInput{SessionID: 7, Title: "Починить логин", Priority: 4}
+	third := db.DraftTaskInput{SessionID: 7, Title: "Починить SSO", Priority: 4}
+	mockDB := new(MockDBManager)
+	mockDB.On("ListDraftRevisions", mock.Anything, 7).Return([]db.DraftRevision{
+		{SessionID: 7, Revision: 1, Draft: first},
+		{SessionID: 7, Revision: 2, Instruction: "срочно", Draft: second},
+		{SessionID: 7, Revision: 3, Instruction: "переименуй", Draft: third},
+	}, nil)
+	mockDB.On("SaveDraftTask", mock.Anything, second).Return(nil)
+	mockDB.On("DeleteDraftRevisions", mock.Anything, 7, 3).Return(nil)
+
+	draft, canUndoMore, err := UndoDraftEdit(context.Background(), mockDB, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, second, draft)
+	assert.True(t, canUndoMore)
+	mockDB.AssertExpectations(t)
+}
+
+func TestUndoDraftEdit_NothingToUndo(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("ListDraftRevisions", mock.Anything, 7).Return([]db.DraftRevision{
+		{SessionID: 7, Revision: 1, Draft: db.DraftTaskInput{SessionID: 7, Title: "Починить логин"}},
+	}, nil)
+
+	_, _, err := UndoDraftEdit(context.Background(), mockDB, 7)
+
+	assert.ErrorIs(t, err, ErrNothingToUndo)
+	mockDB.AssertNotCalled(t, "SaveDraftTask", mock.Anything, mock.Anything)
+}
+
+func TestDiffDrafts(t *testing.T) {
+	before := db.DraftTaskInput{Title: "Починить логин", Priority: 2, Labels: []string{"auth"}, Description: "Не входит"}
+	after := db.DraftTaskInput{
+		Title:       "Починить логин",
+		Priority:    4,
+		DueISO:      "2026-10-20",
+		Labels:      []string{"auth", "urgent"},
+		Description: "Не входит после обновления",
+		Fields:      taskfields.TaskFields{ReproductionSteps: "1. Открыть страницу входа"},
+	}
+
+	changes := DiffDrafts(before, after, priority.Names{}, i18n.Russian)
+
+	assert.Equal(t, []string{
+		"Описание изменено",
+		"Срок: нет → 20 октября (Вторник)",
+		"Приоритет: Средний → Срочный",
+		"Метки: auth → auth, urgent",
+		"Шаги воспроизведения изменено",
+	}, changes)
+	assert.Empty(t, DiffDrafts(before, before, priority.Names{}, i18n.Russian))
+}
+
+func TestFormatDraftHistory(t *testing.T) {
+	created := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	revisions := []db.DraftRevision{
+		{Revision: 1, Draft: db.DraftTaskInput{Title: "Починить логин"}, CreatedAt: created},
+		{Revision: 2, Instruction: "переименуй в Починить SSO", Draft: db.DraftTaskInput{Title: "Починить SSO"}, CreatedAt: created.Add(5 * time.Minute)},
+	}
+
+	text := FormatDraftHistory(revisions, priority.Names{}, time.FixedZone("MSK", 3*60*60), i18n.Russian)
+
+	assert.Contains(t, text, "1. 15.10 12:00 — черновик по обсуждению")
+	assert.Contains(t, text, "2. 15.10 12:05 — «переименуй в Починить SSO»\n   • Название: Починить логин → Починить SSO")
+	assert.Contains(t, text, "↩️ Отменить правку")
+}
+
+func TestDraftHistoryCommand_Execute_NoDraft(t *testing.T) {
+	chatID := int64(100)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7, ChatID: chatID}, nil)
+	mockDB.On("ListDraftRevisions", mock.Anything, 7).Return(nil, nil)
+
+	response := NewDraftHistoryCommand(mockDB).Execute(CreateCommandMessage(chatID, "/draft_history"))
+
+	assert.Contains(t, response.Text, "Черновика пока нет")
+}
@@ -0,0 +1,195 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const (
+	// duplicateThreshold is the share of common title words that marks a likely duplicate
+	duplicateThreshold = 0.6
+	// titleStemLength cuts words to a rough stem, so «логин» and «логина» match
+	titleStemLength = 5
+)
+
+// FindDuplicate returns the open task whose title is most similar to title,
+// nil when none is similar enough
+func FindDuplicate(title string, tasks []*tracker.Task) *tracker.Task {
+	words := titleStems(title)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var best *tracker.Task
+	bestScore := 0.0
+	for _, task := range tasks {
+		if task == nil || task.Completed {
+			continue
+		}
+		score := titleSimilarity(words, titleStems(task.Title))
+		if score > bestScore {
+			best, bestScore = task, score
+		}
+	}
+	if bestScore < duplicateThreshold {
+		return nil
+	}
+	return best
+}
+
+// titleSimilarity is the Jaccard index of two sets of stems
+func titleSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if _, ok := b[word]; ok {
+			common++
+		}
+	}
+	// One shared word of a one-word title says little about the task
+	if common < 2 && (len(a) > 1 || len(b) > 1) {
+		return 0
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// titleStems splits a title into lowercase word stems, skipping short words
+func titleStems(title string) map[string]struct{} {
+	stems := make(map[string]struct{})
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) < 3 {
+			continue
+		}
+		if len(runes) > titleStemLength {
+			runes = runes[:titleStemLength]
+		}
+		stems[string(runes)] = struct{}{}
+	}
+	return stems
+}
+
+// findDuplicate looks for an open task of the project the draft likely repeats;
+// a failure only skips the warning
+func (c *CreateTaskCommand) findDuplicate(ctx context.Context, chatID int64, projectID, title string) *tracker.Task {
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		return nil
+	}
+	tasks, err := client.ListTasks(ctx, projectID)
+	if err != nil {
+		log.Printf("Error listing tasks of project %s for duplicate check: %v", projectID, err)
+		return nil
+	}
+	return FindDuplicate(title, tasks)
+}
+
+// FormatDuplicateWarning points at the existing task a draft likely repeats
+func FormatDuplicateWarning(duplicate *tracker.Task, lang i18n.Lang) string {
+	title := escapeTelegramMarkdown(duplicate.Title)
+	if duplicate.URL != "" {
+		title = fmt.Sprintf("[%s](%s)", title, duplicate.URL)
+	}
+	return fmt.Sprintf(i18n.T(lang, i18n.DuplicateWarning), title)
+}
+
+// DuplicateInlineKeyboard is the draft keyboard with the choices for a likely duplicate
+func DuplicateInlineKeyboard(sessionID int, duplicate *tracker.Task, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	keyboard := CreateInlineKeyboard(sessionID, lang)
+	keyboard.InlineKeyboard[0][0].Text = i18n.T(lang, i18n.ButtonCreateAnyway)
+
+	var row []tgbotapi.InlineKeyboardButton
+	if duplicate.URL != "" {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonURL(i18n.T(lang, i18n.ButtonOpenExisting), duplicate.URL))
+	}
+	data := fmt.Sprintf("%s%s%d%s%s", CallbackMergeDuplicate, CallbackDataSeparator, sessionID, CallbackDataSeparator, duplicate.ID)
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonMergeComment), data))
+	keyboard.InlineKeyboard = append(keyboard.InlineKeyboard, row)
+	return keyboard
+}
+
+// FormatMergeComment turns a draft into a comment for the task it duplicates
+func FormatMergeComment(title, description string) string {
+	comment := "Дополнение из обсуждения в Telegram: " + title
+	if description = strings.TrimSpace(description); description != "" {
+		comment += "\n\n" + description
+	}
+	return comment
+}
+
+// handleMergeDuplicateCallback adds the draft as a comment to the existing
+// task and closes the discussion instead of creating a new task
+func (h *CallbackHandler) handleMergeDuplicateCallback(callback *tgbotapi.CallbackQuery, sessionIDStr, taskID string) *CallbackResponse {
+	isOwner, err := h.verifySessionOwner(sessionIDStr, int64(callback.From.ID))
+	if err != nil {
+		log.Printf("Error verifying session owner: %v", err)
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerCheckFailed))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+	if !isOwner {
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackOwnerOnlyAction))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+
+	sessionID, err := h.parseSessionID(sessionIDStr)
+	if err != nil || taskID == "" {
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackStale))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+
+	ctx := context.Background()
+	draft, err := h.dbManager.GetDraftTask(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting draft task: %v", err)
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackDraftLoadFailed))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+
+	client, err := h.trackers.ForChat(callback.Message.Chat.ID)
+	commenter, ok := client.(tracker.Commenter)
+	if err != nil || !ok {
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackNoComments))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+
+	description := BuildTodoistDescription(draft.Description.String, draft.Fields, draft.SelectedLinks)
+	if err := commenter.AddComment(ctx, taskID, FormatMergeComment(draft.Title.String, description)); err != nil {
+		log.Printf("Error adding draft of session %d to task %s: %v", sessionID, taskID, err)
+		callbackCfg := tgbotapi.NewCallback(callback.ID, h.text(callback, i18n.CallbackCommentFailed))
+		return &CallbackResponse{CallbackConfig: &callbackCfg, IsOwner: false}
+	}
+
+	if err := h.dbManager.DeleteDraftTask(ctx, sessionID); err != nil {
+		log.Printf("Error deleting merged draft of session %d: %v", sessionID, err)
+	}
+	if err := h.dbManager.CloseSession(ctx, sessionID); err != nil {
+		log.Printf("Error closing session: %v", err)
+	}
+
+	lang := ChatLanguage(ctx, h.dbManager, callback.Message.Chat.ID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.CallbackMerged))
+	text := i18n.T(lang, i18n.CallbackMergedMsg)
+	if task, err := client.GetTask(ctx, taskID); err == nil && task.URL != "" {
+		text = fmt.Sprintf(i18n.T(lang, i18n.CallbackMergedTaskMsg), escapeTelegramMarkdown(task.Title), task.URL)
+	}
+	msg := tgbotapi.NewMessage(callback.Message.Chat.ID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	return &CallbackResponse{
+		CallbackConfig:  &callbackCfg,
+		IsOwner:         true,
+		ResponseMessage: &msg,
+	}
+}
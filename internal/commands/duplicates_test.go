@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// commentingTodoistClient is a Todoist mock that records added comments
+type commentingTodoistClient struct {
+	*MockTodoistClient
+	taskID  string
+	comment string
+}
+
+func (c *commentingTodoistClient) UploadFile(ctx context.Context, name string, data []byte) (*todoist.FileAttachment, error) {
+	return nil, nil
+}
+
+func (c *commentingTodoistClient) AddComment(ctx context.Context, taskID, content string, attachment *todoist.FileAttachment) (*todoist.Comment, error) {
+	c.taskID, c.comment = taskID, content
+	return &todoist.Comment{}, nil
+}
+
+func TestFindDuplicate(t *testing.T) {
+	tasks := []*tracker.Task{
+		{ID: "t1", Title: "Обновить README"},
+		{ID: "t2", Title: "Починить логин через Google"},
+		{ID: "t3", Title: "Починить логина через Google", Completed: true},
+	}
+
+	assert.Equal(t, "t2", FindDuplicate("Починить логин через Google на мобильных", tasks).ID)
+	assert.Nil(t, FindDuplicate("Починить экспорт отчётов", tasks))
+	assert.Nil(t, FindDuplicate("", tasks))
+}
+
+func TestDuplicateInlineKeyboard(t *testing.T) {
+	keyboard := DuplicateInlineKeyboard(42, &tracker.Task{ID: "t2", URL: "https://app.todoist.com/app/task/t2"}, i18n.Russian)
+
+	assert.Len(t, keyboard.InlineKeyboard, 4)
+	assert.Equal(t, "✅ Всё равно создать", keyboard.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "https://app.todoist.com/app/task/t2", *keyboard.InlineKeyboard[3][0].URL)
+	assert.Equal(t, "merge_task:42:t2", *keyboard.InlineKeyboard[3][1].CallbackData)
+}
+
+// Tests that merging adds the draft as a comment and closes the discussion without a new task
+func TestCallbackHandler_MergeDuplicate(t *testing.T) {
+	chatID, userID := int64(789), int64(456)
+	mockDB := new(MockDBManager)
+	mockDB.On("IsSessionOwner", mock.Anything, 123, userID).Return(true, nil)
+	mockDB.On("GetDraftTask", mock.Anything, 123).Return(db.DraftTask{
+		SessionID:   123,
+		Title:       sql.NullString{String: "Починить логин", Valid: true},
+		Description: sql.NullString{String: "Падает на Android", Valid: true},
+	}, nil)
+	mockDB.On("DeleteDraftTask", mock.Anything, 123).Return(nil)
+	mockDB.On("CloseSession", mock.Anything, 123).Return(nil)
+	client := &commentingTodoistClient{MockTodoistClient: new(MockTodoistClient)}
+	client.On("GetTask", mock.Anything, "t2").Return(&todoist.TaskResponse{ID: "t2", Content: "Починить логин через Google"}, nil)
+
+	response := NewCallbackHandler(client, mockDB).HandleCallback(sectionCallback(chatID, userID, "merge_task:123:t2"))
+
+	assert.True(t, response.IsOwner)
+	assert.Nil(t, response.CreatedTask)
+	assert.Equal(t, "t2", client.taskID)
+	assert.Contains(t, client.comment, "Починить логин")
+	assert.Contains(t, client.comment, "Падает на Android")
+	assert.Contains(t, response.ResponseMessage.Text, "app.todoist.com/app/task/t2")
+	client.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+	mockDB.AssertExpectations(t)
+}
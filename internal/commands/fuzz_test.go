@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// Callback data, command arguments and draft due dates come from arbitrary
+// chat members, so their parsers must not panic on any input.
+// Run a target longer with e.g. go test ./internal/commands -run='^$' -fuzz=FuzzHandleCallback
+
+func FuzzHandleCallback(f *testing.F) {
+	for _, seed := range []string{
+		"confirm_task:123", "edit_task:1", "cancel_task:-5", "finish_discussion:9",
+		"keep_discussion:0", "select_project:abc", "project_page:3", "project_page:-1", "unknown:1", "confirm_task",
+		"confirm_task:1:2", ":", "", "confirm_task:99999999999999999999", "confirm_task:\x00",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, data string) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, mock.Anything).Return("", nil)
+		mockDB.On("IsSessionOwner", mock.Anything, mock.Anything, mock.Anything).Return(false, nil)
+		mockDB.On("SetTodoistProjectID", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		mockTodoist := new(MockTodoistClient)
+		mockTodoist.On("GetProjects", mock.Anything).Return([]todoist.Project{{ID: "abc", Name: "Backend"}}, nil).Maybe()
+		handler := NewCallbackHandler(mockTodoist, mockDB)
+
+		resp := handler.HandleCallback(&tgbotapi.CallbackQuery{
+			ID:      "cb",
+			From:    &tgbotapi.User{ID: 456},
+			Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: 789}},
+			Data:    data,
+		})
+
+		if resp == nil || resp.CallbackConfig == nil {
+			t.Fatalf("no callback answer for %q", data)
+		}
+		if strings.Count(data, CallbackDataSeparator) != 1 && resp.IsOwner {
+			t.Fatalf("malformed data %q was treated as coming from the owner", data)
+		}
+		if resp.CreatedTask != nil {
+			t.Fatalf("data %q created a task for a non-owner", data)
+		}
+	})
+}
+
+func FuzzParseTaskReference(f *testing.F) {
+	for _, seed := range []string{
+		"6X7rM8997g3RQmvh", "<https://app.todoist.com/app/task/fix-login-6X7rM8997g3RQmvh>",
+		"https://todoist.com/showTask?id=2995104339", "https://todoist.com/app/project/x/task/%zz",
+		"short", "", "  ", "<>", "https://example.com/app/task/123456",
+	} {
+		f.Add(seed)
+	}
+
+	idPattern := regexp.MustCompile(`^[0-9A-Za-z_-]+$`)
+	f.Fuzz(func(t *testing.T, arg string) {
+		id, ok := ParseTaskReference(arg)
+		if !ok {
+			if id != "" {
+				t.Fatalf("rejected %q but returned id %q", arg, id)
+			}
+			return
+		}
+		if !idPattern.MatchString(id) {
+			t.Fatalf("%q resolved to unsafe task id %q", arg, id)
+		}
+	})
+}
+
+func FuzzBulkParseArguments(f *testing.F) {
+	for _, seed := range []string{
+		"overdue 2026-10-20", "overdue & @bug завтра", "today", "", " ", "\t\t", "a\tb", "p1 | p2  2026-01-01 ",
+	} {
+		f.Add(seed)
+	}
+
+	commands := []*BulkCommand{NewCompleteAllCommand(nil, nil, nil), NewRescheduleCommand(nil, nil, nil)}
+	f.Fuzz(func(t *testing.T, args string) {
+		for _, cmd := range commands {
+			filter, due, ok := cmd.parseArguments(args)
+			if !ok {
+				continue
+			}
+			if strings.TrimSpace(filter) == "" {
+				t.Fatalf("%s accepted %q with an empty filter", cmd.Name(), args)
+			}
+			if cmd.kind == BulkReschedule && (due == "" || strings.ContainsAny(due, " \t")) {
+				t.Fatalf("%s parsed due %q from %q", cmd.Name(), due, args)
+			}
+		}
+	})
+}
+
+func FuzzConvertToDueISO(f *testing.F) {
+	for _, seed := range []string{
+		"", "today", "tomorrow", "пятница", "sunday", "2026-04-01", "2026-04-01T10:00:00Z", "202", "через неделю", "\xff",
+	} {
+		f.Add(seed)
+	}
+
+	cmd := &CreateTaskCommand{}
+	f.Fuzz(func(t *testing.T, due string) {
+		got := cmd.convertToDueISO(due, time.UTC)
+		if due == "" {
+			if got != "" {
+				t.Fatalf("empty due converted to %q", got)
+			}
+			return
+		}
+		if got == due {
+			return
+		}
+		_, dateErr := time.Parse("2006-01-02", got)
+		_, dateTimeErr := time.Parse(time.RFC3339, got)
+		if (dateErr != nil && dateTimeErr != nil) || !utf8.ValidString(got) {
+			t.Fatalf("%q converted to %q, want the input, an ISO date or an RFC 3339 time", due, got)
+		}
+	})
+}
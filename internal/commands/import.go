@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/taskimport"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+const ReplyKindImportUpload = "import_upload"
+
+// importPreviewLimit is how many tasks are listed in the preview
+const importPreviewLimit = 10
+
+// ImportCommand asks for a CSV file with tasks to create in the chat's project
+type ImportCommand struct {
+	dbManager DBManager
+}
+
+func NewImportCommand(dbManager DBManager) *ImportCommand {
+	return &ImportCommand{dbManager: dbManager}
+}
+
+func (c *ImportCommand) Name() string {
+	return "import"
+}
+
+func (c *ImportCommand) Description() string {
+	return "Импортировать задачи в Todoist из CSV-файла"
+}
+
+func (c *ImportCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
+	if err != nil || projectID == "" {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ImportChooseProject))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ImportPrompt))
+	msg.ParseMode = "Markdown"
+	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
+	return &msg
+}
+
+func (c *ImportCommand) WaitingReply(message *tgbotapi.Message) (string, string, bool) {
+	projectID, err := c.dbManager.GetTodoistProjectID(context.Background(), message.Chat.ID)
+	if err != nil || projectID == "" {
+		return "", "", false
+	}
+	return ReplyKindImportUpload, fmt.Sprintf("%d:%s", message.Chat.ID, projectID), true
+}
+
+// FormatImportPreview describes a parsed CSV before any task is created
+func FormatImportPreview(result *taskimport.Result, lang i18n.Lang) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.ImportFound)+"\n", len(result.Rows))
+	for i, row := range result.Rows {
+		if i == importPreviewLimit {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.MoreItems)+"\n", len(result.Rows)-importPreviewLimit)
+			break
+		}
+		fmt.Fprintf(&sb, "• %s", row.Task.Content)
+		if row.Task.DueString != "" {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.DueSuffix), row.Task.DueString)
+		}
+		sb.WriteString("\n")
+	}
+	if result.Skipped > 0 {
+		fmt.Fprintf(&sb, "\n"+i18n.T(lang, i18n.ImportSkipped)+"\n", result.Skipped)
+	}
+	writeImportProblems(&sb, lang, i18n.T(lang, i18n.ImportInvalidRows), result.Problems)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// FormatImportReport summarizes a finished import
+func FormatImportReport(rows []taskimport.Row, results []todoist.BatchTaskResult, lang i18n.Lang) string {
+	var failed []taskimport.Problem
+	created := 0
+	for i, result := range results {
+		if result.Err != nil {
+			failed = append(failed, taskimport.Problem{Line: rows[i].Line, Reason: result.Err.Error()})
+			continue
+		}
+		created++
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.ImportDone)+"\n", created, len(rows))
+	writeImportProblems(&sb, lang, i18n.T(lang, i18n.ImportCreateFailed), failed)
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func writeImportProblems(sb *strings.Builder, lang i18n.Lang, title string, problems []taskimport.Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprintf(sb, "\n⚠️ %s (%d):\n", title, len(problems))
+	for i, problem := range problems {
+		if i == importPreviewLimit {
+			fmt.Fprintf(sb, i18n.T(lang, i18n.MoreItems)+"\n", len(problems)-importPreviewLimit)
+			break
+		}
+		fmt.Fprintf(sb, i18n.T(lang, i18n.ImportProblemLine)+"\n", problem.Line, problem.Reason)
+	}
+}
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/taskimport"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestImportCommand_RequiresProject(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, int64(5)).Return("", db.ErrProjectIDNotSet)
+	cmd := NewImportCommand(mockDB)
+
+	message := CreateCommandMessage(5, "/import")
+	response := cmd.Execute(message)
+	_, _, wait := cmd.WaitingReply(message)
+
+	assert.Contains(t, response.Text, "/set_project")
+	assert.False(t, wait)
+}
+
+func TestImportCommand_WaitsForFile(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, int64(5)).Return("777", nil)
+
+	kind, value, wait := NewImportCommand(mockDB).WaitingReply(CreateCommandMessage(5, "/import"))
+
+	assert.True(t, wait)
+	assert.Equal(t, ReplyKindImportUpload, kind)
+	assert.Equal(t, "5:777", value)
+}
+
+func TestFormatImportPreviewAndReport(t *testing.T) {
+	rows := []taskimport.Row{
+		{Line: 2, Task: todoist.TaskRequest{Content: "Первая", DueString: "завтра"}},
+		{Line: 3, Task: todoist.TaskRequest{Content: "Вторая"}},
+	}
+
+	preview := FormatImportPreview(&taskimport.Result{
+		Rows:     rows,
+		Skipped:  1,
+		Problems: []taskimport.Problem{{Line: 4, Reason: "PRIORITY must be 1-4"}},
+	}, i18n.Russian)
+	assert.Contains(t, preview, "Найдено задач: 2")
+	assert.Contains(t, preview, "• Первая (срок: завтра)")
+	assert.Contains(t, preview, "пропущены: 1")
+	assert.Contains(t, preview, "строка 4: PRIORITY must be 1-4")
+
+	report := FormatImportReport(rows, []todoist.BatchTaskResult{{ID: "1"}, {Err: errors.New("todoist error 15: Invalid argument")}}, i18n.Russian)
+	assert.Contains(t, report, "создано 1 из 2")
+	assert.Contains(t, report, "строка 3: todoist error 15")
+}
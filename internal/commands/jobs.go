@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/jobs"
+)
+
+// JobManager is the part of the job queue available to admins
+type JobManager interface {
+	List() []jobs.Info
+	Cancel(id int64) error
+	Retry(id int64) error
+}
+
+type JobsCommand struct {
+	jobs      JobManager
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewJobsCommand(jobManager JobManager, dbManager DBManager, admins admin.Users) *JobsCommand {
+	return &JobsCommand{
+		jobs:      jobManager,
+		dbManager: dbManager,
+		admins:    admins,
+	}
+}
+
+func (c *JobsCommand) Name() string {
+	return "jobs"
+}
+
+func (c *JobsCommand) Description() string {
+	return "Очередь AI-задач: /jobs, /jobs cancel <id>, /jobs retry <id> (для администраторов)"
+}
+
+func (c *JobsCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+func (c *JobsCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	lang := ReplyLanguage(context.Background(), c.dbManager, message)
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AdminOnlyCommand))
+		return &msg
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		msg := tgbotapi.NewMessage(message.Chat.ID, formatJobList(c.jobs.List(), time.Now(), lang))
+		return &msg
+	}
+
+	usage := i18n.T(lang, i18n.JobsUsage)
+	if len(args) != 2 {
+		msg := tgbotapi.NewMessage(message.Chat.ID, usage)
+		return &msg
+	}
+	id, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+	if err != nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID, usage)
+		return &msg
+	}
+
+	var text string
+	switch args[0] {
+	case "cancel":
+		err = c.jobs.Cancel(id)
+		text = fmt.Sprintf(i18n.T(lang, i18n.JobsCanceled), id)
+	case "retry":
+		err = c.jobs.Retry(id)
+		text = fmt.Sprintf(i18n.T(lang, i18n.JobsRetried), id)
+	default:
+		msg := tgbotapi.NewMessage(message.Chat.ID, usage)
+		return &msg
+	}
+
+	if errors.Is(err, jobs.ErrJobNotFound) {
+		text = fmt.Sprintf(i18n.T(lang, i18n.JobsNotFound), id)
+	} else if err != nil {
+		text = fmt.Sprintf(i18n.T(lang, i18n.JobsActionFailed), id, err)
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	return &msg
+}
+
+func formatJobList(infos []jobs.Info, now time.Time, lang i18n.Lang) string {
+	if len(infos) == 0 {
+		return i18n.T(lang, i18n.JobsEmpty)
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, i18n.JobsHeader) + "\n")
+	for _, info := range infos {
+		age := now.Sub(info.EnqueuedAt).Round(time.Second)
+		if info.Status == jobs.StatusRunning {
+			age = now.Sub(info.StartedAt).Round(time.Second)
+		}
+		fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.JobsLine), info.ID, info.Kind, info.Status, info.ChatID, age, info.Retries())
+		if info.Error != "" {
+			fmt.Fprintf(&b, "\n   "+i18n.T(lang, i18n.JobsError), info.Error)
+		}
+	}
+	return b.String()
+}
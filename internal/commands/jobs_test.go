@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/jobs"
+)
+
+func newTestJobsCommand(t *testing.T) (*JobsCommand, *jobs.Queue) {
+	t.Helper()
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+
+	queue := jobs.NewQueue(nil)
+	t.Cleanup(queue.Stop)
+	return NewJobsCommand(queue, new(MockDBManager), admins), queue
+}
+
+func TestJobsCommand_Execute_NotAdmin(t *testing.T) {
+	cmd, _ := newTestJobsCommand(t)
+
+	response := cmd.Execute(CreateCommandMessage(100, "/jobs"))
+
+	assert.Contains(t, response.Text, "только администраторам")
+}
+
+func TestJobsCommand_Execute_ListAndCancel(t *testing.T) {
+	cmd, queue := newTestJobsCommand(t)
+	id, _, err := queue.Submit(jobs.Job{Kind: "analyze_discussion", ChatID: -5, Run: func(ctx context.Context) error { return nil }})
+	assert.NoError(t, err)
+
+	response := cmd.Execute(CreateCommandMessage(42, "/jobs"))
+	assert.Contains(t, response.Text, "analyze_discussion · queued · чат -5")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/jobs", "cancel 1"))
+	assert.Contains(t, response.Text, "отменена")
+
+	infos := queue.List()
+	assert.Len(t, infos, 1)
+	assert.Equal(t, id, infos[0].ID)
+	assert.Equal(t, jobs.StatusCanceled, infos[0].Status)
+
+	response = cmd.Execute(CreateCommandMessage(42, "/jobs", "retry 1"))
+	assert.Contains(t, response.Text, "повторно")
+	assert.Equal(t, jobs.StatusQueued, queue.List()[0].Status)
+}
+
+func TestJobsCommand_Execute_UnknownJob(t *testing.T) {
+	cmd, _ := newTestJobsCommand(t)
+
+	response := cmd.Execute(CreateCommandMessage(42, "/jobs", "retry 7"))
+	assert.Contains(t, response.Text, "не найдена")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/jobs", "restart 7"))
+	assert.Contains(t, response.Text, "Использование")
+}
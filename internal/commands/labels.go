@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// ResolveLabels matches the labels the AI suggested against the labels the
+// tracker already has, ignoring case and a leading "@", and returns them under
+// their existing names without duplicates. Unknown labels are kept when
+// createMissing is set, to be created on confirm, and dropped otherwise. When
+// the tracker has no label list or it cannot be loaded, labels are only cleaned.
+func ResolveLabels(ctx context.Context, client tracker.Client, labels []string, createMissing bool) []string {
+	labels = normalizeLabels(labels)
+	manager, ok := client.(tracker.LabelManager)
+	if !ok || len(labels) == 0 {
+		return labels
+	}
+	existing, err := manager.ListLabels(ctx)
+	if err != nil {
+		log.Printf("Error listing labels, keeping suggested ones unchecked: %v", err)
+		return labels
+	}
+
+	known := make(map[string]string, len(existing))
+	for _, name := range existing {
+		known[strings.ToLower(name)] = name
+	}
+	resolved := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if name, ok := known[strings.ToLower(label)]; ok {
+			resolved = append(resolved, name)
+		} else if createMissing {
+			resolved = append(resolved, label)
+		} else {
+			log.Printf("Dropping unknown label %q suggested for a draft", label)
+		}
+	}
+	return normalizeLabels(resolved)
+}
+
+// EnsureLabels creates the labels of a confirmed task that the tracker does not have yet
+func EnsureLabels(ctx context.Context, client tracker.Client, labels []string) {
+	manager, ok := client.(tracker.LabelManager)
+	if !ok || len(labels) == 0 {
+		return
+	}
+	existing, err := manager.ListLabels(ctx)
+	if err != nil {
+		log.Printf("Error listing labels: %v", err)
+		return
+	}
+	known := make(map[string]struct{}, len(existing))
+	for _, name := range existing {
+		known[strings.ToLower(name)] = struct{}{}
+	}
+	for _, label := range labels {
+		if _, ok := known[strings.ToLower(label)]; ok {
+			continue
+		}
+		if err := manager.CreateLabel(ctx, label); err != nil {
+			log.Printf("Error creating label %q: %v", label, err)
+		}
+	}
+}
+
+// normalizeLabels trims labels and drops empty ones and case-insensitive duplicates
+func normalizeLabels(labels []string) []string {
+	seen := make(map[string]struct{}, len(labels))
+	result := make([]string, 0, len(labels))
+	for _, label := range cleanLabels(labels) {
+		label = strings.TrimSpace(strings.TrimPrefix(label, "@"))
+		key := strings.ToLower(label)
+		if _, dup := seen[key]; dup || label == "" {
+			continue
+		}
+		seen[key] = struct{}{}
+		result = append(result, label)
+	}
+	return result
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+func TestResolveLabels(t *testing.T) {
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetLabels", mock.Anything).Return([]todoist.Label{{Name: "Backend"}, {Name: "bug"}}, nil)
+	client := tracker.NewTodoist(mockTodoist)
+	suggested := []string{"@backend", "BUG", "backend", "idea", " "}
+
+	assert.Equal(t, []string{"Backend", "bug"}, ResolveLabels(context.Background(), client, suggested, false))
+	assert.Equal(t, []string{"Backend", "bug", "idea"}, ResolveLabels(context.Background(), client, suggested, true))
+	assert.Empty(t, ResolveLabels(context.Background(), client, nil, false))
+}
+
+func TestEnsureLabels(t *testing.T) {
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetLabels", mock.Anything).Return([]todoist.Label{{Name: "Backend"}}, nil)
+	mockTodoist.On("CreateLabel", mock.Anything, "idea").Return(&todoist.Label{ID: "2", Name: "idea"}, nil)
+
+	EnsureLabels(context.Background(), tracker.NewTodoist(mockTodoist), []string{"backend", "idea"})
+
+	mockTodoist.AssertExpectations(t)
+	mockTodoist.AssertNumberOfCalls(t, "CreateLabel", 1)
+}
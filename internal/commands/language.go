@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// LanguageCommand picks the reply language: in a private chat for the user,
+// who otherwise gets the locale of their Telegram client, and in a group for
+// the drafts, buttons and task confirmations everyone there sees
+type LanguageCommand struct {
+	dbManager DBManager
+}
+
+func NewLanguageCommand(dbManager DBManager) *LanguageCommand {
+	return &LanguageCommand{dbManager: dbManager}
+}
+
+func (c *LanguageCommand) Name() string {
+	return "language"
+}
+
+func (c *LanguageCommand) Description() string {
+	return "Язык ответов и черновиков: /language ru|en|auto"
+}
+
+func (c *LanguageCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+
+	if !message.Chat.IsPrivate() || message.From == nil {
+		return c.setChatLanguage(ctx, chatID, arg)
+	}
+
+	switch arg {
+	case "":
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.LanguageCurrent), i18n.T(lang, i18n.LanguageName)))
+		return &msg
+	case "auto":
+		if err := c.dbManager.SetUserLanguage(ctx, message.From.ID, ""); err != nil {
+			log.Printf("Error resetting language of user %d: %v", message.From.ID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.LanguageSaveFailed))
+			return &msg
+		}
+		auto := i18n.FromLanguageCode(message.From.LanguageCode)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(auto, i18n.LanguageAuto))
+		return &msg
+	}
+
+	chosen, ok := i18n.Parse(arg)
+	if !ok {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.LanguageUsage))
+		return &msg
+	}
+	if err := c.dbManager.SetUserLanguage(ctx, message.From.ID, string(chosen)); err != nil {
+		log.Printf("Error setting language of user %d: %v", message.From.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.LanguageSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(chosen, i18n.LanguageSet), i18n.T(chosen, i18n.LanguageName)))
+	return &msg
+}
+
+// setChatLanguage shows or changes the language of a group chat
+func (c *LanguageCommand) setChatLanguage(ctx context.Context, chatID int64, arg string) *tgbotapi.MessageConfig {
+	current := ChatLanguage(ctx, c.dbManager, chatID)
+	if arg == "" {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(current, i18n.LanguageChat), i18n.T(current, i18n.LanguageName)))
+		return &msg
+	}
+
+	chosen, ok := i18n.Parse(arg)
+	if !ok && arg != "auto" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(current, i18n.LanguageUsage))
+		return &msg
+	}
+	if err := c.dbManager.SetChatLanguage(ctx, chatID, string(chosen)); err != nil {
+		log.Printf("Error setting language of chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(current, i18n.LanguageSaveFailed))
+		return &msg
+	}
+	if !ok {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(i18n.Default, i18n.LanguageChatReset))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(chosen, i18n.LanguageChatSet), i18n.T(chosen, i18n.LanguageName)))
+	return &msg
+}
+
+// ReplyLanguage is the language of the chat when one is set, otherwise the
+// language the sender chose with /language or the locale of their Telegram
+// client
+func ReplyLanguage(ctx context.Context, dbManager DBManager, message *tgbotapi.Message) i18n.Lang {
+	if lang, ok := storedChatLanguage(ctx, dbManager, message.Chat.ID); ok {
+		return lang
+	}
+	if message.From == nil {
+		return i18n.Default
+	}
+	// A private chat's language is already the one its user chose
+	if !message.Chat.IsPrivate() {
+		stored, err := dbManager.GetUserLanguage(ctx, message.From.ID)
+		if err != nil {
+			log.Printf("Error getting language of user %d: %v", message.From.ID, err)
+		}
+		if lang, ok := i18n.Parse(stored); ok {
+			return lang
+		}
+	}
+	return i18n.FromLanguageCode(message.From.LanguageCode)
+}
+
+// ChatLanguage returns the language of texts the whole chat sees, such as
+// draft previews and task confirmations; errors fall back to the default
+func ChatLanguage(ctx context.Context, dbManager DBManager, chatID int64) i18n.Lang {
+	if lang, ok := storedChatLanguage(ctx, dbManager, chatID); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+func storedChatLanguage(ctx context.Context, dbManager DBManager, chatID int64) (i18n.Lang, bool) {
+	stored, err := dbManager.GetChatLanguage(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting language of chat %d: %v", chatID, err)
+	}
+	return i18n.Parse(stored)
+}
@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+)
+
+func privateCommandMessage(userID int64, languageCode, commandText string, args ...string) *tgbotapi.Message {
+	message := CreateCommandMessage(userID, commandText, args...)
+	message.Chat.Type = "private"
+	message.From.LanguageCode = languageCode
+	return message
+}
+
+func TestHelpCommand_UsesTelegramLocale(t *testing.T) {
+	chatID := int64(42)
+
+	t.Run("English locale without a choice", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+
+		response := NewHelpCommand(NewRegistry(), mockDB, admin.Users{}).Execute(privateCommandMessage(chatID, "en-US", "/help"))
+
+		assert.Contains(t, response.Text, "All commands")
+	})
+
+	t.Run("stored choice wins over locale", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("ru", nil)
+
+		response := NewHelpCommand(NewRegistry(), mockDB, admin.Users{}).Execute(privateCommandMessage(chatID, "en", "/help"))
+
+		assert.Contains(t, response.Text, "Полный список команд")
+	})
+
+	t.Run("group language wins over the sender's choice", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("en", nil)
+		message := CreateCommandMessage(chatID, "/help")
+		message.Chat.Type = "group"
+
+		response := NewHelpCommand(NewRegistry(), mockDB, admin.Users{}).Execute(message)
+
+		assert.Contains(t, response.Text, "All commands")
+		mockDB.AssertNotCalled(t, "GetUserLanguage", mock.Anything, mock.Anything)
+	})
+}
+
+func TestLanguageCommand_Execute(t *testing.T) {
+	userID := int64(42)
+
+	t.Run("sets language in a private chat", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, userID).Return("", nil)
+		mockDB.On("SetUserLanguage", mock.Anything, userID, "en").Return(nil)
+
+		response := NewLanguageCommand(mockDB).Execute(privateCommandMessage(userID, "ru", "/language", "en"))
+
+		assert.Equal(t, "Done, replying in English.", response.Text)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("auto clears the choice", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, userID).Return("en", nil)
+		mockDB.On("SetUserLanguage", mock.Anything, userID, "").Return(nil)
+
+		response := NewLanguageCommand(mockDB).Execute(privateCommandMessage(userID, "ru", "/language", "auto"))
+
+		assert.Contains(t, response.Text, "по языку Telegram")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("sets the language of a group", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, userID).Return("", nil)
+		mockDB.On("GetUserLanguage", mock.Anything, userID).Return("", nil)
+		mockDB.On("SetChatLanguage", mock.Anything, userID, "en").Return(nil)
+		message := CreateCommandMessage(userID, "/language", "en")
+		message.Chat.Type = "group"
+
+		response := NewLanguageCommand(mockDB).Execute(message)
+
+		assert.Equal(t, "Done, the chat language is English.", response.Text)
+		mockDB.AssertNotCalled(t, "SetUserLanguage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("auto resets the language of a group", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, userID).Return("en", nil)
+		mockDB.On("SetChatLanguage", mock.Anything, userID, "").Return(nil)
+		message := CreateCommandMessage(userID, "/language", "auto")
+		message.Chat.Type = "group"
+
+		response := NewLanguageCommand(mockDB).Execute(message)
+
+		assert.Contains(t, response.Text, "сброшен")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown language", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatLanguage", mock.Anything, userID).Return("", nil)
+
+		response := NewLanguageCommand(mockDB).Execute(privateCommandMessage(userID, "de", "/language", "de"))
+
+		assert.Equal(t, "Usage: /language ru|en|auto", response.Text)
+	})
+}
@@ -3,21 +3,58 @@ package commands
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tasklist"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
+// DefaultListPageSize keeps a page of tasks readable on a phone screen
+const DefaultListPageSize = 10
+
+// listButtonTitleLimit keeps a task's quick action row on one line
+const listButtonTitleLimit = 24
+
+// listFilterNames describe the filters in headings and empty listings
+var listFilterNames = map[tasklist.Filter]i18n.Key{
+	tasklist.FilterOverdue:  i18n.ListFilterOverdue,
+	tasklist.FilterToday:    i18n.ListFilterToday,
+	tasklist.FilterUpcoming: i18n.ListFilterUpcoming,
+	tasklist.FilterNoDue:    i18n.ListFilterNoDue,
+}
+
 // ListCommand handles the /list command to list tasks or projects
 type ListCommand struct {
-	todoistClient todoist.Client
+	trackers  *tracker.Selector
+	dbManager DBManager
+	pageSize  int
+	now       func() time.Time
 }
 
 // NewListCommand creates a new list command handler
-func NewListCommand(todoistClient todoist.Client) *ListCommand {
+func NewListCommand(todoistClient todoist.Client, dbManager DBManager) *ListCommand {
 	return &ListCommand{
-		todoistClient: todoistClient,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
+		dbManager: dbManager,
+		pageSize:  DefaultListPageSize,
+		now:       time.Now,
+	}
+}
+
+// SetTrackers sets the trackers tasks and projects are listed from
+func (c *ListCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+// SetPageSize sets how many tasks one page of the listing shows
+func (c *ListCommand) SetPageSize(size int) {
+	if size > 0 {
+		c.pageSize = size
 	}
 }
 
@@ -28,7 +65,15 @@ func (c *ListCommand) Name() string {
 
 // Description returns the command description
 func (c *ListCommand) Description() string {
-	return "Показать список задач или проектов (использование: /list [tasks|projects] [project_id])"
+	return "Показать список задач или проектов (использование: /list [tasks|projects] [project_id] [overdue|today|upcoming|nodue])"
+}
+
+// TaskListQuery is one page of the tasks of a project, or of all projects,
+// that pass a filter
+type TaskListQuery struct {
+	ProjectID string
+	Filter    tasklist.Filter
+	Page      int
 }
 
 // Execute handles the command execution
@@ -38,84 +83,71 @@ func (c *ListCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig
 
 	// Default to listing tasks
 	listType := "tasks"
-	var projectID string
-
-	if len(args) > 0 {
-		if args[0] == "tasks" || args[0] == "projects" {
-			listType = args[0]
-		} else {
-			// If first arg is not a valid list type, assume it's a project ID
-			projectID = args[0]
-		}
+	if len(args) > 0 && (args[0] == "tasks" || args[0] == "projects") {
+		listType = args[0]
+		args = args[1:]
+	}
 
-		// If second arg exists and we're listing tasks, it's a project ID
-		if len(args) > 1 && listType == "tasks" {
-			projectID = args[1]
+	// The rest is a project ID and a filter, in any order
+	var query TaskListQuery
+	for _, arg := range args {
+		if filter, ok := tasklist.ParseFilter(arg); ok {
+			query.Filter = filter
+		} else if query.ProjectID == "" {
+			query.ProjectID = arg
 		}
 	}
 
-	// Handle based on list type
 	switch listType {
 	case "projects":
-		return c.listProjects(message)
-	case "tasks":
-		return c.listTasks(message, projectID)
+		lang := ReplyLanguage(context.Background(), c.dbManager, message)
+		client, err := c.trackers.ForChat(message.Chat.ID)
+		if err != nil {
+			msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.ListTrackerUnavailable), err))
+			return &msg
+		}
+		return c.listProjects(message, client, lang)
 	default:
-		// Should never reach here
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Неизвестный тип списка. Используйте 'tasks' или 'projects'.")
+		text, keyboard := c.TasksPage(context.Background(), message.Chat.ID, query)
+		msg := tgbotapi.NewMessage(message.Chat.ID, text)
 		msg.ParseMode = "Markdown"
+		if keyboard != nil {
+			msg.ReplyMarkup = *keyboard
+		}
 		return &msg
 	}
 }
 
-// listProjects lists all projects
-func (c *ListCommand) listProjects(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	projects, err := c.todoistClient.GetProjects(context.Background())
-	if err != nil {
-		msg := tgbotapi.NewMessage(message.Chat.ID,
-			fmt.Sprintf("❌ *Ошибка получения проектов:* %v", err))
-		msg.ParseMode = "Markdown"
-		return &msg
-	}
-
-	if len(projects) == 0 {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Проекты не найдены.")
-		msg.ParseMode = "Markdown"
-		return &msg
-	}
-
-	// Format projects list
-	var sb strings.Builder
-	sb.WriteString("📋 *Ваши проекты:*\n\n")
+// Respond sends a long listing as several messages instead of one cut by Telegram
+func (c *ListCommand) Respond(ctx context.Context, message *tgbotapi.Message) *Response {
+	return SplitResponse(c.Execute(message))
+}
 
-	for _, project := range projects {
-		sb.WriteString(fmt.Sprintf("• *%s*\n", project.Name))
-		sb.WriteString(fmt.Sprintf("  ID: `%s`\n", project.ID))
-		sb.WriteString(fmt.Sprintf("  Задачи: Используйте `/list tasks %s`\n\n", project.ID))
+// TasksPage renders a page of the task listing with the buttons to turn the
+// pages, nil when everything fits on one. Failures are rendered as text too.
+// Pages are shared by the whole chat, so they are in the chat's language.
+func (c *ListCommand) TasksPage(ctx context.Context, chatID int64, query TaskListQuery) (string, *tgbotapi.InlineKeyboardMarkup) {
+	lang := ChatLanguage(ctx, c.dbManager, chatID)
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		return fmt.Sprintf(i18n.T(lang, i18n.ListTrackerUnavailable), escapeTelegramMarkdown(err.Error())), nil
 	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, sb.String())
-	msg.ParseMode = "Markdown"
-	return &msg
-}
-
-// listTasks lists tasks, optionally filtered by project
-func (c *ListCommand) listTasks(message *tgbotapi.Message, projectID string) *tgbotapi.MessageConfig {
-	tasks, err := c.todoistClient.GetTasks(context.Background(), projectID)
+	tasks, err := client.ListTasks(ctx, query.ProjectID)
 	if err != nil {
-		msg := tgbotapi.NewMessage(message.Chat.ID,
-			fmt.Sprintf("❌ *Ошибка получения задач:* %v", err))
-		msg.ParseMode = "Markdown"
-		return &msg
+		return fmt.Sprintf(i18n.T(lang, i18n.ListTasksFailed), err), nil
+	}
+	if query.Filter != tasklist.FilterAll {
+		tasks = query.Filter.Apply(tasks, c.now().In(ChatLocation(ctx, c.dbManager, chatID)))
 	}
 
 	// If project ID was specified, get project name
 	var projectName string
-	if projectID != "" {
-		projects, err := c.todoistClient.GetProjects(context.Background())
+	if query.ProjectID != "" {
+		projects, err := client.ListProjects(ctx)
 		if err == nil {
 			for _, p := range projects {
-				if p.ID == projectID {
+				if p.ID == query.ProjectID {
 					projectName = p.Name
 					break
 				}
@@ -123,56 +155,160 @@ func (c *ListCommand) listTasks(message *tgbotapi.Message, projectID string) *tg
 		}
 	}
 
+	filterNote := ""
+	if key, ok := listFilterNames[query.Filter]; ok {
+		name := i18n.T(lang, key)
+		if query.Filter == tasklist.FilterUpcoming {
+			name = fmt.Sprintf(name, tasklist.UpcomingDays)
+		}
+		filterNote = " (" + name + ")"
+	}
+
 	if len(tasks) == 0 {
-		var messageText string
 		if projectName != "" {
-			messageText = fmt.Sprintf("В проекте \"%s\" задач не найдено.", projectName)
-		} else if projectID != "" {
-			messageText = fmt.Sprintf("В проекте с ID %s задач не найдено.", projectID)
-		} else {
-			messageText = "Задач не найдено."
+			return fmt.Sprintf(i18n.T(lang, i18n.ListNoTasksInProject), projectName, filterNote), nil
+		} else if query.ProjectID != "" {
+			return fmt.Sprintf(i18n.T(lang, i18n.ListNoTasksInProjectID), query.ProjectID, filterNote), nil
 		}
-
-		msg := tgbotapi.NewMessage(message.Chat.ID, messageText)
-		msg.ParseMode = "Markdown"
-		return &msg
+		return fmt.Sprintf(i18n.T(lang, i18n.ListNoTasks), filterNote), nil
 	}
 
-	// Format tasks list
-	var sb strings.Builder
+	heading := i18n.T(lang, i18n.ListYourTasks)
 	if projectName != "" {
-		sb.WriteString(fmt.Sprintf("📝 *Задачи в проекте %s:*\n\n", projectName))
-	} else if projectID != "" {
-		sb.WriteString(fmt.Sprintf("📝 *Задачи в проекте %s:*\n\n", projectID))
-	} else {
-		sb.WriteString("📝 *Ваши задачи:*\n\n")
+		heading = fmt.Sprintf(i18n.T(lang, i18n.ListProjectTasks), projectName)
+	} else if query.ProjectID != "" {
+		heading = fmt.Sprintf(i18n.T(lang, i18n.ListProjectTasks), query.ProjectID)
 	}
+	heading += filterNote
 
+	page, current, pages := tasklist.Page(tasks, query.Page, c.pageSize)
+	if pages > 1 {
+		heading += fmt.Sprintf(i18n.T(lang, i18n.ListPage), current+1, pages)
+	}
+	query.Page = current
+	return tasklist.Tasks(heading, page, i18n.T(lang, i18n.ListFooter), lang), listPageKeyboard(query, page, pages, lang)
+}
+
+// TasksPageWithNote renders a page of the task listing under a note on what
+// was just done with one of its tasks
+func (c *ListCommand) TasksPageWithNote(ctx context.Context, chatID int64, query TaskListQuery, note string) (string, *tgbotapi.InlineKeyboardMarkup) {
+	text, keyboard := c.TasksPage(ctx, chatID, query)
+	return escapeTelegramMarkdown(note) + "\n\n" + text, keyboard
+}
+
+// listPageKeyboard has a row of quick actions for each open task of the page
+// and, when there are several pages, the buttons to turn them; the middle
+// one refreshes the current page. Nil when there is nothing to press.
+func listPageKeyboard(query TaskListQuery, tasks []*tracker.Task, pages int, lang i18n.Lang) *tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
 	for _, task := range tasks {
-		// Mark completed tasks
-		if task.IsCompleted {
-			sb.WriteString(fmt.Sprintf("✅ ~%s~\n", task.Content))
-		} else {
-			sb.WriteString(fmt.Sprintf("⬜ *%s*\n", task.Content))
+		if task == nil || task.Completed {
+			continue
+		}
+		row := []tgbotapi.InlineKeyboardButton{
+			tgbotapi.NewInlineKeyboardButtonData("✅ "+truncateRunes(task.Title, listButtonTitleLimit), listTaskData(CallbackListDone, task.ID, query)),
+			tgbotapi.NewInlineKeyboardButtonData("🗑", listTaskData(CallbackListDelete, task.ID, query)),
+		}
+		if task.URL != "" {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonURL("🔗", task.URL))
+		}
+		rows = append(rows, row)
+	}
+
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if query.Page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonListPrev), listPageData(query, query.Page-1)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", query.Page+1, pages), listPageData(query, query.Page)))
+		if query.Page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonListNext), listPageData(query, query.Page+1)))
 		}
+		rows = append(rows, nav)
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+// listPageData is "list_page:{page}:{filter}:{project_id}"
+func listPageData(query TaskListQuery, page int) string {
+	return strings.Join([]string{CallbackListPage, strconv.Itoa(page), string(query.Filter), query.ProjectID}, CallbackDataSeparator)
+}
+
+// listTaskData is "{action}:{task_id}:{page}:{filter}:{project_id}", the page
+// being the one shown again once the task is done
+func listTaskData(action, taskID string, query TaskListQuery) string {
+	return strings.Join([]string{action, taskID, strconv.Itoa(query.Page), string(query.Filter), query.ProjectID}, CallbackDataSeparator)
+}
+
+// ParseListPage reads the listing a page button shows
+func ParseListPage(data CallbackData) (TaskListQuery, bool) {
+	return parseListQuery(data.Args)
+}
 
-		sb.WriteString(fmt.Sprintf("  ID: `%s`\n", task.ID))
+// ParseListTask reads the task of a quick action button and the listing it was pressed in
+func ParseListTask(data CallbackData) (string, TaskListQuery, bool) {
+	taskID := data.Arg(0)
+	if taskID == "" {
+		return "", TaskListQuery{}, false
+	}
+	query, ok := parseListQuery(data.Args[1:])
+	return taskID, query, ok
+}
+
+// parseListQuery reads "{page}:{filter}:{project_id}"
+func parseListQuery(args []string) (TaskListQuery, bool) {
+	data := CallbackData{Args: args}
+	page, err := strconv.Atoi(data.Arg(0))
+	if err != nil || page < 0 {
+		return TaskListQuery{}, false
+	}
+	filter, ok := tasklist.ParseFilter(data.Arg(1))
+	if !ok {
+		return TaskListQuery{}, false
+	}
+	return TaskListQuery{ProjectID: data.Arg(2), Filter: filter, Page: page}, true
+}
 
-		// Show due date if exists
-		if task.Due != nil {
-			sb.WriteString(fmt.Sprintf("  Срок: %s\n", task.Due.Date))
+// CompleteTask closes a task of the chat's tracker, returning it as it was
+func (c *ListCommand) CompleteTask(ctx context.Context, chatID int64, taskID string) (*tracker.Task, error) {
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		return nil, err
+	}
+	task, err := client.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.Completed {
+		if err := client.CompleteTask(ctx, taskID); err != nil {
+			return nil, err
 		}
+	}
+	return task, nil
+}
 
-		sb.WriteString(fmt.Sprintf("  Проект: %s\n\n", task.ProjectID))
+// listProjects lists all projects
+func (c *ListCommand) listProjects(message *tgbotapi.Message, client tracker.Client, lang i18n.Lang) *tgbotapi.MessageConfig {
+	projects, err := client.ListProjects(context.Background())
+	if err != nil {
+		msg := tgbotapi.NewMessage(message.Chat.ID,
+			fmt.Sprintf(i18n.T(lang, i18n.ListProjectsFailed), err))
+		msg.ParseMode = "Markdown"
+		return &msg
 	}
 
-	// Add help text for other commands
-	sb.WriteString("\n*Полезные команды:*\n")
-	sb.WriteString("/create_task — создать задачу из обсуждения\n")
-	sb.WriteString("/start_discussion — начать обсуждение\n")
-	sb.WriteString("/cancel — завершить обсуждение без задачи\n")
+	if len(projects) == 0 {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.ListNoProjects))
+		msg.ParseMode = "Markdown"
+		return &msg
+	}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, sb.String())
+	msg := tgbotapi.NewMessage(message.Chat.ID, tasklist.Projects(projects, lang))
 	msg.ParseMode = "Markdown"
 	return &msg
 }
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/tasklist"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func listTestTasks(count int, due string) []*todoist.TaskResponse {
+	tasks := make([]*todoist.TaskResponse, count)
+	for i := range tasks {
+		tasks[i] = &todoist.TaskResponse{ID: fmt.Sprint(i + 1), Content: fmt.Sprintf("Задача %d", i+1)}
+		if due != "" {
+			tasks[i].Due = &todoist.DueObject{Date: due}
+		}
+	}
+	return tasks
+}
+
+func TestListCommand_Execute_Paginates(t *testing.T) {
+	chatID := int64(123456789)
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTasks", mock.Anything, "").Return(listTestTasks(12, ""), nil)
+	cmd := NewListCommand(mockTodoist, new(MockDBManager))
+	cmd.SetPageSize(5)
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/list"))
+
+	assert.Contains(t, response.Text, "стр. 1/3")
+	assert.Contains(t, response.Text, "Задача 5")
+	assert.NotContains(t, response.Text, "Задача 6")
+	keyboard, ok := response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	require.Len(t, keyboard.InlineKeyboard, 6)
+	buttons := keyboard.InlineKeyboard[5]
+	require.Len(t, buttons, 2)
+	assert.Equal(t, "1/3", buttons[0].Text)
+	assert.Equal(t, "list_page:1::", *buttons[1].CallbackData)
+}
+
+func TestListCommand_Execute_QuickActions(t *testing.T) {
+	tasks := listTestTasks(2, "")
+	tasks[0].URL = "https://app.todoist.com/app/task/1"
+	tasks[1].IsCompleted = true
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTasks", mock.Anything, "").Return(tasks, nil)
+
+	response := NewListCommand(mockTodoist, new(MockDBManager)).Execute(CreateCommandMessage(1, "/list"))
+
+	assert.NotContains(t, response.Text, "стр.")
+	keyboard, ok := response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	// Completed tasks and single pages get no buttons
+	require.Len(t, keyboard.InlineKeyboard, 1)
+	row := keyboard.InlineKeyboard[0]
+	require.Len(t, row, 3)
+	assert.Equal(t, "✅ Задача 1", row[0].Text)
+	assert.Equal(t, "list_done:1:0::", *row[0].CallbackData)
+	assert.Equal(t, "list_delete:1:0::", *row[1].CallbackData)
+	assert.Equal(t, "https://app.todoist.com/app/task/1", *row[2].URL)
+}
+
+func TestListCommand_Execute_Filter(t *testing.T) {
+	chatID := int64(123456789)
+	tasks := append(listTestTasks(1, "2026-10-14"), listTestTasks(1, "")...)
+	tasks[1].Content = "Без срока"
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTasks", mock.Anything, "").Return(tasks, nil)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+	cmd := NewListCommand(mockTodoist, mockDB)
+	cmd.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/list", "overdue"))
+
+	assert.Contains(t, response.Text, "(просроченные)")
+	assert.Contains(t, response.Text, "Задача 1")
+	assert.NotContains(t, response.Text, "Без срока")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/list", "today"))
+	assert.Contains(t, response.Text, "Задач (на сегодня) не найдено.")
+}
+
+func TestListCommand_TasksPage_ClampsPage(t *testing.T) {
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetTasks", mock.Anything, "42").Return(listTestTasks(4, ""), nil)
+	mockTodoist.On("GetProjects", mock.Anything).Return([]todoist.Project{{ID: "42", Name: "Работа"}}, nil)
+	cmd := NewListCommand(mockTodoist, new(MockDBManager))
+	cmd.SetPageSize(3)
+
+	text, keyboard := cmd.TasksPage(context.Background(), 1, TaskListQuery{ProjectID: "42", Page: 7})
+
+	assert.Contains(t, text, "Работа")
+	assert.Contains(t, text, "стр. 2/2")
+	require.NotNil(t, keyboard)
+	buttons := keyboard.InlineKeyboard[len(keyboard.InlineKeyboard)-1]
+	assert.Equal(t, "◀️ Назад", buttons[0].Text)
+	assert.Equal(t, "list_page:0::42", *buttons[0].CallbackData)
+}
+
+func TestParseListPage(t *testing.T) {
+	query, ok := ParseListPage(ParseCallbackData("list_page:2:overdue:42"))
+	assert.True(t, ok)
+	assert.Equal(t, TaskListQuery{ProjectID: "42", Filter: tasklist.FilterOverdue, Page: 2}, query)
+
+	taskID, query, ok := ParseListTask(ParseCallbackData("list_done:7:1::42"))
+	assert.True(t, ok)
+	assert.Equal(t, "7", taskID)
+	assert.Equal(t, TaskListQuery{ProjectID: "42", Page: 1}, query)
+
+	_, _, ok = ParseListTask(ParseCallbackData("list_done"))
+	assert.False(t, ok)
+	_, ok = ParseListPage(ParseCallbackData("list_page:x::"))
+	assert.False(t, ok)
+	_, ok = ParseListPage(ParseCallbackData("list_page:0:later:"))
+	assert.False(t, ok)
+}
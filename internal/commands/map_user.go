@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/assignee"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// MapUserCommand maps one Telegram user to a Todoist collaborator of the chat
+// project without uploading the whole YAML mapping
+type MapUserCommand struct {
+	todoistClient todoist.Client
+	dbManager     DBManager
+}
+
+func NewMapUserCommand(todoistClient todoist.Client, dbManager DBManager) *MapUserCommand {
+	return &MapUserCommand{todoistClient: todoistClient, dbManager: dbManager}
+}
+
+func (c *MapUserCommand) Name() string {
+	return "map_user"
+}
+
+func (c *MapUserCommand) Description() string {
+	return "Назначать задачи на участника Todoist: /map_user @username email"
+}
+
+func (c *MapUserCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectChooseFirstCommand))
+		return &msg
+	}
+
+	args := strings.Fields(message.CommandArguments())
+	if len(args) == 0 {
+		return c.listMappings(ctx, chatID, projectID, lang)
+	}
+	if len(args) != 2 || !strings.HasPrefix(args[0], "@") || !strings.Contains(args[1], "@") {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.MapUserFormat))
+		return &msg
+	}
+	alias, email := args[0], strings.ToLower(args[1])
+
+	collaborators, err := c.todoistClient.GetProjectCollaborators(ctx, projectID)
+	if err != nil {
+		log.Printf("Error getting collaborators of project %s: %v", projectID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.MapUserCollaboratorsFailed))
+		return &msg
+	}
+
+	for _, collaborator := range collaborators {
+		if strings.ToLower(strings.TrimSpace(collaborator.Email)) != email {
+			continue
+		}
+		mapping := db.AssigneeMapping{
+			ChatID:           chatID,
+			TodoistProjectID: projectID,
+			AliasRaw:         alias,
+			AliasNormalized:  assignee.NormalizeAlias(alias),
+			TodoistUserID:    collaborator.ID,
+			TodoistUserName:  collaborator.Name,
+			TodoistUserEmail: collaborator.Email,
+		}
+		if err := c.dbManager.SaveAssigneeMapping(ctx, mapping); err != nil {
+			log.Printf("Error saving assignee mapping for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.MapUserSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.MapUserSaved), alias, collaborator.Name, collaborator.Email))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.MapUserNotFound), args[1]))
+	return &msg
+}
+
+// listMappings shows who the aliases of the chat project are assigned to
+func (c *MapUserCommand) listMappings(ctx context.Context, chatID int64, projectID string, lang i18n.Lang) *tgbotapi.MessageConfig {
+	mappings, err := c.dbManager.GetAssigneeMappings(ctx, chatID, projectID)
+	if err != nil {
+		log.Printf("Error getting assignee mappings for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.MapUserLoadFailed))
+		return &msg
+	}
+	if len(mappings) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.MapUserEmpty))
+		return &msg
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, i18n.MapUserHeader) + "\n")
+	for _, mapping := range mappings {
+		fmt.Fprintf(&b, "%s → %s (%s)\n", mapping.AliasRaw, mapping.TodoistUserName, mapping.TodoistUserEmail)
+	}
+	msg := tgbotapi.NewMessage(chatID, strings.TrimSuffix(b.String(), "\n"))
+	return &msg
+}
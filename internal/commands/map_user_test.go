@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestMapUserCommand_SavesMapping(t *testing.T) {
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockTodoist := new(MockTodoistClient)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	mockTodoist.On("GetProjectCollaborators", mock.Anything, "p1").Return([]todoist.Collaborator{
+		{ID: "u1", Name: "Bob", Email: "bob@example.com"},
+		{ID: "u2", Name: "Alice", Email: "Alice@Example.com"},
+	}, nil)
+	mockDB.On("SaveAssigneeMapping", mock.Anything, db.AssigneeMapping{
+		ChatID:           chatID,
+		TodoistProjectID: "p1",
+		AliasRaw:         "@Alice",
+		AliasNormalized:  "alice",
+		TodoistUserID:    "u2",
+		TodoistUserName:  "Alice",
+		TodoistUserEmail: "Alice@Example.com",
+	}).Return(nil)
+
+	cmd := NewMapUserCommand(mockTodoist, mockDB)
+	response := cmd.Execute(CreateCommandMessage(chatID, "/map_user", "@Alice alice@example.com"))
+
+	assert.Contains(t, response.Text, "будут назначаться на Alice")
+	mockDB.AssertExpectations(t)
+}
+
+func TestMapUserCommand_UnknownEmail(t *testing.T) {
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockTodoist := new(MockTodoistClient)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	mockTodoist.On("GetProjectCollaborators", mock.Anything, "p1").Return([]todoist.Collaborator{
+		{ID: "u1", Name: "Bob", Email: "bob@example.com"},
+	}, nil)
+
+	cmd := NewMapUserCommand(mockTodoist, mockDB)
+	response := cmd.Execute(CreateCommandMessage(chatID, "/map_user", "@alice alice@example.com"))
+
+	assert.Contains(t, response.Text, "не найден среди участников")
+	mockDB.AssertNotCalled(t, "SaveAssigneeMapping", mock.Anything, mock.Anything)
+}
+
+func TestMapUserCommand_ListsMappings(t *testing.T) {
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	mockDB.On("GetAssigneeMappings", mock.Anything, chatID, "p1").Return([]db.AssigneeMapping{
+		{AliasRaw: "@alice", TodoistUserName: "Alice", TodoistUserEmail: "alice@example.com"},
+	}, nil)
+
+	cmd := NewMapUserCommand(new(MockTodoistClient), mockDB)
+	response := cmd.Execute(CreateCommandMessage(chatID, "/map_user"))
+
+	assert.Equal(t, "Исполнители проекта:\n@alice → Alice (alice@example.com)", response.Text)
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MenuScope is where Telegram clients offer a command in the command menu
+type MenuScope int
+
+const (
+	// MenuEverywhere offers the command in private chats and groups
+	MenuEverywhere MenuScope = iota
+	// MenuPrivate offers the command in private chats only
+	MenuPrivate
+	// MenuGroups offers the command in groups only
+	MenuGroups
+	// MenuAdmins offers the command to the bot admins only, in their private chats
+	MenuAdmins
+)
+
+// MenuScoped is implemented by commands not offered in every chat; the rest
+// are offered everywhere
+type MenuScoped interface {
+	MenuScope() MenuScope
+}
+
+// maxMenuDescription is the Telegram limit of a command description
+const maxMenuDescription = 256
+
+// menuCommandPattern is what Telegram accepts as a command name
+var menuCommandPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// CommandScope returns where the command is offered
+func CommandScope(cmd Command) MenuScope {
+	if scoped, ok := cmd.(MenuScoped); ok {
+		return scoped.MenuScope()
+	}
+	return MenuEverywhere
+}
+
+// MenuCommands lists the commands offered in a scope for setMyCommands,
+// sorted by name. The admins' menu is the private one with the admin
+// commands added.
+func (r *Registry) MenuCommands(scope MenuScope) []tgbotapi.BotCommand {
+	var menu []tgbotapi.BotCommand
+	for _, cmd := range r.GetAll() {
+		if !menuCommandPattern.MatchString(cmd.Name()) || !inMenu(CommandScope(cmd), scope) {
+			continue
+		}
+		menu = append(menu, tgbotapi.BotCommand{
+			Command:     cmd.Name(),
+			Description: truncateRunes(cmd.Description(), maxMenuDescription),
+		})
+	}
+	sort.Slice(menu, func(i, j int) bool { return menu[i].Command < menu[j].Command })
+	return menu
+}
+
+// HelpText lists the commands offered in a scope for /help, one
+// "/name — description" line each, in the order of MenuCommands
+func (r *Registry) HelpText(scope MenuScope) string {
+	menu := r.MenuCommands(scope)
+	lines := make([]string, len(menu))
+	for i, command := range menu {
+		lines[i] = "/" + command.Command + " — " + command.Description
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inMenu(command, menu MenuScope) bool {
+	switch command {
+	case MenuEverywhere:
+		return true
+	case MenuPrivate:
+		return menu == MenuPrivate || menu == MenuAdmins
+	default:
+		return command == menu
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/admin"
+)
+
+type menuTestCommand struct {
+	name        string
+	description string
+	scope       MenuScope
+}
+
+func (c *menuTestCommand) Name() string         { return c.name }
+func (c *menuTestCommand) Description() string  { return c.description }
+func (c *menuTestCommand) MenuScope() MenuScope { return c.scope }
+func (c *menuTestCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	return nil
+}
+
+func menuNames(menu []tgbotapi.BotCommand) []string {
+	names := make([]string, len(menu))
+	for i, command := range menu {
+		names[i] = command.Command
+	}
+	return names
+}
+
+func TestRegistry_MenuCommands(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&menuTestCommand{name: "list", description: "Задачи"})
+	registry.Register(&menuTestCommand{name: "start", description: "Начало", scope: MenuPrivate})
+	registry.Register(&menuTestCommand{name: "participants", description: "Участники", scope: MenuGroups})
+	registry.Register(&menuTestCommand{name: "usage", description: "Расход", scope: MenuAdmins})
+	registry.Register(&menuTestCommand{name: "Bad-Name", description: "Не команда Telegram"})
+
+	assert.Equal(t, []string{"list", "start"}, menuNames(registry.MenuCommands(MenuPrivate)))
+	assert.Equal(t, []string{"list", "participants"}, menuNames(registry.MenuCommands(MenuGroups)))
+	assert.Equal(t, []string{"list", "start", "usage"}, menuNames(registry.MenuCommands(MenuAdmins)))
+}
+
+func TestRegistry_MenuCommands_CutsLongDescriptions(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&menuTestCommand{name: "long", description: strings.Repeat("я", 300)})
+
+	menu := registry.MenuCommands(MenuPrivate)
+
+	assert.Equal(t, maxMenuDescription, utf8.RuneCountInString(menu[0].Description))
+}
+
+func TestHelpCommand_ListsRegistryCommands(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&menuTestCommand{name: "list", description: "Задачи"})
+	registry.Register(&menuTestCommand{name: "participants", description: "Участники", scope: MenuGroups})
+	registry.Register(&menuTestCommand{name: "usage", description: "Расход", scope: MenuAdmins})
+	admins, err := admin.ParseUsers("7")
+	assert.NoError(t, err)
+	help := NewHelpCommand(registry, new(MockDBManager), admins)
+
+	group := CreateCommandMessage(-100, "/help")
+	group.Chat.Type = "group"
+	private := CreateCommandMessage(5, "/help")
+	private.Chat.Type = "private"
+	adminChat := CreateCommandMessage(7, "/help")
+	adminChat.Chat.Type = "private"
+
+	groupHelp := help.Execute(group).Text
+	assert.Contains(t, groupHelp, "/list — Задачи")
+	assert.Contains(t, groupHelp, "/participants — Участники")
+	assert.NotContains(t, groupHelp, "/usage")
+	assert.NotContains(t, help.Execute(private).Text, "/usage")
+	assert.Contains(t, help.Execute(adminChat).Text, "/usage — Расход")
+}
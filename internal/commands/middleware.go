@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// Handler runs a command for a message
+type Handler func(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response
+
+// Middleware wraps command execution, like httpclient.Middleware wraps requests
+type Middleware func(Handler) Handler
+
+// RunCommand runs the command itself, without middlewares: Respond for
+// response commands, ExecuteContext for queued ones and Execute otherwise
+func RunCommand(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response {
+	if responder, ok := cmd.(ResponseCommand); ok {
+		return responder.Respond(ctx, message)
+	}
+	if queued, ok := cmd.(QueuedCommand); ok {
+		return MessageResponse(queued.ExecuteContext(ctx, message))
+	}
+	return MessageResponse(cmd.Execute(message))
+}
+
+// RecoveryMiddleware turns a panic in a command into an error reply, so one
+// broken command does not take the bot down
+func RecoveryMiddleware(dbManager DBManager) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command, message *tgbotapi.Message) (reply *Response) {
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					log.Printf("[COMMAND] /%s panicked in chat %d: %v\n%s", cmd.Name(), message.Chat.ID, recovered, debug.Stack())
+					text := i18n.T(ReplyLanguage(ctx, dbManager, message), i18n.CommandFailed)
+					reply = NewResponse(tgbotapi.NewMessage(message.Chat.ID, text))
+				}
+			}()
+			return next(ctx, cmd, message)
+		}
+	}
+}
+
+// LoggingMiddleware logs how long each command took
+func LoggingMiddleware() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response {
+			start := time.Now()
+			reply := next(ctx, cmd, message)
+			log.Printf("[COMMAND] /%s in chat %d took %v", cmd.Name(), message.Chat.ID, time.Since(start).Round(time.Millisecond))
+			return reply
+		}
+	}
+}
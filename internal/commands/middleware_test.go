@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubCommand struct {
+	name    string
+	execute func(message *tgbotapi.Message) *tgbotapi.MessageConfig
+}
+
+func (c *stubCommand) Name() string        { return c.name }
+func (c *stubCommand) Description() string { return "stub" }
+func (c *stubCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	return c.execute(message)
+}
+
+func replyText(text string) func(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	return func(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+		msg := tgbotapi.NewMessage(message.Chat.ID, text)
+		return &msg
+	}
+}
+
+func TestRegistry_ExecuteRunsMiddlewaresInOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response {
+				calls = append(calls, name+" before")
+				reply := next(ctx, cmd, message)
+				calls = append(calls, name+" after")
+				return reply
+			}
+		}
+	}
+
+	registry := NewRegistry()
+	registry.Use(trace("outer"), trace("inner"))
+	cmd := &stubCommand{name: "stub", execute: func(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+		calls = append(calls, "command")
+		return replyText("ok")(message)
+	}}
+
+	reply := registry.Execute(context.Background(), cmd, CreateCommandMessage(1, "/stub"))
+
+	require.NotNil(t, reply.Message())
+	assert.Equal(t, "ok", reply.Message().Text)
+	assert.Equal(t, []string{"outer before", "inner before", "command", "inner after", "outer after"}, calls)
+}
+
+func TestRecoveryMiddleware_RepliesOnPanic(t *testing.T) {
+	registry := NewRegistry()
+	registry.Use(RecoveryMiddleware(new(MockDBManager)))
+	cmd := &stubCommand{name: "broken", execute: func(*tgbotapi.Message) *tgbotapi.MessageConfig {
+		panic("boom")
+	}}
+
+	reply := registry.Execute(context.Background(), cmd, CreateCommandMessage(42, "/broken"))
+
+	require.NotNil(t, reply.Message())
+	assert.Equal(t, int64(42), reply.Message().ChatID)
+	assert.Contains(t, reply.Message().Text, "Не удалось выполнить команду")
+}
+
+func TestRegistry_DispatchRunsGivenHandlerInsideMiddlewares(t *testing.T) {
+	var calls []string
+	registry := NewRegistry()
+	registry.Use(func(next Handler) Handler {
+		return func(ctx context.Context, cmd Command, message *tgbotapi.Message) *Response {
+			calls = append(calls, "middleware")
+			return next(ctx, cmd, message)
+		}
+	})
+	cmd := &stubCommand{name: "stub", execute: func(*tgbotapi.Message) *tgbotapi.MessageConfig {
+		calls = append(calls, "execute")
+		return nil
+	}}
+
+	reply := registry.Dispatch(context.Background(), cmd, CreateCommandMessage(1, "/stub"), func(context.Context, Command, *tgbotapi.Message) *Response {
+		calls = append(calls, "run")
+		return nil
+	})
+
+	assert.Nil(t, reply)
+	assert.Equal(t, []string{"middleware", "run"}, calls)
+}
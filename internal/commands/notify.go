@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+// NotifyCommand configures the notifier plugins a chat forwards its events to
+type NotifyCommand struct {
+	dbManager DBManager
+	registry  *notify.Registry
+	admins    admin.Users
+}
+
+func NewNotifyCommand(dbManager DBManager, registry *notify.Registry, admins admin.Users) *NotifyCommand {
+	return &NotifyCommand{dbManager: dbManager, registry: registry, admins: admins}
+}
+
+func (c *NotifyCommand) Name() string {
+	return "notify"
+}
+
+func (c *NotifyCommand) Description() string {
+	return "Уведомления о задачах: /notify add <тип> <адрес> [секрет], /notify remove <номер> (для администраторов)"
+}
+
+func (c *NotifyCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+func (c *NotifyCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AdminOnlyCommand))
+		return &msg
+	}
+
+	fields := strings.Fields(message.CommandArguments())
+	switch {
+	case len(fields) == 0 || fields[0] == "list":
+		return c.list(ctx, chatID, lang)
+	case fields[0] == "add" && len(fields) == 3:
+		return c.add(ctx, chatID, lang, fields[1], fields[2], "")
+	case fields[0] == "add" && len(fields) == 4 && fields[1] == notify.KindWebhook:
+		return c.add(ctx, chatID, lang, fields[1], fields[2], fields[3])
+	case fields[0] == "remove" && len(fields) == 2:
+		return c.remove(ctx, chatID, lang, fields[1])
+	default:
+		msg := tgbotapi.NewMessage(chatID, c.usage(lang))
+		return &msg
+	}
+}
+
+func (c *NotifyCommand) list(ctx context.Context, chatID int64, lang i18n.Lang) *tgbotapi.MessageConfig {
+	notifiers, err := c.dbManager.ListChatNotifiers(ctx, chatID)
+	if err != nil {
+		log.Printf("Error listing notifiers for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.NotifyListFailed))
+		return &msg
+	}
+	if len(notifiers) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.NotifyNone)+"\n\n"+c.usage(lang))
+		return &msg
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.NotifyHeader) + "\n")
+	for _, n := range notifiers {
+		fmt.Fprintf(&sb, "%d. %s → %s%s\n", n.ID, n.Kind, n.Target, signedMark(n.Secret))
+	}
+	sb.WriteString("\n" + i18n.T(lang, i18n.NotifyRemoveHint))
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	return &msg
+}
+
+func (c *NotifyCommand) add(ctx context.Context, chatID int64, lang i18n.Lang, kind, target, secret string) *tgbotapi.MessageConfig {
+	// Building the notifier validates the target before it is stored
+	if _, err := c.registry.Build(kind, target, secret); err != nil {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.NotifyAddFailed)+"\n\n%s", err, c.usage(lang)))
+		return &msg
+	}
+
+	id, err := c.dbManager.AddChatNotifier(ctx, chatID, kind, target, secret)
+	if err != nil {
+		log.Printf("Error adding %s notifier for chat %d: %v", kind, chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	text := fmt.Sprintf(i18n.T(lang, i18n.NotifyAdded), id, kind, target, signedMark(secret))
+	if secret != "" {
+		text += "\n\n" + fmt.Sprintf(i18n.T(lang, i18n.NotifySigned), notify.HeaderSignature)
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	return &msg
+}
+
+func (c *NotifyCommand) remove(ctx context.Context, chatID int64, lang i18n.Lang, arg string) *tgbotapi.MessageConfig {
+	id, err := strconv.Atoi(arg)
+	if err != nil || id <= 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.NotifyNumberRequired))
+		return &msg
+	}
+
+	removed, err := c.dbManager.RemoveChatNotifier(ctx, chatID, id)
+	if err != nil {
+		log.Printf("Error removing notifier %d for chat %d: %v", id, chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	if !removed {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.NotifyNotFound), id))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.NotifyRemoved), id))
+	return &msg
+}
+
+func (c *NotifyCommand) usage(lang i18n.Lang) string {
+	return fmt.Sprintf(i18n.T(lang, i18n.NotifyUsage), strings.Join(c.registry.Kinds(), ", "))
+}
+
+// signedMark shows that a notifier has a secret without revealing it
+func signedMark(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return " 🔒"
+}
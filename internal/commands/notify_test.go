@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/notify"
+)
+
+func newTestNotifyCommand(t *testing.T, mockDB *MockDBManager) *NotifyCommand {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	registry := notify.NewRegistry()
+	registry.Register(notify.KindWebhook, notify.NewWebhookFactory(httpclient.NewClient(httpclient.DefaultConfig())))
+	return NewNotifyCommand(mockDB, registry, admins)
+}
+
+func TestNotifyCommand_NotAdmin(t *testing.T) {
+	mockDB := new(MockDBManager)
+	response := newTestNotifyCommand(t, mockDB).Execute(CreateCommandMessage(100, "/notify", "list"))
+
+	assert.Contains(t, response.Text, "только администраторам")
+	mockDB.AssertNotCalled(t, "ListChatNotifiers", mock.Anything, mock.Anything)
+}
+
+func TestNotifyCommand_List(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("ListChatNotifiers", mock.Anything, int64(42)).Return([]db.ChatNotifier{
+		{ID: 3, ChatID: 42, Kind: "webhook", Target: "https://example.com/hook", Secret: "s3cret"},
+	}, nil)
+
+	response := newTestNotifyCommand(t, mockDB).Execute(CreateCommandMessage(42, "/notify"))
+
+	assert.Contains(t, response.Text, "3. webhook → https://example.com/hook 🔒")
+	assert.NotContains(t, response.Text, "s3cret")
+}
+
+func TestNotifyCommand_AddValidatesTarget(t *testing.T) {
+	mockDB := new(MockDBManager)
+	cmd := newTestNotifyCommand(t, mockDB)
+
+	response := cmd.Execute(CreateCommandMessage(42, "/notify", "add webhook ftp://example.com"))
+	assert.Contains(t, response.Text, "Не удалось добавить")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/notify", "add email ops@example.com"))
+	assert.Contains(t, response.Text, "unknown notifier kind")
+	mockDB.AssertNotCalled(t, "AddChatNotifier", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestNotifyCommand_AddAndRemove(t *testing.T) {
+	mockDB := new(MockDBManager)
+	mockDB.On("AddChatNotifier", mock.Anything, int64(42), "webhook", "https://example.com/hook", "s3cret").Return(7, nil)
+	mockDB.On("RemoveChatNotifier", mock.Anything, int64(42), 7).Return(true, nil)
+	mockDB.On("RemoveChatNotifier", mock.Anything, int64(42), 8).Return(false, nil)
+	cmd := newTestNotifyCommand(t, mockDB)
+
+	response := cmd.Execute(CreateCommandMessage(42, "/notify", "add webhook https://example.com/hook s3cret"))
+	assert.Contains(t, response.Text, "Уведомление 7 добавлено: webhook → https://example.com/hook 🔒")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/notify", "remove 7"))
+	assert.Contains(t, response.Text, "Уведомление 7 удалено")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/notify", "remove 8"))
+	assert.Contains(t, response.Text, "не найдено")
+	mockDB.AssertExpectations(t)
+}
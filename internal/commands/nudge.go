@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/assignee"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// maxNudgeCandidates caps the "assign to @x" buttons of a nudge
+const maxNudgeCandidates = 3
+
+// NudgeMatchSource marks assignees chosen with the buttons of a nudge
+const NudgeMatchSource = "nudge"
+
+// NudgeCandidate is a discussion participant the task can be assigned to
+type NudgeCandidate struct {
+	Username      string
+	TodoistUserID string
+}
+
+// MappingForAlias returns the Todoist user a Telegram alias is mapped to
+func MappingForAlias(mappings []db.AssigneeMapping, alias string) (db.AssigneeMapping, bool) {
+	normalized := assignee.NormalizeAlias(alias)
+	if normalized == "" {
+		return db.AssigneeMapping{}, false
+	}
+	for _, mapping := range mappings {
+		if mapping.AliasNormalized == normalized {
+			return mapping, true
+		}
+	}
+	return db.AssigneeMapping{}, false
+}
+
+// MappingForTodoistUser returns a mapping of the given Todoist user
+func MappingForTodoistUser(mappings []db.AssigneeMapping, todoistUserID string) (db.AssigneeMapping, bool) {
+	for _, mapping := range mappings {
+		if mapping.TodoistUserID == todoistUserID {
+			return mapping, true
+		}
+	}
+	return db.AssigneeMapping{}, false
+}
+
+// NudgeCandidates picks the discussion participants whose usernames are
+// mapped to Todoist users, one per Todoist user, in the order they are given
+func NudgeCandidates(participants []db.SessionParticipant, mappings []db.AssigneeMapping) []NudgeCandidate {
+	var candidates []NudgeCandidate
+	seen := make(map[string]struct{})
+	for _, p := range participants {
+		if !p.Username.Valid {
+			continue
+		}
+		mapping, ok := MappingForAlias(mappings, p.Username.String)
+		if !ok {
+			continue
+		}
+		if _, dup := seen[mapping.TodoistUserID]; dup {
+			continue
+		}
+		seen[mapping.TodoistUserID] = struct{}{}
+		candidates = append(candidates, NudgeCandidate{Username: p.Username.String, TodoistUserID: mapping.TodoistUserID})
+		if len(candidates) == maxNudgeCandidates {
+			break
+		}
+	}
+	return candidates
+}
+
+// TaskNudgeMessage reminds the discussion owner that a created task has no
+// assignee and offers to take it or to assign it to one of the candidates
+func TaskNudgeMessage(nudge db.TaskNudge, candidates []NudgeCandidate, lang i18n.Lang) tgbotapi.MessageConfig {
+	owner := fmt.Sprintf("[%s](tg://user?id=%d)", i18n.T(lang, i18n.NudgeOwner), nudge.OwnerID)
+	if nudge.OwnerUsername.Valid && nudge.OwnerUsername.String != "" {
+		owner = "@" + escapeTelegramMarkdown(nudge.OwnerUsername.String)
+	}
+	title := nudge.Title
+	if title == "" {
+		title = i18n.T(lang, i18n.NudgeUntitled)
+	}
+
+	text := fmt.Sprintf(i18n.T(lang, i18n.NudgeReminder), owner, escapeTelegramMarkdown(title))
+	if nudge.URL != "" {
+		text += "\n" + nudge.URL
+	}
+
+	id := strconv.Itoa(nudge.CreatedTaskID)
+	rows := [][]tgbotapi.InlineKeyboardButton{{
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonNudgeTake), CallbackNudgeTakeTask+CallbackDataSeparator+id),
+	}}
+	for _, c := range candidates {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(
+			"👤 @"+c.Username, CallbackNudgeAssign+CallbackDataSeparator+id+CallbackDataSeparator+c.TodoistUserID,
+		)))
+	}
+
+	msg := tgbotapi.NewMessage(nudge.ChatID, text)
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return msg
+}
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestNudgeCandidates_MapsUsernamesOncePerTodoistUser(t *testing.T) {
+	participants := []db.SessionParticipant{
+		{UserID: 1, Username: sql.NullString{String: "Alice", Valid: true}},
+		{UserID: 2},
+		{UserID: 3, Username: sql.NullString{String: "bob", Valid: true}},
+		{UserID: 4, Username: sql.NullString{String: "alice_work", Valid: true}},
+		{UserID: 5, Username: sql.NullString{String: "stranger", Valid: true}},
+	}
+	mappings := []db.AssigneeMapping{
+		{AliasNormalized: "alice", TodoistUserID: "100"},
+		{AliasNormalized: "alice_work", TodoistUserID: "100"},
+		{AliasNormalized: "bob", TodoistUserID: "200"},
+	}
+
+	candidates := NudgeCandidates(participants, mappings)
+	if len(candidates) != 2 {
+		t.Fatalf("expected 2 candidates, got %+v", candidates)
+	}
+	if candidates[0] != (NudgeCandidate{Username: "Alice", TodoistUserID: "100"}) ||
+		candidates[1] != (NudgeCandidate{Username: "bob", TodoistUserID: "200"}) {
+		t.Fatalf("unexpected candidates: %+v", candidates)
+	}
+}
+
+func TestTaskNudgeMessage_MentionsOwnerAndOffersButtons(t *testing.T) {
+	nudge := db.TaskNudge{
+		CreatedTaskID: 7,
+		ChatID:        -100,
+		OwnerID:       42,
+		OwnerUsername: sql.NullString{String: "team_lead", Valid: true},
+		Title:         "Починить *логин*",
+		URL:           "https://todoist.com/showTask?id=1",
+	}
+	msg := TaskNudgeMessage(nudge, []NudgeCandidate{{Username: "bob", TodoistUserID: "200"}}, i18n.Russian)
+
+	if msg.ChatID != -100 || msg.ParseMode != "Markdown" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+	if !strings.Contains(msg.Text, `@team\_lead`) || !strings.Contains(msg.Text, `Починить \*логин\*`) {
+		t.Fatalf("expected escaped owner mention and title, got %q", msg.Text)
+	}
+
+	markup := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if len(markup.InlineKeyboard) != 2 {
+		t.Fatalf("expected take and assign buttons, got %+v", markup.InlineKeyboard)
+	}
+	if data := *markup.InlineKeyboard[0][0].CallbackData; data != "nudge_take:7" {
+		t.Errorf("unexpected take button data %q", data)
+	}
+	if data := *markup.InlineKeyboard[1][0].CallbackData; data != "nudge_assign:7:200" {
+		t.Errorf("unexpected assign button data %q", data)
+	}
+}
+
+func TestTaskNudgeMessage_LinksOwnerWithoutUsername(t *testing.T) {
+	msg := TaskNudgeMessage(db.TaskNudge{CreatedTaskID: 1, OwnerID: 42, Title: "Задача"}, nil, i18n.Russian)
+	if !strings.Contains(msg.Text, "tg://user?id=42") {
+		t.Fatalf("expected a user link, got %q", msg.Text)
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// ParticipantsCommand lists who wrote in the active discussion and toggles
+// mentioning them all when the draft is ready for review
+type ParticipantsCommand struct {
+	dbManager DBManager
+}
+
+func NewParticipantsCommand(dbManager DBManager) *ParticipantsCommand {
+	return &ParticipantsCommand{dbManager: dbManager}
+}
+
+func (c *ParticipantsCommand) Name() string {
+	return "participants"
+}
+
+func (c *ParticipantsCommand) Description() string {
+	return "Участники обсуждения; /participants summon on|off — звать всех к готовому черновику"
+}
+
+func (c *ParticipantsCommand) MenuScope() MenuScope {
+	return MenuGroups
+}
+
+func (c *ParticipantsCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	args := strings.Fields(message.CommandArguments())
+	switch {
+	case len(args) == 0:
+		return c.list(ctx, chatID, topics.ThreadID(message), lang)
+	case len(args) == 2 && args[0] == "summon" && (args[1] == "on" || args[1] == "off"):
+		enabled := args[1] == "on"
+		if err := c.dbManager.SetSummonParticipants(ctx, chatID, enabled); err != nil {
+			log.Printf("Error setting participant summoning for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		text := i18n.T(lang, i18n.ParticipantsSummonOff)
+		if enabled {
+			text = i18n.T(lang, i18n.ParticipantsSummonOn)
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	default:
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ParticipantsUsage))
+		return &msg
+	}
+}
+
+func (c *ParticipantsCommand) list(ctx context.Context, chatID int64, threadID int, lang i18n.Lang) *tgbotapi.MessageConfig {
+	session, err := c.dbManager.GetActiveSession(ctx, chatID, threadID)
+	if err != nil {
+		if !errors.Is(err, db.ErrNoActiveSession) {
+			log.Printf("Error getting active session for chat %d: %v", chatID, err)
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SessionNoActive))
+		return &msg
+	}
+
+	participants, err := c.dbManager.GetSessionParticipants(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error getting participants of session %d: %v", session.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ParticipantsLoadFailed))
+		return &msg
+	}
+	if len(participants) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ParticipantsNone))
+		return &msg
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(i18n.T(lang, i18n.ParticipantsHeader)+"\n\n", len(participants)))
+	for _, p := range participants {
+		sb.WriteString(fmt.Sprintf(i18n.T(lang, i18n.ParticipantsLine)+"\n", participantName(p), p.MessageCount))
+	}
+
+	enabled, err := c.dbManager.SummonParticipantsEnabled(ctx, chatID)
+	if err != nil {
+		log.Printf("Error checking participant summoning for chat %d: %v", chatID, err)
+	}
+	if enabled {
+		sb.WriteString("\n" + i18n.T(lang, i18n.ParticipantsSummonEnabled))
+	} else {
+		sb.WriteString("\n" + i18n.T(lang, i18n.ParticipantsSummonDisabled))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, sb.String())
+	msg.ParseMode = "Markdown"
+	return &msg
+}
+
+// participantName shows a participant without notifying them
+func participantName(p db.SessionParticipant) string {
+	if p.DisplayName.Valid {
+		return escapeTelegramMarkdown(p.DisplayName.String)
+	}
+	if p.Username.Valid {
+		return escapeTelegramMarkdown(p.Username.String)
+	}
+	return fmt.Sprintf("id %d", p.UserID)
+}
+
+// mentionNameReplacer keeps a display name from breaking the Markdown link around it
+var mentionNameReplacer = strings.NewReplacer("[", "(", "]", ")", "*", "", "_", " ", "`", "")
+
+// FormatParticipantMentions mentions every participant except the given user
+// in Markdown: by @username, or by a user link for people without one.
+// It returns an empty string when there is nobody to mention.
+func FormatParticipantMentions(participants []db.SessionParticipant, exceptUserID int64, lang i18n.Lang) string {
+	mentions := make([]string, 0, len(participants))
+	for _, p := range participants {
+		if p.UserID == exceptUserID {
+			continue
+		}
+		if p.Username.Valid {
+			mentions = append(mentions, "@"+escapeTelegramMarkdown(p.Username.String))
+			continue
+		}
+		name := i18n.T(lang, i18n.ParticipantsUnnamed)
+		if p.DisplayName.Valid {
+			name = mentionNameReplacer.Replace(p.DisplayName.String)
+		}
+		mentions = append(mentions, fmt.Sprintf("[%s](tg://user?id=%d)", name, p.UserID))
+	}
+	return strings.Join(mentions, ", ")
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestParticipantsCommand_Execute(t *testing.T) {
+	chatID := int64(123456789)
+
+	t.Run("lists participants of the active discussion", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7}, nil)
+		mockDB.On("GetSessionParticipants", mock.Anything, 7).Return([]db.SessionParticipant{
+			{UserID: 1, Username: sql.NullString{String: "ivan_p", Valid: true}, DisplayName: sql.NullString{String: "Иван", Valid: true}, MessageCount: 3},
+			{UserID: 2, Username: sql.NullString{String: "maria", Valid: true}, MessageCount: 1},
+		}, nil)
+		mockDB.On("SummonParticipantsEnabled", mock.Anything, chatID).Return(false, nil)
+
+		response := NewParticipantsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/participants"))
+
+		assert.Contains(t, response.Text, "(2)")
+		assert.Contains(t, response.Text, "• Иван — сообщений: 3")
+		assert.Contains(t, response.Text, "• maria — сообщений: 1")
+		assert.Contains(t, response.Text, "/participants summon on")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("reports missing discussion", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(nil, db.ErrNoActiveSession)
+
+		response := NewParticipantsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/participants"))
+
+		assert.Contains(t, response.Text, "Нет активного обсуждения")
+	})
+
+	t.Run("turns summoning on", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetSummonParticipants", mock.Anything, chatID, true).Return(nil)
+
+		response := NewParticipantsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/participants", "summon on"))
+
+		assert.Contains(t, response.Text, "упомяну всех")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown argument", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewParticipantsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/participants", "summon"))
+
+		assert.Contains(t, response.Text, "Использование")
+		mockDB.AssertNotCalled(t, "SetSummonParticipants", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFormatParticipantMentions(t *testing.T) {
+	participants := []db.SessionParticipant{
+		{UserID: 1, Username: sql.NullString{String: "owner", Valid: true}},
+		{UserID: 2, Username: sql.NullString{String: "ivan_p", Valid: true}},
+		{UserID: 3, DisplayName: sql.NullString{String: "Мария [QA]", Valid: true}},
+		{UserID: 4},
+	}
+
+	got := FormatParticipantMentions(participants, 1, i18n.Russian)
+
+	assert.Equal(t, "@ivan\\_p, [Мария (QA)](tg://user?id=3), [участник](tg://user?id=4)", got)
+	assert.Empty(t, FormatParticipantMentions(participants[:1], 1, i18n.Russian))
+}
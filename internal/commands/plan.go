@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/plans"
+)
+
+// PlanManager exposes chat plans when billing is enabled
+type PlanManager interface {
+	Status(ctx context.Context, chatID int64) (plans.Status, error)
+	SetPlan(ctx context.Context, chatID int64, plan plans.Plan) error
+	ParsePlan(name string) (plans.Plan, bool)
+}
+
+// PlanCommand shows the chat's plan and usage; admins can switch plans
+type PlanCommand struct {
+	plans     PlanManager
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewPlanCommand(planManager PlanManager, dbManager DBManager, admins admin.Users) *PlanCommand {
+	return &PlanCommand{
+		plans:     planManager,
+		dbManager: dbManager,
+		admins:    admins,
+	}
+}
+
+func (c *PlanCommand) Name() string {
+	return "plan"
+}
+
+func (c *PlanCommand) Description() string {
+	return "Тариф чата и расход лимитов; администраторы: /plan <free|pro>"
+}
+
+func (c *PlanCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		if !c.admins.Contains(message.From.ID) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PlanAdminOnly))
+			return &msg
+		}
+		plan, ok := c.plans.ParsePlan(arg)
+		if !ok {
+			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.PlanUnknown), arg))
+			return &msg
+		}
+		if err := c.plans.SetPlan(ctx, chatID, plan); err != nil {
+			log.Printf("Error setting plan for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PlanSetFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.PlanSet), plan))
+		return &msg
+	}
+
+	status, err := c.plans.Status(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting plan status for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PlanStatusFailed))
+		return &msg
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, i18n.T(lang, i18n.PlanStatus), status.Plan)
+	for _, usage := range status.Usage {
+		if usage.Limit > 0 {
+			fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.PlanUsage), featureTitle(usage.Feature, lang), usage.Used, usage.Limit)
+		} else {
+			fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.PlanUsageUnlimited), featureTitle(usage.Feature, lang), usage.Used)
+		}
+	}
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	return &msg
+}
+
+// UpgradePromptText explains a denied feature and how to lift the limit
+func UpgradePromptText(decision plans.Decision, lang i18n.Lang) string {
+	text := fmt.Sprintf(
+		i18n.T(lang, i18n.PlanExhausted),
+		featureTitle(decision.Feature, lang), decision.Plan, decision.Used, decision.Limit,
+	)
+	if decision.UpgradeURL != "" {
+		return text + "\n" + fmt.Sprintf(i18n.T(lang, i18n.PlanUpgradeURL), decision.UpgradeURL)
+	}
+	return text + "\n" + i18n.T(lang, i18n.PlanUpgradeAdmin)
+}
+
+func featureTitle(feature plans.Feature, lang i18n.Lang) string {
+	switch feature {
+	case plans.FeatureAIEdit:
+		return i18n.T(lang, i18n.PlanFeatureAIEdit)
+	default:
+		return string(feature)
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/plans"
+)
+
+type fakePlanManager struct {
+	status plans.Status
+	setTo  plans.Plan
+}
+
+func (f *fakePlanManager) Status(ctx context.Context, chatID int64) (plans.Status, error) {
+	return f.status, nil
+}
+
+func (f *fakePlanManager) SetPlan(ctx context.Context, chatID int64, plan plans.Plan) error {
+	f.setTo = plan
+	return nil
+}
+
+func (f *fakePlanManager) ParsePlan(name string) (plans.Plan, bool) {
+	return plans.Plan(name), name == "free" || name == "pro"
+}
+
+func TestPlanCommand_Execute_ShowsUsage(t *testing.T) {
+	manager := &fakePlanManager{status: plans.Status{
+		Plan:  plans.PlanFree,
+		Usage: []plans.FeatureUsage{{Feature: plans.FeatureAIEdit, Used: 5, Limit: 20}},
+	}}
+	cmd := NewPlanCommand(manager, new(MockDBManager), admin.Users{})
+
+	response := cmd.Execute(CreateCommandMessage(1, "/plan"))
+
+	assert.Contains(t, response.Text, "Тариф чата: free")
+	assert.Contains(t, response.Text, "AI-правки в этом месяце: 5 из 20")
+}
+
+func TestPlanCommand_Execute_SetPlan(t *testing.T) {
+	manager := &fakePlanManager{}
+	admins, _ := admin.ParseUsers("1")
+	cmd := NewPlanCommand(manager, new(MockDBManager), admins)
+
+	response := cmd.Execute(CreateCommandMessage(2, "/plan", "pro"))
+	assert.Contains(t, response.Text, "только администратор")
+	assert.Empty(t, manager.setTo)
+
+	response = cmd.Execute(CreateCommandMessage(1, "/plan", "pro"))
+	assert.Contains(t, response.Text, "Тариф чата: pro")
+	assert.Equal(t, plans.PlanPro, manager.setTo)
+
+	response = cmd.Execute(CreateCommandMessage(1, "/plan", "gold"))
+	assert.Contains(t, response.Text, "Неизвестный тариф")
+}
+
+func TestUpgradePromptText(t *testing.T) {
+	text := UpgradePromptText(plans.Decision{Plan: plans.PlanFree, Feature: plans.FeatureAIEdit, Used: 20, Limit: 20, UpgradeURL: "https://example.com/pro"}, i18n.Russian)
+
+	assert.Contains(t, text, "AI-правки на тарифе «free» закончились: использовано 20 из 20")
+	assert.Contains(t, text, "https://example.com/pro")
+}
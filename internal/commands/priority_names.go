@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+// PriorityNamesCommand renames priority levels for a chat, e.g. to the team's Jira wording
+type PriorityNamesCommand struct {
+	dbManager DBManager
+}
+
+func NewPriorityNamesCommand(dbManager DBManager) *PriorityNamesCommand {
+	return &PriorityNamesCommand{dbManager: dbManager}
+}
+
+func (c *PriorityNamesCommand) Name() string {
+	return "priority_names"
+}
+
+func (c *PriorityNamesCommand) Description() string {
+	return "Названия приоритетов: /priority_names high=Мажор, urgent=Блокер или /priority_names reset"
+}
+
+func (c *PriorityNamesCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	switch arg := strings.TrimSpace(message.CommandArguments()); arg {
+	case "":
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PriorityNamesCurrent)+"\n"+formatPriorityNames(ChatPriorityNames(ctx, c.dbManager, chatID))+
+			"\n\n"+i18n.T(lang, i18n.PriorityNamesHint))
+		return &msg
+	case "reset":
+		if err := c.dbManager.SetPriorityNames(ctx, chatID, ""); err != nil {
+			log.Printf("Error resetting priority names for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PriorityNamesReset)+"\n"+formatPriorityNames(priority.DefaultNames))
+		return &msg
+	default:
+		names, err := priority.ParseNames(arg)
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PriorityNamesInvalid))
+			return &msg
+		}
+
+		// New names are merged into the current ones, so levels can be renamed one by one
+		merged := ChatPriorityNames(ctx, c.dbManager, chatID)
+		for level, name := range names {
+			merged[level] = name
+		}
+		if err := c.dbManager.SetPriorityNames(ctx, chatID, merged.String()); err != nil {
+			log.Printf("Error setting priority names for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.PriorityNamesCurrent)+"\n"+formatPriorityNames(merged))
+		return &msg
+	}
+}
+
+// ChatPriorityNames returns the priority display names of a chat; errors fall back to the defaults
+func ChatPriorityNames(ctx context.Context, dbManager DBManager, chatID int64) priority.Names {
+	stored, err := dbManager.GetPriorityNames(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting priority names for chat %d: %v", chatID, err)
+		return priority.Names{}
+	}
+	names, err := priority.ParseNames(stored)
+	if err != nil {
+		log.Printf("Ignoring invalid priority names %q for chat %d: %v", stored, chatID, err)
+		return priority.Names{}
+	}
+	return names
+}
+
+// ApplyPriorityNames sets the displayed priority of task from its level
+func ApplyPriorityNames(task *ai.AnalyzedTask, names priority.Names) {
+	if level := priority.FromTodoist(task.Priority); level.Valid() {
+		task.PriorityText = names.Display(level)
+	}
+}
+
+func formatPriorityNames(names priority.Names) string {
+	lines := make([]string, 0, len(priority.Levels))
+	for i := len(priority.Levels) - 1; i >= 0; i-- {
+		level := priority.Levels[i]
+		lines = append(lines, fmt.Sprintf("• %s — %s", level.Key(), names.Display(level)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+func TestPriorityNamesCommand_MergesOverrides(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("urgent=Блокер", nil)
+	mockDB.On("SetPriorityNames", mock.Anything, chatID, "high=Мажор,urgent=Блокер").Return(nil)
+
+	msg := NewPriorityNamesCommand(mockDB).Execute(CreateCommandMessage(chatID, "/priority_names", "high=Мажор"))
+
+	assert.Contains(t, msg.Text, "high — Мажор")
+	assert.Contains(t, msg.Text, "urgent — Блокер")
+	assert.Contains(t, msg.Text, "low — Низкий")
+	mockDB.AssertExpectations(t)
+}
+
+func TestPriorityNamesCommand_RejectsUnknownLevel(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+
+	msg := NewPriorityNamesCommand(mockDB).Execute(CreateCommandMessage(chatID, "/priority_names", "p1=Срочно"))
+
+	assert.Contains(t, msg.Text, "Не понял")
+	mockDB.AssertNotCalled(t, "SetPriorityNames", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplyPriorityNames_UsesChatNames(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("high=Мажор", nil)
+
+	task := &ai.AnalyzedTask{Priority: 3, PriorityText: "High"}
+	ApplyPriorityNames(task, ChatPriorityNames(context.Background(), mockDB, chatID))
+	assert.Equal(t, "Мажор", task.PriorityText)
+
+	task = &ai.AnalyzedTask{Priority: 2}
+	ApplyPriorityNames(task, priority.Names{})
+	assert.Equal(t, "Средний", task.PriorityText)
+}
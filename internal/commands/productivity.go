@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+const (
+	productivityDays    = 14
+	productivityWeeks   = 4
+	productivityTimeout = 20 * time.Second
+)
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// ProductivityCommand shows how many tasks of the chat's project were completed recently
+type ProductivityCommand struct {
+	reporter  todoist.ProductivityReporter
+	dbManager DBManager
+	now       func() time.Time
+}
+
+func NewProductivityCommand(reporter todoist.ProductivityReporter, dbManager DBManager) *ProductivityCommand {
+	return &ProductivityCommand{reporter: reporter, dbManager: dbManager, now: time.Now}
+}
+
+func (c *ProductivityCommand) Name() string {
+	return "productivity"
+}
+
+func (c *ProductivityCommand) Description() string {
+	return "Статистика закрытых задач проекта по дням и неделям"
+}
+
+func (c *ProductivityCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	ctx, cancel := context.WithTimeout(context.Background(), productivityTimeout)
+	defer cancel()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectChooseFirstCommand))
+		return &msg
+	}
+
+	now := c.now().In(ChatLocation(ctx, c.dbManager, chatID))
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	since := today.AddDate(0, 0, -(productivityWeeks*7 - 1))
+
+	tasks, err := c.reporter.GetCompletedTasks(ctx, projectID, since, now)
+	if err != nil {
+		log.Printf("Error getting completed tasks for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProductivityFailed))
+		return &msg
+	}
+
+	// Karma belongs to the token's account, so it is a bonus line rather than a requirement
+	stats, err := c.reporter.GetProductivityStats(ctx)
+	if err != nil {
+		log.Printf("Error getting productivity stats: %v", err)
+		stats = nil
+	}
+
+	msg := tgbotapi.NewMessage(chatID, FormatProductivity(tasks, stats, today, lang))
+	return &msg
+}
+
+// FormatProductivity renders daily and weekly completion counts ending with today
+func FormatProductivity(tasks []todoist.CompletedTask, stats *todoist.ProductivityStats, today time.Time, lang i18n.Lang) string {
+	days := productivityWeeks * 7
+	counts := make([]int, days)
+	first := today.AddDate(0, 0, -(days - 1))
+	for _, task := range tasks {
+		completed := task.CompletedAt.In(today.Location())
+		day := time.Date(completed.Year(), completed.Month(), completed.Day(), 0, 0, 0, 0, today.Location())
+		index := int(day.Sub(first).Hours() / 24)
+		if index >= 0 && index < days {
+			counts[index]++
+		}
+	}
+
+	recent := counts[days-productivityDays:]
+	total, best := 0, 0
+	for i, n := range recent {
+		total += n
+		if n > recent[best] {
+			best = i
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.ProductivityHeader) + "\n\n")
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.ProductivityDays)+"\n", productivityDays, Sparkline(recent))
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.ProductivityTotal), total, float64(total)/productivityDays)
+	if recent[best] > 0 {
+		bestDay := today.AddDate(0, 0, best-(productivityDays-1))
+		fmt.Fprintf(&sb, i18n.T(lang, i18n.ProductivityBestDay), bestDay.Format("02.01"), recent[best])
+	}
+	sb.WriteString("\n\n" + i18n.T(lang, i18n.ProductivityWeeks))
+	weeks := make([]string, productivityWeeks)
+	for w := 0; w < productivityWeeks; w++ {
+		sum := 0
+		for _, n := range counts[w*7 : (w+1)*7] {
+			sum += n
+		}
+		weeks[w] = fmt.Sprintf("%d", sum)
+	}
+	sb.WriteString(strings.Join(weeks, " → "))
+	sb.WriteString(i18n.T(lang, i18n.ProductivityWeeksNote))
+
+	if stats != nil {
+		trend := ""
+		switch stats.KarmaTrend {
+		case "up":
+			trend = " ↑"
+		case "down":
+			trend = " ↓"
+		}
+		fmt.Fprintf(&sb, "\n\n"+i18n.T(lang, i18n.ProductivityKarma), stats.Karma, trend)
+	}
+	return sb.String()
+}
+
+// Sparkline draws counts as a row of bars scaled to the largest one
+func Sparkline(counts []int) string {
+	peak := 0
+	for _, n := range counts {
+		if n > peak {
+			peak = n
+		}
+	}
+	bars := make([]rune, len(counts))
+	for i, n := range counts {
+		if peak == 0 {
+			bars[i] = sparkBars[0]
+			continue
+		}
+		bars[i] = sparkBars[n*(len(sparkBars)-1)/peak]
+	}
+	return string(bars)
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestSparkline(t *testing.T) {
+	assert.Equal(t, "▁▄█", Sparkline([]int{0, 2, 4}))
+	assert.Equal(t, "▁▁", Sparkline([]int{0, 0}))
+}
+
+func TestFormatProductivity(t *testing.T) {
+	today := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+	tasks := []todoist.CompletedTask{
+		{ID: "1", CompletedAt: time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)},
+		{ID: "2", CompletedAt: time.Date(2026, 10, 15, 18, 0, 0, 0, time.UTC)},
+		{ID: "3", CompletedAt: time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)},
+		{ID: "4", CompletedAt: time.Date(2026, 9, 20, 12, 0, 0, 0, time.UTC)},
+		{ID: "5", CompletedAt: time.Date(2026, 8, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	text := FormatProductivity(tasks, &todoist.ProductivityStats{Karma: 1520.4, KarmaTrend: "up"}, today, i18n.Russian)
+
+	assert.Contains(t, text, "Всего 3")
+	assert.Contains(t, text, "лучший день 15.10 — 2")
+	assert.Contains(t, text, "По неделям: 1 → 0 → 0 → 3")
+	assert.Contains(t, text, "Карма Todoist: 1520 ↑")
+	assert.Contains(t, text, "▁▁▁▁▁▁▁▁▁▁▁▁▄█")
+}
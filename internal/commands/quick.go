@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// QuickCommand creates a Todoist task from one line of Quick Add syntax,
+// bypassing the discussion and the AI
+type QuickCommand struct {
+	adder     todoist.QuickAdder
+	dbManager DBManager
+	trackers  *tracker.Selector
+}
+
+func NewQuickCommand(adder todoist.QuickAdder, dbManager DBManager) *QuickCommand {
+	return &QuickCommand{adder: adder, dbManager: dbManager}
+}
+
+// SetTrackers sets the trackers of the chats; Quick Add only serves Todoist chats
+func (c *QuickCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *QuickCommand) Name() string {
+	return "quick"
+}
+
+func (c *QuickCommand) Description() string {
+	return "Быстрая задача без AI: /quick купить молоко завтра p1 @дела #Дом"
+}
+
+func (c *QuickCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	if c.trackers != nil && c.trackers.Kind(chatID) != tracker.KindTodoist {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuickTodoistOnly))
+		return &msg
+	}
+
+	text := strings.TrimSpace(message.CommandArguments())
+	if text == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuickUsage))
+		return &msg
+	}
+
+	task, err := c.adder.QuickAddTask(ctx, text)
+	if err != nil {
+		log.Printf("Error quick adding task in chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuickFailed))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(chatID, formatQuickTask(task, lang))
+	msg.ParseMode = "Markdown"
+	msg.DisableWebPagePreview = true
+	return &msg
+}
+
+func formatQuickTask(task *todoist.TaskResponse, lang i18n.Lang) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, i18n.T(lang, i18n.QuickCreated), escapeTelegramMarkdown(task.Content), todoist.TaskURL(task.ID))
+	if task.Due != nil && task.Due.String != "" {
+		fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.QuickDue), escapeTelegramMarkdown(task.Due.String))
+	}
+	if labels := cleanLabels(task.Labels); len(labels) > 0 {
+		fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.QuickLabels), escapeTelegramMarkdown(strings.Join(labels, ", ")))
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/quickedit"
+)
+
+// quickDueButtons are the due shortcuts under a draft preview, in button order
+var quickDueButtons = []struct {
+	shortcut string
+	text     i18n.Key
+}{
+	{quickedit.DueToday, i18n.ButtonDueToday},
+	{quickedit.DueTomorrow, i18n.ButtonDueTomorrow},
+	{quickedit.DueNextWeek, i18n.ButtonDueNextWeek},
+	{quickedit.DueClear, i18n.ButtonDueClear},
+}
+
+// quickEditRows are the priority and due buttons that change a draft without the AI
+func quickEditRows(sessionID int, lang i18n.Lang) [][]tgbotapi.InlineKeyboardButton {
+	var priorityRow []tgbotapi.InlineKeyboardButton
+	// P1 is the most urgent, as Todoist shows it, down to the lowest P4
+	for i := len(priority.Levels) - 1; i >= 0; i-- {
+		level := priority.Levels[i]
+		data := fmt.Sprintf("%s%s%d%s%d", CallbackQuickPriority, CallbackDataSeparator, sessionID, CallbackDataSeparator, level.Todoist())
+		priorityRow = append(priorityRow, tgbotapi.NewInlineKeyboardButtonData(quickPriorityLabel(level), data))
+	}
+
+	var dueRow []tgbotapi.InlineKeyboardButton
+	for _, button := range quickDueButtons {
+		data := fmt.Sprintf("%s%s%d%s%s", CallbackQuickDue, CallbackDataSeparator, sessionID, CallbackDataSeparator, button.shortcut)
+		dueRow = append(dueRow, tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, button.text), data))
+	}
+	return [][]tgbotapi.InlineKeyboardButton{priorityRow, dueRow}
+}
+
+// QuickEditFromButton turns a priority or due button into a draft edit and
+// the instruction in lang it is recorded under in the draft history. Due dates
+// are resolved relative to now.
+func QuickEditFromButton(data CallbackData, now time.Time, lang i18n.Lang) (quickedit.Edit, string, bool) {
+	switch data.Action {
+	case CallbackQuickPriority:
+		value, _ := strconv.Atoi(data.Arg(1))
+		level := priority.FromTodoist(value)
+		if !level.Valid() {
+			return quickedit.Edit{}, "", false
+		}
+		return quickedit.Edit{Priority: &value}, fmt.Sprintf(i18n.T(lang, i18n.QuickEditPriority), quickPriorityLabel(level)), true
+	case CallbackQuickDue:
+		due, ok := quickedit.DueShortcut(data.Arg(1), now)
+		if !ok {
+			return quickedit.Edit{}, "", false
+		}
+		if due == "" {
+			return quickedit.Edit{DueDate: &due}, i18n.T(lang, i18n.QuickEditNoDue), true
+		}
+		return quickedit.Edit{DueDate: &due}, fmt.Sprintf(i18n.T(lang, i18n.QuickEditDue), due), true
+	}
+	return quickedit.Edit{}, "", false
+}
+
+// quickPriorityLabel names a level P1–P4, P1 being urgent
+func quickPriorityLabel(level priority.Level) string {
+	return fmt.Sprintf("P%d", int(priority.LevelUrgent)-int(level)+1)
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestCreateInlineKeyboard_HasQuickEditRows(t *testing.T) {
+	keyboard := CreateInlineKeyboard(42, i18n.Russian)
+
+	require.Len(t, keyboard.InlineKeyboard, 3)
+	priorities := keyboard.InlineKeyboard[1]
+	require.Len(t, priorities, 4)
+	assert.Equal(t, "P1", priorities[0].Text)
+	assert.Equal(t, "quick_priority:42:4", *priorities[0].CallbackData)
+	assert.Equal(t, "P4", priorities[3].Text)
+	assert.Equal(t, "quick_priority:42:1", *priorities[3].CallbackData)
+	assert.Equal(t, "quick_due:42:next_week", *keyboard.InlineKeyboard[2][2].CallbackData)
+}
+
+func TestQuickEditFromButton(t *testing.T) {
+	// Thursday
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	edit, instruction, ok := QuickEditFromButton(ParseCallbackData("quick_priority:42:3"), now, i18n.Russian)
+	require.True(t, ok)
+	assert.Equal(t, 3, *edit.Priority)
+	assert.Nil(t, edit.DueDate)
+	assert.Equal(t, "приоритет P2", instruction)
+
+	edit, instruction, ok = QuickEditFromButton(ParseCallbackData("quick_due:42:tomorrow"), now, i18n.Russian)
+	require.True(t, ok)
+	assert.Equal(t, "2026-10-16", *edit.DueDate)
+	assert.Equal(t, "срок 2026-10-16", instruction)
+
+	edit, instruction, ok = QuickEditFromButton(ParseCallbackData("quick_due:42:clear"), now, i18n.Russian)
+	require.True(t, ok)
+	assert.Equal(t, "", *edit.DueDate)
+	assert.Equal(t, "без срока", instruction)
+
+	for _, data := range []string{"quick_priority:42:5", "quick_priority:42", "quick_due:42:someday", "confirm_task:42"} {
+		_, _, ok := QuickEditFromButton(ParseCallbackData(data), now, i18n.Russian)
+		assert.False(t, ok, data)
+	}
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// fakeQuickAdder records the Quick Add text
+type fakeQuickAdder struct {
+	text string
+	err  error
+}
+
+func (f *fakeQuickAdder) QuickAddTask(ctx context.Context, text string) (*todoist.TaskResponse, error) {
+	f.text = text
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &todoist.TaskResponse{ID: "7", Content: "купить молоко", Labels: []string{"дела"}, Due: &todoist.DueObject{String: "завтра"}}, nil
+}
+
+func TestQuickCommand(t *testing.T) {
+	chatID := int64(123456789)
+	adder := &fakeQuickAdder{}
+	cmd := NewQuickCommand(adder, new(MockDBManager))
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/quick", "купить молоко завтра p1 @дела"))
+
+	assert.Equal(t, "купить молоко завтра p1 @дела", adder.text)
+	assert.Contains(t, response.Text, "[купить молоко](https://app.todoist.com/app/task/7)")
+	assert.Contains(t, response.Text, "*Срок:* завтра")
+	assert.Contains(t, response.Text, "*Метки:* дела")
+}
+
+func TestQuickCommand_Usage(t *testing.T) {
+	adder := &fakeQuickAdder{}
+	response := NewQuickCommand(adder, new(MockDBManager)).Execute(CreateCommandMessage(1, "/quick"))
+
+	assert.Contains(t, response.Text, "Использование")
+	assert.Empty(t, adder.text)
+}
+
+func TestQuickCommand_Errors(t *testing.T) {
+	response := NewQuickCommand(&fakeQuickAdder{err: errors.New("boom")}, new(MockDBManager)).Execute(CreateCommandMessage(1, "/quick", "задача"))
+	assert.Contains(t, response.Text, "Не удалось создать задачу")
+
+	cmd := NewQuickCommand(&fakeQuickAdder{}, new(MockDBManager))
+	cmd.SetTrackers(tracker.NewSelector(httpclient.TrackersConfig{Default: tracker.KindJira}))
+	response = cmd.Execute(CreateCommandMessage(1, "/quick", "задача"))
+	assert.Contains(t, response.Text, "только для Todoist")
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quiethours"
+)
+
+// QuietHoursCommand configures the daily window when non-urgent messages are deferred
+type QuietHoursCommand struct {
+	dbManager DBManager
+}
+
+func NewQuietHoursCommand(dbManager DBManager) *QuietHoursCommand {
+	return &QuietHoursCommand{dbManager: dbManager}
+}
+
+func (c *QuietHoursCommand) Name() string {
+	return "quiet_hours"
+}
+
+func (c *QuietHoursCommand) Description() string {
+	return "Тихие часы для уведомлений: /quiet_hours 22:00-08:00 или /quiet_hours off"
+}
+
+func (c *QuietHoursCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	switch arg := strings.TrimSpace(message.CommandArguments()); arg {
+	case "":
+		window, err := c.dbManager.GetQuietHours(ctx, chatID)
+		if err != nil {
+			log.Printf("Error getting quiet hours for chat %d: %v", chatID, err)
+		}
+		text := i18n.T(lang, i18n.QuietHoursUnset)
+		if window != "" {
+			text = fmt.Sprintf(i18n.T(lang, i18n.QuietHoursStatus), window, ChatLocation(ctx, c.dbManager, chatID))
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	case "off":
+		if err := c.dbManager.SetQuietHours(ctx, chatID, ""); err != nil {
+			log.Printf("Error clearing quiet hours for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuietHoursOff))
+		return &msg
+	default:
+		window, err := quiethours.Parse(arg)
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuietHoursInvalid))
+			return &msg
+		}
+		if err := c.dbManager.SetQuietHours(ctx, chatID, window.String()); err != nil {
+			log.Printf("Error setting quiet hours for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.QuietHoursSet), window, ChatLocation(ctx, c.dbManager, chatID)))
+		return &msg
+	}
+}
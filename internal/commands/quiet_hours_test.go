@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestQuietHoursCommand_Execute(t *testing.T) {
+	chatID := int64(123456789)
+
+	t.Run("shows current window", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetQuietHours", mock.Anything, chatID).Return("22:00-08:00", nil)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+
+		response := NewQuietHoursCommand(mockDB).Execute(CreateCommandMessage(chatID, "/quiet_hours"))
+
+		assert.Contains(t, response.Text, "22:00-08:00")
+	})
+
+	t.Run("stores normalized window", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetQuietHours", mock.Anything, chatID, "23:00-07:30").Return(nil)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+
+		response := NewQuietHoursCommand(mockDB).Execute(CreateCommandMessage(chatID, "/quiet_hours", "23-7:30"))
+
+		assert.Contains(t, response.Text, "23:00-07:30")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("turns quiet hours off", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetQuietHours", mock.Anything, chatID, "").Return(nil)
+
+		response := NewQuietHoursCommand(mockDB).Execute(CreateCommandMessage(chatID, "/quiet_hours", "off"))
+
+		assert.Contains(t, response.Text, "выключены")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid window", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewQuietHoursCommand(mockDB).Execute(CreateCommandMessage(chatID, "/quiet_hours", "ночью"))
+
+		assert.Contains(t, response.Text, "Использование")
+		mockDB.AssertNotCalled(t, "SetQuietHours", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/quota"
+)
+
+// QuotaCommand shows task quota usage and lets admins lift quotas for a chat
+type QuotaCommand struct {
+	dbManager DBManager
+	limits    quota.Limits
+	admins    admin.Users
+}
+
+func NewQuotaCommand(dbManager DBManager, limits quota.Limits, admins admin.Users) *QuotaCommand {
+	return &QuotaCommand{
+		dbManager: dbManager,
+		limits:    limits,
+		admins:    admins,
+	}
+}
+
+func (c *QuotaCommand) Name() string {
+	return "quota"
+}
+
+func (c *QuotaCommand) Description() string {
+	return "Лимиты анализа обсуждений; администраторы: /quota off|on"
+}
+
+func (c *QuotaCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	switch arg := strings.TrimSpace(message.CommandArguments()); arg {
+	case "":
+		return c.showUsage(ctx, message, lang)
+	case "off", "on":
+		if !c.admins.Contains(message.From.ID) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaAdminOnly))
+			return &msg
+		}
+		exempt := arg == "off"
+		if err := c.dbManager.SetQuotaExempt(ctx, chatID, exempt); err != nil {
+			log.Printf("Error setting quota exemption for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaSetFailed))
+			return &msg
+		}
+		text := i18n.T(lang, i18n.QuotaRestored)
+		if exempt {
+			text = i18n.T(lang, i18n.QuotaLifted)
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	default:
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaUsage))
+		return &msg
+	}
+}
+
+func (c *QuotaCommand) showUsage(ctx context.Context, message *tgbotapi.Message, lang i18n.Lang) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	if !c.limits.Enabled() {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaDisabled))
+		return &msg
+	}
+
+	exempt, err := c.dbManager.IsQuotaExempt(ctx, chatID)
+	if err != nil {
+		log.Printf("Error checking quota exemption for chat %d: %v", chatID, err)
+	}
+	if exempt {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaExempt))
+		return &msg
+	}
+
+	chatCount, userCount, err := c.dbManager.CountTaskAnalyses(ctx, chatID, message.From.ID, time.Now().Add(-quota.Window))
+	if err != nil {
+		log.Printf("Error counting task analyses for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.QuotaStatsFailed))
+		return &msg
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(lang, i18n.QuotaHeader))
+	if c.limits.PerChat > 0 {
+		fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.QuotaChat), chatCount, c.limits.PerChat)
+	}
+	if c.limits.PerUser > 0 {
+		fmt.Fprintf(&b, "\n"+i18n.T(lang, i18n.QuotaUser), userCount, c.limits.PerUser)
+	}
+	msg := tgbotapi.NewMessage(chatID, b.String())
+	return &msg
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/quota"
+)
+
+func TestQuotaCommand_Execute_ShowsUsage(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("IsQuotaExempt", mock.Anything, chatID).Return(false, nil)
+	mockDB.On("CountTaskAnalyses", mock.Anything, chatID, chatID, mock.Anything).Return(4, 2, nil)
+
+	cmd := NewQuotaCommand(mockDB, quota.Limits{PerChat: 10, PerUser: 3}, admin.Users{})
+	response := cmd.Execute(CreateCommandMessage(chatID, "/quota"))
+
+	assert.Contains(t, response.Text, "Чат: 4 из 10")
+	assert.Contains(t, response.Text, "Вы: 2 из 3")
+	mockDB.AssertExpectations(t)
+}
+
+func TestQuotaCommand_Execute_OverrideRequiresAdmin(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+
+	cmd := NewQuotaCommand(mockDB, quota.Limits{PerChat: 10}, admin.Users{})
+	response := cmd.Execute(CreateCommandMessage(chatID, "/quota", "off"))
+
+	assert.Contains(t, response.Text, "только администратор")
+	mockDB.AssertNotCalled(t, "SetQuotaExempt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestQuotaCommand_Execute_AdminLiftsQuota(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("SetQuotaExempt", mock.Anything, chatID, true).Return(nil)
+
+	admins, _ := admin.ParseUsers("123456789")
+	cmd := NewQuotaCommand(mockDB, quota.Limits{PerChat: 10}, admins)
+	response := cmd.Execute(CreateCommandMessage(chatID, "/quota", "off"))
+
+	assert.Contains(t, response.Text, "сняты")
+	mockDB.AssertExpectations(t)
+}
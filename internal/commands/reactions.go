@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// ReactionsCommand toggles emoji acknowledgements of messages captured in a discussion
+type ReactionsCommand struct {
+	dbManager DBManager
+}
+
+func NewReactionsCommand(dbManager DBManager) *ReactionsCommand {
+	return &ReactionsCommand{dbManager: dbManager}
+}
+
+func (c *ReactionsCommand) Name() string {
+	return "reactions"
+}
+
+func (c *ReactionsCommand) Description() string {
+	return "Отмечать сохранённые в обсуждение сообщения реакцией: /reactions on|off"
+}
+
+func (c *ReactionsCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	switch arg := strings.TrimSpace(message.CommandArguments()); arg {
+	case "":
+		enabled, err := c.dbManager.ReactionAckEnabled(ctx, chatID)
+		if err != nil {
+			log.Printf("Error checking reaction acknowledgements for chat %d: %v", chatID, err)
+		}
+		text := i18n.T(lang, i18n.ReactionsStatusOff)
+		if enabled {
+			text = i18n.T(lang, i18n.ReactionsStatusOn)
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	case "on", "off":
+		enabled := arg == "on"
+		if err := c.dbManager.SetReactionAck(ctx, chatID, enabled); err != nil {
+			log.Printf("Error setting reaction acknowledgements for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		text := i18n.T(lang, i18n.ReactionsOff)
+		if enabled {
+			text = i18n.T(lang, i18n.ReactionsOn)
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	default:
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ReactionsUsage))
+		return &msg
+	}
+}
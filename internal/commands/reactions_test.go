@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReactionsCommand_Execute(t *testing.T) {
+	chatID := int64(123456789)
+
+	t.Run("shows current setting", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("ReactionAckEnabled", mock.Anything, chatID).Return(true, nil)
+
+		response := NewReactionsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/reactions"))
+
+		assert.Contains(t, response.Text, "включены")
+	})
+
+	t.Run("turns acknowledgements off", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetReactionAck", mock.Anything, chatID, false).Return(nil)
+
+		response := NewReactionsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/reactions", "off"))
+
+		assert.Contains(t, response.Text, "Больше не отмечаю")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown argument", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewReactionsCommand(mockDB).Execute(CreateCommandMessage(chatID, "/reactions", "maybe"))
+
+		assert.Contains(t, response.Text, "Использование")
+		mockDB.AssertNotCalled(t, "SetReactionAck", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/msgsplit"
+)
+
+// Response is everything a command sends back, in order: text messages,
+// photos, edits of earlier messages or chat actions
+type Response struct {
+	Items []tgbotapi.Chattable
+}
+
+// NewResponse creates a response of the given items
+func NewResponse(items ...tgbotapi.Chattable) *Response {
+	return &Response{Items: items}
+}
+
+// MessageResponse wraps the single reply of Execute; a nil reply sends nothing
+func MessageResponse(msg *tgbotapi.MessageConfig) *Response {
+	if msg == nil {
+		return &Response{}
+	}
+	return NewResponse(*msg)
+}
+
+// SplitResponse sends a long reply as several messages that each fit
+// Telegram's limit. The keyboard goes on the last one, where the reader ends up.
+func SplitResponse(msg *tgbotapi.MessageConfig) *Response {
+	if msg == nil {
+		return &Response{}
+	}
+	parts := msgsplit.Split(msg.Text, msgsplit.MaxLength, msg.ParseMode == tgbotapi.ModeMarkdown)
+	resp := &Response{}
+	for i, text := range parts {
+		part := *msg
+		part.Text = text
+		if i > 0 {
+			part.ReplyToMessageID = 0
+		}
+		if i < len(parts)-1 {
+			part.ReplyMarkup = nil
+		}
+		resp.Add(part)
+	}
+	return resp
+}
+
+// Add appends items to the response
+func (r *Response) Add(items ...tgbotapi.Chattable) *Response {
+	r.Items = append(r.Items, items...)
+	return r
+}
+
+// Message returns the last text message of the response, the one users reply
+// to and press buttons on; nil if there is none
+func (r *Response) Message() *tgbotapi.MessageConfig {
+	if r == nil {
+		return nil
+	}
+	for i := len(r.Items) - 1; i >= 0; i-- {
+		if msg, ok := r.Items[i].(tgbotapi.MessageConfig); ok {
+			return &msg
+		}
+	}
+	return nil
+}
+
+// ResponseCommand is implemented by commands that answer with more than one
+// message or with other requests. The bot calls Respond instead of Execute.
+type ResponseCommand interface {
+	Respond(ctx context.Context, message *tgbotapi.Message) *Response
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/msgsplit"
+)
+
+func TestSplitResponse_KeepsKeyboardOnLastPart(t *testing.T) {
+	msg := tgbotapi.NewMessage(1, strings.Repeat("• задача\n", 1000))
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyToMessageID = 5
+	msg.ReplyMarkup = CreateInlineKeyboard(42, i18n.Russian)
+
+	resp := SplitResponse(&msg)
+
+	require.Greater(t, len(resp.Items), 1)
+	for i, item := range resp.Items {
+		part, ok := item.(tgbotapi.MessageConfig)
+		require.True(t, ok)
+		assert.LessOrEqual(t, msgsplit.Length(part.Text), msgsplit.MaxLength)
+		assert.Equal(t, tgbotapi.ModeMarkdown, part.ParseMode)
+		if i == 0 {
+			assert.Equal(t, 5, part.ReplyToMessageID)
+		} else {
+			assert.Zero(t, part.ReplyToMessageID)
+		}
+		if i == len(resp.Items)-1 {
+			assert.NotNil(t, part.ReplyMarkup)
+		} else {
+			assert.Nil(t, part.ReplyMarkup)
+		}
+	}
+}
+
+func TestResponse_MessageIsLastTextMessage(t *testing.T) {
+	resp := NewResponse(
+		tgbotapi.NewChatAction(1, tgbotapi.ChatTyping),
+		tgbotapi.NewMessage(1, "first"),
+		tgbotapi.NewMessage(1, "second"),
+		tgbotapi.NewEditMessageText(1, 3, "edited"),
+	)
+
+	require.NotNil(t, resp.Message())
+	assert.Equal(t, "second", resp.Message().Text)
+	assert.Nil(t, NewResponse(tgbotapi.NewChatAction(1, tgbotapi.ChatTyping)).Message())
+	assert.Empty(t, MessageResponse(nil).Items)
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// maxSectionButtons keeps the section picker readable; boards rarely have more columns
+const maxSectionButtons = 20
+
+// pickSection returns the section a confirmed draft goes to: the chat default
+// if the project still has it, no section if the project has none. Otherwise
+// it returns the response that turns the preview buttons into the section picker.
+func (h *CallbackHandler) pickSection(ctx context.Context, callback *tgbotapi.CallbackQuery, client tracker.Client, projectID string, sessionID int) (string, *CallbackResponse) {
+	sections := listSections(ctx, client, projectID)
+	if len(sections) == 0 {
+		return "", nil
+	}
+
+	chatID := callback.Message.Chat.ID
+	defaultID, err := h.dbManager.GetDefaultSection(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting default section for chat %d: %v", chatID, err)
+	}
+	for _, section := range sections {
+		if section.ID == defaultID {
+			return section.ID, nil
+		}
+	}
+
+	lang := ChatLanguage(ctx, h.dbManager, chatID)
+	callbackCfg := tgbotapi.NewCallback(callback.ID, i18n.T(lang, i18n.SectionChoose))
+	return "", &CallbackResponse{
+		CallbackConfig: &callbackCfg,
+		IsOwner:        true,
+		Edit:           tgbotapi.NewEditMessageReplyMarkup(chatID, callback.Message.MessageID, buildSectionKeyboard(sessionID, sections, lang)),
+	}
+}
+
+// listSections returns the sections of a project, none when the tracker has no
+// sections or they cannot be loaded: the task is then created outside sections
+func listSections(ctx context.Context, client tracker.Client, projectID string) []tracker.Section {
+	lister, ok := client.(tracker.SectionLister)
+	if !ok {
+		return nil
+	}
+	sections, err := lister.ListSections(ctx, projectID)
+	if err != nil {
+		log.Printf("Error listing sections of project %s: %v", projectID, err)
+		return nil
+	}
+	return sections
+}
+
+// buildSectionKeyboard offers the sections of the project for a confirmed draft
+func buildSectionKeyboard(sessionID int, sections []tracker.Section, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	if len(sections) > maxSectionButtons {
+		sections = sections[:maxSectionButtons]
+	}
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(sections)+1)
+	for _, section := range sections {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(section.Name, sectionData(sessionID, section.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonNoSection), sectionData(sessionID, "")),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancel), fmt.Sprintf("%s%s%d", CallbackCancel, CallbackDataSeparator, sessionID)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func sectionData(sessionID int, sectionID string) string {
+	return fmt.Sprintf("%s%s%d%s%s", CallbackSelectSection, CallbackDataSeparator, sessionID, CallbackDataSeparator, sectionID)
+}
+
+// SectionCommand sets the section of the chat project new tasks go to
+type SectionCommand struct {
+	dbManager DBManager
+	trackers  *tracker.Selector
+}
+
+func NewSectionCommand(dbManager DBManager, todoistClient todoist.Client) *SectionCommand {
+	return &SectionCommand{
+		dbManager: dbManager,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
+	}
+}
+
+// SetTrackers sets the trackers sections are listed from
+func (c *SectionCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *SectionCommand) Name() string {
+	return "section"
+}
+
+func (c *SectionCommand) Description() string {
+	return "Раздел проекта для новых задач: /section <название> или /section off"
+}
+
+func (c *SectionCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil {
+		if !errors.Is(err, db.ErrProjectIDNotSet) {
+			log.Printf("Error getting project of chat %d: %v", chatID, err)
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SectionProjectFirst))
+		return &msg
+	}
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if strings.EqualFold(arg, "off") {
+		return c.setDefault(ctx, chatID, lang, "", i18n.T(lang, i18n.SectionAskEachTime))
+	}
+
+	client, err := c.trackers.ForChat(chatID)
+	if err != nil {
+		log.Printf("Error getting task tracker for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.TrackerUnavailable))
+		return &msg
+	}
+	sections := listSections(ctx, client, projectID)
+	if len(sections) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SectionNone))
+		return &msg
+	}
+
+	if arg == "" {
+		return c.describe(ctx, chatID, lang, sections)
+	}
+	for _, section := range sections {
+		if strings.EqualFold(section.Name, arg) {
+			return c.setDefault(ctx, chatID, lang, section.ID, fmt.Sprintf(i18n.T(lang, i18n.SectionSet), section.Name))
+		}
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.SectionNotFound), arg, sectionNames(sections)))
+	return &msg
+}
+
+func (c *SectionCommand) describe(ctx context.Context, chatID int64, lang i18n.Lang, sections []tracker.Section) *tgbotapi.MessageConfig {
+	defaultID, err := c.dbManager.GetDefaultSection(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting default section for chat %d: %v", chatID, err)
+	}
+
+	current := i18n.T(lang, i18n.SectionUnset)
+	for _, section := range sections {
+		if section.ID == defaultID {
+			current = "«" + section.Name + "»"
+		}
+	}
+	msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.SectionStatus),
+		current, sectionNames(sections)))
+	return &msg
+}
+
+func (c *SectionCommand) setDefault(ctx context.Context, chatID int64, lang i18n.Lang, sectionID, text string) *tgbotapi.MessageConfig {
+	if err := c.dbManager.SetDefaultSection(ctx, chatID, sectionID); err != nil {
+		log.Printf("Error setting default section for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	return &msg
+}
+
+func sectionNames(sections []tracker.Section) string {
+	names := make([]string, 0, len(sections))
+	for _, section := range sections {
+		names = append(names, section.Name)
+	}
+	return strings.Join(names, ", ")
+}
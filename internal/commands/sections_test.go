@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// sectionsTodoistClient is a Todoist mock whose projects have sections
+type sectionsTodoistClient struct {
+	*MockTodoistClient
+	sections []todoist.Section
+}
+
+func (c *sectionsTodoistClient) GetSections(ctx context.Context, projectID string) ([]todoist.Section, error) {
+	return c.sections, nil
+}
+
+var boardSections = []todoist.Section{{ID: "s1", Name: "Backlog"}, {ID: "s2", Name: "In progress"}}
+
+func confirmDraftMocks(sessionID int, chatID, userID int64) *MockDBManager {
+	mockDB := new(MockDBManager)
+	mockDB.On("IsSessionOwner", mock.Anything, sessionID, userID).Return(true, nil)
+	mockDB.On("GetDraftTask", mock.Anything, sessionID).Return(db.DraftTask{
+		SessionID: sessionID,
+		Title:     sql.NullString{String: "Test Task", Valid: true},
+	}, nil)
+	mockDB.On("GetCreatedTask", mock.Anything, sessionID).Return(nil, nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	return mockDB
+}
+
+func sectionCallback(chatID, userID int64, data string) *tgbotapi.CallbackQuery {
+	return &tgbotapi.CallbackQuery{
+		ID:      "test_callback_id",
+		From:    &tgbotapi.User{ID: userID},
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}, MessageID: 101},
+		Data:    data,
+	}
+}
+
+// Tests that confirming a draft of a project with sections and no default section shows the section picker
+func TestCallbackHandler_ConfirmAsksForSection(t *testing.T) {
+	chatID, userID := int64(789), int64(456)
+	mockDB := confirmDraftMocks(123, chatID, userID)
+	mockDB.On("GetDefaultSection", mock.Anything, chatID).Return("", nil)
+	mockTodoist := &sectionsTodoistClient{MockTodoistClient: new(MockTodoistClient), sections: boardSections}
+
+	response := NewCallbackHandler(mockTodoist, mockDB).HandleCallback(sectionCallback(chatID, userID, "confirm_task:123"))
+
+	assert.True(t, response.IsOwner)
+	assert.Nil(t, response.CreatedTask)
+	edit, ok := response.Edit.(tgbotapi.EditMessageReplyMarkupConfig)
+	assert.True(t, ok)
+	keyboard := edit.ReplyMarkup.InlineKeyboard
+	assert.Len(t, keyboard, 3)
+	assert.Equal(t, "select_section:123:s1", *keyboard[0][0].CallbackData)
+	assert.Equal(t, "select_section:123:", *keyboard[2][0].CallbackData)
+	mockTodoist.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything)
+}
+
+// Tests that the default section of the chat skips the picker
+func TestCallbackHandler_ConfirmUsesDefaultSection(t *testing.T) {
+	chatID, userID := int64(789), int64(456)
+	mockDB := confirmDraftMocks(123, chatID, userID)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetDefaultSection", mock.Anything, chatID).Return("s2", nil)
+	mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptOff, nil)
+	mockDB.On("SaveCreatedTask", mock.Anything, mock.Anything, "t1", mock.Anything).Return(db.CreatedTask{TodoistTaskID: "t1"}, true, nil)
+	mockDB.On("CloseSession", mock.Anything, 123).Return(nil)
+	mockTodoist := &sectionsTodoistClient{MockTodoistClient: new(MockTodoistClient), sections: boardSections}
+	mockTodoist.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *todoist.TaskRequest) bool {
+		return task.SectionID == "s2"
+	})).Return(&todoist.TaskResponse{ID: "t1"}, nil)
+
+	response := NewCallbackHandler(mockTodoist, mockDB).HandleCallback(sectionCallback(chatID, userID, "confirm_task:123"))
+
+	assert.NotNil(t, response.CreatedTask)
+	mockTodoist.AssertExpectations(t)
+	mockDB.AssertExpectations(t)
+}
+
+// Tests that a section chosen in the picker, or none, is used for the task
+func TestCallbackHandler_SelectSection(t *testing.T) {
+	for _, sectionID := range []string{"s1", ""} {
+		chatID, userID := int64(789), int64(456)
+		mockDB := confirmDraftMocks(123, chatID, userID)
+		mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+		mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptOff, nil)
+		mockDB.On("SaveCreatedTask", mock.Anything, mock.Anything, "t1", mock.Anything).Return(db.CreatedTask{TodoistTaskID: "t1"}, true, nil)
+		mockDB.On("CloseSession", mock.Anything, 123).Return(nil)
+		mockTodoist := &sectionsTodoistClient{MockTodoistClient: new(MockTodoistClient), sections: boardSections}
+		mockTodoist.On("CreateTask", mock.Anything, mock.MatchedBy(func(task *todoist.TaskRequest) bool {
+			return task.SectionID == sectionID
+		})).Return(&todoist.TaskResponse{ID: "t1"}, nil)
+
+		response := NewCallbackHandler(mockTodoist, mockDB).HandleCallback(sectionCallback(chatID, userID, "select_section:123:"+sectionID))
+
+		assert.NotNil(t, response.CreatedTask)
+		mockTodoist.AssertExpectations(t)
+		mockDB.AssertNotCalled(t, "GetDefaultSection", mock.Anything, mock.Anything)
+	}
+}
+
+func TestSectionCommand(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("project123", nil)
+	mockDB.On("SetDefaultSection", mock.Anything, chatID, "s2").Return(nil)
+	mockDB.On("SetDefaultSection", mock.Anything, chatID, "").Return(nil)
+	mockDB.On("GetDefaultSection", mock.Anything, chatID).Return("s2", nil)
+	cmd := NewSectionCommand(mockDB, &sectionsTodoistClient{MockTodoistClient: new(MockTodoistClient), sections: boardSections})
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/section", "in Progress"))
+	assert.Contains(t, response.Text, "«In progress»")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/section"))
+	assert.Contains(t, response.Text, "Раздел для новых задач: «In progress»")
+	assert.Contains(t, response.Text, "Backlog, In progress")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/section", "Done"))
+	assert.Contains(t, response.Text, "не найден")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/section", "off"))
+	assert.Contains(t, response.Text, "спрашиваться")
+
+	mockDB.AssertExpectations(t)
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// maxSessionNameLength limits discussion names, which people type as #tags
+const maxSessionNameLength = 32
+
+// ParseSessionName validates the name of a parallel discussion, e.g.
+// "billing-bug" or "#billing-bug": letters, digits, "-" and "_". Names are
+// compared lowercased.
+func ParseSessionName(arg string) (string, bool) {
+	name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(arg), "#"))
+	if name == "" || utf8.RuneCountInString(name) > maxSessionNameLength {
+		return "", false
+	}
+	for _, r := range name {
+		if !isSessionNameRune(r) {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+func isSessionNameRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+// SessionName returns the discussion named by the command argument, "" when
+// the command addresses the default discussion or the name is invalid
+func SessionName(message *tgbotapi.Message) string {
+	if message == nil || !message.IsCommand() {
+		return ""
+	}
+	fields := strings.Fields(message.CommandArguments())
+	if len(fields) == 0 {
+		return ""
+	}
+	name, _ := ParseSessionName(fields[0])
+	return name
+}
+
+// SessionTags returns the #tags of text that may name discussions, lowercased,
+// in the order they appear
+func SessionTags(text string) []string {
+	var tags []string
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return r != '#' && !isSessionNameRune(r)
+	}) {
+		if !strings.HasPrefix(field, "#") {
+			continue
+		}
+		if name, ok := ParseSessionName(strings.TrimLeft(field, "#")); ok {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
+
+// FindSession returns the discussion a command addresses: the one it names,
+// or the active discussion of the topic
+func FindSession(ctx context.Context, dbManager DBManager, message *tgbotapi.Message) (*db.Session, error) {
+	if name := SessionName(message); name != "" {
+		return dbManager.GetNamedSession(ctx, message.Chat.ID, topics.ThreadID(message), name)
+	}
+	return dbManager.GetActiveSession(ctx, message.Chat.ID, topics.ThreadID(message))
+}
+
+// sessionTitle names a discussion in replies: «billing-bug» or empty for the default one
+func sessionTitle(name string) string {
+	if name == "" {
+		return ""
+	}
+	return " «" + name + "»"
+}
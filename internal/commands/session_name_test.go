@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func TestParseSessionName(t *testing.T) {
+	for arg, want := range map[string]string{
+		"billing-bug":                       "billing-bug",
+		"#Release_2":                        "release_2",
+		" оплата ":                          "оплата",
+		"":                                  "",
+		"#":                                 "",
+		"billing bug":                       "",
+		"bug!":                              "",
+		"a23456789012345678901234567890123": "",
+	} {
+		got, ok := ParseSessionName(arg)
+		assert.Equal(t, want, got, arg)
+		assert.Equal(t, want != "", ok, arg)
+	}
+}
+
+func TestSessionTags(t *testing.T) {
+	assert.Equal(t, []string{"billing-bug", "оплата"}, SessionTags("про #Billing-Bug, и ещё #оплата. foo#bar # #!"))
+	assert.Empty(t, SessionTags("без тегов"))
+}
+
+func TestFindSession_ByName(t *testing.T) {
+	chatID := int64(42)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetNamedSession", mock.Anything, chatID, 0, "release").Return(&db.Session{ID: 3, Name: "release"}, nil)
+
+	session, err := FindSession(context.Background(), mockDB, CreateCommandMessage(chatID, "/create_task", "release"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, session.ID)
+	mockDB.AssertNotCalled(t, "GetActiveSession", mock.Anything, mock.Anything, mock.Anything)
+}
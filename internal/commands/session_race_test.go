@@ -0,0 +1,253 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/tasklinks"
+)
+
+// sessionStore is an in-memory DBManager for the session lifecycle with the
+// guarantees of the database: one open session per chat, atomic start and close.
+// Methods it does not implement fall through to the embedded mock.
+type sessionStore struct {
+	*MockDBManager
+	t *testing.T
+
+	mu       sync.Mutex
+	nextID   int
+	sessions map[int]*db.Session
+	messages map[int]int // messages saved per session
+}
+
+func newSessionStore(t *testing.T) *sessionStore {
+	return &sessionStore{
+		MockDBManager: new(MockDBManager),
+		t:             t,
+		sessions:      make(map[int]*db.Session),
+		messages:      make(map[int]int),
+	}
+}
+
+func (s *sessionStore) GetTodoistProjectID(ctx context.Context, chatID int64) (string, error) {
+	return "project", nil
+}
+
+func (s *sessionStore) GetChatLanguage(ctx context.Context, chatID int64) (string, error) {
+	return "", nil
+}
+
+func (s *sessionStore) openSession(chatID int64) *db.Session {
+	var open *db.Session
+	for _, session := range s.sessions {
+		if session.ChatID == chatID && session.Status == "open" {
+			if open != nil {
+				s.t.Errorf("chat %d has two open sessions: %d and %d", chatID, open.ID, session.ID)
+			}
+			open = session
+		}
+	}
+	return open
+}
+
+func (s *sessionStore) StartSession(ctx context.Context, chatID int64, threadID int, name string, ownerID int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.openSession(chatID) != nil {
+		return 0, db.ErrSessionAlreadyExists
+	}
+	s.nextID++
+	s.sessions[s.nextID] = &db.Session{ID: s.nextID, ChatID: chatID, OwnerID: ownerID, Status: "open"}
+	return s.nextID, nil
+}
+
+func (s *sessionStore) GetActiveSession(ctx context.Context, chatID int64, threadID int) (*db.Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session := s.openSession(chatID)
+	if session == nil {
+		return nil, db.ErrNoActiveSession
+	}
+	copied := *session
+	return &copied, nil
+}
+
+func (s *sessionStore) HasActiveSession(ctx context.Context, chatID int64, threadID int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openSession(chatID) != nil, nil
+}
+
+func (s *sessionStore) CloseSession(ctx context.Context, sessionID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok || session.Status != "open" {
+		return db.ErrNoActiveSession
+	}
+	session.Status = "closed"
+	return nil
+}
+
+func (s *sessionStore) IsSessionOwner(ctx context.Context, sessionID int, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[sessionID]
+	if !ok {
+		return false, fmt.Errorf("session not found")
+	}
+	return session.OwnerID == userID, nil
+}
+
+func (s *sessionStore) SaveMessage(ctx context.Context, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session := s.openSession(chatID); session != nil {
+		s.messages[session.ID]++
+	}
+	return nil
+}
+
+func (s *sessionStore) openSessions(chatID int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	count := 0
+	for _, session := range s.sessions {
+		if session.ChatID == chatID && session.Status == "open" {
+			count++
+		}
+	}
+	return count
+}
+
+// Run with: go test -race ./internal/commands -run Concurrent
+func TestStartDiscussion_ConcurrentStartsOpenOneSession(t *testing.T) {
+	store := newSessionStore(t)
+	cmd := NewStartDiscussionCommand(store, new(MockTodoistClient))
+
+	const chatID = int64(100)
+	const starters = 20
+	var wg sync.WaitGroup
+	replies := make(chan string, starters)
+	for i := 0; i < starters; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			message := CreateCommandMessage(chatID, "/start_discussion")
+			message.From.ID = userID
+			replies <- cmd.Execute(message).Text
+		}(int64(i + 1))
+	}
+	wg.Wait()
+	close(replies)
+
+	started := 0
+	for reply := range replies {
+		if strings.HasPrefix(reply, "Обсуждение началось") {
+			started++
+		} else if !strings.Contains(reply, "Обсуждение уже идёт") {
+			t.Fatalf("unexpected reply: %q", reply)
+		}
+	}
+	if started != 1 || store.openSessions(chatID) != 1 {
+		t.Fatalf("expected one started and open session, got %d started, %d open", started, store.openSessions(chatID))
+	}
+}
+
+func TestSessionLifecycle_ConcurrentCommandsAndCallbacks(t *testing.T) {
+	store := newSessionStore(t)
+	start := NewStartDiscussionCommand(store, new(MockTodoistClient))
+	cancel := NewCancelCommand(store)
+	callbacks := NewCallbackHandler(new(MockTodoistClient), store)
+
+	const chatID = int64(200)
+	const ownerID = int64(1)
+	const rounds = 50
+
+	var wg sync.WaitGroup
+	for round := 0; round < rounds; round++ {
+		wg.Add(4)
+		go func() {
+			defer wg.Done()
+			message := CreateCommandMessage(chatID, "/start_discussion")
+			message.From.ID = ownerID
+			start.Execute(message)
+		}()
+		go func(round int) {
+			defer wg.Done()
+			store.SaveMessage(context.Background(), chatID, 0, round, ownerID, "owner", "сообщение", nil)
+		}(round)
+		go func() {
+			defer wg.Done()
+			message := CreateCommandMessage(chatID, "/cancel")
+			message.From.ID = ownerID
+			cancel.Execute(message)
+		}()
+		go func() {
+			defer wg.Done()
+			session, err := store.GetActiveSession(context.Background(), chatID, 0)
+			if err != nil {
+				return
+			}
+			resp := callbacks.HandleCallback(&tgbotapi.CallbackQuery{
+				ID:      "cb",
+				From:    &tgbotapi.User{ID: ownerID},
+				Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: chatID}},
+				Data:    fmt.Sprintf("%s%s%d", CallbackFinishDiscussion, CallbackDataSeparator, session.ID),
+			})
+			if resp == nil || resp.CallbackConfig == nil {
+				t.Errorf("no callback answer for session %d", session.ID)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if open := store.openSessions(chatID); open > 1 {
+		t.Fatalf("expected at most one open session, got %d", open)
+	}
+}
+
+func TestFinishDiscussion_ConcurrentFinishesCloseOnce(t *testing.T) {
+	store := newSessionStore(t)
+	callbacks := NewCallbackHandler(new(MockTodoistClient), store)
+
+	const chatID = int64(300)
+	sessionID, err := store.StartSession(context.Background(), chatID, 0, "", 1)
+	if err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+
+	const presses = 10
+	var wg sync.WaitGroup
+	finished := make(chan bool, presses)
+	for i := 0; i < presses; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := callbacks.HandleCallback(&tgbotapi.CallbackQuery{
+				ID:      "cb",
+				From:    &tgbotapi.User{ID: 1},
+				Message: &tgbotapi.Message{MessageID: 1, Chat: &tgbotapi.Chat{ID: chatID}},
+				Data:    fmt.Sprintf("%s%s%d", CallbackFinishDiscussion, CallbackDataSeparator, sessionID),
+			})
+			finished <- resp.ResponseMessage != nil
+		}()
+	}
+	wg.Wait()
+	close(finished)
+
+	closed := 0
+	for ok := range finished {
+		if ok {
+			closed++
+		}
+	}
+	if closed != 1 {
+		t.Fatalf("expected the discussion to be reported finished once, got %d", closed)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
 )
 
 const ReplyKindAssigneeMapUpload = "assignee_map_upload"
@@ -26,14 +27,15 @@ func (c *SetAssigneeMapCommand) Description() string {
 }
 
 func (c *SetAssigneeMapCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	projectID, err := c.dbManager.GetTodoistProjectID(context.Background(), message.Chat.ID)
+	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
 	if err != nil || projectID == "" {
-		msg := tgbotapi.NewMessage(message.Chat.ID, "Сначала выберите проект Todoist через /set_project, затем загрузите YAML-маппинг исполнителей.")
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AssigneeMapProjectFirst))
 		return &msg
 	}
 
-	text := "Отправьте YAML-файл маппинга в ответ на это сообщение.\n\nПример:\n```yaml\nversion: 1\nassignees:\n  - todoist_email: \"alice@example.com\"\n    telegram_aliases: [\"@alice\", \"alice\", \"Алиса\"]\n```\n\nФайл заменит текущий маппинг для выбранного проекта."
-	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AssigneeMapPrompt))
 	msg.ParseMode = "Markdown"
 	msg.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: true}
 	return &msg
@@ -3,65 +3,154 @@ package commands
 import (
 	"context"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
 const defaultTimeout = 10 * time.Second
 
 type SetProjectCommand struct {
 	todoistClient todoist.Client
+	trackers      *tracker.Selector
 	dbManager     DBManager
 }
 
 func NewSetProjectCommand(todoistClient todoist.Client, dbManager DBManager) *SetProjectCommand {
 	return &SetProjectCommand{
 		todoistClient: todoistClient,
+		trackers:      tracker.Single(tracker.NewTodoist(todoistClient)),
 		dbManager:     dbManager,
 	}
 }
 
+// SetTrackers sets the trackers projects are offered from
+func (c *SetProjectCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
 func (c *SetProjectCommand) Name() string {
 	return "set_project"
 }
 
 func (c *SetProjectCommand) Description() string {
-	return "Выбрать или сменить проект Todoist"
+	return "Выбрать или сменить проект Todoist, можно сразу ссылкой: /set_project <ссылка>"
 }
 
 func (c *SetProjectCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
-	return buildProjectSelectionMessage(context.Background(), c.todoistClient, message.Chat.ID, "Выберите проект Todoist:")
+	lang := ReplyLanguage(context.Background(), c.dbManager, message)
+	if link := strings.TrimSpace(message.CommandArguments()); link != "" {
+		return c.setProjectFromURL(message.Chat.ID, link, lang)
+	}
+	return buildProjectSelectionMessage(context.Background(), c.trackers, message.Chat.ID, lang, i18n.T(lang, i18n.ProjectChoose))
 }
 
-func buildProjectSelectionMessage(ctx context.Context, todoistClient todoist.Client, chatID int64, intro string) *tgbotapi.MessageConfig {
+// setProjectFromURL selects the project of a pasted Todoist link
+func (c *SetProjectCommand) setProjectFromURL(chatID int64, link string, lang i18n.Lang) *tgbotapi.MessageConfig {
+	projectID, err := todoist.ParseProjectURL(link)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectBadLink))
+		return &msg
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+
+	projects, err := c.todoistClient.GetProjects(ctx)
+	if err != nil {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.ProjectsLoadFailed), err))
+		return &msg
+	}
+
+	for _, project := range projects {
+		if project.ID != projectID {
+			continue
+		}
+		if err := c.dbManager.SetTodoistProjectID(ctx, chatID, project.ID); err != nil {
+			log.Printf("Error saving Todoist project ID: %v", err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectSaveFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.ProjectSelected), project.Name))
+		return &msg
+	}
+
+	// Old numeric links do not match the current IDs, the keyboard always works
+	return buildProjectSelectionMessage(ctx, c.trackers, chatID, lang, i18n.T(lang, i18n.ProjectLinkNotFound))
+}
+
+func buildProjectSelectionMessage(ctx context.Context, trackers *tracker.Selector, chatID int64, lang i18n.Lang, intro string) *tgbotapi.MessageConfig {
 	ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
 	defer cancel()
 
-	projects, err := todoistClient.GetProjects(ctx)
+	projects, err := listChatProjects(ctx, trackers, chatID)
 	if err != nil {
-		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf("Не удалось загрузить проекты Todoist: %v", err))
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.ProjectsLoadFailed), err))
 		return &msg
 	}
 
 	if len(projects) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "В Todoist не найдено ни одного проекта.")
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectsNone))
 		return &msg
 	}
 
 	msg := tgbotapi.NewMessage(chatID, intro)
-	msg.ReplyMarkup = buildProjectSelectionKeyboard(projects)
+	msg.ReplyMarkup = buildProjectSelectionKeyboard(projects, 0)
 	return &msg
 }
 
-func buildProjectSelectionKeyboard(projects []todoist.Project) tgbotapi.InlineKeyboardMarkup {
-	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(projects))
+// listChatProjects loads the projects of the chat's tracker
+func listChatProjects(ctx context.Context, trackers *tracker.Selector, chatID int64) ([]tracker.Project, error) {
+	client, err := trackers.ForChat(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("chat task tracker unavailable: %w", err)
+	}
+	return client.ListProjects(ctx)
+}
+
+// projectsPerPage keeps the selection keyboard short enough to see the message above it
+const projectsPerPage = 8
 
-	for _, project := range projects {
+// buildProjectSelectionKeyboard shows one page of projects, one per row, and
+// buttons to the neighbouring pages. Pages past the end show the last one.
+func buildProjectSelectionKeyboard(projects []tracker.Project, page int) tgbotapi.InlineKeyboardMarkup {
+	pages := (len(projects) + projectsPerPage - 1) / projectsPerPage
+	if page >= pages {
+		page = pages - 1
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	start := page * projectsPerPage
+	end := min(start+projectsPerPage, len(projects))
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, end-start+1)
+	for _, project := range projects[start:end] {
 		button := tgbotapi.NewInlineKeyboardButtonData(project.Name, CallbackSelectProject+CallbackDataSeparator+project.ID)
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(button))
 	}
 
+	if pages > 1 {
+		var nav []tgbotapi.InlineKeyboardButton
+		if page > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️", projectPageData(page-1)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", page+1, pages), projectPageData(page)))
+		if page < pages-1 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("▶️", projectPageData(page+1)))
+		}
+		rows = append(rows, nav)
+	}
+
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
+
+func projectPageData(page int) string {
+	return CallbackProjectPage + CallbackDataSeparator + strconv.Itoa(page)
+}
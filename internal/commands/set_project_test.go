@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"strconv"
 	"testing"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
@@ -42,3 +43,63 @@ func TestSetProjectCommand_Execute_ShowsProjects(t *testing.T) {
 	mockTodoistClient.AssertExpectations(t)
 	mockDBManager.AssertNotCalled(t, "SetTodoistProjectID", mock.Anything, mock.Anything, mock.Anything)
 }
+
+func TestSetProjectCommand_Execute_SelectsProjectFromURL(t *testing.T) {
+	mockTodoistClient := new(MockTodoistClient)
+	mockDBManager := new(MockDBManager)
+
+	cmd := NewSetProjectCommand(mockTodoistClient, mockDBManager)
+
+	chatID := int64(123456789)
+	projects := []todoist.Project{
+		{ID: "6Jf8VQXxpwv56VQ7", Name: "Backend"},
+		{ID: "6Jf8VQXxpwv56VQ8", Name: "Frontend"},
+	}
+
+	mockTodoistClient.On("GetProjects", mock.Anything).Return(projects, nil)
+	mockDBManager.On("SetTodoistProjectID", mock.Anything, chatID, "6Jf8VQXxpwv56VQ8").Return(nil)
+
+	message := CreateCommandMessage(chatID, "/set_project", "https://app.todoist.com/app/project/frontend-6Jf8VQXxpwv56VQ8")
+	response := cmd.Execute(message)
+
+	assert.Equal(t, "✅ Проект выбран: Frontend", response.Text)
+	mockTodoistClient.AssertExpectations(t)
+	mockDBManager.AssertExpectations(t)
+}
+
+func TestSetProjectCommand_Execute_RejectsForeignURL(t *testing.T) {
+	mockTodoistClient := new(MockTodoistClient)
+	mockDBManager := new(MockDBManager)
+
+	cmd := NewSetProjectCommand(mockTodoistClient, mockDBManager)
+
+	message := CreateCommandMessage(123456789, "/set_project", "https://example.com/project/1")
+	response := cmd.Execute(message)
+
+	assert.Contains(t, response.Text, "Не понял ссылку")
+	mockTodoistClient.AssertNotCalled(t, "GetProjects", mock.Anything)
+}
+
+func TestSetProjectCommand_Execute_PaginatesProjects(t *testing.T) {
+	mockTodoistClient := new(MockTodoistClient)
+	cmd := NewSetProjectCommand(mockTodoistClient, new(MockDBManager))
+
+	var projects []todoist.Project
+	for i := 0; i < projectsPerPage+3; i++ {
+		projects = append(projects, todoist.Project{ID: strconv.Itoa(i), Name: "Project " + strconv.Itoa(i)})
+	}
+	mockTodoistClient.On("GetProjects", mock.Anything).Return(projects, nil)
+
+	response := cmd.Execute(CreateCommandMessage(123456789, "/set_project"))
+
+	markup, ok := response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Len(t, markup.InlineKeyboard, projectsPerPage+1)
+	nav := markup.InlineKeyboard[projectsPerPage]
+	assert.Equal(t, "1/2", nav[0].Text)
+	if assert.Len(t, nav, 2) && assert.NotNil(t, nav[1].CallbackData) {
+		assert.Equal(t, "project_page:1", *nav[1].CallbackData)
+	}
+}
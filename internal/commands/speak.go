@@ -0,0 +1,110 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tasklinks"
+	"github.com/user/telegram-bot/internal/topics"
+)
+
+// SpeakCommand voices the current task draft and toggles voice previews for the chat
+type SpeakCommand struct {
+	dbManager DBManager
+}
+
+func NewSpeakCommand(dbManager DBManager) *SpeakCommand {
+	return &SpeakCommand{dbManager: dbManager}
+}
+
+func (c *SpeakCommand) Name() string {
+	return "speak"
+}
+
+func (c *SpeakCommand) Description() string {
+	return "Озвучить черновик задачи; /speak on|off — озвучивать каждый черновик"
+}
+
+func (c *SpeakCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	switch arg := strings.TrimSpace(message.CommandArguments()); arg {
+	case "":
+		if _, ok := c.currentDraft(ctx, chatID, topics.ThreadID(message)); ok {
+			// The draft is sent as a voice note by the bot
+			return nil
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SpeakNoDraft))
+		return &msg
+	case "on", "off":
+		enabled := arg == "on"
+		if err := c.dbManager.SetVoicePreviews(ctx, chatID, enabled); err != nil {
+			log.Printf("Error setting voice previews for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+			return &msg
+		}
+		text := i18n.T(lang, i18n.SpeakOff)
+		if enabled {
+			text = i18n.T(lang, i18n.SpeakOn)
+		}
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	default:
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SpeakUsage))
+		return &msg
+	}
+}
+
+// VoiceText returns the preview of the current draft for /speak without arguments
+func (c *SpeakCommand) VoiceText(ctx context.Context, message *tgbotapi.Message) string {
+	if strings.TrimSpace(message.CommandArguments()) != "" {
+		return ""
+	}
+	draft, ok := c.currentDraft(ctx, message.Chat.ID, topics.ThreadID(message))
+	if !ok {
+		return ""
+	}
+	task := DraftToAnalyzedTask(draft)
+	ApplyPriorityNames(task, ChatPriorityNames(ctx, c.dbManager, message.Chat.ID))
+	return FormatTaskPreview(task, task.DueDate, task.AssigneeNote, db.AssigneeSnapshot{
+		TodoistID: draft.AssigneeTodoistID.String,
+		Name:      draft.AssigneeName.String,
+		Email:     draft.AssigneeEmail.String,
+	}, "", ChatLanguage(ctx, c.dbManager, message.Chat.ID))
+}
+
+func (c *SpeakCommand) currentDraft(ctx context.Context, chatID int64, threadID int) (db.DraftTask, bool) {
+	session, err := c.dbManager.GetActiveSession(ctx, chatID, threadID)
+	if err != nil {
+		return db.DraftTask{}, false
+	}
+	draft, err := c.dbManager.GetDraftTask(ctx, session.ID)
+	if err != nil {
+		return db.DraftTask{}, false
+	}
+	return draft, true
+}
+
+// DraftToAnalyzedTask converts a stored draft back to the AI task model
+func DraftToAnalyzedTask(draftTask db.DraftTask) *ai.AnalyzedTask {
+	return &ai.AnalyzedTask{
+		Title:          draftTask.Title.String,
+		Description:    draftTask.Description.String,
+		DueDate:        draftTask.DueISO.String,
+		Priority:       int(draftTask.Priority.Int32),
+		PriorityText:   "",
+		AssigneeNote:   draftTask.AssigneeNote.String,
+		Labels:         []string(draftTask.Labels),
+		TaskType:       draftTask.TaskType.String,
+		MissingDetails: []string(draftTask.MissingDetails),
+		SelectedLinks:  []tasklinks.TaskLink(draftTask.SelectedLinks),
+		TaskFields:     draftTask.Fields,
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func TestSpeakCommand_VoicesCurrentDraft(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatLanguage", mock.Anything, chatID).Return("", nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 5, ChatID: chatID}, nil)
+	mockDB.On("GetDraftTask", mock.Anything, 5).Return(db.DraftTask{
+		SessionID: 5,
+		Title:     sql.NullString{String: "Починить логин", Valid: true},
+	}, nil)
+	mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("", nil)
+
+	cmd := NewSpeakCommand(mockDB)
+	message := CreateCommandMessage(chatID, "/speak")
+
+	assert.Nil(t, cmd.Execute(message))
+	assert.Contains(t, cmd.VoiceText(context.Background(), message), "Починить логин")
+}
+
+func TestSpeakCommand_NoDraft(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(nil, db.ErrNoActiveSession)
+
+	cmd := NewSpeakCommand(mockDB)
+	message := CreateCommandMessage(chatID, "/speak")
+
+	response := cmd.Execute(message)
+	assert.Contains(t, response.Text, "Нет черновика")
+	assert.Empty(t, cmd.VoiceText(context.Background(), message))
+}
+
+func TestSpeakCommand_TogglesVoicePreviews(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("SetVoicePreviews", mock.Anything, chatID, true).Return(nil)
+
+	cmd := NewSpeakCommand(mockDB)
+	response := cmd.Execute(CreateCommandMessage(chatID, "/speak", "on"))
+
+	assert.Contains(t, response.Text, "голосовым сообщением")
+	mockDB.AssertExpectations(t)
+}
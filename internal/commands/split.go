@@ -0,0 +1,296 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const (
+	// maxSplitSubtasks keeps the selection keyboard readable
+	maxSplitSubtasks = 10
+	// splitButtonTitleLimit is how many runes of a subtask title fit on its button
+	splitButtonTitleLimit = 40
+)
+
+// SplitProposal is an AI split of a discussion waiting for the owner to pick subtasks
+type SplitProposal struct {
+	SessionID int
+	ProjectID string
+	Plan      *ai.SplitPlan
+	Selected  []bool
+}
+
+// SelectedSubtasks returns the subtasks the owner kept
+func (p *SplitProposal) SelectedSubtasks() []ai.SplitSubtask {
+	var subtasks []ai.SplitSubtask
+	for i, subtask := range p.Plan.Subtasks {
+		if p.Selected[i] {
+			subtasks = append(subtasks, subtask)
+		}
+	}
+	return subtasks
+}
+
+// SplitStore keeps the split proposals of discussions by session ID
+type SplitStore struct {
+	mu        sync.Mutex
+	proposals map[int]*SplitProposal
+}
+
+func NewSplitStore() *SplitStore {
+	return &SplitStore{proposals: make(map[int]*SplitProposal)}
+}
+
+// Put replaces the pending proposal of the session
+func (s *SplitStore) Put(proposal *SplitProposal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.proposals[proposal.SessionID] = proposal
+}
+
+// Toggle flips the selection of the i-th subtask and returns a copy of the
+// updated proposal, false if the session has no proposal or no such subtask
+func (s *SplitStore) Toggle(sessionID, i int) (SplitProposal, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	proposal, ok := s.proposals[sessionID]
+	if !ok || i < 0 || i >= len(proposal.Selected) {
+		return SplitProposal{}, false
+	}
+	proposal.Selected[i] = !proposal.Selected[i]
+
+	snapshot := *proposal
+	snapshot.Selected = append([]bool(nil), proposal.Selected...)
+	return snapshot, true
+}
+
+// Take removes and returns the pending proposal of the session
+func (s *SplitStore) Take(sessionID int) (*SplitProposal, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	proposal, ok := s.proposals[sessionID]
+	delete(s.proposals, sessionID)
+	return proposal, ok
+}
+
+// SplitCommand asks the AI to split the discussion into a parent task with
+// subtasks and lets the owner pick which subtasks to create
+type SplitCommand struct {
+	dbManager DBManager
+	aiClient  ai.Client
+	store     *SplitStore
+	trackers  *tracker.Selector
+}
+
+func NewSplitCommand(dbManager DBManager, aiClient ai.Client, store *SplitStore) *SplitCommand {
+	return &SplitCommand{dbManager: dbManager, aiClient: aiClient, store: store}
+}
+
+// SetTrackers sets the trackers of the chats; subtasks are created in Todoist only
+func (c *SplitCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *SplitCommand) Name() string {
+	return "split"
+}
+
+func (c *SplitCommand) Description() string {
+	return "Разбить обсуждение на задачу с подзадачами"
+}
+
+// JobKind returns the job queue kind for the split analysis
+func (c *SplitCommand) JobKind() string {
+	return "split_discussion"
+}
+
+func (c *SplitCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	return c.ExecuteContext(context.Background(), message)
+}
+
+// ExecuteContext handles the command execution within a job context
+func (c *SplitCommand) ExecuteContext(ctx context.Context, message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	if c.trackers != nil && c.trackers.Kind(chatID) != tracker.KindTodoist {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SplitTodoistOnly))
+		return &msg
+	}
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ProjectChooseFirstCommand))
+		return &msg
+	}
+
+	session, err := FindSession(ctx, c.dbManager, message)
+	if errors.Is(err, db.ErrNoActiveSession) {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SessionNoActive))
+		return &msg
+	}
+	if err != nil {
+		log.Printf("Error getting session for split in chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ErrorSessionLoad))
+		return &msg
+	}
+	if session.OwnerID != message.From.ID {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SplitOwnerOnly))
+		return &msg
+	}
+	ctx = ai.WithUsageScope(ctx, chatID, session.ID)
+
+	messages, err := c.dbManager.GetSessionMessages(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error getting messages of session %d for split: %v", session.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ErrorMessagesLoad))
+		return &msg
+	}
+	texts := discussionTexts(messages)
+	if len(texts) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SplitNoMessages))
+		return &msg
+	}
+
+	answer, err := c.aiClient.RunPrompt(ctx, ai.PromptSplitDiscussion, strings.Join(texts, "\n"))
+	if err != nil {
+		log.Printf("AI split of session %d failed: %v", session.ID, err)
+		if errors.Is(err, ai.ErrUnavailable) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AIUnavailable))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SplitFailed))
+		return &msg
+	}
+	plan, err := ai.ParseSplitPlan(answer)
+	if err != nil {
+		log.Printf("Error parsing AI split of session %d: %v", session.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SplitFailed))
+		return &msg
+	}
+	if len(plan.Subtasks) > maxSplitSubtasks {
+		plan.Subtasks = plan.Subtasks[:maxSplitSubtasks]
+	}
+
+	proposal := &SplitProposal{
+		SessionID: session.ID,
+		ProjectID: projectID,
+		Plan:      plan,
+		Selected:  make([]bool, len(plan.Subtasks)),
+	}
+	for i := range proposal.Selected {
+		proposal.Selected[i] = true
+	}
+	c.store.Put(proposal)
+
+	msg := tgbotapi.NewMessage(chatID, FormatSplitPreview(proposal, lang))
+	msg.ReplyMarkup = SplitKeyboard(proposal, lang)
+	return &msg
+}
+
+// FormatSplitPreview shows the parent task and the proposed subtasks
+func FormatSplitPreview(proposal *SplitProposal, lang i18n.Lang) string {
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.SplitProposal) + "\n\n")
+	fmt.Fprintf(&sb, "📌 %s\n", proposal.Plan.Title)
+	if proposal.Plan.Description != "" {
+		sb.WriteString(proposal.Plan.Description + "\n")
+	}
+	sb.WriteString("\n")
+	for i, subtask := range proposal.Plan.Subtasks {
+		fmt.Fprintf(&sb, "%d. %s", i+1, subtask.Title)
+		if subtask.Description != "" {
+			fmt.Fprintf(&sb, " — %s", subtask.Description)
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n" + i18n.T(lang, i18n.SplitChoose))
+	return sb.String()
+}
+
+// SplitKeyboard has a toggle per subtask and the create and cancel buttons
+func SplitKeyboard(proposal *SplitProposal, lang i18n.Lang) tgbotapi.InlineKeyboardMarkup {
+	session := CallbackDataSeparator + strconv.Itoa(proposal.SessionID)
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	selected := 0
+	for i, subtask := range proposal.Plan.Subtasks {
+		mark := "⬜"
+		if proposal.Selected[i] {
+			mark = "☑️"
+			selected++
+		}
+		title := fmt.Sprintf("%s %d. %s", mark, i+1, truncateRunes(subtask.Title, splitButtonTitleLimit))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(title, CallbackSplitToggle+session+CallbackDataSeparator+strconv.Itoa(i)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(i18n.T(lang, i18n.ButtonSplitCreate), selected), CallbackSplitCreate+session),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(lang, i18n.ButtonCancel), CallbackSplitCancel+session),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// SplitTaskRequests builds the parent task and the selected subtasks of a proposal
+func SplitTaskRequests(proposal *SplitProposal) (*todoist.TaskRequest, []*todoist.TaskRequest) {
+	parent := &todoist.TaskRequest{
+		Content:     proposal.Plan.Title,
+		Description: proposal.Plan.Description,
+		ProjectID:   proposal.ProjectID,
+	}
+	var subtasks []*todoist.TaskRequest
+	for _, subtask := range proposal.SelectedSubtasks() {
+		subtasks = append(subtasks, &todoist.TaskRequest{
+			Content:     subtask.Title,
+			Description: subtask.Description,
+			ProjectID:   proposal.ProjectID,
+		})
+	}
+	return parent, subtasks
+}
+
+// FormatSplitReport summarizes the created parent task and subtasks; the
+// first result is the parent
+func FormatSplitReport(proposal *SplitProposal, results []todoist.BatchTaskResult, lang i18n.Lang) string {
+	if len(results) == 0 || results[0].Err != nil {
+		return i18n.T(lang, i18n.SplitCreateFailed)
+	}
+
+	subtasks := proposal.SelectedSubtasks()
+	var failed []string
+	for i, result := range results[1:] {
+		if result.Err != nil && i < len(subtasks) {
+			failed = append(failed, fmt.Sprintf("• %s: %v", subtasks[i].Title, result.Err))
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.SplitCreated)+"\n%s",
+		proposal.Plan.Title, len(results)-1-len(failed), len(results)-1, todoist.TaskURL(results[0].ID))
+	if len(failed) > 0 {
+		fmt.Fprintf(&sb, "\n\n"+i18n.T(lang, i18n.SplitPartlyFailed)+"\n%s", len(failed), strings.Join(failed, "\n"))
+	}
+	return sb.String()
+}
+
+// truncateRunes shortens s to limit runes, marking the cut with an ellipsis
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestSplitCommand_PreviewsSubtasks(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	ConfigureMockDB(mockDB).WithProjectID(chatID, "p1", nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7, ChatID: chatID, OwnerID: chatID}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 7).Return([]db.Message{{ID: 1, Text: "Переносим биллинг"}}, nil)
+	mockAI := new(MockAIClient)
+	mockAI.On("RunPrompt", mock.Anything, ai.PromptSplitDiscussion, mock.Anything).
+		Return(`{"title": "Переезд биллинга", "subtasks": [{"title": "Выгрузить тарифы"}, {"title": "Настроить вебхуки"}]}`, nil)
+	store := NewSplitStore()
+
+	msg := NewSplitCommand(mockDB, mockAI, store).Execute(CreateCommandMessage(chatID, "/split"))
+
+	assert.Contains(t, msg.Text, "📌 Переезд биллинга")
+	assert.Contains(t, msg.Text, "2. Настроить вебхуки")
+	markup, ok := msg.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	if assert.True(t, ok) && assert.Len(t, markup.InlineKeyboard, 3) {
+		assert.Equal(t, "☑️ 1. Выгрузить тарифы", markup.InlineKeyboard[0][0].Text)
+		assert.Equal(t, "split_toggle:7:0", *markup.InlineKeyboard[0][0].CallbackData)
+		assert.Equal(t, "split_create:7", *markup.InlineKeyboard[2][0].CallbackData)
+	}
+
+	proposal, ok := store.Toggle(7, 0)
+	assert.True(t, ok)
+	assert.Equal(t, "⬜ 1. Выгрузить тарифы", SplitKeyboard(&proposal, i18n.Russian).InlineKeyboard[0][0].Text)
+	_, ok = store.Toggle(7, 5)
+	assert.False(t, ok)
+
+	taken, ok := store.Take(7)
+	assert.True(t, ok)
+	parent, subtasks := SplitTaskRequests(taken)
+	assert.Equal(t, &todoist.TaskRequest{Content: "Переезд биллинга", ProjectID: "p1"}, parent)
+	if assert.Len(t, subtasks, 1) {
+		assert.Equal(t, "Настроить вебхуки", subtasks[0].Content)
+	}
+}
+
+func TestSplitCommand_OnlyOwner(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	ConfigureMockDB(mockDB).WithProjectID(chatID, "p1", nil)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7, ChatID: chatID, OwnerID: 42}, nil)
+	mockAI := new(MockAIClient)
+
+	msg := NewSplitCommand(mockDB, mockAI, NewSplitStore()).Execute(CreateCommandMessage(chatID, "/split"))
+
+	assert.Contains(t, msg.Text, "Только автор обсуждения")
+	mockAI.AssertNotCalled(t, "RunPrompt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFormatSplitReport(t *testing.T) {
+	proposal := &SplitProposal{
+		Plan:     &ai.SplitPlan{Title: "Переезд биллинга", Subtasks: []ai.SplitSubtask{{Title: "A"}, {Title: "B"}}},
+		Selected: []bool{true, true},
+	}
+
+	report := FormatSplitReport(proposal, []todoist.BatchTaskResult{{ID: "p"}, {ID: "a"}, {Err: errors.New("boom")}}, i18n.Russian)
+
+	assert.Contains(t, report, "подзадачами: 1 из 2")
+	assert.Contains(t, report, todoist.TaskURL("p"))
+	assert.Contains(t, report, "• B: boom")
+	assert.Contains(t, FormatSplitReport(proposal, []todoist.BatchTaskResult{{Err: errors.New("boom")}}, i18n.Russian), "Не удалось создать")
+}
@@ -4,24 +4,34 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
 	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/topics"
+	"github.com/user/telegram-bot/internal/tracker"
 )
 
 type StartDiscussionCommand struct {
-	dbManager     DBManager
-	todoistClient todoist.Client
+	dbManager DBManager
+	trackers  *tracker.Selector
 }
 
 func NewStartDiscussionCommand(dbManager DBManager, todoistClient todoist.Client) *StartDiscussionCommand {
 	return &StartDiscussionCommand{
-		dbManager:     dbManager,
-		todoistClient: todoistClient,
+		dbManager: dbManager,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
 	}
 }
 
+// SetTrackers sets the trackers that offer a project when a discussion starts
+// in a chat without one
+func (c *StartDiscussionCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
 func (c *StartDiscussionCommand) Name() string {
 	return "start_discussion"
 }
@@ -32,29 +42,47 @@ func (c *StartDiscussionCommand) Description() string {
 
 func (c *StartDiscussionCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
 	ctx := context.Background()
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	// "/start_discussion billing-bug" runs a named discussion next to the others of the chat
+	var name string
+	if arg := strings.TrimSpace(message.CommandArguments()); arg != "" {
+		var ok bool
+		if name, ok = ParseSessionName(arg); !ok {
+			msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.DiscussionBadName), maxSessionNameLength))
+			return &msg
+		}
+	}
 
 	projectID, err := c.dbManager.GetTodoistProjectID(ctx, message.Chat.ID)
 	if err != nil {
 		if err == db.ErrProjectIDNotSet {
-			return buildProjectSelectionMessage(ctx, c.todoistClient, message.Chat.ID, "Сначала выберите проект Todoist:")
+			return buildProjectSelectionMessage(ctx, c.trackers, message.Chat.ID, lang, i18n.T(lang, i18n.StartChooseProject))
 		}
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error getting project ID: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.DiscussionProjectFailed), err))
 		return &msg
 	}
 
-	sessionID, err := c.dbManager.StartSession(ctx, message.Chat.ID, int64(message.From.ID))
+	sessionID, err := c.dbManager.StartSession(ctx, message.Chat.ID, topics.ThreadID(message), name, int64(message.From.ID))
 	if err != nil {
 		if err == db.ErrSessionAlreadyExists {
-			msg := tgbotapi.NewMessage(message.Chat.ID, "Обсуждение уже идёт! Прежде, чем начать новое завершите текущее.")
+			text := i18n.T(lang, i18n.DiscussionAlreadyRunning)
+			if name != "" {
+				text = fmt.Sprintf(i18n.T(lang, i18n.DiscussionNamedRunning), name, name)
+			}
+			msg := tgbotapi.NewMessage(message.Chat.ID, text)
 			return &msg
 		}
-		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf("Error starting discussion: %v", err))
+		msg := tgbotapi.NewMessage(message.Chat.ID, fmt.Sprintf(i18n.T(lang, i18n.DiscussionStartFailed), err))
 		return &msg
 	}
 
 	log.Printf("Start for id: %s session: %d\n", projectID, sessionID)
 
-	responseText := "Обсуждение началось.\nСообщения будут сохраняться, пока вы не создадите задачу (/create_task) или не завершите обсуждение (/cancel)."
+	responseText := i18n.T(lang, i18n.DiscussionStarted)
+	if name != "" {
+		responseText = fmt.Sprintf(i18n.T(lang, i18n.DiscussionNamedStarted), sessionTitle(name), name)
+	}
 
 	msg := tgbotapi.NewMessage(message.Chat.ID, responseText)
 	return &msg
@@ -101,3 +101,32 @@ func TestStartDiscussion_AlreadyActive(t *testing.T) {
 	// Verify mock
 	mockDBManager.AssertExpectations(t)
 }
+
+// Tests StartDiscussionCommand with a name: the session is started under it
+// and the reply explains how to address it
+func TestStartDiscussion_Named(t *testing.T) {
+	chatID := int64(123456789)
+
+	mockDBManager := new(MockDBManager)
+	ConfigureMockDB(mockDBManager).WithProjectID(chatID, "12345", nil)
+	mockDBManager.On("StartSession", mock.Anything, chatID, 0, "billing-bug", chatID).Return(2, nil)
+
+	cmd := NewStartDiscussionCommand(mockDBManager, new(MockTodoistClient))
+	response := cmd.Execute(CreateCommandMessage(chatID, "/start_discussion", "#Billing-Bug"))
+
+	assert.Contains(t, response.Text, "Обсуждение «billing-bug» началось")
+	assert.Contains(t, response.Text, "/create_task billing-bug")
+	mockDBManager.AssertExpectations(t)
+}
+
+// Tests StartDiscussionCommand with a name that cannot be a #tag
+func TestStartDiscussion_InvalidName(t *testing.T) {
+	chatID := int64(123456789)
+	mockDBManager := new(MockDBManager)
+
+	cmd := NewStartDiscussionCommand(mockDBManager, new(MockTodoistClient))
+	response := cmd.Execute(CreateCommandMessage(chatID, "/start_discussion", "billing bug"))
+
+	assert.Contains(t, response.Text, "Название обсуждения")
+	mockDBManager.AssertNotCalled(t, "StartSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,165 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/timezone"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/topics"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const statusTimeout = 15 * time.Second
+
+// StatusCommand shows what the bot knows about the chat: the open
+// discussions and the settings tasks are created with
+type StatusCommand struct {
+	dbManager DBManager
+	trackers  *tracker.Selector
+	now       func() time.Time
+}
+
+func NewStatusCommand(todoistClient todoist.Client, dbManager DBManager) *StatusCommand {
+	return &StatusCommand{
+		dbManager: dbManager,
+		trackers:  tracker.Single(tracker.NewTodoist(todoistClient)),
+		now:       time.Now,
+	}
+}
+
+// SetTrackers sets the trackers the chat's project is looked up in
+func (c *StatusCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *StatusCommand) Name() string {
+	return "status"
+}
+
+func (c *StatusCommand) Description() string {
+	return "Состояние бота в чате: идущие обсуждения, проект, трекер и часовой пояс"
+}
+
+func (c *StatusCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), statusTimeout)
+	defer cancel()
+
+	chatID := message.Chat.ID
+	stored, err := c.dbManager.GetChatTimezone(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting timezone for chat %d: %v", chatID, err)
+	}
+	loc := timezone.ForChat(stored)
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	var sb strings.Builder
+	sb.WriteString(i18n.T(lang, i18n.StatusHeader) + "\n\n")
+	c.writeSessions(ctx, &sb, chatID, topics.ThreadID(message), loc, lang)
+	sb.WriteString("\n")
+	c.writeProject(ctx, &sb, chatID, lang)
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.StatusTracker)+"\n", c.trackers.Kind(chatID))
+
+	zone := timezone.Label(loc, c.now())
+	if stored == "" {
+		zone += i18n.T(lang, i18n.StatusDefaultZone)
+	}
+	fmt.Fprintf(&sb, i18n.T(lang, i18n.StatusTimezone)+"\n", zone)
+
+	msg := tgbotapi.NewMessage(chatID, strings.TrimRight(sb.String(), "\n"))
+	return &msg
+}
+
+// writeSessions describes each open discussion of the topic: who started it,
+// when, and how many messages it has collected
+func (c *StatusCommand) writeSessions(ctx context.Context, sb *strings.Builder, chatID int64, threadID int, loc *time.Location, lang i18n.Lang) {
+	sessions, err := c.dbManager.ListActiveSessions(ctx, chatID, threadID)
+	if err != nil {
+		log.Printf("Error listing active sessions for chat %d: %v", chatID, err)
+		sb.WriteString(i18n.T(lang, i18n.StatusSessionsFailed) + "\n")
+		return
+	}
+	if len(sessions) == 0 {
+		sb.WriteString(i18n.T(lang, i18n.StatusNoSessions) + "\n")
+		return
+	}
+
+	if len(sessions) == 1 {
+		sb.WriteString(i18n.T(lang, i18n.StatusOneSession))
+	} else {
+		fmt.Fprintf(sb, i18n.T(lang, i18n.StatusSessions), len(sessions))
+	}
+	sb.WriteString(":\n")
+	for _, session := range sessions {
+		name := i18n.T(lang, i18n.StatusUnnamed)
+		if session.Name != "" {
+			name = "«" + session.Name + "»"
+		}
+		fmt.Fprintf(sb, i18n.T(lang, i18n.StatusSession)+"\n",
+			name, c.sessionOwner(ctx, session), session.StartedAt.In(loc).Format("02.01.2006 15:04"), c.sessionMessages(ctx, session.ID))
+	}
+}
+
+// sessionOwner names who started a discussion, as they appear among its participants
+func (c *StatusCommand) sessionOwner(ctx context.Context, session db.Session) string {
+	participants, err := c.dbManager.GetSessionParticipants(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error getting participants of session %d: %v", session.ID, err)
+	}
+	for _, p := range participants {
+		if p.UserID != session.OwnerID {
+			continue
+		}
+		if p.Username.Valid && p.Username.String != "" {
+			return "@" + p.Username.String
+		}
+		if p.DisplayName.Valid && p.DisplayName.String != "" {
+			return p.DisplayName.String
+		}
+	}
+	return fmt.Sprintf("id %d", session.OwnerID)
+}
+
+func (c *StatusCommand) sessionMessages(ctx context.Context, sessionID int) string {
+	messages, err := c.dbManager.GetSessionMessages(ctx, sessionID)
+	if err != nil {
+		log.Printf("Error getting messages of session %d: %v", sessionID, err)
+		return "?"
+	}
+	return fmt.Sprint(len(messages))
+}
+
+// writeProject names the chat's project as the tracker calls it; the ID is
+// shown alone when the tracker cannot be asked
+func (c *StatusCommand) writeProject(ctx context.Context, sb *strings.Builder, chatID int64, lang i18n.Lang) {
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		sb.WriteString(i18n.T(lang, i18n.StatusNoProject) + "\n")
+		return
+	}
+
+	name := ""
+	if client, err := c.trackers.ForChat(chatID); err == nil {
+		projects, err := client.ListProjects(ctx)
+		if err != nil {
+			log.Printf("Error listing projects for /status in chat %d: %v", chatID, err)
+		}
+		for _, project := range projects {
+			if project.ID == projectID {
+				name = project.Name
+				break
+			}
+		}
+	}
+	if name == "" {
+		fmt.Fprintf(sb, i18n.T(lang, i18n.StatusProjectID)+"\n", projectID)
+		return
+	}
+	fmt.Fprintf(sb, i18n.T(lang, i18n.StatusProject)+"\n", name, projectID)
+}
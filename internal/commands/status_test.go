@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestStatusCommand_Execute_ActiveDiscussion(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("Asia/Tokyo", nil)
+	mockDB.On("ListActiveSessions", mock.Anything, chatID, 0).Return([]db.Session{
+		{ID: 7, ChatID: chatID, Name: "billing-bug", OwnerID: 42, StartedAt: time.Date(2026, 10, 15, 5, 30, 0, 0, time.UTC)},
+	}, nil)
+	mockDB.On("GetSessionParticipants", mock.Anything, 7).Return([]db.SessionParticipant{
+		{SessionID: 7, UserID: 42, Username: sql.NullString{String: "alice", Valid: true}},
+	}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 7).Return([]db.Message{{}, {}, {}}, nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	mockTodoist := new(MockTodoistClient)
+	mockTodoist.On("GetProjects", mock.Anything).Return([]todoist.Project{{ID: "p1", Name: "Работа"}}, nil)
+
+	response := NewStatusCommand(mockTodoist, mockDB).Execute(CreateCommandMessage(chatID, "/status"))
+
+	assert.Contains(t, response.Text, "• «billing-bug» — начал @alice 15.10.2026 14:30, сообщений: 3")
+	assert.Contains(t, response.Text, "📁 Проект: Работа (ID p1)")
+	assert.Contains(t, response.Text, "🗂 Трекер: todoist")
+	assert.Contains(t, response.Text, "🕒 Часовой пояс: Asia/Tokyo, UTC+09:00")
+	assert.NotContains(t, response.Text, "по умолчанию")
+}
+
+func TestStatusCommand_Execute_Idle(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+	mockDB.On("ListActiveSessions", mock.Anything, chatID, 0).Return([]db.Session{}, nil)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("", nil)
+
+	response := NewStatusCommand(new(MockTodoistClient), mockDB).Execute(CreateCommandMessage(chatID, "/status"))
+
+	assert.Contains(t, response.Text, "Обсуждения нет. Начать: /start_discussion")
+	assert.Contains(t, response.Text, "Проект не выбран: /set_project")
+	assert.Contains(t, response.Text, "Europe/Moscow, UTC+03:00 (по умолчанию)")
+}
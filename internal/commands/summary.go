@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// SummaryCommand sums up the discussion so far: decisions and open questions,
+// without creating a task or closing the session
+type SummaryCommand struct {
+	dbManager DBManager
+	aiClient  ai.Client
+}
+
+func NewSummaryCommand(dbManager DBManager, aiClient ai.Client) *SummaryCommand {
+	return &SummaryCommand{dbManager: dbManager, aiClient: aiClient}
+}
+
+func (c *SummaryCommand) Name() string {
+	return "summary"
+}
+
+func (c *SummaryCommand) Description() string {
+	return "Краткое содержание обсуждения: решения и открытые вопросы"
+}
+
+// JobKind returns the job queue kind for the summary
+func (c *SummaryCommand) JobKind() string {
+	return "summarize_discussion"
+}
+
+func (c *SummaryCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	return c.ExecuteContext(context.Background(), message)
+}
+
+// ExecuteContext handles the command execution within a job context
+func (c *SummaryCommand) ExecuteContext(ctx context.Context, message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	chatID := message.Chat.ID
+	name := SessionName(message)
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	session, err := FindSession(ctx, c.dbManager, message)
+	if errors.Is(err, db.ErrNoActiveSession) {
+		msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.SessionNotFound), sessionTitle(name), name))
+		return &msg
+	}
+	if err != nil {
+		log.Printf("Error getting session for summary in chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ErrorSessionLoad))
+		return &msg
+	}
+	ctx = ai.WithUsageScope(ctx, chatID, session.ID)
+
+	messages, err := c.dbManager.GetSessionMessages(ctx, session.ID)
+	if err != nil {
+		log.Printf("Error getting messages of session %d for summary: %v", session.ID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.ErrorMessagesLoad))
+		return &msg
+	}
+	texts := discussionTexts(messages)
+	if len(texts) == 0 {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SummaryNoMessages))
+		return &msg
+	}
+
+	summary, err := c.aiClient.RunPrompt(ctx, ai.PromptSummarize, strings.Join(texts, "\n"))
+	if err != nil {
+		log.Printf("AI summary of session %d failed: %v", session.ID, err)
+		if errors.Is(err, ai.ErrUnavailable) {
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.AIUnavailable))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SummaryFailed))
+		return &msg
+	}
+	if summary == "" {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SummaryEmpty))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(chatID, FormatDiscussionSummary(name, len(texts), summary, lang))
+	return &msg
+}
+
+// FormatDiscussionSummary titles the AI summary of a discussion
+func FormatDiscussionSummary(name string, messages int, summary string, lang i18n.Lang) string {
+	return fmt.Sprintf(i18n.T(lang, i18n.SummaryText), sessionTitle(name), messages, summary)
+}
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestSummaryCommand_SummarizesDiscussion(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7, ChatID: chatID, OwnerID: 42}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 7).Return([]db.Message{
+		{ID: 1, Text: "Переносим биллинг на новый API", Username: sql.NullString{String: "anna", Valid: true}},
+		{ID: 2, Text: "Кто пишет миграцию?"},
+	}, nil)
+	mockAI := new(MockAIClient)
+	mockAI.On("RunPrompt", mock.Anything, ai.PromptSummarize, mock.MatchedBy(func(input string) bool {
+		return assert.Contains(t, input, "anna") && assert.Contains(t, input, "Кто пишет миграцию?")
+	})).Return("Суть:\n• Переезд биллинга\nОткрытые вопросы:\n• Кто пишет миграцию", nil)
+
+	msg := NewSummaryCommand(mockDB, mockAI).Execute(CreateCommandMessage(chatID, "/summary"))
+
+	assert.Contains(t, msg.Text, "📝 Краткое содержание обсуждения (сообщений: 2)")
+	assert.Contains(t, msg.Text, "• Кто пишет миграцию")
+	mockDB.AssertNotCalled(t, "CloseSession", mock.Anything, mock.Anything)
+}
+
+func TestSummaryCommand_WithoutDiscussion(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(nil, db.ErrNoActiveSession)
+	mockAI := new(MockAIClient)
+
+	msg := NewSummaryCommand(mockDB, mockAI).Execute(CreateCommandMessage(chatID, "/summary"))
+
+	assert.Contains(t, msg.Text, "Нет обсуждения")
+	mockAI.AssertNotCalled(t, "RunPrompt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSummaryCommand_ReportsUnavailableAI(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetActiveSession", mock.Anything, chatID, 0).Return(&db.Session{ID: 7, ChatID: chatID}, nil)
+	mockDB.On("GetSessionMessages", mock.Anything, 7).Return([]db.Message{{ID: 1, Text: "Привет"}}, nil)
+	mockAI := new(MockAIClient)
+	mockAI.On("RunPrompt", mock.Anything, ai.PromptSummarize, mock.Anything).Return("", ai.ErrUnavailable)
+
+	msg := NewSummaryCommand(mockDB, mockAI).Execute(CreateCommandMessage(chatID, "/summary"))
+
+	assert.Equal(t, i18n.T(i18n.Russian, i18n.AIUnavailable), msg.Text)
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/taskdefaults"
+)
+
+// TaskDefaultsCommand shows the chat's task defaults; bot admins change them
+type TaskDefaultsCommand struct {
+	dbManager DBManager
+	admins    admin.Users
+}
+
+func NewTaskDefaultsCommand(dbManager DBManager, admins admin.Users) *TaskDefaultsCommand {
+	return &TaskDefaultsCommand{dbManager: dbManager, admins: admins}
+}
+
+func (c *TaskDefaultsCommand) Name() string {
+	return "task_defaults"
+}
+
+func (c *TaskDefaultsCommand) Description() string {
+	return "Значения по умолчанию для задач чата; администраторы: /task_defaults due=+7d priority=medium labels=from-telegram"
+}
+
+func (c *TaskDefaultsCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	usage := i18n.T(lang, i18n.DefaultsUsage)
+
+	arg := strings.TrimSpace(message.CommandArguments())
+	if arg == "" {
+		msg := tgbotapi.NewMessage(chatID, formatTaskDefaults(ChatTaskDefaults(ctx, c.dbManager, chatID), ChatPriorityNames(ctx, c.dbManager, chatID), lang)+"\n\n"+usage)
+		return &msg
+	}
+
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.DefaultsAdminOnly))
+		return &msg
+	}
+
+	var policy taskdefaults.Policy
+	if arg != "reset" {
+		var err error
+		policy, err = taskdefaults.Parse(arg)
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.DefaultsInvalid)+"\n\n%s", err, usage))
+			return &msg
+		}
+	}
+
+	if err := c.dbManager.SetTaskDefaults(ctx, chatID, policy.String()); err != nil {
+		log.Printf("Error setting task defaults for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, formatTaskDefaults(policy, ChatPriorityNames(ctx, c.dbManager, chatID), lang))
+	return &msg
+}
+
+// ChatTaskDefaults returns the task defaults of a chat; errors fall back to no defaults
+func ChatTaskDefaults(ctx context.Context, dbManager DBManager, chatID int64) taskdefaults.Policy {
+	stored, err := dbManager.GetTaskDefaults(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting task defaults for chat %d: %v", chatID, err)
+		return taskdefaults.Policy{}
+	}
+	policy, err := taskdefaults.Parse(stored)
+	if err != nil {
+		log.Printf("Ignoring invalid task defaults %q for chat %d: %v", stored, chatID, err)
+		return taskdefaults.Policy{}
+	}
+	return policy
+}
+
+// ApplyTaskDefaults fills in what the analysis left out and returns the new
+// due date with a note for the preview, empty when nothing was applied
+func ApplyTaskDefaults(task *ai.AnalyzedTask, dueISO string, policy taskdefaults.Policy, now time.Time, lang i18n.Lang) (string, string) {
+	if policy.Empty() {
+		return dueISO, ""
+	}
+	draft := taskdefaults.Task{DueISO: dueISO, Priority: priority.FromTodoist(task.Priority), Labels: task.Labels}
+	applied := policy.Apply(&draft, now, now.Location())
+	if len(applied) == 0 {
+		return dueISO, ""
+	}
+	task.Priority = draft.Priority.Todoist()
+	task.Labels = draft.Labels
+
+	parts := make([]string, 0, len(applied))
+	for _, a := range applied {
+		switch a {
+		case taskdefaults.AppliedDue:
+			parts = append(parts, i18n.T(lang, i18n.DefaultsDue))
+		case taskdefaults.AppliedPriority:
+			parts = append(parts, i18n.T(lang, i18n.DefaultsPriority))
+		case taskdefaults.AppliedLabels:
+			parts = append(parts, i18n.T(lang, i18n.DefaultsLabels))
+		}
+	}
+	return draft.DueISO, fmt.Sprintf(i18n.T(lang, i18n.DefaultsApplied), strings.Join(parts, ", "))
+}
+
+func formatTaskDefaults(policy taskdefaults.Policy, names priority.Names, lang i18n.Lang) string {
+	if policy.Empty() {
+		return i18n.T(lang, i18n.DefaultsUnset)
+	}
+	lines := []string{i18n.T(lang, i18n.DefaultsHeader)}
+	if policy.DueInDays > 0 {
+		lines = append(lines, fmt.Sprintf(i18n.T(lang, i18n.DefaultsDueLine), policy.DueInDays))
+	}
+	if policy.Priority.Valid() {
+		lines = append(lines, fmt.Sprintf(i18n.T(lang, i18n.DefaultsPriorityLine), names.Display(policy.Priority)))
+	}
+	if len(policy.Labels) > 0 {
+		lines = append(lines, fmt.Sprintf(i18n.T(lang, i18n.DefaultsLabelsLine), strings.Join(policy.Labels, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
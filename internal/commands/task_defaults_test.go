@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/taskdefaults"
+)
+
+func TestTaskDefaultsCommand_Execute(t *testing.T) {
+	chatID := int64(555)
+
+	t.Run("shows current defaults", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetTaskDefaults", mock.Anything, chatID).Return("due=+7d labels=from-telegram", nil)
+		mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("", nil)
+
+		response := NewTaskDefaultsCommand(mockDB, admin.Users{}).Execute(CreateCommandMessage(chatID, "/task_defaults"))
+
+		assert.Contains(t, response.Text, "через 7 дн.")
+		assert.Contains(t, response.Text, "from-telegram")
+	})
+
+	t.Run("admin sets defaults", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetTaskDefaults", mock.Anything, chatID, "due=+3d priority=high").Return(nil)
+		mockDB.On("GetPriorityNames", mock.Anything, chatID).Return("high=Мажор", nil)
+
+		admins, err := admin.ParseUsers("555")
+		assert.NoError(t, err)
+
+		response := NewTaskDefaultsCommand(mockDB, admins).Execute(CreateCommandMessage(chatID, "/task_defaults", "priority=high due=3d"))
+
+		assert.Contains(t, response.Text, "приоритет — Мажор")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("others cannot change defaults", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewTaskDefaultsCommand(mockDB, admin.Users{}).Execute(CreateCommandMessage(chatID, "/task_defaults", "reset"))
+
+		assert.Contains(t, response.Text, "только администратор")
+		mockDB.AssertNotCalled(t, "SetTaskDefaults", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestApplyTaskDefaults(t *testing.T) {
+	task := &ai.AnalyzedTask{Priority: priority.LevelLow.Todoist(), Labels: []string{}}
+	policy := taskdefaults.Policy{DueInDays: 1, Priority: priority.LevelHigh, Labels: []string{"from-telegram"}}
+
+	dueISO, note := ApplyTaskDefaults(task, "", policy, time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC), i18n.Russian)
+
+	assert.Equal(t, "2026-05-05", dueISO)
+	assert.Equal(t, priority.LevelHigh.Todoist(), task.Priority)
+	assert.Equal(t, []string{"from-telegram"}, task.Labels)
+	assert.Equal(t, "⚙️ По умолчанию чата: срок, приоритет, метки", note)
+}
@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// taskIDPattern matches bare Todoist task IDs, legacy numeric and v2 alphanumeric
+var taskIDPattern = regexp.MustCompile(`^[0-9A-Za-z]{6,}$`)
+
+// ParseTaskReference resolves what users paste where a task ID is expected:
+// a Todoist task link or the ID itself. Commands that take a task use it so
+// nobody has to dig the numeric ID out of a link by hand.
+func ParseTaskReference(arg string) (string, bool) {
+	arg = strings.Trim(strings.TrimSpace(arg), "<>")
+	if arg == "" {
+		return "", false
+	}
+	if id, err := todoist.ParseTaskURL(arg); err == nil {
+		return id, true
+	}
+	if taskIDPattern.MatchString(arg) {
+		return arg, true
+	}
+	return "", false
+}
@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTaskReference(t *testing.T) {
+	tests := map[string]string{
+		"https://app.todoist.com/app/task/fix-login-6X7rM8997g3RQmvh": "6X7rM8997g3RQmvh",
+		"<https://todoist.com/showTask?id=2995104339>":                "2995104339",
+		"6X7rM8997g3RQmvh": "6X7rM8997g3RQmvh",
+		" 2995104339 ":     "2995104339",
+	}
+	for input, want := range tests {
+		got, ok := ParseTaskReference(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "https://example.com/task/2995104339", "купить молоко", "42"} {
+		_, ok := ParseTaskReference(input)
+		assert.False(t, ok, input)
+	}
+}
@@ -0,0 +1,250 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/dates"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tasklist"
+	"github.com/user/telegram-bot/internal/todoist"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const (
+	taskViewTimeout = 15 * time.Second
+	// maxTaskViewTasks keeps a view and its buttons within one message
+	maxTaskViewTasks = 20
+	// taskViewButtonsPerRow fits the numbered complete buttons on a phone screen
+	taskViewButtonsPerRow = 5
+)
+
+// taskView is a Todoist filter the chat's project tasks are shown by
+type taskView struct {
+	query       string
+	heading     i18n.Key
+	empty       i18n.Key
+	description string
+	// days is the number of days the heading and the empty text count, if any
+	days int
+	// showDate puts the due day next to the due time; a view of one day needs only the time
+	showDate bool
+}
+
+var taskViews = map[string]taskView{
+	"today": {
+		query:       "today",
+		heading:     i18n.ViewTodayHeading,
+		empty:       i18n.ViewTodayEmpty,
+		description: "Задачи проекта чата на сегодня",
+	},
+	"upcoming": {
+		query:       fmt.Sprintf("%d days", tasklist.UpcomingDays),
+		heading:     i18n.ViewUpcomingHeading,
+		empty:       i18n.ViewUpcomingEmpty,
+		description: fmt.Sprintf("Задачи проекта чата на ближайшие %d дней", tasklist.UpcomingDays),
+		days:        tasklist.UpcomingDays,
+		showDate:    true,
+	},
+	"overdue": {
+		query:       "overdue",
+		heading:     i18n.ViewOverdueHeading,
+		empty:       i18n.ViewOverdueEmpty,
+		description: "Просроченные задачи проекта чата",
+		showDate:    true,
+	},
+}
+
+// TaskViewCommand shows the chat project's tasks selected by a Todoist
+// filter: /today, /upcoming or /overdue
+type TaskViewCommand struct {
+	name      string
+	view      taskView
+	filterer  todoist.TaskFilterer
+	dbManager DBManager
+	trackers  *tracker.Selector
+}
+
+func NewTodayCommand(filterer todoist.TaskFilterer, dbManager DBManager) *TaskViewCommand {
+	return newTaskViewCommand("today", filterer, dbManager)
+}
+
+func NewUpcomingCommand(filterer todoist.TaskFilterer, dbManager DBManager) *TaskViewCommand {
+	return newTaskViewCommand("upcoming", filterer, dbManager)
+}
+
+func NewOverdueCommand(filterer todoist.TaskFilterer, dbManager DBManager) *TaskViewCommand {
+	return newTaskViewCommand("overdue", filterer, dbManager)
+}
+
+func newTaskViewCommand(name string, filterer todoist.TaskFilterer, dbManager DBManager) *TaskViewCommand {
+	return &TaskViewCommand{name: name, view: taskViews[name], filterer: filterer, dbManager: dbManager}
+}
+
+// SetTrackers sets the trackers of the chats; Todoist filters only serve Todoist chats
+func (c *TaskViewCommand) SetTrackers(trackers *tracker.Selector) {
+	c.trackers = trackers
+}
+
+func (c *TaskViewCommand) Name() string {
+	return c.name
+}
+
+func (c *TaskViewCommand) Description() string {
+	return c.view.description
+}
+
+func (c *TaskViewCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), taskViewTimeout)
+	defer cancel()
+
+	text, keyboard := c.Render(ctx, message.Chat.ID)
+	msg := tgbotapi.NewMessage(message.Chat.ID, text)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.DisableWebPagePreview = true
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	return &msg
+}
+
+// Render lists the tasks of the view with a complete button for each, nil
+// when there are none. Failures are rendered as text too.
+func (c *TaskViewCommand) Render(ctx context.Context, chatID int64) (string, *tgbotapi.InlineKeyboardMarkup) {
+	lang := ChatLanguage(ctx, c.dbManager, chatID)
+	if c.trackers != nil && c.trackers.Kind(chatID) != tracker.KindTodoist {
+		return i18n.T(lang, i18n.ViewTodoistOnly), nil
+	}
+
+	projectID, err := c.dbManager.GetTodoistProjectID(ctx, chatID)
+	if err != nil || projectID == "" {
+		return i18n.T(lang, i18n.ViewProjectFirst), nil
+	}
+
+	tasks, err := c.filterer.GetTasksByFilter(ctx, c.view.query)
+	if err != nil {
+		log.Printf("Error getting %s tasks in chat %d: %v", c.name, chatID, err)
+		return i18n.T(lang, i18n.ViewTasksFailed), nil
+	}
+
+	// Filters search the whole account, the chat only sees its own project
+	var projectTasks []*todoist.TaskResponse
+	for _, task := range tasks {
+		if task.ProjectID == projectID {
+			projectTasks = append(projectTasks, task)
+		}
+	}
+	if len(projectTasks) == 0 {
+		return c.view.text(c.view.empty, lang), nil
+	}
+	sortTaskView(projectTasks)
+
+	return formatTaskView(c.view, projectTasks, ChatLocation(ctx, c.dbManager, chatID), lang), taskViewKeyboard(c.name, projectTasks)
+}
+
+// text is the text of key in lang with the view's number of days
+func (v taskView) text(key i18n.Key, lang i18n.Lang) string {
+	if v.days > 0 {
+		return fmt.Sprintf(i18n.T(lang, key), v.days)
+	}
+	return i18n.T(lang, key)
+}
+
+// sortTaskView puts the tasks in the order they are due, the more urgent first
+// within the same time
+func sortTaskView(tasks []*todoist.TaskResponse) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		di, dj := taskViewDueKey(tasks[i]), taskViewDueKey(tasks[j])
+		if di != dj {
+			return di < dj
+		}
+		return tasks[i].Priority > tasks[j].Priority
+	})
+}
+
+func taskViewDueKey(task *todoist.TaskResponse) string {
+	if task.Due == nil {
+		return ""
+	}
+	if task.Due.DateTime != "" {
+		return task.Due.DateTime
+	}
+	return task.Due.Date
+}
+
+// formatTaskView renders one numbered line per task: the linked title, when
+// it is due and its Todoist priority (p1 is the most urgent)
+func formatTaskView(view taskView, tasks []*todoist.TaskResponse, loc *time.Location, lang i18n.Lang) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* (%d):\n\n", view.text(view.heading, lang), len(tasks))
+	for i, task := range tasks {
+		if i == maxTaskViewTasks {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.MoreItems)+"\n", len(tasks)-maxTaskViewTasks)
+			break
+		}
+		fmt.Fprintf(&sb, "%d. [%s](%s)", i+1, escapeTelegramMarkdown(task.Content), todoist.TaskURL(task.ID))
+		if due := formatTaskViewDue(task.Due, loc, view.showDate); due != "" {
+			sb.WriteString(" · " + due)
+		}
+		if task.Priority > 1 {
+			fmt.Fprintf(&sb, " · p%d", 5-task.Priority)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// formatTaskViewDue shows the due time in the chat's zone, e.g. "15.10 14:00";
+// floating times are shown as set
+func formatTaskViewDue(due *todoist.DueObject, loc *time.Location, showDate bool) string {
+	if due == nil {
+		return ""
+	}
+	layout := "15:04"
+	if showDate {
+		layout = "02.01 15:04"
+	}
+	if due.DateTime != "" {
+		if at, err := time.Parse(dates.DateTimeLayout, due.DateTime); err == nil {
+			return at.In(loc).Format(layout)
+		}
+		if at, err := time.Parse("2006-01-02T15:04:05", due.DateTime); err == nil {
+			return at.Format(layout)
+		}
+	}
+	if !showDate {
+		return ""
+	}
+	day, err := time.Parse(dates.DateLayout, due.Date)
+	if err != nil {
+		return due.Date
+	}
+	return day.Format("02.01")
+}
+
+// taskViewKeyboard has a numbered complete button for each listed task
+func taskViewKeyboard(view string, tasks []*todoist.TaskResponse) *tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, task := range tasks {
+		if i == maxTaskViewTasks {
+			break
+		}
+		data := strings.Join([]string{CallbackTaskViewDone, view, task.ID}, CallbackDataSeparator)
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ %d", i+1), data))
+		if len(row) == taskViewButtonsPerRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
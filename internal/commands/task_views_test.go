@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+func TestTaskViewCommand_Execute_ListsChatProjectTasks(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+	mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("Asia/Tokyo", nil)
+	filterer := &fakeBulkUpdater{tasks: []*todoist.TaskResponse{
+		{ID: "t1", Content: "Релиз", ProjectID: "p1", Priority: 4, Due: &todoist.DueObject{Date: "2026-10-17"}},
+		{ID: "t2", Content: "Чужая задача", ProjectID: "p2"},
+		{ID: "t3", Content: "Созвон", ProjectID: "p1", Due: &todoist.DueObject{Date: "2026-10-16", DateTime: "2026-10-16T06:00:00Z"}},
+	}}
+
+	response := NewUpcomingCommand(filterer, mockDB).Execute(CreateCommandMessage(chatID, "/upcoming"))
+
+	assert.Equal(t, "7 days", filterer.query)
+	assert.Contains(t, response.Text, "(2):")
+	assert.Contains(t, response.Text, "1. [Созвон](https://app.todoist.com/app/task/t3) · 16.10 15:00\n")
+	assert.Contains(t, response.Text, "2. [Релиз](https://app.todoist.com/app/task/t1) · 17.10 · p1\n")
+	assert.NotContains(t, response.Text, "Чужая задача")
+
+	keyboard, ok := response.ReplyMarkup.(tgbotapi.InlineKeyboardMarkup)
+	require.True(t, ok)
+	require.Len(t, keyboard.InlineKeyboard, 1)
+	assert.Equal(t, "✅ 1", keyboard.InlineKeyboard[0][0].Text)
+	assert.Equal(t, "view_done:upcoming:t3", *keyboard.InlineKeyboard[0][0].CallbackData)
+}
+
+func TestTaskViewCommand_Execute_Empty(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("p1", nil)
+
+	response := NewOverdueCommand(&fakeBulkUpdater{}, mockDB).Execute(CreateCommandMessage(chatID, "/overdue"))
+
+	assert.Equal(t, "Просроченных задач в проекте чата нет.", response.Text)
+	assert.Nil(t, response.ReplyMarkup)
+}
+
+func TestTaskViewCommand_Execute_NeedsProject(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("GetTodoistProjectID", mock.Anything, chatID).Return("", nil)
+	filterer := &fakeBulkUpdater{}
+
+	response := NewTodayCommand(filterer, mockDB).Execute(CreateCommandMessage(chatID, "/today"))
+
+	assert.Contains(t, response.Text, "/set\\_project")
+	assert.Empty(t, filterer.query)
+}
+
+func TestTaskViewKeyboard_WrapsRows(t *testing.T) {
+	tasks := make([]*todoist.TaskResponse, 7)
+	for i := range tasks {
+		tasks[i] = &todoist.TaskResponse{ID: string(rune('a' + i))}
+	}
+
+	keyboard := taskViewKeyboard("today", tasks)
+
+	require.Len(t, keyboard.InlineKeyboard, 2)
+	assert.Len(t, keyboard.InlineKeyboard[0], taskViewButtonsPerRow)
+	assert.Equal(t, "✅ 7", keyboard.InlineKeyboard[1][1].Text)
+}
@@ -2,9 +2,11 @@ package commands
 
 import (
 	"context"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/activity"
 	"github.com/user/telegram-bot/internal/ai"
 	"github.com/user/telegram-bot/internal/db"
 	"github.com/user/telegram-bot/internal/tasklinks"
@@ -50,6 +52,17 @@ type MockDBManager struct {
 	mock.Mock
 }
 
+// expectsLanguage registers an unset language for a language lookup the test
+// did not mock, so only tests about languages need to mock them
+func (m *MockDBManager) expectsLanguage(method string) {
+	for _, call := range m.ExpectedCalls {
+		if call.Method == method {
+			return
+		}
+	}
+	m.On(method, mock.Anything, mock.Anything).Return("", nil)
+}
+
 func (m *MockDBManager) EnsureChatExists(ctx context.Context, chatID int64) error {
 	args := m.Called(ctx, chatID)
 	return args.Error(0)
@@ -60,26 +73,52 @@ func (m *MockDBManager) SetTodoistProjectID(ctx context.Context, chatID int64, p
 	return args.Error(0)
 }
 
-func (m *MockDBManager) GetTodoistProjectID(ctx context.Context, chatID int64) (string, error) {
+func (m *MockDBManager) SetDefaultSection(ctx context.Context, chatID int64, sectionID string) error {
+	args := m.Called(ctx, chatID, sectionID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetDefaultSection(ctx context.Context, chatID int64) (string, error) {
 	args := m.Called(ctx, chatID)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockDBManager) HasActiveSession(ctx context.Context, chatID int64) (bool, error) {
+func (m *MockDBManager) GetTodoistProjectID(ctx context.Context, chatID int64) (string, error) {
 	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) HasActiveSession(ctx context.Context, chatID int64, threadID int) (bool, error) {
+	args := m.Called(ctx, chatID, threadID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockDBManager) GetActiveSession(ctx context.Context, chatID int64) (*db.Session, error) {
-	args := m.Called(ctx, chatID)
+func (m *MockDBManager) GetActiveSession(ctx context.Context, chatID int64, threadID int) (*db.Session, error) {
+	args := m.Called(ctx, chatID, threadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.Session), args.Error(1)
+}
+
+func (m *MockDBManager) GetNamedSession(ctx context.Context, chatID int64, threadID int, name string) (*db.Session, error) {
+	args := m.Called(ctx, chatID, threadID, name)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*db.Session), args.Error(1)
 }
 
-func (m *MockDBManager) StartSession(ctx context.Context, chatID int64, ownerID int64) (int, error) {
-	args := m.Called(ctx, chatID, ownerID)
+func (m *MockDBManager) ListActiveSessions(ctx context.Context, chatID int64, threadID int) ([]db.Session, error) {
+	args := m.Called(ctx, chatID, threadID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Session), args.Error(1)
+}
+
+func (m *MockDBManager) StartSession(ctx context.Context, chatID int64, threadID int, name string, ownerID int64) (int, error) {
+	args := m.Called(ctx, chatID, threadID, name, ownerID)
 	return args.Int(0), args.Error(1)
 }
 
@@ -88,21 +127,108 @@ func (m *MockDBManager) IsSessionOwner(ctx context.Context, sessionID int, userI
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockDBManager) CloseSession(ctx context.Context, chatID int64) error {
-	args := m.Called(ctx, chatID)
+func (m *MockDBManager) CloseSession(ctx context.Context, sessionID int) error {
+	args := m.Called(ctx, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SaveMessage(ctx context.Context, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
+	args := m.Called(ctx, chatID, threadID, messageID, userID, username, text, links)
 	return args.Error(0)
 }
 
-func (m *MockDBManager) SaveMessage(ctx context.Context, chatID int64, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
-	args := m.Called(ctx, chatID, messageID, userID, username, text, links)
+func (m *MockDBManager) SaveSessionMessage(ctx context.Context, sessionID int, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
+	args := m.Called(ctx, sessionID, chatID, threadID, messageID, userID, username, text, links)
 	return args.Error(0)
 }
 
+func (m *MockDBManager) GetMessageSession(ctx context.Context, chatID int64, messageID int) (int, error) {
+	args := m.Called(ctx, chatID, messageID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockDBManager) GetSessionMessages(ctx context.Context, sessionID int) ([]db.Message, error) {
 	args := m.Called(ctx, sessionID)
 	return args.Get(0).([]db.Message), args.Error(1)
 }
 
+func (m *MockDBManager) SaveAttachment(ctx context.Context, attachment db.Attachment) error {
+	args := m.Called(ctx, attachment)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetSessionAttachments(ctx context.Context, sessionID int) ([]db.Attachment, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.Attachment), args.Error(1)
+}
+
+func (m *MockDBManager) AddSessionParticipant(ctx context.Context, sessionID int, userID int64, username, displayName string) error {
+	args := m.Called(ctx, sessionID, userID, username, displayName)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetSessionParticipants(ctx context.Context, sessionID int) ([]db.SessionParticipant, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.SessionParticipant), args.Error(1)
+}
+
+func (m *MockDBManager) SetSummonParticipants(ctx context.Context, chatID int64, enabled bool) error {
+	args := m.Called(ctx, chatID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SummonParticipantsEnabled(ctx context.Context, chatID int64) (bool, error) {
+	args := m.Called(ctx, chatID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetTranscriptMode(ctx context.Context, chatID int64, mode string) error {
+	args := m.Called(ctx, chatID, mode)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetTranscriptMode(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetUserLanguage(ctx context.Context, userID int64, language string) error {
+	args := m.Called(ctx, userID, language)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetUserLanguage(ctx context.Context, userID int64) (string, error) {
+	m.expectsLanguage("GetUserLanguage")
+	args := m.Called(ctx, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetTaskDefaults(ctx context.Context, chatID int64, defaults string) error {
+	args := m.Called(ctx, chatID, defaults)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetTaskDefaults(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetChatPrompt(ctx context.Context, chatID int64, name, text string) error {
+	args := m.Called(ctx, chatID, name, text)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetChatPrompt(ctx context.Context, chatID int64, name string) (string, error) {
+	args := m.Called(ctx, chatID, name)
+	return args.String(0), args.Error(1)
+}
+
 func (m *MockDBManager) SaveDraftTask(ctx context.Context, input db.DraftTaskInput) error {
 	args := m.Called(ctx, input)
 	return args.Error(0)
@@ -123,11 +249,47 @@ func (m *MockDBManager) DeleteDraftTask(ctx context.Context, sessionID int) erro
 	return args.Error(0)
 }
 
-func (m *MockDBManager) SaveCreatedTask(ctx context.Context, task db.DraftTask, todoistTaskID, url string) error {
-	args := m.Called(ctx, task, todoistTaskID, url)
+func (m *MockDBManager) SaveDraftAlternatives(ctx context.Context, sessionID int, drafts []db.DraftTaskInput) error {
+	args := m.Called(ctx, sessionID, drafts)
 	return args.Error(0)
 }
 
+func (m *MockDBManager) GetDraftAlternative(ctx context.Context, sessionID, option int) (db.DraftTaskInput, error) {
+	args := m.Called(ctx, sessionID, option)
+	return args.Get(0).(db.DraftTaskInput), args.Error(1)
+}
+
+func (m *MockDBManager) AddDraftRevision(ctx context.Context, draft db.DraftTaskInput, instruction string) (int, error) {
+	args := m.Called(ctx, draft, instruction)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDBManager) ListDraftRevisions(ctx context.Context, sessionID int) ([]db.DraftRevision, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.DraftRevision), args.Error(1)
+}
+
+func (m *MockDBManager) DeleteDraftRevisions(ctx context.Context, sessionID, fromRevision int) error {
+	args := m.Called(ctx, sessionID, fromRevision)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SaveCreatedTask(ctx context.Context, task db.DraftTask, todoistTaskID, url string) (db.CreatedTask, bool, error) {
+	args := m.Called(ctx, task, todoistTaskID, url)
+	return args.Get(0).(db.CreatedTask), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDBManager) GetCreatedTask(ctx context.Context, sessionID int) (*db.CreatedTask, error) {
+	args := m.Called(ctx, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*db.CreatedTask), args.Error(1)
+}
+
 func (m *MockDBManager) ReplaceAssigneeMappings(ctx context.Context, chatID int64, projectID string, mappings []db.AssigneeMapping) error {
 	args := m.Called(ctx, chatID, projectID, mappings)
 	return args.Error(0)
@@ -141,6 +303,282 @@ func (m *MockDBManager) GetAssigneeMappings(ctx context.Context, chatID int64, p
 	return nil, args.Error(1)
 }
 
+func (m *MockDBManager) SaveAssigneeMapping(ctx context.Context, mapping db.AssigneeMapping) error {
+	args := m.Called(ctx, mapping)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) ReserveTaskAnalysis(ctx context.Context, chatID, userID int64, sessionID int, since time.Time, chatLimit, userLimit int) (int, int, int, error) {
+	args := m.Called(ctx, chatID, userID, sessionID, since, chatLimit, userLimit)
+	return args.Int(0), args.Int(1), args.Int(2), args.Error(3)
+}
+
+func (m *MockDBManager) ReleaseTaskAnalysis(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) CountTaskAnalyses(ctx context.Context, chatID, userID int64, since time.Time) (int, int, error) {
+	args := m.Called(ctx, chatID, userID, since)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockDBManager) SetQuotaExempt(ctx context.Context, chatID int64, exempt bool) error {
+	args := m.Called(ctx, chatID, exempt)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) IsQuotaExempt(ctx context.Context, chatID int64) (bool, error) {
+	args := m.Called(ctx, chatID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetVoicePreviews(ctx context.Context, chatID int64, enabled bool) error {
+	args := m.Called(ctx, chatID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) VoicePreviewsEnabled(ctx context.Context, chatID int64) (bool, error) {
+	args := m.Called(ctx, chatID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetReactionAck(ctx context.Context, chatID int64, enabled bool) error {
+	args := m.Called(ctx, chatID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) ReactionAckEnabled(ctx context.Context, chatID int64) (bool, error) {
+	args := m.Called(ctx, chatID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetChatInactive(ctx context.Context, chatID int64, inactive bool) error {
+	args := m.Called(ctx, chatID, inactive)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) MigrateChat(ctx context.Context, fromChatID, toChatID int64) error {
+	args := m.Called(ctx, fromChatID, toChatID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SetQuietHours(ctx context.Context, chatID int64, window string) error {
+	args := m.Called(ctx, chatID, window)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetQuietHours(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetPriorityNames(ctx context.Context, chatID int64, names string) error {
+	args := m.Called(ctx, chatID, names)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetPriorityNames(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) AddChatNotifier(ctx context.Context, chatID int64, kind, target, secret string) (int, error) {
+	args := m.Called(ctx, chatID, kind, target, secret)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDBManager) ListChatNotifiers(ctx context.Context, chatID int64) ([]db.ChatNotifier, error) {
+	args := m.Called(ctx, chatID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]db.ChatNotifier), args.Error(1)
+}
+
+func (m *MockDBManager) RemoveChatNotifier(ctx context.Context, chatID int64, id int) (bool, error) {
+	args := m.Called(ctx, chatID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockDBManager) SavePendingEdit(ctx context.Context, chatID int64, messageID int, sessionID int) error {
+	args := m.Called(ctx, chatID, messageID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetPendingEdit(ctx context.Context, chatID int64, messageID int) (int, error) {
+	args := m.Called(ctx, chatID, messageID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDBManager) DeletePendingEdit(ctx context.Context, chatID int64, messageID int) error {
+	args := m.Called(ctx, chatID, messageID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) DeleteStalePendingEdits(ctx context.Context, createdBefore time.Time) (int64, error) {
+	args := m.Called(ctx, createdBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDBManager) DeferMessage(ctx context.Context, chatID int64, text string) error {
+	args := m.Called(ctx, chatID, text)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) ListDeferredChats(ctx context.Context) ([]int64, error) {
+	args := m.Called(ctx)
+	if v := args.Get(0); v != nil {
+		return v.([]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) TakeDeferredMessages(ctx context.Context, chatID int64) ([]string, error) {
+	args := m.Called(ctx, chatID)
+	if v := args.Get(0); v != nil {
+		return v.([]string), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) SaveAIJob(ctx context.Context, job db.AIJob) (int, error) {
+	args := m.Called(ctx, job)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDBManager) DeleteAIJob(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) ListAIJobs(ctx context.Context) ([]db.AIJob, error) {
+	args := m.Called(ctx)
+	if v := args.Get(0); v != nil {
+		return v.([]db.AIJob), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) ListTasksToNudge(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]db.TaskNudge, error) {
+	args := m.Called(ctx, createdAfter, createdBefore, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]db.TaskNudge), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) MarkTaskNudged(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetChatCreatedTask(ctx context.Context, chatID int64, id int) (*db.CreatedTask, error) {
+	args := m.Called(ctx, chatID, id)
+	if v := args.Get(0); v != nil {
+		return v.(*db.CreatedTask), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) ListTaskChats(ctx context.Context, todoistTaskID string) ([]int64, error) {
+	args := m.Called(ctx, todoistTaskID)
+	if v := args.Get(0); v != nil {
+		return v.([]int64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) SetCreatedTaskAssignee(ctx context.Context, id int, assignee db.AssigneeSnapshot) error {
+	args := m.Called(ctx, id, assignee)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SaveDecisionSummary(ctx context.Context, sessionID int, chatID int64, text string) error {
+	args := m.Called(ctx, sessionID, chatID, text)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) SetSessionNotice(ctx context.Context, sessionID int, messageID int) error {
+	args := m.Called(ctx, sessionID, messageID)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetSessionNotice(ctx context.Context, sessionID int) (int, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetDigestTime(ctx context.Context, chatID int64, clock string) error {
+	args := m.Called(ctx, chatID, clock)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetDigestTime(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetChatTimezone(ctx context.Context, chatID int64, zone string) error {
+	args := m.Called(ctx, chatID, zone)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetChatTimezone(ctx context.Context, chatID int64) (string, error) {
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) SetChatLanguage(ctx context.Context, chatID int64, language string) error {
+	args := m.Called(ctx, chatID, language)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetChatLanguage(ctx context.Context, chatID int64) (string, error) {
+	m.expectsLanguage("GetChatLanguage")
+	args := m.Called(ctx, chatID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockDBManager) MessageActivity(ctx context.Context, chatID int64, since time.Time, timezone string) ([]activity.Bucket, error) {
+	args := m.Called(ctx, chatID, since, timezone)
+	if v := args.Get(0); v != nil {
+		return v.([]activity.Bucket), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) RecordPreviewMessage(ctx context.Context, sessionID int, chatID int64, messageID int, text string) error {
+	args := m.Called(ctx, sessionID, chatID, messageID, text)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) ListStalePreviews(ctx context.Context, createdBefore time.Time, limit int) ([]db.PreviewMessage, error) {
+	args := m.Called(ctx, createdBefore, limit)
+	if v := args.Get(0); v != nil {
+		return v.([]db.PreviewMessage), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) MarkPreviewCleaned(ctx context.Context, id int) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockDBManager) GetAnalysisCache(ctx context.Context, sessionID int, hash string, since time.Time) ([]byte, error) {
+	args := m.Called(ctx, sessionID, hash, since)
+	if v := args.Get(0); v != nil {
+		return v.([]byte), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockDBManager) SaveAnalysisCache(ctx context.Context, sessionID int, hash string, payload []byte) error {
+	args := m.Called(ctx, sessionID, hash, payload)
+	return args.Error(0)
+}
+
 // Helper functions for fluent API style mock configuration
 func ConfigureMockDB(m *MockDBManager) *MockDBHelper {
 	return &MockDBHelper{mock: m}
@@ -165,13 +603,13 @@ func (h *MockDBHelper) WithSetProjectID(chatID int64, projectID string, err erro
 
 // WithActiveSession sets up the mock to expect and respond to HasActiveSession calls
 func (h *MockDBHelper) WithActiveSession(chatID int64, hasActive bool, err error) *MockDBHelper {
-	h.mock.On("HasActiveSession", mock.Anything, chatID).Return(hasActive, err)
+	h.mock.On("HasActiveSession", mock.Anything, chatID, 0).Return(hasActive, err)
 	return h
 }
 
 // WithStartSession sets up the mock to expect and respond to StartSession calls
 func (h *MockDBHelper) WithStartSession(chatID int64, ownerID int64, sessionID int, err error) *MockDBHelper {
-	h.mock.On("StartSession", mock.Anything, chatID, ownerID).Return(sessionID, err)
+	h.mock.On("StartSession", mock.Anything, chatID, 0, "", ownerID).Return(sessionID, err)
 	return h
 }
 
@@ -182,8 +620,8 @@ func (h *MockDBHelper) WithIsSessionOwner(sessionID int, userID int64, isOwner b
 }
 
 // WithCloseSession sets up the mock to expect and respond to CloseSession calls
-func (h *MockDBHelper) WithCloseSession(chatID int64, err error) *MockDBHelper {
-	h.mock.On("CloseSession", mock.Anything, chatID).Return(err)
+func (h *MockDBHelper) WithCloseSession(sessionID int, err error) *MockDBHelper {
+	h.mock.On("CloseSession", mock.Anything, sessionID).Return(err)
 	return h
 }
 
@@ -230,6 +668,11 @@ func (m *AIClientMock) AnalyzeAssignee(ctx context.Context, messages []string, a
 	return nil, args.Error(1)
 }
 
+func (m *AIClientMock) RunPrompt(ctx context.Context, name string, input string) (string, error) {
+	args := m.Called(ctx, name, input)
+	return args.String(0), args.Error(1)
+}
+
 type AIClientMockMockHelper struct {
 	m *AIClientMock
 }
@@ -258,6 +701,22 @@ func (m *MockTodoistClient) GetProjects(ctx context.Context) ([]todoist.Project,
 	return nil, args.Error(1)
 }
 
+func (m *MockTodoistClient) GetLabels(ctx context.Context) ([]todoist.Label, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]todoist.Label), args.Error(1)
+}
+
+func (m *MockTodoistClient) CreateLabel(ctx context.Context, name string) (*todoist.Label, error) {
+	args := m.Called(ctx, name)
+	if v := args.Get(0); v != nil {
+		return v.(*todoist.Label), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockTodoistClient) GetProjectCollaborators(ctx context.Context, projectID string) ([]todoist.Collaborator, error) {
 	args := m.Called(ctx, projectID)
 	if v := args.Get(0); v != nil {
@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/timezone"
+)
+
+// TimezoneCommand sets the time zone the chat's due dates, quiet hours and
+// digest time are read and shown in
+type TimezoneCommand struct {
+	dbManager DBManager
+	now       func() time.Time
+}
+
+func NewTimezoneCommand(dbManager DBManager) *TimezoneCommand {
+	return &TimezoneCommand{dbManager: dbManager, now: time.Now}
+}
+
+func (c *TimezoneCommand) Name() string {
+	return "timezone"
+}
+
+func (c *TimezoneCommand) Description() string {
+	return "Часовой пояс чата для сроков, тихих часов и сводок: /timezone Europe/Berlin или reset"
+}
+
+func (c *TimezoneCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	usage := i18n.T(lang, i18n.TimezoneUsage)
+
+	args := strings.Fields(message.CommandArguments())
+	switch {
+	case len(args) == 0:
+		stored, err := c.dbManager.GetChatTimezone(ctx, chatID)
+		if err != nil {
+			log.Printf("Error getting timezone for chat %d: %v", chatID, err)
+		}
+		text := fmt.Sprintf(i18n.T(lang, i18n.TimezoneDefault), timezone.Label(timezone.Default(), c.now()))
+		if stored != "" {
+			text = fmt.Sprintf(i18n.T(lang, i18n.TimezoneCurrent), timezone.Label(timezone.ForChat(stored), c.now()))
+		}
+		text += "\n\n" + usage
+		msg := tgbotapi.NewMessage(chatID, text)
+		return &msg
+	case len(args) == 1 && args[0] == "reset":
+		return c.set(ctx, chatID, lang, "", fmt.Sprintf(i18n.T(lang, i18n.TimezoneReset), timezone.Label(timezone.Default(), c.now())))
+	case len(args) == 1:
+		loc, err := timezone.Load(args[0])
+		if err != nil {
+			msg := tgbotapi.NewMessage(chatID, fmt.Sprintf(i18n.T(lang, i18n.TimezoneUnknown), args[0]))
+			return &msg
+		}
+		return c.set(ctx, chatID, lang, loc.String(), fmt.Sprintf(i18n.T(lang, i18n.TimezoneSet), timezone.Label(loc, c.now())))
+	default:
+		msg := tgbotapi.NewMessage(chatID, usage)
+		return &msg
+	}
+}
+
+func (c *TimezoneCommand) set(ctx context.Context, chatID int64, lang i18n.Lang, zone, text string) *tgbotapi.MessageConfig {
+	if err := c.dbManager.SetChatTimezone(ctx, chatID, zone); err != nil {
+		log.Printf("Error setting timezone for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, text)
+	return &msg
+}
+
+// ChatLocation returns the time zone of a chat; errors fall back to the default
+func ChatLocation(ctx context.Context, dbManager DBManager, chatID int64) *time.Location {
+	stored, err := dbManager.GetChatTimezone(ctx, chatID)
+	if err != nil {
+		log.Printf("Error getting timezone for chat %d: %v", chatID, err)
+		return timezone.Default()
+	}
+	return timezone.ForChat(stored)
+}
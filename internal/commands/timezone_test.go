@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTimezoneCommand_Execute(t *testing.T) {
+	chatID := int64(123456789)
+
+	t.Run("shows default zone", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("GetChatTimezone", mock.Anything, chatID).Return("", nil)
+
+		response := NewTimezoneCommand(mockDB).Execute(CreateCommandMessage(chatID, "/timezone"))
+
+		assert.Contains(t, response.Text, "Europe/Moscow")
+		assert.Contains(t, response.Text, "по умолчанию")
+	})
+
+	t.Run("stores valid zone", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetChatTimezone", mock.Anything, chatID, "Asia/Tokyo").Return(nil)
+
+		response := NewTimezoneCommand(mockDB).Execute(CreateCommandMessage(chatID, "/timezone", "Asia/Tokyo"))
+
+		assert.Contains(t, response.Text, "Asia/Tokyo, UTC+09:00")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("resets to default", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+		mockDB.On("SetChatTimezone", mock.Anything, chatID, "").Return(nil)
+
+		response := NewTimezoneCommand(mockDB).Execute(CreateCommandMessage(chatID, "/timezone", "reset"))
+
+		assert.Contains(t, response.Text, "сброшен")
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown zone", func(t *testing.T) {
+		mockDB := new(MockDBManager)
+
+		response := NewTimezoneCommand(mockDB).Execute(CreateCommandMessage(chatID, "/timezone", "Mars/Olympus"))
+
+		assert.Contains(t, response.Text, "Не знаю")
+		mockDB.AssertNotCalled(t, "SetChatTimezone", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
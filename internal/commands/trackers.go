@@ -0,0 +1,9 @@
+package commands
+
+import "github.com/user/telegram-bot/internal/tracker"
+
+// TrackerAware is implemented by commands that reach the task tracker of a
+// chat through a tracker.Selector; by default they use Todoist for every chat
+type TrackerAware interface {
+	SetTrackers(trackers *tracker.Selector)
+}
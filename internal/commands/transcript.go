@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/db"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// maxDescriptionTranscript keeps the transcript appended to a task description
+// well below the tracker's description limit
+const maxDescriptionTranscript = 8000
+
+// TranscriptCommand chooses where the discussion transcript goes when a task is created
+type TranscriptCommand struct {
+	dbManager DBManager
+}
+
+func NewTranscriptCommand(dbManager DBManager) *TranscriptCommand {
+	return &TranscriptCommand{dbManager: dbManager}
+}
+
+func (c *TranscriptCommand) Name() string {
+	return "transcript"
+}
+
+func (c *TranscriptCommand) Description() string {
+	return "Переписка в созданной задаче: /transcript off|description|comment"
+}
+
+func (c *TranscriptCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx := context.Background()
+	chatID := message.Chat.ID
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+
+	arg := strings.ToLower(strings.TrimSpace(message.CommandArguments()))
+	if arg == "" {
+		mode, err := c.dbManager.GetTranscriptMode(ctx, chatID)
+		if err != nil {
+			log.Printf("Error getting transcript mode for chat %d: %v", chatID, err)
+			msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingLoadFailed))
+			return &msg
+		}
+		msg := tgbotapi.NewMessage(chatID, transcriptModeText(mode, lang)+"\n\n"+i18n.T(lang, i18n.TranscriptChange))
+		return &msg
+	}
+
+	var mode string
+	switch arg {
+	case "off":
+		mode = db.TranscriptOff
+	case db.TranscriptDescription, db.TranscriptComment:
+		mode = arg
+	default:
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.TranscriptUsage))
+		return &msg
+	}
+
+	if err := c.dbManager.SetTranscriptMode(ctx, chatID, mode); err != nil {
+		log.Printf("Error setting transcript mode for chat %d: %v", chatID, err)
+		msg := tgbotapi.NewMessage(chatID, i18n.T(lang, i18n.SettingSaveFailed))
+		return &msg
+	}
+	msg := tgbotapi.NewMessage(chatID, transcriptModeText(mode, lang))
+	return &msg
+}
+
+func transcriptModeText(mode string, lang i18n.Lang) string {
+	switch mode {
+	case db.TranscriptDescription:
+		return i18n.T(lang, i18n.TranscriptModeDescription)
+	case db.TranscriptComment:
+		return i18n.T(lang, i18n.TranscriptModeComment)
+	default:
+		return i18n.T(lang, i18n.TranscriptModeOff)
+	}
+}
+
+// FormatTranscript renders the saved messages of a discussion as lines like
+// "[15.10 14:03] @ivan: text" in the chat time zone loc
+func FormatTranscript(messages []db.Message, loc *time.Location) []string {
+	lines := make([]string, 0, len(messages))
+	for _, msg := range messages {
+		if msg.Text == "" {
+			continue
+		}
+		author := "участник"
+		if msg.Username.Valid && msg.Username.String != "" {
+			author = "@" + msg.Username.String
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s: %s", msg.Timestamp.In(loc).Format("02.01 15:04"), author, msg.Text))
+	}
+	return lines
+}
+
+// TruncateTranscript joins whole transcript lines while they fit limit runes
+// and notes how many were left out
+func TruncateTranscript(lines []string, limit int) string {
+	var sb strings.Builder
+	size := 0
+	for i, line := range lines {
+		width := utf8.RuneCountInString(line) + 1
+		if size+width > limit {
+			fmt.Fprintf(&sb, "… и ещё сообщений: %d", len(lines)-i)
+			break
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+		size += width
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// AppendTranscript adds the discussion transcript to a task description
+func AppendTranscript(description string, lines []string) string {
+	if len(lines) == 0 {
+		return description
+	}
+	appendix := "## Переписка обсуждения\n" + TruncateTranscript(lines, maxDescriptionTranscript)
+	if strings.TrimSpace(description) == "" {
+		return appendix
+	}
+	return description + "\n\n" + appendix
+}
@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/user/telegram-bot/internal/db"
+)
+
+func TestFormatTranscript(t *testing.T) {
+	messages := []db.Message{
+		{Username: sql.NullString{String: "ivan", Valid: true}, Text: "логин падает", Timestamp: time.Date(2026, 10, 15, 11, 3, 0, 0, time.UTC)},
+		{Text: ""},
+		{Text: "[photo]", Timestamp: time.Date(2026, 10, 15, 11, 5, 0, 0, time.UTC)},
+	}
+
+	lines := FormatTranscript(messages, time.FixedZone("MSK", 3*60*60))
+
+	assert.Equal(t, []string{
+		"[15.10 14:03] @ivan: логин падает",
+		"[15.10 14:05] участник: [photo]",
+	}, lines)
+}
+
+func TestTruncateTranscript(t *testing.T) {
+	lines := []string{"first", "second", "third"}
+
+	assert.Equal(t, "first\nsecond\nthird", TruncateTranscript(lines, 100))
+	assert.Equal(t, "first\n… и ещё сообщений: 2", TruncateTranscript(lines, 8))
+	assert.Equal(t, "Описание", AppendTranscript("Описание", nil))
+	assert.True(t, strings.HasPrefix(AppendTranscript("", lines), "## Переписка обсуждения\nfirst"))
+}
+
+func TestTranscriptCommand(t *testing.T) {
+	chatID := int64(123456789)
+	mockDB := new(MockDBManager)
+	mockDB.On("SetTranscriptMode", mock.Anything, chatID, db.TranscriptComment).Return(nil)
+	mockDB.On("GetTranscriptMode", mock.Anything, chatID).Return(db.TranscriptOff, nil)
+
+	cmd := NewTranscriptCommand(mockDB)
+
+	response := cmd.Execute(CreateCommandMessage(chatID, "/transcript", "comment"))
+	assert.Contains(t, response.Text, "комментариями")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/transcript"))
+	assert.Contains(t, response.Text, "не попадает")
+
+	response = cmd.Execute(CreateCommandMessage(chatID, "/transcript", "everywhere"))
+	assert.Contains(t, response.Text, "Использование")
+
+	mockDB.AssertExpectations(t)
+}
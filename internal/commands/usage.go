@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/timezone"
+)
+
+const (
+	usageTimeout = 10 * time.Second
+	// maxUsageChats keeps the report within one Telegram message
+	maxUsageChats = 30
+)
+
+// AIUsageReporter is the part of the database that sums recorded AI token usage
+type AIUsageReporter interface {
+	AIUsageByChat(ctx context.Context, since time.Time) ([]ai.ChatUsage, error)
+}
+
+// UsageCommand shows the AI tokens each chat spent this month and their estimated cost
+type UsageCommand struct {
+	reporter  AIUsageReporter
+	dbManager DBManager
+	prices    ai.TokenPrices
+	admins    admin.Users
+	now       func() time.Time
+}
+
+func NewUsageCommand(reporter AIUsageReporter, dbManager DBManager, prices ai.TokenPrices, admins admin.Users) *UsageCommand {
+	return &UsageCommand{reporter: reporter, dbManager: dbManager, prices: prices, admins: admins, now: time.Now}
+}
+
+func (c *UsageCommand) Name() string {
+	return "usage"
+}
+
+func (c *UsageCommand) Description() string {
+	return "Расход AI-токенов по чатам за месяц и его примерная стоимость (для администраторов)"
+}
+
+func (c *UsageCommand) MenuScope() MenuScope {
+	return MenuAdmins
+}
+
+func (c *UsageCommand) Execute(message *tgbotapi.Message) *tgbotapi.MessageConfig {
+	ctx, cancel := context.WithTimeout(context.Background(), usageTimeout)
+	defer cancel()
+
+	lang := ReplyLanguage(ctx, c.dbManager, message)
+	if message.From == nil || !c.admins.Contains(message.From.ID) {
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.AdminOnlyCommand))
+		return &msg
+	}
+
+	now := c.now().In(timezone.Default())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	usage, err := c.reporter.AIUsageByChat(ctx, monthStart)
+	if err != nil {
+		log.Printf("Error getting AI usage: %v", err)
+		msg := tgbotapi.NewMessage(message.Chat.ID, i18n.T(lang, i18n.UsageFailed))
+		return &msg
+	}
+
+	msg := tgbotapi.NewMessage(message.Chat.ID, FormatUsageReport(usage, c.prices, monthStart, lang))
+	return &msg
+}
+
+// chatUsageTotal is the usage of one chat over all models
+type chatUsageTotal struct {
+	chatID     int64
+	requests   int
+	prompt     int64
+	completion int64
+	cost       float64
+}
+
+// FormatUsageReport lists the chats by tokens spent since monthStart with the
+// cost of the models that have a price
+func FormatUsageReport(usage []ai.ChatUsage, prices ai.TokenPrices, monthStart time.Time, lang i18n.Lang) string {
+	header := fmt.Sprintf(i18n.T(lang, i18n.UsageHeader), monthStart.Format("02.01.2006"))
+	if len(usage) == 0 {
+		return header + "\n\n" + i18n.T(lang, i18n.UsageNone)
+	}
+
+	byChat := make(map[int64]*chatUsageTotal)
+	var chats []*chatUsageTotal
+	var total chatUsageTotal
+	unpriced := make(map[string]struct{})
+	for _, u := range usage {
+		chat, ok := byChat[u.ChatID]
+		if !ok {
+			chat = &chatUsageTotal{chatID: u.ChatID}
+			byChat[u.ChatID] = chat
+			chats = append(chats, chat)
+		}
+		cost, priced := prices.Cost(u.Model, u.PromptTokens, u.CompletionTokens)
+		if !priced {
+			unpriced[u.Model] = struct{}{}
+		}
+		for _, t := range []*chatUsageTotal{chat, &total} {
+			t.requests += u.Requests
+			t.prompt += u.PromptTokens
+			t.completion += u.CompletionTokens
+			t.cost += cost
+		}
+	}
+	sort.SliceStable(chats, func(i, j int) bool {
+		return chats[i].prompt+chats[i].completion > chats[j].prompt+chats[j].completion
+	})
+
+	var sb strings.Builder
+	sb.WriteString(header + "\n\n")
+	for i, chat := range chats {
+		if i == maxUsageChats {
+			fmt.Fprintf(&sb, i18n.T(lang, i18n.UsageMoreChats)+"\n", len(chats)-maxUsageChats)
+			break
+		}
+		name := fmt.Sprintf(i18n.T(lang, i18n.UsageChat), chat.chatID)
+		if chat.chatID == 0 {
+			name = i18n.T(lang, i18n.UsageNoChat)
+		}
+		fmt.Fprintf(&sb, "• %s: %s\n", name, formatUsageTotal(chat, lang))
+	}
+	fmt.Fprintf(&sb, "\n"+i18n.T(lang, i18n.UsageTotal), formatUsageTotal(&total, lang))
+
+	if len(unpriced) > 0 {
+		models := make([]string, 0, len(unpriced))
+		for model := range unpriced {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+		fmt.Fprintf(&sb, "\n\n"+i18n.T(lang, i18n.UsageUnpriced), ai.EnvTokenPrices, strings.Join(models, ", "))
+	}
+	return sb.String()
+}
+
+func formatUsageTotal(t *chatUsageTotal, lang i18n.Lang) string {
+	return fmt.Sprintf(i18n.T(lang, i18n.UsageLine),
+		t.requests, t.prompt+t.completion, t.prompt, t.completion, t.cost)
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/user/telegram-bot/internal/admin"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+type fakeUsageReporter struct {
+	usage []ai.ChatUsage
+	since time.Time
+}
+
+func (f *fakeUsageReporter) AIUsageByChat(ctx context.Context, since time.Time) ([]ai.ChatUsage, error) {
+	f.since = since
+	return f.usage, nil
+}
+
+func TestUsageCommand_Execute(t *testing.T) {
+	admins, err := admin.ParseUsers("42")
+	assert.NoError(t, err)
+	reporter := &fakeUsageReporter{usage: []ai.ChatUsage{
+		{ChatID: -1, Model: "gpt-4o-mini", Requests: 2, PromptTokens: 1000, CompletionTokens: 200},
+		{ChatID: -2, Model: "gpt-4o-mini", Requests: 5, PromptTokens: 2_000_000, CompletionTokens: 1_000_000},
+		{ChatID: -2, Model: "qwen2.5:7b", Requests: 1, PromptTokens: 500, CompletionTokens: 100},
+	}}
+	prices, err := ai.ParseTokenPrices("gpt-4o-mini=0.15/0.6")
+	assert.NoError(t, err)
+	cmd := NewUsageCommand(reporter, new(MockDBManager), prices, admins)
+	cmd.now = func() time.Time { return time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC) }
+
+	response := cmd.Execute(CreateCommandMessage(100, "/usage"))
+	assert.Contains(t, response.Text, "только администраторам")
+
+	response = cmd.Execute(CreateCommandMessage(42, "/usage"))
+
+	assert.Equal(t, "2026-10-01", reporter.since.Format("2006-01-02"))
+	assert.Contains(t, response.Text, "с 01.10.2026")
+	assert.Contains(t, response.Text, "• Чат -2: 6 запр., 3000600 токенов (вход 2000500, выход 1000100) ≈ $0.90")
+	assert.Less(t, strings.Index(response.Text, "Чат -2"), strings.Index(response.Text, "Чат -1"), "the chat with most tokens goes first")
+	assert.Contains(t, response.Text, "Итого: 8 запр.")
+	assert.Contains(t, response.Text, "в стоимость не вошли: qwen2.5:7b")
+}
+
+func TestFormatUsageReport_Empty(t *testing.T) {
+	text := FormatUsageReport(nil, nil, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), i18n.Russian)
+
+	assert.Contains(t, text, "Запросов к AI в этом месяце не было")
+}
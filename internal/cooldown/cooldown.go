@@ -0,0 +1,143 @@
+// Package cooldown limits how often expensive commands can run in a chat.
+package cooldown
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+// EnvCooldowns overrides per-command cooldowns, e.g. "create_task=30s,backup=1h".
+// A zero duration removes the cooldown of a command.
+const EnvCooldowns = "COMMAND_COOLDOWNS"
+
+// DefaultRules protects the AI and Todoist quotas from repeated expensive commands
+func DefaultRules() map[string]time.Duration {
+	return map[string]time.Duration{
+		"create_task": 30 * time.Second,
+		"summary":     time.Minute,
+		"backup":      10 * time.Minute,
+	}
+}
+
+// ParseRules reads "command=duration" pairs separated by commas
+func ParseRules(raw string) (map[string]time.Duration, error) {
+	rules := make(map[string]time.Duration)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid cooldown %q: expected command=duration", part)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid cooldown for %q: %w", name, err)
+		}
+		if duration < 0 {
+			return nil, fmt.Errorf("invalid cooldown for %q: must not be negative", name)
+		}
+		rules[strings.TrimPrefix(strings.TrimSpace(name), "/")] = duration
+	}
+	return rules, nil
+}
+
+// RulesFromEnv returns DefaultRules with COMMAND_COOLDOWNS applied on top
+func RulesFromEnv() (map[string]time.Duration, error) {
+	rules := DefaultRules()
+	overrides, err := ParseRules(os.Getenv(EnvCooldowns))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", EnvCooldowns, err)
+	}
+	for name, duration := range overrides {
+		if duration == 0 {
+			delete(rules, name)
+			continue
+		}
+		rules[name] = duration
+	}
+	return rules, nil
+}
+
+type key struct {
+	command string
+	chatID  int64
+}
+
+// Limiter remembers when each command last ran in each chat
+type Limiter struct {
+	rules map[string]time.Duration
+	now   func() time.Time
+
+	mu   sync.Mutex
+	last map[key]time.Time
+}
+
+// NewLimiter creates a limiter with the given per-command cooldowns
+func NewLimiter(rules map[string]time.Duration) *Limiter {
+	return &Limiter{rules: rules, now: time.Now, last: make(map[key]time.Time)}
+}
+
+// Allow reports whether the command may run in the chat now and records the
+// run if so. Otherwise it returns how long to wait.
+func (l *Limiter) Allow(command string, chatID int64) (time.Duration, bool) {
+	cooldown, ok := l.rules[command]
+	if !ok || cooldown <= 0 {
+		return 0, true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	k := key{command: command, chatID: chatID}
+	if last, ok := l.last[k]; ok {
+		if wait := last.Add(cooldown).Sub(now); wait > 0 {
+			return wait, false
+		}
+	}
+	l.last[k] = now
+	l.prune(now)
+	return 0, true
+}
+
+// Cooldown returns the configured cooldown of a command
+func (l *Limiter) Cooldown(command string) time.Duration {
+	return l.rules[command]
+}
+
+// prune drops entries whose cooldown is over so the map does not grow forever
+func (l *Limiter) prune(now time.Time) {
+	if len(l.last) < 1024 {
+		return
+	}
+	for k, last := range l.last {
+		if now.Sub(last) >= l.rules[k.command] {
+			delete(l.last, k)
+		}
+	}
+}
+
+// FormatWait formats a duration for users in lang, rounding up
+func FormatWait(d time.Duration, lang i18n.Lang) string {
+	seconds := int((d + time.Second - 1) / time.Second)
+	switch {
+	case seconds < 60:
+		return fmt.Sprintf(i18n.T(lang, i18n.WaitSeconds), seconds)
+	case seconds < 3600:
+		return fmt.Sprintf(i18n.T(lang, i18n.WaitMinutes), (seconds+59)/60)
+	default:
+		hours := seconds / 3600
+		minutes := (seconds%3600 + 59) / 60
+		if minutes == 0 {
+			return fmt.Sprintf(i18n.T(lang, i18n.WaitHours), hours)
+		}
+		return fmt.Sprintf(i18n.T(lang, i18n.WaitHoursMinutes), hours, minutes)
+	}
+}
@@ -0,0 +1,72 @@
+package cooldown
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/telegram-bot/internal/i18n"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	limiter := NewLimiter(map[string]time.Duration{"create_task": 30 * time.Second})
+	limiter.now = func() time.Time { return now }
+
+	if _, ok := limiter.Allow("create_task", 1); !ok {
+		t.Fatal("expected first run to be allowed")
+	}
+
+	now = now.Add(10 * time.Second)
+	wait, ok := limiter.Allow("create_task", 1)
+	if ok || wait != 20*time.Second {
+		t.Fatalf("expected 20s wait, got %v (ok=%v)", wait, ok)
+	}
+	if _, ok := limiter.Allow("create_task", 2); !ok {
+		t.Fatal("expected other chats not to share the cooldown")
+	}
+	if _, ok := limiter.Allow("list", 1); !ok {
+		t.Fatal("expected commands without a rule to be allowed")
+	}
+
+	now = now.Add(20 * time.Second)
+	if _, ok := limiter.Allow("create_task", 1); !ok {
+		t.Fatal("expected run after the cooldown to be allowed")
+	}
+}
+
+func TestRulesFromEnv(t *testing.T) {
+	t.Setenv(EnvCooldowns, "create_task=0, /summary=2m")
+
+	rules, err := RulesFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := rules["create_task"]; ok {
+		t.Error("expected zero duration to remove the cooldown")
+	}
+	if rules["summary"] != 2*time.Minute || rules["backup"] != 10*time.Minute {
+		t.Errorf("unexpected rules: %v", rules)
+	}
+}
+
+func TestParseRules_Invalid(t *testing.T) {
+	for _, raw := range []string{"create_task", "create_task=soon", "create_task=-1s"} {
+		if _, err := ParseRules(raw); err == nil {
+			t.Errorf("ParseRules(%q): expected error", raw)
+		}
+	}
+}
+
+func TestFormatWait(t *testing.T) {
+	cases := map[time.Duration]string{
+		1500 * time.Millisecond: "2 сек.",
+		90 * time.Second:        "2 мин.",
+		time.Hour:               "1 ч.",
+		time.Hour + time.Second: "1 ч. 1 мин.",
+	}
+	for d, want := range cases {
+		if got := FormatWait(d, i18n.Russian); got != want {
+			t.Errorf("FormatWait(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
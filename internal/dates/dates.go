@@ -0,0 +1,409 @@
+// Package dates turns the due dates people write in Russian and English
+// ("завтра", "через 3 дня", "in 2 weeks", "31 декабря", "пятница вечером")
+// into a calendar date, optionally with a time of day. Relative expressions
+// are resolved against a given now and in its location.
+package dates
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of a due date without a time
+	DateLayout = "2006-01-02"
+	// DateTimeLayout is the layout of a due date with a time
+	DateTimeLayout = time.RFC3339
+)
+
+// Due is a parsed due date. Without a time At is the midnight of the day.
+type Due struct {
+	At      time.Time
+	HasTime bool
+}
+
+// String formats the due as YYYY-MM-DD, or as RFC 3339 when it has a time
+func (d Due) String() string {
+	if d.HasTime {
+		return d.At.Format(DateTimeLayout)
+	}
+	return d.At.Format(DateLayout)
+}
+
+// Date returns the day of the due as YYYY-MM-DD
+func (d Due) Date() string {
+	return d.At.Format(DateLayout)
+}
+
+var (
+	spacesRe      = regexp.MustCompile(`\s+`)
+	isoDateTimeRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})(?:[t ](\d{1,2}:\d{2})(?::\d{2})?)?$`)
+	dotDateRe     = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})(?:\.(\d{2}|\d{4}))?$`)
+	dayMonthRe    = regexp.MustCompile(`^(\d{1,2})(?:-?(?:е|го))?\s+([\p{L}]+)\.?(?:\s+(\d{4}))?$`)
+	monthDayRe    = regexp.MustCompile(`^([\p{L}]+)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?$`)
+	inRe          = regexp.MustCompile(`^(?:через|in)\s+(?:(\d+|[\p{L}]+)\s+)?([\p{L}]+)$`)
+	clockRe       = regexp.MustCompile(`^(?:(?:в|во|at|к)\s+)?(\d{1,2})(?::(\d{2}))?\s*(am|pm)?$`)
+	clockSuffixRe = regexp.MustCompile(`^(.*?)\s*(?:,\s*)?(?:(?:в|во|at|к)\s+)?(\d{1,2}(?::\d{2})?\s*(?:am|pm)?|\d{1,2}:\d{2})$`)
+)
+
+// prefixes are dropped before the date itself: "до пятницы", "by friday"
+var prefixes = []string{"не позже", "не позднее", "до", "к", "ко", "by", "on", "due", "в", "во", "на"}
+
+var weekdays = map[string]time.Weekday{
+	"понедельник": time.Monday, "понедельника": time.Monday, "понедельнику": time.Monday, "пн": time.Monday, "monday": time.Monday, "mon": time.Monday,
+	"вторник": time.Tuesday, "вторника": time.Tuesday, "вторнику": time.Tuesday, "вт": time.Tuesday, "tuesday": time.Tuesday, "tue": time.Tuesday,
+	"среда": time.Wednesday, "среду": time.Wednesday, "среды": time.Wednesday, "среде": time.Wednesday, "ср": time.Wednesday, "wednesday": time.Wednesday, "wed": time.Wednesday,
+	"четверг": time.Thursday, "четверга": time.Thursday, "четвергу": time.Thursday, "чт": time.Thursday, "thursday": time.Thursday, "thu": time.Thursday,
+	"пятница": time.Friday, "пятницу": time.Friday, "пятницы": time.Friday, "пятнице": time.Friday, "пт": time.Friday, "friday": time.Friday, "fri": time.Friday,
+	"суббота": time.Saturday, "субботу": time.Saturday, "субботы": time.Saturday, "субботе": time.Saturday, "сб": time.Saturday, "saturday": time.Saturday, "sat": time.Saturday,
+	"воскресенье": time.Sunday, "воскресенья": time.Sunday, "воскресенью": time.Sunday, "вс": time.Sunday, "sunday": time.Sunday, "sun": time.Sunday,
+}
+
+// months maps month names and their usual abbreviations, Russian ones in the genitive
+var months = map[string]time.Month{
+	"января": time.January, "янв": time.January, "january": time.January, "jan": time.January,
+	"февраля": time.February, "фев": time.February, "february": time.February, "feb": time.February,
+	"марта": time.March, "мар": time.March, "march": time.March, "mar": time.March,
+	"апреля": time.April, "апр": time.April, "april": time.April, "apr": time.April,
+	"мая": time.May, "май": time.May, "may": time.May,
+	"июня": time.June, "июн": time.June, "june": time.June, "jun": time.June,
+	"июля": time.July, "июл": time.July, "july": time.July, "jul": time.July,
+	"августа": time.August, "авг": time.August, "august": time.August, "aug": time.August,
+	"сентября": time.September, "сен": time.September, "сент": time.September, "september": time.September, "sep": time.September, "sept": time.September,
+	"октября": time.October, "окт": time.October, "october": time.October, "oct": time.October,
+	"ноября": time.November, "ноя": time.November, "нояб": time.November, "november": time.November, "nov": time.November,
+	"декабря": time.December, "дек": time.December, "december": time.December, "dec": time.December,
+}
+
+// numberWords are the amounts written as words in "через две недели"
+var numberWords = map[string]int{
+	"один": 1, "одну": 1, "одного": 1, "one": 1, "a": 1, "an": 1,
+	"два": 2, "две": 2, "двух": 2, "two": 2, "пару": 2, "couple": 2,
+	"три": 3, "трёх": 3, "трех": 3, "three": 3,
+	"четыре": 4, "four": 4,
+	"пять": 5, "five": 5,
+	"шесть": 6, "six": 6,
+	"семь": 7, "seven": 7,
+	"десять": 10, "ten": 10,
+}
+
+// unit is a step of "через N …" expressions
+type unit int
+
+const (
+	unitDay unit = iota
+	unitWeek
+	unitMonth
+	unitYear
+)
+
+var units = map[string]unit{
+	"день": unitDay, "дня": unitDay, "дней": unitDay, "сутки": unitDay, "day": unitDay, "days": unitDay,
+	"неделю": unitWeek, "недели": unitWeek, "недель": unitWeek, "week": unitWeek, "weeks": unitWeek,
+	"месяц": unitMonth, "месяца": unitMonth, "месяцев": unitMonth, "month": unitMonth, "months": unitMonth,
+	"год": unitYear, "года": unitYear, "лет": unitYear, "year": unitYear, "years": unitYear,
+}
+
+// partsOfDay are the times of vague parts of the day, "пятница вечером";
+// longer phrases go first so "в обед" is not read as "обед"
+var partsOfDay = []struct {
+	phrase string
+	hour   int
+}{
+	{"в полдень", 12}, {"в обед", 13},
+	{"утром", 9}, {"утро", 9}, {"morning", 9},
+	{"полдень", 12}, {"noon", 12}, {"обед", 13},
+	{"днём", 14}, {"днем", 14}, {"afternoon", 14},
+	{"вечером", 18}, {"вечер", 18}, {"evening", 18},
+	{"ночью", 22}, {"night", 22},
+}
+
+// Parse resolves a due date relative to now. It returns false for anything
+// it does not fully understand, so callers can keep the text as it is.
+func Parse(text string, now time.Time) (Due, bool) {
+	text = normalize(text)
+	if text == "" {
+		return Due{}, false
+	}
+	if due, ok := parseISO(text, now.Location()); ok {
+		return due, true
+	}
+	today := midnight(now)
+
+	day, hour, minute, timed, ok := splitTime(text)
+	if !ok {
+		return Due{}, false
+	}
+	var date time.Time
+	if day == "" {
+		// A time alone is today, or tomorrow once it has passed
+		date = today
+		if !atTime(today, hour, minute).After(now) {
+			date = today.AddDate(0, 0, 1)
+		}
+	} else if date, ok = parseDay(day, today); !ok {
+		return Due{}, false
+	}
+
+	if !timed {
+		return Due{At: date}, true
+	}
+	return Due{At: atTime(date, hour, minute), HasTime: true}, true
+}
+
+// normalize lowercases the text, collapses spaces and drops the punctuation
+// and prefixes around the date
+func normalize(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	text = strings.Trim(text, ".!?\"'«»")
+	text = spacesRe.ReplaceAllString(text, " ")
+	for _, prefix := range prefixes {
+		if rest, ok := strings.CutPrefix(text, prefix+" "); ok {
+			text = rest
+			break
+		}
+	}
+	return strings.TrimSpace(text)
+}
+
+func parseISO(text string, loc *time.Location) (Due, bool) {
+	if at, err := time.Parse(time.RFC3339, strings.ToUpper(text)); err == nil {
+		return Due{At: at.In(loc), HasTime: true}, true
+	}
+	m := isoDateTimeRe.FindStringSubmatch(text)
+	if m == nil {
+		return Due{}, false
+	}
+	date, err := time.ParseInLocation(DateLayout, m[1], loc)
+	if err != nil {
+		return Due{}, false
+	}
+	if m[2] == "" {
+		return Due{At: date}, true
+	}
+	hour, minute, ok := parseClock(m[2], "")
+	if !ok {
+		return Due{}, false
+	}
+	return Due{At: atTime(date, hour, minute), HasTime: true}, true
+}
+
+// splitTime separates the time of day from the day: "пятница вечером",
+// "завтра в 15:00", "friday at 3pm". timed is false when there is no time.
+func splitTime(text string) (day string, hour, minute int, timed, ok bool) {
+	for _, part := range partsOfDay {
+		if text == part.phrase {
+			return "", part.hour, 0, true, true
+		}
+		if rest, found := strings.CutSuffix(text, " "+part.phrase); found {
+			return strings.TrimSuffix(strings.TrimSpace(rest), ","), part.hour, 0, true, true
+		}
+	}
+
+	// A bare number is not a time, "at 3pm" and "15:00" are
+	if m := clockRe.FindStringSubmatch(text); m != nil && (m[2] != "" || m[3] != "") {
+		hour, minute, ok := parseClock(m[1]+":"+orZero(m[2]), m[3])
+		return "", hour, minute, true, ok
+	}
+	if m := clockSuffixRe.FindStringSubmatch(text); m != nil && m[1] != "" && hasClockMarker(text, m[2]) {
+		clock := strings.TrimSpace(m[2])
+		meridiem := ""
+		if strings.HasSuffix(clock, "am") || strings.HasSuffix(clock, "pm") {
+			meridiem = clock[len(clock)-2:]
+			clock = strings.TrimSpace(clock[:len(clock)-2])
+		}
+		if !strings.Contains(clock, ":") {
+			clock += ":00"
+		}
+		hour, minute, ok := parseClock(clock, meridiem)
+		return strings.TrimSpace(m[1]), hour, minute, true, ok
+	}
+	return text, 0, 0, false, true
+}
+
+// hasClockMarker tells a trailing time ("завтра в 15", "friday 3pm",
+// "завтра 15:00") from a trailing number of a date ("31 декабря 2026")
+func hasClockMarker(text, clock string) bool {
+	clock = strings.TrimSpace(clock)
+	if strings.Contains(clock, ":") || strings.HasSuffix(clock, "am") || strings.HasSuffix(clock, "pm") {
+		return true
+	}
+	before := strings.TrimSpace(strings.TrimSuffix(text, clock))
+	for _, marker := range []string{" в", " во", " at", " к"} {
+		if strings.HasSuffix(before, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func orZero(minutes string) string {
+	if minutes == "" {
+		return "00"
+	}
+	return minutes
+}
+
+// parseClock parses "15:30" with an optional am/pm
+func parseClock(clock, meridiem string) (int, int, bool) {
+	hourText, minuteText, _ := strings.Cut(clock, ":")
+	hour, err := strconv.Atoi(hourText)
+	if err != nil {
+		return 0, 0, false
+	}
+	minute, err := strconv.Atoi(minuteText)
+	if err != nil || minute > 59 {
+		return 0, 0, false
+	}
+	switch meridiem {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 {
+		return 0, 0, false
+	}
+	return hour, minute, true
+}
+
+// parseDay resolves the day part of a due relative to today's midnight
+func parseDay(text string, today time.Time) (time.Time, bool) {
+	text = normalize(text)
+	switch text {
+	case "сегодня", "today", "tonight":
+		return today, true
+	case "завтра", "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	case "послезавтра", "day after tomorrow", "the day after tomorrow":
+		return today.AddDate(0, 0, 2), true
+	case "next week", "следующая неделя", "следующей неделе", "на следующей неделе", "следующую неделю", "на следующую неделю":
+		return nextWeekday(today.AddDate(0, 0, 1), time.Monday), true
+	case "next month", "следующий месяц", "следующем месяце", "в следующем месяце", "следующему месяцу":
+		return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+	case "end of week", "конец недели", "концу недели":
+		return nextWeekday(today, time.Friday), true
+	case "end of month", "конец месяца", "концу месяца":
+		return time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()), true
+	case "next year", "следующий год", "следующем году", "в следующем году":
+		return time.Date(today.Year()+1, time.January, 1, 0, 0, 0, 0, today.Location()), true
+	}
+
+	if weekday, ok := weekdays[text]; ok {
+		return nextWeekday(today.AddDate(0, 0, 1), weekday), true
+	}
+	for _, next := range []string{"next ", "следующий ", "следующую ", "следующая ", "следующее ", "в следующий ", "в следующую ", "в следующее ", "this ", "эту ", "этот ", "это ", "в эту ", "в этот ", "в это "} {
+		rest, found := strings.CutPrefix(text, next)
+		if !found {
+			continue
+		}
+		weekday, ok := weekdays[rest]
+		if !ok {
+			return time.Time{}, false
+		}
+		if strings.Contains(next, "next") || strings.Contains(next, "следующ") {
+			// Next Friday is the Friday of next week
+			return nextWeekday(nextWeekday(today.AddDate(0, 0, 1), time.Monday), weekday), true
+		}
+		return nextWeekday(today, weekday), true
+	}
+
+	if m := inRe.FindStringSubmatch(text); m != nil {
+		return parseIn(m[1], m[2], today)
+	}
+	if m := dotDateRe.FindStringSubmatch(text); m != nil {
+		day, _ := strconv.Atoi(m[1])
+		month, _ := strconv.Atoi(m[2])
+		return calendarDate(day, time.Month(month), m[3], today)
+	}
+	if m := dayMonthRe.FindStringSubmatch(text); m != nil {
+		if month, ok := months[m[2]]; ok {
+			day, _ := strconv.Atoi(m[1])
+			return calendarDate(day, month, m[3], today)
+		}
+	}
+	if m := monthDayRe.FindStringSubmatch(text); m != nil {
+		if month, ok := months[m[1]]; ok {
+			day, _ := strconv.Atoi(m[2])
+			return calendarDate(day, month, m[3], today)
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseIn resolves "через 3 дня", "через неделю", "in 2 weeks"
+func parseIn(amountText, unitText string, today time.Time) (time.Time, bool) {
+	step, ok := units[unitText]
+	if !ok {
+		return time.Time{}, false
+	}
+	amount := 1
+	if amountText != "" {
+		if n, err := strconv.Atoi(amountText); err == nil {
+			amount = n
+		} else if n, ok := numberWords[amountText]; ok {
+			amount = n
+		} else {
+			return time.Time{}, false
+		}
+	}
+	if amount < 1 || amount > 1000 {
+		return time.Time{}, false
+	}
+	switch step {
+	case unitWeek:
+		return today.AddDate(0, 0, 7*amount), true
+	case unitMonth:
+		return addMonths(today, amount), true
+	case unitYear:
+		return addMonths(today, 12*amount), true
+	default:
+		return today.AddDate(0, 0, amount), true
+	}
+}
+
+// addMonths moves by whole months, keeping to the last day of a shorter month
+func addMonths(date time.Time, months int) time.Time {
+	first := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, date.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(date.Day(), last)-1)
+}
+
+// calendarDate builds a day of a month; without a year a passed date means next year
+func calendarDate(day int, month time.Month, yearText string, today time.Time) (time.Time, bool) {
+	year := today.Year()
+	if yearText != "" {
+		year, _ = strconv.Atoi(yearText)
+		if year < 100 {
+			year += 2000
+		}
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Day() != day || date.Month() != month {
+		return time.Time{}, false
+	}
+	if yearText == "" && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, true
+}
+
+// nextWeekday returns the first day from from on that falls on weekday
+func nextWeekday(from time.Time, weekday time.Weekday) time.Time {
+	days := (int(weekday) - int(from.Weekday()) + 7) % 7
+	return from.AddDate(0, 0, days)
+}
+
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func atTime(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
@@ -0,0 +1,178 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// Thursday, 15 October 2026, 12:30 in Moscow
+var (
+	moscow = time.FixedZone("MSK", 3*60*60)
+	now    = time.Date(2026, time.October, 15, 12, 30, 0, 0, moscow)
+)
+
+func TestParse_Dates(t *testing.T) {
+	cases := map[string]string{
+		// Absolute
+		"2026-11-01":      "2026-11-01",
+		"31.12":           "2026-12-31",
+		"01.02":           "2027-02-01",
+		"01.02.27":        "2027-02-01",
+		"31 декабря":      "2026-12-31",
+		"31-го декабря":   "2026-12-31",
+		"1 января":        "2027-01-01",
+		"5 мая 2028":      "2028-05-05",
+		"3 дек.":          "2026-12-03",
+		"december 31":     "2026-12-31",
+		"Dec 3rd":         "2026-12-03",
+		"March 1, 2027":   "2027-03-01",
+		"15 october":      "2026-10-15",
+		"до 20 октября":   "2026-10-20",
+		"Не позже 20.10.": "2026-10-20",
+
+		// Relative days
+		"сегодня":         "2026-10-15",
+		"Today":           "2026-10-15",
+		"завтра":          "2026-10-16",
+		"tomorrow":        "2026-10-16",
+		"послезавтра":     "2026-10-17",
+		"через день":      "2026-10-16",
+		"через 3 дня":     "2026-10-18",
+		"in 10 days":      "2026-10-25",
+		"через пару дней": "2026-10-17",
+
+		// Weeks, months and years
+		"через неделю":        "2026-10-22",
+		"через две недели":    "2026-10-29",
+		"in 2 weeks":          "2026-10-29",
+		"in a week":           "2026-10-22",
+		"через месяц":         "2026-11-15",
+		"in 3 months":         "2027-01-15",
+		"через год":           "2027-10-15",
+		"next week":           "2026-10-19",
+		"на следующей неделе": "2026-10-19",
+		"next month":          "2026-11-01",
+		"в следующем месяце":  "2026-11-01",
+		"end of month":        "2026-10-31",
+		"к концу месяца":      "2026-10-31",
+		"к концу недели":      "2026-10-16",
+		"в следующем году":    "2027-01-01",
+
+		// Weekdays are the next occurrence, never today
+		"пятница":             "2026-10-16",
+		"до пятницы":          "2026-10-16",
+		"в понедельник":       "2026-10-19",
+		"четверг":             "2026-10-22",
+		"Friday":              "2026-10-16",
+		"by fri":              "2026-10-16",
+		"next friday":         "2026-10-23",
+		"в следующую пятницу": "2026-10-23",
+		"this thursday":       "2026-10-15",
+		"в эту субботу":       "2026-10-17",
+	}
+
+	for text, want := range cases {
+		t.Run(text, func(t *testing.T) {
+			due, ok := Parse(text, now)
+			if !ok {
+				t.Fatalf("Parse(%q) failed", text)
+			}
+			if due.HasTime {
+				t.Errorf("Parse(%q) has a time: %s", text, due)
+			}
+			if got := due.String(); got != want {
+				t.Errorf("Parse(%q) = %s, want %s", text, got, want)
+			}
+		})
+	}
+}
+
+func TestParse_DateTimes(t *testing.T) {
+	cases := map[string]string{
+		"пятница вечером":        "2026-10-16T18:00:00+03:00",
+		"в пятницу вечером":      "2026-10-16T18:00:00+03:00",
+		"завтра утром":           "2026-10-16T09:00:00+03:00",
+		"завтра в обед":          "2026-10-16T13:00:00+03:00",
+		"сегодня днём":           "2026-10-15T14:00:00+03:00",
+		"завтра в 15:00":         "2026-10-16T15:00:00+03:00",
+		"завтра в 15":            "2026-10-16T15:00:00+03:00",
+		"завтра 9:30":            "2026-10-16T09:30:00+03:00",
+		"31 декабря в 23:59":     "2026-12-31T23:59:00+03:00",
+		"tomorrow at 3pm":        "2026-10-16T15:00:00+03:00",
+		"friday 10am":            "2026-10-16T10:00:00+03:00",
+		"friday evening":         "2026-10-16T18:00:00+03:00",
+		"next monday at 12:15pm": "2026-10-19T12:15:00+03:00",
+		"в 18:00":                "2026-10-15T18:00:00+03:00",
+		"at 9am":                 "2026-10-16T09:00:00+03:00",
+		"вечером":                "2026-10-15T18:00:00+03:00",
+		"2026-11-01 10:00":       "2026-11-01T10:00:00+03:00",
+		"2026-11-01T10:00:00Z":   "2026-11-01T13:00:00+03:00",
+	}
+
+	for text, want := range cases {
+		t.Run(text, func(t *testing.T) {
+			due, ok := Parse(text, now)
+			if !ok {
+				t.Fatalf("Parse(%q) failed", text)
+			}
+			if !due.HasTime {
+				t.Errorf("Parse(%q) has no time: %s", text, due)
+			}
+			if got := due.String(); got != want {
+				t.Errorf("Parse(%q) = %s, want %s", text, got, want)
+			}
+		})
+	}
+}
+
+func TestParse_RejectsWhatItDoesNotUnderstand(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"   ",
+		"когда-нибудь",
+		"asap",
+		"скоро",
+		"через полторы недели",
+		"через 3 попугая",
+		"in 0 days",
+		"31 февраля",
+		"30.02",
+		"2026-13-01",
+		"завтра в 25:00",
+		"friday 13pm",
+		"next someday",
+		"15",
+		"пятница или суббота",
+	} {
+		if due, ok := Parse(text, now); ok {
+			t.Errorf("Parse(%q) = %s, want failure", text, due)
+		}
+	}
+}
+
+func TestParse_MonthEnds(t *testing.T) {
+	jan31 := time.Date(2027, time.January, 31, 10, 0, 0, 0, moscow)
+
+	due, ok := Parse("через месяц", jan31)
+	if !ok || due.String() != "2027-02-28" {
+		t.Errorf("a month after January 31 = %s, %v, want 2027-02-28", due, ok)
+	}
+	due, ok = Parse("next month", time.Date(2026, time.December, 20, 10, 0, 0, 0, moscow))
+	if !ok || due.String() != "2027-01-01" {
+		t.Errorf("next month in December = %s, %v, want 2027-01-01", due, ok)
+	}
+}
+
+func TestParse_PassedTimeMovesToTomorrow(t *testing.T) {
+	due, ok := Parse("в 10:00", now)
+	if !ok || due.String() != "2026-10-16T10:00:00+03:00" {
+		t.Errorf("Parse(в 10:00) = %s, %v, want tomorrow at 10:00", due, ok)
+	}
+}
+
+func TestDue_Date(t *testing.T) {
+	due, _ := Parse("пятница вечером", now)
+	if due.Date() != "2026-10-16" {
+		t.Errorf("Date() = %s, want 2026-10-16", due.Date())
+	}
+}
@@ -3,7 +3,10 @@ package db
 import (
 	"context"
 	"database/sql"
+	_ "embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"time"
@@ -11,8 +14,18 @@ import (
 	_ "github.com/lib/pq"
 )
 
+const schemaPath = "internal/db/schema.sql"
+
+//go:embed schema.sql
+var embeddedSchema string
+
+// DefaultBotID identifies the bot when a single bot runs in the process
+const DefaultBotID = "default"
+
 type Manager struct {
 	db *sql.DB
+	// botID scopes chat settings and sessions to one bot identity
+	botID string
 }
 
 func NewManager() (*Manager, error) {
@@ -39,7 +52,18 @@ func NewManager() (*Manager, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	return &Manager{db: db}, nil
+	return &Manager{db: db, botID: DefaultBotID}, nil
+}
+
+// ForBot returns a manager scoped to another bot identity. It shares the
+// connection pool, so only the original manager should be closed.
+func (m *Manager) ForBot(botID string) *Manager {
+	return &Manager{db: m.db, botID: botID}
+}
+
+// BotID returns the bot identity the manager is scoped to
+func (m *Manager) BotID() string {
+	return m.botID
 }
 
 func (m *Manager) Close() error {
@@ -47,14 +71,14 @@ func (m *Manager) Close() error {
 }
 
 func (m *Manager) InitSchema(ctx context.Context) error {
-	schemaSQL, err := os.ReadFile("internal/db/schema.sql")
+	schemaSQL, source, err := loadSchema()
 	if err != nil {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
 
-	log.Printf("Schema loaded from: internal/db/schema.sql")
+	log.Printf("Schema loaded from: %s", source)
 
-	_, err = m.db.ExecContext(ctx, string(schemaSQL))
+	_, err = m.db.ExecContext(ctx, schemaSQL)
 	if err != nil {
 		return fmt.Errorf("failed to initialize schema: %w", err)
 	}
@@ -62,6 +86,19 @@ func (m *Manager) InitSchema(ctx context.Context) error {
 	return nil
 }
 
+// loadSchema prefers the schema file next to the working directory so it can
+// be patched without a rebuild, and falls back to the embedded copy.
+func loadSchema() (string, string, error) {
+	data, err := os.ReadFile(schemaPath)
+	if err == nil {
+		return string(data), schemaPath, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", "", err
+	}
+	return embeddedSchema, "embedded schema.sql", nil
+}
+
 func (m *Manager) GetDB() *sql.DB {
 	return m.db
 }
\ No newline at end of file
@@ -19,15 +19,55 @@ type ChatSettings struct {
 	UpdatedAt        time.Time `db:"updated_at"`
 }
 
+// Transcript modes of a chat: where the discussion goes when a task is created
+const (
+	TranscriptOff         = ""
+	TranscriptDescription = "description"
+	TranscriptComment     = "comment"
+)
+
 type Session struct {
 	ID        int          `db:"id"`
 	ChatID    int64        `db:"chat_id"`
+	ThreadID  int          `db:"thread_id"` // Forum topic, 0 outside topics
+	Name      string       `db:"name"`      // Set by /start_discussion <name>, empty for the default session
 	OwnerID   int64        `db:"owner_id"`
 	Status    string       `db:"status"`
 	StartedAt time.Time    `db:"started_at"`
 	ClosedAt  sql.NullTime `db:"closed_at"`
 }
 
+// SessionParticipant is a person who wrote in a discussion
+type SessionParticipant struct {
+	SessionID    int            `db:"session_id"`
+	UserID       int64          `db:"user_id"`
+	Username     sql.NullString `db:"username"`
+	DisplayName  sql.NullString `db:"display_name"`
+	MessageCount int            `db:"message_count"`
+	FirstSeenAt  time.Time      `db:"first_seen_at"`
+}
+
+// PreviewMessage is a sent draft preview whose buttons are still live
+type PreviewMessage struct {
+	ID            int       `db:"id"`
+	SessionID     int       `db:"session_id"`
+	ChatID        int64     `db:"chat_id"`
+	MessageID     int       `db:"message_id"`
+	Text          string    `db:"text"`
+	CreatedAt     time.Time `db:"created_at"`
+	SessionClosed bool      `db:"-"`
+}
+
+// ChatNotifier is a notifier plugin configured for a chat
+type ChatNotifier struct {
+	ID        int       `db:"id"`
+	ChatID    int64     `db:"chat_id"`
+	Kind      string    `db:"kind"`
+	Target    string    `db:"target"`
+	Secret    string    `db:"secret"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
 type Message struct {
 	ID        int                     `db:"id"`
 	ChatID    int64                   `db:"chat_id"`
@@ -44,6 +84,16 @@ func (m Message) GetLinks() []tasklinks.TaskLink {
 	return []tasklinks.TaskLink(m.Links)
 }
 
+// Attachment is a photo or document posted in a discussion, referenced by its Telegram file ID
+type Attachment struct {
+	ID        int    `db:"id"`
+	SessionID int    `db:"session_id"`
+	MessageID int    `db:"message_id"`
+	FileID    string `db:"file_id"`
+	FileName  string `db:"file_name"`
+	FileSize  int64  `db:"file_size"`
+}
+
 func (m Message) GetMessageID() int {
 	return m.MessageID
 }
@@ -103,6 +153,19 @@ type CreatedTask struct {
 	CreatedAt           time.Time `db:"created_at"`
 }
 
+// TaskNudge is a created task that still has no assignee and whose chat was not reminded yet
+type TaskNudge struct {
+	CreatedTaskID int            `db:"id"`
+	SessionID     int            `db:"session_id"`
+	ChatID        int64          `db:"chat_id"`
+	OwnerID       int64          `db:"owner_id"`
+	OwnerUsername sql.NullString `db:"owner_username"`
+	TodoistTaskID string         `db:"todoist_task_id"`
+	Title         string         `db:"title"`
+	URL           string         `db:"url"`
+	CreatedAt     time.Time      `db:"created_at"`
+}
+
 type AssigneeSnapshot struct {
 	TodoistID   string
 	Name        string
@@ -137,6 +200,27 @@ type AssigneeMapping struct {
 	UpdatedAt        time.Time `db:"updated_at"`
 }
 
+// DraftRevision is one version of a session draft; Instruction is the edit
+// that produced it, empty for the analysis of the discussion
+type DraftRevision struct {
+	SessionID   int
+	Revision    int
+	Instruction string
+	Draft       DraftTaskInput
+	CreatedAt   time.Time
+}
+
+// AIJob is an AI job that was queued and has not finished yet; the bot
+// rebuilds it from Payload after a restart
+type AIJob struct {
+	ID         int
+	Kind       string
+	ChatID     int64
+	SessionID  int
+	Payload    []byte
+	EnqueuedAt time.Time
+}
+
 type AuditEdit struct {
 	ID              int       `db:"id"`
 	SessionID       int       `db:"session_id"`
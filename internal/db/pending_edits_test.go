@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// startTestSession opens a session in a fresh chat
+func startTestSession(t *testing.T, manager *Manager) (int64, int) {
+	t.Helper()
+	ctx := context.Background()
+	chatID := -time.Now().UnixNano() % 1_000_000_000
+	if err := manager.EnsureChatExists(ctx, chatID); err != nil {
+		t.Fatalf("failed to create chat: %v", err)
+	}
+	sessionID, err := manager.StartSession(ctx, chatID, 0, "", 1)
+	if err != nil {
+		t.Fatalf("failed to start session: %v", err)
+	}
+	return chatID, sessionID
+}
+
+func TestPendingEdits_SaveGetDelete(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	chatID, sessionID := startTestSession(t, manager)
+
+	if got, err := manager.GetPendingEdit(ctx, chatID, 10); err != nil || got != 0 {
+		t.Fatalf("expected no edit request, got %d (%v)", got, err)
+	}
+
+	if err := manager.SavePendingEdit(ctx, chatID, 10, sessionID); err != nil {
+		t.Fatalf("failed to save edit request: %v", err)
+	}
+	// Saving the same message again is not an error
+	if err := manager.SavePendingEdit(ctx, chatID, 10, sessionID); err != nil {
+		t.Fatalf("failed to save edit request again: %v", err)
+	}
+	if got, err := manager.GetPendingEdit(ctx, chatID, 10); err != nil || got != sessionID {
+		t.Fatalf("expected session %d, got %d (%v)", sessionID, got, err)
+	}
+
+	// Edit requests are kept per bot
+	if got, err := manager.ForBot(manager.botID+"-other").GetPendingEdit(ctx, chatID, 10); err != nil || got != 0 {
+		t.Fatalf("expected another bot not to see the edit request, got %d (%v)", got, err)
+	}
+
+	if err := manager.DeletePendingEdit(ctx, chatID, 10); err != nil {
+		t.Fatalf("failed to delete edit request: %v", err)
+	}
+	if got, err := manager.GetPendingEdit(ctx, chatID, 10); err != nil || got != 0 {
+		t.Fatalf("expected the edit request to be gone, got %d (%v)", got, err)
+	}
+}
+
+func TestPendingEdits_DeleteStale(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	chatID, sessionID := startTestSession(t, manager)
+	_, closedSessionID := startTestSession(t, manager)
+
+	for messageID := 1; messageID <= 2; messageID++ {
+		if err := manager.SavePendingEdit(ctx, chatID, messageID, sessionID); err != nil {
+			t.Fatalf("failed to save edit request: %v", err)
+		}
+	}
+	if err := manager.SavePendingEdit(ctx, chatID, 3, closedSessionID); err != nil {
+		t.Fatalf("failed to save edit request: %v", err)
+	}
+	if _, err := manager.db.ExecContext(ctx, `
+		UPDATE pending_edits SET created_at = NOW() - INTERVAL '2 days'
+		WHERE bot_id = $1 AND chat_id = $2 AND message_id = 1
+	`, manager.botID, chatID); err != nil {
+		t.Fatalf("failed to age edit request: %v", err)
+	}
+	if err := manager.CloseSession(ctx, closedSessionID); err != nil {
+		t.Fatalf("failed to close session: %v", err)
+	}
+
+	deleted, err := manager.DeleteStalePendingEdits(ctx, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		t.Fatalf("failed to delete stale edit requests: %v", err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected the old request and the closed session's one to be deleted, got %d", deleted)
+	}
+	if got, _ := manager.GetPendingEdit(ctx, chatID, 2); got != sessionID {
+		t.Fatalf("expected the fresh request to stay, got session %d", got)
+	}
+}
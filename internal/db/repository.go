@@ -2,11 +2,18 @@ package db
 
 import (
 	"context"
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+	"github.com/user/telegram-bot/internal/activity"
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/shard"
 	"github.com/user/telegram-bot/internal/taskfields"
 	"github.com/user/telegram-bot/internal/tasklinks"
 )
@@ -174,27 +181,65 @@ func (m *Manager) SetTodoistProjectID(ctx context.Context, chatID int64, project
 	}
 
 	query := `
-		INSERT INTO chat_settings (chat_id, todoist_project_id, updated_at)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (chat_id) DO UPDATE
-		SET todoist_project_id = $2, updated_at = $3
+		INSERT INTO chat_settings (bot_id, chat_id, todoist_project_id, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET todoist_project_id = $3, updated_at = $4,
+		    -- sections belong to a project, so the default one does not survive a switch
+		    todoist_section_id = CASE WHEN chat_settings.todoist_project_id IS DISTINCT FROM $3 THEN '' ELSE chat_settings.todoist_section_id END
 	`
-	_, err := m.db.ExecContext(ctx, query, chatID, projectID, time.Now())
+	_, err := m.db.ExecContext(ctx, query, m.botID, chatID, projectID, time.Now())
 	if err != nil {
 		return fmt.Errorf("failed to set todoist project id: %w", err)
 	}
 	return nil
 }
 
+// SetDefaultSection stores the section of the chat project new tasks go to; an empty ID asks on every confirm
+func (m *Manager) SetDefaultSection(ctx context.Context, chatID int64, sectionID string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, todoist_section_id, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET todoist_section_id = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, sectionID, time.Now()); err != nil {
+		return fmt.Errorf("failed to set default section: %w", err)
+	}
+	return nil
+}
+
+// GetDefaultSection returns the default section of a chat, or an empty string if none is set
+func (m *Manager) GetDefaultSection(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT todoist_section_id
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var sectionID string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&sectionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get default section: %w", err)
+	}
+	return sectionID, nil
+}
+
 // GetTodoistProjectID gets the Todoist project ID for a chat
 func (m *Manager) GetTodoistProjectID(ctx context.Context, chatID int64) (string, error) {
 	query := `
 		SELECT todoist_project_id
 		FROM chat_settings
-		WHERE chat_id = $1
+		WHERE bot_id = $1 AND chat_id = $2
 	`
 	var projectID sql.NullString
-	err := m.db.QueryRowContext(ctx, query, chatID).Scan(&projectID)
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&projectID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", ErrProjectIDNotSet
@@ -209,44 +254,49 @@ func (m *Manager) GetTodoistProjectID(ctx context.Context, chatID int64) (string
 	return projectID.String, nil
 }
 
-// StartSession creates a new session for a chat with the specified owner
-func (m *Manager) StartSession(ctx context.Context, chatID int64, ownerID int64) (int, error) {
-	// Check if there's an active session
-	active, err := m.HasActiveSession(ctx, chatID)
-	if err != nil {
-		return 0, err
-	}
-
-	if active {
-		return 0, ErrSessionAlreadyExists
-	}
-
-	// Create a new session with owner
+// StartSession creates a new session for a chat topic with the specified
+// owner; threadID is 0 outside forum topics. A topic runs one unnamed session
+// (name "") and any number of sessions with distinct names.
+func (m *Manager) StartSession(ctx context.Context, chatID int64, threadID int, name string, ownerID int64) (int, error) {
+	// The partial unique index on open sessions rejects a second open session
+	// with the same name, even when two /start_discussion commands arrive at the same time
 	query := `
-		INSERT INTO sessions (chat_id, owner_id, status)
-		VALUES ($1, $2, 'open')
+		INSERT INTO sessions (bot_id, chat_id, thread_id, name, owner_id, status)
+		VALUES ($1, $2, $3, $4, $5, 'open')
 		RETURNING id
 	`
 	var sessionID int
-	err = m.db.QueryRowContext(ctx, query, chatID, ownerID).Scan(&sessionID)
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID, threadID, name, ownerID).Scan(&sessionID)
 	if err != nil {
+		if isUniqueViolation(err, openSessionIndex) {
+			return 0, ErrSessionAlreadyExists
+		}
 		return 0, fmt.Errorf("failed to start session: %w", err)
 	}
 
 	return sessionID, nil
 }
 
-// HasActiveSession checks if a chat has an active session
-func (m *Manager) HasActiveSession(ctx context.Context, chatID int64) (bool, error) {
+// openSessionIndex is the unique index allowing one open session per name in a chat topic
+const openSessionIndex = "sessions_one_open_per_name_idx"
+
+// isUniqueViolation reports whether err is a PostgreSQL unique violation of constraint
+func isUniqueViolation(err error, constraint string) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == constraint
+}
+
+// HasActiveSession checks if a chat topic has an active session, named or not
+func (m *Manager) HasActiveSession(ctx context.Context, chatID int64, threadID int) (bool, error) {
 	query := `
 		SELECT EXISTS (
 			SELECT 1
 			FROM sessions
-			WHERE chat_id = $1 AND status = 'open'
+			WHERE bot_id = $1 AND chat_id = $2 AND thread_id = $3 AND status = 'open'
 		)
 	`
 	var exists bool
-	err := m.db.QueryRowContext(ctx, query, chatID).Scan(&exists)
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID, threadID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check active session: %w", err)
 	}
@@ -254,24 +304,39 @@ func (m *Manager) HasActiveSession(ctx context.Context, chatID int64) (bool, err
 	return exists, nil
 }
 
-// GetActiveSession returns the active session for a chat
-func (m *Manager) GetActiveSession(ctx context.Context, chatID int64) (*Session, error) {
-	query := `
-		SELECT id, chat_id, owner_id, status, started_at, closed_at
-		FROM sessions
-		WHERE chat_id = $1 AND status = 'open'
-		ORDER BY started_at DESC
-		LIMIT 1
-	`
+// sessionColumns are the columns scanned by scanSession
+const sessionColumns = `id, chat_id, thread_id, name, owner_id, status, started_at, closed_at`
+
+// scanSession scans a row of sessionColumns
+func scanSession(row interface{ Scan(...any) error }) (*Session, error) {
 	var session Session
-	err := m.db.QueryRowContext(ctx, query, chatID).Scan(
+	err := row.Scan(
 		&session.ID,
 		&session.ChatID,
+		&session.ThreadID,
+		&session.Name,
 		&session.OwnerID,
 		&session.Status,
 		&session.StartedAt,
 		&session.ClosedAt,
 	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActiveSession returns the active session for a chat topic: the unnamed
+// one, or the latest named one when the topic has no unnamed session
+func (m *Manager) GetActiveSession(ctx context.Context, chatID int64, threadID int) (*Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE bot_id = $1 AND chat_id = $2 AND thread_id = $3 AND status = 'open'
+		ORDER BY name = '' DESC, started_at DESC
+		LIMIT 1
+	`
+	session, err := scanSession(m.db.QueryRowContext(ctx, query, m.botID, chatID, threadID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNoActiveSession
@@ -279,7 +344,97 @@ func (m *Manager) GetActiveSession(ctx context.Context, chatID int64) (*Session,
 		return nil, fmt.Errorf("failed to get active session: %w", err)
 	}
 
-	return &session, nil
+	return session, nil
+}
+
+// GetNamedSession returns the active session with the given name in a chat
+// topic; the name "" selects the unnamed session
+func (m *Manager) GetNamedSession(ctx context.Context, chatID int64, threadID int, name string) (*Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE bot_id = $1 AND chat_id = $2 AND thread_id = $3 AND name = $4 AND status = 'open'
+	`
+	session, err := scanSession(m.db.QueryRowContext(ctx, query, m.botID, chatID, threadID, name))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoActiveSession
+		}
+		return nil, fmt.Errorf("failed to get session %q: %w", name, err)
+	}
+
+	return session, nil
+}
+
+// ListActiveSessions returns the active sessions of a chat topic, oldest first
+func (m *Manager) ListActiveSessions(ctx context.Context, chatID int64, threadID int) ([]Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE bot_id = $1 AND chat_id = $2 AND thread_id = $3 AND status = 'open'
+		ORDER BY started_at ASC, id ASC
+	`
+	rows, err := m.db.QueryContext(ctx, query, m.botID, chatID, threadID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session row: %w", err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session rows: %w", err)
+	}
+	return sessions, nil
+}
+
+// GetMessageSession returns the session a saved chat message belongs to, 0 if
+// the message was not captured into one
+func (m *Manager) GetMessageSession(ctx context.Context, chatID int64, messageID int) (int, error) {
+	query := `
+		SELECT session_id
+		FROM messages
+		WHERE bot_id = $1 AND chat_id = $2 AND message_id = $3 AND session_id IS NOT NULL
+		ORDER BY id DESC
+		LIMIT 1
+	`
+	var sessionID int
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID, messageID).Scan(&sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get message session: %w", err)
+	}
+	return sessionID, nil
+}
+
+// SetSessionNotice records the pinned status message of a session; 0 clears it
+func (m *Manager) SetSessionNotice(ctx context.Context, sessionID int, messageID int) error {
+	_, err := m.db.ExecContext(ctx, `UPDATE sessions SET notice_message_id = NULLIF($2, 0) WHERE id = $1`, sessionID, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to set session notice: %w", err)
+	}
+	return nil
+}
+
+// GetSessionNotice returns the pinned status message of a session, 0 if there is none
+func (m *Manager) GetSessionNotice(ctx context.Context, sessionID int) (int, error) {
+	var messageID sql.NullInt64
+	err := m.db.QueryRowContext(ctx, `SELECT notice_message_id FROM sessions WHERE id = $1`, sessionID).Scan(&messageID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get session notice: %w", err)
+	}
+	return int(messageID.Int64), nil
 }
 
 // IsSessionOwner checks if the given user is the owner of the session
@@ -306,43 +461,54 @@ func (m *Manager) IsSessionOwner(ctx context.Context, sessionID int, userID int6
 	return ownerID.Int64 == userID, nil
 }
 
-// CloseSession closes an active session
-func (m *Manager) CloseSession(ctx context.Context, chatID int64) error {
-	session, err := m.GetActiveSession(ctx, chatID)
-	if err != nil {
-		return err
-	}
-
+// CloseSession closes an active session. The check and the update are one
+// statement, so of two concurrent closes only one succeeds.
+func (m *Manager) CloseSession(ctx context.Context, sessionID int) error {
 	query := `
 		UPDATE sessions
 		SET status = 'closed', closed_at = $1
-		WHERE id = $2
+		WHERE bot_id = $2 AND id = $3 AND status = 'open'
 	`
-	_, err = m.db.ExecContext(ctx, query, time.Now(), session.ID)
+	res, err := m.db.ExecContext(ctx, query, time.Now(), m.botID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to close session: %w", err)
+	}
+	closed, err := res.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to close session: %w", err)
 	}
+	if closed == 0 {
+		return ErrNoActiveSession
+	}
 
 	return nil
 }
 
-// SaveMessage saves a message from a chat
-func (m *Manager) SaveMessage(ctx context.Context, chatID int64, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
+// SaveMessage saves a message from a chat topic; it joins the active session of the topic
+func (m *Manager) SaveMessage(ctx context.Context, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
+	var sessionID int
+	if session, err := m.GetActiveSession(ctx, chatID, threadID); err == nil {
+		sessionID = session.ID
+	}
+	return m.SaveSessionMessage(ctx, sessionID, chatID, threadID, messageID, userID, username, text, links)
+}
+
+// SaveSessionMessage saves a message from a chat topic into the given session,
+// chosen by the sender with a reply or a #tag; sessionID 0 saves it outside sessions
+func (m *Manager) SaveSessionMessage(ctx context.Context, sessionID int, chatID int64, threadID int, messageID int, userID int64, username, text string, links []tasklinks.TaskLink) error {
 	if err := m.EnsureChatExists(ctx, chatID); err != nil {
 		return err
 	}
 
-	// Get active session if exists
-	var sessionID sql.NullInt32
-	session, err := m.GetActiveSession(ctx, chatID)
-	if err == nil {
-		sessionID.Int32 = int32(session.ID)
-		sessionID.Valid = true
+	var nullSessionID sql.NullInt32
+	if sessionID != 0 {
+		nullSessionID.Int32 = int32(sessionID)
+		nullSessionID.Valid = true
 	}
 
 	query := `
-		INSERT INTO messages (chat_id, session_id, message_id, user_id, username, text, links)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO messages (chat_id, session_id, message_id, user_id, username, text, links, bot_id, thread_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
 	var nullUserID sql.NullInt64
@@ -357,16 +523,18 @@ func (m *Manager) SaveMessage(ctx context.Context, chatID int64, messageID int,
 		nullUsername.Valid = true
 	}
 
-	_, err = m.db.ExecContext(
+	_, err := m.db.ExecContext(
 		ctx,
 		query,
 		chatID,
-		sessionID,
+		nullSessionID,
 		messageID,
 		nullUserID,
 		nullUsername,
 		text,
 		tasklinks.TaskLinkSlice(links),
+		m.botID,
+		threadID,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to save message: %w", err)
@@ -416,6 +584,94 @@ func (m *Manager) GetSessionMessages(ctx context.Context, sessionID int) ([]Mess
 	return messages, nil
 }
 
+// SaveAttachment records a file posted in a discussion
+func (m *Manager) SaveAttachment(ctx context.Context, attachment Attachment) error {
+	query := `
+		INSERT INTO message_attachments (session_id, message_id, file_id, file_name, file_size)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := m.db.ExecContext(ctx, query, attachment.SessionID, attachment.MessageID, attachment.FileID, attachment.FileName, attachment.FileSize)
+	if err != nil {
+		return fmt.Errorf("failed to save attachment: %w", err)
+	}
+	return nil
+}
+
+// GetSessionAttachments returns the files posted in a discussion, oldest first
+func (m *Manager) GetSessionAttachments(ctx context.Context, sessionID int) ([]Attachment, error) {
+	query := `
+		SELECT id, session_id, message_id, file_id, file_name, file_size
+		FROM message_attachments
+		WHERE session_id = $1
+		ORDER BY id ASC
+	`
+	rows, err := m.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []Attachment
+	for rows.Next() {
+		var a Attachment
+		if err := rows.Scan(&a.ID, &a.SessionID, &a.MessageID, &a.FileID, &a.FileName, &a.FileSize); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment row: %w", err)
+		}
+		attachments = append(attachments, a)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating attachment rows: %w", err)
+	}
+	return attachments, nil
+}
+
+// AddSessionParticipant records that a user wrote in an open discussion,
+// counting their messages. A closed session is left as it is.
+func (m *Manager) AddSessionParticipant(ctx context.Context, sessionID int, userID int64, username, displayName string) error {
+	query := `
+		INSERT INTO session_participants (session_id, user_id, username, display_name)
+		SELECT id, $3, NULLIF($4, ''), NULLIF($5, '')
+		FROM sessions
+		WHERE bot_id = $1 AND id = $2 AND status = 'open'
+		ON CONFLICT (session_id, user_id) DO UPDATE
+		SET message_count = session_participants.message_count + 1,
+			username = COALESCE(EXCLUDED.username, session_participants.username),
+			display_name = COALESCE(EXCLUDED.display_name, session_participants.display_name)
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, sessionID, userID, username, displayName); err != nil {
+		return fmt.Errorf("failed to add session participant: %w", err)
+	}
+	return nil
+}
+
+// GetSessionParticipants lists the participants of a session in the order they joined
+func (m *Manager) GetSessionParticipants(ctx context.Context, sessionID int) ([]SessionParticipant, error) {
+	query := `
+		SELECT session_id, user_id, username, display_name, message_count, first_seen_at
+		FROM session_participants
+		WHERE session_id = $1
+		ORDER BY first_seen_at ASC, user_id ASC
+	`
+	rows, err := m.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session participants: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []SessionParticipant
+	for rows.Next() {
+		var p SessionParticipant
+		if err := rows.Scan(&p.SessionID, &p.UserID, &p.Username, &p.DisplayName, &p.MessageCount, &p.FirstSeenAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session participant row: %w", err)
+		}
+		participants = append(participants, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating session participant rows: %w", err)
+	}
+	return participants, nil
+}
+
 // SaveDraftTask saves a draft task for a session
 func (m *Manager) SaveDraftTask(ctx context.Context, input DraftTaskInput) error {
 	query := `
@@ -532,8 +788,128 @@ func (m *Manager) DeleteDraftTask(ctx context.Context, sessionID int) error {
 	return nil
 }
 
+// SaveDraftAlternatives replaces the drafts a session owner chooses from;
+// option numbers start at 1 in the order of drafts
+func (m *Manager) SaveDraftAlternatives(ctx context.Context, sessionID int, drafts []DraftTaskInput) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM draft_alternatives WHERE session_id = $1`, sessionID); err != nil {
+		return fmt.Errorf("failed to clear draft alternatives: %w", err)
+	}
+	for i, draft := range drafts {
+		data, err := json.Marshal(draft)
+		if err != nil {
+			return fmt.Errorf("failed to encode draft alternative: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO draft_alternatives (session_id, option, draft, created_at)
+			VALUES ($1, $2, $3, NOW())
+		`, sessionID, i+1, data); err != nil {
+			return fmt.Errorf("failed to save draft alternative: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit draft alternatives: %w", err)
+	}
+	return nil
+}
+
+// GetDraftAlternative returns one of the drafts saved by SaveDraftAlternatives
+func (m *Manager) GetDraftAlternative(ctx context.Context, sessionID, option int) (DraftTaskInput, error) {
+	var data []byte
+	err := m.db.QueryRowContext(ctx, `
+		SELECT draft FROM draft_alternatives
+		WHERE session_id = $1 AND option = $2
+	`, sessionID, option).Scan(&data)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DraftTaskInput{}, fmt.Errorf("draft alternative not found: %w", err)
+		}
+		return DraftTaskInput{}, fmt.Errorf("failed to get draft alternative: %w", err)
+	}
+
+	var draft DraftTaskInput
+	if err := json.Unmarshal(data, &draft); err != nil {
+		return DraftTaskInput{}, fmt.Errorf("failed to decode draft alternative: %w", err)
+	}
+	draft.SessionID = sessionID
+	return draft, nil
+}
+
+// AddDraftRevision records draft as the next version of its session draft
+// and returns its number, starting at 1
+func (m *Manager) AddDraftRevision(ctx context.Context, draft DraftTaskInput, instruction string) (int, error) {
+	data, err := json.Marshal(draft)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode draft revision: %w", err)
+	}
+
+	var revision int
+	err = m.db.QueryRowContext(ctx, `
+		INSERT INTO draft_revisions (session_id, revision, instruction, draft, created_at)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, NOW()
+		FROM draft_revisions
+		WHERE session_id = $1
+		RETURNING revision
+	`, draft.SessionID, instruction, data).Scan(&revision)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save draft revision: %w", err)
+	}
+	return revision, nil
+}
+
+// ListDraftRevisions returns the versions of a session draft, oldest first
+func (m *Manager) ListDraftRevisions(ctx context.Context, sessionID int) ([]DraftRevision, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT revision, instruction, draft, created_at
+		FROM draft_revisions
+		WHERE session_id = $1
+		ORDER BY revision
+	`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list draft revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []DraftRevision
+	for rows.Next() {
+		r := DraftRevision{SessionID: sessionID}
+		var data []byte
+		if err := rows.Scan(&r.Revision, &r.Instruction, &data, &r.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan draft revision row: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.Draft); err != nil {
+			return nil, fmt.Errorf("failed to decode draft revision %d: %w", r.Revision, err)
+		}
+		r.Draft.SessionID = sessionID
+		revisions = append(revisions, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating draft revision rows: %w", err)
+	}
+	return revisions, nil
+}
+
+// DeleteDraftRevisions removes the versions of a session draft from revision on
+func (m *Manager) DeleteDraftRevisions(ctx context.Context, sessionID, fromRevision int) error {
+	if _, err := m.db.ExecContext(ctx, `
+		DELETE FROM draft_revisions
+		WHERE session_id = $1 AND revision >= $2
+	`, sessionID, fromRevision); err != nil {
+		return fmt.Errorf("failed to delete draft revisions: %w", err)
+	}
+	return nil
+}
+
 // SaveCreatedTask saves a created Todoist task and a snapshot of the fields used to create it.
-func (m *Manager) SaveCreatedTask(ctx context.Context, task DraftTask, todoistTaskID, url string) error {
+// A session has one created task: if another confirm already saved one, the
+// existing row is returned with created set to false.
+func (m *Manager) SaveCreatedTask(ctx context.Context, task DraftTask, todoistTaskID, url string) (CreatedTask, bool, error) {
 	query := `
 		INSERT INTO created_tasks (
 			session_id, todoist_task_id, url, title, description, due_iso, priority, task_type, labels, selected_links, assignee_note,
@@ -548,6 +924,8 @@ func (m *Manager) SaveCreatedTask(ctx context.Context, task DraftTask, todoistTa
 			$16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28,
 			$29, $30, $31, $32, $33, $34, $35, $36, $37
 		)
+		ON CONFLICT (session_id) DO NOTHING
+		RETURNING id, session_id, todoist_task_id, url, created_at
 	`
 	args := []any{
 		task.SessionID,
@@ -567,47 +945,249 @@ func (m *Manager) SaveCreatedTask(ctx context.Context, task DraftTask, todoistTa
 		task.AssigneeMatchSource,
 	}
 	args = append(args, nullableTaskFieldsFrom(task.Fields).values()...)
-	_, err := m.db.ExecContext(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("failed to save created task: %w", err)
+
+	var saved CreatedTask
+	err := m.db.QueryRowContext(ctx, query, args...).Scan(&saved.ID, &saved.SessionID, &saved.TodoistTaskID, &saved.URL, &saved.CreatedAt)
+	if err == nil {
+		return saved, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return CreatedTask{}, false, fmt.Errorf("failed to save created task: %w", err)
 	}
 
-	return nil
+	existing, err := m.GetCreatedTask(ctx, task.SessionID)
+	if err != nil {
+		return CreatedTask{}, false, err
+	}
+	if existing == nil {
+		return CreatedTask{}, false, fmt.Errorf("failed to save created task: conflicting row of session %d disappeared", task.SessionID)
+	}
+	return *existing, false, nil
 }
 
-// SaveAuditEdit saves an audit edit record
-func (m *Manager) SaveAuditEdit(ctx context.Context, sessionID int, instructionText string, diffJSON []byte) error {
-	query := `
-		INSERT INTO audit_edits (session_id, instruction_text, diff_json)
-		VALUES ($1, $2, $3)
-	`
-	_, err := m.db.ExecContext(ctx, query, sessionID, instructionText, diffJSON)
+// GetCreatedTask returns the task created from a session, or nil if none was created
+func (m *Manager) GetCreatedTask(ctx context.Context, sessionID int) (*CreatedTask, error) {
+	var task CreatedTask
+	err := m.db.QueryRowContext(ctx, `
+		SELECT id, session_id, todoist_task_id, url, created_at
+		FROM created_tasks
+		WHERE session_id = $1
+	`, sessionID).Scan(&task.ID, &task.SessionID, &task.TodoistTaskID, &task.URL, &task.CreatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to save audit edit: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get created task: %w", err)
 	}
+	return &task, nil
+}
 
-	return nil
+// GetChatCreatedTask returns a created task of the chat with its assignee, or nil if the chat has no such task
+func (m *Manager) GetChatCreatedTask(ctx context.Context, chatID int64, id int) (*CreatedTask, error) {
+	var task CreatedTask
+	err := m.db.QueryRowContext(ctx, `
+		SELECT t.id, t.session_id, t.todoist_task_id, t.url, t.title, t.assignee_todoist_id, t.assignee_name, t.created_at
+		FROM created_tasks t
+		JOIN sessions s ON s.id = t.session_id
+		WHERE t.id = $1 AND s.chat_id = $2 AND s.bot_id = $3
+	`, id, chatID, m.botID).Scan(&task.ID, &task.SessionID, &task.TodoistTaskID, &task.URL, &task.Title,
+		&task.AssigneeTodoistID, &task.AssigneeName, &task.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get created task: %w", err)
+	}
+	return &task, nil
 }
 
-func (m *Manager) ReplaceAssigneeMappings(ctx context.Context, chatID int64, projectID string, mappings []AssigneeMapping) error {
-	if err := m.EnsureChatExists(ctx, chatID); err != nil {
-		return err
+// ListTaskChats returns the chats a tracker task was created from
+func (m *Manager) ListTaskChats(ctx context.Context, todoistTaskID string) ([]int64, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT DISTINCT s.chat_id
+		FROM created_tasks t
+		JOIN sessions s ON s.id = t.session_id
+		WHERE t.todoist_task_id = $1 AND s.bot_id = $2
+	`, todoistTaskID, m.botID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats of task: %w", err)
 	}
+	defer rows.Close()
 
-	tx, err := m.db.BeginTx(ctx, nil)
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan chat of task: %w", err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+// SetCreatedTaskAssignee records the assignee a created task got after it was created
+func (m *Manager) SetCreatedTaskAssignee(ctx context.Context, id int, assignee AssigneeSnapshot) error {
+	_, err := m.db.ExecContext(ctx, `
+		UPDATE created_tasks
+		SET assignee_todoist_id = $2, assignee_name = $3, assignee_email = $4, assignee_match_source = $5
+		WHERE id = $1
+	`, id, assignee.TodoistID, assignee.Name, assignee.Email, assignee.MatchSource)
 	if err != nil {
-		return fmt.Errorf("failed to start transaction: %w", err)
+		return fmt.Errorf("failed to set created task assignee: %w", err)
 	}
-	defer tx.Rollback()
+	return nil
+}
 
-	if _, err := tx.ExecContext(ctx, `
-		DELETE FROM assignee_mappings
-		WHERE chat_id = $1 AND todoist_project_id = $2
-	`, chatID, projectID); err != nil {
-		return fmt.Errorf("failed to clear assignee mappings: %w", err)
+// ListTasksToNudge returns created tasks without an assignee that were created
+// between createdAfter and createdBefore and were not nudged yet, oldest first
+func (m *Manager) ListTasksToNudge(ctx context.Context, createdAfter, createdBefore time.Time, limit int) ([]TaskNudge, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT t.id, t.session_id, s.chat_id, s.owner_id, p.username, t.todoist_task_id, COALESCE(t.title, ''), t.url, t.created_at
+		FROM created_tasks t
+		JOIN sessions s ON s.id = t.session_id
+		LEFT JOIN session_participants p ON p.session_id = s.id AND p.user_id = s.owner_id
+		LEFT JOIN chat_settings cs ON cs.bot_id = s.bot_id AND cs.chat_id = s.chat_id
+		WHERE s.bot_id = $1
+		  AND cs.inactive_since IS NULL
+		  AND t.nudged_at IS NULL
+		  AND COALESCE(t.assignee_todoist_id, '') = ''
+		  AND t.created_at > $2 AND t.created_at < $3
+		ORDER BY t.created_at
+		LIMIT $4
+	`, m.botID, createdAfter, createdBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks to nudge: %w", err)
 	}
+	defer rows.Close()
 
-	for _, mapping := range mappings {
+	var nudges []TaskNudge
+	for rows.Next() {
+		var n TaskNudge
+		if err := rows.Scan(&n.CreatedTaskID, &n.SessionID, &n.ChatID, &n.OwnerID, &n.OwnerUsername, &n.TodoistTaskID, &n.Title, &n.URL, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan task to nudge: %w", err)
+		}
+		nudges = append(nudges, n)
+	}
+	return nudges, rows.Err()
+}
+
+// MarkTaskNudged records that the chat was reminded about a created task
+func (m *Manager) MarkTaskNudged(ctx context.Context, id int) error {
+	if _, err := m.db.ExecContext(ctx, `UPDATE created_tasks SET nudged_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark task nudged: %w", err)
+	}
+	return nil
+}
+
+// SaveDecisionSummary stores the summary of a discussion that ended without a
+// task; a newer summary of the same session replaces the old one
+func (m *Manager) SaveDecisionSummary(ctx context.Context, sessionID int, chatID int64, text string) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO decision_summaries (session_id, chat_id, text)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (session_id) DO UPDATE
+		SET text = EXCLUDED.text, created_at = NOW()
+	`, sessionID, chatID, text)
+	if err != nil {
+		return fmt.Errorf("failed to save decision summary: %w", err)
+	}
+	return nil
+}
+
+// SavePendingEdit remembers that a reply to the bot message is an edit of the
+// session draft
+func (m *Manager) SavePendingEdit(ctx context.Context, chatID int64, messageID int, sessionID int) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO pending_edits (bot_id, chat_id, message_id, session_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id, message_id) DO UPDATE
+		SET session_id = EXCLUDED.session_id, created_at = NOW()
+	`, m.botID, chatID, messageID, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to save pending edit: %w", err)
+	}
+	return nil
+}
+
+// GetPendingEdit returns the session whose draft a reply to the bot message
+// edits, 0 if the message does not ask for an edit
+func (m *Manager) GetPendingEdit(ctx context.Context, chatID int64, messageID int) (int, error) {
+	var sessionID int
+	err := m.db.QueryRowContext(ctx, `
+		SELECT session_id
+		FROM pending_edits
+		WHERE bot_id = $1 AND chat_id = $2 AND message_id = $3
+	`, m.botID, chatID, messageID).Scan(&sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get pending edit: %w", err)
+	}
+	return sessionID, nil
+}
+
+// DeletePendingEdit forgets the edit request of the bot message
+func (m *Manager) DeletePendingEdit(ctx context.Context, chatID int64, messageID int) error {
+	_, err := m.db.ExecContext(ctx, `
+		DELETE FROM pending_edits
+		WHERE bot_id = $1 AND chat_id = $2 AND message_id = $3
+	`, m.botID, chatID, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pending edit: %w", err)
+	}
+	return nil
+}
+
+// DeleteStalePendingEdits forgets edit requests sent before createdBefore or
+// belonging to closed sessions, whose replies would edit nothing
+func (m *Manager) DeleteStalePendingEdits(ctx context.Context, createdBefore time.Time) (int64, error) {
+	result, err := m.db.ExecContext(ctx, `
+		DELETE FROM pending_edits p
+		USING sessions s
+		WHERE s.id = p.session_id
+		  AND p.bot_id = $1
+		  AND (p.created_at < $2 OR s.status = 'closed')
+	`, m.botID, createdBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale pending edits: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SaveAuditEdit saves an audit edit record
+func (m *Manager) SaveAuditEdit(ctx context.Context, sessionID int, instructionText string, diffJSON []byte) error {
+	query := `
+		INSERT INTO audit_edits (session_id, instruction_text, diff_json)
+		VALUES ($1, $2, $3)
+	`
+	_, err := m.db.ExecContext(ctx, query, sessionID, instructionText, diffJSON)
+	if err != nil {
+		return fmt.Errorf("failed to save audit edit: %w", err)
+	}
+
+	return nil
+}
+
+func (m *Manager) ReplaceAssigneeMappings(ctx context.Context, chatID int64, projectID string, mappings []AssigneeMapping) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM assignee_mappings
+		WHERE chat_id = $1 AND todoist_project_id = $2
+	`, chatID, projectID); err != nil {
+		return fmt.Errorf("failed to clear assignee mappings: %w", err)
+	}
+
+	for _, mapping := range mappings {
 		if _, err := tx.ExecContext(ctx, `
 			INSERT INTO assignee_mappings (
 				chat_id, todoist_project_id, alias_raw, alias_normalized,
@@ -633,6 +1213,40 @@ func (m *Manager) ReplaceAssigneeMappings(ctx context.Context, chatID int64, pro
 	return nil
 }
 
+// SaveAssigneeMapping adds one alias of a Todoist user to the mapping of a
+// chat project, replacing the user the alias pointed to before
+func (m *Manager) SaveAssigneeMapping(ctx context.Context, mapping AssigneeMapping) error {
+	if err := m.EnsureChatExists(ctx, mapping.ChatID); err != nil {
+		return err
+	}
+
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO assignee_mappings (
+			chat_id, todoist_project_id, alias_raw, alias_normalized,
+			todoist_user_id, todoist_user_name, todoist_user_email, created_at, updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW(), NOW())
+		ON CONFLICT (chat_id, todoist_project_id, alias_normalized) DO UPDATE
+		SET alias_raw = EXCLUDED.alias_raw,
+			todoist_user_id = EXCLUDED.todoist_user_id,
+			todoist_user_name = EXCLUDED.todoist_user_name,
+			todoist_user_email = EXCLUDED.todoist_user_email,
+			updated_at = NOW()
+	`,
+		mapping.ChatID,
+		mapping.TodoistProjectID,
+		mapping.AliasRaw,
+		mapping.AliasNormalized,
+		mapping.TodoistUserID,
+		mapping.TodoistUserName,
+		mapping.TodoistUserEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save assignee mapping: %w", err)
+	}
+	return nil
+}
+
 func (m *Manager) GetAssigneeMappings(ctx context.Context, chatID int64, projectID string) ([]AssigneeMapping, error) {
 	rows, err := m.db.QueryContext(ctx, `
 		SELECT chat_id, todoist_project_id, alias_raw, alias_normalized,
@@ -671,3 +1285,1163 @@ func (m *Manager) GetAssigneeMappings(ctx context.Context, chatID int64, project
 
 	return mappings, nil
 }
+
+// ReserveTaskAnalysis counts an analysis unless the chat or the user (across
+// all chats) already started their limit of analyses since the given time; a
+// limit of 0 is unlimited. It returns the ID of the counted analysis, 0 when a
+// limit is reached, and the usage before it. Reservations of the same chat or
+// user wait for each other, so concurrent ones cannot exceed a limit together.
+func (m *Manager) ReserveTaskAnalysis(ctx context.Context, chatID, userID int64, sessionID int, since time.Time, chatLimit, userLimit int) (id, chatCount, userCount int, err error) {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Chat locks are always taken before user locks, so waiting transactions cannot deadlock
+	for _, key := range []string{fmt.Sprintf("task_analyses:chat:%d", chatID), fmt.Sprintf("task_analyses:user:%d", userID)} {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, key); err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to lock task quota: %w", err)
+		}
+	}
+
+	// The check and the increment are one statement, which sees every
+	// analysis committed before the locks were granted
+	err = tx.QueryRowContext(ctx, `
+		WITH usage AS (
+			SELECT
+				COUNT(*) FILTER (WHERE chat_id = $1) AS chat_count,
+				COUNT(*) FILTER (WHERE user_id = $2) AS user_count
+			FROM task_analyses
+			WHERE created_at >= $3 AND (chat_id = $1 OR user_id = $2)
+		), counted AS (
+			INSERT INTO task_analyses (chat_id, user_id, session_id)
+			SELECT $1, $2, $4 FROM usage
+			WHERE ($5 = 0 OR chat_count < $5) AND ($6 = 0 OR user_count < $6)
+			RETURNING id
+		)
+		SELECT COALESCE((SELECT id FROM counted), 0), chat_count, user_count FROM usage
+	`, chatID, userID, since, sessionID, chatLimit, userLimit).Scan(&id, &chatCount, &userCount)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to reserve task analysis: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to commit task analysis: %w", err)
+	}
+	return id, chatCount, userCount, nil
+}
+
+// ReleaseTaskAnalysis uncounts a reserved analysis that did not produce a draft
+func (m *Manager) ReleaseTaskAnalysis(ctx context.Context, id int) error {
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM task_analyses WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to release task analysis: %w", err)
+	}
+	return nil
+}
+
+// CountTaskAnalyses returns how many analyses the chat and the user (across all chats) started since the given time
+func (m *Manager) CountTaskAnalyses(ctx context.Context, chatID, userID int64, since time.Time) (chatCount, userCount int, err error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE chat_id = $1),
+			COUNT(*) FILTER (WHERE user_id = $2)
+		FROM task_analyses
+		WHERE created_at >= $3 AND (chat_id = $1 OR user_id = $2)
+	`
+	err = m.db.QueryRowContext(ctx, query, chatID, userID, since).Scan(&chatCount, &userCount)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count task analyses: %w", err)
+	}
+	return chatCount, userCount, nil
+}
+
+// SetQuotaExempt lifts or restores task quotas for a chat
+func (m *Manager) SetQuotaExempt(ctx context.Context, chatID int64, exempt bool) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, quota_exempt, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET quota_exempt = $3, updated_at = $4
+	`
+	_, err := m.db.ExecContext(ctx, query, m.botID, chatID, exempt, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to set quota exemption: %w", err)
+	}
+	return nil
+}
+
+// IsQuotaExempt reports whether an admin lifted task quotas for a chat
+func (m *Manager) IsQuotaExempt(ctx context.Context, chatID int64) (bool, error) {
+	query := `
+		SELECT quota_exempt
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var exempt bool
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&exempt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get quota exemption: %w", err)
+	}
+	return exempt, nil
+}
+
+// SetVoicePreviews enables or disables voice notes for task previews in a chat
+func (m *Manager) SetVoicePreviews(ctx context.Context, chatID int64, enabled bool) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, voice_previews, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET voice_previews = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, enabled, time.Now()); err != nil {
+		return fmt.Errorf("failed to set voice previews: %w", err)
+	}
+	return nil
+}
+
+// VoicePreviewsEnabled reports whether task previews in a chat are also voiced
+func (m *Manager) VoicePreviewsEnabled(ctx context.Context, chatID int64) (bool, error) {
+	query := `
+		SELECT voice_previews
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var enabled bool
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get voice previews setting: %w", err)
+	}
+	return enabled, nil
+}
+
+// SetReactionAck enables or disables emoji acknowledgements of captured messages
+func (m *Manager) SetReactionAck(ctx context.Context, chatID int64, enabled bool) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, reaction_ack, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET reaction_ack = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, enabled, time.Now()); err != nil {
+		return fmt.Errorf("failed to set reaction acknowledgements: %w", err)
+	}
+	return nil
+}
+
+// ReactionAckEnabled reports whether captured messages get an emoji reaction
+func (m *Manager) ReactionAckEnabled(ctx context.Context, chatID int64) (bool, error) {
+	query := `
+		SELECT reaction_ack
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var enabled bool
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get reaction acknowledgements setting: %w", err)
+	}
+	return enabled, nil
+}
+
+// SetSummonParticipants enables or disables mentioning all participants when a draft is ready
+func (m *Manager) SetSummonParticipants(ctx context.Context, chatID int64, enabled bool) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, summon_participants, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET summon_participants = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, enabled, time.Now()); err != nil {
+		return fmt.Errorf("failed to set participant summoning: %w", err)
+	}
+	return nil
+}
+
+// SummonParticipantsEnabled reports whether participants are mentioned when a draft is ready
+func (m *Manager) SummonParticipantsEnabled(ctx context.Context, chatID int64) (bool, error) {
+	query := `
+		SELECT summon_participants
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var enabled bool
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&enabled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get participant summoning setting: %w", err)
+	}
+	return enabled, nil
+}
+
+// SetUserLanguage stores the reply language a user chose; an empty language
+// goes back to the locale of the user's Telegram client
+func (m *Manager) SetUserLanguage(ctx context.Context, userID int64, language string) error {
+	query := `
+		INSERT INTO user_settings (bot_id, user_id, language, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, user_id) DO UPDATE
+		SET language = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, userID, language, time.Now()); err != nil {
+		return fmt.Errorf("failed to set user language: %w", err)
+	}
+	return nil
+}
+
+// GetUserLanguage returns the reply language a user chose, or an empty string
+func (m *Manager) GetUserLanguage(ctx context.Context, userID int64) (string, error) {
+	query := `
+		SELECT language
+		FROM user_settings
+		WHERE bot_id = $1 AND user_id = $2
+	`
+	var language string
+	err := m.db.QueryRowContext(ctx, query, m.botID, userID).Scan(&language)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get user language: %w", err)
+	}
+	return language, nil
+}
+
+// SetQuietHours stores the quiet hours window of a chat; an empty window turns them off
+func (m *Manager) SetQuietHours(ctx context.Context, chatID int64, window string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, quiet_hours, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET quiet_hours = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, window, time.Now()); err != nil {
+		return fmt.Errorf("failed to set quiet hours: %w", err)
+	}
+	return nil
+}
+
+// GetQuietHours returns the quiet hours window of a chat, or an empty string if none is set
+func (m *Manager) GetQuietHours(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT quiet_hours
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var window string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&window)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get quiet hours: %w", err)
+	}
+	return window, nil
+}
+
+// SetPriorityNames stores the priority display names of a chat; an empty value restores the defaults
+func (m *Manager) SetPriorityNames(ctx context.Context, chatID int64, names string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, priority_names, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET priority_names = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, names, time.Now()); err != nil {
+		return fmt.Errorf("failed to set priority names: %w", err)
+	}
+	return nil
+}
+
+// GetPriorityNames returns the priority display names of a chat, or an empty string if none are set
+func (m *Manager) GetPriorityNames(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT priority_names
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var names string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&names)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get priority names: %w", err)
+	}
+	return names, nil
+}
+
+// SetTaskDefaults stores the task defaults of a chat; an empty value removes them
+func (m *Manager) SetTaskDefaults(ctx context.Context, chatID int64, defaults string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, task_defaults, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET task_defaults = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, defaults, time.Now()); err != nil {
+		return fmt.Errorf("failed to set task defaults: %w", err)
+	}
+	return nil
+}
+
+// GetTaskDefaults returns the task defaults of a chat, or an empty string if none are set
+func (m *Manager) GetTaskDefaults(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT task_defaults
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var defaults string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&defaults)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get task defaults: %w", err)
+	}
+	return defaults, nil
+}
+
+// chatPromptColumns are the chat_settings columns of the prompts a chat may extend
+var chatPromptColumns = map[string]string{
+	"create_task": "create_task_prompt",
+	"edit_task":   "edit_task_prompt",
+}
+
+// SetChatPrompt stores the chat's addition to a create_task or edit_task
+// prompt; an empty text removes it
+func (m *Manager) SetChatPrompt(ctx context.Context, chatID int64, name, text string) error {
+	column, ok := chatPromptColumns[name]
+	if !ok {
+		return fmt.Errorf("prompt %q cannot be set per chat", name)
+	}
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO chat_settings (bot_id, chat_id, %[1]s, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET %[1]s = $3, updated_at = $4
+	`, column)
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, text, time.Now()); err != nil {
+		return fmt.Errorf("failed to set chat prompt: %w", err)
+	}
+	return nil
+}
+
+// GetChatPrompt returns the chat's addition to a prompt, or an empty string if none is set
+func (m *Manager) GetChatPrompt(ctx context.Context, chatID int64, name string) (string, error) {
+	column, ok := chatPromptColumns[name]
+	if !ok {
+		return "", fmt.Errorf("prompt %q cannot be set per chat", name)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`, column)
+	var text string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&text)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get chat prompt: %w", err)
+	}
+	return text, nil
+}
+
+// SetDigestTime stores when digests and nudges are delivered to a chat; an empty value delivers them any time
+func (m *Manager) SetDigestTime(ctx context.Context, chatID int64, clock string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, digest_time, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET digest_time = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, clock, time.Now()); err != nil {
+		return fmt.Errorf("failed to set digest time: %w", err)
+	}
+	return nil
+}
+
+// GetDigestTime returns the digest time of a chat, or an empty string if none is set
+func (m *Manager) GetDigestTime(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT digest_time
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var clock string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&clock)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get digest time: %w", err)
+	}
+	return clock, nil
+}
+
+// SetChatTimezone stores the IANA time zone of a chat; an empty value uses the default zone
+func (m *Manager) SetChatTimezone(ctx context.Context, chatID int64, zone string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, timezone, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET timezone = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, zone, time.Now()); err != nil {
+		return fmt.Errorf("failed to set chat timezone: %w", err)
+	}
+	return nil
+}
+
+// GetChatTimezone returns the time zone of a chat, or an empty string if none is set
+func (m *Manager) GetChatTimezone(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT timezone
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var zone string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&zone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get chat timezone: %w", err)
+	}
+	return zone, nil
+}
+
+// SetChatLanguage stores the language of a chat; an empty language returns
+// the chat to the default one
+func (m *Manager) SetChatLanguage(ctx context.Context, chatID int64, language string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, language, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET language = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, language, time.Now()); err != nil {
+		return fmt.Errorf("failed to set chat language: %w", err)
+	}
+	return nil
+}
+
+// GetChatLanguage returns the language of a chat, or an empty string if none
+// is set. A private chat shares its ID with the user, so there the language
+// the user chose with /language counts as the chat's.
+func (m *Manager) GetChatLanguage(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT COALESCE(
+			NULLIF((SELECT language FROM chat_settings WHERE bot_id = $1 AND chat_id = $2), ''),
+			(SELECT language FROM user_settings WHERE bot_id = $1 AND user_id = $2),
+			''
+		)
+	`
+	var language string
+	if err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&language); err != nil {
+		return "", fmt.Errorf("failed to get chat language: %w", err)
+	}
+	return language, nil
+}
+
+// SetTranscriptMode stores where the discussion transcript goes when a task is created
+func (m *Manager) SetTranscriptMode(ctx context.Context, chatID int64, mode string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, transcript_mode, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET transcript_mode = $3, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, mode, time.Now()); err != nil {
+		return fmt.Errorf("failed to set transcript mode: %w", err)
+	}
+	return nil
+}
+
+// GetTranscriptMode returns the transcript mode of a chat, TranscriptOff if none is set
+func (m *Manager) GetTranscriptMode(ctx context.Context, chatID int64) (string, error) {
+	query := `
+		SELECT transcript_mode
+		FROM chat_settings
+		WHERE bot_id = $1 AND chat_id = $2
+	`
+	var mode string
+	err := m.db.QueryRowContext(ctx, query, m.botID, chatID).Scan(&mode)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TranscriptOff, nil
+		}
+		return TranscriptOff, fmt.Errorf("failed to get transcript mode: %w", err)
+	}
+	return mode, nil
+}
+
+// MessageActivity counts the discussion messages of a chat since the given
+// time by weekday and hour in the given time zone
+func (m *Manager) MessageActivity(ctx context.Context, chatID int64, since time.Time, timezone string) ([]activity.Bucket, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT EXTRACT(DOW FROM m.ts AT TIME ZONE $4)::int AS weekday,
+		       EXTRACT(HOUR FROM m.ts AT TIME ZONE $4)::int AS hour,
+		       COUNT(*)
+		FROM messages m
+		JOIN sessions s ON s.id = m.session_id
+		WHERE s.bot_id = $1 AND m.chat_id = $2 AND m.ts >= $3
+		GROUP BY weekday, hour
+	`, m.botID, chatID, since, timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query message activity: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []activity.Bucket
+	for rows.Next() {
+		var b activity.Bucket
+		var weekday int
+		if err := rows.Scan(&weekday, &b.Hour, &b.Messages); err != nil {
+			return nil, fmt.Errorf("failed to scan message activity: %w", err)
+		}
+		b.Weekday = time.Weekday(weekday)
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// DeferMessage holds a non-urgent message until the chat's quiet hours end
+func (m *Manager) DeferMessage(ctx context.Context, chatID int64, text string) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO deferred_messages (bot_id, chat_id, text)
+		VALUES ($1, $2, $3)
+	`, m.botID, chatID, text)
+	if err != nil {
+		return fmt.Errorf("failed to defer message: %w", err)
+	}
+	return nil
+}
+
+// ListDeferredChats returns chats that have undelivered deferred messages
+func (m *Manager) ListDeferredChats(ctx context.Context) ([]int64, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT DISTINCT chat_id
+		FROM deferred_messages d
+		WHERE d.bot_id = $1 AND d.delivered_at IS NULL
+		  AND NOT EXISTS (
+			SELECT 1 FROM chat_settings s
+			WHERE s.bot_id = d.bot_id AND s.chat_id = d.chat_id AND s.inactive_since IS NOT NULL
+		  )
+	`, m.botID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chats with deferred messages: %w", err)
+	}
+	defer rows.Close()
+
+	var chatIDs []int64
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("failed to scan chat id: %w", err)
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+// TakeDeferredMessages marks the deferred messages of a chat delivered and
+// returns their texts, oldest first
+func (m *Manager) TakeDeferredMessages(ctx context.Context, chatID int64) ([]string, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		WITH taken AS (
+			UPDATE deferred_messages
+			SET delivered_at = NOW()
+			WHERE bot_id = $1 AND chat_id = $2 AND delivered_at IS NULL
+			RETURNING id, text
+		)
+		SELECT text FROM taken ORDER BY id
+	`, m.botID, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take deferred messages: %w", err)
+	}
+	defer rows.Close()
+
+	var texts []string
+	for rows.Next() {
+		var text string
+		if err := rows.Scan(&text); err != nil {
+			return nil, fmt.Errorf("failed to scan deferred message: %w", err)
+		}
+		texts = append(texts, text)
+	}
+	return texts, rows.Err()
+}
+
+// SetChatInactive records that the bot can no longer write to a chat, or that it can again
+func (m *Manager) SetChatInactive(ctx context.Context, chatID int64, inactive bool) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_settings (bot_id, chat_id, inactive_since, updated_at)
+		VALUES ($1, $2, CASE WHEN $3 THEN NOW() END, $4)
+		ON CONFLICT (bot_id, chat_id) DO UPDATE
+		SET inactive_since = CASE WHEN $3 THEN COALESCE(chat_settings.inactive_since, NOW()) END, updated_at = $4
+	`
+	if _, err := m.db.ExecContext(ctx, query, m.botID, chatID, inactive, time.Now()); err != nil {
+		return fmt.Errorf("failed to set chat activity: %w", err)
+	}
+	return nil
+}
+
+// AddChatNotifier configures a notifier for a chat and returns its ID
+func (m *Manager) AddChatNotifier(ctx context.Context, chatID int64, kind, target, secret string) (int, error) {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return 0, err
+	}
+
+	var id int
+	err := m.db.QueryRowContext(ctx, `
+		INSERT INTO chat_notifiers (bot_id, chat_id, kind, target, secret)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, m.botID, chatID, kind, target, secret).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add chat notifier: %w", err)
+	}
+	return id, nil
+}
+
+// ListChatNotifiers returns the notifiers of a chat, oldest first
+func (m *Manager) ListChatNotifiers(ctx context.Context, chatID int64) ([]ChatNotifier, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT id, chat_id, kind, target, secret, created_at
+		FROM chat_notifiers
+		WHERE bot_id = $1 AND chat_id = $2
+		ORDER BY id
+	`, m.botID, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chat notifiers: %w", err)
+	}
+	defer rows.Close()
+
+	var notifiers []ChatNotifier
+	for rows.Next() {
+		var n ChatNotifier
+		if err := rows.Scan(&n.ID, &n.ChatID, &n.Kind, &n.Target, &n.Secret, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan chat notifier: %w", err)
+		}
+		notifiers = append(notifiers, n)
+	}
+	return notifiers, rows.Err()
+}
+
+// RemoveChatNotifier deletes a notifier of a chat; removed is false if the chat has no such notifier
+func (m *Manager) RemoveChatNotifier(ctx context.Context, chatID int64, id int) (bool, error) {
+	res, err := m.db.ExecContext(ctx, `
+		DELETE FROM chat_notifiers
+		WHERE bot_id = $1 AND chat_id = $2 AND id = $3
+	`, m.botID, chatID, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to remove chat notifier: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to remove chat notifier: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// InstallationID returns the random ID of this installation, creating it on
+// first use. It is shared by all bots and identifies nothing but the database.
+func (m *Manager) InstallationID(ctx context.Context) (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate installation id: %w", err)
+	}
+	if _, err := m.db.ExecContext(ctx, `
+		INSERT INTO installation (id) VALUES ($1)
+		ON CONFLICT (singleton) DO NOTHING
+	`, hex.EncodeToString(b[:])); err != nil {
+		return "", fmt.Errorf("failed to create installation id: %w", err)
+	}
+
+	var id string
+	if err := m.db.QueryRowContext(ctx, `SELECT id FROM installation`).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to get installation id: %w", err)
+	}
+	return id, nil
+}
+
+// chatTables lists tables keyed by Telegram chat ID that follow a chat when
+// a group is upgraded to a supergroup
+var chatTables = []string{
+	"chat_settings", "sessions", "messages", "assignee_mappings", "task_analyses",
+	"chat_plans", "feature_usage", "preview_messages", "deferred_messages",
+	"chat_notifiers", "ai_usage", "decision_summaries", "pending_edits",
+	"ai_jobs",
+}
+
+// MigrateChat moves all data of a group to the supergroup it was upgraded to.
+// Chat data is shared between bots, so rows of every bot are moved.
+func (m *Manager) MigrateChat(ctx context.Context, fromChatID, toChatID int64) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to start transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO chats (id) VALUES ($1) ON CONFLICT (id) DO NOTHING`, toChatID); err != nil {
+		return fmt.Errorf("failed to create migrated chat: %w", err)
+	}
+	for _, table := range chatTables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET chat_id = $2 WHERE chat_id = $1`, table), fromChatID, toChatID); err != nil {
+			return fmt.Errorf("failed to migrate %s: %w", table, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit chat migration: %w", err)
+	}
+	return nil
+}
+
+// GetChatPlan returns the billing plan of a chat, or an empty string if none is assigned
+func (m *Manager) GetChatPlan(ctx context.Context, chatID int64) (string, error) {
+	var plan string
+	err := m.db.QueryRowContext(ctx, `SELECT plan FROM chat_plans WHERE chat_id = $1`, chatID).Scan(&plan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get chat plan: %w", err)
+	}
+	return plan, nil
+}
+
+// SetChatPlan assigns a billing plan to a chat
+func (m *Manager) SetChatPlan(ctx context.Context, chatID int64, plan string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO chat_plans (chat_id, plan, updated_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (chat_id) DO UPDATE
+		SET plan = $2, updated_at = NOW()
+	`
+	if _, err := m.db.ExecContext(ctx, query, chatID, plan); err != nil {
+		return fmt.Errorf("failed to set chat plan: %w", err)
+	}
+	return nil
+}
+
+// GetFeatureUsage returns how many times a chat used a feature in a billing period
+func (m *Manager) GetFeatureUsage(ctx context.Context, chatID int64, feature, period string) (int, error) {
+	query := `
+		SELECT used
+		FROM feature_usage
+		WHERE chat_id = $1 AND feature = $2 AND period = $3
+	`
+	var used int
+	err := m.db.QueryRowContext(ctx, query, chatID, feature, period).Scan(&used)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get feature usage: %w", err)
+	}
+	return used, nil
+}
+
+// IncrementFeatureUsage counts one use of a feature in a billing period
+func (m *Manager) IncrementFeatureUsage(ctx context.Context, chatID int64, feature, period string) error {
+	if err := m.EnsureChatExists(ctx, chatID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO feature_usage (chat_id, feature, period, used)
+		VALUES ($1, $2, $3, 1)
+		ON CONFLICT (chat_id, feature, period) DO UPDATE
+		SET used = feature_usage.used + 1
+	`
+	if _, err := m.db.ExecContext(ctx, query, chatID, feature, period); err != nil {
+		return fmt.Errorf("failed to increment feature usage: %w", err)
+	}
+	return nil
+}
+
+// HeartbeatShardInstance registers an instance or refreshes its heartbeat and URL
+func (m *Manager) HeartbeatShardInstance(ctx context.Context, instance shard.Instance) error {
+	query := `
+		INSERT INTO shard_instances (instance_id, url, heartbeat_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (instance_id) DO UPDATE
+		SET url = $2, heartbeat_at = NOW()
+	`
+	if _, err := m.db.ExecContext(ctx, query, instance.ID, instance.URL); err != nil {
+		return fmt.Errorf("failed to heartbeat shard instance: %w", err)
+	}
+	return nil
+}
+
+// ListShardInstances returns instances whose heartbeat is not older than aliveSince
+func (m *Manager) ListShardInstances(ctx context.Context, aliveSince time.Time) ([]shard.Instance, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT instance_id, url
+		FROM shard_instances
+		WHERE heartbeat_at >= $1
+		ORDER BY instance_id
+	`, aliveSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shard instances: %w", err)
+	}
+	defer rows.Close()
+
+	var instances []shard.Instance
+	for rows.Next() {
+		var instance shard.Instance
+		if err := rows.Scan(&instance.ID, &instance.URL); err != nil {
+			return nil, fmt.Errorf("failed to scan shard instance: %w", err)
+		}
+		instances = append(instances, instance)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate shard instances: %w", err)
+	}
+	return instances, nil
+}
+
+// RemoveShardInstance removes an instance from the membership table
+func (m *Manager) RemoveShardInstance(ctx context.Context, instanceID string) error {
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM shard_instances WHERE instance_id = $1`, instanceID); err != nil {
+		return fmt.Errorf("failed to remove shard instance: %w", err)
+	}
+	return nil
+}
+
+// GetActivePrompt returns the latest stored version of a prompt, or nil if
+// the prompt was never edited
+func (m *Manager) GetActivePrompt(ctx context.Context, name string) (*ai.Prompt, error) {
+	prompt := ai.Prompt{Name: name}
+	err := m.db.QueryRowContext(ctx, `
+		SELECT version, text, created_at
+		FROM ai_prompts
+		WHERE name = $1
+		ORDER BY version DESC
+		LIMIT 1
+	`, name).Scan(&prompt.Version, &prompt.Text, &prompt.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get prompt: %w", err)
+	}
+	return &prompt, nil
+}
+
+// ListPromptVersions returns stored versions of a prompt, newest first
+func (m *Manager) ListPromptVersions(ctx context.Context, name string) ([]ai.Prompt, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT version, text, created_at
+		FROM ai_prompts
+		WHERE name = $1
+		ORDER BY version DESC
+	`, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prompt versions: %w", err)
+	}
+	defer rows.Close()
+
+	var prompts []ai.Prompt
+	for rows.Next() {
+		prompt := ai.Prompt{Name: name}
+		if err := rows.Scan(&prompt.Version, &prompt.Text, &prompt.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan prompt version: %w", err)
+		}
+		prompts = append(prompts, prompt)
+	}
+	return prompts, rows.Err()
+}
+
+// SavePrompt stores text as the next version of a prompt
+func (m *Manager) SavePrompt(ctx context.Context, name, text string) (*ai.Prompt, error) {
+	prompt := ai.Prompt{Name: name, Text: text}
+	err := m.db.QueryRowContext(ctx, `
+		INSERT INTO ai_prompts (name, version, text)
+		SELECT $1, COALESCE(MAX(version), 0) + 1, $2
+		FROM ai_prompts
+		WHERE name = $1
+		RETURNING version, created_at
+	`, name, text).Scan(&prompt.Version, &prompt.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save prompt: %w", err)
+	}
+	return &prompt, nil
+}
+
+// SaveAICall records one request to an AI provider
+func (m *Manager) SaveAICall(ctx context.Context, call ai.Call) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO ai_calls (provider, model, prompt_hash, latency_ms, prompt_tokens, completion_tokens, total_tokens, output, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, call.Provider, call.Model, call.PromptHash, call.LatencyMs, call.PromptTokens, call.CompletionTokens,
+		call.TotalTokens, call.Output, call.Error, call.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save AI call: %w", err)
+	}
+	return nil
+}
+
+// PruneAICalls deletes AI calls created before olderThan, unless it is zero,
+// and all but the newest keep calls, unless keep is zero
+func (m *Manager) PruneAICalls(ctx context.Context, olderThan time.Time, keep int) (int64, error) {
+	var deleted int64
+	if !olderThan.IsZero() {
+		result, err := m.db.ExecContext(ctx, `DELETE FROM ai_calls WHERE created_at < $1`, olderThan)
+		if err != nil {
+			return 0, fmt.Errorf("failed to prune old AI calls: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	if keep > 0 {
+		result, err := m.db.ExecContext(ctx, `
+			DELETE FROM ai_calls
+			WHERE id <= (SELECT id FROM ai_calls ORDER BY id DESC OFFSET $1 LIMIT 1)
+		`, keep)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to prune excess AI calls: %w", err)
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	return deleted, nil
+}
+
+// SaveAIUsage records the tokens of one AI request; requests made outside a
+// chat or discussion are stored without it
+func (m *Manager) SaveAIUsage(ctx context.Context, usage ai.Usage) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO ai_usage (chat_id, session_id, provider, model, prompt_tokens, completion_tokens, created_at)
+		VALUES (NULLIF($1, 0), NULLIF($2, 0), $3, $4, $5, $6, $7)
+	`, usage.ChatID, usage.SessionID, usage.Provider, usage.Model, usage.PromptTokens, usage.CompletionTokens, usage.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save AI usage: %w", err)
+	}
+	return nil
+}
+
+// AIUsageByChat sums the tokens of AI requests since the given time per chat
+// and model; chat 0 collects requests made outside chats
+func (m *Manager) AIUsageByChat(ctx context.Context, since time.Time) ([]ai.ChatUsage, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT COALESCE(chat_id, 0), model, COUNT(*), SUM(prompt_tokens), SUM(completion_tokens)
+		FROM ai_usage
+		WHERE created_at >= $1
+		GROUP BY COALESCE(chat_id, 0), model
+		ORDER BY COALESCE(chat_id, 0), model
+	`, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query AI usage: %w", err)
+	}
+	defer rows.Close()
+
+	var usage []ai.ChatUsage
+	for rows.Next() {
+		var u ai.ChatUsage
+		if err := rows.Scan(&u.ChatID, &u.Model, &u.Requests, &u.PromptTokens, &u.CompletionTokens); err != nil {
+			return nil, fmt.Errorf("failed to scan AI usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+	return usage, rows.Err()
+}
+
+// GetAnalysisCache returns the cached AI analysis of a session if it was made
+// for the same transcript hash after since. It returns nil when there is none.
+func (m *Manager) GetAnalysisCache(ctx context.Context, sessionID int, hash string, since time.Time) ([]byte, error) {
+	var payload []byte
+	err := m.db.QueryRowContext(ctx, `
+		SELECT analysis_cache
+		FROM draft_tasks
+		WHERE session_id = $1 AND analysis_hash = $2 AND analysis_cached_at >= $3 AND analysis_cache IS NOT NULL
+	`, sessionID, hash, since).Scan(&payload)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get analysis cache: %w", err)
+	}
+	return payload, nil
+}
+
+// SaveAnalysisCache stores the AI analysis next to the session draft. The
+// draft must already exist; edits of the draft keep the cached analysis.
+func (m *Manager) SaveAnalysisCache(ctx context.Context, sessionID int, hash string, payload []byte) error {
+	_, err := m.db.ExecContext(ctx, `
+		UPDATE draft_tasks
+		SET analysis_hash = $2, analysis_cache = $3, analysis_cached_at = NOW()
+		WHERE session_id = $1
+	`, sessionID, hash, payload)
+	if err != nil {
+		return fmt.Errorf("failed to save analysis cache: %w", err)
+	}
+	return nil
+}
+
+// RecordPreviewMessage remembers a sent draft preview with live buttons
+func (m *Manager) RecordPreviewMessage(ctx context.Context, sessionID int, chatID int64, messageID int, text string) error {
+	_, err := m.db.ExecContext(ctx, `
+		INSERT INTO preview_messages (session_id, chat_id, message_id, text)
+		VALUES ($1, $2, $3, $4)
+	`, sessionID, chatID, messageID, text)
+	if err != nil {
+		return fmt.Errorf("failed to record preview message: %w", err)
+	}
+	return nil
+}
+
+// ListStalePreviews returns previews that still have buttons although their
+// session is closed or they were sent before createdBefore
+func (m *Manager) ListStalePreviews(ctx context.Context, createdBefore time.Time, limit int) ([]PreviewMessage, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT p.id, p.session_id, p.chat_id, p.message_id, p.text, p.created_at, s.status = 'closed'
+		FROM preview_messages p
+		JOIN sessions s ON s.id = p.session_id
+		WHERE s.bot_id = $1
+		  AND p.cleaned_at IS NULL
+		  AND (s.status = 'closed' OR p.created_at < $2)
+		ORDER BY p.created_at
+		LIMIT $3
+	`, m.botID, createdBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stale previews: %w", err)
+	}
+	defer rows.Close()
+
+	var previews []PreviewMessage
+	for rows.Next() {
+		var p PreviewMessage
+		if err := rows.Scan(&p.ID, &p.SessionID, &p.ChatID, &p.MessageID, &p.Text, &p.CreatedAt, &p.SessionClosed); err != nil {
+			return nil, fmt.Errorf("failed to scan preview message: %w", err)
+		}
+		previews = append(previews, p)
+	}
+	return previews, rows.Err()
+}
+
+// MarkPreviewCleaned records that the buttons of a preview were removed
+func (m *Manager) MarkPreviewCleaned(ctx context.Context, id int) error {
+	if _, err := m.db.ExecContext(ctx, `UPDATE preview_messages SET cleaned_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to mark preview cleaned: %w", err)
+	}
+	return nil
+}
+
+// SaveAIJob stores a queued AI job until DeleteAIJob and returns its ID
+func (m *Manager) SaveAIJob(ctx context.Context, job AIJob) (int, error) {
+	var id int
+	err := m.db.QueryRowContext(ctx, `
+		INSERT INTO ai_jobs (bot_id, kind, chat_id, session_id, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, m.botID, job.Kind, job.ChatID, job.SessionID, job.Payload).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save AI job: %w", err)
+	}
+	return id, nil
+}
+
+// DeleteAIJob forgets a stored AI job once it finished or was canceled
+func (m *Manager) DeleteAIJob(ctx context.Context, id int) error {
+	if _, err := m.db.ExecContext(ctx, `DELETE FROM ai_jobs WHERE id = $1 AND bot_id = $2`, id, m.botID); err != nil {
+		return fmt.Errorf("failed to delete AI job: %w", err)
+	}
+	return nil
+}
+
+// ListAIJobs returns the stored AI jobs of the bot in the order they were queued
+func (m *Manager) ListAIJobs(ctx context.Context) ([]AIJob, error) {
+	rows, err := m.db.QueryContext(ctx, `
+		SELECT id, kind, chat_id, session_id, payload, enqueued_at
+		FROM ai_jobs
+		WHERE bot_id = $1
+		ORDER BY id
+	`, m.botID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AI jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var stored []AIJob
+	for rows.Next() {
+		var job AIJob
+		if err := rows.Scan(&job.ID, &job.Kind, &job.ChatID, &job.SessionID, &job.Payload, &job.EnqueuedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan AI job: %w", err)
+		}
+		stored = append(stored, job)
+	}
+	return stored, rows.Err()
+}
@@ -13,6 +13,27 @@ CREATE TABLE IF NOT EXISTS chat_settings (
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
 );
 
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS quota_exempt BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS bot_id TEXT NOT NULL DEFAULT 'default';
+
+-- Send a voice note with every task preview (needs TTS_PROVIDER)
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS voice_previews BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- React to captured discussion messages instead of staying silent
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS reaction_ack BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Several bots may serve the same chat, so settings are keyed by bot and chat
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'chat_settings_bot_chat_pkey') THEN
+        ALTER TABLE chat_settings DROP CONSTRAINT IF EXISTS chat_settings_pkey;
+        ALTER TABLE chat_settings ADD CONSTRAINT chat_settings_bot_chat_pkey PRIMARY KEY (bot_id, chat_id);
+    END IF;
+END $$;
+
 -- Create sessions table
 CREATE TABLE IF NOT EXISTS sessions (
     id SERIAL PRIMARY KEY,
@@ -25,6 +46,10 @@ CREATE TABLE IF NOT EXISTS sessions (
 CREATE INDEX IF NOT EXISTS sessions_chat_id_idx ON sessions(chat_id);
 CREATE INDEX IF NOT EXISTS sessions_status_idx ON sessions(status);
 
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS bot_id TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS sessions_bot_chat_idx ON sessions(bot_id, chat_id);
+
 -- Create messages table
 CREATE TABLE IF NOT EXISTS messages (
     id SERIAL PRIMARY KEY,
@@ -42,7 +67,8 @@ CREATE INDEX IF NOT EXISTS messages_session_id_idx ON messages(session_id);
 CREATE INDEX IF NOT EXISTS messages_ts_idx ON messages(ts);
 
 ALTER TABLE messages
-    ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]'::jsonb;
+    ADD COLUMN IF NOT EXISTS links JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ADD COLUMN IF NOT EXISTS bot_id TEXT NOT NULL DEFAULT 'default';
 
 -- Create draft_tasks table
 CREATE TABLE IF NOT EXISTS draft_tasks (
@@ -221,3 +247,309 @@ CREATE TABLE IF NOT EXISTS assignee_mappings (
     PRIMARY KEY (chat_id, todoist_project_id, alias_normalized)
 );
 CREATE INDEX IF NOT EXISTS assignee_mappings_project_idx ON assignee_mappings(chat_id, todoist_project_id);
+
+-- Create task_analyses table (usage counter for per-chat and per-user quotas)
+CREATE TABLE IF NOT EXISTS task_analyses (
+    id SERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id),
+    user_id BIGINT NOT NULL,
+    session_id INTEGER REFERENCES sessions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS task_analyses_chat_idx ON task_analyses(chat_id, created_at);
+CREATE INDEX IF NOT EXISTS task_analyses_user_idx ON task_analyses(user_id, created_at);
+
+-- Billing plans (only used when BILLING_ENABLED=true)
+CREATE TABLE IF NOT EXISTS chat_plans (
+    chat_id BIGINT PRIMARY KEY REFERENCES chats(id),
+    plan TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS feature_usage (
+    chat_id BIGINT NOT NULL REFERENCES chats(id),
+    feature TEXT NOT NULL,
+    period TEXT NOT NULL,
+    used INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (chat_id, feature, period)
+);
+
+-- Bot instances taking part in chat sharding (only used when SHARD_INSTANCE_ID is set)
+CREATE TABLE IF NOT EXISTS shard_instances (
+    instance_id TEXT PRIMARY KEY,
+    heartbeat_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Base URL other instances forward the updates of this instance's chats to
+ALTER TABLE shard_instances
+    ADD COLUMN IF NOT EXISTS url TEXT NOT NULL DEFAULT '';
+
+-- Edited versions of named AI prompts; the latest version of a name is active
+CREATE TABLE IF NOT EXISTS ai_prompts (
+    name TEXT NOT NULL,
+    version INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (name, version)
+);
+
+-- Cached AI analysis of a session transcript, reused when /create_task is re-run unchanged
+ALTER TABLE draft_tasks
+    ADD COLUMN IF NOT EXISTS analysis_hash TEXT,
+    ADD COLUMN IF NOT EXISTS analysis_cache JSONB,
+    ADD COLUMN IF NOT EXISTS analysis_cached_at TIMESTAMP WITH TIME ZONE;
+
+-- Sent draft previews, so their buttons can be removed once the session is closed or expired
+CREATE TABLE IF NOT EXISTS preview_messages (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES sessions(id),
+    chat_id BIGINT NOT NULL,
+    message_id INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    cleaned_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS preview_messages_pending_idx ON preview_messages(created_at) WHERE cleaned_at IS NULL;
+
+-- Daily window (HH:MM-HH:MM) when non-urgent messages are held back; empty means never
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS quiet_hours TEXT NOT NULL DEFAULT '';
+
+-- Non-urgent messages deferred by quiet hours, delivered as one summary afterwards
+CREATE TABLE IF NOT EXISTS deferred_messages (
+    id SERIAL PRIMARY KEY,
+    bot_id TEXT NOT NULL DEFAULT 'default',
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS deferred_messages_pending_idx ON deferred_messages(bot_id, chat_id) WHERE delivered_at IS NULL;
+
+-- Set when Telegram reports the bot was blocked or removed from the chat
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS inactive_since TIMESTAMP WITH TIME ZONE;
+
+-- Chat overrides of priority display names, e.g. "high=Мажор,urgent=Блокер"
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS priority_names TEXT NOT NULL DEFAULT '';
+
+-- Notifier plugins a chat forwards events to (kind: telegram, email, webhook)
+CREATE TABLE IF NOT EXISTS chat_notifiers (
+    id SERIAL PRIMARY KEY,
+    bot_id TEXT NOT NULL DEFAULT 'default',
+    chat_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    target TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS chat_notifiers_chat_idx ON chat_notifiers(bot_id, chat_id);
+
+-- HMAC secret webhook notifiers sign their requests with; empty means unsigned
+ALTER TABLE chat_notifiers
+    ADD COLUMN IF NOT EXISTS secret TEXT NOT NULL DEFAULT '';
+
+-- Random ID of this installation for the opt-in telemetry heartbeat; one row
+CREATE TABLE IF NOT EXISTS installation (
+    singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    id TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Forum topic of a discussion; 0 outside forum supergroups and in the General topic
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS thread_id INTEGER NOT NULL DEFAULT 0;
+
+-- At most one open session per chat topic and bot: concurrent /start_discussion
+-- must not open two. Older duplicates are closed before the index is built.
+UPDATE sessions s
+SET status = 'closed', closed_at = COALESCE(s.closed_at, NOW())
+WHERE s.status = 'open' AND EXISTS (
+    SELECT 1 FROM sessions n
+    WHERE n.bot_id = s.bot_id AND n.chat_id = s.chat_id AND n.thread_id = s.thread_id AND n.status = 'open' AND n.id > s.id
+);
+DROP INDEX IF EXISTS sessions_one_open_per_chat_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_one_open_per_topic_idx ON sessions(bot_id, chat_id, thread_id) WHERE status = 'open';
+
+-- One created task per session: a retried confirm must not record a second task.
+-- Duplicates recorded before the constraint existed are dropped, keeping the first.
+DELETE FROM created_tasks c
+WHERE EXISTS (SELECT 1 FROM created_tasks o WHERE o.session_id = c.session_id AND o.id < c.id);
+CREATE UNIQUE INDEX IF NOT EXISTS created_tasks_session_id_key ON created_tasks(session_id);
+
+-- Distinct people who wrote in a discussion, for /participants and summoning them to review the draft
+CREATE TABLE IF NOT EXISTS session_participants (
+    session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    username TEXT,
+    display_name TEXT,
+    message_count INTEGER NOT NULL DEFAULT 1,
+    first_seen_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, user_id)
+);
+
+-- Open discussions started before the table existed get their participants from saved messages
+INSERT INTO session_participants (session_id, user_id, username, message_count, first_seen_at)
+SELECT m.session_id, m.user_id, MAX(m.username), COUNT(*), MIN(m.ts)
+FROM messages m
+JOIN sessions s ON s.id = m.session_id
+WHERE s.status = 'open' AND m.user_id IS NOT NULL
+GROUP BY m.session_id, m.user_id
+ON CONFLICT (session_id, user_id) DO NOTHING;
+
+-- Mention every participant when the draft preview is ready
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS summon_participants BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Every AI request and its response for offline evaluation; pruned by age and row count
+CREATE TABLE IF NOT EXISTS ai_calls (
+    id BIGSERIAL PRIMARY KEY,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_hash TEXT NOT NULL,
+    latency_ms BIGINT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    output TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ai_calls_created_at_idx ON ai_calls(created_at);
+CREATE INDEX IF NOT EXISTS ai_calls_prompt_hash_idx ON ai_calls(prompt_hash);
+
+-- Tokens spent by each answered AI request, for per-chat accounting and cost reports
+CREATE TABLE IF NOT EXISTS ai_usage (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT,
+    session_id INTEGER REFERENCES sessions(id) ON DELETE SET NULL,
+    provider TEXT NOT NULL,
+    model TEXT NOT NULL,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ai_usage_created_at_idx ON ai_usage(created_at);
+CREATE INDEX IF NOT EXISTS ai_usage_chat_idx ON ai_usage(chat_id, created_at);
+
+-- Per-user settings chosen in a private chat with the bot
+CREATE TABLE IF NOT EXISTS user_settings (
+    bot_id TEXT NOT NULL DEFAULT 'default',
+    user_id BIGINT NOT NULL,
+    language TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bot_id, user_id)
+);
+
+-- Defaults applied to drafts when the discussion does not name them, e.g. "due=+7d priority=medium labels=from-telegram"
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS task_defaults TEXT NOT NULL DEFAULT '';
+
+-- When the chat was reminded that a created task still has no assignee
+ALTER TABLE created_tasks
+    ADD COLUMN IF NOT EXISTS nudged_at TIMESTAMP WITH TIME ZONE;
+
+-- Why a discussion ended without a task, written by AI on request of the session owner
+CREATE TABLE IF NOT EXISTS decision_summaries (
+    session_id INTEGER PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Pinned "discussion in progress" notice, unpinned when the session closes
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS notice_message_id INTEGER;
+
+-- Time of day, HH:MM in the chat's time zone, when digests and nudges are delivered to the chat
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS digest_time TEXT NOT NULL DEFAULT '';
+
+-- Bot messages asking for an edit of the draft; a reply to one is the edit instruction.
+-- Requests older than a day or of closed sessions are swept by the preview cleanup
+CREATE TABLE IF NOT EXISTS pending_edits (
+    bot_id TEXT NOT NULL DEFAULT 'default',
+    chat_id BIGINT NOT NULL,
+    message_id INTEGER NOT NULL,
+    session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (bot_id, chat_id, message_id)
+);
+
+-- Forum topic a message was written in, 0 outside topics
+ALTER TABLE messages
+    ADD COLUMN IF NOT EXISTS thread_id INTEGER NOT NULL DEFAULT 0;
+
+-- Name of a discussion started with /start_discussion <name>, empty for the
+-- default one. A topic may run several discussions, one open per name.
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '';
+DROP INDEX IF EXISTS sessions_one_open_per_topic_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_one_open_per_name_idx ON sessions(bot_id, chat_id, thread_id, name) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS messages_chat_message_idx ON messages(bot_id, chat_id, message_id);
+
+-- Photos and documents posted in a discussion, forwarded to the created task
+CREATE TABLE IF NOT EXISTS message_attachments (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    message_id INTEGER NOT NULL,
+    file_id TEXT NOT NULL,
+    file_name TEXT NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS message_attachments_session_idx ON message_attachments(session_id);
+
+-- Where the discussion transcript goes when a task is created: '' (nowhere),
+-- 'description' (appended to the task description) or 'comment' (task comments)
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS transcript_mode TEXT NOT NULL DEFAULT '';
+
+-- Section of the chat's Todoist project new tasks go to, empty to ask on confirm
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS todoist_section_id TEXT NOT NULL DEFAULT '';
+
+-- Team conventions a chat adds to the create_task and edit_task prompts, empty for none
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS create_task_prompt TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS edit_task_prompt TEXT NOT NULL DEFAULT '';
+
+-- Drafts of the last /create_task to choose from when AI_DRAFT_ALTERNATIVES offers several
+CREATE TABLE IF NOT EXISTS draft_alternatives (
+    session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    option INTEGER NOT NULL,
+    draft JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, option)
+);
+
+-- Versions of a session draft with the edit instruction that produced each, for undo and /draft_history
+CREATE TABLE IF NOT EXISTS draft_revisions (
+    id SERIAL PRIMARY KEY,
+    session_id INTEGER NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    instruction TEXT NOT NULL DEFAULT '',
+    draft JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, revision)
+);
+
+-- IANA time zone the chat reads and writes dates in, empty for the BOT_TIMEZONE default
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT '';
+
+-- Language of the chat's drafts, buttons and task confirmations, empty for the default
+ALTER TABLE chat_settings
+    ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT '';
+
+-- AI jobs waiting in the queue or running; the bot queues them again when it restarts
+CREATE TABLE IF NOT EXISTS ai_jobs (
+    id SERIAL PRIMARY KEY,
+    bot_id TEXT NOT NULL DEFAULT 'default',
+    kind TEXT NOT NULL,
+    chat_id BIGINT NOT NULL,
+    session_id INTEGER NOT NULL DEFAULT 0,
+    payload JSONB NOT NULL,
+    enqueued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ai_jobs_bot_idx ON ai_jobs(bot_id);
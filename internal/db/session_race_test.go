@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestManager connects to TEST_DATABASE_URL; tests using it are skipped without a database
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	t.Setenv("DATABASE_URL", dbURL)
+
+	manager, err := NewManager()
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { manager.Close() })
+	if err := manager.InitSchema(context.Background()); err != nil {
+		t.Fatalf("failed to init schema: %v", err)
+	}
+	// A fresh bot ID keeps runs independent from each other and from real data
+	return manager.ForBot(fmt.Sprintf("race-test-%d", time.Now().UnixNano()))
+}
+
+func TestStartSession_ConcurrentStartsOpenOneSession(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	chatID := -time.Now().UnixNano() % 1_000_000_000
+	if err := manager.EnsureChatExists(ctx, chatID); err != nil {
+		t.Fatalf("failed to create chat: %v", err)
+	}
+
+	const starters = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, starters)
+	for i := 0; i < starters; i++ {
+		wg.Add(1)
+		go func(ownerID int64) {
+			defer wg.Done()
+			_, err := manager.StartSession(ctx, chatID, 0, "", ownerID)
+			errs <- err
+		}(int64(i + 1))
+	}
+	wg.Wait()
+	close(errs)
+
+	started := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			started++
+		case !errors.Is(err, ErrSessionAlreadyExists):
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if started != 1 {
+		t.Fatalf("expected exactly one started session, got %d", started)
+	}
+
+	session, err := manager.GetActiveSession(ctx, chatID, 0)
+	if err != nil {
+		t.Fatalf("failed to get started session: %v", err)
+	}
+
+	// Concurrent closes close it once
+	closeErrs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			closeErrs <- manager.CloseSession(ctx, session.ID)
+		}()
+	}
+	wg.Wait()
+	close(closeErrs)
+
+	closed := 0
+	for err := range closeErrs {
+		switch {
+		case err == nil:
+			closed++
+		case !errors.Is(err, ErrNoActiveSession):
+			t.Fatalf("unexpected close error: %v", err)
+		}
+	}
+	if closed != 1 {
+		t.Fatalf("expected exactly one close to succeed, got %d", closed)
+	}
+	if _, err := manager.StartSession(ctx, chatID, 0, "", 1); err != nil {
+		t.Fatalf("expected a new session after close, got %v", err)
+	}
+}
+
+func TestStartSession_NamedSessionsRunInParallel(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	chatID := -time.Now().UnixNano() % 1_000_000_000
+	if err := manager.EnsureChatExists(ctx, chatID); err != nil {
+		t.Fatalf("failed to create chat: %v", err)
+	}
+
+	for _, name := range []string{"", "billing-bug", "release"} {
+		if _, err := manager.StartSession(ctx, chatID, 0, name, 1); err != nil {
+			t.Fatalf("failed to start session %q: %v", name, err)
+		}
+	}
+	if _, err := manager.StartSession(ctx, chatID, 0, "release", 2); !errors.Is(err, ErrSessionAlreadyExists) {
+		t.Fatalf("expected a second %q session to be rejected, got %v", "release", err)
+	}
+
+	sessions, err := manager.ListActiveSessions(ctx, chatID, 0)
+	if err != nil {
+		t.Fatalf("failed to list sessions: %v", err)
+	}
+	if len(sessions) != 3 {
+		t.Fatalf("expected 3 open sessions, got %d", len(sessions))
+	}
+
+	billing, err := manager.GetNamedSession(ctx, chatID, 0, "billing-bug")
+	if err != nil {
+		t.Fatalf("failed to get named session: %v", err)
+	}
+	if err := manager.SaveSessionMessage(ctx, billing.ID, chatID, 0, 100, 1, "owner", "invoice is wrong", nil); err != nil {
+		t.Fatalf("failed to save message: %v", err)
+	}
+	if sessionID, err := manager.GetMessageSession(ctx, chatID, 100); err != nil || sessionID != billing.ID {
+		t.Fatalf("expected message in session %d, got %d (%v)", billing.ID, sessionID, err)
+	}
+
+	active, err := manager.GetActiveSession(ctx, chatID, 0)
+	if err != nil {
+		t.Fatalf("failed to get active session: %v", err)
+	}
+	if active.Name != "" {
+		t.Fatalf("expected the unnamed session to be the active one, got %q", active.Name)
+	}
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReserveTaskAnalysis_ConcurrentReservationsKeepLimit(t *testing.T) {
+	manager := newTestManager(t)
+	ctx := context.Background()
+	chatID, sessionID := startTestSession(t, manager)
+	since := time.Now().Add(-time.Hour)
+
+	const reservers = 10
+	var wg sync.WaitGroup
+	ids := make(chan int, reservers)
+	for i := 0; i < reservers; i++ {
+		wg.Add(1)
+		go func(userID int64) {
+			defer wg.Done()
+			id, _, _, err := manager.ReserveTaskAnalysis(ctx, chatID, userID, sessionID, since, 3, 0)
+			if err != nil {
+				t.Errorf("failed to reserve: %v", err)
+			}
+			ids <- id
+		}(int64(i + 1))
+	}
+	wg.Wait()
+	close(ids)
+
+	var reserved []int
+	for id := range ids {
+		if id != 0 {
+			reserved = append(reserved, id)
+		}
+	}
+	if len(reserved) != 3 {
+		t.Fatalf("expected exactly 3 reservations, got %d", len(reserved))
+	}
+
+	// A released reservation frees its slot
+	if err := manager.ReleaseTaskAnalysis(ctx, reserved[0]); err != nil {
+		t.Fatalf("failed to release: %v", err)
+	}
+	id, chatCount, _, err := manager.ReserveTaskAnalysis(ctx, chatID, 1, sessionID, since, 3, 0)
+	if err != nil || id == 0 {
+		t.Fatalf("expected a reservation after release, got %d (%v)", id, err)
+	}
+	if chatCount != 2 {
+		t.Fatalf("expected 2 analyses before the reservation, got %d", chatCount)
+	}
+}
@@ -0,0 +1,190 @@
+// Package dispatch runs the updates of different chats concurrently while the
+// updates of one chat stay in the order they arrived.
+package dispatch
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStopped is returned by Submit after Stop
+var ErrStopped = errors.New("dispatcher stopped")
+
+// Stats describes the load of a dispatcher
+type Stats struct {
+	Workers int `json:"workers"`
+	// QueueSize is how many updates may wait before Submit blocks
+	QueueSize int `json:"queue_size"`
+	// Queued counts updates waiting or running
+	Queued int `json:"queued"`
+	// Chats counts chats with queued updates
+	Chats     int   `json:"chats"`
+	Processed int64 `json:"processed"`
+	// Blocked counts Submit calls that waited for room in the queue, and
+	// BlockedFor is how long they waited in total
+	Blocked    int64         `json:"blocked"`
+	BlockedFor time.Duration `json:"blocked_for_ns"`
+}
+
+// chatQueue holds the updates of one chat; a chat is in the ready channel or
+// with a worker while it has any, never in both, which keeps them ordered
+type chatQueue struct {
+	pending []func()
+}
+
+// Dispatcher is a pool of workers fed by per-chat queues
+type Dispatcher struct {
+	workers   int
+	queueSize int
+
+	mu      sync.Mutex
+	chats   map[int64]*chatQueue
+	stopped bool
+
+	// slots holds one token per queued update; a full channel is the backpressure
+	slots chan struct{}
+	// ready lists chats whose next update can run; it never holds more chats
+	// than there are queued updates, so sends to it do not block
+	ready chan int64
+	wg    sync.WaitGroup
+
+	processed  atomic.Int64
+	blocked    atomic.Int64
+	blockedFor atomic.Int64
+}
+
+// New creates a dispatcher with the given number of workers and room for
+// queueSize updates across all chats
+func New(workers, queueSize int) *Dispatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < workers {
+		queueSize = workers
+	}
+	return &Dispatcher{
+		workers:   workers,
+		queueSize: queueSize,
+		chats:     make(map[int64]*chatQueue),
+		slots:     make(chan struct{}, queueSize),
+		ready:     make(chan int64, queueSize),
+	}
+}
+
+// Start launches the workers
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.workers; i++ {
+		d.wg.Add(1)
+		go d.worker()
+	}
+}
+
+// Submit queues fn after the earlier updates of the chat. When the queue is
+// full it blocks until a worker frees a slot, which in turn slows down polling.
+func (d *Dispatcher) Submit(chatID int64, fn func()) error {
+	select {
+	case d.slots <- struct{}{}:
+	default:
+		start := time.Now()
+		d.slots <- struct{}{}
+		waited := time.Since(start)
+		if d.blocked.Add(1)%100 == 1 {
+			log.Printf("Update queue is full (%d updates), waited %v for a free slot", d.queueSize, waited.Round(time.Millisecond))
+		}
+		d.blockedFor.Add(int64(waited))
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		<-d.slots
+		return ErrStopped
+	}
+	q, ok := d.chats[chatID]
+	if !ok {
+		q = &chatQueue{}
+		d.chats[chatID] = q
+	}
+	q.pending = append(q.pending, fn)
+	if len(q.pending) == 1 {
+		d.ready <- chatID
+	}
+	return nil
+}
+
+// Stop waits for the queued updates to finish and stops the workers; later
+// Submit calls return ErrStopped
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	if d.stopped {
+		d.mu.Unlock()
+		return
+	}
+	d.stopped = true
+	d.mu.Unlock()
+
+	// Every slot is free once the queued updates are done
+	for i := 0; i < d.queueSize; i++ {
+		d.slots <- struct{}{}
+	}
+	close(d.ready)
+	d.wg.Wait()
+
+	// Freed again, so late Submit calls get a slot and see the stop
+	for i := 0; i < d.queueSize; i++ {
+		<-d.slots
+	}
+}
+
+// Stats returns the current load
+func (d *Dispatcher) Stats() Stats {
+	d.mu.Lock()
+	chats := len(d.chats)
+	d.mu.Unlock()
+	return Stats{
+		Workers:    d.workers,
+		QueueSize:  d.queueSize,
+		Queued:     len(d.slots),
+		Chats:      chats,
+		Processed:  d.processed.Load(),
+		Blocked:    d.blocked.Load(),
+		BlockedFor: time.Duration(d.blockedFor.Load()),
+	}
+}
+
+func (d *Dispatcher) worker() {
+	defer d.wg.Done()
+	for chatID := range d.ready {
+		d.mu.Lock()
+		q := d.chats[chatID]
+		fn := q.pending[0]
+		d.mu.Unlock()
+
+		d.run(chatID, fn)
+
+		d.mu.Lock()
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		if len(q.pending) > 0 {
+			d.ready <- chatID
+		} else {
+			delete(d.chats, chatID)
+		}
+		d.mu.Unlock()
+		<-d.slots
+	}
+}
+
+// run calls fn and keeps a panic in one update from killing the worker
+func (d *Dispatcher) run(chatID int64, fn func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic while handling update of chat %d: %v", chatID, r)
+		}
+	}()
+	fn()
+	d.processed.Add(1)
+}
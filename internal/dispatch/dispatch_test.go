@@ -0,0 +1,99 @@
+package dispatch
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatesOfOneChatStayOrdered(t *testing.T) {
+	d := New(4, 64)
+	d.Start()
+
+	var mu sync.Mutex
+	got := map[int64][]int{}
+	for i := 0; i < 50; i++ {
+		for _, chatID := range []int64{1, 2, 3} {
+			chatID, i := chatID, i
+			require.NoError(t, d.Submit(chatID, func() {
+				mu.Lock()
+				got[chatID] = append(got[chatID], i)
+				mu.Unlock()
+			}))
+		}
+	}
+	d.Stop()
+
+	for _, chatID := range []int64{1, 2, 3} {
+		require.Len(t, got[chatID], 50)
+		for i, v := range got[chatID] {
+			assert.Equal(t, i, v, "chat %d", chatID)
+		}
+	}
+	assert.Equal(t, int64(150), d.Stats().Processed)
+}
+
+func TestSlowChatDoesNotBlockOthers(t *testing.T) {
+	d := New(2, 8)
+	d.Start()
+	defer d.Stop()
+
+	release := make(chan struct{})
+	require.NoError(t, d.Submit(1, func() { <-release }))
+
+	done := make(chan struct{})
+	require.NoError(t, d.Submit(2, func() { close(done) }))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("update of chat 2 waited for chat 1")
+	}
+
+	stats := d.Stats()
+	assert.Equal(t, 1, stats.Queued)
+	assert.Equal(t, 1, stats.Chats)
+	close(release)
+}
+
+func TestFullQueueBlocksSubmit(t *testing.T) {
+	d := New(1, 1)
+	d.Start()
+
+	release := make(chan struct{})
+	require.NoError(t, d.Submit(1, func() { <-release }))
+
+	submitted := make(chan struct{})
+	go func() {
+		d.Submit(2, func() {})
+		close(submitted)
+	}()
+
+	select {
+	case <-submitted:
+		t.Fatal("Submit did not wait for room in the queue")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-submitted
+	d.Stop()
+
+	stats := d.Stats()
+	assert.Equal(t, int64(1), stats.Blocked)
+	assert.Greater(t, stats.BlockedFor, time.Duration(0))
+	assert.ErrorIs(t, d.Submit(1, func() {}), ErrStopped)
+}
+
+func TestPanicDoesNotStopWorker(t *testing.T) {
+	d := New(1, 4)
+	d.Start()
+
+	ran := false
+	require.NoError(t, d.Submit(1, func() { panic("boom") }))
+	require.NoError(t, d.Submit(1, func() { ran = true }))
+	d.Stop()
+
+	assert.True(t, ran)
+}
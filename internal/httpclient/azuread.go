@@ -0,0 +1,78 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultAzureAuthority = "https://login.microsoftonline.com"
+
+// AzureADCredentials obtains tokens with the OAuth2 client credentials flow
+// of an Azure AD app registration (service principal)
+type AzureADCredentials struct {
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// Scope is the resource to get a token for, e.g. "https://cognitiveservices.azure.com/.default"
+	Scope string
+	// AuthorityURL overrides https://login.microsoftonline.com, e.g. for sovereign clouds
+	AuthorityURL string
+	// HTTPClient defaults to a client with a 30 second timeout
+	HTTPClient *http.Client
+}
+
+type azureTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Token implements TokenSource
+func (c *AzureADCredentials) Token(ctx context.Context) (Token, error) {
+	authority := c.AuthorityURL
+	if authority == "" {
+		authority = defaultAzureAuthority
+	}
+	endpoint := strings.TrimSuffix(authority, "/") + "/" + url.PathEscape(c.TenantID) + "/oauth2/v2.0/token"
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {c.ClientID},
+		"client_secret": {c.ClientSecret},
+		"scope":         {c.Scope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Token{}, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body azureTokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Token{}, fmt.Errorf("error decoding token response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return Token{}, fmt.Errorf("Azure AD token request failed with status %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription)
+	}
+
+	return Token{
+		Value:     body.AccessToken,
+		ExpiresAt: time.Now().Add(time.Duration(body.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -63,9 +63,11 @@ func (c *Client) WithMiddleware(middleware Middleware) *Client {
 
 // Do executes a request with context and processes it through the middleware chain
 func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
-	// Apply client-level headers
+	// Apply client-level headers unless the request sets its own, e.g. a multipart Content-Type
 	for key, value := range c.config.Headers {
-		req.Header.Set(key, value)
+		if req.Header.Get(key) == "" {
+			req.Header.Set(key, value)
+		}
 	}
 
 	// Clone the request to avoid modifying the original
@@ -116,8 +118,16 @@ func (c *Client) executeRequest(ctx context.Context, req *http.Request) (*http.R
 		case <-time.After(waitTime):
 		}
 
-		// Clone the request to get a fresh body
-		req = req.Clone(ctx)
+		// Clone the request and rewind the body consumed by the previous attempt
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("error rewinding request body: %w", err)
+			}
+			next.Body = body
+		}
+		req = next
 		retryCount++
 	}
 
@@ -3,6 +3,7 @@ package httpclient
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -198,6 +199,34 @@ func TestClient_Retry(t *testing.T) {
 	}
 }
 
+// Verifies that retried POST requests send the body again instead of an empty one
+func TestClient_RetryResendsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.RetryWaitTime = 10 * time.Millisecond
+
+	err := NewClient(config).Post(context.Background(), "/hook", map[string]string{"event": "ping"}, nil)
+	if err != nil {
+		t.Fatalf("Error making request: %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Errorf("Expected the same body on both attempts, got %q", bodies)
+	}
+}
+
 // Tests that the HTTP client correctly handles API errors and provides appropriate error information
 // Verifies that error status codes are properly detected and helper functions (IsNotFound, IsForbidden) work correctly
 func TestClient_Error(t *testing.T) {
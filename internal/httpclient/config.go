@@ -1,11 +1,13 @@
 package httpclient
 
 import (
+	"encoding/base64"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/user/telegram-bot/configs"
 	"gopkg.in/yaml.v3"
 )
 
@@ -13,6 +15,9 @@ import (
 type AuthorizationConfig struct {
 	Type        string `yaml:"type"`          // e.g., "Bearer"
 	TokenEnvVar string `yaml:"token_env_var"` // Name of environment variable for token
+	// UsernameEnvVar names the user of "Basic" authorization, which then sends
+	// base64("user:token") as APIs like Jira Cloud expect
+	UsernameEnvVar string `yaml:"username_env_var,omitempty"`
 }
 
 // ClientConfig represents the YAML configuration for an HTTP client
@@ -25,16 +30,59 @@ type ClientConfig struct {
 	RetryWaitTime    string               `yaml:"retry_wait_time"`
 	MaxRetryWaitTime string               `yaml:"max_retry_wait_time"`
 	EnableLogging    bool                 `yaml:"enable_logging"`
+	MaxConcurrency   int                  `yaml:"max_concurrency,omitempty"`  // Parallel jobs allowed against this API
+	FailoverTimeout  string               `yaml:"failover_timeout,omitempty"` // Time an AI provider gets, retries included, before the fallback chain moves on
+}
+
+// FailoverTimeoutDuration returns failover_timeout, or timeout when it is not set
+func (c *ClientConfig) FailoverTimeoutDuration() (time.Duration, error) {
+	raw := c.FailoverTimeout
+	if raw == "" {
+		raw = c.Timeout
+	}
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid failover timeout %q", raw)
+	}
+	return timeout, nil
+}
+
+// TrackersConfig selects the task tracker of chats; a tracker is named after
+// its client in clients
+type TrackersConfig struct {
+	Default string             `yaml:"default,omitempty"` // Tracker of chats not listed in chats, todoist if empty
+	Chats   map[int64]string   `yaml:"chats,omitempty"`   // Tracker by Telegram chat ID
+	Jira    *JiraTrackerConfig `yaml:"jira,omitempty"`
+}
+
+// JiraTrackerConfig holds the Jira settings that are not about HTTP
+type JiraTrackerConfig struct {
+	ProjectKey string `yaml:"project_key,omitempty"` // Only project offered to chats; all projects if empty
+	IssueType  string `yaml:"issue_type,omitempty"`  // Type of created issues, Task if empty
+}
+
+// Uses reports whether the default or any chat uses the tracker
+func (t TrackersConfig) Uses(name string) bool {
+	if t.Default == name {
+		return true
+	}
+	for _, chatTracker := range t.Chats {
+		if chatTracker == name {
+			return true
+		}
+	}
+	return false
 }
 
 // APIConfigs represents a map of named API configurations
 type APIConfigs struct {
-	Clients map[string]ClientConfig `yaml:"clients"`
+	Clients  map[string]ClientConfig `yaml:"clients"`
+	Trackers TrackersConfig          `yaml:"trackers,omitempty"`
 }
 
 // LoadConfig loads client configuration from a YAML file
 func LoadConfig(path string) (*APIConfigs, error) {
-	data, err := os.ReadFile(path)
+	data, err := configs.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("error reading config file: %w", err)
 	}
@@ -77,10 +125,25 @@ func (c *APIConfigs) GetClientConfig(name string) (*ClientConfig, error) {
 			config.Headers = make(map[string]string)
 		}
 
+		if config.Authorization.UsernameEnvVar != "" {
+			username := os.Getenv(config.Authorization.UsernameEnvVar)
+			if username == "" {
+				return nil, fmt.Errorf("environment variable %s for authorization username is required but not set", config.Authorization.UsernameEnvVar)
+			}
+			token = base64.StdEncoding.EncodeToString([]byte(username + ":" + token))
+		}
+
 		// Set the Authorization header
 		config.Headers["Authorization"] = authType + " " + token
 	}
 
+	// base_url may name the endpoint of a tenant, e.g. "${AZURE_OPENAI_ENDPOINT}/openai"
+	baseURL, err := expandEnv(config.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	config.BaseURL = baseURL
+
 	// Replace environment variables in all header values (including Authorization if not using authorization block)
 	for key, value := range config.Headers {
 		// Skip Authorization header if already processed via authorization block
@@ -88,33 +151,39 @@ func (c *APIConfigs) GetClientConfig(name string) (*ClientConfig, error) {
 			continue
 		}
 
-		// Look for ${VAR_NAME} pattern anywhere in the value
-		for {
-			start := strings.Index(value, "${")
-			if start == -1 {
-				break // No more variables found
-			}
+		expanded, err := expandEnv(value)
+		if err != nil {
+			return nil, err
+		}
+		config.Headers[key] = expanded
+	}
 
-			end := strings.Index(value[start:], "}")
-			if end == -1 {
-				break // No closing brace found
-			}
-			end = start + end
+	return &config, nil
+}
 
-			envName := value[start+2 : end]
-			envValue := os.Getenv(envName)
-			if envValue == "" {
-				return nil, fmt.Errorf("environment variable %s is required but not set", envName)
-			}
+// expandEnv replaces every ${VAR_NAME} in value with the variable, which must be set
+func expandEnv(value string) (string, error) {
+	for {
+		start := strings.Index(value, "${")
+		if start == -1 {
+			return value, nil // No more variables found
+		}
 
-			// Replace the variable with its value
-			value = value[:start] + envValue + value[end+1:]
+		end := strings.Index(value[start:], "}")
+		if end == -1 {
+			return value, nil // No closing brace found
 		}
+		end = start + end
 
-		config.Headers[key] = value
-	}
+		envName := value[start+2 : end]
+		envValue := os.Getenv(envName)
+		if envValue == "" {
+			return "", fmt.Errorf("environment variable %s is required but not set", envName)
+		}
 
-	return &config, nil
+		// Replace the variable with its value
+		value = value[:start] + envValue + value[end+1:]
+	}
 }
 
 // ToConfig converts a ClientConfig to a httpclient.Config
@@ -0,0 +1,32 @@
+package httpclient
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestGetClientConfig_BasicAuthorizationWithUsername(t *testing.T) {
+	t.Setenv("TEST_JIRA_EMAIL", "bot@example.com")
+	t.Setenv("TEST_JIRA_API_TOKEN", "secret")
+
+	configs := &APIConfigs{Clients: map[string]ClientConfig{
+		"jira": {
+			BaseURL: "https://example.atlassian.net/rest/api/3",
+			Timeout: "30s",
+			Authorization: &AuthorizationConfig{
+				Type:           "Basic",
+				TokenEnvVar:    "TEST_JIRA_API_TOKEN",
+				UsernameEnvVar: "TEST_JIRA_EMAIL",
+			},
+		},
+	}}
+
+	config, err := configs.GetClientConfig("jira")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "Basic " + base64.StdEncoding.EncodeToString([]byte("bot@example.com:secret"))
+	if got := config.Headers["Authorization"]; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDeprecationInterval is how often the same API may raise a deprecation alert
+const DefaultDeprecationInterval = 24 * time.Hour
+
+// Deprecation describes the deprecation headers of an API response
+// (RFC 9745 Deprecation, RFC 8594 Sunset and the matching Link relations)
+type Deprecation struct {
+	API    string
+	Method string
+	Path   string
+	// Deprecation is the raw Deprecation header, e.g. "@1735689600" or "true"
+	Deprecation string
+	// Sunset is the raw Sunset header, an HTTP date
+	Sunset string
+	// Link points to the migration notes, if the API gives one
+	Link string
+}
+
+// SunsetTime parses the Sunset header; it is zero when absent or malformed
+func (d Deprecation) SunsetTime() time.Time {
+	t, err := http.ParseTime(d.Sunset)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// ParseDeprecation reports whether the response announces a deprecation or sunset
+func ParseDeprecation(api string, resp *http.Response) (Deprecation, bool) {
+	d := Deprecation{
+		API:         api,
+		Deprecation: strings.TrimSpace(resp.Header.Get("Deprecation")),
+		Sunset:      strings.TrimSpace(resp.Header.Get("Sunset")),
+		Link:        deprecationLink(resp.Header.Values("Link")),
+	}
+	if d.Deprecation == "" && d.Sunset == "" {
+		return Deprecation{}, false
+	}
+	if resp.Request != nil {
+		d.Method = resp.Request.Method
+		d.Path = resp.Request.URL.Path
+	}
+	return d, true
+}
+
+// deprecationLink returns the target of a rel="deprecation" or rel="sunset" link
+func deprecationLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			for _, param := range parts[1:] {
+				param = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(param), `"`, ""))
+				if param == "rel=deprecation" || param == "rel=sunset" {
+					return target
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// DeprecationMiddleware passes deprecation headers of the API responses to report
+func DeprecationMiddleware(api string, report func(Deprecation)) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			resp, err := next(ctx, req)
+			if err == nil && resp != nil {
+				if d, ok := ParseDeprecation(api, resp); ok {
+					report(d)
+				}
+			}
+			return resp, err
+		}
+	}
+}
+
+// DeprecationTransport does the same for clients that are not built on
+// Client, such as the Telegram one; a nil next uses http.DefaultTransport
+func DeprecationTransport(next http.RoundTripper, api string, report func(Deprecation)) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil {
+			if d, ok := ParseDeprecation(api, resp); ok {
+				report(d)
+			}
+		}
+		return resp, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// DeprecationAlerts logs deprecations and passes them on at most once per
+// interval for each API, since every response of a deprecated endpoint
+// carries the same headers
+type DeprecationAlerts struct {
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	last   map[string]time.Time
+	notify func(Deprecation)
+}
+
+// NewDeprecationAlerts creates alerts throttled to one per interval and API
+func NewDeprecationAlerts(interval time.Duration) *DeprecationAlerts {
+	return &DeprecationAlerts{
+		interval: interval,
+		now:      time.Now,
+		last:     make(map[string]time.Time),
+	}
+}
+
+// SetNotify sets where alerts go besides the log
+func (a *DeprecationAlerts) SetNotify(notify func(Deprecation)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.notify = notify
+}
+
+// Report records a deprecation and raises an alert unless the API already
+// raised one within the interval
+func (a *DeprecationAlerts) Report(d Deprecation) {
+	a.mu.Lock()
+	now := a.now()
+	if last, ok := a.last[d.API]; ok && now.Sub(last) < a.interval {
+		a.mu.Unlock()
+		return
+	}
+	a.last[d.API] = now
+	notify := a.notify
+	a.mu.Unlock()
+
+	log.Printf("[HTTP] %s API deprecation on %s %s: Deprecation=%q Sunset=%q Link=%q", d.API, d.Method, d.Path, d.Deprecation, d.Sunset, d.Link)
+	if notify != nil {
+		notify(d)
+	}
+}
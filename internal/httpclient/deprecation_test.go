@@ -0,0 +1,116 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	resp := &http.Response{
+		Header:  http.Header{},
+		Request: httptest.NewRequest(http.MethodGet, "https://api.example.com/rest/v2/tasks", nil),
+	}
+	if _, ok := ParseDeprecation("todoist", resp); ok {
+		t.Fatal("Expected no deprecation without headers")
+	}
+
+	resp.Header.Set("Deprecation", "@1735689600")
+	resp.Header.Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+	resp.Header.Add("Link", `<https://example.com/next>; rel="next"`)
+	resp.Header.Add("Link", `<https://example.com/migrate>; rel="deprecation"; type="text/html"`)
+
+	d, ok := ParseDeprecation("todoist", resp)
+	if !ok {
+		t.Fatal("Expected a deprecation")
+	}
+	if d.API != "todoist" || d.Method != http.MethodGet || d.Path != "/rest/v2/tasks" {
+		t.Errorf("Unexpected request details: %+v", d)
+	}
+	if d.Link != "https://example.com/migrate" {
+		t.Errorf("Expected the deprecation link, got %q", d.Link)
+	}
+	if want := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC); !d.SunsetTime().Equal(want) {
+		t.Errorf("Expected sunset %v, got %v", want, d.SunsetTime())
+	}
+}
+
+func TestDeprecationMiddleware(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			w.Header().Set("Sunset", "Wed, 01 Jul 2026 00:00:00 GMT")
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var reported []Deprecation
+	client := NewClient(&Config{BaseURL: server.URL, Timeout: time.Second})
+	client.WithMiddleware(DeprecationMiddleware("test", func(d Deprecation) {
+		reported = append(reported, d)
+	}))
+
+	for _, path := range []string{"/new", "/old"} {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+
+	if len(reported) != 1 || reported[0].Path != "/old" {
+		t.Errorf("Expected only /old to be reported, got %+v", reported)
+	}
+}
+
+func TestDeprecationTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Deprecation", "true")
+	}))
+	defer server.Close()
+
+	var reported []Deprecation
+	client := &http.Client{Transport: DeprecationTransport(nil, "telegram", func(d Deprecation) {
+		reported = append(reported, d)
+	})}
+	resp, err := client.Get(server.URL + "/botTOKEN/getMe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(reported) != 1 || reported[0].API != "telegram" || reported[0].Deprecation != "true" {
+		t.Errorf("Unexpected report: %+v", reported)
+	}
+}
+
+func TestDeprecationAlerts_OncePerInterval(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	alerts := NewDeprecationAlerts(DefaultDeprecationInterval)
+	alerts.now = func() time.Time { return now }
+
+	var notified []string
+	alerts.SetNotify(func(d Deprecation) { notified = append(notified, d.API) })
+
+	alerts.Report(Deprecation{API: "todoist", Sunset: "x"})
+	alerts.Report(Deprecation{API: "todoist", Sunset: "x"})
+	alerts.Report(Deprecation{API: "telegram", Deprecation: "true"})
+	now = now.Add(DefaultDeprecationInterval)
+	alerts.Report(Deprecation{API: "todoist", Sunset: "x"})
+
+	want := []string{"todoist", "telegram", "todoist"}
+	if len(notified) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, notified)
+	}
+	for i := range want {
+		if notified[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, notified)
+		}
+	}
+}
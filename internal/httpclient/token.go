@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// tokenRefreshSkew renews a token this long before it expires, so a request
+// never leaves with a token that dies in flight
+const tokenRefreshSkew = 2 * time.Minute
+
+// Token is a short-lived access token
+type Token struct {
+	Value     string
+	ExpiresAt time.Time
+}
+
+// TokenSource fetches a new access token, e.g. from Azure AD
+type TokenSource interface {
+	Token(ctx context.Context) (Token, error)
+}
+
+// TokenMiddleware sets "Authorization: Bearer <token>" from source, caching
+// the token until shortly before it expires. A 401 answer drops the cached
+// token and the request is sent once more with a fresh one.
+func TokenMiddleware(source TokenSource) Middleware {
+	cache := &tokenCache{source: source}
+	return func(next Handler) Handler {
+		return func(ctx context.Context, req *http.Request) (*http.Response, error) {
+			token, err := cache.get(ctx)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := next(ctx, req)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+
+			retry, ok := rewind(ctx, req)
+			if !ok {
+				return resp, nil
+			}
+			cache.invalidate(token)
+			token, err = cache.get(ctx)
+			if err != nil {
+				return resp, nil
+			}
+			resp.Body.Close()
+			retry.Header.Set("Authorization", "Bearer "+token)
+			return next(ctx, retry)
+		}
+	}
+}
+
+// rewind clones req with a fresh body, if the body can be read again
+func rewind(ctx context.Context, req *http.Request) (*http.Request, bool) {
+	retry := req.Clone(ctx)
+	if req.Body == nil || req.Body == http.NoBody {
+		return retry, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retry.Body = body
+	return retry, true
+}
+
+type tokenCache struct {
+	source TokenSource
+
+	mu    sync.Mutex
+	token Token
+}
+
+func (c *tokenCache) get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token.Value != "" && time.Now().Add(tokenRefreshSkew).Before(c.token.ExpiresAt) {
+		return c.token.Value, nil
+	}
+
+	token, err := c.source.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error fetching access token: %w", err)
+	}
+	c.token = token
+	return token.Value, nil
+}
+
+// invalidate drops the cached token unless another request has already replaced it
+func (c *tokenCache) invalidate(value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token.Value == value {
+		c.token = Token{}
+	}
+}
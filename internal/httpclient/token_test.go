@@ -0,0 +1,134 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingTokenSource struct {
+	calls  int
+	expiry time.Duration
+}
+
+func (s *countingTokenSource) Token(ctx context.Context) (Token, error) {
+	s.calls++
+	return Token{Value: fmt.Sprintf("token-%d", s.calls), ExpiresAt: time.Now().Add(s.expiry)}, nil
+}
+
+func TestTokenMiddleware_CachesUntilExpiry(t *testing.T) {
+	var seen []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	source := &countingTokenSource{expiry: time.Hour}
+	client := NewClient(config).WithMiddleware(TokenMiddleware(source))
+
+	for i := 0; i < 3; i++ {
+		if err := client.Get(context.Background(), "/test", nil); err != nil {
+			t.Fatalf("request %d failed: %v", i, err)
+		}
+	}
+
+	if source.calls != 1 {
+		t.Errorf("expected one token fetch, got %d", source.calls)
+	}
+	for _, header := range seen {
+		if header != "Bearer token-1" {
+			t.Errorf("unexpected Authorization header %q", header)
+		}
+	}
+
+	// A token about to expire is renewed before it is used
+	source.expiry = time.Minute
+	client = NewClient(config).WithMiddleware(TokenMiddleware(source))
+	client.Get(context.Background(), "/test", nil)
+	client.Get(context.Background(), "/test", nil)
+	if source.calls != 3 {
+		t.Errorf("expected short-lived tokens to be fetched per request, got %d fetches", source.calls)
+	}
+}
+
+func TestTokenMiddleware_RefreshesOnUnauthorized(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(strings.Builder)
+		fmt.Fprint(buf, r.Header.Get("Authorization"), " ")
+		body := make([]byte, 64)
+		n, _ := r.Body.Read(body)
+		buf.Write(body[:n])
+		bodies = append(bodies, buf.String())
+
+		if r.Header.Get("Authorization") == "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	source := &countingTokenSource{expiry: time.Hour}
+	client := NewClient(config).WithMiddleware(TokenMiddleware(source))
+
+	if err := client.Post(context.Background(), "/test", map[string]string{"a": "b"}, nil); err != nil {
+		t.Fatalf("expected retry with a fresh token to succeed: %v", err)
+	}
+	if len(bodies) != 2 || bodies[1] != `Bearer token-2 {"a":"b"}` {
+		t.Errorf("unexpected requests: %q", bodies)
+	}
+}
+
+func TestAzureADCredentials_Token(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenant-1/oauth2/v2.0/token" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "client_credentials" || r.Form.Get("client_id") != "app" ||
+			r.Form.Get("client_secret") != "secret" || r.Form.Get("scope") != "scope/.default" {
+			t.Errorf("unexpected form %v", r.Form)
+		}
+		w.Write([]byte(`{"access_token":"aad-token","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	credentials := &AzureADCredentials{
+		TenantID:     "tenant-1",
+		ClientID:     "app",
+		ClientSecret: "secret",
+		Scope:        "scope/.default",
+		AuthorityURL: server.URL,
+	}
+	token, err := credentials.Token(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if token.Value != "aad-token" || time.Until(token.ExpiresAt) < 59*time.Minute {
+		t.Errorf("unexpected token %+v", token)
+	}
+}
+
+func TestAzureADCredentials_ReportsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid_client","error_description":"bad secret"}`))
+	}))
+	defer server.Close()
+
+	credentials := &AzureADCredentials{TenantID: "t", AuthorityURL: server.URL}
+	_, err := credentials.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Errorf("expected invalid_client error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/user/telegram-bot/configs"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateConfig checks an API configuration file without creating clients.
+// All problems are reported together, one per line, so a CI run shows every
+// mistake at once instead of failing on the first.
+func ValidateConfig(path string) error {
+	data, err := configs.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	return ValidateConfigData(data)
+}
+
+// ValidateConfigData validates the YAML content of an API configuration
+func ValidateConfigData(data []byte) error {
+	var configs APIConfigs
+	var problems []error
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&configs); err != nil {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return fmt.Errorf("error parsing YAML config: %w", err)
+		}
+		// Unknown keys and wrong types are collected, the rest is still checked
+		for _, msg := range typeErr.Errors {
+			problems = append(problems, errors.New(msg))
+		}
+	}
+
+	if len(configs.Clients) == 0 {
+		problems = append(problems, errors.New("clients: at least one client must be configured"))
+	}
+
+	names := make([]string, 0, len(configs.Clients))
+	for name := range configs.Clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		problems = append(problems, configs.Clients[name].validate("clients."+name)...)
+	}
+	problems = append(problems, configs.Trackers.validate(configs.Clients)...)
+
+	return errors.Join(problems...)
+}
+
+func (c ClientConfig) validate(prefix string) []error {
+	var problems []error
+	if c.BaseURL == "" {
+		problems = append(problems, fmt.Errorf("%s.base_url: is required", prefix))
+	}
+
+	if c.Timeout == "" {
+		problems = append(problems, fmt.Errorf("%s.timeout: is required", prefix))
+	}
+	durations := []struct {
+		key   string
+		value string
+	}{
+		{"timeout", c.Timeout},
+		{"retry_wait_time", c.RetryWaitTime},
+		{"max_retry_wait_time", c.MaxRetryWaitTime},
+		{"failover_timeout", c.FailoverTimeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			problems = append(problems, fmt.Errorf("%s.%s: invalid duration %q (use values like 30s or 1m)", prefix, d.key, d.value))
+		} else if parsed < 0 {
+			problems = append(problems, fmt.Errorf("%s.%s: must not be negative", prefix, d.key))
+		}
+	}
+
+	if c.RetryCount < 0 {
+		problems = append(problems, fmt.Errorf("%s.retry_count: must not be negative", prefix))
+	}
+	if c.MaxConcurrency < 0 {
+		problems = append(problems, fmt.Errorf("%s.max_concurrency: must not be negative", prefix))
+	}
+	if c.Authorization != nil && c.Authorization.TokenEnvVar == "" {
+		problems = append(problems, fmt.Errorf("%s.authorization.token_env_var: is required", prefix))
+	}
+	if c.Authorization != nil && c.Authorization.UsernameEnvVar != "" && c.Authorization.Type != "Basic" {
+		problems = append(problems, fmt.Errorf("%s.authorization.username_env_var: only applies to type Basic", prefix))
+	}
+
+	return problems
+}
+
+func (t TrackersConfig) validate(clients map[string]ClientConfig) []error {
+	var problems []error
+	if t.Default != "" {
+		if _, ok := clients[t.Default]; !ok {
+			problems = append(problems, fmt.Errorf("trackers.default: client %q is not configured", t.Default))
+		}
+	}
+
+	chatIDs := make([]int64, 0, len(t.Chats))
+	for chatID := range t.Chats {
+		chatIDs = append(chatIDs, chatID)
+	}
+	sort.Slice(chatIDs, func(i, j int) bool { return chatIDs[i] < chatIDs[j] })
+
+	for _, chatID := range chatIDs {
+		if _, ok := clients[t.Chats[chatID]]; !ok {
+			problems = append(problems, fmt.Errorf("trackers.chats.%d: client %q is not configured", chatID, t.Chats[chatID]))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateConfigData_Valid(t *testing.T) {
+	data := []byte(`
+clients:
+  todoist:
+    base_url: "https://api.todoist.com/api/v1"
+    timeout: 30s
+    authorization:
+      type: "Bearer"
+      token_env_var: "TODOIST_API_TOKEN"
+    retry_wait_time: 1s
+`)
+	if err := ValidateConfigData(data); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+}
+
+// Checks that every problem is reported at once instead of only the first one
+func TestValidateConfigData_AggregatesProblems(t *testing.T) {
+	data := []byte(`
+clients:
+  todoist:
+    timeout: 30x
+    retry_count: -1
+    retrys: 3
+    authorization:
+      type: "Bearer"
+`)
+	err := ValidateConfigData(data)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	expected := []string{
+		"field retrys not found",
+		"clients.todoist.base_url: is required",
+		`clients.todoist.timeout: invalid duration "30x"`,
+		"clients.todoist.retry_count: must not be negative",
+		"clients.todoist.authorization.token_env_var: is required",
+	}
+	for _, want := range expected {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in error:\n%v", want, err)
+		}
+	}
+}
+
+func TestValidateConfigData_Trackers(t *testing.T) {
+	data := []byte(`
+clients:
+  todoist:
+    base_url: "https://api.todoist.com/api/v1"
+    timeout: 30s
+trackers:
+  default: todoist
+  chats:
+    -1001234567890: todoist
+    -1009876543210: jira
+`)
+	err := ValidateConfigData(data)
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if want := `trackers.chats.-1009876543210: client "jira" is not configured`; !strings.Contains(err.Error(), want) {
+		t.Errorf("expected %q in error:\n%v", want, err)
+	}
+	if strings.Contains(err.Error(), "-1001234567890") || strings.Contains(err.Error(), "trackers.default") {
+		t.Errorf("expected configured trackers to pass:\n%v", err)
+	}
+}
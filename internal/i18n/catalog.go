@@ -0,0 +1,1158 @@
+package i18n
+
+var catalog = map[Lang]map[Key]string{
+	Russian: {
+		StartWelcome: `🤖 Привет! Я AI Task Assistant JiraF 🤖
+
+Я помогаю превращать обсуждения в чате в готовые задачи.
+
+🔧 Что я умею:
+— анализировать обсуждение
+— формировать черновик задачи
+— отправлять задачу в Todoist
+
+📋 Как пользоваться:
+1️⃣ Выбери проект
+2️⃣ Начни обсуждение
+3️⃣ Создай задачу из контекста обсуждения
+
+Нажмите на любую кнопку ниже для быстрого доступа:`,
+		StartChooseProject: "Сначала выберите проект Todoist:",
+		Help:               "🧩 Полный список команд:",
+		HelpFooter:         "Используйте кнопки ниже для быстрого доступа:",
+		LanguageCurrent:    "Язык ответов: %s.\n\nИзменить: /language ru, /language en или /language auto — по языку Telegram.",
+		LanguageAuto:       "Язык ответов снова выбирается по языку Telegram.",
+		LanguageSet:        "Готово, отвечаю на языке: %s.",
+		LanguageUsage:      "Использование: /language ru|en|auto",
+		LanguageSaveFailed: "Не удалось сохранить язык. Попробуйте позже.",
+		LanguageName:       "русский",
+		LanguageChat:       "Язык чата: %s.\n\nЧерновики, кнопки и подтверждения задач показываются на нём. Изменить: /language ru, /language en или /language auto — язык по умолчанию.",
+		LanguageChatSet:    "Готово, язык чата: %s.",
+		LanguageChatReset:  "Язык чата сброшен на язык по умолчанию.",
+
+		PreviewDraftReady:        "✅ Черновик задачи готов.",
+		PreviewUpdated:           "✅ Задача обновлена!\n\nИзменения сохранены:\n",
+		PreviewAlternativeChosen: "✅ Выбран вариант %d.",
+		PreviewChooseAction:      "Проверь описание и выбери действие:",
+		PreviewMissingHint:       "Если хочешь, нажми `Редактировать` и дополни это в задаче.",
+		PreviewReplyHint:         "Если хочешь, просто ответь на это сообщение и дополни это в задаче.",
+		PreviewUndone:            "↩️ Правка отменена, черновик вернулся к предыдущей версии:\n",
+		PreviewTitle:             "Название",
+		PreviewDescription:       "Описание",
+		PreviewDue:               "Срок выполнения",
+		PreviewPriority:          "Приоритет",
+		PreviewTaskType:          "Тип задачи",
+		PreviewAssignee:          "Исполнитель",
+		PreviewLabels:            "Метки",
+		TaskTypeTask:             "Задача",
+		TaskTypeBug:              "Баг",
+		TaskTypeEpic:             "Эпик",
+		ButtonConfirm:            "✅ Подтвердить",
+		ButtonEdit:               "✏️ Редактировать",
+		ButtonCancelCreation:     "❌ Отменить создание",
+		ButtonUndoEdit:           "↩️ Отменить правку",
+		ButtonCreateAnyway:       "✅ Всё равно создать",
+		ButtonOpenExisting:       "🔗 Открыть существующую",
+		ButtonMergeComment:       "📎 Добавить комментарием",
+		ButtonDueToday:           "📅 Сегодня",
+		ButtonDueTomorrow:        "📅 Завтра",
+		ButtonDueNextWeek:        "📅 След. неделя",
+		ButtonDueClear:           "📅 Без срока",
+
+		CallbackCreating:          "✅ Отлично! Создаю задачу.",
+		CallbackTaskCreated:       "✅ *Задача создана*: [%s](%s)",
+		CallbackAlreadyCreated:    "Задача уже создана",
+		CallbackAlreadyCreatedMsg: "ℹ️ *Задача уже создана*: [%s](%s)",
+		CallbackEditPrompt: `
+✏️ Отредактировать задачу
+Пожалуйста, ответьте на это сообщение, указав ваши инструкции по редактированию в произвольном формате.
+Примеры:
+• "Измени заголовок на: Исправление ошибки входа в систему"
+• "Установи высокий приоритет"
+• "Измени срок выполнения на пятницу"
+• "Добавить метку: frontend"
+`,
+		CallbackEditAnswer:       "✏️ Пожалуйста, ответьте на это сообщение с инструкциями по редактированию",
+		CallbackCancelled:        "❌ Создание задачи отменено",
+		CallbackCancelledMsg:     "❌ Создание задачи отменено. Обсуждение продолжается.",
+		CallbackFinished:         "🛑 Обсуждение завершено",
+		CallbackFinishedMsg:      "🛑 Обсуждение завершено без создания задачи.",
+		CallbackKept:             "Обсуждение продолжается",
+		CallbackKeptMsg:          "↩️ Обсуждение продолжается.",
+		CallbackProjectChosen:    "✅ Проект выбран",
+		CallbackProjectChosenMsg: "✅ Проект выбран: %s",
+		AIUnavailable:            "🤖 AI-функции не настроены в этой инсталляции. Обратитесь к администратору бота.",
+		AIInvalidOutput:          "❌ AI вернул задачу в неверном формате даже после исправления. Попробуйте заново или переформулируйте.",
+		AIAnalysisFailed:         "❌ AI суммаризация не удалась(. Попробуйте заново",
+		ErrorProjectLoad:         "❌ Не удалось получить проект чата. Попробуйте позже.",
+		ErrorSessionLoad:         "❌ Не удалось найти обсуждение. Попробуйте позже.",
+		ErrorMessagesLoad:        "❌ Не удалось загрузить обсуждение.",
+		ErrorDraftSave:           "❌ Не удалось сохранить черновик задачи. Попробуйте заново.",
+		SessionNoActive:          "Нет активного обсуждения. Начните его командой /start_discussion.",
+		SessionNotFound:          "Нет обсуждения%s. Начните его командой /start_discussion %s.",
+		CreateTaskOwnerOnly:      "Только автор обсуждения может создать задачу по итогам обсуждения.",
+		CreateTaskNoMessages:     "В обсуждении нет сообщений, чтобы создать задачу.",
+		GuardTooFewMessages:      "В обсуждении пока мало сообщений для задачи: %d из %d. Продолжайте обсуждение и вызовите /create_task позже.",
+		GuardOwnerOnly:           "В обсуждении пока писали только вы. Дождитесь ответа коллег и вызовите /create_task снова.",
+		GuardTooLittleText:       "В обсуждении слишком мало текста для задачи: %d символов из %d. Опишите проблему подробнее и вызовите /create_task снова.",
+		QuotaExceededChat: `⛔ Достигнут лимит для этого чата: %d анализов обсуждений за 24 часа.
+Обсуждение сохранено — попробуйте /create_task позже или обратитесь к администратору бота.`,
+		QuotaExceededUser: `⛔ Достигнут лимит для вас: %d анализов обсуждений за 24 часа.
+Обсуждение сохранено — попробуйте /create_task позже или обратитесь к администратору бота.`,
+		PreviewMissingDetails:        "*Можно ещё уточнить:* похоже, перед созданием задачи стоит обсудить %s.",
+		PreviewLinks:                 "*Полезные материалы:*",
+		TextAnd:                      " и ",
+		DateWeekdays:                 "Воскресенье,Понедельник,Вторник,Среда,Четверг,Пятница,Суббота",
+		DateMonths:                   "января,февраля,марта,апреля,мая,июня,июля,августа,сентября,октября,ноября,декабря",
+		DateFormat:                   "%[1]d %[2]s (%[3]s)",
+		AlternativesReady:            "✅ Готово %d варианта черновика.",
+		AlternativesChoose:           "Выбери вариант, с которого начнём — его можно будет отредактировать перед созданием:",
+		AlternativesOption:           "*Вариант %d.* %s",
+		AlternativesDue:              "Срок: %s",
+		AlternativesPriority:         "Приоритет: %s",
+		ButtonAlternative:            "Вариант %d",
+		RevisionAlternative:          "выбран вариант %d",
+		CallbackStale:                "Кнопка устарела",
+		CallbackOwnerCheckFailed:     "Не удалось проверить автора обсуждения",
+		CallbackOwnerOnlyCreate:      "Только автор обсуждения может создать задачу",
+		CallbackOwnerOnlyEdit:        "Только автор обсуждения может редактировать задачу",
+		CallbackOwnerOnlyCancel:      "Только автор обсуждения может отменить задачу",
+		CallbackOwnerOnlyFinish:      "Только автор обсуждения может завершить его",
+		CallbackOwnerOnlyKeep:        "Только автор обсуждения может продолжить обсуждение",
+		CallbackOwnerOnlyAction:      "Только автор обсуждения может выбрать действие",
+		CallbackOwnerOnlyDecision:    "Записать решение может только автор обсуждения",
+		CallbackOwnerOnlySplit:       "Выбрать подзадачи может только автор обсуждения",
+		CallbackOwnerOnlyAlternative: "Выбрать вариант может только автор обсуждения",
+		CallbackOwnerOnlyUndo:        "Отменить правку может только автор обсуждения",
+		CallbackDraftLoadFailed:      "Не удалось получить черновик задачи",
+		CallbackProjectLoadFailed:    "Не удалось получить проект чата",
+		CallbackTrackerUnavailable:   "Трекер задач чата недоступен",
+		CallbackCreateFailed:         "Не удалось создать задачу",
+		CallbackFinishFailed:         "Не удалось завершить обсуждение",
+		CallbackProjectSaveFailed:    "Не удалось сохранить проект",
+		CallbackProjectsLoadFailed:   "Не удалось загрузить проекты",
+		CallbackNoComments:           "Трекер чата не поддерживает комментарии",
+		CallbackCommentFailed:        "Не удалось добавить комментарий",
+		CallbackMerged:               "📎 Добавлено комментарием",
+		CallbackMergedMsg:            "📎 Черновик добавлен комментарием к существующей задаче, обсуждение завершено.",
+		CallbackMergedTaskMsg:        "📎 Черновик добавлен комментарием к задаче [%s](%s), обсуждение завершено.",
+		DuplicateWarning: `⚠️ *Возможный дубликат:* %s
+Создайте задачу всё равно или добавьте черновик комментарием к существующей.`,
+		CallbackOwnerOnlyQuickEdit: "Редактировать задачу может только автор обсуждения",
+		CallbackTaskCreatedNotice:  "✅ Задача успешно создана",
+		CallbackCancelledNotice:    "❌ Создание задачи отменено. Можете продолжать обсуждение",
+		CallbackKeptNotice:         "✅ Создание задачи отменено, продолжайте обсуждение",
+		SessionNone:                "Нет обсуждения%s.",
+		HistoryLoadFailed:          "❌ Не удалось загрузить историю черновика.",
+		HistoryNoDraft:             "Черновика пока нет. Создайте его командой /create_task.",
+		HistoryHeader:              "🕘 История черновика:",
+		HistoryFromDiscussion:      "черновик по обсуждению",
+		HistoryNoChanges:           "без изменений",
+		HistoryUndoHint:            "Кнопка «%s» под черновиком возвращает предыдущую версию.",
+		DiffNone:                   "нет",
+		DiffChanged:                "%s изменено",
+		DiffDue:                    "Срок",
+		DiffLinks:                  "Полезные материалы",
+		DiffLinkCount:              "%d шт.",
+		ProjectChooseFirstCommand:  "Сначала выберите проект Todoist через /set_project.",
+		SplitTodoistOnly:           "Подзадачи поддерживаются только для Todoist. Создайте задачу через /create_task.",
+		SplitOwnerOnly:             "Только автор обсуждения может разбить его на задачи.",
+		SplitNoMessages:            "В обсуждении нет сообщений, разбивать нечего.",
+		SplitFailed:                "❌ Не удалось разбить обсуждение на подзадачи. Попробуйте заново.",
+		SplitProposal:              "🧩 Предлагаю разбить обсуждение на задачу с подзадачами.",
+		SplitChoose:                "Отметьте подзадачи, которые нужно создать. Подтвердить может только автор обсуждения.",
+		ButtonSplitCreate:          "✅ Создать (%d)",
+		ButtonCancel:               "❌ Отмена",
+		SplitCreateFailed:          "❌ Не удалось создать задачу с подзадачами.",
+		SplitCreated:               "✅ Создана задача «%s» с подзадачами: %d из %d.",
+		SplitPartlyFailed:          "⚠️ Не удалось (%d):",
+		SplitGone:                  "Разбиение уже создано или отменено",
+		SplitCancelled:             "❌ Разбиение отменено. Можете продолжать обсуждение.",
+		SplitNothingChecked:        "Отметьте хотя бы одну подзадачу",
+		SplitUnsupported:           "❌ Подзадачи недоступны для этого Todoist-клиента.",
+		SplitCreateRetry:           "❌ Не удалось создать задачу с подзадачами. Попробуйте позже.",
+		SummaryNoMessages:          "В обсуждении пока нет сообщений.",
+		SummaryFailed:              "❌ Не удалось составить краткое содержание. Попробуйте заново.",
+		SummaryEmpty:               "❌ AI вернул пустое краткое содержание. Попробуйте заново.",
+		SummaryText: `📝 Краткое содержание обсуждения%s (сообщений: %d)
+
+%s
+
+Обсуждение продолжается — задачу можно создать через /create_task.`,
+		ButtonMainSetProject:       "📁 Выбрать проект",
+		ButtonMainStartDiscussion:  "💬 Начать обсуждение",
+		ButtonMainCreateTask:       "✅ Создать задачу",
+		ButtonMainCancel:           "🛑 Завершить обсуждение",
+		ButtonMainList:             "📋 Список задач",
+		ButtonMainHelp:             "❓ Помощь",
+		CommandUnknown:             "Неизвестная команда. Список команд: /help",
+		CommandUnavailable:         "Команда недоступна.",
+		EditDraftLoadFailed:        "❌ Не удалось получить черновик задачи.",
+		EditFailed:                 "❌ Не удалось отредактировать задачу. Попробуйте заново.",
+		EditSaveFailed:             "❌ Не удалось сохранить задачу.",
+		MappingSendDocument:        "❌ Пришлите YAML-файл документом в ответ на сообщение бота.",
+		MappingInternalError:       "❌ Внутренняя ошибка загрузки маппинга.",
+		MappingFileFailed:          "❌ Не удалось получить файл из Telegram.",
+		MappingDownloadFailed:      "❌ Не удалось скачать YAML-файл.",
+		MappingDownloadError:       "❌ Telegram вернул ошибку при скачивании файла.",
+		MappingReadFailed:          "❌ Не удалось прочитать YAML-файл.",
+		MappingCollaboratorsFailed: "❌ Не удалось загрузить участников Todoist-проекта.",
+		MappingImportFailed:        "❌ Не удалось импортировать YAML-маппинг: %v",
+		MappingUpdated: `✅ Маппинг исполнителей обновлён.
+Участников Todoist: %d
+Загружено alias: %d`,
+		MappingSaveFailed:     "❌ Не удалось сохранить маппинг исполнителей.",
+		MappingSaveDuplicates: "❌ Не удалось сохранить маппинг исполнителей: в YAML есть alias, которые после нормализации совпадают. Уберите дубли вроде `@user` и `user` для одного и того же ключа.",
+		MappingSaveError:      "❌ Не удалось сохранить маппинг исполнителей: %s.",
+		DecisionPreparing:     "📝 Готовлю резюме решения…",
+		DecisionNoMessages:    "В обсуждении нет сообщений, резюмировать нечего.",
+		DecisionFailed:        "❌ Не удалось составить резюме решения.",
+		DecisionText: `📝 Решение по обсуждению:
+
+%s`,
+		JobQueued:              "⏳ Запрос поставлен в очередь…",
+		JobQueuedPosition:      "⏳ Запрос поставлен в очередь, позиция: %d",
+		JobQueueFailed:         "❌ Не удалось поставить запрос в очередь. Попробуйте позже.",
+		JobAnalyzing:           "🤖 Анализирую обсуждение…",
+		JobEditQueued:          "⏳ Правка поставлена в очередь…",
+		JobEditQueueFailed:     "❌ Не удалось поставить правку в очередь. Попробуйте позже.",
+		JobEditApplying:        "✏️ Применяю правку…",
+		DocumentFailed:         "❌ Не удалось подготовить файл. Попробуйте позже.",
+		BulkGone:               "Операция уже выполнена или отменена",
+		BulkOwnerOnly:          "Подтвердить может только автор команды",
+		BulkCancelled:          "❌ Операция отменена.",
+		BulkUnsupported:        "❌ Массовые операции недоступны для этого Todoist-клиента.",
+		BulkProgress:           "⏳ Обновляю задачи: %d…",
+		BulkFailed:             "❌ Не удалось обновить задачи. Попробуйте позже.",
+		DeleteOwnerOnly:        "Подтвердить может только тот, кто запросил удаление",
+		DeleteGone:             "Удаление уже выполнено или отменено",
+		DeleteKept:             "↩️ Удаление отменено: «%s» остаётся.",
+		DeleteFailed:           "❌ Не удалось удалить задачу",
+		DeleteDone:             "🗑 Задача «%s» удалена.",
+		DeleteConfirmPrompt:    "Удалить задачу без возможности восстановления? Подтвердить можете только вы.",
+		TaskNotFound:           "Задача не найдена",
+		TaskCompleteFailed:     "❌ Не удалось закрыть задачу",
+		TaskCompleted:          "✅ Выполнено: %s",
+		TaskCompletedInTodoist: "✅ Задача «%s» выполнена в Todoist",
+		TaskCompletedBy:        "✅ Задача «%s» выполнена",
+		AlternativeFailed:      "Не удалось выбрать вариант",
+		UndoNothing:            "Отменять нечего: это первая версия черновика",
+		UndoFailed:             "Не удалось отменить правку",
+		UndoDone:               "↩️ Правка отменена",
+		QuickEditNoDraft:       "Черновик не найден",
+		QuickEditUnchanged:     "Уже так",
+		QuickEditSaveFailed:    "Не удалось сохранить черновик",
+		QuickEditApplied:       "✅ %s",
+		ImportSendDocument:     "❌ Пришлите CSV-файл документом в ответ на сообщение бота.",
+		ImportTooLarge:         "❌ Файл слишком большой, максимум 1 МБ.",
+		ImportInternalError:    "❌ Внутренняя ошибка импорта.",
+		ImportDownloadFailed:   "❌ Не удалось скачать CSV-файл из Telegram.",
+		ImportReadFailed:       "❌ Не удалось прочитать CSV: %v",
+		ImportNothing:          "Импортировать нечего.",
+		ButtonImportCreate:     "✅ Создать %d",
+		ImportGone:             "Импорт уже завершён или отменён",
+		ImportOwnerOnly:        "Подтвердить импорт может только тот, кто загрузил файл",
+		ImportCancelled:        "❌ Импорт отменён.",
+		ImportUnsupported:      "❌ Импорт недоступен для этого Todoist-клиента.",
+		ImportProgress:         "⏳ Создаю задачи: %d…",
+		ImportFailed:           "❌ Не удалось импортировать задачи. Попробуйте позже.",
+		NudgeTaskLoadFailed:    "Не удалось загрузить задачу, попробуйте позже",
+		NudgeAlreadyAssigned:   "Исполнитель уже назначен: %s",
+		NudgeNotMapped:         "Ваш Telegram-ник не сопоставлен с пользователем Todoist, загрузите маппинг через /set_assignee_map",
+		NudgeMappingGone:       "Этого участника больше нет в маппинге исполнителей",
+		NudgeTodoistLoadFailed: "Не удалось загрузить задачу из Todoist",
+		NudgeAssignFailed:      "Не удалось назначить исполнителя в Todoist",
+		NudgeAssigned:          "✅ Исполнитель задачи «%s»: %s (назначил %s)",
+		NoticeDiscussion:       "🎙 Идёт обсуждение, его начал(а) %s. Сообщения чата собираются в задачу: /create_task — подготовить черновик, /cancel — завершить без задачи.",
+		NoticeStarterUnknown:   "участник чата",
+		NoticeClosedCreated:    "✅ Обсуждение завершено: задача создана.",
+		NoticeClosedMerged:     "✅ Обсуждение завершено: добавлено к существующей задаче.",
+		NoticeClosedCanceled:   "🛑 Обсуждение завершено без задачи.",
+		PermissionNotMember:    "бот не состоит в чате — добавьте его заново",
+		PermissionPrivacy:      "бот не видит обычные сообщения: включен режим приватности — сделайте бота администратором или отключите режим в @BotFather (/setprivacy → Disable) и добавьте бота заново",
+		PermissionMuted:        "боту запрещено писать в чат, поэтому он не сможет прислать и обновить черновик — снимите ограничения с бота в настройках участников",
+		PermissionBlocked: `⚠️ Не могу начать обсуждение:
+
+— %s
+
+После этого повторите /start_discussion.`,
+		PrivacyAdmins: "Администраторы чата",
+		PrivacyWarning: `⚠️ Я не вижу обычные сообщения в этом чате: у бота включен режим приватности, поэтому в обсуждение попадут только команды и ответы мне.
+
+%s, сделайте бота администратором чата или отключите режим приватности в @BotFather (/setprivacy → Disable) и добавьте бота в чат заново.`,
+		PreviewClosedNote: `
+
+🔒 _Обсуждение закрыто, кнопки больше не действуют._`,
+		PreviewExpiredNote: `
+
+⌛ _Черновик устарел, начни обсуждение заново._`,
+		QuietHoursDeferred:    "🌅 Пока действовали тихие часы:",
+		ParticipantsSummon:    "👀 Черновик задачи готов, проверьте его: %s",
+		ChannelBusy:           "📢 В канале опубликована задача, но здесь уже идёт обсуждение. Завершите его, чтобы создать задачу из поста.",
+		CooldownWait:          "⏳ /%s можно запускать не чаще раза в %s Попробуйте через %s",
+		WaitSeconds:           "%d сек.",
+		WaitMinutes:           "%d мин.",
+		WaitHours:             "%d ч.",
+		WaitHoursMinutes:      "%d ч. %d мин.",
+		CommandFailed:         "❌ Не удалось выполнить команду. Попробуйте позже.",
+		QuickEditPriority:     "приоритет %s",
+		QuickEditNoDue:        "без срока",
+		QuickEditDue:          "срок %s",
+		Usage:                 "Использование: %s",
+		MoreItems:             "…и ещё %d",
+		DueSuffix:             " (срок: %s)",
+		ImportChooseProject:   "Сначала выберите проект Todoist через /set_project, затем импортируйте задачи.",
+		ImportPrompt:          "Отправьте CSV-файл в ответ на это сообщение.\n\nФормат — как в шаблонах Todoist: колонки `TYPE,CONTENT,DESCRIPTION,PRIORITY,DATE,DATE_LANG`, обязательна только `CONTENT`. Приоритет 1 — самый срочный, метки можно указать в тексте через @.\n\nПеред созданием задач я покажу, что получилось.",
+		ImportFound:           "📥 Найдено задач: %d",
+		ImportSkipped:         "Разделы и заметки пропущены: %d",
+		ImportInvalidRows:     "Строки с ошибками не будут импортированы",
+		ImportDone:            "✅ Импорт завершён: создано %d из %d задач.",
+		ImportCreateFailed:    "Не удалось создать",
+		ImportProblemLine:     "• строка %d: %s",
+		BulkFilterRejected:    "❌ Todoist не принял фильтр. Проверьте запрос, например: overdue, today, @метка, p1.",
+		BulkNoTasks:           "В проекте чата нет задач по фильтру «%s».",
+		ButtonBulkComplete:    "✅ Закрыть %d",
+		ButtonBulkReschedule:  "📅 Перенести %d",
+		BulkPreviewReschedule: "📅 Перенести на «%s» задачи по фильтру «%s» (%d):",
+		BulkPreviewComplete:   "✅ Закрыть задачи по фильтру «%s» (%d):",
+		CommandAuthorConfirms: "Подтвердить может только автор команды.",
+		BulkRescheduled:       "📅 Перенесено задач: %d из %d.",
+		BulkCompleted:         "✅ Закрыто задач: %d из %d.",
+		BulkFailures:          "⚠️ Не удалось (%d):",
+		DeleteTaskNotFound:    "❌ Задача не найдена. Проверьте ID или ссылку.",
+		DeleteConfirm: `🗑 Удалить задачу «%s» без возможности восстановления?
+
+Подтвердить может только автор команды.`,
+		ButtonDelete:         "🗑 Удалить «%s»",
+		ButtonDeleteKeep:     "↩️ Отмена",
+		ListDue:              "Срок",
+		ListProject:          "Проект",
+		ListProjectsHeading:  "Ваши проекты",
+		ListProjectTasksHint: "Задачи: Используйте",
+		ListFooter: `
+*Полезные команды:*
+/create_task — создать задачу из обсуждения
+/start_discussion — начать обсуждение
+/cancel — завершить обсуждение без задачи
+`,
+		ListFilterOverdue:      "просроченные",
+		ListFilterToday:        "на сегодня",
+		ListFilterUpcoming:     "на ближайшие %d дней",
+		ListFilterNoDue:        "без срока",
+		ListTrackerUnavailable: "❌ Трекер задач чата недоступен: %s",
+		ListTasksFailed:        "❌ *Ошибка получения задач:* %v",
+		ListProjectsFailed:     "❌ *Ошибка получения проектов:* %v",
+		ListNoTasksInProject:   "В проекте \"%s\" задач%s не найдено.",
+		ListNoTasksInProjectID: "В проекте с ID %s задач%s не найдено.",
+		ListNoTasks:            "Задач%s не найдено.",
+		ListYourTasks:          "Ваши задачи",
+		ListProjectTasks:       "Задачи в проекте %s",
+		ListPage:               ", стр. %d/%d",
+		ButtonListPrev:         "◀️ Назад",
+		ButtonListNext:         "Вперёд ▶️",
+		ListNoProjects:         "Проекты не найдены.",
+		SettingSaveFailed:      "Не удалось изменить настройку. Попробуйте позже.",
+		AdminOnlyCommand:       "Команда доступна только администраторам бота.",
+		CancelNoSession:        "Нет активного обсуждения%s.",
+		CancelOwnerOnly:        "Только автор обсуждения может завершить его.",
+		CancelConfirm:          "Завершить обсуждение%s без создания задачи?",
+		ButtonFinishDiscussion: "🛑 Завершить",
+		ButtonKeepDiscussion:   "↩️ Продолжить",
+		ButtonDecisionSummary:  "📝 Записать решение",
+		PromptUsage: `Использование: /set_prompt create <правила> или /set_prompt edit <правила>
+Правила добавляются к стандартному промпту, например: «Заголовок начинай с глагола, в описании всегда раздел Критерии готовности».`,
+		PromptAdminOnly: "Менять правила для AI может только администратор бота.",
+		PromptTooLong:   "Правила слишком длинные: %d символов, можно до %d.",
+		PromptSaved: `✅ Правила для %s сохранены и будут добавляться к стандартному промпту.
+Сбросить: /reset_prompt %s`,
+		PromptResetUsage:     "Использование: /reset_prompt, /reset_prompt create или /reset_prompt edit",
+		PromptReset:          "Правила сброшены, AI работает по стандартным промптам.",
+		PromptHeader:         "Правила для AI в этом чате:",
+		PromptDefault:        "не заданы, стандартный промпт",
+		PromptEditTitle:      "правки задачи",
+		PromptCreateTitle:    "создания задачи",
+		DebugUnsupported:     "Текущий AI-клиент не поддерживает отладку анализа.",
+		DebugStarting:        "🔬 Запускаю отладочный анализ обсуждения, черновик не сохраняется…",
+		DebugParsed:          "JSON разобран",
+		DebugParseError:      "ошибка разбора: %s",
+		DebugCaption:         "🔬 %s — токенов: %d (промпт %d, ответ %d), %s. Персональные данные в промпте скрыты.",
+		DigestUsage:          "Использование: /digest_time 09:30, /digest_time off, /digest_time suggest или /digest_time suggest apply",
+		DigestUnset:          "Время сводок и напоминаний не задано, они приходят сразу.",
+		DigestAt:             "Сводки и напоминания приходят в %s (%s).",
+		DigestReset:          "Время сводок и напоминаний сброшено, они приходят сразу.",
+		DigestBadTime:        "Не понял время.",
+		DigestSet:            "Сводки и напоминания будут приходить в %s (%s).",
+		DigestActivityFailed: "Не удалось загрузить активность чата. Попробуйте позже.",
+		DigestTooLittleData:  "Пока мало данных: нужно хотя бы %d сообщений в обсуждениях за последние 4 недели.",
+		DigestActivity:       "Активность обсуждений за 4 недели (%s), сообщений: %d",
+		DigestApplied:        "Чаще всего в чате пишут с %s — сводки и напоминания теперь будут приходить в это время.",
+		DigestSuggested:      "Чаще всего в чате пишут с %s. Присылать сводки и напоминания в это время: /digest\\_time suggest apply",
+		JobsUsage:            "Использование: /jobs, /jobs cancel <id> или /jobs retry <id>",
+		JobsCanceled:         "Задача #%d отменена.",
+		JobsRetried:          "Задача #%d поставлена в очередь повторно.",
+		JobsNotFound:         "Задача #%d не найдена.",
+		JobsActionFailed:     "Не удалось выполнить действие с задачей #%d: %v",
+		JobsEmpty:            "Очередь AI-задач пуста.",
+		JobsHeader:           "Очередь AI-задач:",
+		JobsLine:             "#%d %s · %s · чат %d · %s · повторов: %d",
+		JobsError:            "ошибка: %s",
+		PlanAdminOnly:        "Сменить тариф может только администратор бота.",
+		PlanUnknown:          "Неизвестный тариф %q.",
+		PlanSetFailed:        "Не удалось сменить тариф. Попробуйте позже.",
+		PlanSet:              "Тариф чата: %s.",
+		PlanStatusFailed:     "Не удалось получить тариф. Попробуйте позже.",
+		PlanStatus:           "Тариф чата: %s",
+		PlanUsage:            "%s в этом месяце: %d из %d",
+		PlanUsageUnlimited:   "%s в этом месяце: %d (без лимита)",
+		PlanExhausted:        "💳 %s на тарифе «%s» закончились: использовано %d из %d в этом месяце.",
+		PlanUpgradeURL:       "Перейдите на тариф pro: %s",
+		PlanUpgradeAdmin:     "Чтобы перейти на тариф pro, обратитесь к администратору бота.",
+		PlanFeatureAIEdit:    "AI-правки",
+		UsageFailed:          "❌ Не удалось получить расход токенов. Попробуйте позже.",
+		UsageHeader:          "📊 Расход AI-токенов с %s",
+		UsageNone:            "Запросов к AI в этом месяце не было.",
+		UsageMoreChats:       "…и ещё чатов: %d",
+		UsageChat:            "Чат %d",
+		UsageNoChat:          "Вне чатов",
+		UsageTotal:           "Итого: %s",
+		UsageUnpriced:        "⚠️ Нет цены в %s, в стоимость не вошли: %s",
+		UsageLine:            "%d запр., %d токенов (вход %d, выход %d) ≈ $%.2f",
+		QuickTodoistOnly:     "Быстрое добавление работает только для Todoist. Создайте задачу через /start_discussion.",
+		QuickUsage: `Использование: /quick <текст задачи>
+Todoist сам разберёт срок («завтра в 10»), приоритет (p1–p4), метки (@метка) и проект (#Проект); без проекта задача попадёт во «Входящие».`,
+		QuickFailed:                "❌ Не удалось создать задачу. Попробуйте позже.",
+		QuickCreated:               "✅ *Задача создана*: [%s](%s)",
+		QuickDue:                   "*Срок:* %s",
+		QuickLabels:                "*Метки:* %s",
+		MapUserFormat:              "Формат: /map_user @username email участника Todoist, например /map_user @alice alice@example.com",
+		MapUserCollaboratorsFailed: "Не удалось загрузить участников проекта Todoist. Попробуйте позже.",
+		MapUserSaveFailed:          "Не удалось сохранить маппинг",
+		MapUserSaved:               "✅ Задачи для %s будут назначаться на %s (%s)",
+		MapUserNotFound:            "%s не найден среди участников выбранного проекта Todoist. Проверьте email и что человек добавлен в проект.",
+		MapUserLoadFailed:          "Не удалось загрузить маппинг исполнителей",
+		MapUserEmpty:               "Маппинг исполнителей пуст. Добавьте участника: /map_user @username email",
+		MapUserHeader:              "Исполнители проекта:",
+		NotifyListFailed:           "Не удалось получить список уведомлений. Попробуйте позже.",
+		NotifyNone:                 "Уведомления не настроены.",
+		NotifyHeader:               "Уведомления чата:",
+		NotifyRemoveHint:           "Удалить: /notify remove <номер>",
+		NotifyAddFailed:            "Не удалось добавить уведомление: %v",
+		NotifyAdded:                "Уведомление %d добавлено: %s → %s%s",
+		NotifySigned:               "Запросы подписаны: заголовок %s содержит HMAC-SHA256 тела. Удалите сообщение с секретом из чата.",
+		NotifyNumberRequired:       "Укажите номер уведомления из /notify list.",
+		NotifyNotFound:             "Уведомление %d не найдено.",
+		NotifyRemoved:              "Уведомление %d удалено.",
+		NotifyUsage: `Добавить: /notify add <тип> <адрес>, типы: %s
+Например: /notify add webhook https://example.com/hook [секрет] или /notify add telegram -1001234567890`,
+		NudgeOwner:                 "автор обсуждения",
+		NudgeUntitled:              "без названия",
+		NudgeReminder:              "⏰ %s, у задачи «%s» до сих пор нет исполнителя. Кто её возьмёт?",
+		ButtonNudgeTake:            "🙋 Беру себе",
+		ParticipantsSummonOff:      "Больше не упоминаю участников, когда черновик готов.",
+		ParticipantsSummonOn:       "Когда черновик будет готов, упомяну всех участников обсуждения, чтобы они его проверили.",
+		ParticipantsUsage:          "Использование: /participants или /participants summon on|off",
+		ParticipantsLoadFailed:     "Не удалось получить участников. Попробуйте позже.",
+		ParticipantsNone:           "В обсуждении пока никто не написал.",
+		ParticipantsHeader:         "👥 *Участники обсуждения* (%d):",
+		ParticipantsLine:           "• %s — сообщений: %d",
+		ParticipantsSummonEnabled:  "Когда черновик будет готов, упомяну всех. Выключить: /participants summon off",
+		ParticipantsSummonDisabled: "Звать всех к готовому черновику: /participants summon on",
+		ParticipantsUnnamed:        "участник",
+		PriorityNamesCurrent:       "Приоритеты в этом чате:",
+		PriorityNamesHint: `Переименовать: /priority_names high=Мажор, urgent=Блокер
+Вернуть стандартные: /priority_names reset`,
+		PriorityNamesReset:    "Названия приоритетов сброшены:",
+		PriorityNamesInvalid:  "Не понял названия. Уровни: low, medium, high, urgent. Пример: /priority_names high=Мажор, urgent=Блокер",
+		ProductivityFailed:    "❌ Не удалось получить статистику из Todoist. Попробуйте позже.",
+		ProductivityHeader:    "📈 Закрытые задачи проекта",
+		ProductivityDays:      "За %d дней: %s",
+		ProductivityTotal:     "Всего %d, в среднем %.1f в день",
+		ProductivityBestDay:   ", лучший день %s — %d",
+		ProductivityWeeks:     "По неделям: ",
+		ProductivityWeeksNote: " (последние 7 дней справа)",
+		ProductivityKarma:     "🏆 Карма Todoist: %.0f%s",
+		QuietHoursUnset:       "Тихие часы не заданы. Пример: /quiet_hours 22:00-08:00",
+		QuietHoursStatus:      "Тихие часы: %s (%s). Уведомления за это время придут одной сводкой утром. Выключить: /quiet_hours off",
+		QuietHoursOff:         "Тихие часы выключены.",
+		QuietHoursInvalid:     "Не понял время. Использование: /quiet_hours 22:00-08:00 или /quiet_hours off",
+		QuietHoursSet:         "Тихие часы: %s (%s). Уведомления за это время придут одной сводкой после их окончания.",
+		QuotaAdminOnly:        "Снять или вернуть лимиты может только администратор бота.",
+		QuotaSetFailed:        "Не удалось изменить лимиты. Попробуйте позже.",
+		QuotaRestored:         "Лимиты для этого чата снова действуют.",
+		QuotaLifted:           "Лимиты для этого чата сняты.",
+		QuotaUsage:            "Использование: /quota, /quota off или /quota on",
+		QuotaDisabled:         "Лимиты на анализ обсуждений не настроены.",
+		QuotaExempt:           "Для этого чата лимиты сняты администратором.",
+		QuotaStatsFailed:      "Не удалось получить статистику лимитов. Попробуйте позже.",
+		QuotaHeader:           "Анализы обсуждений за 24 часа:",
+		QuotaChat:             "Чат: %d из %d",
+		QuotaUser:             "Вы: %d из %d",
+		ReactionsStatusOff:    "Реакции на сохранённые сообщения выключены. Включить: /reactions on",
+		ReactionsStatusOn:     "Реакции на сохранённые сообщения включены. Выключить: /reactions off",
+		ReactionsOff:          "Больше не отмечаю сохранённые сообщения.",
+		ReactionsOn:           "Буду отмечать реакцией каждое сообщение, сохранённое в обсуждение.",
+		ReactionsUsage:        "Использование: /reactions, /reactions on или /reactions off",
+		SpeakNoDraft:          "Нет черновика задачи для озвучивания. Сначала выполните /create_task.",
+		SpeakOff:              "🔇 Черновики больше не озвучиваются.",
+		SpeakOn:               "🔊 Теперь каждый черновик задачи будет дублироваться голосовым сообщением.",
+		SpeakUsage:            "Использование: /speak, /speak on или /speak off",
+		SectionChoose:         "Выберите раздел проекта",
+		ButtonNoSection:       "Без раздела",
+		SectionProjectFirst:   "Сначала выберите проект: /set_project",
+		SectionAskEachTime:    "Раздел будет спрашиваться при каждом подтверждении задачи.",
+		TrackerUnavailable:    "Трекер задач недоступен. Попробуйте позже.",
+		SectionNone:           "В проекте чата нет разделов, задачи создаются без раздела.",
+		SectionSet:            "Новые задачи будут попадать в раздел «%s».",
+		SectionNotFound:       "Раздел «%s» не найден. Разделы проекта: %s",
+		SectionUnset:          "не выбран — бот спрашивает при подтверждении задачи",
+		SectionStatus: `Раздел для новых задач: %s
+Разделы проекта: %s
+
+Выбрать: /section <название>, спрашивать каждый раз: /section off`,
+		AssigneeMapProjectFirst:  "Сначала выберите проект Todoist через /set_project, затем загрузите YAML-маппинг исполнителей.",
+		AssigneeMapPrompt:        "Отправьте YAML-файл маппинга в ответ на это сообщение.\n\nПример:\n```yaml\nversion: 1\nassignees:\n  - todoist_email: \"alice@example.com\"\n    telegram_aliases: [\"@alice\", \"alice\", \"Алиса\"]\n```\n\nФайл заменит текущий маппинг для выбранного проекта.",
+		ProjectChoose:            "Выберите проект Todoist:",
+		ProjectBadLink:           "Не понял ссылку. Скопируйте ссылку на проект в Todoist или выберите проект без аргументов: /set_project",
+		ProjectsLoadFailed:       "Не удалось загрузить проекты Todoist: %v",
+		ProjectSaveFailed:        "Не удалось сохранить проект",
+		ProjectSelected:          "✅ Проект выбран: %s",
+		ProjectLinkNotFound:      "Проект по ссылке не найден среди доступных боту. Выберите проект Todoist:",
+		ProjectsNone:             "В Todoist не найдено ни одного проекта.",
+		DiscussionBadName:        "Название обсуждения — одно слово из букв, цифр, «-» и «_», не длиннее %d символов, например /start_discussion billing-bug",
+		DiscussionProjectFailed:  "Не удалось получить проект чата: %v",
+		DiscussionAlreadyRunning: "Обсуждение уже идёт! Прежде, чем начать новое завершите текущее или начните параллельное с названием: /start_discussion <название>.",
+		DiscussionNamedRunning:   "Обсуждение «%s» уже идёт! Завершите его (/cancel %s) или выберите другое название.",
+		DiscussionStartFailed:    "Не удалось начать обсуждение: %v",
+		DiscussionStarted: `Обсуждение началось.
+Сообщения будут сохраняться, пока вы не создадите задачу (/create_task) или не завершите обсуждение (/cancel).`,
+		DiscussionNamedStarted: `Обсуждение%[1]s началось.
+В него попадают ответы на его сообщения и сообщения с #%[2]s. Остальные сообщения идут в обсуждение без названия, а если его нет — в последнее начатое. Создать задачу: /create_task %[2]s, завершить: /cancel %[2]s.`,
+		StatusHeader:              "ℹ️ Состояние бота в чате",
+		StatusTracker:             "🗂 Трекер: %s",
+		StatusDefaultZone:         " (по умолчанию)",
+		StatusTimezone:            "🕒 Часовой пояс: %s",
+		StatusSessionsFailed:      "💬 Не удалось проверить обсуждения.",
+		StatusNoSessions:          "💬 Обсуждения нет. Начать: /start_discussion",
+		StatusOneSession:          "💬 Идёт обсуждение",
+		StatusSessions:            "💬 Идут обсуждения (%d)",
+		StatusUnnamed:             "без названия",
+		StatusSession:             "• %s — начал %s %s, сообщений: %s",
+		StatusNoProject:           "📁 Проект не выбран: /set_project",
+		StatusProjectID:           "📁 Проект: ID %s",
+		StatusProject:             "📁 Проект: %s (ID %s)",
+		DefaultsUsage:             "Использование: /task_defaults due=+7d priority=medium labels=from-telegram или /task_defaults reset",
+		DefaultsAdminOnly:         "Менять значения по умолчанию может только администратор бота.",
+		DefaultsInvalid:           "Не понял настройки: %v",
+		DefaultsDue:               "срок",
+		DefaultsPriority:          "приоритет",
+		DefaultsLabels:            "метки",
+		DefaultsApplied:           "⚙️ По умолчанию чата: %s",
+		DefaultsUnset:             "Значения по умолчанию для задач не заданы.",
+		DefaultsHeader:            "Значения по умолчанию для задач, если их нет в обсуждении:",
+		DefaultsDueLine:           "• срок — через %d дн.",
+		DefaultsPriorityLine:      "• приоритет — %s",
+		DefaultsLabelsLine:        "• метки (всегда) — %s",
+		ViewTodayHeading:          "📅 Задачи на сегодня",
+		ViewTodayEmpty:            "На сегодня задач в проекте чата нет.",
+		ViewUpcomingHeading:       "🗓 Задачи на %d дней",
+		ViewUpcomingEmpty:         "На ближайшие %d дней задач в проекте чата нет.",
+		ViewOverdueHeading:        "⏰ Просроченные задачи",
+		ViewOverdueEmpty:          "Просроченных задач в проекте чата нет.",
+		ViewTodoistOnly:           "Подборки задач работают только для Todoist. Используйте /list.",
+		ViewProjectFirst:          "Сначала выберите проект Todoist через /set\\_project.",
+		ViewTasksFailed:           "❌ Не удалось получить задачи из Todoist. Попробуйте позже.",
+		TimezoneUsage:             "Использование: /timezone Europe/Berlin — часовой пояс из базы IANA, /timezone reset — пояс по умолчанию",
+		TimezoneDefault:           "Часовой пояс чата: %s (по умолчанию).",
+		TimezoneCurrent:           "Часовой пояс чата: %s.",
+		TimezoneReset:             "Часовой пояс сброшен на пояс по умолчанию: %s.",
+		TimezoneUnknown:           "Не знаю часового пояса %q. Укажите его как в базе IANA, например Europe/Moscow или Asia/Yekaterinburg.",
+		TimezoneSet:               "Часовой пояс чата: %s. Сроки, тихие часы и время сводок теперь считаются в нём.",
+		SettingLoadFailed:         "Не удалось получить настройку. Попробуйте позже.",
+		TranscriptChange:          "Изменить: /transcript off|description|comment",
+		TranscriptUsage:           "Использование: /transcript off|description|comment",
+		TranscriptModeDescription: "Переписка обсуждения добавляется в конец описания задачи.",
+		TranscriptModeComment:     "Переписка обсуждения добавляется комментариями к задаче Todoist.",
+		TranscriptModeOff:         "Переписка обсуждения в задачу не попадает.",
+		BackupProjectFailed:       "Не удалось получить проект чата. Попробуйте позже.",
+		BackupNoProject:           "Для чата не выбран проект Todoist. Выберите его командой /set_project.",
+		BackupStarting:            "📦 Собираю резервную копию проекта, это может занять минуту…",
+		BackupCaption:             "📦 «%s» — задач: %d, разделов: %d. Выгружено %s UTC.",
+	},
+	English: {
+		StartWelcome: `🤖 Hi! I'm JiraF, an AI Task Assistant 🤖
+
+I turn chat discussions into ready-to-go tasks.
+
+🔧 What I can do:
+— analyze a discussion
+— draft a task from it
+— send the task to Todoist
+
+📋 How to use me:
+1️⃣ Choose a project
+2️⃣ Start a discussion
+3️⃣ Create a task from the discussion
+
+Tap any button below for quick access:`,
+		StartChooseProject: "First choose a Todoist project:",
+		Help:               "🧩 All commands:",
+		HelpFooter:         "Use the buttons below for quick access:",
+		LanguageCurrent:    "Reply language: %s.\n\nChange it: /language ru, /language en or /language auto to follow your Telegram language.",
+		LanguageAuto:       "The reply language follows your Telegram language again.",
+		LanguageSet:        "Done, replying in %s.",
+		LanguageUsage:      "Usage: /language ru|en|auto",
+		LanguageSaveFailed: "Could not save the language. Please try again later.",
+		LanguageName:       "English",
+		LanguageChat:       "Chat language: %s.\n\nTask drafts, buttons and confirmations are shown in it. Change it: /language ru, /language en or /language auto for the default language.",
+		LanguageChatSet:    "Done, the chat language is %s.",
+		LanguageChatReset:  "The chat language is back to the default one.",
+
+		PreviewDraftReady:        "✅ The task draft is ready.",
+		PreviewUpdated:           "✅ Task updated!\n\nChanges saved:\n",
+		PreviewAlternativeChosen: "✅ Option %d chosen.",
+		PreviewChooseAction:      "Check the description and choose an action:",
+		PreviewMissingHint:       "If you like, tap `Edit` and add this to the task.",
+		PreviewReplyHint:         "If you like, just reply to this message and add this to the task.",
+		PreviewUndone:            "↩️ Edit undone, the draft is back to its previous version:\n",
+		PreviewTitle:             "Title",
+		PreviewDescription:       "Description",
+		PreviewDue:               "Due",
+		PreviewPriority:          "Priority",
+		PreviewTaskType:          "Task type",
+		PreviewAssignee:          "Assignee",
+		PreviewLabels:            "Labels",
+		TaskTypeTask:             "Task",
+		TaskTypeBug:              "Bug",
+		TaskTypeEpic:             "Epic",
+		ButtonConfirm:            "✅ Confirm",
+		ButtonEdit:               "✏️ Edit",
+		ButtonCancelCreation:     "❌ Cancel",
+		ButtonUndoEdit:           "↩️ Undo edit",
+		ButtonCreateAnyway:       "✅ Create anyway",
+		ButtonOpenExisting:       "🔗 Open existing",
+		ButtonMergeComment:       "📎 Add as comment",
+		ButtonDueToday:           "📅 Today",
+		ButtonDueTomorrow:        "📅 Tomorrow",
+		ButtonDueNextWeek:        "📅 Next week",
+		ButtonDueClear:           "📅 No due date",
+
+		CallbackCreating:          "✅ Great! Creating the task.",
+		CallbackTaskCreated:       "✅ *Task created*: [%s](%s)",
+		CallbackAlreadyCreated:    "The task is already created",
+		CallbackAlreadyCreatedMsg: "ℹ️ *The task is already created*: [%s](%s)",
+		CallbackEditPrompt: `
+✏️ Edit the task
+Please reply to this message with your edit instructions in free form.
+Examples:
+• "Change the title to: Fix the login error"
+• "Set high priority"
+• "Move the due date to Friday"
+• "Add label: frontend"
+`,
+		CallbackEditAnswer:       "✏️ Please reply to this message with your edit instructions",
+		CallbackCancelled:        "❌ Task creation cancelled",
+		CallbackCancelledMsg:     "❌ Task creation cancelled. The discussion goes on.",
+		CallbackFinished:         "🛑 Discussion finished",
+		CallbackFinishedMsg:      "🛑 The discussion finished without a task.",
+		CallbackKept:             "The discussion goes on",
+		CallbackKeptMsg:          "↩️ The discussion goes on.",
+		CallbackProjectChosen:    "✅ Project chosen",
+		CallbackProjectChosenMsg: "✅ Project chosen: %s",
+		AIUnavailable:            "🤖 AI features are not set up in this installation. Please contact the bot administrator.",
+		AIInvalidOutput:          "❌ The AI returned the task in a wrong format even after a repair. Please try again or rephrase.",
+		AIAnalysisFailed:         "❌ The AI analysis failed. Please try again",
+		ErrorProjectLoad:         "❌ Could not load the chat's project. Please try again later.",
+		ErrorSessionLoad:         "❌ Could not find the discussion. Please try again later.",
+		ErrorMessagesLoad:        "❌ Could not load the discussion.",
+		ErrorDraftSave:           "❌ Could not save the task draft. Please try again.",
+		SessionNoActive:          "There is no active discussion. Start one with /start_discussion.",
+		SessionNotFound:          "There is no discussion%s. Start it with /start_discussion %s.",
+		CreateTaskOwnerOnly:      "Only the author of the discussion can create a task from it.",
+		CreateTaskNoMessages:     "The discussion has no messages to create a task from.",
+		GuardTooFewMessages:      "The discussion has too few messages for a task yet: %d of %d. Keep discussing and call /create_task later.",
+		GuardOwnerOnly:           "So far only you have written in the discussion. Wait for your colleagues to answer and call /create_task again.",
+		GuardTooLittleText:       "The discussion has too little text for a task: %d characters of %d. Describe the problem in more detail and call /create_task again.",
+		QuotaExceededChat: `⛔ This chat has reached its limit of %d discussion analyses in 24 hours.
+The discussion is kept, so try /create_task later or contact the bot administrator.`,
+		QuotaExceededUser: `⛔ You have reached your limit of %d discussion analyses in 24 hours.
+The discussion is kept, so try /create_task later or contact the bot administrator.`,
+		PreviewMissingDetails:        "*Worth clarifying:* it looks like %s should be discussed before creating the task.",
+		PreviewLinks:                 "*Useful links:*",
+		TextAnd:                      " and ",
+		DateWeekdays:                 "Sunday,Monday,Tuesday,Wednesday,Thursday,Friday,Saturday",
+		DateMonths:                   "January,February,March,April,May,June,July,August,September,October,November,December",
+		DateFormat:                   "%[2]s %[1]d (%[3]s)",
+		AlternativesReady:            "✅ %d draft options are ready.",
+		AlternativesChoose:           "Choose the option to start from; you can edit it before the task is created:",
+		AlternativesOption:           "*Option %d.* %s",
+		AlternativesDue:              "Due: %s",
+		AlternativesPriority:         "Priority: %s",
+		ButtonAlternative:            "Option %d",
+		RevisionAlternative:          "option %d chosen",
+		CallbackStale:                "This button is out of date",
+		CallbackOwnerCheckFailed:     "Could not verify the discussion author",
+		CallbackOwnerOnlyCreate:      "Only the discussion author can create the task",
+		CallbackOwnerOnlyEdit:        "Only the discussion author can edit the task",
+		CallbackOwnerOnlyCancel:      "Only the discussion author can cancel the task",
+		CallbackOwnerOnlyFinish:      "Only the discussion author can finish it",
+		CallbackOwnerOnlyKeep:        "Only the discussion author can keep the discussion going",
+		CallbackOwnerOnlyAction:      "Only the discussion author can choose the action",
+		CallbackOwnerOnlyDecision:    "Only the discussion author can record the decision",
+		CallbackOwnerOnlySplit:       "Only the discussion author can choose the subtasks",
+		CallbackOwnerOnlyAlternative: "Only the discussion author can choose the option",
+		CallbackOwnerOnlyUndo:        "Only the discussion author can undo the edit",
+		CallbackDraftLoadFailed:      "Could not load the task draft",
+		CallbackProjectLoadFailed:    "Could not load the chat's project",
+		CallbackTrackerUnavailable:   "The chat's task tracker is not available",
+		CallbackCreateFailed:         "Could not create the task",
+		CallbackFinishFailed:         "Could not finish the discussion",
+		CallbackProjectSaveFailed:    "Could not save the project",
+		CallbackProjectsLoadFailed:   "Could not load the projects",
+		CallbackNoComments:           "The chat's tracker does not support comments",
+		CallbackCommentFailed:        "Could not add the comment",
+		CallbackMerged:               "📎 Added as a comment",
+		CallbackMergedMsg:            "📎 The draft was added as a comment to the existing task, the discussion is finished.",
+		CallbackMergedTaskMsg:        "📎 The draft was added as a comment to the task [%s](%s), the discussion is finished.",
+		DuplicateWarning: `⚠️ *Possible duplicate:* %s
+Create the task anyway or add the draft as a comment to the existing one.`,
+		CallbackOwnerOnlyQuickEdit: "Only the discussion author can edit the task",
+		CallbackTaskCreatedNotice:  "✅ The task was created",
+		CallbackCancelledNotice:    "❌ Task creation cancelled. You can keep discussing",
+		CallbackKeptNotice:         "✅ Task creation cancelled, keep discussing",
+		SessionNone:                "There is no discussion%s.",
+		HistoryLoadFailed:          "❌ Could not load the draft history.",
+		HistoryNoDraft:             "There is no draft yet. Create one with /create_task.",
+		HistoryHeader:              "🕘 Draft history:",
+		HistoryFromDiscussion:      "draft from the discussion",
+		HistoryNoChanges:           "no changes",
+		HistoryUndoHint:            "The «%s» button under the draft brings the previous version back.",
+		DiffNone:                   "none",
+		DiffChanged:                "%s changed",
+		DiffDue:                    "Due",
+		DiffLinks:                  "Useful links",
+		DiffLinkCount:              "%d",
+		ProjectChooseFirstCommand:  "First choose a Todoist project with /set_project.",
+		SplitTodoistOnly:           "Subtasks are only supported for Todoist. Create a task with /create_task.",
+		SplitOwnerOnly:             "Only the discussion author can split it into tasks.",
+		SplitNoMessages:            "The discussion has no messages, there is nothing to split.",
+		SplitFailed:                "❌ Could not split the discussion into subtasks. Please try again.",
+		SplitProposal:              "🧩 I suggest splitting the discussion into a task with subtasks.",
+		SplitChoose:                "Check the subtasks to create. Only the discussion author can confirm.",
+		ButtonSplitCreate:          "✅ Create (%d)",
+		ButtonCancel:               "❌ Cancel",
+		SplitCreateFailed:          "❌ Could not create the task with subtasks.",
+		SplitCreated:               "✅ Created the task «%s» with %d of %d subtasks.",
+		SplitPartlyFailed:          "⚠️ Failed (%d):",
+		SplitGone:                  "The split is already created or cancelled",
+		SplitCancelled:             "❌ Split cancelled. You can keep discussing.",
+		SplitNothingChecked:        "Check at least one subtask",
+		SplitUnsupported:           "❌ Subtasks are not available for this Todoist client.",
+		SplitCreateRetry:           "❌ Could not create the task with subtasks. Please try again later.",
+		SummaryNoMessages:          "The discussion has no messages yet.",
+		SummaryFailed:              "❌ Could not summarize the discussion. Please try again.",
+		SummaryEmpty:               "❌ The AI returned an empty summary. Please try again.",
+		SummaryText: `📝 Summary of the discussion%s (%d messages)
+
+%s
+
+The discussion goes on; create a task with /create_task.`,
+		ButtonMainSetProject:       "📁 Choose project",
+		ButtonMainStartDiscussion:  "💬 Start discussion",
+		ButtonMainCreateTask:       "✅ Create task",
+		ButtonMainCancel:           "🛑 End discussion",
+		ButtonMainList:             "📋 Task list",
+		ButtonMainHelp:             "❓ Help",
+		CommandUnknown:             "Unknown command. See /help for the list of commands.",
+		CommandUnavailable:         "The command is not available.",
+		EditDraftLoadFailed:        "❌ Could not load the task draft.",
+		EditFailed:                 "❌ Could not edit the task. Please try again.",
+		EditSaveFailed:             "❌ Could not save the task.",
+		MappingSendDocument:        "❌ Send the YAML file as a document in reply to the bot's message.",
+		MappingInternalError:       "❌ Internal error while loading the mapping.",
+		MappingFileFailed:          "❌ Could not get the file from Telegram.",
+		MappingDownloadFailed:      "❌ Could not download the YAML file.",
+		MappingDownloadError:       "❌ Telegram returned an error while downloading the file.",
+		MappingReadFailed:          "❌ Could not read the YAML file.",
+		MappingCollaboratorsFailed: "❌ Could not load the members of the Todoist project.",
+		MappingImportFailed:        "❌ Could not import the YAML mapping: %v",
+		MappingUpdated: `✅ The assignee mapping is updated.
+Todoist members: %d
+Aliases loaded: %d`,
+		MappingSaveFailed:     "❌ Could not save the assignee mapping.",
+		MappingSaveDuplicates: "❌ Could not save the assignee mapping: the YAML has aliases that match after normalization. Remove duplicates like `@user` and `user` for the same key.",
+		MappingSaveError:      "❌ Could not save the assignee mapping: %s.",
+		DecisionPreparing:     "📝 Preparing a summary of the decision…",
+		DecisionNoMessages:    "The discussion has no messages, there is nothing to summarize.",
+		DecisionFailed:        "❌ Could not summarize the decision.",
+		DecisionText: `📝 Decision of the discussion:
+
+%s`,
+		JobQueued:              "⏳ The request is queued…",
+		JobQueuedPosition:      "⏳ The request is queued, position: %d",
+		JobQueueFailed:         "❌ Could not queue the request. Please try again later.",
+		JobAnalyzing:           "🤖 Analyzing the discussion…",
+		JobEditQueued:          "⏳ The edit is queued…",
+		JobEditQueueFailed:     "❌ Could not queue the edit. Please try again later.",
+		JobEditApplying:        "✏️ Applying the edit…",
+		DocumentFailed:         "❌ Could not prepare the file. Please try again later.",
+		BulkGone:               "The operation is already done or canceled",
+		BulkOwnerOnly:          "Only the author of the command can confirm",
+		BulkCancelled:          "❌ The operation is canceled.",
+		BulkUnsupported:        "❌ Bulk operations are not available for this Todoist client.",
+		BulkProgress:           "⏳ Updating tasks: %d…",
+		BulkFailed:             "❌ Could not update the tasks. Please try again later.",
+		DeleteOwnerOnly:        "Only the user who asked to delete can confirm",
+		DeleteGone:             "The deletion is already done or canceled",
+		DeleteKept:             "↩️ Deletion canceled: «%s» stays.",
+		DeleteFailed:           "❌ Could not delete the task",
+		DeleteDone:             "🗑 The task «%s» is deleted.",
+		DeleteConfirmPrompt:    "Delete the task for good? Only you can confirm.",
+		TaskNotFound:           "Task not found",
+		TaskCompleteFailed:     "❌ Could not complete the task",
+		TaskCompleted:          "✅ Done: %s",
+		TaskCompletedInTodoist: "✅ The task «%s» is completed in Todoist",
+		TaskCompletedBy:        "✅ The task «%s» is completed",
+		AlternativeFailed:      "Could not choose the option",
+		UndoNothing:            "Nothing to undo: this is the first version of the draft",
+		UndoFailed:             "Could not undo the edit",
+		UndoDone:               "↩️ The edit is undone",
+		QuickEditNoDraft:       "Draft not found",
+		QuickEditUnchanged:     "Already set",
+		QuickEditSaveFailed:    "Could not save the draft",
+		QuickEditApplied:       "✅ %s",
+		ImportSendDocument:     "❌ Send the CSV file as a document in reply to the bot's message.",
+		ImportTooLarge:         "❌ The file is too large, 1 MB at most.",
+		ImportInternalError:    "❌ Internal import error.",
+		ImportDownloadFailed:   "❌ Could not download the CSV file from Telegram.",
+		ImportReadFailed:       "❌ Could not read the CSV: %v",
+		ImportNothing:          "Nothing to import.",
+		ButtonImportCreate:     "✅ Create %d",
+		ImportGone:             "The import is already finished or canceled",
+		ImportOwnerOnly:        "Only the user who uploaded the file can confirm the import",
+		ImportCancelled:        "❌ The import is canceled.",
+		ImportUnsupported:      "❌ Import is not available for this Todoist client.",
+		ImportProgress:         "⏳ Creating tasks: %d…",
+		ImportFailed:           "❌ Could not import the tasks. Please try again later.",
+		NudgeTaskLoadFailed:    "Could not load the task, please try again later",
+		NudgeAlreadyAssigned:   "The assignee is already set: %s",
+		NudgeNotMapped:         "Your Telegram username is not mapped to a Todoist user, upload the mapping with /set_assignee_map",
+		NudgeMappingGone:       "This member is no longer in the assignee mapping",
+		NudgeTodoistLoadFailed: "Could not load the task from Todoist",
+		NudgeAssignFailed:      "Could not set the assignee in Todoist",
+		NudgeAssigned:          "✅ Assignee of «%s»: %s (set by %s)",
+		NoticeDiscussion:       "🎙 A discussion started by %s is going on. Chat messages are collected into a task: /create_task prepares a draft, /cancel ends it without a task.",
+		NoticeStarterUnknown:   "a chat member",
+		NoticeClosedCreated:    "✅ The discussion is over: the task is created.",
+		NoticeClosedMerged:     "✅ The discussion is over: added to an existing task.",
+		NoticeClosedCanceled:   "🛑 The discussion is over without a task.",
+		PermissionNotMember:    "the bot is not a member of the chat — add it again",
+		PermissionPrivacy:      "the bot cannot see plain messages: privacy mode is on — make the bot an admin or turn the mode off in @BotFather (/setprivacy → Disable) and add the bot again",
+		PermissionMuted:        "the bot may not write to the chat, so it cannot send and update the draft — lift the bot's restrictions in the member settings",
+		PermissionBlocked: `⚠️ Cannot start a discussion:
+
+— %s
+
+Then run /start_discussion again.`,
+		PrivacyAdmins: "Chat admins",
+		PrivacyWarning: `⚠️ I cannot see plain messages in this chat: the bot's privacy mode is on, so discussions only get commands and replies to me.
+
+%s, make the bot a chat admin or turn privacy mode off in @BotFather (/setprivacy → Disable) and add the bot to the chat again.`,
+		PreviewClosedNote: `
+
+🔒 _The discussion is closed, the buttons no longer work._`,
+		PreviewExpiredNote: `
+
+⌛ _The draft is outdated, start the discussion again._`,
+		QuietHoursDeferred:    "🌅 While quiet hours were on:",
+		ParticipantsSummon:    "👀 The task draft is ready, please check it: %s",
+		ChannelBusy:           "📢 A task was posted in the channel, but a discussion is already going on here. End it to create a task from the post.",
+		CooldownWait:          "⏳ /%s can run at most once per %s Try again in %s",
+		WaitSeconds:           "%d s.",
+		WaitMinutes:           "%d min.",
+		WaitHours:             "%d h.",
+		WaitHoursMinutes:      "%d h. %d min.",
+		CommandFailed:         "❌ Could not run the command. Please try again later.",
+		QuickEditPriority:     "priority %s",
+		QuickEditNoDue:        "no due date",
+		QuickEditDue:          "due %s",
+		Usage:                 "Usage: %s",
+		MoreItems:             "…and %d more",
+		DueSuffix:             " (due: %s)",
+		ImportChooseProject:   "Choose a Todoist project with /set_project first, then import the tasks.",
+		ImportPrompt:          "Send a CSV file in reply to this message.\n\nThe format is that of Todoist templates: columns `TYPE,CONTENT,DESCRIPTION,PRIORITY,DATE,DATE_LANG`, only `CONTENT` is required. Priority 1 is the most urgent, labels can be given in the text with @.\n\nI will show what I found before creating the tasks.",
+		ImportFound:           "📥 Tasks found: %d",
+		ImportSkipped:         "Sections and notes skipped: %d",
+		ImportInvalidRows:     "Rows with errors will not be imported",
+		ImportDone:            "✅ Import finished: %d of %d tasks created.",
+		ImportCreateFailed:    "Could not create",
+		ImportProblemLine:     "• row %d: %s",
+		BulkFilterRejected:    "❌ Todoist rejected the filter. Check the query, e.g.: overdue, today, @label, p1.",
+		BulkNoTasks:           "The chat's project has no tasks matching «%s».",
+		ButtonBulkComplete:    "✅ Complete %d",
+		ButtonBulkReschedule:  "📅 Reschedule %d",
+		BulkPreviewReschedule: "📅 Reschedule to «%s» the tasks matching «%s» (%d):",
+		BulkPreviewComplete:   "✅ Complete the tasks matching «%s» (%d):",
+		CommandAuthorConfirms: "Only the author of the command can confirm.",
+		BulkRescheduled:       "📅 Tasks rescheduled: %d of %d.",
+		BulkCompleted:         "✅ Tasks completed: %d of %d.",
+		BulkFailures:          "⚠️ Failed (%d):",
+		DeleteTaskNotFound:    "❌ Task not found. Check the ID or the link.",
+		DeleteConfirm: `🗑 Delete the task «%s» for good?
+
+Only the author of the command can confirm.`,
+		ButtonDelete:         "🗑 Delete «%s»",
+		ButtonDeleteKeep:     "↩️ Cancel",
+		ListDue:              "Due",
+		ListProject:          "Project",
+		ListProjectsHeading:  "Your projects",
+		ListProjectTasksHint: "Tasks: Use",
+		ListFooter: `
+*Useful commands:*
+/create_task — create a task from the discussion
+/start_discussion — start a discussion
+/cancel — end the discussion without a task
+`,
+		ListFilterOverdue:      "overdue",
+		ListFilterToday:        "for today",
+		ListFilterUpcoming:     "for the next %d days",
+		ListFilterNoDue:        "without a due date",
+		ListTrackerUnavailable: "❌ The chat's task tracker is not available: %s",
+		ListTasksFailed:        "❌ *Could not get the tasks:* %v",
+		ListProjectsFailed:     "❌ *Could not get the projects:* %v",
+		ListNoTasksInProject:   "No tasks%[2]s found in the project \"%[1]s\".",
+		ListNoTasksInProjectID: "No tasks%[2]s found in the project with ID %[1]s.",
+		ListNoTasks:            "No tasks%s found.",
+		ListYourTasks:          "Your tasks",
+		ListProjectTasks:       "Tasks of the project %s",
+		ListPage:               ", page %d/%d",
+		ButtonListPrev:         "◀️ Back",
+		ButtonListNext:         "Next ▶️",
+		ListNoProjects:         "No projects found.",
+		SettingSaveFailed:      "Could not change the setting. Please try again later.",
+		AdminOnlyCommand:       "The command is only available to the bot admins.",
+		CancelNoSession:        "No active discussion%s.",
+		CancelOwnerOnly:        "Only the author of the discussion can end it.",
+		CancelConfirm:          "End the discussion%s without creating a task?",
+		ButtonFinishDiscussion: "🛑 End",
+		ButtonKeepDiscussion:   "↩️ Continue",
+		ButtonDecisionSummary:  "📝 Record the decision",
+		PromptUsage: `Usage: /set_prompt create <rules> or /set_prompt edit <rules>
+The rules are added to the standard prompt, e.g.: «Start the title with a verb, always add an Acceptance criteria section to the description».`,
+		PromptAdminOnly: "Only a bot admin can change the AI rules.",
+		PromptTooLong:   "The rules are too long: %d characters, %d at most.",
+		PromptSaved: `✅ The rules for %s are saved and will be added to the standard prompt.
+Reset: /reset_prompt %s`,
+		PromptResetUsage:     "Usage: /reset_prompt, /reset_prompt create or /reset_prompt edit",
+		PromptReset:          "The rules are reset, the AI uses the standard prompts.",
+		PromptHeader:         "AI rules of this chat:",
+		PromptDefault:        "not set, the standard prompt",
+		PromptEditTitle:      "editing a task",
+		PromptCreateTitle:    "creating a task",
+		DebugUnsupported:     "The current AI client does not support debugging the analysis.",
+		DebugStarting:        "🔬 Running a debug analysis of the discussion, the draft is not saved…",
+		DebugParsed:          "JSON parsed",
+		DebugParseError:      "parse error: %s",
+		DebugCaption:         "🔬 %s — tokens: %d (prompt %d, answer %d), %s. Personal data in the prompt is hidden.",
+		DigestUsage:          "Usage: /digest_time 09:30, /digest_time off, /digest_time suggest or /digest_time suggest apply",
+		DigestUnset:          "No time is set for digests and reminders, they arrive right away.",
+		DigestAt:             "Digests and reminders arrive at %s (%s).",
+		DigestReset:          "The time of digests and reminders is reset, they arrive right away.",
+		DigestBadTime:        "Could not read the time.",
+		DigestSet:            "Digests and reminders will arrive at %s (%s).",
+		DigestActivityFailed: "Could not load the chat's activity. Please try again later.",
+		DigestTooLittleData:  "Not enough data yet: at least %d messages in discussions over the last 4 weeks are needed.",
+		DigestActivity:       "Discussion activity over 4 weeks (%s), messages: %d",
+		DigestApplied:        "The chat is busiest from %s — digests and reminders will now arrive at that time.",
+		DigestSuggested:      "The chat is busiest from %s. To send digests and reminders at that time: /digest\\_time suggest apply",
+		JobsUsage:            "Usage: /jobs, /jobs cancel <id> or /jobs retry <id>",
+		JobsCanceled:         "Job #%d is canceled.",
+		JobsRetried:          "Job #%d is queued again.",
+		JobsNotFound:         "Job #%d is not found.",
+		JobsActionFailed:     "Could not act on job #%d: %v",
+		JobsEmpty:            "The AI job queue is empty.",
+		JobsHeader:           "AI job queue:",
+		JobsLine:             "#%d %s · %s · chat %d · %s · retries: %d",
+		JobsError:            "error: %s",
+		PlanAdminOnly:        "Only a bot admin can change the plan.",
+		PlanUnknown:          "Unknown plan %q.",
+		PlanSetFailed:        "Could not change the plan. Please try again later.",
+		PlanSet:              "Chat plan: %s.",
+		PlanStatusFailed:     "Could not load the plan. Please try again later.",
+		PlanStatus:           "Chat plan: %s",
+		PlanUsage:            "%s this month: %d of %d",
+		PlanUsageUnlimited:   "%s this month: %d (no limit)",
+		PlanExhausted:        "💳 %s on the «%s» plan are used up: %d of %d this month.",
+		PlanUpgradeURL:       "Upgrade to the pro plan: %s",
+		PlanUpgradeAdmin:     "To upgrade to the pro plan, contact the bot admin.",
+		PlanFeatureAIEdit:    "AI edits",
+		UsageFailed:          "❌ Could not load the token usage. Please try again later.",
+		UsageHeader:          "📊 AI token usage since %s",
+		UsageNone:            "There were no AI requests this month.",
+		UsageMoreChats:       "…and %d more chats",
+		UsageChat:            "Chat %d",
+		UsageNoChat:          "Outside chats",
+		UsageTotal:           "Total: %s",
+		UsageUnpriced:        "⚠️ No price in %s, not included in the cost: %s",
+		UsageLine:            "%d req., %d tokens (in %d, out %d) ≈ $%.2f",
+		QuickTodoistOnly:     "Quick add only works with Todoist. Create the task with /start_discussion.",
+		QuickUsage: `Usage: /quick <task text>
+Todoist parses the due date («tomorrow at 10»), priority (p1–p4), labels (@label) and project (#Project) itself; without a project the task goes to the Inbox.`,
+		QuickFailed:                "❌ Could not create the task. Please try again later.",
+		QuickCreated:               "✅ *Task created*: [%s](%s)",
+		QuickDue:                   "*Due:* %s",
+		QuickLabels:                "*Labels:* %s",
+		MapUserFormat:              "Format: /map_user @username email of a Todoist collaborator, e.g. /map_user @alice alice@example.com",
+		MapUserCollaboratorsFailed: "Could not load the Todoist project's collaborators. Please try again later.",
+		MapUserSaveFailed:          "Could not save the mapping",
+		MapUserSaved:               "✅ Tasks for %s will be assigned to %s (%s)",
+		MapUserNotFound:            "%s is not among the collaborators of the chosen Todoist project. Check the email and that the person was added to the project.",
+		MapUserLoadFailed:          "Could not load the assignee mapping",
+		MapUserEmpty:               "The assignee mapping is empty. Add a collaborator: /map_user @username email",
+		MapUserHeader:              "Project assignees:",
+		NotifyListFailed:           "Could not load the notifications. Please try again later.",
+		NotifyNone:                 "No notifications are set up.",
+		NotifyHeader:               "Chat notifications:",
+		NotifyRemoveHint:           "Remove: /notify remove <number>",
+		NotifyAddFailed:            "Could not add the notification: %v",
+		NotifyAdded:                "Notification %d added: %s → %s%s",
+		NotifySigned:               "Requests are signed: the %s header holds the HMAC-SHA256 of the body. Delete the message with the secret from the chat.",
+		NotifyNumberRequired:       "Give the notification number from /notify list.",
+		NotifyNotFound:             "Notification %d is not found.",
+		NotifyRemoved:              "Notification %d is removed.",
+		NotifyUsage: `Add: /notify add <kind> <target>, kinds: %s
+For example: /notify add webhook https://example.com/hook [secret] or /notify add telegram -1001234567890`,
+		NudgeOwner:                 "discussion author",
+		NudgeUntitled:              "untitled",
+		NudgeReminder:              "⏰ %s, the task «%s» still has no assignee. Who will take it?",
+		ButtonNudgeTake:            "🙋 I'll take it",
+		ParticipantsSummonOff:      "I no longer mention the participants when a draft is ready.",
+		ParticipantsSummonOn:       "When a draft is ready, I will mention all participants of the discussion so they check it.",
+		ParticipantsUsage:          "Usage: /participants or /participants summon on|off",
+		ParticipantsLoadFailed:     "Could not load the participants. Please try again later.",
+		ParticipantsNone:           "Nobody has written in the discussion yet.",
+		ParticipantsHeader:         "👥 *Discussion participants* (%d):",
+		ParticipantsLine:           "• %s — messages: %d",
+		ParticipantsSummonEnabled:  "When a draft is ready, I will mention everyone. Turn off: /participants summon off",
+		ParticipantsSummonDisabled: "Call everyone to a ready draft: /participants summon on",
+		ParticipantsUnnamed:        "participant",
+		PriorityNamesCurrent:       "Priorities in this chat:",
+		PriorityNamesHint: `Rename: /priority_names high=Major, urgent=Blocker
+Restore the defaults: /priority_names reset`,
+		PriorityNamesReset:    "Priority names are reset:",
+		PriorityNamesInvalid:  "Could not read the names. Levels: low, medium, high, urgent. Example: /priority_names high=Major, urgent=Blocker",
+		ProductivityFailed:    "❌ Could not load the statistics from Todoist. Please try again later.",
+		ProductivityHeader:    "📈 Completed tasks of the project",
+		ProductivityDays:      "Last %d days: %s",
+		ProductivityTotal:     "Total %d, %.1f a day on average",
+		ProductivityBestDay:   ", best day %s — %d",
+		ProductivityWeeks:     "By week: ",
+		ProductivityWeeksNote: " (the last 7 days on the right)",
+		ProductivityKarma:     "🏆 Todoist karma: %.0f%s",
+		QuietHoursUnset:       "Quiet hours are not set. Example: /quiet_hours 22:00-08:00",
+		QuietHoursStatus:      "Quiet hours: %s (%s). Notifications during them arrive as one summary in the morning. Turn off: /quiet_hours off",
+		QuietHoursOff:         "Quiet hours are off.",
+		QuietHoursInvalid:     "Could not read the time. Usage: /quiet_hours 22:00-08:00 or /quiet_hours off",
+		QuietHoursSet:         "Quiet hours: %s (%s). Notifications during them arrive as one summary when they end.",
+		QuotaAdminOnly:        "Only a bot admin can lift or restore the limits.",
+		QuotaSetFailed:        "Could not change the limits. Please try again later.",
+		QuotaRestored:         "The limits apply to this chat again.",
+		QuotaLifted:           "The limits are lifted for this chat.",
+		QuotaUsage:            "Usage: /quota, /quota off or /quota on",
+		QuotaDisabled:         "No limits are set up for discussion analyses.",
+		QuotaExempt:           "An admin lifted the limits for this chat.",
+		QuotaStatsFailed:      "Could not load the limit statistics. Please try again later.",
+		QuotaHeader:           "Discussion analyses over 24 hours:",
+		QuotaChat:             "Chat: %d of %d",
+		QuotaUser:             "You: %d of %d",
+		ReactionsStatusOff:    "Reactions to saved messages are off. Turn on: /reactions on",
+		ReactionsStatusOn:     "Reactions to saved messages are on. Turn off: /reactions off",
+		ReactionsOff:          "I no longer mark saved messages.",
+		ReactionsOn:           "I will mark each message saved to a discussion with a reaction.",
+		ReactionsUsage:        "Usage: /reactions, /reactions on or /reactions off",
+		SpeakNoDraft:          "There is no task draft to voice. Run /create_task first.",
+		SpeakOff:              "🔇 Drafts are no longer voiced.",
+		SpeakOn:               "🔊 Every task draft will now be repeated as a voice message.",
+		SpeakUsage:            "Usage: /speak, /speak on or /speak off",
+		SectionChoose:         "Choose a project section",
+		ButtonNoSection:       "No section",
+		SectionProjectFirst:   "First choose a project: /set_project",
+		SectionAskEachTime:    "The section will be asked on every task confirmation.",
+		TrackerUnavailable:    "The task tracker is unavailable. Please try again later.",
+		SectionNone:           "The chat's project has no sections, tasks are created outside sections.",
+		SectionSet:            "New tasks will go to the «%s» section.",
+		SectionNotFound:       "Section «%s» is not found. Project sections: %s",
+		SectionUnset:          "not chosen — the bot asks on task confirmation",
+		SectionStatus: `Section for new tasks: %s
+Project sections: %s
+
+Choose: /section <name>, ask every time: /section off`,
+		AssigneeMapProjectFirst:  "First choose a Todoist project with /set_project, then upload the YAML assignee mapping.",
+		AssigneeMapPrompt:        "Send the YAML mapping file in reply to this message.\n\nExample:\n```yaml\nversion: 1\nassignees:\n  - todoist_email: \"alice@example.com\"\n    telegram_aliases: [\"@alice\", \"alice\", \"Alice\"]\n```\n\nThe file replaces the current mapping of the chosen project.",
+		ProjectChoose:            "Choose a Todoist project:",
+		ProjectBadLink:           "Could not read the link. Copy the project link in Todoist or choose the project without arguments: /set_project",
+		ProjectsLoadFailed:       "Could not load the Todoist projects: %v",
+		ProjectSaveFailed:        "Could not save the project",
+		ProjectSelected:          "✅ Project selected: %s",
+		ProjectLinkNotFound:      "The linked project is not among the ones available to the bot. Choose a Todoist project:",
+		ProjectsNone:             "No projects were found in Todoist.",
+		DiscussionBadName:        "A discussion name is one word of letters, digits, «-» and «_», at most %d characters, e.g. /start_discussion billing-bug",
+		DiscussionProjectFailed:  "Could not get the chat's project: %v",
+		DiscussionAlreadyRunning: "A discussion is already running! End it before starting a new one or start a parallel one with a name: /start_discussion <name>.",
+		DiscussionNamedRunning:   "Discussion «%s» is already running! End it (/cancel %s) or choose another name.",
+		DiscussionStartFailed:    "Could not start the discussion: %v",
+		DiscussionStarted: `The discussion has started.
+Messages are saved until you create a task (/create_task) or end the discussion (/cancel).`,
+		DiscussionNamedStarted: `The discussion%[1]s has started.
+It gets replies to its messages and messages with #%[2]s. Other messages go to the unnamed discussion or, without one, to the latest started. Create the task: /create_task %[2]s, end: /cancel %[2]s.`,
+		StatusHeader:              "ℹ️ Bot status in the chat",
+		StatusTracker:             "🗂 Tracker: %s",
+		StatusDefaultZone:         " (default)",
+		StatusTimezone:            "🕒 Time zone: %s",
+		StatusSessionsFailed:      "💬 Could not check the discussions.",
+		StatusNoSessions:          "💬 No discussion. Start one: /start_discussion",
+		StatusOneSession:          "💬 A discussion is running",
+		StatusSessions:            "💬 Discussions are running (%d)",
+		StatusUnnamed:             "unnamed",
+		StatusSession:             "• %s — started by %s %s, messages: %s",
+		StatusNoProject:           "📁 No project is chosen: /set_project",
+		StatusProjectID:           "📁 Project: ID %s",
+		StatusProject:             "📁 Project: %s (ID %s)",
+		DefaultsUsage:             "Usage: /task_defaults due=+7d priority=medium labels=from-telegram or /task_defaults reset",
+		DefaultsAdminOnly:         "Only a bot admin can change the defaults.",
+		DefaultsInvalid:           "Could not read the settings: %v",
+		DefaultsDue:               "due date",
+		DefaultsPriority:          "priority",
+		DefaultsLabels:            "labels",
+		DefaultsApplied:           "⚙️ Chat defaults: %s",
+		DefaultsUnset:             "No task defaults are set.",
+		DefaultsHeader:            "Task defaults, when the discussion has none:",
+		DefaultsDueLine:           "• due date — in %d days",
+		DefaultsPriorityLine:      "• priority — %s",
+		DefaultsLabelsLine:        "• labels (always) — %s",
+		ViewTodayHeading:          "📅 Tasks for today",
+		ViewTodayEmpty:            "The chat's project has no tasks for today.",
+		ViewUpcomingHeading:       "🗓 Tasks for %d days",
+		ViewUpcomingEmpty:         "The chat's project has no tasks for the next %d days.",
+		ViewOverdueHeading:        "⏰ Overdue tasks",
+		ViewOverdueEmpty:          "The chat's project has no overdue tasks.",
+		ViewTodoistOnly:           "Task views only work with Todoist. Use /list.",
+		ViewProjectFirst:          "First choose a Todoist project with /set\\_project.",
+		ViewTasksFailed:           "❌ Could not load the tasks from Todoist. Please try again later.",
+		TimezoneUsage:             "Usage: /timezone Europe/Berlin — a time zone from the IANA database, /timezone reset — the default zone",
+		TimezoneDefault:           "Chat time zone: %s (default).",
+		TimezoneCurrent:           "Chat time zone: %s.",
+		TimezoneReset:             "The time zone is reset to the default: %s.",
+		TimezoneUnknown:           "Unknown time zone %q. Give it as in the IANA database, e.g. Europe/Moscow or Asia/Yekaterinburg.",
+		TimezoneSet:               "Chat time zone: %s. Due dates, quiet hours and digest times now use it.",
+		SettingLoadFailed:         "Could not load the setting. Please try again later.",
+		TranscriptChange:          "Change: /transcript off|description|comment",
+		TranscriptUsage:           "Usage: /transcript off|description|comment",
+		TranscriptModeDescription: "The discussion transcript is added to the end of the task description.",
+		TranscriptModeComment:     "The discussion transcript is added as comments to the Todoist task.",
+		TranscriptModeOff:         "The discussion transcript is not added to the task.",
+		BackupProjectFailed:       "Could not get the chat's project. Please try again later.",
+		BackupNoProject:           "No Todoist project is chosen for the chat. Choose one with /set_project.",
+		BackupStarting:            "📦 Backing up the project, this may take a minute…",
+		BackupCaption:             "📦 «%s» — tasks: %d, sections: %d. Exported %s UTC.",
+	},
+}
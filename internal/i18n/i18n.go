@@ -0,0 +1,578 @@
+// Package i18n holds the translated texts of the bot. Russian is the source
+// language: a text missing in another language falls back to it.
+package i18n
+
+import (
+	"strings"
+)
+
+// Lang is a supported reply language
+type Lang string
+
+const (
+	Russian Lang = "ru"
+	English Lang = "en"
+)
+
+// Default is the language of users who never chose one and whose Telegram
+// client reports no locale
+const Default = Russian
+
+// Languages lists the supported languages in the order they are offered
+var Languages = []Lang{Russian, English}
+
+// Parse returns the supported language named by code, e.g. "en"
+func Parse(code string) (Lang, bool) {
+	for _, lang := range Languages {
+		if strings.EqualFold(strings.TrimSpace(code), string(lang)) {
+			return lang, true
+		}
+	}
+	return "", false
+}
+
+// russianSpeaking are the locales whose users get Russian rather than English
+var russianSpeaking = map[string]bool{"ru": true, "uk": true, "be": true, "kk": true}
+
+// FromLanguageCode picks the reply language for an IETF tag reported by
+// Telegram, e.g. "en-US" or "ru": Russian for Russian-speaking locales and
+// English for every other locale
+func FromLanguageCode(code string) Lang {
+	base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(code)), "-")
+	if base == "" {
+		return Default
+	}
+	if russianSpeaking[base] {
+		return Russian
+	}
+	return English
+}
+
+// Key names a text of the catalog
+type Key string
+
+const (
+	StartWelcome       Key = "start.welcome"
+	StartChooseProject Key = "start.choose_project"
+	Help               Key = "help"
+	HelpFooter         Key = "help.footer"
+	LanguageCurrent    Key = "language.current"
+	LanguageAuto       Key = "language.auto"
+	LanguageSet        Key = "language.set"
+	LanguageUsage      Key = "language.usage"
+	LanguageSaveFailed Key = "language.save_failed"
+	LanguageName       Key = "language.name"
+	LanguageChat       Key = "language.chat"
+	LanguageChatSet    Key = "language.chat_set"
+	LanguageChatReset  Key = "language.chat_reset"
+
+	PreviewDraftReady        Key = "preview.draft_ready"
+	PreviewUpdated           Key = "preview.updated"
+	PreviewAlternativeChosen Key = "preview.alternative_chosen"
+	PreviewChooseAction      Key = "preview.choose_action"
+	PreviewMissingHint       Key = "preview.missing_hint"
+	PreviewReplyHint         Key = "preview.reply_hint"
+	PreviewUndone            Key = "preview.undone"
+	PreviewTitle             Key = "preview.title"
+	PreviewDescription       Key = "preview.description"
+	PreviewDue               Key = "preview.due"
+	PreviewPriority          Key = "preview.priority"
+	PreviewTaskType          Key = "preview.task_type"
+	PreviewAssignee          Key = "preview.assignee"
+	PreviewLabels            Key = "preview.labels"
+	TaskTypeTask             Key = "task_type.task"
+	TaskTypeBug              Key = "task_type.bug"
+	TaskTypeEpic             Key = "task_type.epic"
+	ButtonConfirm            Key = "button.confirm"
+	ButtonEdit               Key = "button.edit"
+	ButtonCancelCreation     Key = "button.cancel_creation"
+	ButtonUndoEdit           Key = "button.undo_edit"
+	ButtonCreateAnyway       Key = "button.create_anyway"
+	ButtonOpenExisting       Key = "button.open_existing"
+	ButtonMergeComment       Key = "button.merge_comment"
+	ButtonDueToday           Key = "button.due_today"
+	ButtonDueTomorrow        Key = "button.due_tomorrow"
+	ButtonDueNextWeek        Key = "button.due_next_week"
+	ButtonDueClear           Key = "button.due_clear"
+
+	CallbackCreating             Key = "callback.creating"
+	CallbackTaskCreated          Key = "callback.task_created"
+	CallbackAlreadyCreated       Key = "callback.already_created"
+	CallbackAlreadyCreatedMsg    Key = "callback.already_created_message"
+	CallbackEditPrompt           Key = "callback.edit_prompt"
+	CallbackEditAnswer           Key = "callback.edit_answer"
+	CallbackCancelled            Key = "callback.cancelled"
+	CallbackCancelledMsg         Key = "callback.cancelled_message"
+	CallbackFinished             Key = "callback.finished"
+	CallbackFinishedMsg          Key = "callback.finished_message"
+	CallbackKept                 Key = "callback.kept"
+	CallbackKeptMsg              Key = "callback.kept_message"
+	CallbackProjectChosen        Key = "callback.project_chosen"
+	CallbackProjectChosenMsg     Key = "callback.project_chosen_message"
+	AIUnavailable                Key = "ai.unavailable"
+	AIInvalidOutput              Key = "ai.invalid_output"
+	AIAnalysisFailed             Key = "ai.analysis_failed"
+	ErrorProjectLoad             Key = "error.project_load"
+	ErrorSessionLoad             Key = "error.session_load"
+	ErrorMessagesLoad            Key = "error.messages_load"
+	ErrorDraftSave               Key = "error.draft_save"
+	SessionNoActive              Key = "session.no_active"
+	SessionNotFound              Key = "session.not_found"
+	CreateTaskOwnerOnly          Key = "create_task.owner_only"
+	CreateTaskNoMessages         Key = "create_task.no_messages"
+	GuardTooFewMessages          Key = "guard.too_few_messages"
+	GuardOwnerOnly               Key = "guard.owner_only"
+	GuardTooLittleText           Key = "guard.too_little_text"
+	QuotaExceededChat            Key = "quota.exceeded_chat"
+	QuotaExceededUser            Key = "quota.exceeded_user"
+	PreviewMissingDetails        Key = "preview.missing_details"
+	PreviewLinks                 Key = "preview.links"
+	TextAnd                      Key = "text.and"
+	DateWeekdays                 Key = "date.weekdays"
+	DateMonths                   Key = "date.months"
+	DateFormat                   Key = "date.format"
+	AlternativesReady            Key = "alternatives.ready"
+	AlternativesChoose           Key = "alternatives.choose"
+	AlternativesOption           Key = "alternatives.option"
+	AlternativesDue              Key = "alternatives.due"
+	AlternativesPriority         Key = "alternatives.priority"
+	ButtonAlternative            Key = "button.alternative"
+	RevisionAlternative          Key = "revision.alternative"
+	CallbackStale                Key = "callback.stale"
+	CallbackOwnerCheckFailed     Key = "callback.owner_check_failed"
+	CallbackOwnerOnlyCreate      Key = "callback.owner_only_create"
+	CallbackOwnerOnlyEdit        Key = "callback.owner_only_edit"
+	CallbackOwnerOnlyCancel      Key = "callback.owner_only_cancel"
+	CallbackOwnerOnlyFinish      Key = "callback.owner_only_finish"
+	CallbackOwnerOnlyKeep        Key = "callback.owner_only_keep"
+	CallbackOwnerOnlyAction      Key = "callback.owner_only_action"
+	CallbackOwnerOnlyDecision    Key = "callback.owner_only_decision"
+	CallbackOwnerOnlySplit       Key = "callback.owner_only_split"
+	CallbackOwnerOnlyAlternative Key = "callback.owner_only_alternative"
+	CallbackOwnerOnlyUndo        Key = "callback.owner_only_undo"
+	CallbackDraftLoadFailed      Key = "callback.draft_load_failed"
+	CallbackProjectLoadFailed    Key = "callback.project_load_failed"
+	CallbackTrackerUnavailable   Key = "callback.tracker_unavailable"
+	CallbackCreateFailed         Key = "callback.create_failed"
+	CallbackFinishFailed         Key = "callback.finish_failed"
+	CallbackProjectSaveFailed    Key = "callback.project_save_failed"
+	CallbackProjectsLoadFailed   Key = "callback.projects_load_failed"
+	CallbackNoComments           Key = "callback.no_comments"
+	CallbackCommentFailed        Key = "callback.comment_failed"
+	CallbackMerged               Key = "callback.merged"
+	CallbackMergedMsg            Key = "callback.merged_message"
+	CallbackMergedTaskMsg        Key = "callback.merged_task_message"
+	DuplicateWarning             Key = "duplicate.warning"
+	CallbackOwnerOnlyQuickEdit   Key = "callback.owner_only_quick_edit"
+	CallbackTaskCreatedNotice    Key = "callback.task_created_notice"
+	CallbackCancelledNotice      Key = "callback.cancelled_notice"
+	CallbackKeptNotice           Key = "callback.kept_notice"
+	SessionNone                  Key = "session.none"
+	HistoryLoadFailed            Key = "history.load_failed"
+	HistoryNoDraft               Key = "history.no_draft"
+	HistoryHeader                Key = "history.header"
+	HistoryFromDiscussion        Key = "history.from_discussion"
+	HistoryNoChanges             Key = "history.no_changes"
+	HistoryUndoHint              Key = "history.undo_hint"
+	DiffNone                     Key = "diff.none"
+	DiffChanged                  Key = "diff.changed"
+	DiffDue                      Key = "diff.due"
+	DiffLinks                    Key = "diff.links"
+	DiffLinkCount                Key = "diff.link_count"
+	ProjectChooseFirstCommand    Key = "project.choose_first_command"
+	SplitTodoistOnly             Key = "split.todoist_only"
+	SplitOwnerOnly               Key = "split.owner_only"
+	SplitNoMessages              Key = "split.no_messages"
+	SplitFailed                  Key = "split.failed"
+	SplitProposal                Key = "split.proposal"
+	SplitChoose                  Key = "split.choose"
+	ButtonSplitCreate            Key = "button.split_create"
+	ButtonCancel                 Key = "button.cancel"
+	SplitCreateFailed            Key = "split.create_failed"
+	SplitCreated                 Key = "split.created"
+	SplitPartlyFailed            Key = "split.partly_failed"
+	SplitGone                    Key = "split.gone"
+	SplitCancelled               Key = "split.cancelled"
+	SplitNothingChecked          Key = "split.nothing_checked"
+	SplitUnsupported             Key = "split.unsupported"
+	SplitCreateRetry             Key = "split.create_retry"
+	SummaryNoMessages            Key = "summary.no_messages"
+	SummaryFailed                Key = "summary.failed"
+	SummaryEmpty                 Key = "summary.empty"
+	SummaryText                  Key = "summary.text"
+	ButtonMainSetProject         Key = "button.main_set_project"
+	ButtonMainStartDiscussion    Key = "button.main_start_discussion"
+	ButtonMainCreateTask         Key = "button.main_create_task"
+	ButtonMainCancel             Key = "button.main_cancel"
+	ButtonMainList               Key = "button.main_list"
+	ButtonMainHelp               Key = "button.main_help"
+	CommandUnknown               Key = "command.unknown"
+	CommandUnavailable           Key = "command.unavailable"
+	EditDraftLoadFailed          Key = "edit.draft_load_failed"
+	EditFailed                   Key = "edit.failed"
+	EditSaveFailed               Key = "edit.save_failed"
+	MappingSendDocument          Key = "mapping.send_document"
+	MappingInternalError         Key = "mapping.internal_error"
+	MappingFileFailed            Key = "mapping.file_failed"
+	MappingDownloadFailed        Key = "mapping.download_failed"
+	MappingDownloadError         Key = "mapping.download_error"
+	MappingReadFailed            Key = "mapping.read_failed"
+	MappingCollaboratorsFailed   Key = "mapping.collaborators_failed"
+	MappingImportFailed          Key = "mapping.import_failed"
+	MappingUpdated               Key = "mapping.updated"
+	MappingSaveFailed            Key = "mapping.save_failed"
+	MappingSaveDuplicates        Key = "mapping.save_duplicates"
+	MappingSaveError             Key = "mapping.save_error"
+	DecisionPreparing            Key = "decision.preparing"
+	DecisionNoMessages           Key = "decision.no_messages"
+	DecisionFailed               Key = "decision.failed"
+	DecisionText                 Key = "decision.text"
+	JobQueued                    Key = "job.queued"
+	JobQueuedPosition            Key = "job.queued_position"
+	JobQueueFailed               Key = "job.queue_failed"
+	JobAnalyzing                 Key = "job.analyzing"
+	JobEditQueued                Key = "job.edit_queued"
+	JobEditQueueFailed           Key = "job.edit_queue_failed"
+	JobEditApplying              Key = "job.edit_applying"
+	DocumentFailed               Key = "document.failed"
+	BulkGone                     Key = "bulk.gone"
+	BulkOwnerOnly                Key = "bulk.owner_only"
+	BulkCancelled                Key = "bulk.cancelled"
+	BulkUnsupported              Key = "bulk.unsupported"
+	BulkProgress                 Key = "bulk.progress"
+	BulkFailed                   Key = "bulk.failed"
+	DeleteOwnerOnly              Key = "delete.owner_only"
+	DeleteGone                   Key = "delete.gone"
+	DeleteKept                   Key = "delete.kept"
+	DeleteFailed                 Key = "delete.failed"
+	DeleteDone                   Key = "delete.done"
+	DeleteConfirmPrompt          Key = "delete.confirm_prompt"
+	TaskNotFound                 Key = "task.not_found"
+	TaskCompleteFailed           Key = "task.complete_failed"
+	TaskCompleted                Key = "task.completed"
+	TaskCompletedInTodoist       Key = "task.completed_in_todoist"
+	TaskCompletedBy              Key = "task.completed_event"
+	AlternativeFailed            Key = "alternative.failed"
+	UndoNothing                  Key = "undo.nothing"
+	UndoFailed                   Key = "undo.failed"
+	UndoDone                     Key = "undo.done"
+	QuickEditNoDraft             Key = "quick_edit.no_draft"
+	QuickEditUnchanged           Key = "quick_edit.unchanged"
+	QuickEditSaveFailed          Key = "quick_edit.save_failed"
+	QuickEditApplied             Key = "quick_edit.applied"
+	ImportSendDocument           Key = "import.send_document"
+	ImportTooLarge               Key = "import.too_large"
+	ImportInternalError          Key = "import.internal_error"
+	ImportDownloadFailed         Key = "import.download_failed"
+	ImportReadFailed             Key = "import.read_failed"
+	ImportNothing                Key = "import.nothing"
+	ButtonImportCreate           Key = "button.import_create"
+	ImportGone                   Key = "import.gone"
+	ImportOwnerOnly              Key = "import.owner_only"
+	ImportCancelled              Key = "import.cancelled"
+	ImportUnsupported            Key = "import.unsupported"
+	ImportProgress               Key = "import.progress"
+	ImportFailed                 Key = "import.failed"
+	NudgeTaskLoadFailed          Key = "nudge.task_load_failed"
+	NudgeAlreadyAssigned         Key = "nudge.already_assigned"
+	NudgeNotMapped               Key = "nudge.not_mapped"
+	NudgeMappingGone             Key = "nudge.mapping_gone"
+	NudgeTodoistLoadFailed       Key = "nudge.todoist_load_failed"
+	NudgeAssignFailed            Key = "nudge.assign_failed"
+	NudgeAssigned                Key = "nudge.assigned"
+	NoticeDiscussion             Key = "notice.discussion"
+	NoticeStarterUnknown         Key = "notice.starter_unknown"
+	NoticeClosedCreated          Key = "notice.closed_created"
+	NoticeClosedMerged           Key = "notice.closed_merged"
+	NoticeClosedCanceled         Key = "notice.closed_canceled"
+	PermissionNotMember          Key = "permission.not_member"
+	PermissionPrivacy            Key = "permission.privacy"
+	PermissionMuted              Key = "permission.muted"
+	PermissionBlocked            Key = "permission.blocked"
+	PrivacyAdmins                Key = "privacy.admins"
+	PrivacyWarning               Key = "privacy.warning"
+	PreviewClosedNote            Key = "preview.closed_note"
+	PreviewExpiredNote           Key = "preview.expired_note"
+	QuietHoursDeferred           Key = "quiet_hours.deferred"
+	ParticipantsSummon           Key = "participants.summon"
+	ChannelBusy                  Key = "channel.busy"
+	CooldownWait                 Key = "cooldown.wait"
+	WaitSeconds                  Key = "wait.seconds"
+	WaitMinutes                  Key = "wait.minutes"
+	WaitHours                    Key = "wait.hours"
+	WaitHoursMinutes             Key = "wait.hours_minutes"
+	CommandFailed                Key = "command.failed"
+	QuickEditPriority            Key = "quick_edit.priority"
+	QuickEditNoDue               Key = "quick_edit.no_due"
+	QuickEditDue                 Key = "quick_edit.due"
+	Usage                        Key = "command.usage"
+	MoreItems                    Key = "list.more_items"
+	DueSuffix                    Key = "list.due_suffix"
+	ImportChooseProject          Key = "import.choose_project"
+	ImportPrompt                 Key = "import.prompt"
+	ImportFound                  Key = "import.found"
+	ImportSkipped                Key = "import.skipped"
+	ImportInvalidRows            Key = "import.invalid_rows"
+	ImportDone                   Key = "import.done"
+	ImportCreateFailed           Key = "import.create_failed"
+	ImportProblemLine            Key = "import.problem_line"
+	BulkFilterRejected           Key = "bulk.filter_rejected"
+	BulkNoTasks                  Key = "bulk.no_tasks"
+	ButtonBulkComplete           Key = "button.bulk_complete"
+	ButtonBulkReschedule         Key = "button.bulk_reschedule"
+	BulkPreviewReschedule        Key = "bulk.preview_reschedule"
+	BulkPreviewComplete          Key = "bulk.preview_complete"
+	CommandAuthorConfirms        Key = "command.author_confirms"
+	BulkRescheduled              Key = "bulk.rescheduled"
+	BulkCompleted                Key = "bulk.completed"
+	BulkFailures                 Key = "bulk.failures"
+	DeleteTaskNotFound           Key = "delete.task_not_found"
+	DeleteConfirm                Key = "delete.confirm"
+	ButtonDelete                 Key = "button.delete"
+	ButtonDeleteKeep             Key = "button.delete_keep"
+	ListDue                      Key = "list.due"
+	ListProject                  Key = "list.project"
+	ListProjectsHeading          Key = "list.projects_heading"
+	ListProjectTasksHint         Key = "list.project_tasks_hint"
+	ListFooter                   Key = "list.footer"
+	ListFilterOverdue            Key = "list.filter_overdue"
+	ListFilterToday              Key = "list.filter_today"
+	ListFilterUpcoming           Key = "list.filter_upcoming"
+	ListFilterNoDue              Key = "list.filter_no_due"
+	ListTrackerUnavailable       Key = "list.tracker_unavailable"
+	ListTasksFailed              Key = "list.tasks_failed"
+	ListProjectsFailed           Key = "list.projects_failed"
+	ListNoTasksInProject         Key = "list.no_tasks_in_project"
+	ListNoTasksInProjectID       Key = "list.no_tasks_in_project_id"
+	ListNoTasks                  Key = "list.no_tasks"
+	ListYourTasks                Key = "list.your_tasks"
+	ListProjectTasks             Key = "list.project_tasks"
+	ListPage                     Key = "list.page"
+	ButtonListPrev               Key = "button.list_prev"
+	ButtonListNext               Key = "button.list_next"
+	ListNoProjects               Key = "list.no_projects"
+	SettingSaveFailed            Key = "setting.save_failed"
+	AdminOnlyCommand             Key = "command.admin_only"
+	CancelNoSession              Key = "cancel.no_session"
+	CancelOwnerOnly              Key = "cancel.owner_only"
+	CancelConfirm                Key = "cancel.confirm"
+	ButtonFinishDiscussion       Key = "button.finish_discussion"
+	ButtonKeepDiscussion         Key = "button.keep_discussion"
+	ButtonDecisionSummary        Key = "button.decision_summary"
+	PromptUsage                  Key = "prompt.usage"
+	PromptAdminOnly              Key = "prompt.admin_only"
+	PromptTooLong                Key = "prompt.too_long"
+	PromptSaved                  Key = "prompt.saved"
+	PromptResetUsage             Key = "prompt.reset_usage"
+	PromptReset                  Key = "prompt.reset"
+	PromptHeader                 Key = "prompt.header"
+	PromptDefault                Key = "prompt.default"
+	PromptEditTitle              Key = "prompt.edit_title"
+	PromptCreateTitle            Key = "prompt.create_title"
+	DebugUnsupported             Key = "debug.unsupported"
+	DebugStarting                Key = "debug.starting"
+	DebugParsed                  Key = "debug.parsed"
+	DebugParseError              Key = "debug.parse_error"
+	DebugCaption                 Key = "debug.caption"
+	DigestUsage                  Key = "digest.usage"
+	DigestUnset                  Key = "digest.unset"
+	DigestAt                     Key = "digest.at"
+	DigestReset                  Key = "digest.reset"
+	DigestBadTime                Key = "digest.bad_time"
+	DigestSet                    Key = "digest.set"
+	DigestActivityFailed         Key = "digest.activity_failed"
+	DigestTooLittleData          Key = "digest.too_little_data"
+	DigestActivity               Key = "digest.activity"
+	DigestApplied                Key = "digest.applied"
+	DigestSuggested              Key = "digest.suggested"
+	JobsUsage                    Key = "jobs.usage"
+	JobsCanceled                 Key = "jobs.canceled"
+	JobsRetried                  Key = "jobs.retried"
+	JobsNotFound                 Key = "jobs.not_found"
+	JobsActionFailed             Key = "jobs.action_failed"
+	JobsEmpty                    Key = "jobs.empty"
+	JobsHeader                   Key = "jobs.header"
+	JobsLine                     Key = "jobs.line"
+	JobsError                    Key = "jobs.error"
+	PlanAdminOnly                Key = "plan.admin_only"
+	PlanUnknown                  Key = "plan.unknown"
+	PlanSetFailed                Key = "plan.set_failed"
+	PlanSet                      Key = "plan.set"
+	PlanStatusFailed             Key = "plan.status_failed"
+	PlanStatus                   Key = "plan.status"
+	PlanUsage                    Key = "plan.usage"
+	PlanUsageUnlimited           Key = "plan.usage_unlimited"
+	PlanExhausted                Key = "plan.exhausted"
+	PlanUpgradeURL               Key = "plan.upgrade_url"
+	PlanUpgradeAdmin             Key = "plan.upgrade_admin"
+	PlanFeatureAIEdit            Key = "plan.feature_ai_edit"
+	UsageFailed                  Key = "usage.failed"
+	UsageHeader                  Key = "usage.header"
+	UsageNone                    Key = "usage.none"
+	UsageMoreChats               Key = "usage.more_chats"
+	UsageChat                    Key = "usage.chat"
+	UsageNoChat                  Key = "usage.no_chat"
+	UsageTotal                   Key = "usage.total"
+	UsageUnpriced                Key = "usage.unpriced"
+	UsageLine                    Key = "usage.line"
+	QuickTodoistOnly             Key = "quick.todoist_only"
+	QuickUsage                   Key = "quick.usage"
+	QuickFailed                  Key = "quick.failed"
+	QuickCreated                 Key = "quick.created"
+	QuickDue                     Key = "quick.due"
+	QuickLabels                  Key = "quick.labels"
+	MapUserFormat                Key = "map_user.format"
+	MapUserCollaboratorsFailed   Key = "map_user.collaborators_failed"
+	MapUserSaveFailed            Key = "map_user.save_failed"
+	MapUserSaved                 Key = "map_user.saved"
+	MapUserNotFound              Key = "map_user.not_found"
+	MapUserLoadFailed            Key = "map_user.load_failed"
+	MapUserEmpty                 Key = "map_user.empty"
+	MapUserHeader                Key = "map_user.header"
+	NotifyListFailed             Key = "notify.list_failed"
+	NotifyNone                   Key = "notify.none"
+	NotifyHeader                 Key = "notify.header"
+	NotifyRemoveHint             Key = "notify.remove_hint"
+	NotifyAddFailed              Key = "notify.add_failed"
+	NotifyAdded                  Key = "notify.added"
+	NotifySigned                 Key = "notify.signed"
+	NotifyNumberRequired         Key = "notify.number_required"
+	NotifyNotFound               Key = "notify.not_found"
+	NotifyRemoved                Key = "notify.removed"
+	NotifyUsage                  Key = "notify.usage"
+	NudgeOwner                   Key = "nudge.owner"
+	NudgeUntitled                Key = "nudge.untitled"
+	NudgeReminder                Key = "nudge.reminder"
+	ButtonNudgeTake              Key = "button.nudge_take"
+	ParticipantsSummonOff        Key = "participants.summon_off"
+	ParticipantsSummonOn         Key = "participants.summon_on"
+	ParticipantsUsage            Key = "participants.usage"
+	ParticipantsLoadFailed       Key = "participants.load_failed"
+	ParticipantsNone             Key = "participants.none"
+	ParticipantsHeader           Key = "participants.header"
+	ParticipantsLine             Key = "participants.line"
+	ParticipantsSummonEnabled    Key = "participants.summon_enabled"
+	ParticipantsSummonDisabled   Key = "participants.summon_disabled"
+	ParticipantsUnnamed          Key = "participants.unnamed"
+	PriorityNamesCurrent         Key = "priority_names.current"
+	PriorityNamesHint            Key = "priority_names.hint"
+	PriorityNamesReset           Key = "priority_names.reset"
+	PriorityNamesInvalid         Key = "priority_names.invalid"
+	ProductivityFailed           Key = "productivity.failed"
+	ProductivityHeader           Key = "productivity.header"
+	ProductivityDays             Key = "productivity.days"
+	ProductivityTotal            Key = "productivity.total"
+	ProductivityBestDay          Key = "productivity.best_day"
+	ProductivityWeeks            Key = "productivity.weeks"
+	ProductivityWeeksNote        Key = "productivity.weeks_note"
+	ProductivityKarma            Key = "productivity.karma"
+	QuietHoursUnset              Key = "quiet_hours.unset"
+	QuietHoursStatus             Key = "quiet_hours.status"
+	QuietHoursOff                Key = "quiet_hours.off"
+	QuietHoursInvalid            Key = "quiet_hours.invalid"
+	QuietHoursSet                Key = "quiet_hours.set"
+	QuotaAdminOnly               Key = "quota.admin_only"
+	QuotaSetFailed               Key = "quota.set_failed"
+	QuotaRestored                Key = "quota.restored"
+	QuotaLifted                  Key = "quota.lifted"
+	QuotaUsage                   Key = "quota.usage"
+	QuotaDisabled                Key = "quota.disabled"
+	QuotaExempt                  Key = "quota.exempt"
+	QuotaStatsFailed             Key = "quota.stats_failed"
+	QuotaHeader                  Key = "quota.header"
+	QuotaChat                    Key = "quota.chat"
+	QuotaUser                    Key = "quota.user"
+	ReactionsStatusOff           Key = "reactions.status_off"
+	ReactionsStatusOn            Key = "reactions.status_on"
+	ReactionsOff                 Key = "reactions.off"
+	ReactionsOn                  Key = "reactions.on"
+	ReactionsUsage               Key = "reactions.usage"
+	SpeakNoDraft                 Key = "speak.no_draft"
+	SpeakOff                     Key = "speak.off"
+	SpeakOn                      Key = "speak.on"
+	SpeakUsage                   Key = "speak.usage"
+	SectionChoose                Key = "section.choose"
+	ButtonNoSection              Key = "button.no_section"
+	SectionProjectFirst          Key = "section.project_first"
+	SectionAskEachTime           Key = "section.ask_each_time"
+	TrackerUnavailable           Key = "tracker.unavailable"
+	SectionNone                  Key = "section.none"
+	SectionSet                   Key = "section.set"
+	SectionNotFound              Key = "section.not_found"
+	SectionUnset                 Key = "section.unset"
+	SectionStatus                Key = "section.status"
+	AssigneeMapProjectFirst      Key = "assignee_map.project_first"
+	AssigneeMapPrompt            Key = "assignee_map.prompt"
+	ProjectChoose                Key = "project.choose"
+	ProjectBadLink               Key = "project.bad_link"
+	ProjectsLoadFailed           Key = "project.load_failed"
+	ProjectSaveFailed            Key = "project.save_failed"
+	ProjectSelected              Key = "project.selected"
+	ProjectLinkNotFound          Key = "project.link_not_found"
+	ProjectsNone                 Key = "project.none"
+	DiscussionBadName            Key = "discussion.bad_name"
+	DiscussionProjectFailed      Key = "discussion.project_failed"
+	DiscussionAlreadyRunning     Key = "discussion.already_running"
+	DiscussionNamedRunning       Key = "discussion.named_running"
+	DiscussionStartFailed        Key = "discussion.start_failed"
+	DiscussionStarted            Key = "discussion.started"
+	DiscussionNamedStarted       Key = "discussion.named_started"
+	StatusHeader                 Key = "status.header"
+	StatusTracker                Key = "status.tracker"
+	StatusDefaultZone            Key = "status.default_zone"
+	StatusTimezone               Key = "status.timezone"
+	StatusSessionsFailed         Key = "status.sessions_failed"
+	StatusNoSessions             Key = "status.no_sessions"
+	StatusOneSession             Key = "status.one_session"
+	StatusSessions               Key = "status.sessions"
+	StatusUnnamed                Key = "status.unnamed"
+	StatusSession                Key = "status.session"
+	StatusNoProject              Key = "status.no_project"
+	StatusProjectID              Key = "status.project_id"
+	StatusProject                Key = "status.project"
+	DefaultsUsage                Key = "defaults.usage"
+	DefaultsAdminOnly            Key = "defaults.admin_only"
+	DefaultsInvalid              Key = "defaults.invalid"
+	DefaultsDue                  Key = "defaults.due"
+	DefaultsPriority             Key = "defaults.priority"
+	DefaultsLabels               Key = "defaults.labels"
+	DefaultsApplied              Key = "defaults.applied"
+	DefaultsUnset                Key = "defaults.unset"
+	DefaultsHeader               Key = "defaults.header"
+	DefaultsDueLine              Key = "defaults.due_line"
+	DefaultsPriorityLine         Key = "defaults.priority_line"
+	DefaultsLabelsLine           Key = "defaults.labels_line"
+	ViewTodayHeading             Key = "view.today_heading"
+	ViewTodayEmpty               Key = "view.today_empty"
+	ViewUpcomingHeading          Key = "view.upcoming_heading"
+	ViewUpcomingEmpty            Key = "view.upcoming_empty"
+	ViewOverdueHeading           Key = "view.overdue_heading"
+	ViewOverdueEmpty             Key = "view.overdue_empty"
+	ViewTodoistOnly              Key = "view.todoist_only"
+	ViewProjectFirst             Key = "view.project_first"
+	ViewTasksFailed              Key = "view.tasks_failed"
+	TimezoneUsage                Key = "timezone.usage"
+	TimezoneDefault              Key = "timezone.default"
+	TimezoneCurrent              Key = "timezone.current"
+	TimezoneReset                Key = "timezone.reset"
+	TimezoneUnknown              Key = "timezone.unknown"
+	TimezoneSet                  Key = "timezone.set"
+	SettingLoadFailed            Key = "setting.load_failed"
+	TranscriptChange             Key = "transcript.change"
+	TranscriptUsage              Key = "transcript.usage"
+	TranscriptModeDescription    Key = "transcript.mode_description"
+	TranscriptModeComment        Key = "transcript.mode_comment"
+	TranscriptModeOff            Key = "transcript.mode_off"
+	BackupProjectFailed          Key = "backup.project_failed"
+	BackupNoProject              Key = "backup.no_project"
+	BackupStarting               Key = "backup.starting"
+	BackupCaption                Key = "backup.caption"
+)
+
+// T returns the text of key in lang, falling back to Russian
+func T(lang Lang, key Key) string {
+	if text, ok := catalog[lang][key]; ok {
+		return text
+	}
+	return catalog[Default][key]
+}
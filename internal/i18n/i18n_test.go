@@ -0,0 +1,39 @@
+package i18n
+
+import "testing"
+
+func TestFromLanguageCode(t *testing.T) {
+	tests := map[string]Lang{
+		"":      Russian,
+		"ru":    Russian,
+		"uk":    Russian,
+		"en":    English,
+		"en-US": English,
+		"de":    English,
+		"RU-ru": Russian,
+	}
+	for code, want := range tests {
+		if got := FromLanguageCode(code); got != want {
+			t.Errorf("FromLanguageCode(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for key := range catalog[Default] {
+		for _, lang := range Languages {
+			if _, ok := catalog[lang][key]; !ok {
+				t.Errorf("%s has no %q text", lang, key)
+			}
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	if lang, ok := Parse(" EN "); !ok || lang != English {
+		t.Errorf("Parse(EN) = %q, %v", lang, ok)
+	}
+	if _, ok := Parse("de"); ok {
+		t.Error("expected unsupported language to be rejected")
+	}
+}
@@ -0,0 +1,65 @@
+package jira
+
+import "strings"
+
+// adfNode is a node of the Atlassian Document Format, which API v3 uses for
+// rich text fields such as the description
+type adfNode struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version,omitempty"`
+	Text    string    `json:"text,omitempty"`
+	Content []adfNode `json:"content,omitempty"`
+}
+
+// textToADF turns plain text into a document: blank lines separate
+// paragraphs, single line breaks stay hard breaks
+func textToADF(text string) adfNode {
+	doc := adfNode{Type: "doc", Version: 1}
+	for _, block := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		block = strings.Trim(block, "\n")
+		if block == "" {
+			continue
+		}
+		paragraph := adfNode{Type: "paragraph"}
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, adfNode{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+	return doc
+}
+
+// adfToText flattens a document back to plain text, dropping formatting
+func adfToText(node *adfNode) string {
+	if node == nil {
+		return ""
+	}
+	var b strings.Builder
+	writeADFText(&b, *node)
+	return strings.TrimSpace(b.String())
+}
+
+func writeADFText(b *strings.Builder, node adfNode) {
+	switch node.Type {
+	case "text":
+		b.WriteString(node.Text)
+		return
+	case "hardBreak":
+		b.WriteByte('\n')
+		return
+	}
+	for _, child := range node.Content {
+		writeADFText(b, child)
+	}
+	switch node.Type {
+	case "paragraph", "heading", "codeBlock", "blockquote":
+		b.WriteString("\n\n")
+	case "listItem":
+		b.WriteByte('\n')
+	}
+}
@@ -0,0 +1,401 @@
+// Package jira implements the task tracker on the Jira Cloud REST API v3:
+// tasks are issues, projects are Jira projects addressed by their keys.
+package jira
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/dates"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+const (
+	defaultIssueType = "Task"
+	// searchPageSize is the largest page /search/jql returns
+	searchPageSize = 100
+	// maxSearchResults keeps a huge backlog from turning a listing into dozens of requests
+	maxSearchResults = 500
+	// statusCategoryDone is the key of the status category of finished issues
+	statusCategoryDone = "done"
+)
+
+// issueFields are the fields the bot reads from issues
+var issueFields = []string{"summary", "description", "project", "priority", "duedate", "assignee", "labels", "status"}
+
+// Client is a Jira Cloud client
+type Client struct {
+	httpClient *httpclient.Client
+	siteURL    string
+	projectKey string
+	issueType  string
+}
+
+var _ tracker.Client = (*Client)(nil)
+
+// NewClient creates a client from the jira client of configs/api.yaml
+func NewClient(config *httpclient.JiraTrackerConfig) (*Client, error) {
+	configs, err := httpclient.LoadConfig("configs/api.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API configuration: %w", err)
+	}
+	clientConfig, err := configs.GetClientConfig("jira")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Jira client configuration: %w", err)
+	}
+	httpClient, err := clientConfig.CreateClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	return newClient(httpClient, clientConfig.BaseURL, config), nil
+}
+
+func newClient(httpClient *httpclient.Client, baseURL string, config *httpclient.JiraTrackerConfig) *Client {
+	c := &Client{
+		httpClient: httpClient,
+		siteURL:    siteURL(baseURL),
+		issueType:  defaultIssueType,
+	}
+	if config != nil {
+		c.projectKey = config.ProjectKey
+		if config.IssueType != "" {
+			c.issueType = config.IssueType
+		}
+	}
+	return c
+}
+
+// siteURL strips the API path from the base URL, e.g.
+// https://acme.atlassian.net/rest/api/3 becomes https://acme.atlassian.net
+func siteURL(baseURL string) string {
+	if i := strings.Index(baseURL, "/rest/"); i != -1 {
+		return baseURL[:i]
+	}
+	return strings.TrimSuffix(baseURL, "/")
+}
+
+// IssueURL returns the browser link of an issue
+func (c *Client) IssueURL(key string) string {
+	return c.siteURL + "/browse/" + key
+}
+
+type issue struct {
+	ID     string      `json:"id"`
+	Key    string      `json:"key"`
+	Fields issueValues `json:"fields"`
+}
+
+type issueValues struct {
+	Summary     string        `json:"summary"`
+	Description *adfNode      `json:"description"`
+	Project     *projectRef   `json:"project"`
+	Priority    *namedRef     `json:"priority"`
+	DueDate     string        `json:"duedate"`
+	Assignee    *accountRef   `json:"assignee"`
+	Labels      []string      `json:"labels"`
+	Status      *statusFields `json:"status"`
+}
+
+type projectRef struct {
+	ID   string `json:"id,omitempty"`
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+type namedRef struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type accountRef struct {
+	AccountID string `json:"accountId"`
+}
+
+type statusFields struct {
+	Name           string `json:"name"`
+	StatusCategory struct {
+		Key string `json:"key"`
+	} `json:"statusCategory"`
+}
+
+type transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   struct {
+		StatusCategory struct {
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"to"`
+}
+
+// issueFieldsRequest builds the fields of a create or edit request from the
+// non-empty fields of the input
+func (c *Client) issueFieldsRequest(task *tracker.TaskInput, create bool) map[string]any {
+	fields := map[string]any{}
+	if create {
+		projectKey := task.ProjectID
+		if projectKey == "" {
+			projectKey = c.projectKey
+		}
+		fields["project"] = map[string]string{"key": projectKey}
+		fields["issuetype"] = map[string]string{"name": c.issueType}
+	}
+	if task.Title != "" {
+		fields["summary"] = task.Title
+	}
+	if task.Description != "" {
+		fields["description"] = textToADF(task.Description)
+	}
+	// The lowest priority leaves the project default
+	if level := priority.FromTodoist(task.Priority); level > priority.LevelLow {
+		fields["priority"] = map[string]string{"name": level.Jira()}
+	}
+	if task.DueDate != "" {
+		// Jira due dates have no time, so a due with one keeps its day
+		fields["duedate"] = dueDay(task.DueDate)
+	}
+	if len(task.Labels) > 0 {
+		fields["labels"] = jiraLabels(task.Labels)
+	}
+	if create && task.AssigneeID != "" {
+		fields["assignee"] = map[string]string{"accountId": task.AssigneeID}
+	}
+	return fields
+}
+
+// jiraLabels replaces spaces, which Jira does not allow in labels
+func jiraLabels(labels []string) []string {
+	result := make([]string, len(labels))
+	for i, label := range labels {
+		result[i] = strings.Join(strings.Fields(label), "_")
+	}
+	return result
+}
+
+// CreateTask creates an issue in the project of the task, or in the
+// configured project when the task has none
+func (c *Client) CreateTask(ctx context.Context, task *tracker.TaskInput) (*tracker.Task, error) {
+	if task.Title == "" {
+		return nil, fmt.Errorf("task title is required")
+	}
+	if task.ProjectID == "" && c.projectKey == "" {
+		return nil, fmt.Errorf("jira project key is required")
+	}
+
+	var created issue
+	body := map[string]any{"fields": c.issueFieldsRequest(task, true)}
+	if err := c.httpClient.Post(ctx, "issue", body, &created); err != nil {
+		return nil, fmt.Errorf("error creating issue: %w", err)
+	}
+
+	log.Printf("Created Jira issue %s: %s", created.Key, task.Title)
+	return c.GetTask(ctx, created.Key)
+}
+
+// GetTask returns an issue by key or ID
+func (c *Client) GetTask(ctx context.Context, taskID string) (*tracker.Task, error) {
+	var found issue
+	path := fmt.Sprintf("issue/%s?fields=%s", url.PathEscape(taskID), strings.Join(issueFields, ","))
+	if err := c.httpClient.Get(ctx, path, &found); err != nil {
+		if httpclient.IsNotFound(err) {
+			return nil, fmt.Errorf("issue not found: %s", taskID)
+		}
+		return nil, fmt.Errorf("error getting issue: %w", err)
+	}
+	return c.toTask(found), nil
+}
+
+// UpdateTask edits the non-empty fields of an issue and assigns it when the
+// input names an assignee
+func (c *Client) UpdateTask(ctx context.Context, taskID string, task *tracker.TaskInput) (*tracker.Task, error) {
+	if fields := c.issueFieldsRequest(task, false); len(fields) > 0 {
+		if err := c.httpClient.Put(ctx, "issue/"+url.PathEscape(taskID), map[string]any{"fields": fields}, nil); err != nil {
+			return nil, fmt.Errorf("error updating issue: %w", err)
+		}
+	}
+	if task.AssigneeID != "" {
+		if err := c.Assign(ctx, taskID, task.AssigneeID); err != nil {
+			return nil, err
+		}
+	}
+	log.Printf("Updated Jira issue %s", taskID)
+	return c.GetTask(ctx, taskID)
+}
+
+// Assign sets the assignee of an issue by Atlassian account ID
+func (c *Client) Assign(ctx context.Context, taskID, accountID string) error {
+	body := accountRef{AccountID: accountID}
+	if err := c.httpClient.Put(ctx, "issue/"+url.PathEscape(taskID)+"/assignee", body, nil); err != nil {
+		return fmt.Errorf("error assigning issue: %w", err)
+	}
+	return nil
+}
+
+// Transitions returns the transitions available for an issue
+func (c *Client) Transitions(ctx context.Context, taskID string) ([]transition, error) {
+	var resp struct {
+		Transitions []transition `json:"transitions"`
+	}
+	if err := c.httpClient.Get(ctx, "issue/"+url.PathEscape(taskID)+"/transitions", &resp); err != nil {
+		return nil, fmt.Errorf("error getting transitions: %w", err)
+	}
+	return resp.Transitions, nil
+}
+
+// Transition moves an issue through the workflow transition with the given name
+func (c *Client) Transition(ctx context.Context, taskID, name string) error {
+	transitions, err := c.Transitions(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, name) {
+			return c.doTransition(ctx, taskID, t.ID)
+		}
+	}
+	return fmt.Errorf("issue %s has no transition %q", taskID, name)
+}
+
+func (c *Client) doTransition(ctx context.Context, taskID, transitionID string) error {
+	body := map[string]any{"transition": map[string]string{"id": transitionID}}
+	if err := c.httpClient.Post(ctx, "issue/"+url.PathEscape(taskID)+"/transitions", body, nil); err != nil {
+		return fmt.Errorf("error transitioning issue: %w", err)
+	}
+	return nil
+}
+
+// CompleteTask moves an issue to the first status of the done category its
+// workflow allows, since workflows name that transition differently
+func (c *Client) CompleteTask(ctx context.Context, taskID string) error {
+	transitions, err := c.Transitions(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	for _, t := range transitions {
+		if t.To.StatusCategory.Key == statusCategoryDone {
+			if err := c.doTransition(ctx, taskID, t.ID); err != nil {
+				return err
+			}
+			log.Printf("Moved Jira issue %s to done via %q", taskID, t.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("issue %s has no transition to a done status", taskID)
+}
+
+// DeleteTask deletes an issue
+func (c *Client) DeleteTask(ctx context.Context, taskID string) error {
+	if err := c.httpClient.Delete(ctx, "issue/"+url.PathEscape(taskID)); err != nil {
+		return fmt.Errorf("error deleting issue: %w", err)
+	}
+	log.Printf("Deleted Jira issue %s", taskID)
+	return nil
+}
+
+// ListTasks returns unfinished issues of a project, of the configured one
+// when projectID is empty
+func (c *Client) ListTasks(ctx context.Context, projectID string) ([]*tracker.Task, error) {
+	if projectID == "" {
+		projectID = c.projectKey
+	}
+	jql := "statusCategory != Done ORDER BY created DESC"
+	if projectID != "" {
+		jql = fmt.Sprintf("project = %s AND %s", quoteJQL(projectID), jql)
+	}
+	return c.Search(ctx, jql)
+}
+
+// quoteJQL quotes a value for a JQL query
+func quoteJQL(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// Search returns the issues matching a JQL query
+func (c *Client) Search(ctx context.Context, jql string) ([]*tracker.Task, error) {
+	var tasks []*tracker.Task
+	pageToken := ""
+	for len(tasks) < maxSearchResults {
+		body := map[string]any{
+			"jql":        jql,
+			"fields":     issueFields,
+			"maxResults": searchPageSize,
+		}
+		if pageToken != "" {
+			body["nextPageToken"] = pageToken
+		}
+		var resp struct {
+			Issues        []issue `json:"issues"`
+			NextPageToken string  `json:"nextPageToken"`
+		}
+		if err := c.httpClient.Post(ctx, "search/jql", body, &resp); err != nil {
+			return nil, fmt.Errorf("error searching issues: %w", err)
+		}
+		for _, found := range resp.Issues {
+			tasks = append(tasks, c.toTask(found))
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		pageToken = resp.NextPageToken
+	}
+	return tasks, nil
+}
+
+// ListProjects returns the projects issues can be created in; with a
+// configured project key only that project
+func (c *Client) ListProjects(ctx context.Context) ([]tracker.Project, error) {
+	path := "project/search?maxResults=100"
+	if c.projectKey != "" {
+		path += "&keys=" + url.QueryEscape(c.projectKey)
+	}
+	var resp struct {
+		Values []projectRef `json:"values"`
+	}
+	if err := c.httpClient.Get(ctx, path, &resp); err != nil {
+		return nil, fmt.Errorf("error getting projects: %w", err)
+	}
+
+	projects := make([]tracker.Project, 0, len(resp.Values))
+	for _, p := range resp.Values {
+		projects = append(projects, tracker.Project{ID: p.Key, Name: p.Name, URL: c.siteURL + "/browse/" + p.Key})
+	}
+	return projects, nil
+}
+
+func (c *Client) toTask(found issue) *tracker.Task {
+	task := &tracker.Task{
+		ID:          found.Key,
+		Title:       found.Fields.Summary,
+		Description: adfToText(found.Fields.Description),
+		DueDate:     found.Fields.DueDate,
+		Labels:      found.Fields.Labels,
+		URL:         c.IssueURL(found.Key),
+		Priority:    1,
+	}
+	if found.Fields.Project != nil {
+		task.ProjectID = found.Fields.Project.Key
+	}
+	if found.Fields.Priority != nil {
+		task.Priority = priority.FromJira(found.Fields.Priority.Name).Todoist()
+	}
+	if found.Fields.Assignee != nil {
+		task.AssigneeID = found.Fields.Assignee.AccountID
+	}
+	if found.Fields.Status != nil {
+		task.Completed = found.Fields.Status.StatusCategory.Key == statusCategoryDone
+	}
+	return task
+}
+
+// dueDay is the YYYY-MM-DD day of a due date, with or without a time
+func dueDay(due string) string {
+	if len(due) > len(dates.DateLayout) {
+		return due[:len(dates.DateLayout)]
+	}
+	return due
+}
@@ -0,0 +1,185 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// newTestClient serves the given routes ("METHOD /path") under /rest/api/3
+func newTestClient(t *testing.T, config *httpclient.JiraTrackerConfig, routes map[string]string) (*Client, *[]recordedRequest) {
+	t.Helper()
+	var requests []recordedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := recordedRequest{Method: r.Method, Path: r.URL.Path}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			require.NoError(t, json.Unmarshal(data, &recorded.Body))
+		}
+		requests = append(requests, recorded)
+
+		resp, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if resp == "" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, resp)
+	}))
+	t.Cleanup(server.Close)
+
+	baseURL := server.URL + "/rest/api/3"
+	httpClient := httpclient.NewClient(&httpclient.Config{BaseURL: baseURL, Timeout: 5 * time.Second})
+	return newClient(httpClient, baseURL, config), &requests
+}
+
+const issueOPS1 = `{"id": "10001", "key": "OPS-1", "fields": {
+	"summary": "Починить вход",
+	"description": {"type": "doc", "version": 1, "content": [
+		{"type": "paragraph", "content": [{"type": "text", "text": "Первая строка"}, {"type": "hardBreak"}, {"type": "text", "text": "вторая"}]},
+		{"type": "paragraph", "content": [{"type": "text", "text": "Итог"}]}
+	]},
+	"project": {"key": "OPS"},
+	"priority": {"name": "High"},
+	"duedate": "2026-04-01",
+	"assignee": {"accountId": "acc-1"},
+	"labels": ["backend"],
+	"status": {"name": "To Do", "statusCategory": {"key": "new"}}
+}}`
+
+func TestCreateTask(t *testing.T) {
+	client, requests := newTestClient(t, &httpclient.JiraTrackerConfig{ProjectKey: "OPS"}, map[string]string{
+		"POST /rest/api/3/issue":      `{"id": "10001", "key": "OPS-1"}`,
+		"GET /rest/api/3/issue/OPS-1": issueOPS1,
+	})
+
+	task, err := client.CreateTask(context.Background(), &tracker.TaskInput{
+		Title:       "Починить вход",
+		Description: "Первая строка\nвторая\n\nИтог",
+		Priority:    3,
+		DueDate:     "2026-04-01",
+		AssigneeID:  "acc-1",
+		Labels:      []string{"from telegram"},
+	})
+	require.NoError(t, err)
+
+	fields := (*requests)[0].Body["fields"].(map[string]any)
+	assert.Equal(t, map[string]any{"key": "OPS"}, fields["project"], "the configured project is used without a chat project")
+	assert.Equal(t, map[string]any{"name": "Task"}, fields["issuetype"])
+	assert.Equal(t, "Починить вход", fields["summary"])
+	assert.Equal(t, map[string]any{"name": "High"}, fields["priority"])
+	assert.Equal(t, "2026-04-01", fields["duedate"])
+	assert.Equal(t, map[string]any{"accountId": "acc-1"}, fields["assignee"])
+	assert.Equal(t, []any{"from_telegram"}, fields["labels"])
+	assert.Len(t, fields["description"].(map[string]any)["content"], 2)
+
+	assert.Equal(t, &tracker.Task{
+		ID:          "OPS-1",
+		Title:       "Починить вход",
+		Description: "Первая строка\nвторая\n\nИтог",
+		ProjectID:   "OPS",
+		Priority:    3,
+		DueDate:     "2026-04-01",
+		AssigneeID:  "acc-1",
+		Labels:      []string{"backend"},
+		URL:         client.siteURL + "/browse/OPS-1",
+	}, task)
+}
+
+func TestGetTaskMapsPrioritySchemes(t *testing.T) {
+	for name, want := range map[string]int{"Blocker": 4, "Highest": 4, "Major": 3, "Medium": 2, "Lowest": 1, "Custom": 1} {
+		issue := strings.Replace(issueOPS1, `"priority": {"name": "High"}`, `"priority": {"name": "`+name+`"}`, 1)
+		client, _ := newTestClient(t, nil, map[string]string{"GET /rest/api/3/issue/OPS-1": issue})
+
+		task, err := client.GetTask(context.Background(), "OPS-1")
+		require.NoError(t, err)
+		assert.Equal(t, want, task.Priority, name)
+	}
+}
+
+func TestCreateTaskNeedsProject(t *testing.T) {
+	client, requests := newTestClient(t, nil, nil)
+	_, err := client.CreateTask(context.Background(), &tracker.TaskInput{Title: "Без проекта"})
+	assert.ErrorContains(t, err, "project key is required")
+	assert.Empty(t, *requests)
+}
+
+func TestUpdateTaskAssigns(t *testing.T) {
+	client, requests := newTestClient(t, nil, map[string]string{
+		"PUT /rest/api/3/issue/OPS-1":          "",
+		"PUT /rest/api/3/issue/OPS-1/assignee": "",
+		"GET /rest/api/3/issue/OPS-1":          issueOPS1,
+	})
+
+	_, err := client.UpdateTask(context.Background(), "OPS-1", &tracker.TaskInput{DueDate: "2026-05-01", AssigneeID: "acc-2"})
+	require.NoError(t, err)
+
+	require.Len(t, *requests, 3)
+	assert.Equal(t, map[string]any{"fields": map[string]any{"duedate": "2026-05-01"}}, (*requests)[0].Body)
+	assert.Equal(t, "/rest/api/3/issue/OPS-1/assignee", (*requests)[1].Path)
+	assert.Equal(t, map[string]any{"accountId": "acc-2"}, (*requests)[1].Body)
+}
+
+func TestCompleteTaskUsesDoneTransition(t *testing.T) {
+	client, requests := newTestClient(t, nil, map[string]string{
+		"GET /rest/api/3/issue/OPS-1/transitions": `{"transitions": [
+			{"id": "11", "name": "In Progress", "to": {"statusCategory": {"key": "indeterminate"}}},
+			{"id": "31", "name": "Закрыть", "to": {"statusCategory": {"key": "done"}}}
+		]}`,
+		"POST /rest/api/3/issue/OPS-1/transitions": "",
+	})
+
+	require.NoError(t, client.CompleteTask(context.Background(), "OPS-1"))
+	assert.Equal(t, map[string]any{"transition": map[string]any{"id": "31"}}, (*requests)[1].Body)
+
+	require.NoError(t, client.Transition(context.Background(), "OPS-1", "in progress"))
+	assert.Equal(t, map[string]any{"transition": map[string]any{"id": "11"}}, (*requests)[3].Body)
+
+	assert.ErrorContains(t, client.Transition(context.Background(), "OPS-1", "Reopen"), `no transition "Reopen"`)
+}
+
+func TestListTasksByJQL(t *testing.T) {
+	client, requests := newTestClient(t, &httpclient.JiraTrackerConfig{ProjectKey: "OPS"}, map[string]string{
+		"POST /rest/api/3/search/jql": `{"issues": [` + issueOPS1 + `]}`,
+	})
+
+	tasks, err := client.ListTasks(context.Background(), "")
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "OPS-1", tasks[0].ID)
+	assert.Equal(t, `project = "OPS" AND statusCategory != Done ORDER BY created DESC`, (*requests)[0].Body["jql"])
+}
+
+func TestListProjects(t *testing.T) {
+	client, _ := newTestClient(t, nil, map[string]string{
+		"GET /rest/api/3/project/search": `{"values": [{"id": "1", "key": "OPS", "name": "Operations"}]}`,
+	})
+
+	projects, err := client.ListProjects(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []tracker.Project{{ID: "OPS", Name: "Operations", URL: client.siteURL + "/browse/OPS"}}, projects)
+}
+
+func TestSiteURL(t *testing.T) {
+	assert.Equal(t, "https://acme.atlassian.net", siteURL("https://acme.atlassian.net/rest/api/3"))
+	assert.Equal(t, "https://jira.example.com", siteURL("https://jira.example.com/"))
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAdminAddr is where the admin endpoint listens unless JOBS_ADMIN_ADDR is set.
+// It is bound to loopback because the endpoint has no authentication.
+const DefaultAdminAddr = "127.0.0.1:8090"
+
+// CheckAdminAddr refuses listen addresses reachable from other hosts: the
+// endpoint has no authentication, so it must stay on loopback.
+func CheckAdminAddr(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid admin address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return nil
+	}
+	return fmt.Errorf("admin address %q is not a loopback address, the endpoint has no authentication", addr)
+}
+
+// NewAdminHandler exposes the queue to the local `jobs` CLI:
+//
+//	GET  /jobs             list jobs
+//	POST /jobs/cancel?id=N cancel a pending or running job
+//	POST /jobs/retry?id=N  retry a failed, canceled or stuck job
+func NewAdminHandler(q *Queue) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(q.List())
+	})
+	mux.HandleFunc("/jobs/cancel", adminAction(q.Cancel))
+	mux.HandleFunc("/jobs/retry", adminAction(q.Retry))
+	return mux
+}
+
+func adminAction(action func(id int64) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		id, err := strconv.ParseInt(r.URL.Query().Get("id"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid job id", http.StatusBadRequest)
+			return
+		}
+		switch err := action(id); {
+		case errors.Is(err, ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+}
+
+// AdminClient talks to the admin endpoint of a running bot.
+type AdminClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewAdminClient creates a client for the admin endpoint listening on addr.
+func NewAdminClient(addr string) *AdminClient {
+	if !strings.HasPrefix(addr, "http://") && !strings.HasPrefix(addr, "https://") {
+		addr = "http://" + addr
+	}
+	return &AdminClient{
+		baseURL:    strings.TrimRight(addr, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// List returns the jobs known to the running bot.
+func (c *AdminClient) List(ctx context.Context) ([]Info, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/jobs", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError(resp)
+	}
+
+	var infos []Info
+	if err := json.NewDecoder(resp.Body).Decode(&infos); err != nil {
+		return nil, fmt.Errorf("failed to decode jobs: %w", err)
+	}
+	return infos, nil
+}
+
+// Cancel cancels a job in the running bot.
+func (c *AdminClient) Cancel(ctx context.Context, id int64) error {
+	return c.post(ctx, "/jobs/cancel", id)
+}
+
+// Retry retries a job in the running bot.
+func (c *AdminClient) Retry(ctx context.Context, id int64) error {
+	return c.post(ctx, "/jobs/retry", id)
+}
+
+func (c *AdminClient) post(ctx context.Context, path string, id int64) error {
+	url := fmt.Sprintf("%s%s?id=%d", c.baseURL, path, id)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrJobNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent {
+		return responseError(resp)
+	}
+	return nil
+}
+
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("admin endpoint returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,16 @@
+package jobs
+
+import "testing"
+
+func TestCheckAdminAddr(t *testing.T) {
+	for _, addr := range []string{DefaultAdminAddr, "localhost:8090", "[::1]:8090"} {
+		if err := CheckAdminAddr(addr); err != nil {
+			t.Errorf("CheckAdminAddr(%q): unexpected error: %v", addr, err)
+		}
+	}
+	for _, addr := range []string{":8090", "0.0.0.0:8090", "10.0.0.5:8090", "admin.example.com:8090", "8090"} {
+		if err := CheckAdminAddr(addr); err == nil {
+			t.Errorf("CheckAdminAddr(%q): expected error", addr)
+		}
+	}
+}
@@ -0,0 +1,491 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+)
+
+var ErrQueueStopped = errors.New("job queue is stopped")
+var ErrJobNotFound = errors.New("job not found")
+
+// historySize is how many failed or canceled jobs are kept for inspection and retry.
+const historySize = 20
+
+// Priority orders pending jobs; higher values are dispatched first.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+type Status string
+
+const (
+	StatusQueued   Status = "queued"
+	StatusRunning  Status = "running"
+	StatusFailed   Status = "failed"
+	StatusCanceled Status = "canceled"
+)
+
+// Job is a unit of asynchronous work, usually a call to an AI provider.
+type Job struct {
+	Kind      string
+	ChatID    int64
+	SessionID int // the discussion the job works on, 0 when it has none
+	Provider  string
+	Priority  Priority
+	// Run performs the work. The context is canceled when the job is canceled
+	// (for example, when the discussion it belongs to is closed) or the queue stops.
+	Run func(ctx context.Context) error
+	// OnStart is called right before Run, when the job leaves the queue.
+	OnStart func()
+	// OnFinish is called once the job is done, has failed or was canceled. It
+	// is not called for jobs the queue stops before they finish, so a stored
+	// job can be submitted again on the next start.
+	OnFinish func()
+}
+
+// Info is a point-in-time view of a job for admin listings.
+type Info struct {
+	ID         int64     `json:"id"`
+	Kind       string    `json:"kind"`
+	ChatID     int64     `json:"chat_id"`
+	SessionID  int       `json:"session_id,omitempty"`
+	Provider   string    `json:"provider"`
+	Priority   Priority  `json:"priority"`
+	Status     Status    `json:"status"`
+	Attempts   int       `json:"attempts"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	StartedAt  time.Time `json:"started_at,omitempty"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Retries returns how many times the job was re-dispatched after the first attempt.
+func (i Info) Retries() int {
+	if i.Attempts <= 1 {
+		return 0
+	}
+	return i.Attempts - 1
+}
+
+type entry struct {
+	id         int64
+	seq        int64
+	job        Job
+	status     Status
+	attempts   int
+	err        error
+	ctx        context.Context
+	cancel     context.CancelFunc
+	enqueuedAt time.Time
+	startedAt  time.Time
+}
+
+func (e *entry) info() Info {
+	info := Info{
+		ID:         e.id,
+		Kind:       e.job.Kind,
+		ChatID:     e.job.ChatID,
+		SessionID:  e.job.SessionID,
+		Provider:   e.job.Provider,
+		Priority:   e.job.Priority,
+		Status:     e.status,
+		Attempts:   e.attempts,
+		EnqueuedAt: e.enqueuedAt,
+		StartedAt:  e.startedAt,
+	}
+	if e.err != nil {
+		info.Error = e.err.Error()
+	}
+	return info
+}
+
+// Queue dispatches jobs by priority while limiting how many jobs run
+// concurrently against each provider.
+type Queue struct {
+	mu           sync.Mutex
+	cond         *sync.Cond
+	limits       map[string]int
+	defaultLimit int
+	running      map[string]int
+	pending      []*entry
+	active       map[int64]*entry
+	history      []*entry
+	nextID       int64
+	nextSeq      int64
+	stopped      bool
+	started      bool
+	baseCtx      context.Context
+	stopAll      context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewQueue creates a queue with per-provider concurrency limits. Providers
+// missing from limits (or with a non-positive limit) run one job at a time.
+func NewQueue(limits map[string]int) *Queue {
+	baseCtx, stopAll := context.WithCancel(context.Background())
+	q := &Queue{
+		limits:       make(map[string]int, len(limits)),
+		defaultLimit: 1,
+		running:      make(map[string]int),
+		active:       make(map[int64]*entry),
+		baseCtx:      baseCtx,
+		stopAll:      stopAll,
+	}
+	for provider, limit := range limits {
+		if limit > 0 {
+			q.limits[provider] = limit
+		}
+	}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// Start launches the worker pool. There is one worker per concurrency slot,
+// plus one shared slot for providers without an explicit limit.
+func (q *Queue) Start() {
+	q.mu.Lock()
+	if q.started || q.stopped {
+		q.mu.Unlock()
+		return
+	}
+	q.started = true
+	workers := q.defaultLimit
+	for _, limit := range q.limits {
+		workers += limit
+	}
+	q.mu.Unlock()
+
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go q.worker()
+	}
+}
+
+// Stop cancels running jobs, drops pending ones and waits for workers to exit.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	q.stopped = true
+	for _, e := range q.pending {
+		e.cancel()
+	}
+	q.pending = nil
+	q.mu.Unlock()
+
+	q.stopAll()
+	q.cond.Broadcast()
+	q.wg.Wait()
+}
+
+// Submit enqueues a job and returns its ID and 1-based position among the jobs
+// waiting for the same provider.
+func (q *Queue) Submit(job Job) (int64, int, error) {
+	if job.Run == nil {
+		return 0, 0, errors.New("job run function is required")
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return 0, 0, ErrQueueStopped
+	}
+
+	q.nextID++
+	e := q.enqueueLocked(q.nextID, job, 0)
+	return e.id, q.positionLocked(e), nil
+}
+
+func (q *Queue) enqueueLocked(id int64, job Job, attempts int) *entry {
+	q.nextSeq++
+	ctx, cancel := context.WithCancel(q.baseCtx)
+	e := &entry{
+		id:         id,
+		seq:        q.nextSeq,
+		job:        job,
+		status:     StatusQueued,
+		attempts:   attempts,
+		ctx:        ctx,
+		cancel:     cancel,
+		enqueuedAt: time.Now(),
+	}
+	q.pending = append(q.pending, e)
+	q.cond.Broadcast()
+	return e
+}
+
+// List returns pending and running jobs followed by recently failed or canceled ones.
+func (q *Queue) List() []Info {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	result := make([]Info, 0, len(q.active)+len(q.pending)+len(q.history))
+	for _, e := range q.active {
+		result = append(result, e.info())
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	for _, e := range q.pending {
+		result = append(result, e.info())
+	}
+	for i := len(q.history) - 1; i >= 0; i-- {
+		result = append(result, q.history[i].info())
+	}
+	return result
+}
+
+// Cancel cancels a pending or running job.
+func (q *Queue) Cancel(id int64) error {
+	q.mu.Lock()
+	for i, e := range q.pending {
+		if e.id == id {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			e.status = StatusCanceled
+			e.cancel()
+			q.rememberLocked(e)
+			q.mu.Unlock()
+			finish(e)
+			return nil
+		}
+	}
+	defer q.mu.Unlock()
+
+	// A running job finishes once its Run returns
+	if e, ok := q.active[id]; ok && e.status == StatusRunning {
+		e.status = StatusCanceled
+		e.cancel()
+		return nil
+	}
+
+	return ErrJobNotFound
+}
+
+// Retry re-enqueues a failed or canceled job, or restarts a stuck running one.
+// The job keeps its ID and its attempt counter grows on the next dispatch.
+func (q *Queue) Retry(id int64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.stopped {
+		return ErrQueueStopped
+	}
+
+	if e, ok := q.active[id]; ok {
+		// The stuck run is abandoned; its worker slot is freed once Run returns.
+		e.status = StatusCanceled
+		e.cancel()
+		delete(q.active, id)
+		q.enqueueLocked(id, e.job, e.attempts)
+		return nil
+	}
+
+	for i, e := range q.history {
+		if e.id == id {
+			q.history = append(q.history[:i], q.history[i+1:]...)
+			q.enqueueLocked(id, e.job, e.attempts)
+			return nil
+		}
+	}
+
+	for _, e := range q.pending {
+		if e.id == id {
+			return nil
+		}
+	}
+
+	return ErrJobNotFound
+}
+
+func (q *Queue) rememberLocked(e *entry) {
+	q.history = append(q.history, e)
+	if len(q.history) > historySize {
+		q.history = q.history[len(q.history)-historySize:]
+	}
+}
+
+// CancelChat cancels every pending and running job of a chat and returns how
+// many jobs were affected.
+func (q *Queue) CancelChat(chatID int64) int {
+	return q.cancelMatching(func(job Job) bool { return job.ChatID == chatID })
+}
+
+// CancelSession cancels the pending and running jobs of one discussion, leaving
+// the other discussions of the chat alone, and returns how many were affected.
+func (q *Queue) CancelSession(sessionID int) int {
+	if sessionID == 0 {
+		return 0
+	}
+	return q.cancelMatching(func(job Job) bool { return job.SessionID == sessionID })
+}
+
+func (q *Queue) cancelMatching(match func(Job) bool) int {
+	q.mu.Lock()
+
+	var dropped []*entry
+	kept := q.pending[:0]
+	for _, e := range q.pending {
+		if match(e.job) {
+			e.status = StatusCanceled
+			e.cancel()
+			q.rememberLocked(e)
+			dropped = append(dropped, e)
+			continue
+		}
+		kept = append(kept, e)
+	}
+	q.pending = kept
+
+	canceled := len(dropped)
+	for _, e := range q.active {
+		if match(e.job) && e.status == StatusRunning {
+			e.status = StatusCanceled
+			e.cancel()
+			canceled++
+		}
+	}
+
+	q.mu.Unlock()
+	finish(dropped...)
+	return canceled
+}
+
+// Pending returns the number of jobs waiting to be dispatched.
+func (q *Queue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+func (q *Queue) worker() {
+	defer q.wg.Done()
+
+	for {
+		e := q.next()
+		if e == nil {
+			return
+		}
+		q.run(e)
+	}
+}
+
+func (q *Queue) next() *entry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if q.stopped {
+			return nil
+		}
+
+		if idx := q.nextEligibleLocked(); idx >= 0 {
+			e := q.pending[idx]
+			q.pending = append(q.pending[:idx], q.pending[idx+1:]...)
+			e.status = StatusRunning
+			e.startedAt = time.Now()
+			e.attempts++
+			q.running[e.job.Provider]++
+			q.active[e.id] = e
+			return e
+		}
+
+		q.cond.Wait()
+	}
+}
+
+func (q *Queue) run(e *entry) {
+	var runErr error
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[JOBS] job %d (%s) panicked: %v", e.id, e.job.Kind, r)
+			runErr = fmt.Errorf("panic: %v", r)
+		}
+		e.cancel()
+
+		q.mu.Lock()
+		q.running[e.job.Provider]--
+		finished := false
+		// A retried job is already back in the queue under the same ID.
+		if q.active[e.id] == e {
+			delete(q.active, e.id)
+			if runErr != nil || e.status == StatusCanceled {
+				if e.status != StatusCanceled {
+					e.status = StatusFailed
+				}
+				e.err = runErr
+				q.rememberLocked(e)
+			}
+			// A job the stopping queue interrupted is not finished
+			finished = !q.stopped || runErr == nil
+		}
+		q.mu.Unlock()
+		q.cond.Broadcast()
+		if finished {
+			finish(e)
+		}
+	}()
+
+	if e.job.OnStart != nil {
+		e.job.OnStart()
+	}
+
+	log.Printf("[JOBS] started job %d (%s) for chat %d after %s in queue, attempt %d", e.id, e.job.Kind, e.job.ChatID, e.startedAt.Sub(e.enqueuedAt).Round(time.Millisecond), e.attempts)
+	if runErr = e.job.Run(e.ctx); runErr != nil {
+		log.Printf("[JOBS] job %d (%s) failed: %v", e.id, e.job.Kind, runErr)
+		return
+	}
+	log.Printf("[JOBS] finished job %d (%s) in %s", e.id, e.job.Kind, time.Since(e.startedAt).Round(time.Millisecond))
+}
+
+// nextEligibleLocked returns the index of the highest-priority pending job
+// whose provider still has a free concurrency slot, or -1.
+func (q *Queue) nextEligibleLocked() int {
+	best := -1
+	for i, e := range q.pending {
+		if q.running[e.job.Provider] >= q.limitFor(e.job.Provider) {
+			continue
+		}
+		if best == -1 || before(e, q.pending[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func (q *Queue) positionLocked(target *entry) int {
+	position := 1
+	for _, e := range q.pending {
+		if e != target && e.job.Provider == target.job.Provider && before(e, target) {
+			position++
+		}
+	}
+	return position
+}
+
+func (q *Queue) limitFor(provider string) int {
+	if limit, ok := q.limits[provider]; ok {
+		return limit
+	}
+	return q.defaultLimit
+}
+
+// finish reports jobs that left the queue for good
+func finish(entries ...*entry) {
+	for _, e := range entries {
+		if e.job.OnFinish != nil {
+			e.job.OnFinish()
+		}
+	}
+}
+
+func before(a, b *entry) bool {
+	if a.job.Priority != b.job.Priority {
+		return a.job.Priority > b.job.Priority
+	}
+	return a.seq < b.seq
+}
@@ -0,0 +1,306 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("condition was not met in time")
+}
+
+// Tests that pending jobs are dispatched by priority and then in submission order
+func TestQueue_DispatchesByPriority(t *testing.T) {
+	q := NewQueue(map[string]int{"ai": 1})
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			mu.Lock()
+			order = append(order, name)
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	q.Submit(Job{Kind: "low", Provider: "ai", Priority: PriorityLow, Run: record("low")})
+	q.Submit(Job{Kind: "normal-1", Provider: "ai", Priority: PriorityNormal, Run: record("normal-1")})
+	q.Submit(Job{Kind: "high", Provider: "ai", Priority: PriorityHigh, Run: record("high")})
+	_, position, _ := q.Submit(Job{Kind: "normal-2", Provider: "ai", Priority: PriorityNormal, Run: record("normal-2")})
+	if position != 3 {
+		t.Fatalf("expected position 3 behind high and normal-1, got %d", position)
+	}
+
+	q.Start()
+	defer q.Stop()
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 4
+	})
+
+	expected := []string{"high", "normal-1", "normal-2", "low"}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("unexpected dispatch order: %v", order)
+		}
+	}
+}
+
+// Tests that no more than the configured number of jobs run against one provider at a time
+func TestQueue_RespectsProviderConcurrency(t *testing.T) {
+	q := NewQueue(map[string]int{"ai": 2})
+	q.Start()
+	defer q.Stop()
+
+	var current, peak, done int32
+	release := make(chan struct{})
+	for i := 0; i < 6; i++ {
+		q.Submit(Job{Provider: "ai", Run: func(ctx context.Context) error {
+			n := atomic.AddInt32(&current, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			<-release
+			atomic.AddInt32(&current, -1)
+			atomic.AddInt32(&done, 1)
+			return nil
+		}})
+	}
+
+	waitFor(t, func() bool { return atomic.LoadInt32(&current) == 2 })
+	close(release)
+	waitFor(t, func() bool { return atomic.LoadInt32(&done) == 6 })
+
+	if peak != 2 {
+		t.Fatalf("expected at most 2 concurrent jobs, got %d", peak)
+	}
+}
+
+// Tests that canceling a chat drops its pending jobs and cancels the running one
+func TestQueue_CancelChat(t *testing.T) {
+	q := NewQueue(map[string]int{"ai": 1})
+	q.Start()
+	defer q.Stop()
+
+	started := make(chan struct{})
+	runningCanceled := make(chan struct{})
+	q.Submit(Job{ChatID: 1, Provider: "ai", Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(runningCanceled)
+		return ctx.Err()
+	}})
+	<-started
+
+	var pendingRan int32
+	q.Submit(Job{ChatID: 1, Provider: "ai", Run: func(ctx context.Context) error {
+		atomic.AddInt32(&pendingRan, 1)
+		return nil
+	}})
+	otherDone := make(chan struct{})
+	q.Submit(Job{ChatID: 2, Provider: "ai", Run: func(ctx context.Context) error {
+		close(otherDone)
+		return nil
+	}})
+
+	if canceled := q.CancelChat(1); canceled != 2 {
+		t.Fatalf("expected 2 canceled jobs, got %d", canceled)
+	}
+
+	select {
+	case <-runningCanceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("running job was not canceled")
+	}
+	select {
+	case <-otherDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job of another chat did not run")
+	}
+	if atomic.LoadInt32(&pendingRan) != 0 {
+		t.Fatal("canceled pending job should not run")
+	}
+}
+
+func TestQueue_CancelSession(t *testing.T) {
+	q := NewQueue(map[string]int{"ai": 1})
+	q.Start()
+	defer q.Stop()
+
+	started := make(chan struct{})
+	runningCanceled := make(chan struct{})
+	q.Submit(Job{ChatID: 1, SessionID: 10, Provider: "ai", Run: func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		close(runningCanceled)
+		return ctx.Err()
+	}})
+	<-started
+
+	var pendingRan int32
+	q.Submit(Job{ChatID: 1, SessionID: 10, Provider: "ai", Run: func(ctx context.Context) error {
+		atomic.AddInt32(&pendingRan, 1)
+		return nil
+	}})
+	otherDone := make(chan struct{})
+	q.Submit(Job{ChatID: 1, SessionID: 11, Provider: "ai", Run: func(ctx context.Context) error {
+		close(otherDone)
+		return nil
+	}})
+
+	if canceled := q.CancelSession(10); canceled != 2 {
+		t.Fatalf("expected 2 canceled jobs, got %d", canceled)
+	}
+
+	select {
+	case <-runningCanceled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("running job was not canceled")
+	}
+	select {
+	case <-otherDone:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job of another discussion in the chat did not run")
+	}
+	if atomic.LoadInt32(&pendingRan) != 0 {
+		t.Fatal("canceled pending job should not run")
+	}
+}
+
+// Tests that OnFinish reports finished and canceled jobs, but not the jobs the
+// queue stops before they finish
+func TestQueue_OnFinish(t *testing.T) {
+	q := NewQueue(map[string]int{"ai": 1})
+	q.Start()
+
+	var mu sync.Mutex
+	finished := map[string]bool{}
+	job := func(kind string, run func(ctx context.Context) error) Job {
+		return Job{Kind: kind, Provider: "ai", Run: run, OnFinish: func() {
+			mu.Lock()
+			finished[kind] = true
+			mu.Unlock()
+		}}
+	}
+	isFinished := func(kind string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return finished[kind]
+	}
+
+	q.Submit(job("done", func(ctx context.Context) error { return nil }))
+	waitFor(t, func() bool { return isFinished("done") })
+
+	started := make(chan struct{})
+	q.Submit(job("interrupted", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	<-started
+	canceledID, _, _ := q.Submit(job("canceled", func(ctx context.Context) error { return nil }))
+	q.Submit(job("dropped", func(ctx context.Context) error { return nil }))
+
+	if err := q.Cancel(canceledID); err != nil {
+		t.Fatalf("cancel: %v", err)
+	}
+	if !isFinished("canceled") {
+		t.Fatal("expected a canceled pending job to finish")
+	}
+
+	q.Stop()
+	if isFinished("interrupted") || isFinished("dropped") {
+		t.Fatalf("expected jobs the queue stopped to stay unfinished, got %v", finished)
+	}
+}
+
+func TestQueue_SubmitAfterStop(t *testing.T) {
+	q := NewQueue(nil)
+	q.Start()
+	q.Stop()
+
+	if _, _, err := q.Submit(Job{Run: func(ctx context.Context) error { return nil }}); err != ErrQueueStopped {
+		t.Fatalf("expected ErrQueueStopped, got %v", err)
+	}
+}
+
+// Tests that a failed job is listed with its error and can be retried under the same ID
+func TestQueue_RetryFailedJob(t *testing.T) {
+	q := NewQueue(nil)
+	q.Start()
+	defer q.Stop()
+
+	var runs int32
+	id, _, _ := q.Submit(Job{Kind: "analyze", Run: func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			return errors.New("provider timeout")
+		}
+		return nil
+	}})
+
+	waitFor(t, func() bool {
+		infos := q.List()
+		return len(infos) == 1 && infos[0].Status == StatusFailed
+	})
+	info := q.List()[0]
+	if info.ID != id || info.Error != "provider timeout" || info.Retries() != 0 {
+		t.Fatalf("unexpected failed job info: %+v", info)
+	}
+
+	if err := q.Retry(id); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	waitFor(t, func() bool { return atomic.LoadInt32(&runs) == 2 && len(q.List()) == 0 })
+
+	if err := q.Retry(id); err != ErrJobNotFound {
+		t.Fatalf("expected ErrJobNotFound for finished job, got %v", err)
+	}
+}
+
+// Tests that retrying a stuck running job cancels it and runs it again
+func TestQueue_RetryStuckJob(t *testing.T) {
+	q := NewQueue(nil)
+	q.Start()
+	defer q.Stop()
+
+	var runs int32
+	started := make(chan struct{}, 2)
+	id, _, _ := q.Submit(Job{Run: func(ctx context.Context) error {
+		if atomic.AddInt32(&runs, 1) == 1 {
+			started <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		started <- struct{}{}
+		return nil
+	}})
+	<-started
+
+	if err := q.Retry(id); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stuck job was not restarted")
+	}
+	waitFor(t, func() bool { return len(q.List()) == 0 })
+}
@@ -0,0 +1,233 @@
+// Package msgsplit breaks long bot messages into parts that fit Telegram's
+// message limit without cutting Markdown entities apart.
+package msgsplit
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLength is Telegram's limit for the text of one message, in UTF-16 code units
+const MaxLength = 4096
+
+const (
+	// indicatorReserve leaves room for the page indicator, e.g. "\n\n_(12/12)_"
+	indicatorReserve = 16
+	preFence         = "```"
+	preOpen          = preFence + "\n"
+	preClose         = "\n" + preFence
+)
+
+// Boundary ranks, higher is a nicer place to cut
+const (
+	rankRune = iota
+	rankSpace
+	rankLine
+	rankParagraph
+)
+
+type boundary struct {
+	end   int // byte offset where the current part ends
+	next  int // byte offset where the next part starts
+	rank  int
+	inPre bool
+}
+
+// Length returns the length of text the way Telegram counts it
+func Length(text string) int {
+	n := 0
+	for _, r := range text {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// runeWidth is the number of UTF-16 code units of r
+func runeWidth(r rune) int {
+	if r >= 0x10000 {
+		return 2
+	}
+	return 1
+}
+
+// Split breaks text into parts of at most limit UTF-16 code units, preferring
+// paragraph breaks, then line breaks, then spaces. With markdown set, text is
+// treated as Telegram legacy Markdown: cuts never fall inside bold, italic,
+// inline code or links, and a code block crossing a cut is closed and reopened.
+// When there are several parts, each one ends with a page indicator.
+func Split(text string, limit int, markdown bool) []string {
+	if Length(text) <= limit {
+		return []string{text}
+	}
+
+	budget := limit - indicatorReserve
+	if budget <= len(preOpen)+len(preClose) {
+		budget = limit
+	}
+
+	widths := prefixWidths(text)
+	boundaries := findBoundaries(text, markdown)
+
+	var parts []string
+	start, startInPre, next := 0, false, 0
+	for start < len(text) {
+		prefix := ""
+		if startInPre {
+			prefix = preOpen
+		}
+		if len(prefix)+widths[len(text)]-widths[start] <= budget {
+			parts = append(parts, prefix+text[start:])
+			break
+		}
+
+		for next < len(boundaries) && boundaries[next].end <= start {
+			next++
+		}
+		cut, ok := pickBoundary(boundaries[next:], widths, start, budget-len(prefix))
+		if !ok {
+			cut = hardCut(text, widths, start, budget-len(prefix)-len(preClose), startInPre)
+		}
+
+		part := prefix + text[start:cut.end]
+		if cut.inPre {
+			part += preClose
+		}
+		parts = append(parts, part)
+
+		start, startInPre = cut.next, cut.inPre
+		if !startInPre {
+			for start < len(text) && text[start] == '\n' {
+				start++
+			}
+		}
+	}
+
+	if len(parts) < 2 {
+		return parts
+	}
+	for i := range parts {
+		parts[i] += pageIndicator(i+1, len(parts), markdown)
+	}
+	return parts
+}
+
+func pageIndicator(page, total int, markdown bool) string {
+	if markdown {
+		return fmt.Sprintf("\n\n_(%d/%d)_", page, total)
+	}
+	return fmt.Sprintf("\n\n(%d/%d)", page, total)
+}
+
+// prefixWidths maps every byte offset to the UTF-16 length of the text before it
+func prefixWidths(text string) []int {
+	widths := make([]int, len(text)+1)
+	width := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		for j := i; j < i+size; j++ {
+			widths[j] = width
+		}
+		width += runeWidth(r)
+		i += size
+	}
+	widths[len(text)] = width
+	return widths
+}
+
+// pickBoundary chooses the best boundary that fits: the highest rank in the
+// second half of the budget, or the last one that fits when the half is empty
+func pickBoundary(boundaries []boundary, widths []int, start, budget int) (boundary, bool) {
+	var best, last boundary
+	found, foundLast := false, false
+	for _, b := range boundaries {
+		width := widths[b.end] - widths[start]
+		if b.inPre {
+			width += len(preClose)
+		}
+		if width > budget {
+			break
+		}
+		last, foundLast = b, true
+		if width >= budget/2 && (!found || b.rank >= best.rank) {
+			best, found = b, true
+		}
+	}
+	if found {
+		return best, true
+	}
+	return last, foundLast
+}
+
+// hardCut cuts at the budget when no boundary fits, e.g. inside a huge entity
+func hardCut(text string, widths []int, start, budget int, inPre bool) boundary {
+	end := start
+	for i := range text[start:] {
+		if widths[start+i]-widths[start] > budget {
+			break
+		}
+		end = start + i
+	}
+	if end == start {
+		_, size := utf8.DecodeRuneInString(text[start:])
+		end = start + size
+	}
+	return boundary{end: end, next: end, inPre: inPre}
+}
+
+// findBoundaries lists the offsets where text may be cut, in order
+func findBoundaries(text string, markdown bool) []boundary {
+	var boundaries []boundary
+	var closer string // what closes the entity the scan is in, "" outside entities
+	inLinkURL := false
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		_, size := utf8.DecodeRuneInString(text[i:])
+
+		if closer == "" && !inLinkURL {
+			if i > 0 {
+				boundaries = append(boundaries, boundary{end: i, next: i, rank: rankRune})
+			}
+			switch {
+			case c == '\n':
+				rank := rankLine
+				if i+1 < len(text) && text[i+1] == '\n' {
+					rank = rankParagraph
+				}
+				boundaries = append(boundaries, boundary{end: i, next: i + 1, rank: rank})
+			case c == ' ':
+				boundaries = append(boundaries, boundary{end: i, next: i + 1, rank: rankSpace})
+			case !markdown:
+			case c == '\\' && i+1 < len(text):
+				_, escaped := utf8.DecodeRuneInString(text[i+1:])
+				size = 1 + escaped
+			case strings.HasPrefix(text[i:], preFence):
+				closer, size = preFence, len(preFence)
+			case c == '*' || c == '_' || c == '`':
+				closer = string(c)
+			case c == '[':
+				closer = "]"
+			}
+			i += size
+			continue
+		}
+
+		switch {
+		case inLinkURL:
+			if c == ')' {
+				inLinkURL = false
+			}
+		case closer == preFence && c == '\n' && !strings.HasPrefix(text[i+1:], preFence):
+			boundaries = append(boundaries, boundary{end: i, next: i + 1, rank: rankLine, inPre: true})
+		case strings.HasPrefix(text[i:], closer):
+			size = len(closer)
+			if closer == "]" && i+1 < len(text) && text[i+1] == '(' {
+				inLinkURL, size = true, 2
+			}
+			closer = ""
+		}
+		i += size
+	}
+	return boundaries
+}
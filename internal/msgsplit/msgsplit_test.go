@@ -0,0 +1,100 @@
+package msgsplit
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSplit_ShortTextUnchanged(t *testing.T) {
+	parts := Split("*короткий* текст", 100, true)
+	if len(parts) != 1 || parts[0] != "*короткий* текст" {
+		t.Fatalf("expected text to stay whole, got %q", parts)
+	}
+}
+
+func TestSplit_PrefersParagraphsAndAddsIndicators(t *testing.T) {
+	paragraph := strings.TrimSpace(strings.Repeat("строка списка\n", 4))
+	text := strings.Join([]string{paragraph, paragraph, paragraph, paragraph}, "\n\n")
+
+	parts := Split(text, 150, false)
+	if len(parts) < 2 {
+		t.Fatalf("expected several parts, got %q", parts)
+	}
+	for i, part := range parts {
+		if n := Length(part); n > 150 {
+			t.Errorf("part %d has length %d, limit is 150", i+1, n)
+		}
+		body := part[:strings.LastIndex(part, "\n\n(")]
+		if !strings.HasSuffix(body, "строка списка") || strings.HasPrefix(body, "\n") {
+			t.Errorf("expected part %d to be cut between lines, got %q", i+1, body)
+		}
+	}
+	if !strings.HasSuffix(parts[0], "(1/"+strconv.Itoa(len(parts))+")") {
+		t.Errorf("expected page indicator, got %q", parts[0])
+	}
+}
+
+func TestSplit_KeepsMarkdownEntitiesWhole(t *testing.T) {
+	var sb strings.Builder
+	for i := 0; i < 30; i++ {
+		sb.WriteString("• *жирная задача* с [ссылкой](https://example.com/a_b) и `код_1` \\_escaped\\_\n")
+	}
+
+	for _, part := range Split(sb.String(), 200, true) {
+		if n := Length(part); n > 200 {
+			t.Errorf("part has length %d, limit is 200", n)
+		}
+		body := part[:strings.LastIndex(part, "\n\n_(")]
+		if !balanced(body) {
+			t.Errorf("expected entities to be balanced in %q", body)
+		}
+	}
+}
+
+func TestSplit_ReopensCodeBlocks(t *testing.T) {
+	text := "```\n" + strings.TrimSpace(strings.Repeat("line of code\n", 40)) + "\n```"
+
+	parts := Split(text, 150, true)
+	if len(parts) < 2 {
+		t.Fatalf("expected several parts, got %q", parts)
+	}
+	for i, part := range parts {
+		if n := Length(part); n > 150 {
+			t.Errorf("part %d has length %d, limit is 150", i+1, n)
+		}
+		if !strings.HasPrefix(part, "```\n") || strings.Count(part, "```")%2 != 0 {
+			t.Errorf("expected part %d to hold a closed code block, got %q", i+1, part)
+		}
+	}
+}
+
+func TestSplit_HardCutsWithoutBoundaries(t *testing.T) {
+	parts := Split(strings.Repeat("я", 250), 100, false)
+	if len(parts) != 3 {
+		t.Fatalf("expected 3 parts, got %d", len(parts))
+	}
+	var sb strings.Builder
+	for _, part := range parts {
+		if n := Length(part); n > 100 {
+			t.Errorf("part has length %d, limit is 100", n)
+		}
+		sb.WriteString(part[:strings.LastIndex(part, "\n\n(")])
+	}
+	if sb.String() != strings.Repeat("я", 250) {
+		t.Error("expected hard cuts to keep the whole text")
+	}
+}
+
+func TestLength_CountsUTF16Units(t *testing.T) {
+	if n := Length("a я 🎉"); n != 6 {
+		t.Fatalf("expected 6 code units, got %d", n)
+	}
+}
+
+// balanced reports whether no legacy Markdown entity is left open
+func balanced(text string) bool {
+	boundaries := findBoundaries(text+"\n", true)
+	last := boundaries[len(boundaries)-1]
+	return last.end == len(text) && !last.inPre
+}
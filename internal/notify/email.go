@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// Environment variables of the SMTP server used by email notifiers
+const (
+	EnvSMTPAddr     = "SMTP_ADDR"
+	EnvSMTPFrom     = "SMTP_FROM"
+	EnvSMTPUsername = "SMTP_USERNAME"
+	EnvSMTPPassword = "SMTP_PASSWORD"
+)
+
+// EmailConfig is the SMTP server email notifiers send through
+type EmailConfig struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// EmailConfigFromEnv reads the SMTP settings; ok is false when SMTP_ADDR is not set
+func EmailConfigFromEnv() (EmailConfig, bool, error) {
+	cfg := EmailConfig{
+		Addr:     strings.TrimSpace(os.Getenv(EnvSMTPAddr)),
+		From:     strings.TrimSpace(os.Getenv(EnvSMTPFrom)),
+		Username: os.Getenv(EnvSMTPUsername),
+		Password: os.Getenv(EnvSMTPPassword),
+	}
+	if cfg.Addr == "" {
+		return EmailConfig{}, false, nil
+	}
+	if _, _, err := net.SplitHostPort(cfg.Addr); err != nil {
+		return EmailConfig{}, false, fmt.Errorf("invalid %s %q: %w", EnvSMTPAddr, cfg.Addr, err)
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return EmailConfig{}, false, fmt.Errorf("invalid %s %q: %w", EnvSMTPFrom, cfg.From, err)
+	}
+	return cfg, true, nil
+}
+
+// EmailNotifier mails events to one address
+type EmailNotifier struct {
+	cfg EmailConfig
+	to  string
+}
+
+// NewEmailFactory builds notifiers for email address targets
+func NewEmailFactory(cfg EmailConfig) Factory {
+	return func(target, _ string) (Notifier, error) {
+		addr, err := mail.ParseAddress(strings.TrimSpace(target))
+		if err != nil {
+			return nil, fmt.Errorf("email target must be an address, got %q", target)
+		}
+		return &EmailNotifier{cfg: cfg, to: addr.Address}, nil
+	}
+}
+
+func (n *EmailNotifier) OnDraftCreated(ctx context.Context, event DraftEvent) error {
+	return n.send(ctx, "Черновик задачи: "+event.Draft.Title, event.Describe())
+}
+
+func (n *EmailNotifier) OnTaskCreated(ctx context.Context, event TaskEvent) error {
+	return n.send(ctx, "Создана задача: "+event.Task.Title, event.Describe(false))
+}
+
+func (n *EmailNotifier) OnTaskCompleted(ctx context.Context, event TaskEvent) error {
+	return n.send(ctx, "Закрыта задача: "+event.Task.Title, event.Describe(true))
+}
+
+func (n *EmailNotifier) OnSessionClosed(ctx context.Context, event SessionEvent) error {
+	return n.send(ctx, "Обсуждение завершено", event.Describe())
+}
+
+func (n *EmailNotifier) send(ctx context.Context, subject, body string) error {
+	message := buildEmail(n.cfg.From, n.to, subject, body)
+
+	var auth smtp.Auth
+	if n.cfg.Username != "" {
+		host, _, _ := net.SplitHostPort(n.cfg.Addr)
+		auth = smtp.PlainAuth("", n.cfg.Username, n.cfg.Password, host)
+	}
+
+	// net/smtp has no context support, so the deadline only stops waiting for the result
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(n.cfg.Addr, auth, n.cfg.From, []string{n.to}, message)
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email to %s: %w", n.to, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to send email to %s: %w", n.to, ctx.Err())
+	}
+}
+
+func buildEmail(from, to, subject, body string) []byte {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", from)
+	fmt.Fprintf(&sb, "To: %s\r\n", to)
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,159 @@
+// Package notify lets chats forward bot events to other systems. A Notifier
+// receives the events; a Registry builds notifiers of each kind from the
+// target a chat configured, so new kinds plug in without touching the bot.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Built-in notifier kinds
+const (
+	KindTelegram = "telegram"
+	KindEmail    = "email"
+	KindWebhook  = "webhook"
+)
+
+// Task is the task an event is about
+type Task struct {
+	ID    string
+	Title string
+	URL   string
+}
+
+// TaskEvent is sent when a task is created or completed through the bot
+type TaskEvent struct {
+	ChatID    int64
+	ChatTitle string
+	SessionID int
+	Actor     string
+	Task      Task
+}
+
+// Reasons a discussion session was closed
+const (
+	ReasonTaskCreated = "task_created"
+	ReasonCanceled    = "canceled"
+	// ReasonMerged closes a discussion whose draft was added to an existing task
+	ReasonMerged = "merged"
+)
+
+// Draft is the AI draft of a task waiting for confirmation
+type Draft struct {
+	Title       string
+	Description string
+	DueDate     string
+	Priority    int
+}
+
+// DraftEvent is sent when the bot shows a new task draft for a discussion
+type DraftEvent struct {
+	ChatID    int64
+	ChatTitle string
+	SessionID int
+	Actor     string
+	Draft     Draft
+}
+
+// SessionEvent is sent when a discussion session is closed
+type SessionEvent struct {
+	ChatID    int64
+	ChatTitle string
+	SessionID int
+	Actor     string
+	Reason    string
+}
+
+// Notifier receives bot events for one configured target
+type Notifier interface {
+	OnDraftCreated(ctx context.Context, event DraftEvent) error
+	OnTaskCreated(ctx context.Context, event TaskEvent) error
+	OnTaskCompleted(ctx context.Context, event TaskEvent) error
+	OnSessionClosed(ctx context.Context, event SessionEvent) error
+}
+
+// Factory builds a notifier for a target such as a chat ID, an email address or a URL.
+// The secret is optional; kinds that do not sign requests ignore it. It validates
+// the target, so it is also called when a chat adds a notifier.
+type Factory func(target, secret string) (Notifier, error)
+
+// Registry holds the notifier kinds available to chats
+type Registry struct {
+	factories map[string]Factory
+}
+
+func NewRegistry() *Registry {
+	return &Registry{factories: make(map[string]Factory)}
+}
+
+// Register adds or replaces a notifier kind
+func (r *Registry) Register(kind string, factory Factory) {
+	r.factories[kind] = factory
+}
+
+// Kinds returns the registered kinds in alphabetical order
+func (r *Registry) Kinds() []string {
+	kinds := make([]string, 0, len(r.factories))
+	for kind := range r.factories {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// Build creates a notifier of kind for target
+func (r *Registry) Build(kind, target, secret string) (Notifier, error) {
+	factory, ok := r.factories[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier kind %q", kind)
+	}
+	return factory(target, secret)
+}
+
+// Describe returns the human readable text of an event, used by notifiers that send text
+func (e TaskEvent) Describe(completed bool) string {
+	verb := "Создана задача"
+	if completed {
+		verb = "Закрыта задача"
+	}
+	text := fmt.Sprintf("%s «%s»", verb, e.Task.Title)
+	if e.ChatTitle != "" {
+		text += fmt.Sprintf(" в чате «%s»", e.ChatTitle)
+	}
+	if e.Actor != "" {
+		text += ", " + e.Actor
+	}
+	if e.Task.URL != "" {
+		text += "\n" + e.Task.URL
+	}
+	return text
+}
+
+// Describe returns the human readable text of an event, used by notifiers that send text
+func (e DraftEvent) Describe() string {
+	text := fmt.Sprintf("Черновик задачи «%s»", e.Draft.Title)
+	if e.ChatTitle != "" {
+		text += fmt.Sprintf(" в чате «%s»", e.ChatTitle)
+	}
+	if e.Actor != "" {
+		text += ", " + e.Actor
+	}
+	return text
+}
+
+// Describe returns the human readable text of an event, used by notifiers that send text
+func (e SessionEvent) Describe() string {
+	text := "Обсуждение завершено"
+	if e.Reason == ReasonTaskCreated {
+		text = "Обсуждение завершено задачей"
+	}
+	if e.ChatTitle != "" {
+		text += fmt.Sprintf(" в чате «%s»", e.ChatTitle)
+	}
+	if e.Actor != "" {
+		text += ", " + e.Actor
+	}
+	return text
+}
@@ -0,0 +1,160 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/webhookauth"
+)
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(KindWebhook, NewWebhookFactory(newTestHTTPClient()))
+	registry.Register(KindTelegram, NewTelegramFactory(func(int64, string) {}))
+
+	assert.Equal(t, []string{KindTelegram, KindWebhook}, registry.Kinds())
+
+	_, err := registry.Build(KindEmail, "ops@example.com", "")
+	assert.Error(t, err)
+	_, err = registry.Build(KindTelegram, "not-a-chat", "")
+	assert.Error(t, err)
+	_, err = registry.Build(KindWebhook, "example.com/hook", "")
+	assert.Error(t, err)
+
+	notifier, err := registry.Build(KindTelegram, "-1001234567890", "")
+	require.NoError(t, err)
+	assert.IsType(t, &TelegramNotifier{}, notifier)
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	var gotChat int64
+	var gotText string
+	notifier, err := NewTelegramFactory(func(chatID int64, text string) {
+		gotChat, gotText = chatID, text
+	})("-100500", "")
+	require.NoError(t, err)
+
+	err = notifier.OnTaskCreated(context.Background(), TaskEvent{
+		ChatTitle: "Команда",
+		Actor:     "@alice",
+		Task:      Task{ID: "1", Title: "Починить вход", URL: "https://todoist.com/showTask?id=1"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(-100500), gotChat)
+	assert.Equal(t, "✅ Создана задача «Починить вход» в чате «Команда», @alice\nhttps://todoist.com/showTask?id=1", gotText)
+}
+
+func newTestHTTPClient() *httpclient.Client {
+	config := httpclient.DefaultConfig()
+	config.RetryWaitTime = time.Millisecond
+	return httpclient.NewClient(config)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var got map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "session_canceled", r.Header.Get(HeaderEvent))
+		assert.Empty(t, r.Header.Get(HeaderSignature))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookFactory(newTestHTTPClient())(server.URL, "")
+	require.NoError(t, err)
+
+	err = notifier.OnSessionClosed(context.Background(), SessionEvent{ChatID: 42, SessionID: 7, Reason: ReasonCanceled})
+	require.NoError(t, err)
+
+	assert.Equal(t, "session_canceled", got["type"])
+	assert.Equal(t, float64(SchemaVersion), got["version"])
+	assert.Equal(t, map[string]any{"id": float64(42)}, got["chat"])
+	assert.Equal(t, map[string]any{"id": float64(7), "close_reason": "canceled"}, got["session"])
+	assert.NotContains(t, got, "task")
+}
+
+func TestWebhookNotifier_SignedRetries(t *testing.T) {
+	var deliveries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.True(t, hmac.Equal([]byte(Signature("s3cret", r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderDelivery), body)), []byte(r.Header.Get(HeaderSignature))))
+		deliveries = append(deliveries, r.Header.Get(HeaderDelivery))
+		if len(deliveries) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookFactory(newTestHTTPClient())(server.URL, "s3cret")
+	require.NoError(t, err)
+
+	err = notifier.OnDraftCreated(context.Background(), DraftEvent{ChatID: 42, Draft: Draft{Title: "Починить вход"}})
+	require.NoError(t, err)
+
+	require.Len(t, deliveries, 3)
+	assert.NotEmpty(t, deliveries[0])
+	assert.Equal(t, deliveries[0], deliveries[2])
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier, err := NewWebhookFactory(newTestHTTPClient())(server.URL, "")
+	require.NoError(t, err)
+
+	err = notifier.OnTaskCompleted(context.Background(), TaskEvent{Task: Task{ID: "1"}})
+	assert.ErrorContains(t, err, "status 400")
+}
+
+func TestSignature(t *testing.T) {
+	// printf '1700000000.d-1.{"event":"ping"}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, "sha256=579ece21d40133f5f7834027360c913b0417ea2ae9d8ca69b856eab925549393",
+		Signature("secret", "1700000000", "d-1", []byte(`{"event":"ping"}`)))
+}
+
+func TestWebhookNotifier_PassesInboundVerification(t *testing.T) {
+	verifier := webhookauth.NewVerifier(webhookauth.Generic, "s3cret", webhookauth.DefaultTolerance)
+	var received []byte
+	server := httptest.NewServer(verifier.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	defer server.Close()
+
+	notifier, err := NewWebhookFactory(newTestHTTPClient())(server.URL, "s3cret")
+	require.NoError(t, err)
+
+	err = notifier.OnTaskCompleted(context.Background(), TaskEvent{ChatID: 42, Task: Task{ID: "1", Title: "Вход"}})
+	require.NoError(t, err)
+
+	envelope, err := ParseEnvelope(received)
+	require.NoError(t, err)
+	assert.Equal(t, EventTaskCompleted, envelope.Type)
+	assert.Equal(t, int64(42), envelope.Chat.ID)
+}
+
+func TestBuildEmail(t *testing.T) {
+	message := string(buildEmail("bot@example.com", "ops@example.com", "Создана задача: Вход", "строка 1\nстрока 2"))
+
+	assert.Contains(t, message, "Subject: =?utf-8?q?")
+	assert.Contains(t, message, "Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	assert.True(t, strings.HasSuffix(message, "строка 1\r\nстрока 2\r\n"))
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+// SchemaVersion is the version of the event envelope sent to integrations.
+// It changes only on breaking changes: new optional fields keep the version,
+// renames and removals bump it. The schema is described in EVENTS.md.
+const SchemaVersion = 1
+
+// Event types of the envelope
+const (
+	EventDraftCreated    = "draft_created"
+	EventTaskCreated     = "task_created"
+	EventTaskCompleted   = "task_completed"
+	EventSessionClosed   = "session_closed"
+	EventSessionCanceled = "session_canceled"
+)
+
+// Envelope is the JSON document of one event. Its types are separate from
+// the event types, so renaming a Go field never changes the wire format.
+type Envelope struct {
+	Version    int              `json:"version"`
+	ID         string           `json:"id"`
+	Type       string           `json:"type"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Chat       EnvelopeChat     `json:"chat"`
+	Session    *EnvelopeSession `json:"session,omitempty"`
+	Actor      *EnvelopeActor   `json:"actor,omitempty"`
+	Draft      *EnvelopeDraft   `json:"draft,omitempty"`
+	Task       *EnvelopeTask    `json:"task,omitempty"`
+}
+
+// EnvelopeChat is the Telegram chat the event happened in
+type EnvelopeChat struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// EnvelopeSession is the discussion session of the event
+type EnvelopeSession struct {
+	ID          int    `json:"id"`
+	CloseReason string `json:"close_reason,omitempty"`
+}
+
+// EnvelopeActor is the Telegram user who triggered the event
+type EnvelopeActor struct {
+	Name string `json:"name"`
+}
+
+// EnvelopeDraft is a task draft waiting for confirmation
+type EnvelopeDraft struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	DueDate     string `json:"due_date,omitempty"`
+	Priority    string `json:"priority,omitempty"`
+}
+
+// EnvelopeTask is a task in the tracker
+type EnvelopeTask struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URL   string `json:"url,omitempty"`
+}
+
+// DraftEnvelope returns the envelope of a draft_created event
+func DraftEnvelope(event DraftEvent) Envelope {
+	envelope := newEnvelope(EventDraftCreated, event.ChatID, event.ChatTitle, event.SessionID, event.Actor)
+	envelope.Draft = &EnvelopeDraft{
+		Title:       event.Draft.Title,
+		Description: event.Draft.Description,
+		DueDate:     event.Draft.DueDate,
+		Priority:    priority.FromTodoist(event.Draft.Priority).Key(),
+	}
+	return envelope
+}
+
+// TaskEnvelope returns the envelope of a task_created or task_completed event
+func TaskEnvelope(eventType string, event TaskEvent) Envelope {
+	envelope := newEnvelope(eventType, event.ChatID, event.ChatTitle, event.SessionID, event.Actor)
+	envelope.Task = &EnvelopeTask{ID: event.Task.ID, Title: event.Task.Title, URL: event.Task.URL}
+	return envelope
+}
+
+// SessionEnvelope returns the envelope of a closed session; canceled
+// discussions are reported as session_canceled
+func SessionEnvelope(event SessionEvent) Envelope {
+	eventType := EventSessionClosed
+	if event.Reason == ReasonCanceled {
+		eventType = EventSessionCanceled
+	}
+	envelope := newEnvelope(eventType, event.ChatID, event.ChatTitle, event.SessionID, event.Actor)
+	if envelope.Session != nil {
+		envelope.Session.CloseReason = event.Reason
+	}
+	return envelope
+}
+
+func newEnvelope(eventType string, chatID int64, chatTitle string, sessionID int, actor string) Envelope {
+	envelope := Envelope{
+		Version: SchemaVersion,
+		Type:    eventType,
+		Chat:    EnvelopeChat{ID: chatID, Title: chatTitle},
+	}
+	if sessionID != 0 {
+		envelope.Session = &EnvelopeSession{ID: sessionID}
+	}
+	if actor != "" {
+		envelope.Actor = &EnvelopeActor{Name: actor}
+	}
+	return envelope
+}
+
+// ParseEnvelope decodes an envelope of the supported version. Unknown fields
+// are ignored, so receivers keep working when optional fields are added.
+func ParseEnvelope(data []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return Envelope{}, fmt.Errorf("failed to decode event envelope: %w", err)
+	}
+	if envelope.Version != SchemaVersion {
+		return Envelope{}, fmt.Errorf("unsupported event envelope version %d, expected %d", envelope.Version, SchemaVersion)
+	}
+	if envelope.Type == "" || envelope.ID == "" {
+		return Envelope{}, fmt.Errorf("event envelope has no type or id")
+	}
+	return envelope, nil
+}
@@ -0,0 +1,106 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The expected documents are the published schema: a failing test here means
+// integrations would break, so change EVENTS.md and SchemaVersion instead.
+func TestEnvelopeSchema(t *testing.T) {
+	stamp := func(envelope Envelope) Envelope {
+		envelope.ID = "d1"
+		envelope.OccurredAt = time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+		return envelope
+	}
+
+	tests := []struct {
+		name     string
+		envelope Envelope
+		want     string
+	}{
+		{
+			name: "draft created",
+			envelope: DraftEnvelope(DraftEvent{
+				ChatID: -100500, ChatTitle: "Команда", SessionID: 7, Actor: "@alice",
+				Draft: Draft{Title: "Починить вход", Description: "Падает на iOS", DueDate: "2026-10-20", Priority: 3},
+			}),
+			want: `{
+				"version": 1, "id": "d1", "type": "draft_created", "occurred_at": "2026-10-15T09:30:00Z",
+				"chat": {"id": -100500, "title": "Команда"},
+				"session": {"id": 7},
+				"actor": {"name": "@alice"},
+				"draft": {"title": "Починить вход", "description": "Падает на iOS", "due_date": "2026-10-20", "priority": "high"}
+			}`,
+		},
+		{
+			name: "task created",
+			envelope: TaskEnvelope(EventTaskCreated, TaskEvent{
+				ChatID: -100500, SessionID: 7,
+				Task: Task{ID: "123", Title: "Починить вход", URL: "https://app.todoist.com/app/task/123"},
+			}),
+			want: `{
+				"version": 1, "id": "d1", "type": "task_created", "occurred_at": "2026-10-15T09:30:00Z",
+				"chat": {"id": -100500},
+				"session": {"id": 7},
+				"task": {"id": "123", "title": "Починить вход", "url": "https://app.todoist.com/app/task/123"}
+			}`,
+		},
+		{
+			name:     "task completed without session",
+			envelope: TaskEnvelope(EventTaskCompleted, TaskEvent{ChatID: 42, Task: Task{ID: "123", Title: "Вход"}}),
+			want: `{
+				"version": 1, "id": "d1", "type": "task_completed", "occurred_at": "2026-10-15T09:30:00Z",
+				"chat": {"id": 42},
+				"task": {"id": "123", "title": "Вход"}
+			}`,
+		},
+		{
+			name:     "session closed by task",
+			envelope: SessionEnvelope(SessionEvent{ChatID: 42, SessionID: 7, Reason: ReasonTaskCreated}),
+			want: `{
+				"version": 1, "id": "d1", "type": "session_closed", "occurred_at": "2026-10-15T09:30:00Z",
+				"chat": {"id": 42},
+				"session": {"id": 7, "close_reason": "task_created"}
+			}`,
+		},
+		{
+			name:     "session canceled",
+			envelope: SessionEnvelope(SessionEvent{ChatID: 42, SessionID: 7, Actor: "Алиса", Reason: ReasonCanceled}),
+			want: `{
+				"version": 1, "id": "d1", "type": "session_canceled", "occurred_at": "2026-10-15T09:30:00Z",
+				"chat": {"id": 42},
+				"session": {"id": 7, "close_reason": "canceled"},
+				"actor": {"name": "Алиса"}
+			}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(stamp(tt.envelope))
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	envelope, err := ParseEnvelope([]byte(`{
+		"version": 1, "id": "d1", "type": "task_created", "occurred_at": "2026-10-15T09:30:00Z",
+		"chat": {"id": 42}, "task": {"id": "123", "title": "Вход", "labels": ["new-field"]}
+	}`))
+	require.NoError(t, err)
+	assert.Equal(t, EventTaskCreated, envelope.Type)
+	assert.Equal(t, "123", envelope.Task.ID)
+
+	_, err = ParseEnvelope([]byte(`{"version": 2, "id": "d1", "type": "task_created"}`))
+	assert.ErrorContains(t, err, "unsupported event envelope version 2")
+
+	_, err = ParseEnvelope([]byte(`{"version": 1, "id": "d1"}`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TelegramNotifier posts events to another Telegram chat, e.g. an ops channel
+type TelegramNotifier struct {
+	chatID int64
+	send   func(chatID int64, text string)
+}
+
+// NewTelegramFactory builds notifiers for chat ID targets that send through send
+func NewTelegramFactory(send func(chatID int64, text string)) Factory {
+	return func(target, _ string) (Notifier, error) {
+		chatID, err := strconv.ParseInt(strings.TrimSpace(target), 10, 64)
+		if err != nil || chatID == 0 {
+			return nil, fmt.Errorf("telegram target must be a chat ID, got %q", target)
+		}
+		return &TelegramNotifier{chatID: chatID, send: send}, nil
+	}
+}
+
+func (n *TelegramNotifier) OnDraftCreated(ctx context.Context, event DraftEvent) error {
+	n.send(n.chatID, "📝 "+event.Describe())
+	return nil
+}
+
+func (n *TelegramNotifier) OnTaskCreated(ctx context.Context, event TaskEvent) error {
+	n.send(n.chatID, "✅ "+event.Describe(false))
+	return nil
+}
+
+func (n *TelegramNotifier) OnTaskCompleted(ctx context.Context, event TaskEvent) error {
+	n.send(n.chatID, "☑️ "+event.Describe(true))
+	return nil
+}
+
+func (n *TelegramNotifier) OnSessionClosed(ctx context.Context, event SessionEvent) error {
+	n.send(n.chatID, "🏁 "+event.Describe())
+	return nil
+}
@@ -0,0 +1,119 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+	"github.com/user/telegram-bot/internal/webhookauth"
+)
+
+// Headers of webhook requests; all but the event follow webhookauth.Generic,
+// so the bot's own inbound endpoint accepts its webhooks
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = webhookauth.GenericDeliveryHeader
+	HeaderSignature = webhookauth.GenericSignatureHeader
+	HeaderTimestamp = webhookauth.GenericTimestampHeader
+)
+
+// WebhookNotifier POSTs events as JSON envelopes to a URL. Requests are retried with
+// backoff by the HTTP client and signed with HMAC-SHA256 when a secret is set.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *httpclient.Client
+}
+
+// NewWebhookFactory builds notifiers for http(s) URL targets
+func NewWebhookFactory(client *httpclient.Client) Factory {
+	return func(target, secret string) (Notifier, error) {
+		target = strings.TrimSpace(target)
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("webhook target must be an http(s) URL, got %q", target)
+		}
+		return &WebhookNotifier{url: target, secret: secret, client: client}, nil
+	}
+}
+
+// Signature returns the value of the signature header for the body of a
+// delivery sent at timestamp (Unix seconds), so receivers can verify a request
+// by comparing it with hmac.Equal
+func Signature(secret, timestamp, delivery string, body []byte) string {
+	return webhookauth.SignGeneric(secret, timestamp, delivery, body)
+}
+
+func (n *WebhookNotifier) OnDraftCreated(ctx context.Context, event DraftEvent) error {
+	return n.post(ctx, DraftEnvelope(event))
+}
+
+func (n *WebhookNotifier) OnTaskCreated(ctx context.Context, event TaskEvent) error {
+	return n.post(ctx, TaskEnvelope(EventTaskCreated, event))
+}
+
+func (n *WebhookNotifier) OnTaskCompleted(ctx context.Context, event TaskEvent) error {
+	return n.post(ctx, TaskEnvelope(EventTaskCompleted, event))
+}
+
+func (n *WebhookNotifier) OnSessionClosed(ctx context.Context, event SessionEvent) error {
+	return n.post(ctx, SessionEnvelope(event))
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, envelope Envelope) error {
+	delivery, err := newDeliveryID()
+	if err != nil {
+		return err
+	}
+	envelope.ID = delivery
+	envelope.OccurredAt = time.Now().UTC().Truncate(time.Second)
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, envelope.Type)
+	// Retries keep the envelope ID, so receivers can drop duplicates
+	req.Header.Set(HeaderDelivery, envelope.ID)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if n.secret != "" {
+		req.Header.Set(HeaderSignature, Signature(n.secret, timestamp, delivery, body))
+	}
+
+	resp, err := n.client.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func newDeliveryID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate delivery id: %w", err)
+	}
+	return hex.EncodeToString(b[:]), nil
+}
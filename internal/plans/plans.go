@@ -0,0 +1,205 @@
+// Package plans gates paid features per chat. Self-hosted deployments use
+// Unlimited and never touch plan storage.
+package plans
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	EnvBillingEnabled          = "BILLING_ENABLED"
+	EnvFreeAIEditsPerMonth     = "PLAN_FREE_AI_EDITS_PER_MONTH"
+	EnvUpgradeURL              = "BILLING_UPGRADE_URL"
+	defaultFreeAIEditsPerMonth = 20
+)
+
+type Plan string
+
+const (
+	PlanFree Plan = "free"
+	PlanPro  Plan = "pro"
+)
+
+// Feature is a metered capability. Only AI edits are gated for now: the
+// tracker of a chat is chosen by the operator in configs/api.yaml, not by the
+// chat, and digests come once a day with no frequency to limit. Gates on the
+// number of trackers and the digest frequency are a follow-up, see
+// DECISION_LOG.md.
+type Feature string
+
+const FeatureAIEdit Feature = "ai_edit"
+
+// Limits holds monthly allowances per feature. Features missing from the map are unlimited.
+type Limits map[Feature]int
+
+// Catalog maps every plan to its limits.
+type Catalog map[Plan]Limits
+
+// Decision is the outcome of a feature check.
+type Decision struct {
+	Allowed bool
+	Plan    Plan
+	Feature Feature
+	Used    int
+	Limit   int
+	// UpgradeURL is where a denied chat can upgrade its plan, if configured.
+	UpgradeURL string
+}
+
+// FeatureUsage is the monthly usage of a feature against its limit (0 means unlimited).
+type FeatureUsage struct {
+	Feature Feature
+	Used    int
+	Limit   int
+}
+
+// Status describes a chat's plan and its usage in the current month.
+type Status struct {
+	Plan  Plan
+	Usage []FeatureUsage
+}
+
+// Gate decides whether a chat may use a feature.
+type Gate interface {
+	// Consume counts one use of the feature if the chat's plan allows it.
+	Consume(ctx context.Context, chatID int64, feature Feature) (Decision, error)
+}
+
+// Unlimited allows everything and stores nothing.
+type Unlimited struct{}
+
+func (Unlimited) Consume(ctx context.Context, chatID int64, feature Feature) (Decision, error) {
+	return Decision{Allowed: true, Feature: feature}, nil
+}
+
+// Store persists chat plans and monthly feature usage.
+type Store interface {
+	GetChatPlan(ctx context.Context, chatID int64) (string, error)
+	SetChatPlan(ctx context.Context, chatID int64, plan string) error
+	GetFeatureUsage(ctx context.Context, chatID int64, feature, period string) (int, error)
+	IncrementFeatureUsage(ctx context.Context, chatID int64, feature, period string) error
+}
+
+// Metered enforces plan limits using usage counters kept in a Store.
+type Metered struct {
+	store      Store
+	catalog    Catalog
+	upgradeURL string
+	now        func() time.Time
+}
+
+func NewMetered(store Store, catalog Catalog, upgradeURL string) *Metered {
+	return &Metered{
+		store:      store,
+		catalog:    catalog,
+		upgradeURL: upgradeURL,
+		now:        time.Now,
+	}
+}
+
+// DefaultCatalog returns the free and pro plans, reading the free allowance
+// from PLAN_FREE_AI_EDITS_PER_MONTH.
+func DefaultCatalog() (Catalog, error) {
+	freeEdits := defaultFreeAIEditsPerMonth
+	if raw := os.Getenv(EnvFreeAIEditsPerMonth); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer, got %q", EnvFreeAIEditsPerMonth, raw)
+		}
+		freeEdits = value
+	}
+	return Catalog{
+		PlanFree: {FeatureAIEdit: freeEdits},
+		PlanPro:  {},
+	}, nil
+}
+
+// Enabled reports whether BILLING_ENABLED turns plan gating on.
+func Enabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(EnvBillingEnabled))
+	return enabled
+}
+
+// ParsePlan validates a plan name against the catalog.
+func (m *Metered) ParsePlan(name string) (Plan, bool) {
+	plan := Plan(name)
+	_, ok := m.catalog[plan]
+	return plan, ok
+}
+
+func (m *Metered) Consume(ctx context.Context, chatID int64, feature Feature) (Decision, error) {
+	plan, err := m.planFor(ctx, chatID)
+	if err != nil {
+		return Decision{}, err
+	}
+
+	decision := Decision{Allowed: true, Plan: plan, Feature: feature}
+	limit, limited := m.catalog[plan][feature]
+	period := m.period()
+	if limited {
+		used, err := m.store.GetFeatureUsage(ctx, chatID, string(feature), period)
+		if err != nil {
+			return Decision{}, err
+		}
+		decision.Used = used
+		decision.Limit = limit
+		if used >= limit {
+			decision.Allowed = false
+			decision.UpgradeURL = m.upgradeURL
+			return decision, nil
+		}
+	}
+
+	if err := m.store.IncrementFeatureUsage(ctx, chatID, string(feature), period); err != nil {
+		return Decision{}, err
+	}
+	decision.Used++
+	return decision, nil
+}
+
+// Status returns the chat's plan and usage of every feature it meters.
+func (m *Metered) Status(ctx context.Context, chatID int64) (Status, error) {
+	plan, err := m.planFor(ctx, chatID)
+	if err != nil {
+		return Status{}, err
+	}
+
+	status := Status{Plan: plan}
+	period := m.period()
+	for _, feature := range []Feature{FeatureAIEdit} {
+		used, err := m.store.GetFeatureUsage(ctx, chatID, string(feature), period)
+		if err != nil {
+			return Status{}, err
+		}
+		status.Usage = append(status.Usage, FeatureUsage{Feature: feature, Used: used, Limit: m.catalog[plan][feature]})
+	}
+	return status, nil
+}
+
+// SetPlan switches the chat to another plan.
+func (m *Metered) SetPlan(ctx context.Context, chatID int64, plan Plan) error {
+	if _, ok := m.catalog[plan]; !ok {
+		return fmt.Errorf("unknown plan %q", plan)
+	}
+	return m.store.SetChatPlan(ctx, chatID, string(plan))
+}
+
+func (m *Metered) planFor(ctx context.Context, chatID int64) (Plan, error) {
+	name, err := m.store.GetChatPlan(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+	if plan, ok := m.ParsePlan(name); ok {
+		return plan, nil
+	}
+	return PlanFree, nil
+}
+
+// period is the billing month in UTC, e.g. "2026-03".
+func (m *Metered) period() string {
+	return m.now().UTC().Format("2006-01")
+}
@@ -0,0 +1,87 @@
+package plans
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type memoryStore struct {
+	plans map[int64]string
+	usage map[string]int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{plans: map[int64]string{}, usage: map[string]int{}}
+}
+
+func (s *memoryStore) GetChatPlan(ctx context.Context, chatID int64) (string, error) {
+	return s.plans[chatID], nil
+}
+
+func (s *memoryStore) SetChatPlan(ctx context.Context, chatID int64, plan string) error {
+	s.plans[chatID] = plan
+	return nil
+}
+
+func (s *memoryStore) key(chatID int64, feature, period string) string {
+	return fmt.Sprintf("%d/%s/%s", chatID, feature, period)
+}
+
+func (s *memoryStore) GetFeatureUsage(ctx context.Context, chatID int64, feature, period string) (int, error) {
+	return s.usage[s.key(chatID, feature, period)], nil
+}
+
+func (s *memoryStore) IncrementFeatureUsage(ctx context.Context, chatID int64, feature, period string) error {
+	s.usage[s.key(chatID, feature, period)]++
+	return nil
+}
+
+func TestMetered_ConsumeEnforcesMonthlyLimit(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	gate := NewMetered(store, Catalog{PlanFree: {FeatureAIEdit: 2}, PlanPro: {}}, "")
+	gate.now = func() time.Time { return time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC) }
+
+	for i := 0; i < 2; i++ {
+		if decision, err := gate.Consume(ctx, 1, FeatureAIEdit); err != nil || !decision.Allowed {
+			t.Fatalf("edit %d should be allowed: %+v, %v", i+1, decision, err)
+		}
+	}
+	decision, err := gate.Consume(ctx, 1, FeatureAIEdit)
+	if err != nil || decision.Allowed || decision.Plan != PlanFree || decision.Limit != 2 {
+		t.Fatalf("third edit should be denied on free plan: %+v, %v", decision, err)
+	}
+
+	// A new month resets the counter
+	gate.now = func() time.Time { return time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC) }
+	if decision, _ := gate.Consume(ctx, 1, FeatureAIEdit); !decision.Allowed {
+		t.Fatal("usage should reset in a new month")
+	}
+}
+
+func TestMetered_ProPlanIsUnlimited(t *testing.T) {
+	ctx := context.Background()
+	gate := NewMetered(newMemoryStore(), Catalog{PlanFree: {FeatureAIEdit: 0}, PlanPro: {}}, "")
+
+	if decision, _ := gate.Consume(ctx, 1, FeatureAIEdit); decision.Allowed {
+		t.Fatal("free plan with zero allowance should deny")
+	}
+	if err := gate.SetPlan(ctx, 1, PlanPro); err != nil {
+		t.Fatalf("set plan: %v", err)
+	}
+	if decision, _ := gate.Consume(ctx, 1, FeatureAIEdit); !decision.Allowed || decision.Plan != PlanPro {
+		t.Fatalf("pro plan should allow edits: %+v", decision)
+	}
+	if err := gate.SetPlan(ctx, 1, Plan("enterprise")); err == nil {
+		t.Fatal("unknown plan should be rejected")
+	}
+}
+
+func TestUnlimited_AllowsEverything(t *testing.T) {
+	decision, err := Unlimited{}.Consume(context.Background(), 1, FeatureAIEdit)
+	if err != nil || !decision.Allowed {
+		t.Fatalf("unlimited gate should allow: %+v, %v", decision, err)
+	}
+}
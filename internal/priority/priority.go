@@ -0,0 +1,189 @@
+// Package priority is the backend-independent task priority. Each tracker
+// encodes priority its own way (Todoist 1–4 with 4 urgent, Jira names,
+// Linear 0–4 with 1 urgent); converters map them to one Level, and chats
+// choose the names the Level is displayed with.
+package priority
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is a task priority from LevelLow to LevelUrgent; LevelNone means unset
+type Level int
+
+const (
+	LevelNone Level = iota
+	LevelLow
+	LevelMedium
+	LevelHigh
+	LevelUrgent
+)
+
+// Levels lists the set levels from the lowest
+var Levels = []Level{LevelLow, LevelMedium, LevelHigh, LevelUrgent}
+
+// keys are the stable names used in settings, e.g. "high=Мажор"
+var keys = map[Level]string{
+	LevelLow:    "low",
+	LevelMedium: "medium",
+	LevelHigh:   "high",
+	LevelUrgent: "urgent",
+}
+
+// Key returns the stable name of l for settings
+func (l Level) Key() string {
+	return keys[l]
+}
+
+// Valid reports whether l is a set level
+func (l Level) Valid() bool {
+	return l >= LevelLow && l <= LevelUrgent
+}
+
+// FromTodoist converts a Todoist API priority, where 4 is urgent
+func FromTodoist(p int) Level {
+	if p < 1 || p > 4 {
+		return LevelNone
+	}
+	return Level(p)
+}
+
+// Todoist returns the Todoist API priority; unset maps to Todoist's default 1
+func (l Level) Todoist() int {
+	if !l.Valid() {
+		return 1
+	}
+	return int(l)
+}
+
+// FromJira converts a Jira priority name, including the Highest/Lowest ends of the default scheme
+func FromJira(name string) Level {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "lowest", "low", "trivial", "minor":
+		return LevelLow
+	case "medium", "normal":
+		return LevelMedium
+	case "high", "major":
+		return LevelHigh
+	case "highest", "critical", "blocker":
+		return LevelUrgent
+	}
+	return LevelNone
+}
+
+// Jira returns the name of l in Jira's default priority scheme
+func (l Level) Jira() string {
+	switch l {
+	case LevelLow:
+		return "Low"
+	case LevelMedium:
+		return "Medium"
+	case LevelHigh:
+		return "High"
+	case LevelUrgent:
+		return "Highest"
+	}
+	return ""
+}
+
+// FromLinear converts a Linear priority: 0 none, 1 urgent, 2 high, 3 medium, 4 low
+func FromLinear(p int) Level {
+	if p < 1 || p > 4 {
+		return LevelNone
+	}
+	return Level(5 - p)
+}
+
+// Linear returns the Linear priority of l
+func (l Level) Linear() int {
+	if !l.Valid() {
+		return 0
+	}
+	return 5 - int(l)
+}
+
+// Parse reads a priority the way people and models write it: a Todoist
+// number or an English or Russian name. An empty string is LevelNone.
+func Parse(text string) (Level, error) {
+	trimmed := strings.ToLower(strings.TrimSpace(text))
+	switch trimmed {
+	case "":
+		return LevelNone, nil
+	case "1", "2", "3", "4":
+		return FromTodoist(int(trimmed[0] - '0')), nil
+	case "low", "низкий", "normal", "обычный":
+		return LevelLow, nil
+	case "medium", "mid", "средний":
+		return LevelMedium, nil
+	case "high", "высокий":
+		return LevelHigh, nil
+	case "urgent", "critical", "срочный", "критичный":
+		return LevelUrgent, nil
+	}
+	return LevelNone, fmt.Errorf("unsupported priority value %q", text)
+}
+
+// Names are the display names of levels in a chat
+type Names map[Level]string
+
+// DefaultNames are used for levels a chat did not rename
+var DefaultNames = Names{
+	LevelLow:    "Низкий",
+	LevelMedium: "Средний",
+	LevelHigh:   "Высокий",
+	LevelUrgent: "Срочный",
+}
+
+// Display returns the name of l, falling back to DefaultNames
+func (n Names) Display(l Level) string {
+	if name, ok := n[l]; ok && name != "" {
+		return name
+	}
+	return DefaultNames[l]
+}
+
+// ParseNames reads overrides like "high=Мажор, urgent=Блокер"; an empty string means no overrides
+func ParseNames(text string) (Names, error) {
+	names := Names{}
+	for _, item := range strings.Split(text, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, name, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("priority name %q must look like level=name", item)
+		}
+		level, found := levelByKey(strings.ToLower(strings.TrimSpace(key)))
+		if !found {
+			return nil, fmt.Errorf("unknown priority level %q", key)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, ",=") {
+			return nil, fmt.Errorf("invalid name %q for priority %s", name, key)
+		}
+		names[level] = name
+	}
+	return names, nil
+}
+
+// String formats the overrides for storage, the inverse of ParseNames
+func (n Names) String() string {
+	items := make([]string, 0, len(n))
+	for _, level := range Levels {
+		if name := n[level]; name != "" {
+			items = append(items, level.Key()+"="+name)
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+func levelByKey(key string) (Level, bool) {
+	for level, k := range keys {
+		if k == key {
+			return level, true
+		}
+	}
+	return LevelNone, false
+}
@@ -0,0 +1,89 @@
+package priority
+
+import "testing"
+
+func TestBackendConverters_AgreeOnLevels(t *testing.T) {
+	tests := []struct {
+		level   Level
+		todoist int
+		jira    string
+		linear  int
+	}{
+		{LevelLow, 1, "Low", 4},
+		{LevelMedium, 2, "Medium", 3},
+		{LevelHigh, 3, "High", 2},
+		{LevelUrgent, 4, "Highest", 1},
+	}
+
+	for _, tt := range tests {
+		if got := FromTodoist(tt.todoist); got != tt.level {
+			t.Errorf("FromTodoist(%d) = %v, want %v", tt.todoist, got, tt.level)
+		}
+		if got := tt.level.Todoist(); got != tt.todoist {
+			t.Errorf("%v.Todoist() = %d, want %d", tt.level, got, tt.todoist)
+		}
+		if got := FromJira(tt.jira); got != tt.level {
+			t.Errorf("FromJira(%q) = %v, want %v", tt.jira, got, tt.level)
+		}
+		if got := tt.level.Jira(); got != tt.jira {
+			t.Errorf("%v.Jira() = %q, want %q", tt.level, got, tt.jira)
+		}
+		if got := FromLinear(tt.linear); got != tt.level {
+			t.Errorf("FromLinear(%d) = %v, want %v", tt.linear, got, tt.level)
+		}
+		if got := tt.level.Linear(); got != tt.linear {
+			t.Errorf("%v.Linear() = %d, want %d", tt.level, got, tt.linear)
+		}
+	}
+
+	if FromLinear(0) != LevelNone || LevelNone.Linear() != 0 {
+		t.Error("expected Linear's 0 to mean no priority")
+	}
+	if FromJira("Lowest") != LevelLow || FromJira("Blocker") != LevelUrgent {
+		t.Error("expected the ends of Jira schemes to map to the nearest level")
+	}
+	if LevelNone.Todoist() != 1 {
+		t.Error("expected unset priority to use Todoist's default")
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := map[string]Level{
+		"":         LevelNone,
+		"3":        LevelHigh,
+		"Высокий":  LevelHigh,
+		" urgent ": LevelUrgent,
+		"обычный":  LevelLow,
+	}
+	for input, want := range tests {
+		got, err := Parse(input)
+		if err != nil || got != want {
+			t.Errorf("Parse(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := Parse("asap"); err == nil {
+		t.Error("expected an error for an unknown priority")
+	}
+}
+
+func TestNames(t *testing.T) {
+	names, err := ParseNames(" urgent=Блокер, high = Мажор ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := names.Display(LevelHigh); got != "Мажор" {
+		t.Errorf("expected override, got %q", got)
+	}
+	if got := names.Display(LevelLow); got != "Низкий" {
+		t.Errorf("expected default name, got %q", got)
+	}
+	if got := names.String(); got != "high=Мажор,urgent=Блокер" {
+		t.Errorf("unexpected stored form %q", got)
+	}
+
+	for _, input := range []string{"high", "p1=Срочно", "low="} {
+		if _, err := ParseNames(input); err == nil {
+			t.Errorf("expected an error for %q", input)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Package quickedit recognizes simple field edits of a task draft ("срок
+// пятница", "приоритет высокий") so they can be applied without an AI call.
+// Anything it does not fully understand is left to the AI edit prompt.
+package quickedit
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/ai"
+	"github.com/user/telegram-bot/internal/dates"
+	"github.com/user/telegram-bot/internal/priority"
+	"github.com/user/telegram-bot/internal/taskfields"
+)
+
+// Edit is a set of field changes parsed from a user reply. Nil fields are not changed.
+type Edit struct {
+	DueDate   *string
+	Priority  *int
+	Title     *string
+	AddLabels []string
+}
+
+var (
+	dueRe      = regexp.MustCompile(`^(?:(?:поставь|установи|измени|поменяй|перенеси)\s+)?(?:срок|дедлайн|due(?:\s+date)?|deadline)(?:\s+(?:на|до|to|by))?\s*:?\s+(.+)$`)
+	setDueRe   = regexp.MustCompile(`^set\s+(?:the\s+)?(?:due(?:\s+date)?|deadline)\s+(?:to\s+)?(.+)$`)
+	noDueRe    = regexp.MustCompile(`^(?:без\s+срока|убери\s+срок|удали\s+срок|no\s+due(?:\s+date)?|remove\s+(?:the\s+)?due(?:\s+date)?)$`)
+	priorityRe = regexp.MustCompile(`^(?:(?:поставь|установи|измени|поменяй|set)\s+)?(?:приоритет|priority)(?:\s+(?:на|to))?\s*:?\s+(.+)$`)
+	titleRe    = regexp.MustCompile(`(?i)^(?:(?:переименуй|измени|поменяй)\s+)?(?:название|заголовок|title)(?:\s+(?:на|to))?\s*:?\s+(.+)$`)
+	renameRe   = regexp.MustCompile(`(?i)^(?:переименуй\s+в|rename\s+to)\s+(.+)$`)
+	labelRe    = regexp.MustCompile(`^(?:(?:добавь|add)\s+)?(?:метк[уи]|тег(?:и)?|labels?|tags?)\s*:?\s+(.+)$`)
+)
+
+var priorityWords = map[string]int{
+	"низкий": 1, "low": 1, "p4": 1,
+	"средний": 2, "обычный": 2, "medium": 2, "normal": 2, "p3": 2,
+	"высокий": 3, "high": 3, "p2": 3,
+	"срочный": 4, "критичный": 4, "urgent": 4, "p1": 4,
+}
+
+// Parse recognizes a reply where every line is a simple field edit. It
+// returns false when any line needs the AI, so partial edits never happen.
+func Parse(text string, now time.Time) (Edit, bool) {
+	var edit Edit
+	matched := false
+	for _, line := range strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' }) {
+		line = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line), "."))
+		if line == "" {
+			continue
+		}
+		if !parseLine(line, now, &edit) {
+			return Edit{}, false
+		}
+		matched = true
+	}
+	return edit, matched
+}
+
+func parseLine(line string, now time.Time, edit *Edit) bool {
+	lower := strings.ToLower(line)
+
+	if noDueRe.MatchString(lower) {
+		empty := ""
+		edit.DueDate = &empty
+		return true
+	}
+	for _, re := range []*regexp.Regexp{setDueRe, dueRe} {
+		if m := re.FindStringSubmatch(lower); m != nil {
+			due, ok := parseDate(m[1], now)
+			if !ok {
+				return false
+			}
+			edit.DueDate = &due
+			return true
+		}
+	}
+
+	if m := priorityRe.FindStringSubmatch(lower); m != nil {
+		value := strings.TrimSpace(m[1])
+		priority, ok := priorityWords[value]
+		if !ok {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 || n > 4 {
+				return false
+			}
+			priority = n
+		}
+		edit.Priority = &priority
+		return true
+	}
+
+	// Titles keep the user's casing, so they are matched case-insensitively on the original line
+	for _, re := range []*regexp.Regexp{titleRe, renameRe} {
+		if m := re.FindStringSubmatch(line); m != nil {
+			title := strings.Trim(strings.TrimSpace(m[1]), `"«»`)
+			if title == "" {
+				return false
+			}
+			edit.Title = &title
+			return true
+		}
+	}
+
+	if m := labelRe.FindStringSubmatch(lower); m != nil {
+		for _, label := range strings.Split(m[1], ",") {
+			label = strings.TrimPrefix(strings.TrimSpace(label), "#")
+			if label == "" || strings.ContainsAny(label, " \t") {
+				return false
+			}
+			edit.AddLabels = append(edit.AddLabels, label)
+		}
+		return len(edit.AddLabels) > 0
+	}
+
+	return false
+}
+
+// parseDate resolves relative and absolute dates to YYYY-MM-DD in now's
+// location, or RFC 3339 when a time is given
+func parseDate(value string, now time.Time) (string, bool) {
+	due, ok := dates.Parse(value, now)
+	if !ok {
+		return "", false
+	}
+	return due.String(), true
+}
+
+// Apply changes the task in place
+func (e Edit) Apply(task *ai.AnalyzedTask) {
+	if e.DueDate != nil {
+		task.DueDate = *e.DueDate
+		if task.DueDate != "" {
+			task.MissingDetails = removeDetail(task.MissingDetails, taskfields.LowerLabelForKey(taskfields.DueDate))
+		}
+	}
+	if e.Priority != nil {
+		task.Priority = *e.Priority
+		task.PriorityText = priority.DefaultNames.Display(priority.FromTodoist(*e.Priority))
+	}
+	if e.Title != nil {
+		task.Title = *e.Title
+	}
+	for _, label := range e.AddLabels {
+		if !containsFold(task.Labels, label) {
+			task.Labels = append(task.Labels, label)
+		}
+	}
+}
+
+func removeDetail(details []string, label string) []string {
+	if label == "" {
+		return details
+	}
+	result := make([]string, 0, len(details))
+	for _, detail := range details {
+		if !strings.EqualFold(strings.TrimSpace(detail), label) {
+			result = append(result, detail)
+		}
+	}
+	return result
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Due shortcuts of the draft preview buttons
+const (
+	DueToday    = "today"
+	DueTomorrow = "tomorrow"
+	DueNextWeek = "next_week"
+	DueClear    = "clear"
+)
+
+// DueShortcut resolves a due button to YYYY-MM-DD in now's location; next week
+// is the next Monday and clearing gives an empty date
+func DueShortcut(shortcut string, now time.Time) (string, bool) {
+	switch shortcut {
+	case DueToday:
+		return now.Format("2006-01-02"), true
+	case DueTomorrow:
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), true
+	case DueNextWeek:
+		days := (int(time.Monday) - int(now.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return now.AddDate(0, 0, days).Format("2006-01-02"), true
+	case DueClear:
+		return "", true
+	}
+	return "", false
+}
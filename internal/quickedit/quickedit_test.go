@@ -0,0 +1,119 @@
+package quickedit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/telegram-bot/internal/ai"
+)
+
+// Thursday, 15 October 2026
+var now = time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+func TestParse_RecognizesSimpleEdits(t *testing.T) {
+	cases := []struct {
+		text     string
+		due      string
+		priority int
+		title    string
+	}{
+		{text: "срок пятница", due: "2026-10-16"},
+		{text: "Срок до пятницы", due: "2026-10-16"},
+		{text: "set due to Friday", due: "2026-10-16"},
+		{text: "дедлайн завтра", due: "2026-10-16"},
+		{text: "срок четверг", due: "2026-10-22"},
+		{text: "срок 2026-11-01", due: "2026-11-01"},
+		{text: "срок: 01.02", due: "2027-02-01"},
+		{text: "без срока", due: ""},
+		{text: "срок через 3 дня", due: "2026-10-18"},
+		{text: "дедлайн 31 декабря", due: "2026-12-31"},
+		{text: "срок пятница вечером", due: "2026-10-16T18:00:00Z"},
+		{text: "приоритет высокий", priority: 3},
+		{text: "priority p1", priority: 4},
+		{text: "приоритет 2", priority: 2},
+		{text: "Название: Починить логин в Safari", title: "Починить логин в Safari"},
+		{text: "rename to Fix login", title: "Fix login"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.text, func(t *testing.T) {
+			edit, ok := Parse(tc.text, now)
+			if !ok {
+				t.Fatalf("expected %q to be a quick edit", tc.text)
+			}
+			if tc.priority != 0 && (edit.Priority == nil || *edit.Priority != tc.priority) {
+				t.Errorf("priority = %v, want %d", edit.Priority, tc.priority)
+			}
+			if tc.title != "" && (edit.Title == nil || *edit.Title != tc.title) {
+				t.Errorf("title = %v, want %q", edit.Title, tc.title)
+			}
+			if tc.priority == 0 && tc.title == "" && (edit.DueDate == nil || *edit.DueDate != tc.due) {
+				t.Errorf("due = %v, want %q", edit.DueDate, tc.due)
+			}
+		})
+	}
+}
+
+func TestParse_LeavesComplexEditsToAI(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"перепиши описание подробнее",
+		"срок через полторы недели",
+		"приоритет повыше и добавь критерии готовности",
+		"срок пятница\nи распиши шаги воспроизведения",
+		"срок 31.02",
+	} {
+		if _, ok := Parse(text, now); ok {
+			t.Errorf("expected %q to need the AI", text)
+		}
+	}
+}
+
+func TestEdit_Apply(t *testing.T) {
+	edit, ok := Parse("срок пятница; приоритет срочный; метки: backend, #auth", now)
+	if !ok {
+		t.Fatal("expected combined quick edit")
+	}
+
+	task := &ai.AnalyzedTask{
+		Title:          "Починить логин",
+		Priority:       1,
+		Labels:         []string{"backend"},
+		MissingDetails: []string{"срок", "риски"},
+	}
+	edit.Apply(task)
+
+	if task.DueDate != "2026-10-16" {
+		t.Errorf("due = %q", task.DueDate)
+	}
+	if task.Priority != 4 || task.PriorityText != "Срочный" {
+		t.Errorf("priority = %d %q", task.Priority, task.PriorityText)
+	}
+	if len(task.Labels) != 2 || task.Labels[1] != "auth" {
+		t.Errorf("labels = %v", task.Labels)
+	}
+	if len(task.MissingDetails) != 1 || task.MissingDetails[0] != "риски" {
+		t.Errorf("missing details = %v", task.MissingDetails)
+	}
+	if task.Title != "Починить логин" {
+		t.Errorf("title changed: %q", task.Title)
+	}
+}
+
+func TestDueShortcut(t *testing.T) {
+	cases := map[string]string{
+		DueToday:    "2026-10-15",
+		DueTomorrow: "2026-10-16",
+		DueNextWeek: "2026-10-19",
+		DueClear:    "",
+	}
+	for shortcut, want := range cases {
+		due, ok := DueShortcut(shortcut, now)
+		if !ok || due != want {
+			t.Errorf("DueShortcut(%q) = %q, %v, want %q", shortcut, due, ok, want)
+		}
+	}
+	if _, ok := DueShortcut("someday", now); ok {
+		t.Error("expected an unknown shortcut to be rejected")
+	}
+}
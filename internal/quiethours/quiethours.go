@@ -0,0 +1,86 @@
+// Package quiethours describes the daily period when a chat does not want
+// non-urgent bot messages.
+package quiethours
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var clockRe = regexp.MustCompile(`^\s*(\d{1,2})(?::(\d{2}))?\s*$`)
+
+var windowRe = regexp.MustCompile(`^\s*(\d{1,2})(?::(\d{2}))?\s*[-–—]\s*(\d{1,2})(?::(\d{2}))?\s*$`)
+
+// Window is a daily period in minutes since midnight. Start may be after End,
+// then the window spans midnight (22:00-08:00).
+type Window struct {
+	Start int
+	End   int
+}
+
+// Parse reads a window like "22:00-08:00" or "22-8"
+func Parse(value string) (Window, error) {
+	m := windowRe.FindStringSubmatch(value)
+	if m == nil {
+		return Window{}, fmt.Errorf("invalid quiet hours %q: expected HH:MM-HH:MM", value)
+	}
+	start, err := minutes(m[1], m[2])
+	if err != nil {
+		return Window{}, err
+	}
+	end, err := minutes(m[3], m[4])
+	if err != nil {
+		return Window{}, err
+	}
+	if start == end {
+		return Window{}, fmt.Errorf("invalid quiet hours %q: start and end are equal", value)
+	}
+	return Window{Start: start, End: end}, nil
+}
+
+func minutes(hours, mins string) (int, error) {
+	h, _ := strconv.Atoi(hours)
+	m := 0
+	if mins != "" {
+		m, _ = strconv.Atoi(mins)
+	}
+	if h > 23 || m > 59 {
+		return 0, fmt.Errorf("invalid time %s:%02d", hours, m)
+	}
+	return h*60 + m, nil
+}
+
+// String formats the window as HH:MM-HH:MM
+func (w Window) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// Contains reports whether t falls into the window in t's location, so
+// callers pass the time in the chat's zone
+func (w Window) Contains(t time.Time) bool {
+	return w.ContainsMinute(t.Hour()*60 + t.Minute())
+}
+
+// ContainsMinute reports whether a time of day, in minutes since midnight, falls into the window
+func (w Window) ContainsMinute(minute int) bool {
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+	return minute >= w.Start || minute < w.End
+}
+
+// ParseClock reads a time of day like "09:30" or "9" as minutes since midnight
+func ParseClock(value string) (int, error) {
+	m := clockRe.FindStringSubmatch(value)
+	if m == nil {
+		return 0, fmt.Errorf("invalid time %q: expected HH:MM", value)
+	}
+	return minutes(m[1], m[2])
+}
+
+// FormatClock formats minutes since midnight as HH:MM
+func FormatClock(minute int) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
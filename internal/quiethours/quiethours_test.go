@@ -0,0 +1,68 @@
+package quiethours
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]string{
+		"22:00-08:00":   "22:00-08:00",
+		"22-8":          "22:00-08:00",
+		" 23:30 – 7:15": "23:30-07:15",
+		"13:00-14:00":   "13:00-14:00",
+	}
+	for input, want := range cases {
+		window, err := Parse(input)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if window.String() != want {
+			t.Errorf("Parse(%q) = %s, want %s", input, window, want)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, input := range []string{"", "ночью", "25:00-08:00", "22:60-08:00", "08:00-08:00"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): expected error", input)
+		}
+	}
+}
+
+func TestWindow_Contains(t *testing.T) {
+	loc := time.FixedZone("MSK", 3*60*60)
+	at := func(hour, min int) time.Time { return time.Date(2024, 5, 10, hour, min, 0, 0, loc) }
+
+	overnight := Window{Start: 22 * 60, End: 8 * 60}
+	if !overnight.Contains(at(23, 0)) || !overnight.Contains(at(3, 0)) {
+		t.Error("expected night hours to be quiet")
+	}
+	if overnight.Contains(at(8, 0)) || overnight.Contains(at(12, 0)) {
+		t.Error("expected day hours not to be quiet")
+	}
+
+	lunch := Window{Start: 13 * 60, End: 14 * 60}
+	if !lunch.Contains(at(13, 30)) || lunch.Contains(at(14, 0)) {
+		t.Error("expected same-day window to end at its end time")
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	cases := map[string]int{"09:30": 9*60 + 30, "9": 9 * 60, " 23:05 ": 23*60 + 5}
+	for input, want := range cases {
+		got, err := ParseClock(input)
+		if err != nil || got != want {
+			t.Errorf("ParseClock(%q) = %d, %v; want %d", input, got, err, want)
+		}
+		if FormatClock(got) == "" {
+			t.Errorf("FormatClock(%d) is empty", got)
+		}
+	}
+	for _, input := range []string{"", "24:00", "9:60", "утром", "9-10"} {
+		if _, err := ParseClock(input); err == nil {
+			t.Errorf("ParseClock(%q): expected error", input)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package quota
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	EnvPerChatDaily = "TASK_QUOTA_PER_CHAT_DAY"
+	EnvPerUserDaily = "TASK_QUOTA_PER_USER_DAY"
+)
+
+// Window is the rolling period quotas are counted over.
+const Window = 24 * time.Hour
+
+type Scope string
+
+const (
+	ScopeChat Scope = "chat"
+	ScopeUser Scope = "user"
+)
+
+// Limits caps how many discussion analyses a chat or a single user may start
+// within Window. Zero means unlimited.
+type Limits struct {
+	PerChat int
+	PerUser int
+}
+
+// Usage is the number of analyses already started within Window.
+type Usage struct {
+	Chat int
+	User int
+}
+
+// LimitsFromEnv reads TASK_QUOTA_PER_CHAT_DAY and TASK_QUOTA_PER_USER_DAY.
+func LimitsFromEnv() (Limits, error) {
+	perChat, err := readLimit(EnvPerChatDaily)
+	if err != nil {
+		return Limits{}, err
+	}
+	perUser, err := readLimit(EnvPerUserDaily)
+	if err != nil {
+		return Limits{}, err
+	}
+	return Limits{PerChat: perChat, PerUser: perUser}, nil
+}
+
+func readLimit(name string) (int, error) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", name, raw)
+	}
+	return limit, nil
+}
+
+// Enabled reports whether any limit is configured.
+func (l Limits) Enabled() bool {
+	return l.PerChat > 0 || l.PerUser > 0
+}
+
+// Exceeded returns the scope whose limit is already reached, checking the chat first.
+func (l Limits) Exceeded(usage Usage) (Scope, int, bool) {
+	if l.PerChat > 0 && usage.Chat >= l.PerChat {
+		return ScopeChat, l.PerChat, true
+	}
+	if l.PerUser > 0 && usage.User >= l.PerUser {
+		return ScopeUser, l.PerUser, true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,38 @@
+package quota
+
+import "testing"
+
+func TestLimitsExceeded(t *testing.T) {
+	limits := Limits{PerChat: 5, PerUser: 2}
+
+	if _, _, exceeded := limits.Exceeded(Usage{Chat: 4, User: 1}); exceeded {
+		t.Fatal("usage below limits should not be exceeded")
+	}
+	if scope, limit, exceeded := limits.Exceeded(Usage{Chat: 5, User: 2}); !exceeded || scope != ScopeChat || limit != 5 {
+		t.Fatalf("expected chat limit to win, got %v %d %v", scope, limit, exceeded)
+	}
+	if scope, limit, exceeded := limits.Exceeded(Usage{Chat: 3, User: 2}); !exceeded || scope != ScopeUser || limit != 2 {
+		t.Fatalf("expected user limit, got %v %d %v", scope, limit, exceeded)
+	}
+	if _, _, exceeded := (Limits{}).Exceeded(Usage{Chat: 100, User: 100}); exceeded {
+		t.Fatal("zero limits are unlimited")
+	}
+}
+
+func TestLimitsFromEnv(t *testing.T) {
+	t.Setenv(EnvPerChatDaily, "20")
+	t.Setenv(EnvPerUserDaily, "")
+
+	limits, err := LimitsFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limits.PerChat != 20 || limits.PerUser != 0 || !limits.Enabled() {
+		t.Fatalf("unexpected limits: %+v", limits)
+	}
+
+	t.Setenv(EnvPerUserDaily, "-1")
+	if _, err := LimitsFromEnv(); err == nil {
+		t.Fatal("expected error for negative limit")
+	}
+}
@@ -0,0 +1,142 @@
+package shard
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvInstanceID turns sharding on and names this instance in the membership table
+	EnvInstanceID = "SHARD_INSTANCE_ID"
+	// EnvInstanceURL is the base URL other instances forward this instance's updates to
+	EnvInstanceURL = "SHARD_INSTANCE_URL"
+
+	heartbeatInterval = 10 * time.Second
+	// memberTTL is how long an instance stays on the ring without a heartbeat
+	memberTTL = 30 * time.Second
+)
+
+// Instance is a live member of the ring
+type Instance struct {
+	ID string
+	// URL is where the instance receives the updates forwarded to it
+	URL string
+}
+
+// Store keeps instance membership in the shared database
+type Store interface {
+	HeartbeatShardInstance(ctx context.Context, instance Instance) error
+	ListShardInstances(ctx context.Context, aliveSince time.Time) ([]Instance, error)
+	RemoveShardInstance(ctx context.Context, instanceID string) error
+}
+
+// Coordinator keeps this instance registered and rebuilds the ring when
+// instances join or leave.
+type Coordinator struct {
+	store Store
+	self  Instance
+
+	mu   sync.RWMutex
+	ring *Ring
+	urls map[string]string
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewCoordinator(store Store, self Instance) *Coordinator {
+	return &Coordinator{
+		store: store,
+		self:  self,
+		// Until the first refresh the instance only knows about itself
+		ring:   NewRing([]string{self.ID}),
+		urls:   map[string]string{self.ID: self.URL},
+		stopCh: make(chan struct{}),
+	}
+}
+
+// Start registers the instance and refreshes membership in the background
+func (c *Coordinator) Start(ctx context.Context) error {
+	if err := c.refresh(ctx); err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.stopCh:
+				return
+			case <-ticker.C:
+				refreshCtx, cancel := context.WithTimeout(context.Background(), heartbeatInterval)
+				if err := c.refresh(refreshCtx); err != nil {
+					log.Printf("[SHARD] membership refresh failed, keeping previous ring: %v", err)
+				}
+				cancel()
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop leaves the ring so the remaining instances take over this instance's chats
+func (c *Coordinator) Stop(ctx context.Context) {
+	close(c.stopCh)
+	c.wg.Wait()
+	if err := c.store.RemoveShardInstance(ctx, c.self.ID); err != nil {
+		log.Printf("[SHARD] failed to leave the ring: %v", err)
+	}
+}
+
+// Owns reports whether this instance handles the chat
+func (c *Coordinator) Owns(chatID int64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ring.Owner(chatID) == c.self.ID
+}
+
+// OwnerURL returns the URL of the instance that handles the chat, empty when
+// it is this instance
+func (c *Coordinator) OwnerURL(chatID int64) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	owner := c.ring.Owner(chatID)
+	if owner == c.self.ID {
+		return ""
+	}
+	return c.urls[owner]
+}
+
+func (c *Coordinator) refresh(ctx context.Context) error {
+	if err := c.store.HeartbeatShardInstance(ctx, c.self); err != nil {
+		return err
+	}
+	instances, err := c.store.ListShardInstances(ctx, time.Now().Add(-memberTTL))
+	if err != nil {
+		return err
+	}
+
+	members := make([]string, 0, len(instances))
+	urls := make(map[string]string, len(instances))
+	for _, instance := range instances {
+		members = append(members, instance.ID)
+		urls[instance.ID] = instance.URL
+	}
+	ring := NewRing(members)
+	c.mu.Lock()
+	previous := c.ring.Members()
+	c.ring = ring
+	c.urls = urls
+	c.mu.Unlock()
+
+	if current := ring.Members(); strings.Join(previous, ",") != strings.Join(current, ",") {
+		log.Printf("[SHARD] rebalanced: %d instance(s) %v", len(current), current)
+	}
+	return nil
+}
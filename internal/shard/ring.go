@@ -0,0 +1,63 @@
+// Package shard assigns chats to bot instances with consistent hashing so
+// that adding or removing an instance only moves the chats of that instance.
+package shard
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// virtualNodes spreads every instance over the ring to even out the load
+const virtualNodes = 64
+
+// Ring maps chat IDs to instance IDs
+type Ring struct {
+	hashes  []uint32
+	owners  map[uint32]string
+	members []string
+}
+
+// NewRing builds a ring over the given instances
+func NewRing(instances []string) *Ring {
+	r := &Ring{owners: make(map[uint32]string)}
+	r.members = append(r.members, instances...)
+	sort.Strings(r.members)
+
+	for _, instance := range r.members {
+		for v := 0; v < virtualNodes; v++ {
+			h := hash(instance + "#" + strconv.Itoa(v))
+			if _, taken := r.owners[h]; taken {
+				continue
+			}
+			r.owners[h] = instance
+			r.hashes = append(r.hashes, h)
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// Owner returns the instance responsible for a chat, or "" for an empty ring
+func (r *Ring) Owner(chatID int64) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := hash(strconv.FormatInt(chatID, 10))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.owners[r.hashes[i]]
+}
+
+// Members returns the sorted instance IDs on the ring
+func (r *Ring) Members() []string {
+	return append([]string(nil), r.members...)
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,95 @@
+package shard
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRing_OwnerIsStable(t *testing.T) {
+	ring := NewRing([]string{"a", "b", "c"})
+	other := NewRing([]string{"c", "a", "b"})
+
+	for chatID := int64(-1000); chatID < 1000; chatID++ {
+		if ring.Owner(chatID) != other.Owner(chatID) {
+			t.Fatalf("owner of chat %d depends on member order", chatID)
+		}
+	}
+	if (&Ring{}).Owner(1) != "" {
+		t.Fatal("empty ring should have no owner")
+	}
+}
+
+func TestRing_RemovingInstanceOnlyMovesItsChats(t *testing.T) {
+	before := NewRing([]string{"a", "b", "c"})
+	after := NewRing([]string{"a", "b"})
+
+	counts := map[string]int{}
+	for chatID := int64(0); chatID < 3000; chatID++ {
+		owner := before.Owner(chatID)
+		counts[owner]++
+		if owner != "c" && after.Owner(chatID) != owner {
+			t.Fatalf("chat %d moved from %s although %s stayed on the ring", chatID, owner, owner)
+		}
+	}
+	for _, instance := range []string{"a", "b", "c"} {
+		if counts[instance] < 500 {
+			t.Fatalf("uneven distribution: %v", counts)
+		}
+	}
+}
+
+type memoryStore struct {
+	heartbeats map[string]time.Time
+}
+
+func (s *memoryStore) HeartbeatShardInstance(ctx context.Context, instance Instance) error {
+	s.heartbeats[instance.ID] = time.Now()
+	return nil
+}
+
+func (s *memoryStore) ListShardInstances(ctx context.Context, aliveSince time.Time) ([]Instance, error) {
+	var instances []Instance
+	for id, at := range s.heartbeats {
+		if !at.Before(aliveSince) {
+			instances = append(instances, Instance{ID: id, URL: "http://" + id})
+		}
+	}
+	return instances, nil
+}
+
+func (s *memoryStore) RemoveShardInstance(ctx context.Context, instanceID string) error {
+	delete(s.heartbeats, instanceID)
+	return nil
+}
+
+func TestCoordinator_OwnsItsShareOfChats(t *testing.T) {
+	store := &memoryStore{heartbeats: map[string]time.Time{
+		"peer":  time.Now(),
+		"stale": time.Now().Add(-time.Hour),
+	}}
+	coordinator := NewCoordinator(store, Instance{ID: "self", URL: "http://self"})
+	if err := coordinator.Start(context.Background()); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	ring := NewRing([]string{"self", "peer"})
+	for chatID := int64(0); chatID < 100; chatID++ {
+		owner := ring.Owner(chatID)
+		if coordinator.Owns(chatID) != (owner == "self") {
+			t.Fatalf("ownership of chat %d does not match a ring of live instances", chatID)
+		}
+		wantURL := "http://" + owner
+		if owner == "self" {
+			wantURL = ""
+		}
+		if got := coordinator.OwnerURL(chatID); got != wantURL {
+			t.Fatalf("chat %d: expected owner URL %q, got %q", chatID, wantURL, got)
+		}
+	}
+
+	coordinator.Stop(context.Background())
+	if _, ok := store.heartbeats["self"]; ok {
+		t.Fatal("instance should leave the ring on stop")
+	}
+}
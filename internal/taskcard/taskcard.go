@@ -0,0 +1,176 @@
+// Package taskcard renders the image header of task cards: a banner in the
+// project color with a priority badge, so created tasks stand out in busy chats.
+package taskcard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvTaskCards turns on image task cards, e.g. "true"
+const EnvTaskCards = "TASK_CARDS"
+
+const (
+	width       = 960
+	height      = 240
+	stripHeight = 24
+	badgeSize   = 144
+	badgeMargin = 48
+	// MaxCaptionLength is Telegram's limit for a photo caption
+	MaxCaptionLength = 1024
+)
+
+// projectColors is the Todoist project palette by color name
+var projectColors = map[string]color.RGBA{
+	"berry_red":   rgb(0xb8256f),
+	"red":         rgb(0xdb4035),
+	"orange":      rgb(0xff9933),
+	"yellow":      rgb(0xfad000),
+	"olive_green": rgb(0xafb83b),
+	"lime_green":  rgb(0x7ecc49),
+	"green":       rgb(0x299438),
+	"mint_green":  rgb(0x6accbc),
+	"teal":        rgb(0x158fad),
+	"sky_blue":    rgb(0x14aaf5),
+	"light_blue":  rgb(0x96c3eb),
+	"blue":        rgb(0x4073ff),
+	"grape":       rgb(0x884dff),
+	"violet":      rgb(0xaf38eb),
+	"lavender":    rgb(0xeb96eb),
+	"magenta":     rgb(0xe05194),
+	"salmon":      rgb(0xff8d85),
+	"charcoal":    rgb(0x808080),
+	"grey":        rgb(0xb8b8b8),
+	"taupe":       rgb(0xccac93),
+}
+
+var defaultProjectColor = projectColors["charcoal"]
+
+// priorityColors follows the Todoist flags, keyed by API priority (4 is urgent)
+var priorityColors = map[int]color.RGBA{
+	4: rgb(0xd1453b),
+	3: rgb(0xeb8909),
+	2: rgb(0x246fe0),
+}
+
+var defaultPriorityColor = rgb(0x808080)
+
+// Header describes the image of one card
+type Header struct {
+	ProjectColor string
+	Priority     int
+}
+
+// Renderer draws card headers and keeps the encoded images, since a chat
+// only ever needs a handful of color and priority combinations
+type Renderer struct {
+	mu    sync.Mutex
+	cache map[Header][]byte
+}
+
+// NewRenderer creates a renderer with an empty cache
+func NewRenderer() *Renderer {
+	return &Renderer{cache: make(map[Header][]byte)}
+}
+
+// EnabledFromEnv reads TASK_CARDS
+func EnabledFromEnv() (bool, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvTaskCards))
+	if raw == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q: %w", EnvTaskCards, raw, err)
+	}
+	return enabled, nil
+}
+
+// Render returns the PNG header for h
+func (r *Renderer) Render(h Header) ([]byte, error) {
+	h.ProjectColor = strings.ToLower(strings.TrimSpace(h.ProjectColor))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if data, ok := r.cache[h]; ok {
+		return data, nil
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, drawHeader(h)); err != nil {
+		return nil, fmt.Errorf("failed to encode card header: %w", err)
+	}
+	r.cache[h] = buf.Bytes()
+	return buf.Bytes(), nil
+}
+
+func drawHeader(h Header) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	background, ok := projectColors[h.ProjectColor]
+	if !ok {
+		background = defaultProjectColor
+	}
+	fill(img, img.Bounds(), background)
+	fill(img, image.Rect(0, height-stripHeight, width, height), shade(background, 0.75))
+
+	flagColor, ok := priorityColors[h.Priority]
+	if !ok {
+		flagColor = defaultPriorityColor
+	}
+	badge := image.Rect(width-badgeMargin-badgeSize, (height-badgeSize)/2, width-badgeMargin, (height+badgeSize)/2)
+	fillCircle(img, badge, color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff})
+	drawFlag(img, badge, flagColor)
+
+	return img
+}
+
+// drawFlag draws a flag like the Todoist priority icon in the middle of the badge
+func drawFlag(img *image.RGBA, badge image.Rectangle, c color.RGBA) {
+	size := badge.Dx()
+	left := badge.Min.X + size*3/10
+	top := badge.Min.Y + size/4
+	bottom := badge.Max.Y - size/4
+	poleWidth := size / 18
+
+	fill(img, image.Rect(left, top, left+poleWidth, bottom), c)
+	fill(img, image.Rect(left+poleWidth, top, left+size*9/20, top+size/4), c)
+}
+
+func fill(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	draw.Draw(img, rect, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
+
+func fillCircle(img *image.RGBA, rect image.Rectangle, c color.RGBA) {
+	r := rect.Dx() / 2
+	cx, cy := rect.Min.X+r, rect.Min.Y+r
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			dx, dy := x-cx, y-cy
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func shade(c color.RGBA, factor float64) color.RGBA {
+	return color.RGBA{
+		R: uint8(float64(c.R) * factor),
+		G: uint8(float64(c.G) * factor),
+		B: uint8(float64(c.B) * factor),
+		A: c.A,
+	}
+}
+
+func rgb(hex uint32) color.RGBA {
+	return color.RGBA{R: uint8(hex >> 16), G: uint8(hex >> 8), B: uint8(hex), A: 0xff}
+}
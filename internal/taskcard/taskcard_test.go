@@ -0,0 +1,58 @@
+package taskcard
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRender_DrawsProjectAndPriorityColors(t *testing.T) {
+	data, err := NewRenderer().Render(Header{ProjectColor: "Berry_Red", Priority: 4})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a PNG, got error: %v", err)
+	}
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != height {
+		t.Fatalf("unexpected size %v", img.Bounds())
+	}
+	if got := img.At(10, 10); got != projectColors["berry_red"] {
+		t.Errorf("expected project color in the corner, got %v", got)
+	}
+
+	badgeLeft := width - badgeMargin - badgeSize
+	flagX := badgeLeft + badgeSize*3/10 + 2
+	flagY := (height-badgeSize)/2 + badgeSize/4 + 2
+	if got := img.At(flagX, flagY); got != priorityColors[4] {
+		t.Errorf("expected urgent flag color, got %v", got)
+	}
+}
+
+func TestRender_UsesCache(t *testing.T) {
+	r := NewRenderer()
+	first, err := r.Render(Header{ProjectColor: "blue", Priority: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, _ := r.Render(Header{ProjectColor: " BLUE ", Priority: 1})
+	if &first[0] != &second[0] {
+		t.Error("expected the cached image to be reused")
+	}
+	if len(r.cache) != 1 {
+		t.Errorf("expected 1 cached header, got %d", len(r.cache))
+	}
+}
+
+func TestRender_UnknownColorFallsBack(t *testing.T) {
+	data, err := NewRenderer().Render(Header{ProjectColor: "neon", Priority: 0})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, _ := png.Decode(bytes.NewReader(data))
+	if got := img.At(10, 10); got != defaultProjectColor {
+		t.Errorf("expected default color, got %v", got)
+	}
+}
@@ -0,0 +1,136 @@
+// Package taskdefaults holds the values a chat wants on every task when the
+// discussion does not name them: a due date, a priority and labels.
+package taskdefaults
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+// maxDueInDays keeps a typo like due=+700d from scheduling tasks years ahead
+const maxDueInDays = 365
+
+var dueRe = regexp.MustCompile(`^\+?(\d{1,3})d?$`)
+
+// Policy is a chat's defaults; zero values mean no default
+type Policy struct {
+	// DueInDays sets the due date that many days ahead when none was detected
+	DueInDays int
+	// Priority replaces the lowest priority, which is what tasks without a detected priority get
+	Priority priority.Level
+	// Labels are added to every task
+	Labels []string
+}
+
+// Empty reports whether the policy sets nothing
+func (p Policy) Empty() bool {
+	return p.DueInDays == 0 && !p.Priority.Valid() && len(p.Labels) == 0
+}
+
+// Parse reads settings like "due=+7d priority=medium labels=from-telegram,bug".
+// An empty string is an empty policy.
+func Parse(text string) (Policy, error) {
+	var policy Policy
+	for _, item := range strings.Fields(text) {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok || value == "" {
+			return Policy{}, fmt.Errorf("setting %q must look like key=value", item)
+		}
+		switch strings.ToLower(key) {
+		case "due":
+			m := dueRe.FindStringSubmatch(strings.ToLower(value))
+			if m == nil {
+				return Policy{}, fmt.Errorf("due %q must look like +7d", value)
+			}
+			days, _ := strconv.Atoi(m[1])
+			if days < 1 || days > maxDueInDays {
+				return Policy{}, fmt.Errorf("due must be 1 to %d days ahead, got %d", maxDueInDays, days)
+			}
+			policy.DueInDays = days
+		case "priority":
+			level, err := priority.Parse(value)
+			if err != nil || !level.Valid() {
+				return Policy{}, fmt.Errorf("unknown priority %q", value)
+			}
+			policy.Priority = level
+		case "labels", "label":
+			for _, label := range strings.Split(value, ",") {
+				if label = strings.TrimSpace(label); label != "" && !contains(policy.Labels, label) {
+					policy.Labels = append(policy.Labels, label)
+				}
+			}
+		default:
+			return Policy{}, fmt.Errorf("unknown setting %q: expected due, priority or labels", key)
+		}
+	}
+	return policy, nil
+}
+
+// String formats the policy for storage, the inverse of Parse
+func (p Policy) String() string {
+	var items []string
+	if p.DueInDays > 0 {
+		items = append(items, fmt.Sprintf("due=+%dd", p.DueInDays))
+	}
+	if p.Priority.Valid() {
+		items = append(items, "priority="+p.Priority.Key())
+	}
+	if len(p.Labels) > 0 {
+		items = append(items, "labels="+strings.Join(p.Labels, ","))
+	}
+	return strings.Join(items, " ")
+}
+
+// Task is what a policy fills in; the caller copies the fields back to its draft
+type Task struct {
+	DueISO   string
+	Priority priority.Level
+	Labels   []string
+}
+
+// Applied names a default that changed a task, for showing it in the preview
+type Applied string
+
+const (
+	AppliedDue      Applied = "due"
+	AppliedPriority Applied = "priority"
+	AppliedLabels   Applied = "labels"
+)
+
+// Apply fills in the defaults the task lacks. Dates are counted in loc.
+func (p Policy) Apply(task *Task, now time.Time, loc *time.Location) []Applied {
+	var applied []Applied
+	if p.DueInDays > 0 && task.DueISO == "" {
+		task.DueISO = now.In(loc).AddDate(0, 0, p.DueInDays).Format("2006-01-02")
+		applied = append(applied, AppliedDue)
+	}
+	if p.Priority.Valid() && (!task.Priority.Valid() || task.Priority == priority.LevelLow) && task.Priority != p.Priority {
+		task.Priority = p.Priority
+		applied = append(applied, AppliedPriority)
+	}
+	added := false
+	for _, label := range p.Labels {
+		if !contains(task.Labels, label) {
+			task.Labels = append(task.Labels, label)
+			added = true
+		}
+	}
+	if added {
+		applied = append(applied, AppliedLabels)
+	}
+	return applied
+}
+
+func contains(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
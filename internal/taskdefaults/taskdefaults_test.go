@@ -0,0 +1,70 @@
+package taskdefaults
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/user/telegram-bot/internal/priority"
+)
+
+func TestParse_RoundTrip(t *testing.T) {
+	policy, err := Parse("due=+7d priority=medium labels=from-telegram,bug")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := Policy{DueInDays: 7, Priority: priority.LevelMedium, Labels: []string{"from-telegram", "bug"}}
+	if !reflect.DeepEqual(policy, want) {
+		t.Fatalf("Parse() = %+v, want %+v", policy, want)
+	}
+
+	again, err := Parse(policy.String())
+	if err != nil || !reflect.DeepEqual(again, policy) {
+		t.Errorf("String() does not round-trip: %q -> %+v, %v", policy.String(), again, err)
+	}
+}
+
+func TestParse_Rejects(t *testing.T) {
+	for _, text := range []string{"due=week", "due=+0d", "due=+999d", "priority=p9", "owner=me", "labels"} {
+		if _, err := Parse(text); err == nil {
+			t.Errorf("Parse(%q) expected error", text)
+		}
+	}
+	if policy, err := Parse(""); err != nil || !policy.Empty() {
+		t.Errorf("Parse(\"\") = %+v, %v; want empty policy", policy, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)
+	moscow := time.FixedZone("MSK", 3*60*60)
+	policy := Policy{DueInDays: 7, Priority: priority.LevelMedium, Labels: []string{"from-telegram"}}
+
+	t.Run("fills in what is missing", func(t *testing.T) {
+		task := Task{Priority: priority.LevelLow, Labels: []string{"bug"}}
+		applied := policy.Apply(&task, now, moscow)
+
+		if task.DueISO != "2026-03-18" {
+			t.Errorf("expected due date counted in the chat time zone, got %s", task.DueISO)
+		}
+		if task.Priority != priority.LevelMedium {
+			t.Errorf("expected default priority, got %v", task.Priority)
+		}
+		if !reflect.DeepEqual(task.Labels, []string{"bug", "from-telegram"}) {
+			t.Errorf("unexpected labels %v", task.Labels)
+		}
+		if !reflect.DeepEqual(applied, []Applied{AppliedDue, AppliedPriority, AppliedLabels}) {
+			t.Errorf("unexpected applied %v", applied)
+		}
+	})
+
+	t.Run("keeps detected values", func(t *testing.T) {
+		task := Task{DueISO: "2026-03-12", Priority: priority.LevelUrgent, Labels: []string{"From-Telegram"}}
+		if applied := policy.Apply(&task, now, moscow); len(applied) != 0 {
+			t.Errorf("expected nothing applied, got %v", applied)
+		}
+		if task.DueISO != "2026-03-12" || task.Priority != priority.LevelUrgent || len(task.Labels) != 1 {
+			t.Errorf("detected values changed: %+v", task)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+// Package taskimport reads tasks from CSV files in Todoist's template format
+// (TYPE, CONTENT, DESCRIPTION, PRIORITY, DATE, DATE_LANG, ...).
+package taskimport
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// MaxTasks limits one import so a wrong file cannot flood a project
+const MaxTasks = 500
+
+// Row is a task read from one CSV line
+type Row struct {
+	Line int
+	Task todoist.TaskRequest
+}
+
+// Problem is a CSV line that could not be imported
+type Problem struct {
+	Line   int
+	Reason string
+}
+
+// Result is a parsed CSV file
+type Result struct {
+	Rows     []Row
+	Problems []Problem
+	// Skipped counts sections and notes, which are not imported
+	Skipped int
+}
+
+// Parse reads a Todoist template CSV. Only CONTENT is required; rows of other
+// types than "task" are skipped. Line errors do not stop parsing.
+func Parse(data []byte) (*Result, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("file is empty")
+		}
+		return nil, fmt.Errorf("failed to read header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["CONTENT"]; !ok {
+		return nil, fmt.Errorf("header has no CONTENT column")
+	}
+
+	result := &Result{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			result.Problems = append(result.Problems, Problem{Line: line, Reason: err.Error()})
+			continue
+		}
+		field := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		if kind := strings.ToLower(field("TYPE")); kind != "" && kind != "task" {
+			result.Skipped++
+			continue
+		}
+
+		task, reason := parseTask(field)
+		if reason != "" {
+			result.Problems = append(result.Problems, Problem{Line: line, Reason: reason})
+			continue
+		}
+		if task.Content == "" {
+			// Blank spacer lines are common in exported templates
+			continue
+		}
+		if len(result.Rows) == MaxTasks {
+			return nil, fmt.Errorf("more than %d tasks in one file", MaxTasks)
+		}
+		result.Rows = append(result.Rows, Row{Line: line, Task: task})
+	}
+	return result, nil
+}
+
+func parseTask(field func(string) string) (todoist.TaskRequest, string) {
+	content, labels := splitLabels(field("CONTENT"))
+	task := todoist.TaskRequest{
+		Content:     content,
+		Description: field("DESCRIPTION"),
+		Labels:      labels,
+		DueString:   field("DATE"),
+		DueLang:     field("DATE_LANG"),
+	}
+
+	if raw := field("PRIORITY"); raw != "" {
+		// In Todoist CSV 1 is the most urgent, in the API it is 4
+		priority, err := strconv.Atoi(raw)
+		if err != nil || priority < 1 || priority > 4 {
+			return task, fmt.Sprintf("PRIORITY must be 1-4, got %q", raw)
+		}
+		task.Priority = 5 - priority
+	}
+	if len([]rune(task.Content)) > 500 {
+		return task, "CONTENT is longer than 500 characters"
+	}
+	return task, ""
+}
+
+// splitLabels moves @labels out of the task content
+func splitLabels(content string) (string, []string) {
+	var words, labels []string
+	for _, word := range strings.Fields(content) {
+		if len(word) > 1 && strings.HasPrefix(word, "@") {
+			labels = append(labels, strings.TrimPrefix(word, "@"))
+			continue
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " "), labels
+}
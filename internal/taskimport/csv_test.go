@@ -0,0 +1,47 @@
+package taskimport
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	data := "\xef\xbb\xbfTYPE,CONTENT,DESCRIPTION,PRIORITY,INDENT,AUTHOR,RESPONSIBLE,DATE,DATE_LANG\n" +
+		"section,Backlog,,,,,,,\n" +
+		"task,Починить выгрузку @backend,Падает на больших файлах,1,1,,,tomorrow,en\n" +
+		",,,,,,,,\n" +
+		"task,Обновить README,,,1,,,,\n" +
+		"task,Сломанный приоритет,,7,1,,,,\n"
+
+	result, err := Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(result.Rows) != 2 || result.Skipped != 1 || len(result.Problems) != 1 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	first := result.Rows[0]
+	if first.Line != 3 || first.Task.Content != "Починить выгрузку" || first.Task.Priority != 4 {
+		t.Errorf("unexpected first task: %+v", first)
+	}
+	if len(first.Task.Labels) != 1 || first.Task.Labels[0] != "backend" {
+		t.Errorf("expected label from content, got %v", first.Task.Labels)
+	}
+	if first.Task.DueString != "tomorrow" || first.Task.DueLang != "en" {
+		t.Errorf("unexpected due: %+v", first.Task)
+	}
+	if result.Problems[0].Line != 6 || !strings.Contains(result.Problems[0].Reason, "PRIORITY") {
+		t.Errorf("unexpected problem: %+v", result.Problems[0])
+	}
+}
+
+func TestParse_RequiresContentColumn(t *testing.T) {
+	if _, err := Parse([]byte("TITLE,DATE\nfoo,today\n")); err == nil {
+		t.Fatal("expected error for missing CONTENT column")
+	}
+	if _, err := Parse(nil); err == nil {
+		t.Fatal("expected error for empty file")
+	}
+}
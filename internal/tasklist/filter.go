@@ -0,0 +1,93 @@
+package tasklist
+
+import (
+	"strings"
+	"time"
+
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// Filter narrows a listing down by due date
+type Filter string
+
+const (
+	FilterAll Filter = ""
+	// FilterOverdue keeps open tasks due before today
+	FilterOverdue Filter = "overdue"
+	// FilterToday keeps tasks due today
+	FilterToday Filter = "today"
+	// FilterUpcoming keeps tasks due in the UpcomingDays after today
+	FilterUpcoming Filter = "upcoming"
+	// FilterNoDue keeps tasks without a due date
+	FilterNoDue Filter = "nodue"
+)
+
+// UpcomingDays is how far ahead FilterUpcoming looks
+const UpcomingDays = 7
+
+// Filters lists the named filters in the order they are offered
+var Filters = []Filter{FilterOverdue, FilterToday, FilterUpcoming, FilterNoDue}
+
+// ParseFilter returns the filter named by s, e.g. "overdue"
+func ParseFilter(s string) (Filter, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for _, f := range Filters {
+		if s == string(f) {
+			return f, true
+		}
+	}
+	return FilterAll, s == ""
+}
+
+// Match reports whether task passes the filter on the day of now; due dates
+// are compared as calendar days in now's location
+func (f Filter) Match(task *tracker.Task, now time.Time) bool {
+	if task == nil {
+		return false
+	}
+	due := dueDay(task.DueDate)
+	today := now.Format("2006-01-02")
+	switch f {
+	case FilterOverdue:
+		return due != "" && due < today && !task.Completed
+	case FilterToday:
+		return due == today
+	case FilterUpcoming:
+		return due > today && due <= now.AddDate(0, 0, UpcomingDays).Format("2006-01-02")
+	case FilterNoDue:
+		return due == ""
+	}
+	return true
+}
+
+// Apply returns the tasks that pass the filter, sorted as they came
+func (f Filter) Apply(tasks []*tracker.Task, now time.Time) []*tracker.Task {
+	if f == FilterAll {
+		return tasks
+	}
+	var matched []*tracker.Task
+	for _, task := range tasks {
+		if f.Match(task, now) {
+			matched = append(matched, task)
+		}
+	}
+	return matched
+}
+
+// dueDay is the calendar day of a due date, which may carry a time
+func dueDay(due string) string {
+	if len(due) > len("2006-01-02") {
+		return due[:len("2006-01-02")]
+	}
+	return due
+}
+
+// Page returns the tasks of a page of size tasks, with the page clamped to
+// the existing ones and the number of pages
+func Page(tasks []*tracker.Task, page, size int) ([]*tracker.Task, int, int) {
+	pages := max((len(tasks)+size-1)/size, 1)
+	page = min(max(page, 0), pages-1)
+	start := page * size
+	end := min(start+size, len(tasks))
+	return tasks[start:end], page, pages
+}
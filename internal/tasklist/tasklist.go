@@ -0,0 +1,118 @@
+// Package tasklist renders task and project listings as Telegram Markdown and
+// filters and pages the tasks of a listing.
+// Listings can hold hundreds of tasks and are rendered for every chat, so the
+// renderer writes into pooled buffers and escapes text in place instead of
+// formatting each line with fmt.
+package tasklist
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+// bytesPerTask is a rough size of one rendered task, used to grow the buffer once
+const bytesPerTask = 128
+
+// maxPooledBuffer keeps a rare huge listing from pinning its buffer in the pool
+const maxPooledBuffer = 64 << 10
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer(size int) *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	b.Grow(size)
+	return b
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(b)
+}
+
+// Tasks renders tasks in lang under the heading, followed by the footer.
+// The heading is escaped; the footer is written as is, since it carries markup.
+func Tasks(heading string, tasks []*tracker.Task, footer string, lang i18n.Lang) string {
+	b := getBuffer(len(heading) + len(footer) + len(tasks)*bytesPerTask)
+	defer putBuffer(b)
+	due := "  " + i18n.T(lang, i18n.ListDue) + ": "
+	project := "  " + i18n.T(lang, i18n.ListProject) + ": "
+
+	b.WriteString("📝 *")
+	writeEscaped(b, heading)
+	b.WriteString(":*\n\n")
+
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		if task.Completed {
+			b.WriteString("✅ ~")
+			writeEscaped(b, task.Title)
+			b.WriteString("~\n")
+		} else {
+			b.WriteString("⬜ *")
+			writeEscaped(b, task.Title)
+			b.WriteString("*\n")
+		}
+
+		b.WriteString("  ID: `")
+		b.WriteString(task.ID)
+		b.WriteString("`\n")
+
+		if task.DueDate != "" {
+			b.WriteString(due)
+			writeEscaped(b, task.DueDate)
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(project)
+		writeEscaped(b, task.ProjectID)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(footer)
+	return b.String()
+}
+
+// Projects renders projects in lang with hints on listing their tasks
+func Projects(projects []tracker.Project, lang i18n.Lang) string {
+	b := getBuffer(len(projects) * bytesPerTask)
+	defer putBuffer(b)
+	hint := "`\n  " + i18n.T(lang, i18n.ListProjectTasksHint) + " `/list tasks "
+
+	b.WriteString("📋 *" + i18n.T(lang, i18n.ListProjectsHeading) + ":*\n\n")
+	for _, project := range projects {
+		b.WriteString("• *")
+		writeEscaped(b, project.Name)
+		b.WriteString("*\n  ID: `")
+		b.WriteString(project.ID)
+		b.WriteString(hint)
+		b.WriteString(project.ID)
+		b.WriteString("`\n\n")
+	}
+	return b.String()
+}
+
+// writeEscaped writes s with the legacy Markdown control characters escaped,
+// copying unescaped runs whole so plain text costs a single write
+func writeEscaped(b *bytes.Buffer, s string) {
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '_', '*', '`', '[':
+			b.WriteString(s[start:i])
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+			start = i + 1
+		}
+	}
+	b.WriteString(s[start:])
+}
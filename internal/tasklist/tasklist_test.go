@@ -0,0 +1,139 @@
+package tasklist
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/telegram-bot/internal/i18n"
+	"github.com/user/telegram-bot/internal/tracker"
+)
+
+func TestTasks_RendersTasksAndEscapesText(t *testing.T) {
+	tasks := []*tracker.Task{
+		{ID: "1", Title: "Починить *логин*", ProjectID: "p1", DueDate: "2026-04-01"},
+		{ID: "2", Title: "snake_case [ссылка]", ProjectID: "p1", Completed: true},
+		nil,
+	}
+
+	got := Tasks("Задачи в проекте my_project", tasks, "FOOTER", i18n.Russian)
+	want := "📝 *Задачи в проекте my\\_project:*\n\n" +
+		"⬜ *Починить \\*логин\\**\n  ID: `1`\n  Срок: 2026-04-01\n  Проект: p1\n\n" +
+		"✅ ~snake\\_case \\[ссылка]~\n  ID: `2`\n  Проект: p1\n\n" +
+		"FOOTER"
+	if got != want {
+		t.Fatalf("unexpected listing:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestProjects_RendersProjects(t *testing.T) {
+	got := Projects([]tracker.Project{{ID: "p1", Name: "Work_Stuff"}}, i18n.Russian)
+	want := "📋 *Ваши проекты:*\n\n" +
+		"• *Work\\_Stuff*\n  ID: `p1`\n  Задачи: Используйте `/list tasks p1`\n\n"
+	if got != want {
+		t.Fatalf("unexpected listing:\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTasks_PooledBuffersDoNotLeakBetweenCalls(t *testing.T) {
+	first := Tasks("A", largeTaskList(50), "", i18n.Russian)
+	second := Tasks("B", largeTaskList(1), "", i18n.Russian)
+	if strings.Contains(second, "Задача 10") {
+		t.Fatalf("expected a fresh buffer, got %q", second)
+	}
+	if !strings.HasPrefix(first, "📝 *A:*") || Tasks("A", largeTaskList(50), "", i18n.Russian) != first {
+		t.Fatal("expected rendering to be stable across pooled buffers")
+	}
+}
+
+func BenchmarkTasks200(b *testing.B) {
+	tasks := largeTaskList(200)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Tasks("Ваши задачи", tasks, "", i18n.Russian)
+	}
+}
+
+func BenchmarkProjects200(b *testing.B) {
+	projects := make([]tracker.Project, 200)
+	for i := range projects {
+		projects[i] = tracker.Project{ID: strconv.Itoa(i), Name: "Проект_" + strconv.Itoa(i)}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Projects(projects, i18n.Russian)
+	}
+}
+
+func largeTaskList(n int) []*tracker.Task {
+	tasks := make([]*tracker.Task, n)
+	for i := range tasks {
+		tasks[i] = &tracker.Task{
+			ID:        strconv.Itoa(1000000 + i),
+			Title:     "Задача " + strconv.Itoa(i) + ": обновить *конфиг* сервиса user_profile",
+			ProjectID: "2203306141",
+			Completed: i%5 == 0,
+			DueDate:   "2026-04-01",
+		}
+	}
+	return tasks
+}
+
+func TestFilter_Match(t *testing.T) {
+	now := time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)
+	tests := []struct {
+		filter Filter
+		task   tracker.Task
+		want   bool
+	}{
+		{FilterOverdue, tracker.Task{DueDate: "2026-10-14"}, true},
+		{FilterOverdue, tracker.Task{DueDate: "2026-10-14", Completed: true}, false},
+		{FilterOverdue, tracker.Task{DueDate: "2026-10-15"}, false},
+		{FilterOverdue, tracker.Task{}, false},
+		{FilterToday, tracker.Task{DueDate: "2026-10-15T09:00:00"}, true},
+		{FilterToday, tracker.Task{DueDate: "2026-10-16"}, false},
+		{FilterUpcoming, tracker.Task{DueDate: "2026-10-16"}, true},
+		{FilterUpcoming, tracker.Task{DueDate: "2026-10-22"}, true},
+		{FilterUpcoming, tracker.Task{DueDate: "2026-10-23"}, false},
+		{FilterUpcoming, tracker.Task{DueDate: "2026-10-15"}, false},
+		{FilterNoDue, tracker.Task{}, true},
+		{FilterNoDue, tracker.Task{DueDate: "2026-10-15"}, false},
+		{FilterAll, tracker.Task{DueDate: "2020-01-01"}, true},
+	}
+	for _, tt := range tests {
+		task := tt.task
+		if got := tt.filter.Match(&task, now); got != tt.want {
+			t.Errorf("%q.Match(due %q) = %v, want %v", tt.filter, task.DueDate, got, tt.want)
+		}
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	if f, ok := ParseFilter(" Overdue "); !ok || f != FilterOverdue {
+		t.Errorf("ParseFilter(Overdue) = %q, %v", f, ok)
+	}
+	if f, ok := ParseFilter(""); !ok || f != FilterAll {
+		t.Errorf("ParseFilter(\"\") = %q, %v", f, ok)
+	}
+	if _, ok := ParseFilter("later"); ok {
+		t.Error("expected unknown filter to be rejected")
+	}
+}
+
+func TestPage(t *testing.T) {
+	tasks := largeTaskList(23)
+
+	got, page, pages := Page(tasks, 2, 10)
+	if len(got) != 3 || page != 2 || pages != 3 || got[0] != tasks[20] {
+		t.Fatalf("Page(2) = %d tasks, page %d of %d", len(got), page, pages)
+	}
+	if _, page, _ := Page(tasks, 9, 10); page != 2 {
+		t.Errorf("page past the end clamped to %d, want 2", page)
+	}
+	if got, page, pages := Page(nil, 0, 10); len(got) != 0 || page != 0 || pages != 1 {
+		t.Errorf("Page(nil) = %d tasks, page %d of %d", len(got), page, pages)
+	}
+}
@@ -0,0 +1,160 @@
+// Package telemetry sends an anonymous heartbeat with feature usage counts, so
+// maintainers learn which features are actually used. It is off unless the
+// installation opts in with TELEMETRY_OPT_IN=true. Only feature names and
+// counts leave the process: no message text, chat, user or bot IDs.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// Environment variables of the heartbeat
+const (
+	EnvOptIn    = "TELEMETRY_OPT_IN"
+	EnvEndpoint = "TELEMETRY_ENDPOINT"
+	EnvInterval = "TELEMETRY_INTERVAL"
+)
+
+// DefaultInterval is how often the heartbeat is sent
+const DefaultInterval = 24 * time.Hour
+
+// Config is the heartbeat destination
+type Config struct {
+	Endpoint string
+	Interval time.Duration
+}
+
+// ConfigFromEnv reads the heartbeat settings; ok is false unless the installation opted in
+func ConfigFromEnv() (Config, bool, error) {
+	raw := strings.TrimSpace(os.Getenv(EnvOptIn))
+	if raw == "" {
+		return Config{}, false, nil
+	}
+	optIn, err := strconv.ParseBool(raw)
+	if err != nil {
+		return Config{}, false, fmt.Errorf("invalid %s %q: %w", EnvOptIn, raw, err)
+	}
+	if !optIn {
+		return Config{}, false, nil
+	}
+
+	cfg := Config{Endpoint: strings.TrimSpace(os.Getenv(EnvEndpoint)), Interval: DefaultInterval}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return Config{}, false, fmt.Errorf("%s requires an http(s) %s, got %q", EnvOptIn, EnvEndpoint, cfg.Endpoint)
+	}
+	if raw := strings.TrimSpace(os.Getenv(EnvInterval)); raw != "" {
+		if cfg.Interval, err = time.ParseDuration(raw); err != nil || cfg.Interval < time.Minute {
+			return Config{}, false, fmt.Errorf("invalid %s %q: use a duration of at least 1m", EnvInterval, raw)
+		}
+	}
+	return cfg, true, nil
+}
+
+// FeatureUsage is how much a feature was used during a heartbeat period
+type FeatureUsage struct {
+	Uses  int `json:"uses"`
+	Chats int `json:"chats"`
+}
+
+// Report is the heartbeat body
+type Report struct {
+	InstallationID string                  `json:"installation_id"`
+	PeriodStart    time.Time               `json:"period_start"`
+	PeriodEnd      time.Time               `json:"period_end"`
+	Features       map[string]FeatureUsage `json:"features"`
+}
+
+// Collector counts feature usage between heartbeats. Chat IDs are kept only
+// in memory to count distinct chats and are never reported.
+type Collector struct {
+	mu    sync.Mutex
+	since time.Time
+	uses  map[string]int
+	chats map[string]map[int64]struct{}
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		since: time.Now().UTC(),
+		uses:  make(map[string]int),
+		chats: make(map[string]map[int64]struct{}),
+	}
+}
+
+// Record counts one use of feature in a chat
+func (c *Collector) Record(feature string, chatID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.uses[feature]++
+	if c.chats[feature] == nil {
+		c.chats[feature] = make(map[int64]struct{})
+	}
+	c.chats[feature][chatID] = struct{}{}
+}
+
+// Take returns the usage since the previous call and starts a new period
+func (c *Collector) Take() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now().UTC()
+	report := Report{PeriodStart: c.since, PeriodEnd: now, Features: make(map[string]FeatureUsage, len(c.uses))}
+	for feature, uses := range c.uses {
+		report.Features[feature] = FeatureUsage{Uses: uses, Chats: len(c.chats[feature])}
+	}
+	c.since = now
+	c.uses = make(map[string]int)
+	c.chats = make(map[string]map[int64]struct{})
+	return report
+}
+
+// Reporter sends the collected usage to the endpoint on every interval
+type Reporter struct {
+	cfg            Config
+	collector      *Collector
+	installationID string
+	client         *httpclient.Client
+}
+
+// NewReporter creates a reporter; installationID is a random ID of this installation
+func NewReporter(cfg Config, collector *Collector, installationID string, client *httpclient.Client) *Reporter {
+	return &Reporter{cfg: cfg, collector: collector, installationID: installationID, client: client}
+}
+
+// Run sends a heartbeat on every interval until ctx is done
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Send(ctx); err != nil {
+				log.Printf("Error sending telemetry heartbeat: %v", err)
+			}
+		}
+	}
+}
+
+// Send reports the usage since the previous heartbeat. Usage of a failed
+// heartbeat is dropped: telemetry never piles up.
+func (r *Reporter) Send(ctx context.Context) error {
+	report := r.collector.Take()
+	report.InstallationID = r.installationID
+	if err := r.client.Post(ctx, r.cfg.Endpoint, report, nil); err != nil {
+		return fmt.Errorf("failed to post heartbeat: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv(EnvOptIn, "")
+	_, ok, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	t.Setenv(EnvOptIn, "false")
+	t.Setenv(EnvEndpoint, "https://telemetry.example.com/v1/heartbeat")
+	_, ok, err = ConfigFromEnv()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	t.Setenv(EnvOptIn, "true")
+	cfg, ok, err := ConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, DefaultInterval, cfg.Interval)
+
+	t.Setenv(EnvInterval, "30s")
+	_, _, err = ConfigFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv(EnvInterval, "")
+	t.Setenv(EnvEndpoint, "")
+	_, _, err = ConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestCollector(t *testing.T) {
+	collector := NewCollector()
+	collector.Record("create_task", 1)
+	collector.Record("create_task", 1)
+	collector.Record("create_task", 2)
+	collector.Record("backup", 2)
+
+	report := collector.Take()
+	assert.Equal(t, map[string]FeatureUsage{
+		"create_task": {Uses: 3, Chats: 2},
+		"backup":      {Uses: 1, Chats: 1},
+	}, report.Features)
+
+	next := collector.Take()
+	assert.Empty(t, next.Features)
+	assert.Equal(t, report.PeriodEnd, next.PeriodStart)
+}
+
+func TestReporterSend(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	collector := NewCollector()
+	collector.Record("notify", -1001234567890)
+	config := httpclient.DefaultConfig()
+	config.RetryWaitTime = time.Millisecond
+	reporter := NewReporter(Config{Endpoint: server.URL, Interval: time.Hour}, collector, "install-1", httpclient.NewClient(config))
+
+	require.NoError(t, reporter.Send(context.Background()))
+
+	var got Report
+	require.NoError(t, json.Unmarshal(body, &got))
+	assert.Equal(t, "install-1", got.InstallationID)
+	assert.Equal(t, map[string]FeatureUsage{"notify": {Uses: 1, Chats: 1}}, got.Features)
+	assert.NotContains(t, string(body), "1234567890")
+}
@@ -0,0 +1,84 @@
+// Package timezone holds the time zones dates are read and shown in: the
+// global default from BOT_TIMEZONE and the IANA zone a chat picked with
+// /timezone, which takes precedence for that chat.
+package timezone
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// EnvDefault is the IANA time zone of chats that did not pick their own
+const EnvDefault = "BOT_TIMEZONE"
+
+// DefaultName is the zone used when BOT_TIMEZONE is not set
+const DefaultName = "Europe/Moscow"
+
+var defaultLocation atomic.Pointer[time.Location]
+
+// FromEnv reads BOT_TIMEZONE, Europe/Moscow by default
+func FromEnv() (*time.Location, error) {
+	name := strings.TrimSpace(os.Getenv(EnvDefault))
+	if name == "" {
+		name = DefaultName
+	}
+	loc, err := Load(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", EnvDefault, err)
+	}
+	return loc, nil
+}
+
+// SetDefault sets the zone of chats without their own
+func SetDefault(loc *time.Location) {
+	defaultLocation.Store(loc)
+}
+
+// Default returns the zone of chats without their own: the one set with
+// SetDefault, else Europe/Moscow, or UTC when tzdata is missing
+func Default() *time.Location {
+	if loc := defaultLocation.Load(); loc != nil {
+		return loc
+	}
+	if loc, err := time.LoadLocation(DefaultName); err == nil {
+		return loc
+	}
+	return time.UTC
+}
+
+// Load resolves an IANA zone name like "Europe/Berlin". Unlike
+// time.LoadLocation it rejects the empty name and "Local", which depend on
+// the server rather than the chat.
+func Load(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// ForChat returns the zone a chat stored, or the default when it stored none
+// or one that no longer loads
+func ForChat(stored string) *time.Location {
+	if stored == "" {
+		return Default()
+	}
+	loc, err := Load(stored)
+	if err != nil {
+		return Default()
+	}
+	return loc
+}
+
+// Label is how replies name a zone: its name with the current UTC offset,
+// e.g. "Europe/Berlin, UTC+02:00"
+func Label(loc *time.Location, now time.Time) string {
+	return fmt.Sprintf("%s, UTC%s", loc, now.In(loc).Format("-07:00"))
+}
@@ -0,0 +1,52 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	loc, err := Load(" Europe/Berlin ")
+	if err != nil || loc.String() != "Europe/Berlin" {
+		t.Fatalf("Load(Europe/Berlin) = %v, %v", loc, err)
+	}
+	for _, name := range []string{"", "Local", "Mars/Olympus", "МСК"} {
+		if _, err := Load(name); err == nil {
+			t.Errorf("Load(%q) succeeded, want an error", name)
+		}
+	}
+}
+
+func TestForChatFallsBackToDefault(t *testing.T) {
+	berlin, _ := Load("Europe/Berlin")
+	SetDefault(berlin)
+	defer SetDefault(nil)
+
+	if got := ForChat(""); got != berlin {
+		t.Errorf("ForChat(\"\") = %v, want the default", got)
+	}
+	if got := ForChat("Broken/Zone"); got != berlin {
+		t.Errorf("ForChat(broken) = %v, want the default", got)
+	}
+	if got := ForChat("Asia/Tokyo"); got.String() != "Asia/Tokyo" {
+		t.Errorf("ForChat(Asia/Tokyo) = %v", got)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(EnvDefault, "")
+	if loc, err := FromEnv(); err != nil || loc.String() != DefaultName {
+		t.Errorf("FromEnv() without %s = %v, %v, want %s", EnvDefault, loc, err, DefaultName)
+	}
+	t.Setenv(EnvDefault, "Nowhere/City")
+	if _, err := FromEnv(); err == nil {
+		t.Error("expected an unknown zone to be rejected")
+	}
+}
+
+func TestLabel(t *testing.T) {
+	tokyo, _ := Load("Asia/Tokyo")
+	if got := Label(tokyo, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)); got != "Asia/Tokyo, UTC+09:00" {
+		t.Errorf("Label = %q", got)
+	}
+}
@@ -0,0 +1,78 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+// FileAttachment is a file uploaded to Todoist, ready to be attached to a comment
+type FileAttachment struct {
+	FileName     string `json:"file_name"`
+	FileType     string `json:"file_type,omitempty"`
+	FileURL      string `json:"file_url"`
+	FileSize     int64  `json:"file_size,omitempty"`
+	ResourceType string `json:"resource_type,omitempty"`
+}
+
+// AttachmentUploader is implemented by clients that can upload files and
+// attach them to task comments
+type AttachmentUploader interface {
+	UploadFile(ctx context.Context, fileName string, content []byte) (*FileAttachment, error)
+	AddComment(ctx context.Context, taskID, content string, attachment *FileAttachment) (*Comment, error)
+}
+
+type commentRequest struct {
+	TaskID     string          `json:"task_id"`
+	Content    string          `json:"content"`
+	Attachment *FileAttachment `json:"attachment,omitempty"`
+}
+
+// UploadFile uploads a file as multipart form data
+func (c *TodoistClient) UploadFile(ctx context.Context, fileName string, content []byte) (*FileAttachment, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("file_name", fileName); err != nil {
+		return nil, fmt.Errorf("error building upload: %w", err)
+	}
+	part, err := form.CreateFormFile("file", fileName)
+	if err != nil {
+		return nil, fmt.Errorf("error building upload: %w", err)
+	}
+	if _, err := part.Write(content); err != nil {
+		return nil, fmt.Errorf("error building upload: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("error building upload: %w", err)
+	}
+
+	req, err := c.httpClient.NewRequest(ctx, http.MethodPost, "uploads", nil)
+	if err != nil {
+		return nil, err
+	}
+	// Retries resend the body, so it must be readable again
+	payload := body.Bytes()
+	req.Body = io.NopCloser(bytes.NewReader(payload))
+	req.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(payload)), nil }
+	req.ContentLength = int64(len(payload))
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	var attachment FileAttachment
+	if err := c.httpClient.DoRequest(ctx, req, &attachment); err != nil {
+		return nil, fmt.Errorf("error uploading file: %w", err)
+	}
+	return &attachment, nil
+}
+
+// AddComment adds a comment to a task, with an uploaded file if attachment is not nil
+func (c *TodoistClient) AddComment(ctx context.Context, taskID, content string, attachment *FileAttachment) (*Comment, error) {
+	var comment Comment
+	err := c.httpClient.Post(ctx, "comments", commentRequest{TaskID: taskID, Content: content, Attachment: attachment}, &comment)
+	if err != nil {
+		return nil, fmt.Errorf("error adding comment: %w", err)
+	}
+	return &comment, nil
+}
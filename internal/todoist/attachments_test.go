@@ -0,0 +1,61 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Tests that a file is uploaded as multipart form data and attached to a task comment
+func TestTodoistClient_UploadAndComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/uploads":
+			file, header, err := r.FormFile("file")
+			if err != nil {
+				t.Fatalf("Expected a multipart file: %v", err)
+			}
+			content, _ := io.ReadAll(file)
+			if header.Filename != "photo_7.jpg" || string(content) != "jpeg" {
+				t.Errorf("Unexpected upload %q: %q", header.Filename, content)
+			}
+			fmt.Fprint(w, `{"file_name":"photo_7.jpg","file_type":"image/jpeg","file_url":"https://files.example/photo_7.jpg","resource_type":"image"}`)
+		case "/comments":
+			var body commentRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("Error decoding comment: %v", err)
+			}
+			if body.TaskID != "42" || body.Attachment == nil || body.Attachment.FileURL != "https://files.example/photo_7.jpg" {
+				t.Errorf("Unexpected comment: %+v", body)
+			}
+			fmt.Fprint(w, `{"id":"c1","content":"📎 photo_7.jpg","file_attachment":{"file_name":"photo_7.jpg","file_url":"https://files.example/photo_7.jpg"}}`)
+		default:
+			t.Logf("Unhandled request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(AttachmentUploader)
+
+	attachment, err := client.UploadFile(context.Background(), "photo_7.jpg", []byte("jpeg"))
+	if err != nil {
+		t.Fatalf("Error uploading file: %v", err)
+	}
+	comment, err := client.AddComment(context.Background(), "42", "📎 photo_7.jpg", attachment)
+	if err != nil {
+		t.Fatalf("Error adding comment: %v", err)
+	}
+	if comment.Attachment == nil || comment.Attachment.FileName != "photo_7.jpg" {
+		t.Errorf("Expected the attachment on the comment, got %+v", comment)
+	}
+}
@@ -0,0 +1,166 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// pageLimit is the largest page size Todoist accepts
+const pageLimit = 200
+
+// maxPages stops pagination if the API keeps returning cursors
+const maxPages = 500
+
+// Section represents a Todoist project section
+type Section struct {
+	ID        string `json:"id"`
+	ProjectID string `json:"project_id"`
+	Name      string `json:"name"`
+	Order     int    `json:"order"`
+}
+
+// Comment represents a comment on a Todoist task
+type Comment struct {
+	ID       string `json:"id"`
+	TaskID   string `json:"task_id,omitempty"`
+	Content  string `json:"content"`
+	PostedAt string `json:"posted_at"`
+	PostedBy string `json:"posted_uid,omitempty"`
+
+	Attachment *FileAttachment `json:"file_attachment,omitempty"`
+}
+
+// TaskBackup is a task with its comments
+type TaskBackup struct {
+	*TaskResponse
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+// ProjectSnapshot is a full export of a project
+type ProjectSnapshot struct {
+	ExportedAt time.Time    `json:"exported_at"`
+	Project    Project      `json:"project"`
+	Sections   []Section    `json:"sections"`
+	Tasks      []TaskBackup `json:"tasks"`
+}
+
+// ProjectExporter is implemented by clients that can export a whole project
+type ProjectExporter interface {
+	ExportProject(ctx context.Context, projectID string) (*ProjectSnapshot, error)
+}
+
+// SectionLister is implemented by clients that can list the sections of a project
+type SectionLister interface {
+	GetSections(ctx context.Context, projectID string) ([]Section, error)
+}
+
+// TaskFilterer is implemented by clients that can select tasks with a Todoist
+// filter query
+type TaskFilterer interface {
+	GetTasksByFilter(ctx context.Context, query string) ([]*TaskResponse, error)
+}
+
+type page[T any] struct {
+	Results    []T     `json:"results"`
+	NextCursor *string `json:"next_cursor"`
+}
+
+// getAllPages follows next_cursor until the last page
+func getAllPages[T any](ctx context.Context, c *TodoistClient, path string, params url.Values) ([]T, error) {
+	if params == nil {
+		params = url.Values{}
+	}
+	params.Set("limit", fmt.Sprintf("%d", pageLimit))
+
+	var all []T
+	for i := 0; i < maxPages; i++ {
+		var resp page[T]
+		if err := c.httpClient.Get(ctx, path+"?"+params.Encode(), &resp); err != nil {
+			return nil, err
+		}
+		all = append(all, resp.Results...)
+		if resp.NextCursor == nil || *resp.NextCursor == "" {
+			return all, nil
+		}
+		params.Set("cursor", *resp.NextCursor)
+	}
+	return nil, fmt.Errorf("%s: more than %d pages", path, maxPages)
+}
+
+// GetAllTasks returns every active task of a project, following pagination
+func (c *TodoistClient) GetAllTasks(ctx context.Context, projectID string) ([]*TaskResponse, error) {
+	tasks, err := getAllPages[*TaskResponse](ctx, c, "tasks", url.Values{"project_id": {projectID}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetTasksByFilter returns the active tasks matching a Todoist filter query, e.g. "overdue & @bug"
+func (c *TodoistClient) GetTasksByFilter(ctx context.Context, query string) ([]*TaskResponse, error) {
+	tasks, err := getAllPages[*TaskResponse](ctx, c, "tasks/filter", url.Values{"query": {query}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting tasks by filter: %w", err)
+	}
+	return tasks, nil
+}
+
+// GetSections returns the sections of a project
+func (c *TodoistClient) GetSections(ctx context.Context, projectID string) ([]Section, error) {
+	sections, err := getAllPages[Section](ctx, c, "sections", url.Values{"project_id": {projectID}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting sections: %w", err)
+	}
+	return sections, nil
+}
+
+// GetComments returns the comments of a task
+func (c *TodoistClient) GetComments(ctx context.Context, taskID string) ([]Comment, error) {
+	comments, err := getAllPages[Comment](ctx, c, "comments", url.Values{"task_id": {taskID}})
+	if err != nil {
+		return nil, fmt.Errorf("error getting comments: %w", err)
+	}
+	return comments, nil
+}
+
+// ExportProject collects the project, its sections, tasks and task comments.
+// Comments are fetched only for tasks that have any.
+func (c *TodoistClient) ExportProject(ctx context.Context, projectID string) (*ProjectSnapshot, error) {
+	if projectID == "" {
+		return nil, fmt.Errorf("project id is required")
+	}
+
+	var project Project
+	if err := c.httpClient.Get(ctx, fmt.Sprintf("projects/%s", projectID), &project); err != nil {
+		return nil, fmt.Errorf("error getting project: %w", err)
+	}
+
+	sections, err := c.GetSections(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := c.GetAllTasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &ProjectSnapshot{
+		ExportedAt: time.Now().UTC(),
+		Project:    project,
+		Sections:   sections,
+		Tasks:      make([]TaskBackup, 0, len(tasks)),
+	}
+	for _, task := range tasks {
+		backup := TaskBackup{TaskResponse: task}
+		if task.CommentCount > 0 {
+			if backup.Comments, err = c.GetComments(ctx, task.ID); err != nil {
+				return nil, err
+			}
+		}
+		snapshot.Tasks = append(snapshot.Tasks, backup)
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,63 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Tests that a project export follows pagination and fetches comments only for commented tasks
+func TestTodoistClient_ExportProject(t *testing.T) {
+	commentRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/projects/42":
+			fmt.Fprint(w, `{"id":"42","name":"Board"}`)
+		case "/sections":
+			fmt.Fprint(w, `{"results":[{"id":"s1","project_id":"42","name":"Backlog"}],"next_cursor":null}`)
+		case "/tasks":
+			if r.URL.Query().Get("project_id") != "42" {
+				t.Errorf("unexpected project filter %q", r.URL.Query().Get("project_id"))
+			}
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprint(w, `{"results":[{"id":"1","content":"First","comment_count":2}],"next_cursor":"next"}`)
+				return
+			}
+			fmt.Fprint(w, `{"results":[{"id":"2","content":"Second"}],"next_cursor":null}`)
+		case "/comments":
+			commentRequests++
+			fmt.Fprint(w, `{"results":[{"id":"c1","content":"Looks good"},{"id":"c2","content":"Done"}]}`)
+		default:
+			t.Logf("Unhandled request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(ProjectExporter)
+
+	snapshot, err := client.ExportProject(context.Background(), "42")
+	if err != nil {
+		t.Fatalf("Error exporting project: %v", err)
+	}
+
+	if snapshot.Project.Name != "Board" || len(snapshot.Sections) != 1 {
+		t.Errorf("Unexpected project or sections: %+v", snapshot)
+	}
+	if len(snapshot.Tasks) != 2 {
+		t.Fatalf("Expected tasks from both pages, got %d", len(snapshot.Tasks))
+	}
+	if len(snapshot.Tasks[0].Comments) != 2 || len(snapshot.Tasks[1].Comments) != 0 {
+		t.Errorf("Unexpected comments: %+v", snapshot.Tasks)
+	}
+	if commentRequests != 1 {
+		t.Errorf("Expected comments to be fetched once, got %d", commentRequests)
+	}
+}
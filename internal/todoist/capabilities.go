@@ -0,0 +1,81 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// Capability is an API permission the bot relies on
+type Capability string
+
+const (
+	CapabilityProjectsRead Capability = "projects:read"
+	CapabilityTasksRead    Capability = "tasks:read"
+	CapabilityTasksWrite   Capability = "tasks:write"
+)
+
+// CapabilityCheck is the result of probing a single capability
+type CapabilityCheck struct {
+	Capability Capability
+	OK         bool
+	// Problem explains what is wrong with the token when OK is false
+	Problem string
+}
+
+// CapabilityVerifier is implemented by clients that can check their token on startup
+type CapabilityVerifier interface {
+	VerifyCapabilities(ctx context.Context) []CapabilityCheck
+}
+
+// VerifyCapabilities probes the endpoints the bot needs without changing any data.
+// Task creation is probed with an empty task: Todoist rejects it with 400 when the
+// token may write tasks and with 401/403 when it may not.
+func (c *TodoistClient) VerifyCapabilities(ctx context.Context) []CapabilityCheck {
+	checks := []CapabilityCheck{
+		probe(CapabilityProjectsRead, c.httpClient.Get(ctx, "projects", nil), false),
+		probe(CapabilityTasksRead, c.httpClient.Get(ctx, "tasks", nil), false),
+	}
+
+	var created TaskResponse
+	err := c.httpClient.Post(ctx, "tasks", map[string]string{}, &created)
+	if err == nil && created.ID != "" {
+		// Should never happen, but do not leave a stray task behind
+		c.httpClient.Delete(ctx, fmt.Sprintf("tasks/%s", created.ID))
+	}
+	checks = append(checks, probe(CapabilityTasksWrite, err, true))
+
+	return checks
+}
+
+func probe(capability Capability, err error, badRequestMeansAllowed bool) CapabilityCheck {
+	check := CapabilityCheck{Capability: capability}
+	switch {
+	case err == nil:
+		check.OK = true
+	case httpclient.IsBadRequest(err) && badRequestMeansAllowed:
+		check.OK = true
+	case httpclient.IsUnauthorized(err):
+		check.Problem = "token is invalid or revoked (401); set a valid TODOIST_API_TOKEN"
+	case httpclient.IsForbidden(err):
+		check.Problem = fmt.Sprintf("token lacks the %s permission (403); issue a token with full data access", capability)
+	default:
+		check.Problem = fmt.Sprintf("probe failed: %v", err)
+	}
+	return check
+}
+
+// FailedCapabilities formats failed checks, one per line, or returns an empty string
+func FailedCapabilities(checks []CapabilityCheck) string {
+	var lines []string
+	for _, check := range checks {
+		if !check.OK {
+			lines = append(lines, fmt.Sprintf("%s: %s", check.Capability, check.Problem))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+var _ CapabilityVerifier = (*TodoistClient)(nil)
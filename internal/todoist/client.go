@@ -126,6 +126,10 @@ type Client interface {
 	CompleteTask(ctx context.Context, taskID string) error
 	// DeleteTask permanently deletes a task
 	DeleteTask(ctx context.Context, taskID string) error
+	// GetLabels returns the personal labels
+	GetLabels(ctx context.Context) ([]Label, error)
+	// CreateLabel creates a personal label
+	CreateLabel(ctx context.Context, name string) (*Label, error)
 }
 
 // TodoistClient is the implementation of the Client interface
@@ -135,12 +139,29 @@ type TodoistClient struct {
 
 // NewClient creates a new Todoist client
 func NewClient() (Client, error) {
+	return NewClientWithTokenEnv("")
+}
+
+// NewClientWithTokenEnv creates a Todoist client that reads its API token from
+// the given environment variable instead of the one in configs/api.yaml.
+// An empty name keeps the configured variable.
+func NewClientWithTokenEnv(tokenEnvVar string) (Client, error) {
 	// Load configuration from YAML file
 	configs, err := httpclient.LoadConfig("configs/api.yaml")
 	if err != nil {
 		return nil, fmt.Errorf("failed to load API configuration: %w", err)
 	}
 
+	if tokenEnvVar != "" {
+		todoistConfig, ok := configs.Clients["todoist"]
+		if ok && todoistConfig.Authorization != nil {
+			authorization := *todoistConfig.Authorization
+			authorization.TokenEnvVar = tokenEnvVar
+			todoistConfig.Authorization = &authorization
+			configs.Clients["todoist"] = todoistConfig
+		}
+	}
+
 	// Get Todoist client configuration
 	clientConfig, err := configs.GetClientConfig("todoist")
 	if err != nil {
@@ -168,6 +189,11 @@ func NewClient() (Client, error) {
 	}, nil
 }
 
+// Use adds a middleware to the requests of the client
+func (c *TodoistClient) Use(middleware httpclient.Middleware) {
+	c.httpClient.WithMiddleware(middleware)
+}
+
 // CreateTask creates a new task in Todoist
 func (c *TodoistClient) CreateTask(ctx context.Context, task *TaskRequest) (*TaskResponse, error) {
 	if task.Content == "" {
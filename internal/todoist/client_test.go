@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"text/template"
 
@@ -307,3 +308,49 @@ func TestTodoistClient_GetProjectCollaborators(t *testing.T) {
 		t.Fatalf("Unexpected collaborator payload: %#v", collaborators[0])
 	}
 }
+
+// Tests that capability probes pass when the token may read projects and tasks and write tasks
+func TestTodoistClient_VerifyCapabilities(t *testing.T) {
+	server := setupTestServer(t)
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(CapabilityVerifier)
+
+	checks := client.VerifyCapabilities(context.Background())
+	if len(checks) != 3 {
+		t.Fatalf("Expected 3 capability checks, got %d", len(checks))
+	}
+	if failed := FailedCapabilities(checks); failed != "" {
+		t.Errorf("Expected all capabilities to pass, got:\n%s", failed)
+	}
+}
+
+// Tests that a read-only token is reported as missing the tasks:write capability
+func TestTodoistClient_VerifyCapabilities_ReadOnlyToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, `{"error":"Insufficient scope"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"results":[]}`)
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(CapabilityVerifier)
+
+	failed := FailedCapabilities(client.VerifyCapabilities(context.Background()))
+	if !strings.Contains(failed, "tasks:write") || !strings.Contains(failed, "403") {
+		t.Errorf("Expected tasks:write to fail with 403, got %q", failed)
+	}
+	if strings.Contains(failed, "projects:read") {
+		t.Errorf("Expected projects:read to pass, got %q", failed)
+	}
+}
@@ -0,0 +1,33 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+)
+
+// Label represents a personal Todoist label
+type Label struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color,omitempty"`
+	Order      int    `json:"order,omitempty"`
+	IsFavorite bool   `json:"is_favorite,omitempty"`
+}
+
+// GetLabels returns the personal labels of the account, following pagination
+func (c *TodoistClient) GetLabels(ctx context.Context) ([]Label, error) {
+	labels, err := getAllPages[Label](ctx, c, "labels", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error getting labels: %w", err)
+	}
+	return labels, nil
+}
+
+// CreateLabel creates a personal label
+func (c *TodoistClient) CreateLabel(ctx context.Context, name string) (*Label, error) {
+	var label Label
+	if err := c.httpClient.Post(ctx, "labels", map[string]string{"name": name}, &label); err != nil {
+		return nil, fmt.Errorf("error creating label: %w", err)
+	}
+	return &label, nil
+}
@@ -0,0 +1,57 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Tests that labels are listed across pages and created by name
+func TestTodoistClient_Labels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/labels" && r.Method == http.MethodGet:
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprint(w, `{"results":[{"id":"1","name":"backend"}],"next_cursor":"next"}`)
+				return
+			}
+			fmt.Fprint(w, `{"results":[{"id":"2","name":"bug"}],"next_cursor":null}`)
+		case r.URL.Path == "/labels" && r.Method == http.MethodPost:
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body["name"] != "idea" {
+				t.Errorf("Unexpected label request: %v %v", body, err)
+			}
+			fmt.Fprint(w, `{"id":"3","name":"idea"}`)
+		default:
+			t.Logf("Unhandled request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath)
+
+	labels, err := client.GetLabels(context.Background())
+	if err != nil {
+		t.Fatalf("Error getting labels: %v", err)
+	}
+	if len(labels) != 2 || labels[1].Name != "bug" {
+		t.Errorf("Expected labels from both pages, got %+v", labels)
+	}
+
+	label, err := client.CreateLabel(context.Background(), "idea")
+	if err != nil {
+		t.Fatalf("Error creating label: %v", err)
+	}
+	if label.ID != "3" {
+		t.Errorf("Unexpected label: %+v", label)
+	}
+}
@@ -0,0 +1,26 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+)
+
+// QuickAdder is implemented by clients that create tasks from one line of
+// Todoist Quick Add syntax, e.g. "buy milk tomorrow p1 @errands #Home"
+type QuickAdder interface {
+	QuickAddTask(ctx context.Context, text string) (*TaskResponse, error)
+}
+
+type quickAddRequest struct {
+	Text string `json:"text"`
+}
+
+// QuickAddTask creates a task, letting Todoist parse the due date, priority,
+// labels and project out of the text
+func (c *TodoistClient) QuickAddTask(ctx context.Context, text string) (*TaskResponse, error) {
+	var task TaskResponse
+	if err := c.httpClient.Post(ctx, "tasks/quick", quickAddRequest{Text: text}, &task); err != nil {
+		return nil, fmt.Errorf("error adding task: %w", err)
+	}
+	return &task, nil
+}
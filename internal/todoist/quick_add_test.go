@@ -0,0 +1,40 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// Tests that Quick Add sends the raw text and returns the parsed task
+func TestTodoistClient_QuickAddTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/tasks/quick" {
+			t.Errorf("Unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var body quickAddRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Text != "buy milk tomorrow p1 @errands" {
+			t.Errorf("Unexpected quick add request: %+v %v", body, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id":"7","content":"buy milk","priority":4,"labels":["errands"],"due":{"string":"tomorrow","date":"2026-10-16"}}`)
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(QuickAdder)
+
+	task, err := client.QuickAddTask(context.Background(), "buy milk tomorrow p1 @errands")
+	if err != nil {
+		t.Fatalf("Error adding task: %v", err)
+	}
+	if task.Content != "buy milk" || task.Priority != 4 || task.Due == nil || task.Due.Date != "2026-10-16" {
+		t.Errorf("Unexpected task: %+v", task)
+	}
+}
@@ -0,0 +1,67 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// CompletedTask is a task completed in the reported period
+type CompletedTask struct {
+	ID          string    `json:"id"`
+	Content     string    `json:"content"`
+	ProjectID   string    `json:"project_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ProductivityStats is the account's karma summary
+type ProductivityStats struct {
+	Karma          float64 `json:"karma"`
+	KarmaTrend     string  `json:"karma_trend"`
+	CompletedCount int     `json:"completed_count"`
+}
+
+// ProductivityReporter is implemented by clients that can report completed tasks
+type ProductivityReporter interface {
+	GetCompletedTasks(ctx context.Context, projectID string, since, until time.Time) ([]CompletedTask, error)
+	GetProductivityStats(ctx context.Context) (*ProductivityStats, error)
+}
+
+type completedPage struct {
+	Items      []CompletedTask `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
+}
+
+// GetCompletedTasks returns the tasks of a project completed between since and until
+func (c *TodoistClient) GetCompletedTasks(ctx context.Context, projectID string, since, until time.Time) ([]CompletedTask, error) {
+	params := url.Values{
+		"since":      {since.UTC().Format(time.RFC3339)},
+		"until":      {until.UTC().Format(time.RFC3339)},
+		"project_id": {projectID},
+		"limit":      {fmt.Sprintf("%d", pageLimit)},
+	}
+
+	var all []CompletedTask
+	for i := 0; i < maxPages; i++ {
+		var resp completedPage
+		if err := c.httpClient.Get(ctx, "tasks/completed/by_completion_date?"+params.Encode(), &resp); err != nil {
+			return nil, fmt.Errorf("error getting completed tasks: %w", err)
+		}
+		all = append(all, resp.Items...)
+		if resp.NextCursor == nil || *resp.NextCursor == "" {
+			return all, nil
+		}
+		params.Set("cursor", *resp.NextCursor)
+	}
+	return nil, fmt.Errorf("completed tasks: more than %d pages", maxPages)
+}
+
+// GetProductivityStats returns karma of the account behind the token
+func (c *TodoistClient) GetProductivityStats(ctx context.Context) (*ProductivityStats, error) {
+	var stats ProductivityStats
+	if err := c.httpClient.Get(ctx, "tasks/completed/stats", &stats); err != nil {
+		return nil, fmt.Errorf("error getting productivity stats: %w", err)
+	}
+	return &stats, nil
+}
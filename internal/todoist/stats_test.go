@@ -0,0 +1,56 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// Tests that completed tasks follow pagination and karma is read from the stats endpoint
+func TestTodoistClient_ProductivityReporter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/tasks/completed/by_completion_date":
+			if r.URL.Query().Get("project_id") != "42" || r.URL.Query().Get("since") != "2026-10-01T00:00:00Z" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			if r.URL.Query().Get("cursor") == "" {
+				fmt.Fprint(w, `{"items":[{"id":"1","project_id":"42","completed_at":"2026-10-02T10:00:00Z"}],"next_cursor":"next"}`)
+				return
+			}
+			fmt.Fprint(w, `{"items":[{"id":"2","project_id":"42","completed_at":"2026-10-03T10:00:00Z"}],"next_cursor":null}`)
+		case "/tasks/completed/stats":
+			fmt.Fprint(w, `{"karma":1520.5,"karma_trend":"up","completed_count":300}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(ProductivityReporter)
+
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	tasks, err := client.GetCompletedTasks(context.Background(), "42", since, since.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("Error getting completed tasks: %v", err)
+	}
+	if len(tasks) != 2 || tasks[1].CompletedAt.Day() != 3 {
+		t.Errorf("Expected tasks from both pages, got %+v", tasks)
+	}
+
+	stats, err := client.GetProductivityStats(context.Background())
+	if err != nil {
+		t.Fatalf("Error getting stats: %v", err)
+	}
+	if stats.Karma != 1520.5 || stats.KarmaTrend != "up" {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
@@ -0,0 +1,252 @@
+package todoist
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// syncBatchLimit is the number of commands Todoist accepts in one sync request
+const syncBatchLimit = 100
+
+// SyncCommand is a single write command of the Sync API
+type SyncCommand struct {
+	Type   string         `json:"type"`
+	UUID   string         `json:"uuid"`
+	TempID string         `json:"temp_id,omitempty"`
+	Args   map[string]any `json:"args"`
+}
+
+type syncRequest struct {
+	Commands []SyncCommand `json:"commands"`
+}
+
+type syncResponse struct {
+	SyncStatus    map[string]json.RawMessage `json:"sync_status"`
+	TempIDMapping map[string]string          `json:"temp_id_mapping"`
+}
+
+type syncError struct {
+	ErrorCode int    `json:"error_code"`
+	Error     string `json:"error"`
+}
+
+// BatchTaskResult is the outcome of one task of a batch, in request order
+type BatchTaskResult struct {
+	ID  string
+	Err error
+}
+
+// BatchCreator is implemented by clients that can create many tasks in a few requests
+type BatchCreator interface {
+	CreateTasksBatch(ctx context.Context, tasks []*TaskRequest) ([]BatchTaskResult, error)
+}
+
+// SubtaskCreator is implemented by clients that can create a task together
+// with its subtasks in one request
+type SubtaskCreator interface {
+	CreateTaskWithSubtasks(ctx context.Context, parent *TaskRequest, subtasks []*TaskRequest) ([]BatchTaskResult, error)
+}
+
+// BulkUpdater is implemented by clients that can select tasks with a Todoist
+// filter and complete or reschedule them in a few requests
+type BulkUpdater interface {
+	TaskFilterer
+	CompleteTasksBatch(ctx context.Context, taskIDs []string) ([]BatchTaskResult, error)
+	RescheduleTasksBatch(ctx context.Context, taskIDs []string, due string) ([]BatchTaskResult, error)
+}
+
+// CreateTasksBatch creates tasks through the Sync API, up to syncBatchLimit
+// per request. A failed request fails all of its tasks; per-task errors are
+// reported in the results.
+func (c *TodoistClient) CreateTasksBatch(ctx context.Context, tasks []*TaskRequest) ([]BatchTaskResult, error) {
+	commands := make([]SyncCommand, 0, len(tasks))
+	for _, task := range tasks {
+		command, err := newItemAddCommand(itemAddArgs(task))
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return c.runSync(ctx, commands), nil
+}
+
+// CreateTaskWithSubtasks creates a task and its subtasks in one sync request:
+// the subtasks reference the parent by its temp ID, so Todoist resolves the
+// hierarchy itself. The first result is the parent, then the subtasks in order.
+// A retried request carries the same command UUIDs, and Todoist does not run
+// commands it has already applied.
+func (c *TodoistClient) CreateTaskWithSubtasks(ctx context.Context, parent *TaskRequest, subtasks []*TaskRequest) ([]BatchTaskResult, error) {
+	// Temp IDs only resolve within one request
+	if len(subtasks)+1 > syncBatchLimit {
+		return nil, fmt.Errorf("too many subtasks: %d, at most %d", len(subtasks), syncBatchLimit-1)
+	}
+
+	parentCommand, err := newItemAddCommand(itemAddArgs(parent))
+	if err != nil {
+		return nil, err
+	}
+	commands := []SyncCommand{parentCommand}
+	for _, subtask := range subtasks {
+		args := itemAddArgs(subtask)
+		args["parent_id"] = parentCommand.TempID
+		command, err := newItemAddCommand(args)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return c.runSync(ctx, commands), nil
+}
+
+// CompleteTasksBatch completes tasks through the Sync API
+func (c *TodoistClient) CompleteTasksBatch(ctx context.Context, taskIDs []string) ([]BatchTaskResult, error) {
+	commands := make([]SyncCommand, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		command, err := newSyncCommand("item_close", map[string]any{"id": id})
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return c.runSync(ctx, commands), nil
+}
+
+// RescheduleTasksBatch moves tasks to a new due date, either YYYY-MM-DD or a
+// natural language date like "завтра"
+func (c *TodoistClient) RescheduleTasksBatch(ctx context.Context, taskIDs []string, due string) ([]BatchTaskResult, error) {
+	dueArgs := map[string]string{"string": due, "lang": "ru"}
+	if isISODate(due) {
+		dueArgs = map[string]string{"date": due}
+	}
+
+	commands := make([]SyncCommand, 0, len(taskIDs))
+	for _, id := range taskIDs {
+		command, err := newSyncCommand("item_update", map[string]any{"id": id, "due": dueArgs})
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, command)
+	}
+	return c.runSync(ctx, commands), nil
+}
+
+func newSyncCommand(commandType string, args map[string]any) (SyncCommand, error) {
+	uuid, err := newUUID()
+	if err != nil {
+		return SyncCommand{}, err
+	}
+	return SyncCommand{Type: commandType, UUID: uuid, Args: args}, nil
+}
+
+// newItemAddCommand creates an item_add command with a temp ID, which maps to
+// the created task ID in the response and may be referenced by later commands
+func newItemAddCommand(args map[string]any) (SyncCommand, error) {
+	command, err := newSyncCommand("item_add", args)
+	if err != nil {
+		return SyncCommand{}, err
+	}
+	if command.TempID, err = newUUID(); err != nil {
+		return SyncCommand{}, err
+	}
+	return command, nil
+}
+
+// runSync sends commands in requests of up to syncBatchLimit. Results follow
+// the command order; the ID is the created ID for item_add and the task ID otherwise.
+func (c *TodoistClient) runSync(ctx context.Context, commands []SyncCommand) []BatchTaskResult {
+	results := make([]BatchTaskResult, len(commands))
+	for start := 0; start < len(commands); start += syncBatchLimit {
+		end := start + syncBatchLimit
+		if end > len(commands) {
+			end = len(commands)
+		}
+
+		var resp syncResponse
+		if err := c.httpClient.Post(ctx, "sync", syncRequest{Commands: commands[start:end]}, &resp); err != nil {
+			for i := start; i < end; i++ {
+				results[i].Err = fmt.Errorf("error syncing tasks: %w", err)
+			}
+			continue
+		}
+
+		for i := start; i < end; i++ {
+			results[i] = batchResult(commands[i], resp)
+		}
+	}
+	return results
+}
+
+func batchResult(command SyncCommand, resp syncResponse) BatchTaskResult {
+	status, ok := resp.SyncStatus[command.UUID]
+	if !ok {
+		return BatchTaskResult{Err: fmt.Errorf("no sync status for command")}
+	}
+	var okStatus string
+	if json.Unmarshal(status, &okStatus) == nil && okStatus == "ok" {
+		if command.TempID != "" {
+			return BatchTaskResult{ID: resp.TempIDMapping[command.TempID]}
+		}
+		id, _ := command.Args["id"].(string)
+		return BatchTaskResult{ID: id}
+	}
+	var failure syncError
+	if err := json.Unmarshal(status, &failure); err != nil {
+		return BatchTaskResult{Err: fmt.Errorf("unexpected sync status %s", status)}
+	}
+	return BatchTaskResult{Err: fmt.Errorf("todoist error %d: %s", failure.ErrorCode, failure.Error)}
+}
+
+// itemAddArgs converts a REST task request to item_add arguments
+func itemAddArgs(task *TaskRequest) map[string]any {
+	args := map[string]any{"content": task.Content}
+	if task.Description != "" {
+		args["description"] = task.Description
+	}
+	if task.ProjectID != "" {
+		args["project_id"] = task.ProjectID
+	}
+	if task.SectionID != "" {
+		args["section_id"] = task.SectionID
+	}
+	if task.ParentID != "" {
+		args["parent_id"] = task.ParentID
+	}
+	if task.Priority != 0 {
+		args["priority"] = task.Priority
+	}
+	if len(task.Labels) > 0 {
+		args["labels"] = task.Labels
+	}
+	if task.AssigneeID != "" {
+		args["responsible_uid"] = task.AssigneeID
+	}
+	switch {
+	case task.DueDate != "":
+		args["due"] = map[string]string{"date": task.DueDate}
+	case task.DueString != "":
+		due := map[string]string{"string": task.DueString}
+		if task.DueLang != "" {
+			due["lang"] = task.DueLang
+		}
+		args["due"] = due
+	}
+	return args
+}
+
+func isISODate(value string) bool {
+	_, err := time.Parse("2006-01-02", value)
+	return err == nil
+}
+
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate command id: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
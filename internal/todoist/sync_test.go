@@ -0,0 +1,148 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Tests that batch creation maps temp ids to created ids and reports per-task errors
+func TestTodoistClient_CreateTasksBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/sync" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var req syncRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Error decoding sync request: %v", err)
+		}
+		if len(req.Commands) != 2 || req.Commands[0].Type != "item_add" {
+			t.Fatalf("Unexpected commands: %+v", req.Commands)
+		}
+		if due := req.Commands[0].Args["due"].(map[string]any); due["string"] != "tomorrow" {
+			t.Errorf("Expected due string to be passed, got %v", due)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"sync_status":{%q:"ok",%q:{"error_code":15,"error":"Invalid argument"}},"temp_id_mapping":{%q:"9001"}}`,
+			req.Commands[0].UUID, req.Commands[1].UUID, req.Commands[0].TempID)
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(BatchCreator)
+
+	results, err := client.CreateTasksBatch(context.Background(), []*TaskRequest{
+		{Content: "First", DueString: "tomorrow"},
+		{Content: "Second", Priority: 9},
+	})
+	if err != nil {
+		t.Fatalf("Error creating batch: %v", err)
+	}
+	if results[0].ID != "9001" || results[0].Err != nil {
+		t.Errorf("Expected first task to be created, got %+v", results[0])
+	}
+	if results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "Invalid argument") {
+		t.Errorf("Expected second task to fail, got %+v", results[1])
+	}
+}
+
+// Tests that completion and rescheduling send one command per task and report task ids
+func TestTodoistClient_CompleteAndRescheduleBatch(t *testing.T) {
+	var got []SyncCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req syncRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Error decoding sync request: %v", err)
+		}
+		got = append(got, req.Commands...)
+
+		statuses := make([]string, 0, len(req.Commands))
+		for _, command := range req.Commands {
+			statuses = append(statuses, fmt.Sprintf("%q:\"ok\"", command.UUID))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"sync_status":{%s}}`, strings.Join(statuses, ","))
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(BulkUpdater)
+
+	results, err := client.CompleteTasksBatch(context.Background(), []string{"a1", "b2"})
+	if err != nil || len(results) != 2 || results[1].ID != "b2" || results[1].Err != nil {
+		t.Fatalf("Unexpected completion results: %+v, %v", results, err)
+	}
+	if _, err := client.RescheduleTasksBatch(context.Background(), []string{"a1"}, "2026-10-20"); err != nil {
+		t.Fatalf("Error rescheduling: %v", err)
+	}
+	if _, err := client.RescheduleTasksBatch(context.Background(), []string{"a1"}, "завтра"); err != nil {
+		t.Fatalf("Error rescheduling: %v", err)
+	}
+
+	if len(got) != 4 || got[0].Type != "item_close" || got[2].Type != "item_update" {
+		t.Fatalf("Unexpected commands: %+v", got)
+	}
+	if due := got[2].Args["due"].(map[string]any); due["date"] != "2026-10-20" {
+		t.Errorf("Expected ISO date to be sent as date, got %v", due)
+	}
+	if due := got[3].Args["due"].(map[string]any); due["string"] != "завтра" || due["lang"] != "ru" {
+		t.Errorf("Expected natural date to be sent as string, got %v", due)
+	}
+}
+
+// Tests that subtasks are sent in the parent's request and reference it by temp id
+func TestTodoistClient_CreateTaskWithSubtasks(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req syncRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("Error decoding sync request: %v", err)
+		}
+		if len(req.Commands) != 3 {
+			t.Fatalf("Expected parent and two subtasks in one request, got %+v", req.Commands)
+		}
+		parent := req.Commands[0]
+		for _, sub := range req.Commands[1:] {
+			if sub.Args["parent_id"] != parent.TempID {
+				t.Errorf("Expected subtask to reference parent temp id %s, got %v", parent.TempID, sub.Args["parent_id"])
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"sync_status":{%q:"ok",%q:"ok",%q:"ok"},"temp_id_mapping":{%q:"1",%q:"2",%q:"3"}}`,
+			req.Commands[0].UUID, req.Commands[1].UUID, req.Commands[2].UUID,
+			req.Commands[0].TempID, req.Commands[1].TempID, req.Commands[2].TempID)
+	}))
+	defer server.Close()
+
+	configPath := createTestConfig(t, server.URL)
+	defer os.Remove(configPath)
+
+	client := newTestClient(t, configPath).(SubtaskCreator)
+
+	results, err := client.CreateTaskWithSubtasks(context.Background(),
+		&TaskRequest{Content: "Release", ProjectID: "42"},
+		[]*TaskRequest{{Content: "Changelog"}, {Content: "Tag"}})
+	if err != nil {
+		t.Fatalf("Error creating task with subtasks: %v", err)
+	}
+	if requests != 1 || len(results) != 3 || results[0].ID != "1" || results[2].ID != "3" {
+		t.Errorf("Unexpected results after %d requests: %+v", requests, results)
+	}
+
+	if _, err := client.CreateTaskWithSubtasks(context.Background(), &TaskRequest{Content: "Huge"}, make([]*TaskRequest, syncBatchLimit)); err == nil {
+		t.Error("Expected an error for more subtasks than one request holds")
+	}
+}
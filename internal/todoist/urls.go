@@ -0,0 +1,95 @@
+package todoist
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// ErrNotTodoistURL is returned for links that do not point to a Todoist project or task
+var ErrNotTodoistURL = errors.New("not a Todoist URL")
+
+// idPattern matches both legacy numeric IDs and the alphanumeric v2 IDs
+var idPattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// ParseProjectURL extracts the project ID from a Todoist link. Supported shapes:
+//
+//	https://app.todoist.com/app/project/work-6Jf8VQXxpwv56VQ7
+//	https://app.todoist.com/app/project/6Jf8VQXxpwv56VQ7/view/board
+//	https://todoist.com/app/project/2203306141
+//	https://todoist.com/app/#project/2203306141
+//	https://todoist.com/showProject?id=2203306141
+func ParseProjectURL(raw string) (string, error) {
+	return parseURL(raw, "project")
+}
+
+// ParseTaskURL extracts the task ID from a Todoist link. Supported shapes:
+//
+//	https://app.todoist.com/app/task/buy-milk-6X7rM8997g3RQmvh
+//	https://app.todoist.com/app/project/work-6Jf8VQXxpwv56VQ7/task/6X7rM8997g3RQmvh
+//	https://app.todoist.com/app/today/task/6X7rM8997g3RQmvh
+//	https://todoist.com/app/#task/2995104339
+//	https://todoist.com/showTask?id=2995104339
+func ParseTaskURL(raw string) (string, error) {
+	return parseURL(raw, "task")
+}
+
+// TaskURL returns the web app link of a task
+func TaskURL(taskID string) string {
+	return "https://app.todoist.com/app/task/" + taskID
+}
+
+func parseURL(raw, kind string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || !isTodoistHost(u.Hostname()) {
+		return "", ErrNotTodoistURL
+	}
+
+	// Legacy links: /showProject?id=... and /showTask?id=...
+	if strings.EqualFold(strings.Trim(u.Path, "/"), "show"+kind) {
+		if id := u.Query().Get("id"); idPattern.MatchString(id) {
+			return id, nil
+		}
+		return "", ErrNotTodoistURL
+	}
+
+	// The web app used hash routes before moving to paths, e.g. /app/#project/123
+	segments := append(splitPath(u.Path), splitPath(u.Fragment)...)
+
+	// Task links inside a project contain both kinds, so the last match wins
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] == kind {
+			if id := idFromSlug(segments[i+1]); id != "" {
+				return id, nil
+			}
+		}
+	}
+	return "", ErrNotTodoistURL
+}
+
+func isTodoistHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "todoist.com" || strings.HasSuffix(host, ".todoist.com")
+}
+
+func splitPath(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// idFromSlug returns the ID at the end of "name-ID" slugs, or the segment itself
+func idFromSlug(slug string) string {
+	if i := strings.LastIndex(slug, "-"); i >= 0 {
+		slug = slug[i+1:]
+	}
+	if !idPattern.MatchString(slug) {
+		return ""
+	}
+	return slug
+}
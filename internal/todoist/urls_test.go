@@ -0,0 +1,63 @@
+package todoist
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseProjectURL(t *testing.T) {
+	tests := map[string]string{
+		"https://app.todoist.com/app/project/work-6Jf8VQXxpwv56VQ7":             "6Jf8VQXxpwv56VQ7",
+		"https://app.todoist.com/app/project/6Jf8VQXxpwv56VQ7":                  "6Jf8VQXxpwv56VQ7",
+		"https://app.todoist.com/app/project/inbox-2203306141/view/board":       "2203306141",
+		"https://app.todoist.com/app/project/my-big-project-6Jf8VQXxpwv56VQ7?x": "6Jf8VQXxpwv56VQ7",
+		"https://todoist.com/app/project/2203306141":                            "2203306141",
+		"https://todoist.com/app/#project/2203306141":                           "2203306141",
+		"https://todoist.com/showProject?id=2203306141":                         "2203306141",
+		" https://www.todoist.com/app/project/2203306141 ":                      "2203306141",
+	}
+	for raw, want := range tests {
+		got, err := ParseProjectURL(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseProjectURL(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+}
+
+func TestParseTaskURL(t *testing.T) {
+	tests := map[string]string{
+		"https://app.todoist.com/app/task/buy-milk-6X7rM8997g3RQmvh":                      "6X7rM8997g3RQmvh",
+		"https://app.todoist.com/app/task/6X7rM8997g3RQmvh":                               "6X7rM8997g3RQmvh",
+		"https://app.todoist.com/app/project/work-6Jf8VQXxpwv56VQ7/task/6X7rM8997g3RQmvh": "6X7rM8997g3RQmvh",
+		"https://app.todoist.com/app/today/task/fix-login-6X7rM8997g3RQmvh":               "6X7rM8997g3RQmvh",
+		"https://todoist.com/app/project/2203306141/task/2995104339":                      "2995104339",
+		"https://todoist.com/app/#task/2995104339":                                        "2995104339",
+		"https://todoist.com/showTask?id=2995104339":                                      "2995104339",
+		"https://todoist.com/showTask?id=2995104339&sync_id=12":                           "2995104339",
+	}
+	for raw, want := range tests {
+		got, err := ParseTaskURL(raw)
+		if err != nil || got != want {
+			t.Errorf("ParseTaskURL(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+}
+
+func TestParseURL_Rejects(t *testing.T) {
+	inputs := []string{
+		"https://example.com/app/task/6X7rM8997g3RQmvh",
+		"https://nottodoist.com/app/project/123",
+		"https://app.todoist.com/app/project/work-6Jf8VQXxpwv56VQ7",
+		"https://todoist.com/showTask?id=",
+		"https://app.todoist.com/app/today",
+		"6X7rM8997g3RQmvh",
+	}
+	for _, raw := range inputs {
+		if id, err := ParseTaskURL(raw); !errors.Is(err, ErrNotTodoistURL) {
+			t.Errorf("ParseTaskURL(%q) = %q, %v; want ErrNotTodoistURL", raw, id, err)
+		}
+	}
+	if _, err := ParseProjectURL("https://todoist.com/showTask?id=2995104339"); !errors.Is(err, ErrNotTodoistURL) {
+		t.Error("expected a task link not to parse as a project")
+	}
+}
@@ -0,0 +1,145 @@
+// Package topics tracks the forum topics of messages. tgbotapi v5 does not
+// decode message_thread_id, so the topic of each message is read from the raw
+// getUpdates response or webhook update and later looked up by chat and
+// message ID.
+package topics
+
+import (
+	"encoding/json"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// DefaultCapacity is how many topic messages the default index remembers;
+// updates are handled within seconds, so older entries are never looked up
+const DefaultCapacity = 10000
+
+type key struct {
+	chatID    int64
+	messageID int
+}
+
+// Index maps messages to their forum topic
+type Index struct {
+	mu       sync.Mutex
+	threads  map[key]int
+	order    []key // ring of recorded keys, oldest evicted first
+	next     int
+	capacity int
+}
+
+// NewIndex creates an index remembering up to capacity messages
+func NewIndex(capacity int) *Index {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Index{
+		threads:  make(map[key]int),
+		order:    make([]key, 0, capacity),
+		capacity: capacity,
+	}
+}
+
+type rawMessage struct {
+	MessageID int `json:"message_id"`
+	Chat      struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	MessageThreadID int  `json:"message_thread_id"`
+	IsTopicMessage  bool `json:"is_topic_message"`
+}
+
+type rawUpdate struct {
+	Message           *rawMessage `json:"message"`
+	EditedMessage     *rawMessage `json:"edited_message"`
+	ChannelPost       *rawMessage `json:"channel_post"`
+	EditedChannelPost *rawMessage `json:"edited_channel_post"`
+	CallbackQuery     *struct {
+		Message *rawMessage `json:"message"`
+	} `json:"callback_query"`
+}
+
+// Record reads the topics of the messages in a getUpdates response body.
+// Bodies that are not a successful getUpdates response are ignored.
+func (i *Index) Record(body []byte) {
+	var resp struct {
+		OK     bool        `json:"ok"`
+		Result []rawUpdate `json:"result"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || !resp.OK {
+		return
+	}
+	i.addUpdates(resp.Result)
+}
+
+// RecordUpdate reads the topics of the messages in a single update, the body
+// Telegram posts to a webhook
+func (i *Index) RecordUpdate(body []byte) {
+	var update rawUpdate
+	if err := json.Unmarshal(body, &update); err != nil {
+		return
+	}
+	i.addUpdates([]rawUpdate{update})
+}
+
+func (i *Index) addUpdates(updates []rawUpdate) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, update := range updates {
+		i.add(update.Message)
+		i.add(update.EditedMessage)
+		i.add(update.ChannelPost)
+		i.add(update.EditedChannelPost)
+		if update.CallbackQuery != nil {
+			i.add(update.CallbackQuery.Message)
+		}
+	}
+}
+
+// add records a message in a topic; the caller holds mu. Replies in groups
+// without topics carry a message_thread_id too, is_topic_message tells them apart.
+func (i *Index) add(msg *rawMessage) {
+	if msg == nil || !msg.IsTopicMessage || msg.MessageThreadID == 0 {
+		return
+	}
+	k := key{chatID: msg.Chat.ID, messageID: msg.MessageID}
+	if _, ok := i.threads[k]; !ok {
+		if len(i.order) < i.capacity {
+			i.order = append(i.order, k)
+		} else {
+			delete(i.threads, i.order[i.next])
+			i.order[i.next] = k
+			i.next = (i.next + 1) % i.capacity
+		}
+	}
+	i.threads[k] = msg.MessageThreadID
+}
+
+// Thread returns the forum topic of a message, 0 outside topics
+func (i *Index) Thread(message *tgbotapi.Message) int {
+	if message == nil || message.Chat == nil {
+		return 0
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.threads[key{chatID: message.Chat.ID, messageID: message.MessageID}]
+}
+
+var defaultIndex = NewIndex(DefaultCapacity)
+
+// Record reads a getUpdates response body into the default index
+func Record(body []byte) {
+	defaultIndex.Record(body)
+}
+
+// RecordUpdate reads a webhook update body into the default index
+func RecordUpdate(body []byte) {
+	defaultIndex.RecordUpdate(body)
+}
+
+// ThreadID returns the forum topic of a message from the default index, 0
+// outside topics
+func ThreadID(message *tgbotapi.Message) int {
+	return defaultIndex.Thread(message)
+}
@@ -0,0 +1,70 @@
+package topics
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func message(chatID int64, messageID int) *tgbotapi.Message {
+	return &tgbotapi.Message{MessageID: messageID, Chat: &tgbotapi.Chat{ID: chatID}}
+}
+
+func TestIndex_RecordsTopicMessages(t *testing.T) {
+	index := NewIndex(10)
+	index.Record([]byte(`{"ok":true,"result":[
+		{"update_id":1,"message":{"message_id":10,"chat":{"id":-100},"message_thread_id":7,"is_topic_message":true}},
+		{"update_id":2,"message":{"message_id":11,"chat":{"id":-100},"message_thread_id":9}},
+		{"update_id":3,"callback_query":{"id":"q","message":{"message_id":12,"chat":{"id":-100},"message_thread_id":8,"is_topic_message":true}}}
+	]}`))
+
+	if got := index.Thread(message(-100, 10)); got != 7 {
+		t.Fatalf("expected topic 7, got %d", got)
+	}
+	if got := index.Thread(message(-100, 11)); got != 0 {
+		t.Fatalf("expected a reply thread outside topics to be ignored, got %d", got)
+	}
+	if got := index.Thread(message(-100, 12)); got != 8 {
+		t.Fatalf("expected topic 8 of the callback message, got %d", got)
+	}
+	if got := index.Thread(message(-200, 10)); got != 0 {
+		t.Fatalf("expected no topic in another chat, got %d", got)
+	}
+}
+
+func TestIndex_RecordsWebhookUpdate(t *testing.T) {
+	index := NewIndex(10)
+	index.RecordUpdate([]byte(`{"update_id":1,"message":{"message_id":10,"chat":{"id":-100},"message_thread_id":7,"is_topic_message":true}}`))
+
+	if got := index.Thread(message(-100, 10)); got != 7 {
+		t.Fatalf("expected topic 7, got %d", got)
+	}
+}
+
+func TestIndex_EvictsOldestMessages(t *testing.T) {
+	index := NewIndex(2)
+	for _, body := range []string{
+		`{"ok":true,"result":[{"update_id":1,"message":{"message_id":1,"chat":{"id":5},"message_thread_id":3,"is_topic_message":true}}]}`,
+		`{"ok":true,"result":[{"update_id":2,"message":{"message_id":2,"chat":{"id":5},"message_thread_id":3,"is_topic_message":true}}]}`,
+		`{"ok":true,"result":[{"update_id":3,"message":{"message_id":3,"chat":{"id":5},"message_thread_id":3,"is_topic_message":true}}]}`,
+	} {
+		index.Record([]byte(body))
+	}
+
+	if got := index.Thread(message(5, 1)); got != 0 {
+		t.Fatalf("expected the oldest message to be evicted, got topic %d", got)
+	}
+	if got := index.Thread(message(5, 3)); got != 3 {
+		t.Fatalf("expected topic 3 of the newest message, got %d", got)
+	}
+}
+
+func TestIndex_IgnoresErrorResponses(t *testing.T) {
+	index := NewIndex(10)
+	index.Record([]byte(`{"ok":false,"error_code":409,"description":"Conflict"}`))
+	index.Record([]byte(`not json`))
+
+	if got := index.Thread(message(1, 1)); got != 0 {
+		t.Fatalf("expected no topic, got %d", got)
+	}
+}
@@ -0,0 +1,95 @@
+package tracker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+// Selector picks the tracker of a chat: the one configured for the chat in
+// the trackers section of configs/api.yaml, otherwise the default one
+type Selector struct {
+	defaultKind string
+	chats       map[int64]string
+	clients     map[string]Client
+}
+
+// LoadConfig reads the trackers section of an API configuration file
+func LoadConfig(path string) (httpclient.TrackersConfig, error) {
+	configs, err := httpclient.LoadConfig(path)
+	if err != nil {
+		return httpclient.TrackersConfig{}, err
+	}
+	return configs.Trackers, nil
+}
+
+// NewSelector creates a selector; without a default Todoist is used
+func NewSelector(config httpclient.TrackersConfig) *Selector {
+	defaultKind := config.Default
+	if defaultKind == "" {
+		defaultKind = KindTodoist
+	}
+	chats := make(map[int64]string, len(config.Chats))
+	for chatID, kind := range config.Chats {
+		chats[chatID] = kind
+	}
+	return &Selector{
+		defaultKind: defaultKind,
+		chats:       chats,
+		clients:     make(map[string]Client),
+	}
+}
+
+// Single returns a selector that sends every chat to the Todoist client
+func Single(client Client) *Selector {
+	s := NewSelector(httpclient.TrackersConfig{})
+	s.Register(KindTodoist, client)
+	return s
+}
+
+// Register makes a backend available under its kind
+func (s *Selector) Register(kind string, client Client) {
+	s.clients[kind] = client
+}
+
+// Kind returns the tracker kind configured for the chat
+func (s *Selector) Kind(chatID int64) string {
+	if kind, ok := s.chats[chatID]; ok {
+		return kind
+	}
+	return s.defaultKind
+}
+
+// ForChat returns the tracker of the chat
+func (s *Selector) ForChat(chatID int64) (Client, error) {
+	kind := s.Kind(chatID)
+	client, ok := s.clients[kind]
+	if !ok {
+		return nil, fmt.Errorf("task tracker %q of chat %d is not available", kind, chatID)
+	}
+	return client, nil
+}
+
+// Validate reports configured kinds that have no registered backend, so a
+// typo in configs/api.yaml stops the start instead of failing in a chat
+func (s *Selector) Validate() error {
+	kinds := map[string]struct{}{s.defaultKind: {}}
+	for _, kind := range s.chats {
+		kinds[kind] = struct{}{}
+	}
+	missing := make([]string, 0)
+	for kind := range kinds {
+		if _, ok := s.clients[kind]; !ok {
+			missing = append(missing, kind)
+		}
+	}
+	sort.Strings(missing)
+
+	var problems []error
+	for _, kind := range missing {
+		problems = append(problems, fmt.Errorf("task tracker %q is not supported", kind))
+	}
+	return errors.Join(problems...)
+}
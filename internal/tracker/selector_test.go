@@ -0,0 +1,40 @@
+package tracker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/httpclient"
+)
+
+func TestSelectorForChat(t *testing.T) {
+	todoistTracker := NewTodoist(nil)
+	jiraTracker := NewTodoist(nil)
+
+	s := NewSelector(httpclient.TrackersConfig{Chats: map[int64]string{-100: "jira", -200: "redmine"}})
+	s.Register(KindTodoist, todoistTracker)
+	s.Register("jira", jiraTracker)
+
+	client, err := s.ForChat(-1)
+	require.NoError(t, err)
+	assert.Same(t, todoistTracker, client, "chats without settings use Todoist")
+
+	client, err = s.ForChat(-100)
+	require.NoError(t, err)
+	assert.Same(t, jiraTracker, client)
+
+	_, err = s.ForChat(-200)
+	assert.ErrorContains(t, err, `task tracker "redmine" of chat -200 is not available`)
+}
+
+func TestSelectorValidate(t *testing.T) {
+	s := NewSelector(httpclient.TrackersConfig{Default: "jira", Chats: map[int64]string{-100: KindTodoist, -200: "redmine"}})
+	s.Register(KindTodoist, NewTodoist(nil))
+
+	err := s.Validate()
+	require.Error(t, err)
+	assert.Equal(t, "task tracker \"jira\" is not supported\ntask tracker \"redmine\" is not supported", err.Error())
+
+	assert.NoError(t, Single(NewTodoist(nil)).Validate())
+}
@@ -0,0 +1,176 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/telegram-bot/internal/dates"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// Todoist adapts a Todoist client to the tracker interface
+type Todoist struct {
+	client todoist.Client
+}
+
+// NewTodoist wraps a Todoist client
+func NewTodoist(client todoist.Client) *Todoist {
+	return &Todoist{client: client}
+}
+
+// CreateTask creates a task in Todoist
+func (t *Todoist) CreateTask(ctx context.Context, task *TaskInput) (*Task, error) {
+	created, err := t.client.CreateTask(ctx, todoistRequest(task))
+	if err != nil {
+		return nil, err
+	}
+	return fromTodoist(created), nil
+}
+
+// ListTasks returns active Todoist tasks, optionally of one project
+func (t *Todoist) ListTasks(ctx context.Context, projectID string) ([]*Task, error) {
+	tasks, err := t.client.GetTasks(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task != nil {
+			result = append(result, fromTodoist(task))
+		}
+	}
+	return result, nil
+}
+
+// GetTask returns a Todoist task by ID
+func (t *Todoist) GetTask(ctx context.Context, taskID string) (*Task, error) {
+	task, err := t.client.GetTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return fromTodoist(task), nil
+}
+
+// UpdateTask updates a Todoist task. Todoist requires the content on every
+// update, so an empty title keeps the current one.
+func (t *Todoist) UpdateTask(ctx context.Context, taskID string, task *TaskInput) (*Task, error) {
+	request := todoistRequest(task)
+	if request.Content == "" {
+		current, err := t.client.GetTask(ctx, taskID)
+		if err != nil {
+			return nil, err
+		}
+		request.Content = current.Content
+	}
+	updated, err := t.client.UpdateTask(ctx, taskID, request)
+	if err != nil {
+		return nil, err
+	}
+	return fromTodoist(updated), nil
+}
+
+// CompleteTask closes a Todoist task
+func (t *Todoist) CompleteTask(ctx context.Context, taskID string) error {
+	return t.client.CompleteTask(ctx, taskID)
+}
+
+// DeleteTask deletes a Todoist task
+func (t *Todoist) DeleteTask(ctx context.Context, taskID string) error {
+	return t.client.DeleteTask(ctx, taskID)
+}
+
+// ListProjects returns the Todoist projects
+func (t *Todoist) ListProjects(ctx context.Context) ([]Project, error) {
+	projects, err := t.client.GetProjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Project, 0, len(projects))
+	for _, p := range projects {
+		result = append(result, Project{ID: p.ID, Name: p.Name, URL: p.URL})
+	}
+	return result, nil
+}
+
+// ListSections returns the sections of a Todoist project
+func (t *Todoist) ListSections(ctx context.Context, projectID string) ([]Section, error) {
+	lister, ok := t.client.(todoist.SectionLister)
+	if !ok {
+		return nil, nil
+	}
+	sections, err := lister.GetSections(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]Section, 0, len(sections))
+	for _, s := range sections {
+		result = append(result, Section{ID: s.ID, Name: s.Name})
+	}
+	return result, nil
+}
+
+// ListLabels returns the names of the personal Todoist labels
+func (t *Todoist) ListLabels(ctx context.Context) ([]string, error) {
+	labels, err := t.client.GetLabels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(labels))
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	return names, nil
+}
+
+// CreateLabel creates a personal Todoist label
+func (t *Todoist) CreateLabel(ctx context.Context, name string) error {
+	_, err := t.client.CreateLabel(ctx, name)
+	return err
+}
+
+// AddComment adds a comment to a Todoist task
+func (t *Todoist) AddComment(ctx context.Context, taskID, text string) error {
+	commenter, ok := t.client.(todoist.AttachmentUploader)
+	if !ok {
+		return fmt.Errorf("todoist client does not support comments")
+	}
+	_, err := commenter.AddComment(ctx, taskID, text, nil)
+	return err
+}
+
+func todoistRequest(task *TaskInput) *todoist.TaskRequest {
+	request := &todoist.TaskRequest{
+		Content:     task.Title,
+		Description: task.Description,
+		ProjectID:   task.ProjectID,
+		SectionID:   task.SectionID,
+		Priority:    task.Priority,
+		DueDate:     task.DueDate,
+		AssigneeID:  task.AssigneeID,
+		Labels:      task.Labels,
+	}
+	// Todoist takes a due with a time in a field of its own
+	if len(task.DueDate) > len(dates.DateLayout) {
+		request.DueDate, request.DueDateTime = "", task.DueDate
+	}
+	return request
+}
+
+func fromTodoist(task *todoist.TaskResponse) *Task {
+	result := &Task{
+		ID:          task.ID,
+		Title:       task.Content,
+		Description: task.Description,
+		ProjectID:   task.ProjectID,
+		Priority:    task.Priority,
+		AssigneeID:  task.AssigneeID,
+		Labels:      task.Labels,
+		// The API still returns legacy showTask links, the app link opens everywhere
+		URL:       todoist.TaskURL(task.ID),
+		Completed: task.IsCompleted,
+	}
+	if task.Due != nil {
+		result.DueDate = task.Due.Date
+	}
+	return result
+}
@@ -0,0 +1,104 @@
+package tracker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/telegram-bot/internal/todoist"
+)
+
+// fakeTodoist records the requests of the adapter; other methods are not used
+type fakeTodoist struct {
+	todoist.Client
+	created *todoist.TaskRequest
+	updated *todoist.TaskRequest
+	task    *todoist.TaskResponse
+}
+
+func (f *fakeTodoist) CreateTask(_ context.Context, task *todoist.TaskRequest) (*todoist.TaskResponse, error) {
+	f.created = task
+	return &todoist.TaskResponse{ID: "t1", Content: task.Content, Priority: task.Priority, URL: "https://todoist.com/showTask?id=t1"}, nil
+}
+
+func (f *fakeTodoist) GetTask(_ context.Context, taskID string) (*todoist.TaskResponse, error) {
+	return f.task, nil
+}
+
+func (f *fakeTodoist) UpdateTask(_ context.Context, taskID string, task *todoist.TaskRequest) (*todoist.TaskResponse, error) {
+	f.updated = task
+	return &todoist.TaskResponse{ID: taskID, Content: task.Content, AssigneeID: task.AssigneeID}, nil
+}
+
+func TestTodoistCreateTask(t *testing.T) {
+	fake := &fakeTodoist{}
+	task, err := NewTodoist(fake).CreateTask(context.Background(), &TaskInput{
+		Title:      "Починить вход",
+		ProjectID:  "p1",
+		Priority:   3,
+		DueDate:    "2026-04-01",
+		AssigneeID: "u1",
+		Labels:     []string{"backend"},
+		SectionID:  "s1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &todoist.TaskRequest{
+		Content:    "Починить вход",
+		ProjectID:  "p1",
+		SectionID:  "s1",
+		Priority:   3,
+		DueDate:    "2026-04-01",
+		AssigneeID: "u1",
+		Labels:     []string{"backend"},
+	}, fake.created)
+	assert.Equal(t, "t1", task.ID)
+	assert.Equal(t, "Починить вход", task.Title)
+	assert.Equal(t, "https://app.todoist.com/app/task/t1", task.URL)
+}
+
+func TestTodoistCreateTaskWithDueTime(t *testing.T) {
+	fake := &fakeTodoist{}
+	_, err := NewTodoist(fake).CreateTask(context.Background(), &TaskInput{
+		Title:     "Созвон с клиентом",
+		ProjectID: "p1",
+		DueDate:   "2026-04-01T18:00:00+03:00",
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, fake.created.DueDate)
+	assert.Equal(t, "2026-04-01T18:00:00+03:00", fake.created.DueDateTime)
+}
+
+func TestTodoistUpdateTaskKeepsTitle(t *testing.T) {
+	fake := &fakeTodoist{task: &todoist.TaskResponse{ID: "t1", Content: "Старое название", Due: &todoist.DueObject{Date: "2026-04-01"}}}
+	task, err := NewTodoist(fake).UpdateTask(context.Background(), "t1", &TaskInput{AssigneeID: "u2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Старое название", fake.updated.Content, "Todoist requires the content on update")
+	assert.Equal(t, "u2", task.AssigneeID)
+
+	current, err := NewTodoist(fake).GetTask(context.Background(), "t1")
+	require.NoError(t, err)
+	assert.Equal(t, "2026-04-01", current.DueDate)
+}
+
+// sectionTodoist is a Todoist client that can list sections
+type sectionTodoist struct {
+	fakeTodoist
+}
+
+func (f *sectionTodoist) GetSections(_ context.Context, projectID string) ([]todoist.Section, error) {
+	return []todoist.Section{{ID: "s1", ProjectID: projectID, Name: "Backlog"}}, nil
+}
+
+func TestTodoistListSections(t *testing.T) {
+	sections, err := NewTodoist(&sectionTodoist{}).ListSections(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.Equal(t, []Section{{ID: "s1", Name: "Backlog"}}, sections)
+
+	sections, err = NewTodoist(&fakeTodoist{}).ListSections(context.Background(), "p1")
+	require.NoError(t, err)
+	assert.Empty(t, sections, "clients without sections support list none")
+}
@@ -0,0 +1,94 @@
+// Package tracker hides the task tracker behind one interface, so a chat can
+// keep its tasks in Todoist or in another backend selected in configs/api.yaml.
+package tracker
+
+import "context"
+
+// Tracker kinds, named after their clients in configs/api.yaml
+const (
+	// KindTodoist is the default backend
+	KindTodoist = "todoist"
+	KindJira    = "jira"
+)
+
+// Task is a task as the bot sees it, whatever tracker keeps it
+type Task struct {
+	ID          string
+	Title       string
+	Description string
+	ProjectID   string
+	// Priority goes from 1 (normal) to 4 (urgent), as in the Todoist API
+	Priority   int
+	DueDate    string // YYYY-MM-DD, empty without a due date
+	AssigneeID string
+	Labels     []string
+	URL        string
+	Completed  bool
+}
+
+// TaskInput holds the fields of a created or updated task. UpdateTask leaves
+// empty fields unchanged.
+type TaskInput struct {
+	Title       string
+	Description string
+	ProjectID   string
+	Priority    int
+	// DueDate is YYYY-MM-DD, or RFC 3339 for a due date with a time
+	DueDate    string
+	AssigneeID string
+	Labels     []string
+	// SectionID puts the task into a section (board column) of the project
+	SectionID string
+}
+
+// Project is a project, board or space tasks are created in
+type Project struct {
+	ID   string
+	Name string
+	URL  string
+}
+
+// Client is a task tracker backend
+type Client interface {
+	// CreateTask creates a task
+	CreateTask(ctx context.Context, task *TaskInput) (*Task, error)
+	// ListTasks returns open tasks, optionally of one project
+	ListTasks(ctx context.Context, projectID string) ([]*Task, error)
+	// GetTask returns a task by ID
+	GetTask(ctx context.Context, taskID string) (*Task, error)
+	// UpdateTask changes the non-empty fields of a task
+	UpdateTask(ctx context.Context, taskID string, task *TaskInput) (*Task, error)
+	// CompleteTask closes a task
+	CompleteTask(ctx context.Context, taskID string) error
+	// DeleteTask permanently deletes a task
+	DeleteTask(ctx context.Context, taskID string) error
+	// ListProjects returns the projects tasks can be created in
+	ListProjects(ctx context.Context) ([]Project, error)
+}
+
+// Section is a section of a project, a column on a board
+type Section struct {
+	ID   string
+	Name string
+}
+
+// SectionLister is implemented by trackers whose projects are split into sections
+type SectionLister interface {
+	// ListSections returns the sections of a project, none if
+	// the backend does not support them
+	ListSections(ctx context.Context, projectID string) ([]Section, error)
+}
+
+// LabelManager is implemented by trackers that keep a list of known labels
+type LabelManager interface {
+	// ListLabels returns the names of the existing labels
+	ListLabels(ctx context.Context) ([]string, error)
+	// CreateLabel adds a label to the list
+	CreateLabel(ctx context.Context, name string) error
+}
+
+// Commenter is implemented by trackers that can comment on tasks
+type Commenter interface {
+	// AddComment adds a text comment to a task
+	AddComment(ctx context.Context, taskID, text string) error
+}
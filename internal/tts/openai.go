@@ -0,0 +1,94 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "tts-1"
+	defaultOpenAIVoice   = "alloy"
+)
+
+// OpenAISynthesizer uses an OpenAI-compatible /audio/speech endpoint
+type OpenAISynthesizer struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	model      string
+	voice      string
+}
+
+// NewOpenAISynthesizer creates a synthesizer for an OpenAI-compatible API
+func NewOpenAISynthesizer(baseURL, apiKey, model, voice string) *OpenAISynthesizer {
+	return &OpenAISynthesizer{
+		httpClient: &http.Client{Timeout: 60 * time.Second},
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		model:      model,
+		voice:      voice,
+	}
+}
+
+func newOpenAIFromEnv() (Synthesizer, error) {
+	apiKey := os.Getenv(EnvAPIKey)
+	if apiKey == "" {
+		return nil, fmt.Errorf("%s is required for the openai TTS provider", EnvAPIKey)
+	}
+	return NewOpenAISynthesizer(
+		envOrDefault(EnvBaseURL, defaultOpenAIBaseURL),
+		apiKey,
+		envOrDefault(EnvModel, defaultOpenAIModel),
+		envOrDefault(EnvVoice, defaultOpenAIVoice),
+	), nil
+}
+
+// Synthesize requests Opus audio, which Telegram accepts as a voice note
+func (s *OpenAISynthesizer) Synthesize(ctx context.Context, text string) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{
+		"model":           s.model,
+		"voice":           s.voice,
+		"input":           text,
+		"response_format": "opus",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal speech request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/audio/speech", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create speech request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("speech request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	audio, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read speech response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("speech API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(audio)))
+	}
+	return audio, nil
+}
+
+func envOrDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
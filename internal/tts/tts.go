@@ -0,0 +1,91 @@
+// Package tts turns task previews into short voice notes. Providers are
+// pluggable; the bot only needs OGG/Opus audio it can send as a Telegram voice.
+package tts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Environment variables configuring speech synthesis
+const (
+	EnvProvider = "TTS_PROVIDER"
+	EnvAPIKey   = "TTS_API_KEY"
+	EnvBaseURL  = "TTS_BASE_URL"
+	EnvModel    = "TTS_MODEL"
+	EnvVoice    = "TTS_VOICE"
+)
+
+// MaxTextLength keeps voice notes short; longer previews are cut at a sentence
+const MaxTextLength = 1000
+
+// Synthesizer converts text to OGG/Opus audio
+type Synthesizer interface {
+	Synthesize(ctx context.Context, text string) ([]byte, error)
+}
+
+// providers maps TTS_PROVIDER values to constructors
+var providers = map[string]func() (Synthesizer, error){
+	"openai": newOpenAIFromEnv,
+}
+
+// NewFromEnv creates the synthesizer selected by TTS_PROVIDER. It returns nil
+// without an error when speech synthesis is not configured.
+func NewFromEnv() (Synthesizer, error) {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv(EnvProvider)))
+	if name == "" {
+		return nil, nil
+	}
+
+	newProvider, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown %s %q (supported: %s)", EnvProvider, name, strings.Join(providerNames(), ", "))
+	}
+	return newProvider()
+}
+
+func providerNames() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	urlRe      = regexp.MustCompile(`https?://\S+`)
+	markdownRe = regexp.MustCompile("[*_`\\[\\]\\\\]")
+	spacesRe   = regexp.MustCompile(`[ \t]+`)
+)
+
+// PlainText prepares a Markdown preview for reading aloud: formatting and
+// links are dropped and the text is shortened to MaxTextLength.
+func PlainText(preview string) string {
+	text := urlRe.ReplaceAllString(preview, "ссылка")
+	text = markdownRe.ReplaceAllString(text, "")
+	text = spacesRe.ReplaceAllString(text, " ")
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	text = strings.Join(kept, "\n")
+
+	runes := []rune(text)
+	if len(runes) <= MaxTextLength {
+		return text
+	}
+	cut := string(runes[:MaxTextLength])
+	if i := strings.LastIndexAny(cut, ".!?\n"); i > MaxTextLength/2 {
+		cut = cut[:i+1]
+	}
+	return strings.TrimSpace(cut)
+}
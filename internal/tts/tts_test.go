@@ -0,0 +1,80 @@
+package tts
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPlainText_StripsMarkdownAndLinks(t *testing.T) {
+	preview := "*Название:* Починить логин\n\n*Полезные материалы:*\n- docs: https://docs.example.com/login — `инструкция`"
+
+	got := PlainText(preview)
+
+	want := "Название: Починить логин\nПолезные материалы:\n- docs: ссылка — инструкция"
+	if got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestPlainText_CutsLongTextAtSentence(t *testing.T) {
+	text := strings.Repeat("Очень длинное предложение. ", 100)
+
+	got := PlainText(text)
+
+	if len([]rune(got)) > MaxTextLength {
+		t.Errorf("expected at most %d runes, got %d", MaxTextLength, len([]rune(got)))
+	}
+	if !strings.HasSuffix(got, ".") {
+		t.Errorf("expected text to end at a sentence, got %q", got[len(got)-20:])
+	}
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Setenv(EnvProvider, "")
+	if s, err := NewFromEnv(); s != nil || err != nil {
+		t.Errorf("expected disabled synthesizer, got %v, %v", s, err)
+	}
+
+	t.Setenv(EnvProvider, "unknown")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+
+	t.Setenv(EnvProvider, "openai")
+	t.Setenv(EnvAPIKey, "")
+	if _, err := NewFromEnv(); err == nil {
+		t.Error("expected error without API key")
+	}
+}
+
+func TestOpenAISynthesizer_RequestsOpus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/audio/speech" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected authorization %q", r.Header.Get("Authorization"))
+		}
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if body["response_format"] != "opus" || body["input"] != "привет" {
+			t.Errorf("unexpected request %v", body)
+		}
+		w.Write([]byte("OggS"))
+	}))
+	defer server.Close()
+
+	audio, err := NewOpenAISynthesizer(server.URL+"/", "key", "tts-1", "alloy").Synthesize(context.Background(), "привет")
+	if err != nil {
+		t.Fatalf("Synthesize() error = %v", err)
+	}
+	if string(audio) != "OggS" {
+		t.Errorf("unexpected audio %q", audio)
+	}
+}
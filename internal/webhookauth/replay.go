@@ -0,0 +1,48 @@
+package webhookauth
+
+import (
+	"sync"
+	"time"
+)
+
+// ReplayCache remembers deliveries for a while to reject repeated requests
+type ReplayCache struct {
+	ttl  time.Duration
+	mu   sync.Mutex
+	seen map[string]time.Time
+	// nextPrune limits sweeping expired IDs to once per ttl
+	nextPrune time.Time
+}
+
+// NewReplayCache creates a cache that forgets IDs after ttl
+func NewReplayCache(ttl time.Duration) *ReplayCache {
+	return &ReplayCache{ttl: ttl, seen: make(map[string]time.Time)}
+}
+
+// Seen records id and reports whether it was already recorded within ttl
+func (c *ReplayCache) Seen(id string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.nextPrune) {
+		for key, at := range c.seen {
+			if now.Sub(at) > c.ttl {
+				delete(c.seen, key)
+			}
+		}
+		c.nextPrune = now.Add(c.ttl)
+	}
+
+	if at, ok := c.seen[id]; ok && now.Sub(at) <= c.ttl {
+		return true
+	}
+	c.seen[id] = now
+	return false
+}
+
+// Forget drops id, so a delivery that was not processed can be retried
+func (c *ReplayCache) Forget(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.seen, id)
+}
@@ -0,0 +1,216 @@
+// Package webhookauth verifies inbound webhook requests before they can reach
+// a chat: an HMAC-SHA256 signature of the body, a timestamp within a tolerance
+// window and a delivery that was not processed before. Rejections are JSON 4xx
+// responses, so senders can tell a bad secret from a replay.
+package webhookauth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultTolerance is how far a request timestamp may be from the local clock
+	DefaultTolerance = 5 * time.Minute
+	// MaxBodyBytes caps the size of a webhook body
+	MaxBodyBytes = 1 << 20
+)
+
+// Scheme describes how a sender signs its requests
+type Scheme struct {
+	Name            string
+	SignatureHeader string
+	// TimestampHeader holds Unix seconds; empty for senders without timestamps
+	TimestampHeader string
+	// DeliveryHeader identifies a delivery; retries of the same delivery reuse it
+	DeliveryHeader string
+	// SignsDelivery tells that Sign covers the delivery ID. Otherwise the header
+	// could be changed freely, so deliveries are told apart by their body.
+	SignsDelivery bool
+	// Sign returns the expected signature header value
+	Sign func(secret, timestamp, delivery string, body []byte) string
+}
+
+// Headers of the generic scheme, the ones notify webhooks are sent with
+const (
+	GenericSignatureHeader = "X-Webhook-Signature-256"
+	GenericTimestampHeader = "X-Webhook-Timestamp"
+	GenericDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+// Generic is the scheme of our own webhooks: X-Webhook-Signature-256 is
+// SignGeneric of the body, X-Webhook-Timestamp and X-Webhook-Delivery
+var Generic = Scheme{
+	Name:            "generic",
+	SignatureHeader: GenericSignatureHeader,
+	TimestampHeader: GenericTimestampHeader,
+	DeliveryHeader:  GenericDeliveryHeader,
+	SignsDelivery:   true,
+	Sign:            SignGeneric,
+}
+
+// SignGeneric returns "sha256=" and the hex HMAC of
+// "<timestamp>.<delivery>.<body>", so neither the timestamp nor the delivery
+// ID can be changed without the secret
+func SignGeneric(secret, timestamp, delivery string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + delivery + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Todoist is the scheme of Todoist webhooks: the base64 HMAC of the body with
+// the app's client secret. Todoist signs neither a timestamp nor the delivery
+// ID, so replays are caught by the body alone.
+var Todoist = Scheme{
+	Name:            "todoist",
+	SignatureHeader: "X-Todoist-Hmac-SHA256",
+	DeliveryHeader:  "X-Todoist-Delivery-ID",
+	Sign: func(secret, _, _ string, body []byte) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	},
+}
+
+// Error is a rejected request
+type Error struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+// WriteError answers with the error as {"error": {"code": ..., "message": ...}}
+func WriteError(w http.ResponseWriter, e *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.Status)
+	json.NewEncoder(w).Encode(struct {
+		Error *Error `json:"error"`
+	}{e})
+}
+
+// Verifier checks the requests of one sender
+type Verifier struct {
+	scheme    Scheme
+	secret    string
+	tolerance time.Duration
+	replays   *ReplayCache
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier. Deliveries are remembered for twice the
+// tolerance, or a day for schemes without timestamps.
+func NewVerifier(scheme Scheme, secret string, tolerance time.Duration) *Verifier {
+	ttl := 2 * tolerance
+	if scheme.TimestampHeader == "" {
+		ttl = 24 * time.Hour
+	}
+	return &Verifier{
+		scheme:    scheme,
+		secret:    secret,
+		tolerance: tolerance,
+		replays:   NewReplayCache(ttl),
+		now:       time.Now,
+	}
+}
+
+// Middleware passes only verified requests to next, with the body intact. A
+// delivery counts as processed only once next answers with a 2xx status, so
+// the sender can retry one the handler failed.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, delivery, verr := v.Verify(r)
+		if verr != nil {
+			WriteError(w, verr)
+			return
+		}
+		// Seen also holds the delivery while next runs, so a concurrent
+		// retry is rejected rather than processed twice
+		if v.replays.Seen(delivery, v.now()) {
+			WriteError(w, &Error{Status: http.StatusConflict, Code: "replayed_delivery", Message: "delivery was already processed"})
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(sw, r)
+		if sw.status < 200 || sw.status > 299 {
+			v.replays.Forget(delivery)
+		}
+	})
+}
+
+// statusWriter records the status a handler answered with
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Verify reads the body of r and checks its signature and timestamp. It
+// returns the key the delivery is told apart from others by, derived from
+// signed data only; checking it for replays is left to the caller.
+func (v *Verifier) Verify(r *http.Request) ([]byte, string, *Error) {
+	if r.Method != http.MethodPost {
+		return nil, "", &Error{Status: http.StatusMethodNotAllowed, Code: "method_not_allowed", Message: "webhooks must be POSTed"}
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, MaxBodyBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, "", &Error{Status: http.StatusRequestEntityTooLarge, Code: "body_too_large", Message: "body exceeds " + strconv.Itoa(MaxBodyBytes) + " bytes"}
+		}
+		return nil, "", &Error{Status: http.StatusBadRequest, Code: "unreadable_body", Message: err.Error()}
+	}
+
+	signature := r.Header.Get(v.scheme.SignatureHeader)
+	if signature == "" {
+		return nil, "", &Error{Status: http.StatusUnauthorized, Code: "missing_signature", Message: v.scheme.SignatureHeader + " header is required"}
+	}
+
+	var timestamp string
+	if v.scheme.TimestampHeader != "" {
+		timestamp = r.Header.Get(v.scheme.TimestampHeader)
+		seconds, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return nil, "", &Error{Status: http.StatusBadRequest, Code: "invalid_timestamp", Message: v.scheme.TimestampHeader + " must be Unix seconds"}
+		}
+		if skew := v.now().Sub(time.Unix(seconds, 0)); skew > v.tolerance || skew < -v.tolerance {
+			return nil, "", &Error{Status: http.StatusUnauthorized, Code: "stale_timestamp", Message: "timestamp is outside the tolerance window"}
+		}
+	}
+
+	delivery := r.Header.Get(v.scheme.DeliveryHeader)
+	if delivery == "" {
+		return nil, "", &Error{Status: http.StatusBadRequest, Code: "missing_delivery_id", Message: v.scheme.DeliveryHeader + " header is required"}
+	}
+
+	// The signature is checked before the delivery is remembered, so forged
+	// requests cannot block real deliveries
+	if !hmac.Equal([]byte(signature), []byte(v.scheme.Sign(v.secret, timestamp, delivery, body))) {
+		return nil, "", &Error{Status: http.StatusUnauthorized, Code: "invalid_signature", Message: "signature does not match"}
+	}
+
+	if !v.scheme.SignsDelivery {
+		digest := sha256.Sum256(body)
+		delivery = hex.EncodeToString(digest[:])
+	}
+	return body, delivery, nil
+}
@@ -0,0 +1,173 @@
+package webhookauth
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "s3cret"
+
+func newTestVerifier(scheme Scheme, now time.Time) *Verifier {
+	v := NewVerifier(scheme, testSecret, DefaultTolerance)
+	v.now = func() time.Time { return now }
+	return v
+}
+
+func genericRequest(body string, at time.Time, delivery string) *http.Request {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	r := httptest.NewRequest(http.MethodPost, "/hooks/generic", strings.NewReader(body))
+	r.Header.Set(Generic.TimestampHeader, ts)
+	r.Header.Set(Generic.DeliveryHeader, delivery)
+	r.Header.Set(Generic.SignatureHeader, Generic.Sign(testSecret, ts, delivery, []byte(body)))
+	return r
+}
+
+func serve(v *Verifier, r *http.Request) (*httptest.ResponseRecorder, string) {
+	return serveWithStatus(v, r, http.StatusNoContent)
+}
+
+// serveWithStatus passes verified requests to a handler answering status
+func serveWithStatus(v *Verifier, r *http.Request, status int) (*httptest.ResponseRecorder, string) {
+	var got string
+	handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = string(body)
+		w.WriteHeader(status)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec, got
+}
+
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var payload struct {
+		Error Error `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payload))
+	return payload.Error.Code
+}
+
+func TestGenericVerifier(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := newTestVerifier(Generic, now)
+
+	rec, body := serve(v, genericRequest(`{"event":"ping"}`, now.Add(-time.Minute), "d-1"))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, `{"event":"ping"}`, body, "the handler gets the body that was verified")
+
+	rec, body = serve(v, genericRequest(`{"event":"ping"}`, now.Add(-time.Minute), "d-1"))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "replayed_delivery", errorCode(t, rec))
+	assert.Empty(t, body)
+
+	rec, _ = serve(v, genericRequest(`{}`, now.Add(-DefaultTolerance-time.Second), "d-2"))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "stale_timestamp", errorCode(t, rec))
+
+	rec, _ = serve(v, genericRequest(`{}`, now.Add(DefaultTolerance+time.Second), "d-3"))
+	assert.Equal(t, "stale_timestamp", errorCode(t, rec))
+}
+
+func TestGenericVerifierRejectsTampering(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := newTestVerifier(Generic, now)
+
+	r := genericRequest(`{"event":"ping"}`, now, "d-1")
+	r.Header.Set(Generic.TimestampHeader, strconv.FormatInt(now.Unix()+1, 10))
+	rec, _ := serve(v, r)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "invalid_signature", errorCode(t, rec))
+
+	// A forged request must not burn the delivery ID of the real one
+	rec, _ = serve(v, genericRequest(`{"event":"ping"}`, now, "d-1"))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	// The delivery ID is signed, so a replay cannot pass as a new delivery
+	r = genericRequest(`{"event":"ping"}`, now, "d-1")
+	r.Header.Set(Generic.DeliveryHeader, "d-9")
+	rec, _ = serve(v, r)
+	assert.Equal(t, "invalid_signature", errorCode(t, rec))
+
+	r = genericRequest(`{}`, now, "d-2")
+	r.Header.Del(Generic.SignatureHeader)
+	rec, _ = serve(v, r)
+	assert.Equal(t, "missing_signature", errorCode(t, rec))
+
+	r = genericRequest(`{}`, now, "d-2")
+	r.Header.Set(Generic.TimestampHeader, "yesterday")
+	rec, _ = serve(v, r)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_timestamp", errorCode(t, rec))
+
+	rec, _ = serve(v, genericRequest(`{}`, now, ""))
+	assert.Equal(t, "missing_delivery_id", errorCode(t, rec))
+
+	r = genericRequest(`{}`, now, "d-2")
+	r.Method = http.MethodGet
+	rec, _ = serve(v, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestTodoistVerifier(t *testing.T) {
+	v := newTestVerifier(Todoist, time.Now())
+	body := `{"event_name":"item:completed"}`
+	request := func(delivery string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, "/hooks/todoist", strings.NewReader(body))
+		r.Header.Set(Todoist.SignatureHeader, Todoist.Sign(testSecret, "", delivery, []byte(body)))
+		r.Header.Set(Todoist.DeliveryHeader, delivery)
+		return r
+	}
+
+	rec, got := serve(v, request("t-1"))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, body, got)
+
+	rec, _ = serve(v, request("t-1"))
+	assert.Equal(t, "replayed_delivery", errorCode(t, rec))
+
+	// Todoist does not sign the delivery ID, so a new one does not hide a replay
+	rec, _ = serve(v, request("t-2"))
+	assert.Equal(t, "replayed_delivery", errorCode(t, rec))
+
+	r := request("t-3")
+	r.Header.Set(Todoist.SignatureHeader, Generic.Sign(testSecret, "", "t-3", []byte(body)))
+	rec, _ = serve(v, r)
+	assert.Equal(t, "invalid_signature", errorCode(t, rec))
+}
+
+func TestVerifierAcceptsRetryOfFailedDelivery(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := newTestVerifier(Generic, now)
+
+	rec, _ := serveWithStatus(v, genericRequest(`{"event":"ping"}`, now, "d-1"), http.StatusInternalServerError)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec, body := serve(v, genericRequest(`{"event":"ping"}`, now, "d-1"))
+	assert.Equal(t, http.StatusNoContent, rec.Code, "a delivery the handler failed is not a replay")
+	assert.Equal(t, `{"event":"ping"}`, body)
+
+	rec, _ = serve(v, genericRequest(`{"event":"ping"}`, now, "d-1"))
+	assert.Equal(t, "replayed_delivery", errorCode(t, rec))
+}
+
+func TestReplayCacheForgetsExpiredIDs(t *testing.T) {
+	cache := NewReplayCache(time.Minute)
+	start := time.Unix(1_700_000_000, 0)
+
+	assert.False(t, cache.Seen("a", start))
+	assert.True(t, cache.Seen("a", start.Add(30*time.Second)))
+	assert.False(t, cache.Seen("a", start.Add(2*time.Minute)))
+	assert.False(t, cache.Seen("b", start.Add(5*time.Minute)))
+	assert.Len(t, cache.seen, 1, "expired IDs are pruned")
+}